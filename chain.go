@@ -0,0 +1,123 @@
+package localize
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Transformer transforms localized strings returned by a reader.
+type Transformer interface {
+	// Transform returns the transformed version of the
+	// localized string s of locale.
+	Transform(locale language.Tag, s string) string
+}
+
+// TransformerFunc is a function implementing Transformer.
+type TransformerFunc func(locale language.Tag, s string) string
+
+// Transform calls fn.
+func (fn TransformerFunc) Transform(locale language.Tag, s string) string {
+	return fn(locale, s)
+}
+
+var (
+	// TitleCaseTransformer converts strings to title case
+	// using the casing rules of the reader's locale.
+	TitleCaseTransformer Transformer = TransformerFunc(
+		func(locale language.Tag, s string) string {
+			return cases.Title(locale).String(s)
+		},
+	)
+
+	// TrademarkSymbolTransformer replaces "(TM)", "(R)" and "(C)"
+	// with the symbols "™", "®" and "©" respectively.
+	TrademarkSymbolTransformer Transformer = TransformerFunc(
+		func(_ language.Tag, s string) string {
+			return replacerTrademarkSymbols.Replace(s)
+		},
+	)
+)
+
+var replacerTrademarkSymbols = strings.NewReplacer(
+	"(TM)", "™",
+	"(R)", "®",
+	"(C)", "©",
+)
+
+// Chain returns a reader that applies all transformers to every localized
+// string returned by r in the order they're passed.
+// Returns r if no transformers are passed.
+func Chain(r Reader, transformers ...Transformer) Reader {
+	if len(transformers) < 1 {
+		return r
+	}
+	return &chainReader{Reader: r, transformers: transformers}
+}
+
+type chainReader struct {
+	Reader
+	transformers []Transformer
+}
+
+func (c *chainReader) transform(s string) string {
+	locale := c.Locale()
+	for _, t := range c.transformers {
+		s = t.Transform(locale, s)
+	}
+	return s
+}
+
+// Unwrap returns the wrapped reader.
+func (c *chainReader) Unwrap() Reader { return c.Reader }
+
+func (c *chainReader) Text(text string) string {
+	return c.transform(c.Reader.Text(text))
+}
+
+func (c *chainReader) Block(text string) string {
+	return c.transform(c.Reader.Block(text))
+}
+
+func (c *chainReader) Plural(templates Forms, quantity any) string {
+	return c.transform(c.Reader.Plural(templates, quantity))
+}
+
+func (c *chainReader) PluralBlock(templates Forms, quantity any) string {
+	return c.transform(c.Reader.PluralBlock(templates, quantity))
+}
+
+func (c *chainReader) Cardinal(otherTemplate string, quantity any) string {
+	return c.transform(c.Reader.Cardinal(otherTemplate, quantity))
+}
+
+func (c *chainReader) PluralRange(templates Forms, from, to any) string {
+	return c.transform(c.Reader.PluralRange(templates, from, to))
+}
+
+func (c *chainReader) PluralOrdinal(templates OrdinalForms, ordinal, quantity any) string {
+	return c.transform(c.Reader.PluralOrdinal(templates, ordinal, quantity))
+}
+
+func (c *chainReader) Grammar(key string, args ...string) string {
+	return c.transform(c.Reader.Grammar(key, args...))
+}
+
+func (c *chainReader) FormatCompact(n float64) string {
+	return c.transform(c.Reader.FormatCompact(n))
+}
+
+func (c *chainReader) WithRegister(register Register) Reader {
+	return &chainReader{
+		Reader:       c.Reader.WithRegister(register),
+		transformers: c.transformers,
+	}
+}
+
+func (c *chainReader) Section(name string) Reader {
+	return &chainReader{
+		Reader:       c.Reader.Section(name),
+		transformers: c.transformers,
+	}
+}
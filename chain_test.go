@@ -0,0 +1,63 @@
+package localize_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestChain(t *testing.T) {
+	r := MockReader{
+		tag: language.English,
+		static: map[string]string{
+			"acme(TM) rocket": "acme(TM) rocket",
+			"\tcopyright (C)": "copyright (C)",
+		},
+	}
+
+	var calls []string
+	logTransformer := localize.TransformerFunc(
+		func(locale language.Tag, s string) string {
+			calls = append(calls, locale.String()+":"+s)
+			return s
+		},
+	)
+
+	c := localize.Chain(r,
+		localize.TrademarkSymbolTransformer,
+		localize.TitleCaseTransformer,
+		logTransformer,
+	)
+	require.Equal(t, language.English, c.Locale())
+	require.Equal(t, "Acme™ Rocket", c.Text("acme(TM) rocket"))
+	require.Equal(t, "Copyright ©", c.Block("\tcopyright (C)"))
+	require.Equal(t, []string{"en:Acme™ Rocket", "en:Copyright ©"}, calls)
+
+	require.Equal(t, r, c.(interface{ Unwrap() localize.Reader }).Unwrap())
+}
+
+func TestChainNoTransformers(t *testing.T) {
+	r := MockReader{tag: language.English}
+	require.Equal(t, localize.Reader(r), localize.Chain(r))
+}
+
+func TestChainDebugReader(t *testing.T) {
+	r := MockCatalogReader{
+		MockReader: MockReader{
+			tag:    language.English,
+			static: map[string]string{"hello": "hello"},
+		},
+		messages: []MockCatalogMessage{{
+			Key:         localize.Key{Hash: "818274c2b2b715d5", Source: "hello"},
+			Translation: localize.Translation{Text: "hello"},
+		}},
+	}
+	upper := localize.TransformerFunc(func(_ language.Tag, s string) string {
+		return strings.ToUpper(s)
+	})
+	d := localize.NewDebugReader(localize.Chain(r, upper))
+	require.Equal(t, "[818274] HELLO", d.Text("hello"))
+}
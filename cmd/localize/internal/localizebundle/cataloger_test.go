@@ -0,0 +1,32 @@
+package localizebundle
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// TestCataloger verifies that the generated readers are found as
+// Cataloger through the readers wrapping them in a bundle.
+func TestCataloger(t *testing.T) {
+	b, err := localize.NewWithOptions(language.English, localize.Options{
+		Strict:            true,
+		NativeDigits:      true,
+		QuantityFormatter: localize.FormatQuantity,
+	}, slices.Collect(Readers())...)
+	require.NoError(t, err)
+
+	c := b.Coverage()
+	require.Len(t, c, 2)
+	require.Positive(t, c[language.English].Total)
+	require.Equal(t, c[language.English].Total, c[language.German].Total)
+
+	var messages int
+	for range (CatalogEn{}).Messages() {
+		messages++
+	}
+	require.Equal(t, messages, c[language.English].Total)
+}
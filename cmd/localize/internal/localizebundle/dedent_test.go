@@ -0,0 +1,53 @@
+package localizebundle
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDedentCacheLimit verifies that Block and PluralBlock texts built at
+// runtime don't grow the dedent caches of the bundle without limit.
+func TestDedentCacheLimit(t *testing.T) {
+	r := CatalogEn{}
+	for i := range dedentCacheLimit + 100 {
+		n := strconv.Itoa(i)
+		require.Equal(t, "Text "+n, r.Block("\n\t\tText "+n+"\n\t"))
+		require.Equal(t, "2 texts "+n, r.PluralBlock(localize.Forms{
+			Other: "\n\t\t%d texts " + n + "\n\t",
+		}, 2))
+	}
+	size := func(m *sync.Map) (n int) {
+		for range m.Range {
+			n++
+		}
+		return n
+	}
+	require.Equal(t, dedentCacheLimit, size(&dedentCache))
+	require.Equal(t, dedentCacheLimit, size(&dedentFormsCache))
+
+	// Texts beyond the limit are still dedented.
+	require.Equal(t, "Uncached", r.Block("\n\t\tUncached\n\t"))
+}
+
+// TestPluralBlockTranslationIndentation verifies that the indentation
+// of translated PluralBlock texts is preserved.
+func TestPluralBlockTranslationIndentation(t *testing.T) {
+	const other = "%d lines:\n  indented"
+	catalogDePlural[other] = localize.Forms{
+		One:   "%d Zeile:\n    eingerückt",
+		Other: "  %d Zeilen:\n    eingerückt",
+	}
+	t.Cleanup(func() { delete(catalogDePlural, other) })
+
+	forms := localize.Forms{
+		One:   "\n\t\t%d line:\n\t\t  indented\n\t",
+		Other: "\n\t\t%d lines:\n\t\t  indented\n\t",
+	}
+	require.Equal(t, "1 Zeile:\n    eingerückt", CatalogDe{}.PluralBlock(forms, 1))
+	require.Equal(t, "  2 Zeilen:\n    eingerückt", CatalogDe{}.PluralBlock(forms, 2))
+	require.Equal(t, "2 lines:\n  indented", CatalogEn{}.PluralBlock(forms, 2))
+}
@@ -1,157 +1,2629 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"cmp"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"iter"
+	"maps"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime/pprof"
 	"slices"
 	"strings"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/cmd/localize/internal/localizebundle"
 	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/audit"
+	"github.com/romshark/localize/internal/badge"
 	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/clidoc"
 	"github.com/romshark/localize/internal/codeparser"
 	"github.com/romshark/localize/internal/config"
+	"github.com/romshark/localize/internal/coverage"
+	"github.com/romshark/localize/internal/dedup"
+	"github.com/romshark/localize/internal/domain"
+	"github.com/romshark/localize/internal/edition"
+	"github.com/romshark/localize/internal/errcode"
+	"github.com/romshark/localize/internal/example"
+	"github.com/romshark/localize/internal/exportstate"
+	"github.com/romshark/localize/internal/fuzzy"
+	"github.com/romshark/localize/internal/gendocs"
 	"github.com/romshark/localize/internal/gengo"
+	"github.com/romshark/localize/internal/goi18n"
+	"github.com/romshark/localize/internal/heading"
+	"github.com/romshark/localize/internal/lockfile"
+	"github.com/romshark/localize/internal/markup"
+	"github.com/romshark/localize/internal/migrate"
+	"github.com/romshark/localize/internal/msglock"
+	"github.com/romshark/localize/internal/msgmerge"
+	"github.com/romshark/localize/internal/msgseen"
+	"github.com/romshark/localize/internal/ordinal"
+	"github.com/romshark/localize/internal/pluralsample"
+	"github.com/romshark/localize/internal/protect"
+	"github.com/romshark/localize/internal/qareport"
+	"github.com/romshark/localize/internal/region"
+	"github.com/romshark/localize/internal/schedule"
+	"github.com/romshark/localize/internal/section"
+	"github.com/romshark/localize/internal/smoke"
+	"github.com/romshark/localize/internal/summary"
+	"github.com/romshark/localize/internal/termcolor"
+	"github.com/romshark/localize/internal/untranslated"
+	"github.com/romshark/localize/internal/vcs"
+	"github.com/romshark/localize/internal/whereis"
+	"github.com/romshark/localize/internal/xtextimport"
+	"github.com/romshark/localize/plugin"
+	"github.com/romshark/localize/typography"
+	"golang.org/x/text/language"
 	"mvdan.cc/gofumpt/format"
 )
 
 func main() {
-	if err := run(os.Args); err != nil {
-		fmt.Println("ERR:", err)
+	// Interrupts cancel the context to abort cleanly and release the bundle lock.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Args)
+	stop()
+	if err != nil {
+		// Prefix of the error a failed command exits with.
+		fmt.Println(stdoutPalette.Red(console.Text("ERR:")), err)
 		os.Exit(1)
 	}
 }
 
+//go:generate go run . generate -l en -p . -b internal/localizebundle
+//go:generate go run . config -o ../../localize.schema.json schema
+
+// console localizes the console output of all commands.
+// console is set by run and defaults to the source locale.
+var console localize.Reader = localizebundle.CatalogEn{}
+
+// palette and stdoutPalette color the console output
+// written to stderr and stdout respectively. Both are set by run.
+var palette, stdoutPalette termcolor.Palette
+
+// warnf prints a warning line to stderr.
+func warnf(format string, a ...any) {
+	fmt.Fprintln(os.Stderr, palette.Yellow(fmt.Sprintf(format, a...)))
+}
+
+// consoleReader returns the reader for the console output in locale lang,
+// or in the system locale if lang is language.Und.
+func consoleReader(lang language.Tag) localize.Reader {
+	b, err := localizebundle.New()
+	if err != nil {
+		panic(fmt.Errorf("initializing console localization: %w", err))
+	}
+	if lang == language.Und {
+		return b.ForSystem()
+	}
+	return b.ForLocale(lang)
+}
+
 var (
-	ErrSourceErrors    = errors.New("source code contains errors")
-	ErrNoCommand       = errors.New("no command")
-	ErrUnknownCommand  = errors.New("unknown command")
-	ErrAnalyzingSource = errors.New("analyzing sources")
+	ErrSourceErrors     = errors.New("source code contains errors")
+	ErrNoCommand        = errors.New("no command")
+	ErrUnknownCommand   = errors.New("unknown command")
+	ErrAnalyzingSource  = errors.New("analyzing sources")
+	ErrLimitsExceeded   = errors.New("limits exceeded")
+	ErrNoMatches        = errors.New("no matches")
+	ErrPluginFailed     = errors.New("plugin failed")
+	ErrHookFailed       = errors.New("post-generate hook failed")
+	ErrCatalogModified  = errors.New("catalog modified since the bundle was generated")
+	ErrNoSourceCatalog  = errors.New("bundle has no source catalog")
+	ErrNondeterministic = errors.New("generate output differs between runs")
+	ErrBundleCompile    = errors.New("generated bundle doesn't compile")
+	ErrCatalogExists    = errors.New("catalog already exists")
+	ErrNoSourceFile     = errors.New("no message file of the source locale")
+	ErrInvalidConfig    = errors.New("invalid configuration file")
+	ErrUntranslated     = errors.New("messages added since the release are untranslated")
+	ErrLookupMismatch   = errors.New("bundle lookups differ from the catalog")
+	ErrCatalogIssues    = errors.New("catalog issues found")
 )
 
-func run(osArgs []string) error {
-	if len(osArgs) < 2 {
-		return fmt.Errorf("%w, use either of: [generate,lint]", ErrNoCommand)
+func run(ctx context.Context, osArgs []string) error {
+	g, command, args, err := config.ParseCLIArgs(osArgs)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+	console = consoleReader(g.Lang)
+	palette = termcolor.New(g.Color, os.Stderr)
+	stdoutPalette = termcolor.New(g.Color, os.Stdout)
+	if command == "" {
+		config.WriteUsage(os.Stderr, g.Program)
+		return ErrNoCommand
+	}
+	r, ok := runners[command]
+	if !ok {
+		return fmt.Errorf("%w %q, use \"%s help\" to list all commands",
+			ErrUnknownCommand, command, g.Program)
+	}
+	return r(ctx, g, args)
+}
+
+// runners maps the names of all commands in config.Commands
+// to their implementations.
+//
+// TODO: implement lint command
+var runners map[string]func(ctx context.Context, g config.Global, args []string) error
+
+func init() {
+	// Initialized in init since runHelp refers to runners.
+	runners = map[string]func(
+		ctx context.Context, g config.Global, args []string,
+	) error{
+		"generate":            runGenerate,
+		"docs":                runDocs,
+		"badge":               runBadge,
+		"release-check":       runReleaseCheck,
+		"export-state":        runExportState,
+		"export-untranslated": runExportUntranslated,
+		"import-untranslated": runImportUntranslated,
+		"extract-unit":        runExtractUnit,
+		"whereis":             runWhereis,
+		"import-go-i18n":      runImportGoI18n,
+		"import-x-text":       runImportXText,
+		"migrate-strings":     runMigrateStrings,
+		"dedup":               runDedup,
+		"check":               runCheck,
+		"fmt":                 runFmt,
+		"merge":               runMerge,
+		"trim":                runTrim,
+		"plural-tests":        runPluralTests,
+		"smoke":               runSmoke,
+		"selftest":            runSelftest,
+		"example":             runExample,
+		"completions":         runCompletions,
+		"config":              runConfig,
+		"man":                 runMan,
+		"help":                runHelp,
+	}
+}
+
+func runHelp(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsHelp(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+	if conf.Command == nil {
+		config.WriteUsage(os.Stdout, g.Program)
+		return nil
+	}
+	conf.Command.WriteUsage(os.Stdout, g.Program)
+	return nil
+}
+
+// writeProfile writes the time spent on the top slowest pkgs to w
+// as a table, all pkgs are written if top is 0.
+func writeProfile(
+	w io.Writer, load time.Duration, pkgs []codeparser.PackageProfile, top int,
+) {
+	// Heading of the table of the time spent on each package (-profile).
+	fmt.Fprintf(w, console.Text("Time by package (loading total %s):")+"\n",
+		load.Round(time.Millisecond))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "total\tparse\ttype-check\tinspect\tfiles\t\tpackage\n")
+	shown := pkgs
+	if top > 0 && len(shown) > top {
+		shown = shown[:top]
+	}
+	for _, p := range shown {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t\t%s\n",
+			p.Total().Round(time.Microsecond), p.Parse.Round(time.Microsecond),
+			p.TypeCheck.Round(time.Microsecond), p.Inspect.Round(time.Microsecond),
+			p.Files, p.PkgPath)
+	}
+	_ = tw.Flush()
+	if n := len(pkgs) - len(shown); n > 0 {
+		// Number of faster packages omitted from the -profile table.
+		fmt.Fprintln(w, console.Plural(localize.Forms{
+			One:   "%d more package",
+			Other: "%d more packages",
+		}, n))
+	}
+}
+
+// lockFileName is the name of the advisory lock file
+// created in the bundle package directory during generation.
+const lockFileName = ".localize.lock"
+
+func runGenerate(ctx context.Context, g config.Global, args []string) error {
+	start := time.Now()
+	conf, err := config.ParseCLIArgsGenerate(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	if conf.CPUProfilePath != "" {
+		f, err := os.Create(conf.CPUProfilePath)
+		if err != nil {
+			return fmt.Errorf("creating CPU profile: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return fmt.Errorf("starting CPU profile: %w", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+	if conf.Profile {
+		conf.Load.Profile = codeparser.NewProfile()
+	}
+	if conf.TemplateDir != "" {
+		// Invalid templates fail before any catalog is written.
+		if _, err := gengo.ParseTemplate(conf.TemplateDir); err != nil {
+			return fmt.Errorf("parsing templates: %w", err)
+		}
+	}
+
+	for _, o := range conf.PluralOverrides {
+		if err := cldr.SetOverride(o); err != nil {
+			return fmt.Errorf("overriding plural forms: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(conf.BundlePkgPath, 0o755); err != nil {
+		return fmt.Errorf("creating bundle package directory: %w", err)
+	}
+
+	// Prevent concurrent runs from interleaving catalog writes.
+	lock, err := lockfile.Acquire(
+		filepath.Join(conf.BundlePkgPath, lockFileName), lockfile.Options{
+			Wait:       conf.LockWait,
+			StaleAfter: conf.LockStaleAfter,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("locking bundle: %w", err)
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			// Error releasing the lock file of the bundle.
+			fmt.Fprintf(os.Stderr, console.Text("releasing bundle lock: %v")+"\n", err)
+		}
+	}()
+
+	if conf.Fix {
+		fixed, err := codeparser.FixLanguageHeaders(conf.BundlePkgPath)
+		if err != nil {
+			return fmt.Errorf("fixing Language headers: %w", err)
+		}
+		if !conf.QuietMode {
+			for _, f := range fixed {
+				// The Language header of a catalog file was corrected.
+				fmt.Fprintf(os.Stderr,
+					console.Text("fixed Language header of %s")+"\n", f)
+			}
+		}
+	}
+
+	// Translation catalogs edited on Windows keep their BOM and line endings.
+	poEncoder := gettext.Encoder{
+		PreserveFormat:    true,
+		CompactReferences: conf.CompactReferences,
+	}
+
+	timer := summary.NewTimer(start)
+	collection, bundle, stats, srcErrs, err := codeparser.Parse(
+		ctx, conf.SrcPathPattern, conf.BundlePkgPath, conf.ImportPath, conf.Locale,
+		conf.Dedent, conf.TrimPath, conf.QuietMode, conf.VerboseMode, conf.Load,
+	)
+	if errors.Is(err, codeparser.ErrLanguageMismatch) {
+		return fmt.Errorf("%w: %w (use -fix to rewrite the header)",
+			ErrAnalyzingSource, err)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAnalyzingSource, err)
+	}
+	if p := conf.Load.Profile; p != nil {
+		// Printed even in quiet mode since it's explicitly requested.
+		writeProfile(os.Stderr, p.Load, p.Packages(), conf.ProfileTop)
+	}
+
+	for _, importPath := range conf.Imports {
+		lib, err := codeparser.LoadLibrary(ctx, conf.SrcPathPattern, importPath)
+		if err != nil {
+			return fmt.Errorf("loading library bundle: %w", err)
+		}
+		added, err := collection.Import(lib)
+		if err != nil {
+			return fmt.Errorf("importing library bundle: %w", err)
+		}
+		if !conf.QuietMode {
+			// Progress: messages of a library bundle were added to the collection.
+			fmt.Fprintf(os.Stderr, console.Text("imported %d messages from %s")+"\n",
+				added, importPath)
+		}
+	}
+	if len(conf.Terms) > 0 {
+		srcErrs = append(srcErrs, collection.CheckTerms(conf.Terms)...)
+	}
+
+	if err := fallBackPluralForms(
+		maps.Keys(bundle.CatalogParts), conf.PluralFallback, conf.QuietMode,
+	); err != nil {
+		return err
+	}
+
+	if !conf.QuietMode {
+		warnCatalogIssues(bundle)
+		warnSkippedEntries(bundle)
+	}
+
+	if err := checkCatalogHashes(conf, bundle); err != nil {
+		return err
+	}
+
+	srcErrs = classifySourceErrors(conf, srcErrs)
+	errCount := 0
+	for _, e := range srcErrs {
+		if e.Severity == codeparser.SeverityError {
+			errCount++
+		}
+	}
+	if conf.ErrorFormat == "json" {
+		if len(srcErrs) > 0 {
+			if err := writeSourceErrorsJSON(os.Stdout, srcErrs); err != nil {
+				return fmt.Errorf("encoding source errors: %w", err)
+			}
+		}
+	} else {
+		if !conf.QuietMode {
+			for _, e := range srcErrs {
+				if e.Severity == codeparser.SeverityWarning {
+					// Prefix of warnings.
+					warnf(console.Text("WARNING: %v"),
+						fmt.Sprintf("%s:%d:%d: %s [%s]",
+							e.Filename, e.Line, e.Column, e.Err.Error(), e.Code()))
+				}
+			}
+		}
+		if errCount > 0 {
+			// Heading of the list of source code errors.
+			fmt.Fprintln(os.Stderr,
+				palette.Red(console.Cardinal("SOURCE ERRORS (%d):", errCount)))
+			for _, e := range srcErrs {
+				if e.Severity == codeparser.SeverityError {
+					fmt.Fprintf(os.Stderr, " %s: %s\n", palette.Cyan(fmt.Sprintf(
+						"%s:%d:%d", e.Filename, e.Line, e.Column)), e.Err.Error())
+				}
+			}
+		}
+	}
+	if errCount > 0 {
+		if err := writeReport(conf, bundle, srcErrs, nil); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		return ErrSourceErrors
+	}
+
+	migrated, err := migrateHashes(conf, bundle, collection)
+	if err != nil {
+		return fmt.Errorf("migrating message hashes: %w", err)
+	}
+
+	headTxt, err := readOrCreateHeadTxt(conf)
+	if err != nil {
+		return err
+	}
+
+	po := collection.MakePO(headTxt)
+
+	if err := checkLimits(conf, collection, bundle, po); err != nil {
+		return err
+	}
+
+	var messageIDs *msglock.Registry
+	if conf.MessageIDs {
+		if messageIDs, err = assignMessageIDs(conf, collection, migrated); err != nil {
+			return fmt.Errorf("assigning message IDs: %w", err)
+		}
+		for i := range po.Messages.List {
+			m := &po.Messages.List[i]
+			id, _ := messageIDs.ID(m.Msgctxt.Text.String())
+			msglock.SetComment(m, id)
+			sortCommentsByType(m)
+		}
+	}
+
+	var seen map[string]msgseen.Seen
+	if conf.TrackSeen {
+		if seen, err = trackSeen(conf, bundle, po); err != nil {
+			return fmt.Errorf("tracking seen runs: %w", err)
+		}
+	}
+
+	vendored, err := codeparser.ParseVendorCatalogs(conf.VendorCatalogs)
+	if err != nil {
+		return fmt.Errorf("parsing vendored catalogs: %w", err)
+	}
+	if !conf.QuietMode {
+		for _, locale := range slices.SortedFunc(maps.Keys(vendored),
+			func(a, b language.Tag) int { return strings.Compare(a.String(), b.String()) },
+		) {
+			if _, ok := bundle.Catalogs[locale]; !ok {
+				// Warning about vendored translations of a locale
+				// the bundle has no translation catalog for.
+				warnf(console.Text("WARNING: no translation catalog for vendored locale %s"),
+					locale)
+			}
+		}
+	}
+
+	timer.End("analyze")
+
+	// Abort before each write, catalogs are never written partially.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	sourceCatalog, err := writeSourceCatalog(conf, poEncoder, po)
+	if err != nil {
+		return fmt.Errorf("writing native catalog: %w", err)
+	}
+	written := []string{sourceCatalog}
+
+	templates, err := writeTranslationTemplate(conf, poEncoder, po)
+	if err != nil {
+		return fmt.Errorf("writing catalog.pot: %w", err)
+	}
+	written = append(written, templates...)
+	timer.End("templates")
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// The source catalog of bundle is outdated, the metadata of the
+	// generated readers is that of the source catalog just written.
+	genBundle := bundle.WithVendored(vendored)
+	genBundle.Source = &codeparser.POFile{Path: sourceCatalog, FilePO: po}
+	goBundle, err := generateGoBundle(conf, headTxt, collection, genBundle)
+	if err != nil {
+		return fmt.Errorf("writing Go bundle: %w", err)
+	}
+	written = append(written, goBundle...)
+	timer.End("bundle")
+
+	changes, err := updateTranslationCatalogs(
+		ctx, conf, bundle, collection, messageIDs, seen, poEncoder,
+	)
+	if err != nil {
+		return fmt.Errorf("updating translation catalogs: %w", err)
+	}
+	written = append(written, changes.Files...)
+	timer.End("catalogs")
+
+	if conf.Audit {
+		if err := appendAuditEntry(conf, changes, written); err != nil {
+			return fmt.Errorf("writing audit log: %w", err)
+		}
+	}
+
+	if err := runPlugins(ctx, conf, bundle, po); err != nil {
+		return fmt.Errorf("running output plugins: %w", err)
+	}
+	timer.End("plugins")
+
+	if err := writeReport(conf, bundle, srcErrs, &po); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	timer.End("report")
+
+	timeTotal := time.Since(start)
+	if conf.SummaryPath != "" || len(conf.PostGenerate) > 0 {
+		slices.Sort(written)
+		sum := summary.Summary{
+			Messages:             stats.Messages,
+			Locales:              changes.Locales,
+			Files:                written,
+			Phases:               timer.Phases(),
+			TimeTotalNanoseconds: timeTotal.Nanoseconds(),
+		}
+		if conf.SummaryPath != "" {
+			if err := summary.Write(conf.SummaryPath, sum); err != nil {
+				return err
+			}
+		}
+		if err := runPostGenerateHooks(ctx, conf, sum); err != nil {
+			return fmt.Errorf("running post-generate hooks: %w", err)
+		}
+	}
+	switch {
+	case conf.StatsFormat == "json":
+		// Always printed to stdout since it's explicitly requested.
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		if err := e.Encode(struct {
+			*codeparser.Statistics
+			TimeTotalNanoseconds int64 `json:"timeTotalNanoseconds"`
+		}{
+			Statistics:           stats,
+			TimeTotalNanoseconds: timeTotal.Nanoseconds(),
+		}); err != nil {
+			return fmt.Errorf("encoding statistics: %w", err)
+		}
+	case !conf.QuietMode:
+		w := os.Stderr
+		_, _ = fmt.Fprintf(w, "Text/Block: %d/%d\n",
+			stats.TextTotal, stats.BlockTotal)
+		_, _ = fmt.Fprintf(w,
+			"Plural/PluralBlock/Cardinal/PluralRange/PluralOrdinal: %d/%d/%d/%d/%d\n",
+			stats.PluralTotal, stats.PluralBlockTotal, stats.CardinalTotal,
+			stats.PluralRangeTotal, stats.PluralOrdinalTotal)
+		// Statistics: number of unique messages.
+		_, _ = fmt.Fprintf(w, console.Text("Messages: %d")+"\n", stats.Messages)
+		if stats.Scheduled > 0 {
+			// Statistics: number of time-limited messages.
+			_, _ = fmt.Fprintf(w, console.Text("Scheduled messages: %d")+"\n",
+				stats.Scheduled)
+			// Statistics: number of scheduled messages not shown yet.
+			_, _ = fmt.Fprintf(w, console.Text("Embargoed messages: %d")+"\n",
+				stats.Embargoed)
+			// Statistics: number of scheduled messages no longer shown.
+			_, _ = fmt.Fprintf(w, console.Text("Expired messages: %d")+"\n",
+				stats.Expired)
+		}
+		// Statistics: number of calls with identical messages merged into one.
+		_, _ = fmt.Fprintf(w, console.Text("Calls merged: %d")+"\n", stats.Merges)
+		// Statistics: number of Go source files scanned.
+		_, _ = fmt.Fprintf(w, console.Text("files scanned: %d")+"\n",
+			stats.FilesTraversed)
+		// Statistics: total duration of the run.
+		_, _ = fmt.Fprintf(w, console.Text("time total: %s")+"\n", timeTotal.String())
+	}
+
+	return nil
+}
+
+func runDocs(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsDocs(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	bundle, err := codeparser.ParseBundleDir(conf.BundlePkgPath)
+	if err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+
+	if err := fallBackPluralForms(
+		maps.Keys(bundle.CatalogParts), conf.PluralFallback, conf.QuietMode,
+	); err != nil {
+		return err
+	}
+
+	if conf.Edition != "" && bundle.Source != nil {
+		bundle.Source.FilePO = edition.Filter(bundle.Source.FilePO, conf.Edition)
+	}
+	if conf.Region != (language.Region{}) && bundle.Source != nil {
+		bundle.Source.FilePO = region.Filter(bundle.Source.FilePO, conf.Region)
+	}
+
+	site, err := gendocs.Make(bundle)
+	if err != nil {
+		return fmt.Errorf("making documentation: %w", err)
+	}
+
+	if err := gendocs.WriteDir(
+		conf.OutPath, gendocs.Format(conf.Format), site,
+	); err != nil {
+		return fmt.Errorf("writing documentation: %w", err)
+	}
+
+	if !conf.QuietMode {
+		// The documentation site was written.
+		fmt.Fprintf(os.Stderr,
+			console.Text("documentation written to %s")+"\n", conf.OutPath)
+	}
+	return nil
+}
+
+func runBadge(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsBadge(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	bundle, err := codeparser.ParseBundleDir(conf.BundlePkgPath)
+	if err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	if bundle.Source == nil {
+		return fmt.Errorf("%w: %q", ErrNoSourceCatalog, conf.BundlePkgPath)
+	}
+	if conf.Edition != "" {
+		bundle.Source.FilePO = edition.Filter(bundle.Source.FilePO, conf.Edition)
+	}
+	if conf.Region != (language.Region{}) {
+		bundle.Source.FilePO = region.Filter(bundle.Source.FilePO, conf.Region)
+	}
+
+	var c coverage.Coverage
+	if catalog, ok := bundle.Catalogs[conf.Locale]; ok {
+		c = coverage.Of(bundle.Source.FilePO, catalog.FilePO)
+	} else if conf.Locale == bundle.SourceLocale {
+		c = coverage.Of(bundle.Source.FilePO, bundle.Source.FilePO)
+	} else {
+		return fmt.Errorf("bundle has no catalog for locale %q", conf.Locale)
+	}
+
+	b := badge.Make(conf.Label, c)
+	var buf bytes.Buffer
+	if conf.Format == "json" {
+		err = b.WriteJSON(&buf)
+	} else {
+		err = b.WriteSVG(&buf)
+	}
+	if err != nil {
+		return fmt.Errorf("rendering badge: %w", err)
+	}
+
+	if conf.OutPath == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(conf.OutPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing badge: %w", err)
+	}
+	if !conf.QuietMode {
+		// The coverage badge file was written.
+		fmt.Fprintf(os.Stderr, console.Text("badge written to %s")+"\n", conf.OutPath)
+	}
+	return nil
+}
+
+func runReleaseCheck(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsReleaseCheck(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	bundle, err := codeparser.ParseBundleDir(conf.BundlePkgPath)
+	if err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	if bundle.Source == nil {
+		return fmt.Errorf("%w: %q", ErrNoSourceCatalog, conf.BundlePkgPath)
+	}
+
+	// All messages are new if the source catalog didn't exist at the release.
+	previous := gettext.FilePO{File: &gettext.File{}}
+	b, err := vcs.Git{}.Show(bundle.Source.Path, conf.Since)
+	switch {
+	case errors.Is(err, vcs.ErrNotFound):
+	case err != nil:
+		return fmt.Errorf("reading source catalog at %s: %w", conf.Since, err)
+	default:
+		previous, err = gettext.NewDecoder().DecodePOBytes(
+			bundle.Source.Path+"@"+conf.Since, b,
+		)
+		if err != nil {
+			return fmt.Errorf("decoding source catalog at %s: %w", conf.Since, err)
+		}
+	}
+	added := coverage.Added(previous, bundle.Source.FilePO)
+
+	locales := conf.Locales
+	if len(locales) < 1 {
+		locales = slices.SortedFunc(
+			maps.Keys(bundle.Catalogs), func(a, b language.Tag) int {
+				return strings.Compare(a.String(), b.String())
+			},
+		)
+	}
+	untranslated := 0
+	w := os.Stdout
+	for _, locale := range locales {
+		if locale == bundle.SourceLocale {
+			continue
+		}
+		catalog, ok := bundle.Catalogs[locale]
+		if !ok {
+			return fmt.Errorf("bundle has no catalog for locale %q", locale)
+		}
+		todo := coverage.Untranslated(added, catalog.FilePO)
+		untranslated += len(todo)
+		if len(todo) < 1 {
+			continue
+		}
+		// Number of untranslated messages of a locale added since the release.
+		_, _ = fmt.Fprintf(w, "%s: %s\n", locale, console.Plural(localize.Forms{
+			One:   "%d untranslated message added since the release",
+			Other: "%d untranslated messages added since the release",
+		}, len(todo)))
+		for _, m := range todo {
+			_, _ = fmt.Fprintf(w, "  %s: %q\n", m.Msgctxt.Text.String(), m.Msgid.Text.String())
+			for _, c := range m.Msgctxt.Comments.Text {
+				if c.Type == gettext.CommentTypeReference {
+					_, _ = fmt.Fprintf(w, "    reference: %s\n", c.Value)
+				}
+			}
+		}
+	}
+	if untranslated > 0 {
+		return fmt.Errorf("%w: %d since %s", ErrUntranslated, untranslated, conf.Since)
+	}
+	if !g.QuietMode {
+		// Number of messages added since the release, all of them translated.
+		fmt.Fprintln(os.Stderr, palette.Green(console.Plural(localize.Forms{
+			One:   "%d message added since the release, translated",
+			Other: "%d messages added since the release, all translated",
+		}, len(added))))
+	}
+	return nil
+}
+
+func runExportState(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsExportState(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	bundle, err := codeparser.ParseBundleDir(conf.BundlePkgPath)
+	if err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+
+	if err := fallBackPluralForms(
+		maps.Keys(bundle.CatalogParts), conf.PluralFallback, conf.QuietMode,
+	); err != nil {
+		return err
+	}
+
+	state, err := exportstate.Make(bundle)
+	if err != nil {
+		return fmt.Errorf("making state: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := state.WriteJSON(&buf); err != nil {
+		return fmt.Errorf("encoding state: %w", err)
+	}
+
+	if conf.OutPath == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(conf.OutPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	if !conf.QuietMode {
+		// The bundle state JSON file was written.
+		fmt.Fprintf(os.Stderr, console.Text("state written to %s")+"\n", conf.OutPath)
+	}
+	return nil
+}
+
+func runExportUntranslated(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsExportUntranslated(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	bundle, err := codeparser.ParseBundleDir(conf.BundlePkgPath)
+	if err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	catalog, ok := bundle.Catalogs[conf.Locale]
+	if !ok {
+		return fmt.Errorf("bundle has no catalog for locale %q", conf.Locale)
+	}
+
+	exported := untranslated.Export(catalog.FilePO)
+	if conf.Tokenize {
+		untranslated.Tokenize(exported)
+	}
+	var buf bytes.Buffer
+	if err := (gettext.Encoder{}).EncodePO(exported, &buf); err != nil {
+		return fmt.Errorf("encoding catalog: %w", err)
+	}
+
+	if conf.OutPath == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(conf.OutPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing catalog: %w", err)
+	}
+	if !conf.QuietMode {
+		// Number of untranslated and fuzzy messages exported.
+		fmt.Fprintln(os.Stderr, console.Plural(localize.Forms{
+			One:   "%d message to translate",
+			Other: "%d messages to translate",
+		}, len(exported.Messages.List)))
+		// The catalog of messages to translate was written.
+		fmt.Fprintf(os.Stderr, console.Text("messages to translate written to %s")+"\n",
+			conf.OutPath)
+	}
+	return nil
+}
+
+func runImportUntranslated(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsImportUntranslated(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	src, err := os.ReadFile(conf.InPath)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+	exported, err := gettext.NewDecoder().DecodePOBytes(conf.InPath, src)
+	if err != nil {
+		return fmt.Errorf("decoding file: %w", err)
+	}
+
+	// Prevent concurrent generate runs from overwriting the imported translations.
+	lock, err := lockfile.Acquire(
+		filepath.Join(conf.BundlePkgPath, lockFileName), lockfile.Options{},
+	)
+	if err != nil {
+		return fmt.Errorf("locking bundle: %w", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	bundle, err := codeparser.ParseBundleDir(conf.BundlePkgPath)
+	if err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	parts, ok := bundle.CatalogParts[conf.Locale]
+	if !ok {
+		return fmt.Errorf("bundle has no catalog for locale %q", conf.Locale)
+	}
+	catalogs := make([]gettext.FilePO, len(parts))
+	for i, p := range parts {
+		catalogs[i] = p.FilePO
+	}
+	result, modified, err := untranslated.Import(catalogs, exported)
+	if err != nil {
+		return fmt.Errorf("importing %s: %w", conf.InPath, err)
+	}
+
+	poEncoder := gettext.Encoder{PreserveFormat: true}
+	for _, i := range modified {
+		var buf bytes.Buffer
+		if err := poEncoder.EncodePO(catalogs[i], &buf); err != nil {
+			return fmt.Errorf("encoding catalog: %w", err)
+		}
+		if err := os.WriteFile(parts[i].Path, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("writing catalog: %w", err)
+		}
+	}
+
+	if !conf.QuietMode {
+		for _, h := range result.Missing {
+			// Warning about a translated message removed from the catalog.
+			warnf(console.Text("WARNING: message %s isn't in the catalog anymore"), h)
+		}
+		for _, h := range result.Changed {
+			// Warning about a translated message whose source text changed.
+			warnf(console.Text("WARNING: source text of message %s changed, skipped"), h)
+		}
+		for _, h := range result.Conflicts {
+			// Warning about a message translated differently in the catalog.
+			warnf(console.Text(
+				"WARNING: message %s was translated in the catalog since, skipped",
+			), h)
+		}
+		for _, inv := range result.Invalid {
+			// Warning about a translation with corrupted placeholder tokens.
+			warnf(console.Text("WARNING: invalid placeholders in message %s, skipped: %v"),
+				inv.Hash, inv.Err)
+		}
+		// Number of translations imported into the catalog.
+		fmt.Fprintln(os.Stderr, console.Plural(localize.Forms{
+			One:   "%d translation imported",
+			Other: "%d translations imported",
+		}, result.Imported))
+		if result.Untranslated > 0 {
+			// Number of messages of the imported file still to translate.
+			fmt.Fprintln(os.Stderr, console.Plural(localize.Forms{
+				One:   "%d message still untranslated",
+				Other: "%d messages still untranslated",
+			}, result.Untranslated))
+		}
+	}
+	return nil
+}
+
+func runExtractUnit(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsExtractUnit(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	unit, err := codeparser.ReadUnit(conf.UnitPath)
+	if err != nil {
+		return fmt.Errorf("reading unit: %w", err)
+	}
+	collection, _, _, srcErrs, err := codeparser.Parse(
+		ctx, unit.Dir, "", "", conf.Locale, conf.Dedent, conf.TrimPath,
+		true, false, codeparser.LoadOptions{
+			DeriveOne:     conf.DeriveOne,
+			Normalization: conf.Normalization,
+			Hash:          conf.Hash,
+			Unit:          unit,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAnalyzingSource, err)
+	}
+
+	errCount := 0
+	for _, e := range srcErrs {
+		if e.Severity == codeparser.SeverityError {
+			errCount++
+			fmt.Fprintf(os.Stderr, "%s:%d:%d: %s\n",
+				e.Filename, e.Line, e.Column, e.Err.Error())
+		} else if !conf.QuietMode {
+			// Prefix of warnings.
+			warnf(console.Text("WARNING: %v"),
+				fmt.Sprintf("%s:%d:%d: %s [%s]",
+					e.Filename, e.Line, e.Column, e.Err.Error(), e.Code()))
+		}
+	}
+	if errCount > 0 {
+		return ErrSourceErrors
+	}
+
+	var buf bytes.Buffer
+	if err := collection.WriteJSON(&buf); err != nil {
+		return fmt.Errorf("encoding messages: %w", err)
+	}
+	if conf.OutPath == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(conf.OutPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing messages: %w", err)
+	}
+	return nil
+}
+
+func runWhereis(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsWhereis(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	bundle, err := codeparser.ParseBundleDir(conf.BundlePkgPath)
+	if err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	// Warnings would pollute the output, only the search results matter.
+	if err := fallBackPluralForms(
+		maps.Keys(bundle.CatalogParts), conf.PluralFallback, true,
+	); err != nil {
+		return err
+	}
+	site, err := gendocs.Make(bundle)
+	if err != nil {
+		return fmt.Errorf("reading messages: %w", err)
+	}
+
+	matches := whereis.Find(site, conf.Text, conf.Locale)
+	if len(matches) < 1 {
+		return fmt.Errorf("%w: %q", ErrNoMatches, conf.Text)
+	}
+
+	if conf.Format == "json" {
+		type form struct {
+			Form string `json:"form,omitempty"`
+			Text string `json:"text"`
+		}
+		type match struct {
+			Locale      string   `json:"locale"`
+			Form        string   `json:"form,omitempty"`
+			Text        string   `json:"text"`
+			Msgctxt     string   `json:"msgctxt"`
+			Description string   `json:"description,omitempty"`
+			Source      []form   `json:"source"`
+			References  []string `json:"references"`
+		}
+		l := make([]match, len(matches))
+		for i, m := range matches {
+			l[i] = match{
+				Locale:      m.Locale,
+				Form:        m.Form.Name,
+				Text:        m.Form.Text,
+				Msgctxt:     m.Message.Hash,
+				Description: m.Message.Description,
+				References:  m.Message.References,
+			}
+			for _, f := range m.Message.Source {
+				l[i].Source = append(l[i].Source, form{Form: f.Name, Text: f.Text})
+			}
+		}
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		return e.Encode(l)
+	}
+
+	w := os.Stdout
+	for _, m := range matches {
+		form := ""
+		if m.Form.Name != "" {
+			form = " (" + m.Form.Name + ")"
+		}
+		_, _ = fmt.Fprintf(w, "%s%s: %q\n", m.Locale, form, m.Form.Text)
+		_, _ = fmt.Fprintf(w, "  msgctxt: %s\n", m.Message.Hash)
+		if m.Message.Description != "" {
+			_, _ = fmt.Fprintf(w, "  description: %s\n", m.Message.Description)
+		}
+		for _, f := range m.Message.Source {
+			if f.Name != "" {
+				_, _ = fmt.Fprintf(w, "  source (%s): %q\n", f.Name, f.Text)
+			} else {
+				_, _ = fmt.Fprintf(w, "  source: %q\n", f.Text)
+			}
+		}
+		for _, r := range m.Message.References {
+			_, _ = fmt.Fprintf(w, "  reference: %s\n", r)
+		}
+	}
+	return nil
+}
+
+// classifySourceErrors removes the ignored warnings from srcErrs
+// and escalates warnings to errors as configured by conf.
+func classifySourceErrors(
+	conf *config.ConfigGenerate, srcErrs []codeparser.ErrorSrc,
+) []codeparser.ErrorSrc {
+	classified := srcErrs[:0]
+	for _, e := range srcErrs {
+		if e.Severity == codeparser.SeverityWarning {
+			code := e.Code()
+			if slices.Contains(conf.WarningsIgnored, code) {
+				continue
+			}
+			if conf.WarningsAsErrorsAll || slices.Contains(conf.WarningsAsErrors, code) {
+				e.Severity = codeparser.SeverityError
+			}
+		}
+		classified = append(classified, e)
+	}
+	return classified
+}
+
+func runImportGoI18n(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsImportGoI18n(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	messages := map[language.Tag][]goi18n.Message{}
+	for _, file := range conf.Files {
+		locale, err := goi18n.LocaleOfFile(file)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("reading message file: %w", err)
+		}
+		l, err := goi18n.ParseFile(file, data)
+		if err != nil {
+			return fmt.Errorf("parsing message file %q: %w", file, err)
+		}
+		messages[locale] = append(messages[locale], l...)
+	}
+	source, ok := messages[conf.Locale]
+	if !ok {
+		return fmt.Errorf("%w %s", ErrNoSourceFile, conf.Locale)
+	}
+	conversions := goi18n.Convert(source)
+
+	locales := slices.SortedFunc(maps.Keys(messages), func(a, b language.Tag) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	for _, locale := range locales {
+		if locale == conf.Locale {
+			continue
+		}
+		fileName := filepath.Join(conf.BundlePkgPath, "catalog."+locale.String()+".po")
+		if _, err := os.Stat(fileName); err == nil {
+			return fmt.Errorf("%w: %s", ErrCatalogExists, fileName)
+		}
+		po, notes, err := goi18n.Catalog(locale, conversions, messages[locale])
+		if err != nil {
+			return fmt.Errorf("converting messages of locale %s: %w", locale, err)
+		}
+		if !conf.QuietMode {
+			for _, n := range notes {
+				// Warning about a translation that couldn't be converted completely.
+				warnf(console.Text("WARNING: locale %s: %s"), locale, n)
+			}
+		}
+		if err := os.MkdirAll(conf.BundlePkgPath, 0o755); err != nil {
+			return fmt.Errorf("creating bundle package directory: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := (gettext.Encoder{}).EncodePO(po, &buf); err != nil {
+			return fmt.Errorf("encoding catalog: %w", err)
+		}
+		if err := os.WriteFile(fileName, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("writing catalog: %w", err)
+		}
+		if !conf.QuietMode {
+			// A translation catalog converted from the message files of another
+			// localization library was written.
+			fmt.Fprintf(os.Stderr, console.Text("catalog written to %s")+"\n", fileName)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := goi18n.WriteRewrites(&buf, conversions); err != nil {
+		return fmt.Errorf("writing code rewrites: %w", err)
+	}
+	if conf.OutPath == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(conf.OutPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing code rewrites: %w", err)
+	}
+	if !conf.QuietMode {
+		// The file listing the suggested source code rewrites was written.
+		fmt.Fprintf(os.Stderr, console.Text("code rewrites written to %s")+"\n",
+			conf.OutPath)
+	}
+	return nil
+}
+
+func runImportXText(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsImportXText(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	var source *xtextimport.Catalog
+	var translations []*xtextimport.Catalog
+	for _, file := range conf.Files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("reading catalog: %w", err)
+		}
+		c, err := xtextimport.ParseCatalog(file, data)
+		if err != nil {
+			return fmt.Errorf("parsing catalog %q: %w", file, err)
+		}
+		if c.Locale == conf.Locale && source == nil {
+			source = c
+			continue
+		}
+		translations = append(translations, c)
+	}
+	if source == nil {
+		return fmt.Errorf("%w %s", ErrNoSourceFile, conf.Locale)
+	}
+	conversions := xtextimport.Convert(source, translations)
+
+	sites, err := xtextimport.Scan(ctx, conf.SrcPathPattern)
+	if err != nil {
+		return fmt.Errorf("scanning source code: %w", err)
+	}
+
+	for _, c := range translations {
+		if c.Locale == conf.Locale {
+			continue
+		}
+		fileName := filepath.Join(conf.BundlePkgPath, "catalog."+c.Locale.String()+".po")
+		if _, err := os.Stat(fileName); err == nil {
+			return fmt.Errorf("%w: %s", ErrCatalogExists, fileName)
+		}
+		po, notes, err := xtextimport.MakeCatalog(c, conversions)
+		if err != nil {
+			return fmt.Errorf("converting messages of locale %s: %w", c.Locale, err)
+		}
+		if !conf.QuietMode {
+			for _, n := range notes {
+				// Warning about a translation that couldn't be converted completely.
+				warnf(console.Text("WARNING: locale %s: %s"), c.Locale, n)
+			}
+		}
+		if err := os.MkdirAll(conf.BundlePkgPath, 0o755); err != nil {
+			return fmt.Errorf("creating bundle package directory: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := (gettext.Encoder{}).EncodePO(po, &buf); err != nil {
+			return fmt.Errorf("encoding catalog: %w", err)
+		}
+		if err := os.WriteFile(fileName, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("writing catalog: %w", err)
+		}
+		if !conf.QuietMode {
+			// A translation catalog converted from the message files of another
+			// localization library was written.
+			fmt.Fprintf(os.Stderr, console.Text("catalog written to %s")+"\n", fileName)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := xtextimport.WriteReport(&buf, sites, conversions); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	if conf.OutPath == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(conf.OutPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	if !conf.QuietMode {
+		// The report listing the message.Printer calls to convert was written.
+		fmt.Fprintf(os.Stderr, console.Text("report written to %s")+"\n", conf.OutPath)
+	}
+	return nil
+}
+
+func runMigrateStrings(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsMigrateStrings(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	var allowed map[string]bool
+	if conf.AllowlistPath != "" {
+		data, err := os.ReadFile(conf.AllowlistPath)
+		if err != nil {
+			return fmt.Errorf("reading allowlist: %w", err)
+		}
+		allowed = map[string]bool{}
+		for line := range strings.Lines(string(data)) {
+			line = strings.TrimRight(line, "\r\n")
+			if line != "" && !strings.HasPrefix(line, "#") {
+				allowed[line] = true
+			}
+		}
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	quit := false
+	opts := migrate.Options{
+		ReaderName: conf.ReaderName,
+		Select: func(c migrate.Candidate) (bool, error) {
+			if allowed != nil && !allowed[c.Text] ||
+				allowed == nil && !migrate.LooksLikeText(c.Text) {
+				return false, nil
+			}
+			if !conf.Interactive {
+				return true, nil
+			}
+			if quit {
+				return false, nil
+			}
+			// Question asking whether to rewrite a string literal.
+			// y rewrites it, n skips it and q skips all following strings.
+			fmt.Fprintf(os.Stderr, console.Text("%s: rewrite %q? [y/N/q] "), c.Pos, c.Text)
+			answer, err := stdin.ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return false, fmt.Errorf("reading answer: %w", err)
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+				return true, nil
+			case "q":
+				quit = true
+			}
+			return false, nil
+		},
+	}
+	result, err := migrate.Run(ctx, conf.PkgPattern, opts)
+	if err != nil {
+		return fmt.Errorf("rewriting strings: %w", err)
+	}
+
+	if !conf.QuietMode {
+		for _, n := range result.Notes {
+			// Prefix of warnings.
+			warnf(console.Text("WARNING: %v"), n)
+		}
+	}
+	strs := 0
+	for _, f := range result.Files {
+		strs += len(f.Migrated)
+		if conf.DryRun {
+			for _, c := range f.Migrated {
+				fmt.Printf("%s: %q\n", c.Pos, c.Text)
+			}
+			continue
+		}
+		if err := os.WriteFile(f.Path, f.Src, 0o644); err != nil {
+			return fmt.Errorf("writing source file: %w", err)
+		}
+	}
+	if !conf.QuietMode && !conf.DryRun {
+		// Number of string literals rewritten into Reader.Text calls.
+		fmt.Fprintln(os.Stderr, console.Plural(localize.Forms{
+			One:   "%d string rewritten",
+			Other: "%d strings rewritten",
+		}, strs))
+	}
+	return nil
+}
+
+func runDedup(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsDedup(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	f, _, err := decodeGettextFile(conf.InPath)
+	if err != nil {
+		return err
+	}
+
+	duplicates := dedup.Merge(f)
+	if !g.QuietMode {
+		for _, d := range duplicates {
+			if d.Conflict {
+				// Warning about a duplicate message with a different translation.
+				warnf(console.Text(
+					"WARNING: %s:%d:%d: conflicting translation of duplicate, keeping %d:%d",
+				), conf.InPath, d.Pos.Line, d.Pos.Column, d.First.Line, d.First.Column)
+			}
+		}
+		// Number of duplicate messages merged.
+		fmt.Fprintln(os.Stderr, console.Plural(localize.Forms{
+			One:   "%d duplicate merged",
+			Other: "%d duplicates merged",
+		}, len(duplicates)))
+	}
+
+	b, err := encodeGettextFile(
+		gettext.Encoder{PreserveFormat: true}, f, isTemplate(conf.InPath),
+	)
+	if err != nil {
+		return err
+	}
+	return writeOutput(conf.OutPath, b)
+}
+
+// isTemplate returns true for the paths of .pot files.
+func isTemplate(path string) bool { return filepath.Ext(path) == ".pot" }
+
+// decodeGettextFile decodes the .po or .pot file at path.
+// src is the content of the file.
+func decodeGettextFile(path string) (f *gettext.File, src []byte, err error) {
+	src, err = os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading file: %w", err)
+	}
+	d := gettext.NewDecoder()
+	if isTemplate(path) {
+		pot, err := d.DecodePOTBytes(path, src)
+		if err != nil {
+			return nil, src, fmt.Errorf("decoding file: %w", err)
+		}
+		return pot.File, src, nil
+	}
+	po, err := d.DecodePOBytes(path, src)
+	if err != nil {
+		return nil, src, fmt.Errorf("decoding file: %w", err)
+	}
+	return po.File, src, nil
+}
+
+// encodeGettextFile encodes f as a .pot file if template is true
+// and as a .po file otherwise.
+func encodeGettextFile(
+	enc gettext.Encoder, f *gettext.File, template bool,
+) ([]byte, error) {
+	var b []byte
+	var err error
+	if template {
+		b, err = enc.EncodePOTToBytes(gettext.FilePOT{File: f})
+	} else {
+		b, err = enc.EncodePOToBytes(gettext.FilePO{File: f})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encoding file: %w", err)
+	}
+	return b, nil
+}
+
+// writeOutput writes b to the file at path or to stdout if path is empty.
+func writeOutput(path string, b []byte) error {
+	if path == "" {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	return nil
+}
+
+func runCheck(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsCheck(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	issues := 0
+	for _, path := range conf.Paths {
+		f, _, err := decodeGettextFile(path)
+		if err != nil {
+			// Syntax errors are reported like issues of the other files.
+			fmt.Fprintln(os.Stderr, err)
+			issues++
+			continue
+		}
+		l := f.Validate()
+		if !isTemplate(path) {
+			l = catalogIssues(f)
+		}
+		for _, issue := range l {
+			fmt.Fprintln(os.Stderr, issue)
+		}
+		issues += len(l)
+	}
+	if issues > 0 {
+		return fmt.Errorf("%w: %d", ErrCatalogIssues, issues)
+	}
+	if !g.QuietMode {
+		// Number of .po and .pot files checked without issues.
+		fmt.Fprintln(os.Stderr, console.Plural(localize.Forms{
+			One:   "%d file checked, no issues found",
+			Other: "%d files checked, no issues found",
+		}, len(conf.Paths)))
+	}
+	return nil
+}
+
+func runFmt(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsFmt(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	enc := gettext.Encoder{
+		PreserveFormat: true,
+		Wrap:           conf.Wrap,
+		SortHeaders:    conf.SortHeaders,
+	}
+	for _, path := range conf.Paths {
+		f, src, err := decodeGettextFile(path)
+		if err != nil {
+			return err
+		}
+		b, err := encodeGettextFile(enc, f, isTemplate(path))
+		if err != nil {
+			return err
+		}
+		changed := !bytes.Equal(src, b)
+		if conf.List && changed {
+			fmt.Println(path)
+		}
+		switch {
+		case conf.Write && changed:
+			if err := os.WriteFile(path, b, 0o644); err != nil {
+				return fmt.Errorf("writing file: %w", err)
+			}
+		case !conf.Write && !conf.List:
+			if _, err := os.Stdout.Write(b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func runMerge(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsMerge(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	def, _, err := decodeGettextFile(conf.DefPath)
+	if err != nil {
+		return err
+	}
+	ref, _, err := decodeGettextFile(conf.RefPath)
+	if err != nil {
+		return err
+	}
+	r := msgmerge.Merge(def, ref)
+	if !g.QuietMode {
+		// Numbers of messages kept, added and made obsolete
+		// when updating a catalog to a template.
+		fmt.Fprintf(os.Stderr, console.Text("%d kept, %d added, %d obsoleted")+"\n",
+			r.Kept, r.Added, r.Obsoleted)
+	}
+
+	b, err := encodeGettextFile(gettext.Encoder{PreserveFormat: true}, def, false)
+	if err != nil {
+		return err
+	}
+	return writeOutput(conf.OutPath, b)
+}
+
+func runPluralTests(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsPluralTests(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	bundle, err := codeparser.ParseBundleDir(conf.BundlePkgPath)
+	if err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	if bundle.Source == nil {
+		return fmt.Errorf("%w: %q", ErrNoSourceCatalog, conf.BundlePkgPath)
+	}
+	goBundleFileName := goBundleFile(conf.BundlePkgPath)
+	f, err := parser.ParseFile(
+		token.NewFileSet(), goBundleFileName, nil, parser.PackageClauseOnly,
+	)
+	if err != nil {
+		return fmt.Errorf("reading package name of Go bundle: %w", err)
+	}
+
+	locales := []language.Tag{bundle.SourceLocale}
+	locales = append(locales, slices.SortedFunc(
+		maps.Keys(bundle.Catalogs), func(a, b language.Tag) int {
+			return strings.Compare(a.String(), b.String())
+		},
+	)...)
+
+	var buf bytes.Buffer
+	if err := gengo.WritePluralTests(&buf, f.Name.Name, locales); err != nil {
+		return fmt.Errorf("generating plural tests: %w", err)
+	}
+	formatted, err := format.Source(buf.Bytes(), format.Options{})
+	if err != nil {
+		return fmt.Errorf("formatting generated plural tests: %w", err)
+	}
+	if err := os.WriteFile(conf.OutPath, formatted, 0o644); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	if !g.QuietMode {
+		// Path of the written plural rules test file.
+		fmt.Fprintf(os.Stderr, console.Text("plural tests written to %s")+"\n",
+			conf.OutPath)
+	}
+	return nil
+}
+
+// runSmoke generates a program performing the lookups of a sample of
+// the messages of a catalog through the compiled reader of its locale
+// and compares the results with the translations of the catalog.
+func runSmoke(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsSmoke(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	bundle, err := codeparser.ParseBundleDir(conf.BundlePkgPath)
+	if err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	catalog, ok := bundle.Catalogs[conf.Locale]
+	if !ok {
+		return fmt.Errorf("bundle has no catalog for locale %q", conf.Locale)
+	}
+
+	opts := smoke.Options{Max: conf.Max}
+	casing := conf.HeadingCasing[conf.Locale]
+	applyTypography := conf.TypographyAll || slices.Contains(conf.Typography, conf.Locale)
+	if casing != typography.CasingNone || applyTypography {
+		// Translations are transformed like generate transforms them.
+		opts.Transform = func(m *gettext.Message, s string) string {
+			if casing != typography.CasingNone && heading.Is(m) {
+				s = casing.Apply(conf.Locale, s, protect.Of(m)...)
+			}
+			if applyTypography {
+				s = typography.Apply(conf.Locale, s)
+			}
+			return s
+		}
+	}
+	checks, err := smoke.Checks(conf.Locale, catalog.Messages.List, opts)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "list", "-f", "{{.ImportPath}}", ".")
+	cmd.Dir = conf.BundlePkgPath
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("resolving import path of the bundle package: %w", err)
+	}
+	importPath := strings.TrimSpace(string(out))
+
+	// The harness is a subpackage of the bundle package such that
+	// bundles in internal directories can be imported. The underscore
+	// prefix excludes it from patterns like "./...".
+	dir, err := os.MkdirTemp(conf.BundlePkgPath, "_localize-smoke-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	var buf bytes.Buffer
+	if err := smoke.WriteHarness(&buf, importPath, conf.Locale, checks); err != nil {
+		return fmt.Errorf("generating harness: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing harness: %w", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd = exec.CommandContext(ctx, "go", "run", ".")
+	cmd.Dir, cmd.Stderr = dir, &stderr
+	if out, err = cmd.Output(); err != nil {
+		return fmt.Errorf("%w: %w\n%s", ErrBundleCompile, err, stderr.Bytes())
+	}
+	var results []string
+	if err := json.Unmarshal(out, &results); err != nil {
+		return fmt.Errorf("decoding harness output: %w", err)
+	}
+	mismatches, err := smoke.Compare(checks, results)
+	if err != nil {
+		return fmt.Errorf("comparing harness output: %w", err)
+	}
+
+	for _, m := range mismatches {
+		fmt.Fprintf(os.Stderr, "%s: %s %q, %s %q\n", m.Ref,
+			// Label of the result of a lookup of the bundle.
+			palette.Red(console.Text("got")), m.Got,
+			// Label of the translation expected by the catalog.
+			palette.Green(console.Text("want")), m.Expect)
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w: %d of %d lookups", ErrLookupMismatch,
+			len(mismatches), len(checks))
+	}
+	if !g.QuietMode {
+		// Result of a successful smoke test.
+		fmt.Fprintln(os.Stderr, palette.Green(console.Plural(localize.Forms{
+			One:   "smoke test passed: %d lookup matches the catalog",
+			Other: "smoke test passed: %d lookups match the catalog",
+		}, len(checks))))
+	}
+	return nil
+}
+
+func runTrim(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsTrim(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	bundle, err := codeparser.ParseBundleDir(conf.BundlePkgPath)
+	if err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	if bundle.Source == nil {
+		return fmt.Errorf("%w: %q", ErrNoSourceCatalog, conf.BundlePkgPath)
+	}
+
+	var remove []codeparser.POFile
+	for _, locale := range slices.SortedFunc(
+		maps.Keys(bundle.CatalogParts), func(a, b language.Tag) int {
+			return strings.Compare(a.String(), b.String())
+		},
+	) {
+		if !slices.Contains(conf.Keep, locale) {
+			remove = append(remove, bundle.CatalogParts[locale]...)
+		}
+	}
+	if !g.QuietMode {
+		for _, locale := range conf.Keep {
+			_, ok := bundle.CatalogParts[locale]
+			if !ok && locale != bundle.SourceLocale {
+				// Warning about a locale to keep that has no translation catalog.
+				warnf(console.Text("WARNING: no translation catalog for locale %s"),
+					locale)
+			}
+		}
+	}
+
+	var reclaimed int64
+	for _, f := range remove {
+		info, err := os.Stat(f.Path)
+		if err != nil {
+			return fmt.Errorf("reading catalog: %w", err)
+		}
+		reclaimed += info.Size()
+		if g.QuietMode {
+			continue
+		}
+		if conf.DryRun {
+			// Catalog file that would be removed and its size.
+			fmt.Fprintf(os.Stderr, console.Text("would remove %s (%s)")+"\n",
+				f.Path, formatByteSize(info.Size()))
+		} else {
+			// Removed catalog file and its size.
+			fmt.Fprintf(os.Stderr, console.Text("removing %s (%s)")+"\n",
+				f.Path, formatByteSize(info.Size()))
+		}
+	}
+	if conf.DryRun || len(remove) < 1 {
+		if !g.QuietMode {
+			// Total size of the catalog files that would be removed.
+			fmt.Fprintf(os.Stderr, console.Text("%s reclaimable")+"\n",
+				formatByteSize(reclaimed))
+		}
+		return nil
+	}
+
+	// goBundleSize returns the total size of all Go bundle files.
+	goBundleSize := func() (size int64) {
+		files, _ := goBundleFiles(conf.BundlePkgPath)
+		for _, f := range files {
+			if info, err := os.Stat(f); err == nil {
+				size += info.Size()
+			}
+		}
+		return size
+	}
+	sizeBefore := goBundleSize()
+
+	// Prevent concurrent generate runs from reading catalogs being removed.
+	lock, err := lockfile.Acquire(
+		filepath.Join(conf.BundlePkgPath, lockFileName), lockfile.Options{},
+	)
+	if err != nil {
+		return fmt.Errorf("locking bundle: %w", err)
+	}
+	for _, f := range remove {
+		if err = os.Remove(f.Path); err != nil {
+			err = fmt.Errorf("removing catalog: %w", err)
+			break
+		}
+	}
+	if errRelease := lock.Release(); err == nil && errRelease != nil {
+		err = fmt.Errorf("releasing bundle lock: %w", errRelease)
+	}
+	if err != nil {
+		return err
+	}
+
+	generateArgs := append([]string{
+		"-b", conf.BundlePkgPath, "-l", bundle.SourceLocale.String(),
+	}, conf.GenerateArgs...)
+	if g.QuietMode {
+		generateArgs = append([]string{"-q"}, generateArgs...)
+	}
+	if err := runGenerate(ctx, g, generateArgs); err != nil {
+		return fmt.Errorf("regenerating bundle: %w", err)
+	}
+
+	reclaimed += sizeBefore - goBundleSize()
+	if !g.QuietMode {
+		// Total size reclaimed by removing catalogs and regenerating the bundle.
+		fmt.Fprintf(os.Stderr, console.Text("%s reclaimed")+"\n",
+			formatByteSize(reclaimed))
+	}
+	return nil
+}
+
+// runSelftest copies the module containing the working directory to two
+// temporary directories, runs generate in both and compares the results
+// byte by byte, then compiles the generated bundle package.
+func runSelftest(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsSelftest(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+	gen, err := config.ParseCLIArgsGenerate(g, conf.GenerateArgs)
+	if err != nil {
+		return fmt.Errorf("parsing generate arguments: %w", err)
+	}
+	if filepath.IsAbs(gen.SrcPathPattern) || filepath.IsAbs(gen.BundlePkgPath) {
+		return errors.New("selftest requires relative paths (-p, -b)")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	moduleRoot, ok := config.FindModuleRoot(wd)
+	if !ok {
+		return fmt.Errorf("no go.mod found in %s or any parent directory", wd)
+	}
+	rel, err := filepath.Rel(moduleRoot, wd)
+	if err != nil {
+		return err
+	}
+
+	var dirs [2]string
+	for i := range dirs {
+		if dirs[i], err = os.MkdirTemp("", "localize-selftest-*"); err != nil {
+			return err
+		}
+		if conf.KeepTemp {
+			if !g.QuietMode {
+				// Path of a temporary module copy kept for inspection.
+				fmt.Fprintf(os.Stderr, console.Text("keeping %s")+"\n", dirs[i])
+			}
+		} else {
+			defer func() { _ = os.RemoveAll(dirs[i]) }()
+		}
+		if err := copyDir(moduleRoot, dirs[i]); err != nil {
+			return fmt.Errorf("copying module: %w", err)
+		}
+		// generate resolves its paths relative to the working directory.
+		if err := os.Chdir(filepath.Join(dirs[i], rel)); err != nil {
+			return err
+		}
+		err := runGenerate(ctx, g, append([]string{"-q"}, conf.GenerateArgs...))
+		if errChdir := os.Chdir(wd); errChdir != nil {
+			return errChdir
+		}
+		if err != nil {
+			return fmt.Errorf("run %d: %w", i+1, err)
+		}
+	}
+
+	differ, files, err := diffDirs(dirs[0], dirs[1])
+	if err != nil {
+		return fmt.Errorf("comparing outputs: %w", err)
+	}
+	if len(differ) > 0 {
+		for _, f := range differ {
+			fmt.Fprintf(os.Stderr, " %s\n", palette.Red(f))
+		}
+		return fmt.Errorf("%w: %d files", ErrNondeterministic, len(differ))
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "build",
+		"./"+filepath.ToSlash(filepath.Clean(gen.BundlePkgPath)))
+	cmd.Dir = filepath.Join(dirs[0], rel)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %w\n%s", ErrBundleCompile, err, out)
+	}
+
+	if !g.QuietMode {
+		// Result of a successful selftest.
+		fmt.Fprintln(os.Stderr, palette.Green(console.Plural(localize.Forms{
+			One:   "selftest passed: %d file identical, bundle compiles",
+			Other: "selftest passed: %d files identical, bundle compiles",
+		}, files)))
+	}
+	return nil
+}
+
+// copyDir copies the regular files of directory src to dst
+// skipping version control directories and symbolic links.
+func runExample(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsExample(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	_, err = example.Write(conf.OutPath, example.Options{
+		Module:  conf.Module,
+		Version: example.Version(),
+	})
+	if err != nil {
+		return fmt.Errorf("writing example: %w", err)
+	}
+
+	if !conf.QuietMode {
+		// The example app was written, followed by the commands running it.
+		fmt.Fprintf(os.Stderr, console.Text("example written to %s, run it using:")+"\n",
+			conf.OutPath)
+		fmt.Fprintf(os.Stderr, "\n  cd %s\n  go mod tidy\n  go generate\n  go run .\n",
+			conf.OutPath)
+	}
+	return nil
+}
+
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir():
+			if slices.Contains([]string{".git", ".hg", ".svn"}, d.Name()) {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0o755)
+		case !d.Type().IsRegular():
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, b, 0o644)
+	})
+}
+
+// diffDirs returns the sorted relative paths of the files that differ
+// in content or only exist in one of the directories a and b,
+// and the number of files compared.
+func diffDirs(a, b string) (differ []string, files int, err error) {
+	read := func(dir string) (map[string][]byte, error) {
+		m := map[string][]byte{}
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			m[filepath.ToSlash(rel)], err = os.ReadFile(path)
+			return err
+		})
+		return m, err
+	}
+	filesA, err := read(a)
+	if err != nil {
+		return nil, 0, err
+	}
+	filesB, err := read(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	for name, contentA := range filesA {
+		if contentB, ok := filesB[name]; !ok || !bytes.Equal(contentA, contentB) {
+			differ = append(differ, name)
+		}
+	}
+	for name := range filesB {
+		if _, ok := filesA[name]; !ok {
+			differ = append(differ, name)
+		}
+	}
+	slices.Sort(differ)
+	return differ, len(filesA), nil
+}
+
+// formatByteSize formats n as a human readable size like "12.3 KiB".
+func formatByteSize(n int64) string {
+	const unit = 1 << 10
+	if n < unit && n > -unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	f, i := float64(n), -1
+	for ; (f >= unit || f <= -unit) && i < 2; i++ {
+		f /= unit
+	}
+	return fmt.Sprintf("%.1f %ciB", f, "KMG"[i])
+}
+
+// writeSourceErrorsJSON writes srcErrs to w as a JSON array
+// for consumption by editors and CI tools.
+func writeSourceErrorsJSON(w io.Writer, srcErrs []codeparser.ErrorSrc) error {
+	type sourceError struct {
+		File     string `json:"file"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+		Severity string `json:"severity"`
+		Code     string `json:"code"`
+		Message  string `json:"message"`
+	}
+	l := make([]sourceError, len(srcErrs))
+	for i, e := range srcErrs {
+		l[i] = sourceError{
+			File:     e.Filename,
+			Line:     e.Line,
+			Column:   e.Column,
+			Severity: e.Severity.String(),
+			Code:     e.Code(),
+			Message:  e.Err.Error(),
+		}
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(l)
+}
+
+// catalogIssues returns the issues of a translation catalog file found by
+// gettext.File.Validate, markup.CheckFile and protect.CheckFile.
+func catalogIssues(f *gettext.File) []gettext.Error {
+	return slices.Concat(f.Validate(), markup.CheckFile(f), protect.CheckFile(f))
+}
+
+// warnCatalogIssues prints the issues of all translation catalogs
+// found by catalogIssues.
+func warnCatalogIssues(bundle *codeparser.Bundle) {
+	locales := slices.SortedFunc(maps.Keys(bundle.CatalogParts),
+		func(a, b language.Tag) int { return strings.Compare(a.String(), b.String()) })
+	for _, l := range locales {
+		for _, p := range bundle.CatalogParts[l] {
+			for _, issue := range catalogIssues(p.File) {
+				// Prefix of warnings.
+				warnf(console.Text("WARNING: %v"), issue)
+			}
+		}
+	}
+}
+
+// warnSkippedEntries prints the malformed entries of all translation
+// catalogs skipped in salvage mode (see -salvage).
+func warnSkippedEntries(bundle *codeparser.Bundle) {
+	locales := slices.SortedFunc(maps.Keys(bundle.CatalogParts),
+		func(a, b language.Tag) int { return strings.Compare(a.String(), b.String()) })
+	for _, l := range locales {
+		parts := bundle.CatalogParts[l]
+		for _, p := range parts {
+			for _, e := range p.Skipped {
+				// Warning about a catalog entry that couldn't be decoded.
+				warnf(console.Text("WARNING: skipped malformed catalog entry: %v"), e)
+			}
+		}
+		if salvaged(parts) {
+			// Warning about a locale whose catalogs are kept as is.
+			warnf(console.Text("WARNING: catalogs of %s not updated until fixed"), l)
+		}
+	}
+}
+
+// salvaged returns true if malformed entries of any of parts were skipped.
+func salvaged(parts []codeparser.POFile) bool {
+	return slices.ContainsFunc(parts, func(p codeparser.POFile) bool {
+		return len(p.Skipped) > 0
+	})
+}
+
+// writeReport writes the QA report of the source code issues srcErrs
+// and the catalogs of bundle if requested. source is the source catalog
+// of the current source code or nil if it couldn't be created,
+// in which case the outdated source catalog of bundle is reported.
+func writeReport(
+	conf *config.ConfigGenerate, bundle *codeparser.Bundle,
+	srcErrs []codeparser.ErrorSrc, source *gettext.FilePO,
+) error {
+	if conf.Report == "" {
+		return nil
+	}
+	b := *bundle
+	if source != nil {
+		b.Source = &codeparser.POFile{FilePO: *source}
+	}
+	var site *gendocs.Site
+	if b.Source != nil {
+		var err error
+		if site, err = gendocs.Make(&b); err != nil {
+			return err
+		}
+	}
+	issues := make(map[string][]gettext.Error, len(bundle.CatalogParts))
+	for l, parts := range bundle.CatalogParts {
+		for _, p := range parts {
+			issues[l.String()] = append(issues[l.String()], catalogIssues(p.File)...)
+		}
+	}
+	var buf bytes.Buffer
+	if err := qareport.Make(site, srcErrs, issues).WriteHTML(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(conf.ReportOutPath, buf.Bytes(), 0o644)
+}
+
+// checkLimits checks the size limits of the extracted messages,
+// the catalog template files and the existing translation catalog files
+// and their translations.
+// Exceeded limits are only printed as warnings if conf.LimitsWarn is true.
+func checkLimits(
+	conf *config.ConfigGenerate, collection *codeparser.Collection,
+	bundle *codeparser.Bundle, po gettext.FilePO,
+) error {
+	violations := conf.Limits.Messages(collection)
+
+	// The size of templates is unknown before they're written.
+	perDomain := map[string]int{}
+	for i := range po.Messages.List {
+		perDomain[conf.Domains.Of(&po.Messages.List[i])]++
+	}
+	dir, name := filepath.Split(conf.OutPathCatalogTemplate)
+	for _, d := range slices.Sorted(maps.Keys(perDomain)) {
+		violations = append(violations, conf.Limits.Catalog(
+			filepath.Join(dir, domain.FileName(name, d)), perDomain[d], -1,
+		)...)
+	}
+
+	locales := slices.SortedFunc(maps.Keys(bundle.CatalogParts),
+		func(a, b language.Tag) int { return strings.Compare(a.String(), b.String()) })
+	for _, l := range locales {
+		for _, p := range bundle.CatalogParts[l] {
+			size := int64(-1)
+			if fi, err := os.Stat(p.Path); err == nil {
+				size = fi.Size()
+			}
+			violations = append(violations, conf.Limits.Catalog(
+				p.Path, len(p.Messages.List), size,
+			)...)
+			violations = append(violations, conf.Limits.Translations(p.Path, p.FilePO)...)
+		}
+	}
+
+	if len(violations) < 1 {
+		return nil
+	}
+	if conf.LimitsWarn {
+		if !conf.QuietMode {
+			for _, v := range violations {
+				// Prefix of warnings.
+				warnf(console.Text("WARNING: %v"), v)
+			}
+		}
+		return nil
+	}
+	// Heading of the list of exceeded size limits.
+	fmt.Fprintln(os.Stderr,
+		palette.Red(console.Cardinal("LIMITS EXCEEDED (%d):", len(violations))))
+	for _, v := range violations {
+		fmt.Fprintf(os.Stderr, " %v\n", v)
+	}
+	return ErrLimitsExceeded
+}
+
+// fallBackPluralForms sets the plural forms of fallback for all locales
+// without CLDR data and warns about them, such that a catalog of an exotic
+// locale doesn't block the generation of all other catalogs.
+func fallBackPluralForms(
+	locales iter.Seq[language.Tag], fallback language.Tag, quiet bool,
+) error {
+	for _, l := range slices.SortedFunc(locales, func(a, b language.Tag) int {
+		return cmp.Compare(a.String(), b.String())
+	}) {
+		if cldr.Supported(l) {
+			continue
+		}
+		if err := cldr.SetFallback(l, fallback); err != nil {
+			return fmt.Errorf("setting plural forms fallback: %w", err)
+		}
+		if quiet {
+			continue
+		}
+		if fallback == language.Und {
+			// Warning about a locale unknown to CLDR using plural form Other only.
+			warnf(console.Text(
+				"WARNING: no CLDR plural rules for locale %s, using form Other only",
+			), l)
+			continue
+		}
+		// Warning about a locale unknown to CLDR using the plural rules of another locale.
+		warnf(console.Text(
+			"WARNING: no CLDR plural rules for locale %s, using the rules of %s",
+		), l, fallback)
+	}
+	return nil
+}
+
+// programName is the name of the executable
+// used in shell completion scripts and the man page.
+const programName = "localize"
+
+func runCompletions(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsCompletions(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+	return clidoc.WriteCompletions(
+		os.Stdout, conf.Shell, programName, config.GlobalFlags, config.Commands,
+	)
+}
+
+// configSchemaTitle is the title of the JSON schema of the configuration file.
+const configSchemaTitle = "localize configuration"
+
+func runConfig(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsConfig(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	if conf.Action == "schema" {
+		var buf bytes.Buffer
+		if err := clidoc.WriteSchema(&buf, configSchemaTitle, config.Commands); err != nil {
+			return fmt.Errorf("rendering schema: %w", err)
+		}
+		if conf.OutPath == "" {
+			_, err = os.Stdout.Write(buf.Bytes())
+			return err
+		}
+		if err := os.WriteFile(conf.OutPath, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("writing schema: %w", err)
+		}
+		return nil
+	}
+
+	data, err := os.ReadFile(conf.InPath)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+	errs := config.ValidateFile(data)
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "%s:%v\n", conf.InPath, e)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, conf.InPath)
+	}
+	if !conf.QuietMode {
+		// The configuration file passed to "config validate" is valid.
+		fmt.Fprintf(os.Stderr, console.Text("%s is valid")+"\n", conf.InPath)
+	}
+	return nil
+}
+
+func runMan(ctx context.Context, g config.Global, args []string) error {
+	conf, err := config.ParseCLIArgsMan(g, args)
+	if err != nil {
+		return fmt.Errorf("parsing arguments: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := clidoc.WriteMan(
+		&buf, programName, "localize Go programs using GNU gettext catalogs",
+		config.GlobalFlags, config.Commands,
+	); err != nil {
+		return fmt.Errorf("rendering man page: %w", err)
+	}
+
+	if conf.OutPath == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(conf.OutPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing man page: %w", err)
+	}
+	return nil
+}
+
+// goBundleFile returns the path of the generated Go bundle file
+// of the bundle package in directory bundlePkgPath.
+func goBundleFile(bundlePkgPath string) string {
+	return filepath.Join(bundlePkgPath, filepath.Base(bundlePkgPath)+"_gen.go")
+}
+
+// goBundleLocaleFile returns the path of the file of the reader of locale
+// of a Go bundle split with -split-files. The suffix "_gen" prevents locales
+// like "linux" from being interpreted as build constraints.
+func goBundleLocaleFile(bundlePkgPath string, locale language.Tag) string {
+	l := strings.ToLower(strings.ReplaceAll(locale.String(), "-", "_"))
+	return filepath.Join(bundlePkgPath, filepath.Base(bundlePkgPath)+"_"+l+"_gen.go")
+}
+
+// goBundleFiles returns the paths of all existing files of the Go bundle
+// of the bundle package in directory bundlePkgPath, which are the bundle file
+// and the generated locale files of a split bundle.
+func goBundleFiles(bundlePkgPath string) ([]string, error) {
+	var files []string
+	if _, err := os.Stat(goBundleFile(bundlePkgPath)); err == nil {
+		files = append(files, goBundleFile(bundlePkgPath))
+	}
+	matches, err := filepath.Glob(filepath.Join(
+		bundlePkgPath, filepath.Base(bundlePkgPath)+"_*_gen.go",
+	))
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		f, err := os.Open(m)
+		if err != nil {
+			return nil, err
+		}
+		head := make([]byte, len(generatedHeader))
+		_, err = io.ReadFull(f, head)
+		_ = f.Close()
+		// Files not generated by localize, like hand-written files
+		// matching the pattern, aren't part of the bundle.
+		if err == nil && string(head) == generatedHeader {
+			files = append(files, m)
+		}
+	}
+	return files, nil
+}
+
+// generatedHeader is the first line of all generated Go bundle files.
+const generatedHeader = "// Code generated by github.com/romshark/localize/cmd/localize."
+
+// checkCatalogHashes compares the hashes of the translation catalogs
+// with the hashes recorded in the Go bundle when it was last generated,
+// reporting catalogs modified since as warnings, or returns
+// ErrCatalogModified in strict mode.
+func checkCatalogHashes(conf *config.ConfigGenerate, bundle *codeparser.Bundle) error {
+	src, err := os.ReadFile(goBundleFile(conf.BundlePkgPath))
+	if err != nil {
+		return nil // Not generated yet.
+	}
+	recorded := gengo.CatalogHashes(src)
+	if recorded == nil {
+		return nil // Generated by a version not recording catalog hashes.
+	}
+	// Catalogs created by generate after the Go bundle aren't recorded
+	// and have no translations yet.
+	untranslated := gengo.CatalogHash(nil)
+	var modified []string
+	for _, parts := range bundle.CatalogParts {
+		for _, p := range parts {
+			want, ok := recorded[filepath.Base(p.Path)]
+			if !ok {
+				want = untranslated
+			}
+			if gengo.CatalogHash(p.Messages.List) != want {
+				modified = append(modified, p.Path)
+			}
+		}
+	}
+	if len(modified) == 0 {
+		return nil
+	}
+	slices.Sort(modified)
+	if conf.Strict {
+		return fmt.Errorf("%w: %s", ErrCatalogModified, strings.Join(modified, ", "))
+	}
+	if !conf.QuietMode {
+		for _, p := range modified {
+			// Warning about a catalog edited without regenerating the Go bundle.
+			warnf(console.Text("WARNING: catalog %s modified since the last generation"), p)
+		}
+	}
+	return nil
+}
+
+// generateGoBundle writes the files of the Go bundle, removes files of
+// locales no longer in the bundle and returns the paths of the files
+// written or removed. Files whose content didn't change aren't written.
+func generateGoBundle(
+	conf *config.ConfigGenerate, headTxt []string,
+	collection *codeparser.Collection, bundle *codeparser.Bundle,
+) ([]string, error) {
+	opts := gengo.Options{
+		PluralFallback: conf.PluralFallback,
+		HeadingCasing:  conf.HeadingCasing,
+		HashIndex:      conf.HashIndex,
+
+		IncludeObsolete: conf.IncludeObsolete,
+		TemplateDir:     conf.TemplateDir,
+	}
+	if conf.TypographyAll || len(conf.Typography) > 0 {
+		opts.Transform = func(locale language.Tag, s string) string {
+			if conf.TypographyAll || slices.Contains(conf.Typography, locale) {
+				return typography.Apply(locale, s)
+			}
+			return s
+		}
+	}
+
+	var files []gengo.File
+	if conf.SplitFiles {
+		var err error
+		files, err = gengo.WriteSplit(
+			conf.Locale, headTxt, conf.PackageName, collection, bundle, opts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("generating Go bundle: %w", err)
+		}
+	} else {
+		var buf bytes.Buffer
+		err := gengo.Write(
+			&buf, conf.Locale, headTxt, conf.PackageName, collection, bundle, opts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("generating Go bundle: %w", err)
+		}
+		files = []gengo.File{{Locale: language.Und, Src: buf.Bytes()}}
+	}
+
+	existing, err := goBundleFiles(conf.BundlePkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("listing Go bundle files: %w", err)
+	}
+	var written []string
+	for _, f := range files {
+		path := goBundleFile(conf.BundlePkgPath)
+		if f.Locale != language.Und {
+			path = goBundleLocaleFile(conf.BundlePkgPath, f.Locale)
+		}
+		existing = slices.DeleteFunc(existing, func(p string) bool { return p == path })
+
+		changed, err := writeGoBundleFile(conf, path, f.Src)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			written = append(written, path)
+		}
 	}
-	switch osArgs[1] {
-	case "lint":
-		// TODO: implement lint command
-		panic("not yet implemented")
-	case "generate":
-		return runGenerate(osArgs)
+	// Remove the files of locales no longer in the bundle
+	// or of a previously split bundle.
+	for _, path := range existing {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale Go bundle file: %w", err)
+		}
+		written = append(written, path)
 	}
-	return fmt.Errorf("%w %q, use either of: [generate,lint]",
-		ErrUnknownCommand, osArgs[1])
+	return written, nil
 }
 
-func runGenerate(osArgs []string) error {
-	start := time.Now()
-	conf, err := config.ParseCLIArgsGenerate(osArgs)
+// writeGoBundleFile formats src and writes it to path unless the content
+// of the existing file is identical. changed is false if path wasn't written.
+func writeGoBundleFile(
+	conf *config.ConfigGenerate, path string, src []byte,
+) (changed bool, err error) {
+	formatted, err := format.Source(src, format.Options{})
 	if err != nil {
-		return fmt.Errorf("parsing arguments: %w", err)
+		return false, fmt.Errorf("formatting generated Go bundle code: %w", err)
 	}
 
-	poEncoder := gettext.Encoder{}
+	formatted, hash := gengo.SetContentHash(formatted)
 
-	collection, bundle, stats, srcErrs, err := codeparser.Parse(
-		conf.SrcPathPattern, conf.BundlePkgPath, conf.Locale,
-		conf.TrimPath, conf.QuietMode, conf.VerboseMode,
-	)
-	if err != nil {
-		return fmt.Errorf("%w: %w", ErrAnalyzingSource, err)
+	// Don't touch the file if its content didn't change to avoid
+	// needless rebuilds by tools relying on modification times.
+	if existing, err := os.ReadFile(path); err == nil {
+		if h, ok := gengo.ContentHash(existing); ok && h == hash {
+			if !conf.QuietMode && conf.VerboseMode {
+				// Verbose log: the generated Go bundle file is up to date.
+				fmt.Fprintf(os.Stderr,
+					console.Text("Go bundle unchanged: %s")+"\n", path)
+			}
+			return false, nil
+		}
 	}
 
-	_ = bundle // TODO
-
-	if len(srcErrs) > 0 {
-		// Print source errors to console.
-		fmt.Fprintf(os.Stderr, "SOURCE ERRORS (%d):\n", len(srcErrs))
-		for _, e := range srcErrs {
-			fmt.Fprintf(os.Stderr, " %s:%d:%d: %s\n",
-				e.Filename, e.Line, e.Column, e.Err.Error())
-		}
-		return ErrSourceErrors
+	if err := os.WriteFile(path, formatted, 0o644); err != nil {
+		return false, fmt.Errorf("writing formatted Go bundle code to file: %w", err)
 	}
+	return true, nil
+}
 
-	if err := os.MkdirAll(conf.BundlePkgPath, 0o755); err != nil {
-		return fmt.Errorf("creating bundle package directory: %w", err)
+// runPlugins runs the output plugins of conf in order
+// and writes the files they generate.
+func runPlugins(
+	ctx context.Context, conf *config.ConfigGenerate,
+	bundle *codeparser.Bundle, po gettext.FilePO,
+) error {
+	if len(conf.Plugins) == 0 {
+		return nil
 	}
 
-	headTxt, err := readOrCreateHeadTxt(conf)
+	// Plugins receive the messages of the current source code,
+	// the source catalog of bundle may be outdated.
+	b := *bundle
+	b.Source = &codeparser.POFile{FilePO: po}
+	site, err := gendocs.Make(&b)
 	if err != nil {
 		return err
 	}
-
-	po := collection.MakePO(headTxt)
-
-	if err := writeSourceCatalog(conf, poEncoder, po); err != nil {
-		return fmt.Errorf("writing native catalog: %w", err)
+	req := plugin.Request{
+		Version:      plugin.ProtocolVersion,
+		SourceLocale: site.SourceLocale,
+		Locales:      make([]string, len(site.Locales)),
+		Messages:     make([]plugin.Message, len(site.Messages)),
+	}
+	for i, l := range site.Locales {
+		req.Locales[i] = l.Tag
+	}
+	for i, m := range site.Messages {
+		pm := plugin.Message{
+			Hash:         m.Hash,
+			Description:  m.Description,
+			References:   m.References,
+			Source:       pluginForms(m.Source),
+			Translations: make([]plugin.Translation, len(m.Translations)),
+		}
+		for i, t := range m.Translations {
+			pm.Translations[i] = plugin.Translation{
+				Locale:     t.Locale,
+				Translated: t.Translated,
+				Forms:      pluginForms(t.Forms),
+			}
+		}
+		req.Messages[i] = pm
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("encoding plugin request: %w", err)
 	}
 
-	if err := writeTranslationTemplate(conf, poEncoder, po); err != nil {
-		return fmt.Errorf("writing catalog.pot: %w", err)
+	for _, p := range conf.Plugins {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := runPlugin(ctx, p, input); err != nil {
+			return fmt.Errorf("plugin %s: %w", p.Name, err)
+		}
 	}
+	return nil
+}
 
-	if err := generateGoBundle(conf, headTxt, collection, bundle); err != nil {
-		return fmt.Errorf("writing bundle_gen.go: %w", err)
+func pluginForms(forms []gendocs.Form) []plugin.Form {
+	if forms == nil {
+		return nil
+	}
+	f := make([]plugin.Form, len(forms))
+	for i, form := range forms {
+		f[i] = plugin.Form{Name: form.Name, Text: form.Text}
 	}
+	return f
+}
 
-	if err := updateTranslationCatalogs(
-		conf, bundle, collection, poEncoder,
-	); err != nil {
-		return fmt.Errorf("updating translation catalogs: %w", err)
+// runPlugin runs plugin p with the encoded request input
+// and writes the files of its response to the output directory of p.
+func runPlugin(ctx context.Context, p config.Plugin, input []byte) error {
+	executable := p.Name
+	if !strings.ContainsAny(p.Name, `/`+string(filepath.Separator)) {
+		executable = plugin.ExecutablePrefix + p.Name
+	}
+	cmd := exec.CommandContext(ctx, executable)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("%w: running %s: %w", ErrPluginFailed, executable, err)
 	}
 
-	timeTotal := time.Since(start)
-	if !conf.QuietMode {
-		w := os.Stderr
-		_, _ = fmt.Fprintf(w, "Text/Block: %d/%d\n",
-			stats.TextTotal.Load(), stats.BlockTotal.Load())
-		_, _ = fmt.Fprintf(w, "Plural/PluralBlock: %d/%d\n",
-			stats.PluralTotal.Load(), stats.PluralBlockTotal.Load())
-		_, _ = fmt.Fprintf(w, "Calls merged: %d\n", stats.Merges.Load())
-		_, _ = fmt.Fprintf(w, "files scanned: %d\n", stats.FilesTraversed.Load())
-		_, _ = fmt.Fprintf(w, "time total: %s\n", timeTotal.String())
+	var resp plugin.Response
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return fmt.Errorf("%w: decoding response: %w", ErrPluginFailed, err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("%w: %s", ErrPluginFailed, resp.Error)
 	}
 
+	// Validate all file names first to never write the output partially.
+	for _, f := range resp.Files {
+		if !filepath.IsLocal(filepath.FromSlash(f.Name)) {
+			return fmt.Errorf("%w: file name %q is not local to the output directory",
+				ErrPluginFailed, f.Name)
+		}
+	}
+	for _, f := range resp.Files {
+		path := filepath.Join(p.OutDir, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(f.Content), 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
 	return nil
 }
 
-func generateGoBundle(
-	conf *config.ConfigGenerate, headTxt []string,
-	collection *codeparser.Collection, bundle *codeparser.Bundle,
+// runPostGenerateHooks runs the post-generate hooks of conf in order
+// passing the JSON encoded summary s to their stdin.
+// Output of hooks is written to stderr, which keeps stdout reserved
+// for the statistics.
+func runPostGenerateHooks(
+	ctx context.Context, conf *config.ConfigGenerate, s summary.Summary,
 ) error {
-	goBundleFileName := filepath.Join(
-		conf.BundlePkgPath, filepath.Base(conf.BundlePkgPath)+"_gen.go",
-	)
-	f, err := os.OpenFile(
-		goBundleFileName,
-		os.O_CREATE|os.O_TRUNC|os.O_WRONLY,
-		0o644,
-	)
-	if err != nil {
-		return fmt.Errorf("opening Go bundle output file: %w", err)
-	}
-	var buf bytes.Buffer
-
-	pkgName := filepath.Base(conf.BundlePkgPath)
-	err = gengo.Write(&buf, conf.Locale, headTxt, pkgName, collection, bundle)
-	if err != nil {
-		return fmt.Errorf("generating Go bundle: %w", err)
+	if len(conf.PostGenerate) == 0 {
+		return nil
 	}
-
-	// Format and write to file.
-	formatted, err := format.Source(buf.Bytes(), format.Options{})
+	input, err := summary.Encode(s)
 	if err != nil {
-		return fmt.Errorf("formatting generated Go bundle code: %w", err)
+		return err
 	}
-
-	if _, err := f.Write(formatted); err != nil {
-		return fmt.Errorf("writing formatted Go bundle code to file: %w", err)
+	for _, command := range conf.PostGenerate {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !conf.QuietMode && conf.VerboseMode {
+			// Verbose log: a post-generate hook command is executed.
+			fmt.Fprintf(os.Stderr, console.Text("running hook: %s")+"\n",
+				strings.Join(command, " "))
+		}
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Stdin = bytes.NewReader(input)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%w: running %s: %w", ErrHookFailed, command[0], err)
+		}
 	}
 	return nil
 }
@@ -161,58 +2633,58 @@ func readOrCreateHeadTxt(conf *config.ConfigGenerate) ([]string, error) {
 	headFilePath := filepath.Join(conf.BundlePkgPath, "head.txt")
 	if fc, err := os.ReadFile(headFilePath); errors.Is(err, os.ErrNotExist) {
 		if !conf.QuietMode {
-			fmt.Fprintln(os.Stderr, "head.txt not found, creating a new one")
+			// The head comment file of generated files is created.
+			fmt.Fprintln(os.Stderr, console.Text("head.txt not found, creating a new one"))
 		}
 		f, err := os.Create(headFilePath)
 		if err != nil {
 			return nil, fmt.Errorf("creating head.txt file: %w", err)
 		}
 		if err := f.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "closing head.txt file: %v", err)
+			// Error closing the newly created head.txt file.
+			fmt.Fprintf(os.Stderr, console.Text("closing head.txt file: %v")+"\n", err)
 		}
 	} else if err != nil {
 		return nil, fmt.Errorf("reading head.txt: %w", err)
-	} else {
+	} else if len(fc) > 0 {
 		return strings.Split(string(fc), "\n"), nil
 	}
+	// An empty head.txt is equivalent to the newly created one.
 	return nil, nil
 }
 
+// writeSourceCatalog writes the source catalog and returns its path.
 func writeSourceCatalog(
 	conf *config.ConfigGenerate, poEncoder gettext.Encoder, po gettext.FilePO,
-) error {
-	{ // Write the source catalog `.po` file.
-		fileName := filepath.Join(
-			conf.BundlePkgPath,
-			"source."+conf.Locale.String()+".po",
-		)
-		f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
-		if err != nil {
-			return fmt.Errorf("opening output file: %v", err)
-		}
-		// Add do not edit head comment.
-		po.Head.HeadComments.Text = append(po.Head.HeadComments.Text,
-			gettext.Comment{Value: "generated by " +
-				"github.com/romshark/localize/cmd/localize. DO NOT EDIT."},
-			gettext.Comment{Value: ""},
-			gettext.Comment{Value: "Any changes made to this file will be overwritten"},
-			gettext.Comment{Value: "as soon as localize is executed again."})
-		if err := poEncoder.EncodePO(po, f); err != nil {
-			return fmt.Errorf("encoding PO file: %w", err)
-		}
+) (string, error) {
+	// Write the source catalog `.po` file.
+	fileName := filepath.Join(
+		conf.BundlePkgPath,
+		"source."+conf.Locale.String()+".po",
+	)
+	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("opening output file: %v", err)
 	}
-	return nil
+	defer func() { _ = f.Close() }()
+	// Add do not edit head comment.
+	po.Head.HeadComments.Text = append(po.Head.HeadComments.Text,
+		gettext.Comment{Value: "generated by " +
+			"github.com/romshark/localize/cmd/localize. DO NOT EDIT."},
+		gettext.Comment{Value: ""},
+		gettext.Comment{Value: "Any changes made to this file will be overwritten"},
+		gettext.Comment{Value: "as soon as localize is executed again."})
+	if err := poEncoder.EncodePO(po, f); err != nil {
+		return "", fmt.Errorf("encoding PO file: %w", err)
+	}
+	return fileName, nil
 }
 
+// writeTranslationTemplate writes the catalog templates of all domains
+// and returns their paths.
 func writeTranslationTemplate(
 	conf *config.ConfigGenerate, poEncoder gettext.Encoder, po gettext.FilePO,
-) error {
-	f, err := os.OpenFile(
-		conf.OutPathCatalogTemplate, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644,
-	)
-	if err != nil {
-		return fmt.Errorf("opening file: %v", err)
-	}
+) (fileNames []string, err error) {
 	pot := po.MakePOT()
 	// Add do not edit head comment.
 	pot.Head.HeadComments.Text = append(pot.Head.HeadComments.Text,
@@ -221,180 +2693,700 @@ func writeTranslationTemplate(
 		gettext.Comment{Value: ""},
 		gettext.Comment{Value: "Any changes made to this file will be overwritten"},
 		gettext.Comment{Value: "as soon as localize is executed again."})
+
+	// Split the template by domain.
+	// The default domain template is always written.
+	byDomain := map[string][]gettext.Message{"": nil}
+	for _, m := range pot.Messages.List {
+		d := conf.Domains.Of(&m)
+		byDomain[d] = append(byDomain[d], m)
+	}
+
+	dir, name := filepath.Split(conf.OutPathCatalogTemplate)
+	for d, msgs := range byDomain {
+		f := gettext.FilePOT{File: &gettext.File{
+			Head:     pot.Head,
+			Messages: gettext.Messages{List: msgs},
+		}}
+		fileName := filepath.Join(dir, domain.FileName(name, d))
+		if err := writePOT(poEncoder, fileName, f); err != nil {
+			return nil, err
+		}
+		fileNames = append(fileNames, fileName)
+	}
+	slices.Sort(fileNames)
+
+	// Remove the templates of domains no longer used.
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading template directory: %w", err)
+	}
+	for _, e := range entries {
+		d, ok := domain.OfFileName(name, e.Name())
+		if !ok || e.IsDir() {
+			continue
+		}
+		if _, used := byDomain[d]; used {
+			continue
+		}
+		fileName := filepath.Join(dir, e.Name())
+		if !conf.QuietMode {
+			// Progress: the template of a domain no longer used is removed.
+			fmt.Fprintf(os.Stderr,
+				console.Text("removing template %s of removed domain %s")+"\n",
+				fileName, d)
+		}
+		if err := os.Remove(fileName); err != nil {
+			return nil, fmt.Errorf("removing template of removed domain: %w", err)
+		}
+	}
+	return fileNames, nil
+}
+
+func writePOT(poEncoder gettext.Encoder, fileName string, pot gettext.FilePOT) error {
+	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
 	if err := poEncoder.EncodePOT(pot, f); err != nil {
-		return fmt.Errorf("encoding POT file: %w", err)
+		return fmt.Errorf("encoding POT file (%q): %w", fileName, err)
 	}
 	return nil
 }
 
+// assignMessageIDs assigns IDs to all new messages of collection
+// in the order of their hashes and updates the message ID registry file.
+func assignMessageIDs(
+	conf *config.ConfigGenerate, collection *codeparser.Collection,
+	migrated map[string]string,
+) (*msglock.Registry, error) {
+	fileName := filepath.Join(conf.BundlePkgPath, msglock.FileName)
+	r, err := msglock.Load(fileName)
+	if err != nil {
+		return nil, err
+	}
+	r.Rehash(migrated)
+	for m := range collection.Ordered() {
+		if id, added := r.Assign(m.Hash); added &&
+			!conf.QuietMode && conf.VerboseMode {
+			// Verbose log: a new message is assigned a numeric ID.
+			fmt.Fprintf(os.Stderr,
+				console.Text("assign message ID %d to %s")+"\n", id, m.Hash)
+		}
+	}
+	if err := r.WriteFile(fileName); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", msglock.FileName, err)
+	}
+	return r, nil
+}
+
+// migrateHashes migrates the message hashes of all catalogs of bundle
+// computed using another hash function to the hash function of collection
+// (see codeparser.MigrateHashes). Returns the new hashes by old hashes
+// of the source catalog, which is nil if it needs no migration.
+func migrateHashes(
+	conf *config.ConfigGenerate, bundle *codeparser.Bundle,
+	collection *codeparser.Collection,
+) (sourceNewByOld map[string]string, err error) {
+	var files []codeparser.POFile
+	if bundle.Source != nil {
+		files = append(files, *bundle.Source)
+	}
+	for _, parts := range bundle.CatalogParts {
+		files = append(files, parts...)
+	}
+	// Catalogs merged from multiple parts are copies of the parts
+	// used for the Go bundle only.
+	written := len(files)
+	for _, c := range bundle.Catalogs {
+		files = append(files, c)
+	}
+
+	// Hash functions are determined before any catalog is migrated
+	// since merged catalogs share the headers of their parts.
+	from := make([]codeparser.HashFunc, len(files))
+	for i, f := range files {
+		if from[i], err = codeparser.HashFuncOf(f.Head); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Path, err)
+		}
+	}
+
+	newByOld := map[codeparser.HashFunc]map[string]string{}
+	for i, f := range files {
+		if from[i] == collection.Hash {
+			continue
+		}
+		m, ok := newByOld[from[i]]
+		if !ok {
+			m = collection.Rehash(from[i])
+			newByOld[from[i]] = m
+		}
+		if bundle.Source != nil && i == 0 {
+			sourceNewByOld = m
+		}
+		n := codeparser.MigrateHashes(f.File, collection.Hash, m)
+		if i < written && !conf.QuietMode {
+			// Progress: the hashes of the messages of a catalog were migrated
+			// to another hash function.
+			fmt.Fprintf(os.Stderr,
+				console.Text("migrated %d hashes of %s from %s to %s")+"\n",
+				n, f.Path, from[i], collection.Hash)
+		}
+	}
+	return sourceNewByOld, nil
+}
+
+// trackSeen sets the seen comments of all messages of po to the current run
+// keeping the first seen runs recorded in the catalogs of bundle.
+// Returns the seen runs by message hash.
+func trackSeen(
+	conf *config.ConfigGenerate, bundle *codeparser.Bundle, po gettext.FilePO,
+) (map[string]msgseen.Seen, error) {
+	run := time.Now().UTC().Format(time.RFC3339)
+	if conf.TrackSeenVCS != nil {
+		var err error
+		if run, err = conf.TrackSeenVCS.Revision(conf.BundlePkgPath); err != nil {
+			return nil, err
+		}
+	}
+
+	var files []*gettext.File
+	if bundle.Source != nil {
+		files = append(files, bundle.Source.File)
+	}
+	for _, l := range slices.SortedFunc(maps.Keys(bundle.CatalogParts),
+		func(a, b language.Tag) int { return strings.Compare(a.String(), b.String()) }) {
+		for _, p := range bundle.CatalogParts[l] {
+			files = append(files, p.File)
+		}
+	}
+	firstSeen := msgseen.Index(files...)
+
+	seen := make(map[string]msgseen.Seen, len(po.Messages.List))
+	for i := range po.Messages.List {
+		m := &po.Messages.List[i]
+		hash := m.Msgctxt.Text.String()
+		s := msgseen.Seen{First: cmp.Or(firstSeen[hash], run), Last: run}
+		msgseen.SetComments(m, s)
+		sortCommentsByType(m)
+		seen[hash] = s
+	}
+	return seen, nil
+}
+
+// catalogChanges are the changes made by updateTranslationCatalogs.
+type catalogChanges struct {
+	// Added and Obsoleted are the numbers of messages added to and marked
+	// obsolete in translation catalogs summed over all catalogs.
+	Added, Obsoleted int
+
+	// Locales are the changes by locale ordered by locale.
+	Locales []summary.Locale
+
+	// Files are the paths of all written catalog files.
+	Files []string
+}
+
+// updateTranslationCatalogs syncs all translation catalogs with collection.
+// Message ID comments are updated unless messageIDs is nil,
+// seen comments are updated unless seen is nil.
 func updateTranslationCatalogs(
-	conf *config.ConfigGenerate,
+	ctx context.Context, conf *config.ConfigGenerate,
 	bundle *codeparser.Bundle, collection *codeparser.Collection,
+	messageIDs *msglock.Registry, seen map[string]msgseen.Seen,
 	poEncoder gettext.Encoder,
-) error {
-	collMsgsByHash := make(map[string]codeparser.Msg, len(collection.Messages))
-	for msg := range collection.Messages {
-		collMsgsByHash[msg.Hash] = msg
+) (changes catalogChanges, err error) {
+	// Buffers are reused across locales.
+	inCatalog := make(map[string]*gettext.Message, len(collection.Messages))
+	var added []gettext.Message
+	// replaced are the translated messages obsoleted by this run,
+	// which added messages may replace (see resolveChangedSources).
+	var replaced []*gettext.Message
+	var stdin *bufio.Reader
+	if conf.Resolve == fuzzy.Prompt {
+		stdin = bufio.NewReader(os.Stdin)
 	}
 
-	for l, b := range bundle.Catalogs {
+	for l, parts := range bundle.CatalogParts {
 		locale := l.String()
+		if salvaged(parts) {
+			// Rewriting the catalogs would drop the skipped entries.
+			continue
+		}
 
 		pluralForms, ok := cldr.ByTagOrBase(l)
 		if !ok {
-			return fmt.Errorf("couldn't find plural forms for locale: %s", locale)
+			return changes, fmt.Errorf("couldn't find plural forms for locale: %s", locale)
 		}
 
-		inCatalog := map[string]*gettext.Message{}
+		clear(inCatalog)
+		added, replaced = added[:0], replaced[:0]
+		localeChanges := summary.Locale{Locale: locale}
 
-		for i, m := range b.Messages.List {
-			msgctxt := m.Msgctxt.Text.String()
-			if _, ok := collMsgsByHash[msgctxt]; !ok {
-				// Message not found in source code any more, make it obsolete.
-				if b.Messages.List[i].Obsolete {
-					// Already marked as obsolete.
-					continue
+		categories := pluralsample.Categories(pluralForms)
+		var samples []string
+		if conf.PluralSamples {
+			samples = pluralsample.Comments(pluralForms)
+		}
+
+		if pluralForms.Merged != nil {
+			// Propagate overridden plural forms to the catalog headers.
+			for _, b := range parts {
+				b.Head.PluralForms = gettext.HeaderPluralForms{
+					N:          uint8(len(pluralForms.CardinalForms)),
+					Expression: pluralForms.GettextFormula,
 				}
+			}
+		}
 
-				if !conf.QuietMode && conf.VerboseMode {
-					fmt.Fprintf(os.Stderr, "obsolete message %s in locale %s\n",
-						msgctxt, locale)
+		for _, b := range parts {
+			for i, m := range b.Messages.List {
+				msgctxt := m.Msgctxt.Text.String()
+				if strings.HasPrefix(msgctxt, localize.GrammarContextPrefix) ||
+					strings.HasPrefix(msgctxt, localize.RegisterContextPrefix) {
+					// Grammar entries and register variants are maintained
+					// by translators and never used in source code directly.
+					continue
 				}
+				if _, meta, ok := collection.ByHash(msgctxt); !ok ||
+					!ordinal.Used(meta.Ordinals, pluralForms) {
+					// Message not found in source code any more or only used
+					// for ordinal categories of other locales, make it obsolete.
+					if b.Messages.List[i].Obsolete {
+						// Already marked as obsolete.
+						continue
+					}
+
+					if !conf.QuietMode && conf.VerboseMode {
+						// Verbose log: a message no longer used in the source code is marked obsolete.
+						fmt.Fprintf(os.Stderr,
+							console.Text("obsolete message %s in locale %s")+"\n",
+							msgctxt, locale)
+					}
 
-				m.Obsolete = true
-				b.Messages.List[i] = m
+					m.Obsolete = true
+					b.Messages.List[i] = m
+					localeChanges.Obsoleted++
+					if coverage.IsTranslated(&m) {
+						replaced = append(replaced, &b.Messages.List[i])
+					}
+				}
+				inCatalog[msgctxt] = &b.Messages.List[i]
 			}
-			inCatalog[msgctxt] = &b.Messages.List[i]
 		}
 
 		for m, meta := range collection.Messages {
+			if !ordinal.Used(meta.Ordinals, pluralForms) {
+				// Forms of ordinal categories the locale doesn't use
+				// are never selected by its readers.
+				continue
+			}
 			if catalogMsg, ok := inCatalog[m.Hash]; !ok {
 				// New message to be added to the catalog.
 
 				if !conf.QuietMode && conf.VerboseMode {
-					fmt.Fprintf(os.Stderr, "add missing message %s in locale %s\n",
+					// Verbose log: a message is added to a catalog.
+					fmt.Fprintf(os.Stderr,
+						console.Text("add missing message %s in locale %s")+"\n",
 						m.Hash, locale)
 				}
 
 				nm := codeparser.MsgFromGettextMessage(pluralForms, m, meta)
-				if len(nm.Msgstr.Text.Lines) > 0 {
-					nm.Msgstr.Text = gettext.StringLiterals{
-						Lines: []gettext.StringLiteral{{}},
+				for _, msgstr := range []*gettext.Msgstr{
+					&nm.Msgstr, &nm.Msgstr0, &nm.Msgstr1, &nm.Msgstr2,
+					&nm.Msgstr3, &nm.Msgstr4, &nm.Msgstr5,
+				} {
+					if len(msgstr.Text.Lines) > 0 {
+						msgstr.Text = gettext.StringLiterals{
+							Lines: []gettext.StringLiteral{{}},
+						}
 					}
 				}
-				if len(nm.Msgstr0.Text.Lines) > 0 {
-					nm.Msgstr0.Text = gettext.StringLiterals{
-						Lines: []gettext.StringLiteral{{}},
-					}
+				if messageIDs != nil {
+					id, _ := messageIDs.ID(m.Hash)
+					msglock.SetComment(&nm, id)
 				}
-				if len(nm.Msgstr1.Text.Lines) > 0 {
-					nm.Msgstr1.Text = gettext.StringLiterals{
-						Lines: []gettext.StringLiteral{{}},
-					}
+				if seen != nil {
+					msgseen.SetComments(&nm, seen[m.Hash])
 				}
-				if len(nm.Msgstr2.Text.Lines) > 0 {
-					nm.Msgstr2.Text = gettext.StringLiterals{
-						Lines: []gettext.StringLiteral{{}},
+				if isPlural(m) {
+					pluralsample.SetCategories(&nm, categories)
+					if samples != nil {
+						pluralsample.Set(&nm, samples)
 					}
 				}
-				if len(nm.Msgstr3.Text.Lines) > 0 {
-					nm.Msgstr3.Text = gettext.StringLiterals{
-						Lines: []gettext.StringLiteral{{}},
-					}
+				added = append(added, nm)
+				localeChanges.New++
+			} else {
+				before := slices.Clone(catalogMsg.Msgctxt.Comments.Text)
+				updateComments(catalogMsg, meta)
+				if commentsChanged(before, catalogMsg.Msgctxt.Comments.Text) {
+					localeChanges.Changed++
 				}
-				if len(nm.Msgstr4.Text.Lines) > 0 {
-					nm.Msgstr4.Text = gettext.StringLiterals{
-						Lines: []gettext.StringLiteral{{}},
-					}
+				if messageIDs != nil {
+					id, _ := messageIDs.ID(m.Hash)
+					msglock.SetComment(catalogMsg, id)
+					sortCommentsByType(catalogMsg)
+				}
+				if seen != nil {
+					msgseen.SetComments(catalogMsg, seen[m.Hash])
+					sortCommentsByType(catalogMsg)
 				}
-				if len(nm.Msgstr5.Text.Lines) > 0 {
-					nm.Msgstr5.Text = gettext.StringLiterals{
-						Lines: []gettext.StringLiteral{{}},
+				if isPlural(m) {
+					pluralsample.SetCategories(catalogMsg, categories)
+					if samples != nil {
+						pluralsample.Set(catalogMsg, samples)
 					}
+					sortCommentsByType(catalogMsg)
 				}
-				b.Messages.List = append(b.Messages.List, nm)
-			} else {
-				updateComments(catalogMsg, meta)
 			}
 		}
 
-		if !conf.QuietMode {
-			fmt.Fprintf(os.Stderr, "updating catalog %s\n", b.Path)
+		if conf.Resolve != fuzzy.Clear && len(replaced) > 0 {
+			if err := resolveChangedSources(
+				conf, stdin, locale, replaced, added,
+			); err != nil {
+				return changes, err
+			}
 		}
 
-		f, err := os.OpenFile(b.Path, os.O_WRONLY|os.O_TRUNC, 0o644)
-		if err != nil {
-			return fmt.Errorf("opening catalog file: %w", err)
+		changes.Added += localeChanges.New
+		changes.Obsoleted += localeChanges.Obsoleted
+		changes.Locales = append(changes.Locales, localeChanges)
+
+		// Catalog files of domains no longer used are removed
+		// and their messages moved to the catalog file of their domain,
+		// or the default domain if their domain isn't used either.
+		used := map[string]bool{"": true}
+		for _, b := range parts {
+			for i := range b.Messages.List {
+				if !b.Messages.List[i].Obsolete {
+					used[conf.Domains.Of(&b.Messages.List[i])] = true
+				}
+			}
+		}
+		for i := range added {
+			used[conf.Domains.Of(&added[i])] = true
+		}
+		first := parts[0]
+		kept := make([]codeparser.POFile, 0, len(parts))
+		var stale []codeparser.POFile
+		for _, b := range parts {
+			if used[b.Domain] {
+				kept = append(kept, b)
+				continue
+			}
+			stale = append(stale, b)
+			added = append(added, b.Messages.List...)
 		}
+		parts = kept
 
-		if err := poEncoder.EncodePO(b.FilePO, f); err != nil {
-			return fmt.Errorf("encoding catalog file: %w", err)
+		// Add new messages to the catalog file of their domain.
+		// Catalog files for new domains are created as necessary.
+		for _, nm := range added {
+			d := conf.Domains.Of(&nm)
+			if !used[d] {
+				d = ""
+			}
+			i := slices.IndexFunc(parts, func(p codeparser.POFile) bool {
+				return p.Domain == d
+			})
+			if i == -1 {
+				dir, name := filepath.Split(first.Path)
+				if first.Domain != "" {
+					name = "catalog." + locale + ".po"
+				}
+				parts = append(parts, codeparser.POFile{
+					Path:   filepath.Join(dir, domain.FileName(name, d)),
+					Domain: d,
+					FilePO: gettext.FilePO{File: &gettext.File{
+						Head:   first.Head.Clone(),
+						Format: first.Format,
+					}},
+				})
+				i = len(parts) - 1
+			}
+			parts[i].Messages.List = append(parts[i].Messages.List, nm)
 		}
-	}
-	return nil
-}
 
-// updateComments syncs the code reference comments in dst with the position from m
-// and returns true if any changes were made, otherwise returns false.
-func updateComments(dst *gettext.Message, m codeparser.MsgMeta) {
-	indexOfComment := func(formatted string) int {
-		for i, com := range dst.Msgctxt.Comments.Text {
-			if com.Type != gettext.CommentTypeReference {
-				continue
+		for _, b := range stale {
+			if !conf.QuietMode {
+				// Progress: the catalog file of a domain no longer used is removed.
+				fmt.Fprintf(os.Stderr,
+					console.Text("removing catalog %s of removed domain %s")+"\n",
+					b.Path, b.Domain)
+			}
+			if err := os.Remove(b.Path); err != nil {
+				return changes, fmt.Errorf("removing catalog of removed domain: %w", err)
+			}
+		}
+
+		for _, b := range parts {
+			if err := ctx.Err(); err != nil {
+				return changes, err
+			}
+			if !conf.QuietMode {
+				// Progress: a catalog file is being updated.
+				fmt.Fprintf(os.Stderr, console.Text("updating catalog %s")+"\n", b.Path)
 			}
-			if com.Value == formatted {
-				return i
+
+			section.Group(b.Messages.List)
+
+			if conf.Blame != nil {
+				if err := blameTranslator(conf.Blame, b); err != nil &&
+					!conf.QuietMode {
+					// Warning about a failure to determine the translators of a catalog.
+					warnf(console.Text("WARNING: blaming catalog %s: %v"),
+						b.Path, err)
+				}
+			}
+
+			f, err := os.OpenFile(b.Path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+			if err != nil {
+				return changes, fmt.Errorf("opening catalog file: %w", err)
+			}
+
+			err = poEncoder.EncodePO(b.FilePO, f)
+			_ = f.Close()
+			if err != nil {
+				return changes, fmt.Errorf("encoding catalog file: %w", err)
 			}
+			changes.Files = append(changes.Files, b.Path)
 		}
-		return -1
 	}
-	indexOfPos := func(comment string) int {
-		for i, pos := range m.Pos {
-			formatted := gettext.FmtCodeRef(pos.Filename, pos.Line)
-			if formatted == comment {
-				return i
+	slices.Sort(changes.Files)
+	slices.SortFunc(changes.Locales, func(a, b summary.Locale) int {
+		return strings.Compare(a.Locale, b.Locale)
+	})
+	return changes, nil
+}
+
+// isPlural returns true for messages of Plural and PluralBlock calls.
+func isPlural(m codeparser.Msg) bool {
+	return m.FuncType == codeparser.FuncTypePlural ||
+		m.FuncType == codeparser.FuncTypePluralBlock
+}
+
+// appendAuditEntry appends the entry of the current run
+// to the audit log of the bundle package.
+func appendAuditEntry(
+	conf *config.ConfigGenerate, changes catalogChanges, written []string,
+) error {
+	files, err := audit.HashFiles(conf.BundlePkgPath, written)
+	if err != nil {
+		return fmt.Errorf("hashing written files: %w", err)
+	}
+	return audit.Append(filepath.Join(conf.BundlePkgPath, audit.FileName), audit.Entry{
+		Time:      time.Now().UTC(),
+		Added:     changes.Added,
+		Obsoleted: changes.Obsoleted,
+		Files:     files,
+	})
+}
+
+// headerTranslatedByCommit is the catalog header carrying the commit
+// of the most recent translation change.
+const headerTranslatedByCommit = "X-Translated-By-Commit"
+
+// blameTranslator sets the Last-Translator and X-Translated-By-Commit headers
+// of b to the author and commit of the most recent committed change
+// to any translation of b. Headers are left unchanged if no translation
+// was committed yet or if b doesn't exist on disk yet.
+func blameTranslator(blame vcs.Blamer, b codeparser.POFile) error {
+	if _, err := os.Stat(b.Path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	origins, err := blame.Blame(b.Path)
+	if err != nil {
+		return err
+	}
+
+	var lines []int
+	for _, m := range b.Messages.List {
+		if m.Obsolete {
+			continue
+		}
+		for _, msgstr := range []gettext.Msgstr{
+			m.Msgstr, m.Msgstr0, m.Msgstr1, m.Msgstr2,
+			m.Msgstr3, m.Msgstr4, m.Msgstr5,
+		} {
+			for _, l := range msgstr.Text.Lines {
+				lines = append(lines, int(l.Line))
 			}
 		}
-		return -1
 	}
+	latest, ok := vcs.Latest(origins, lines)
+	if !ok {
+		return nil
+	}
+
+	b.Head.LastTranslator = latest.Translator()
+	i := slices.IndexFunc(b.Head.NonStandard, func(h gettext.XHeader) bool {
+		return h.Name == headerTranslatedByCommit
+	})
+	if i == -1 {
+		b.Head.NonStandard = append(b.Head.NonStandard, gettext.XHeader{
+			Name: headerTranslatedByCommit,
+		})
+		i = len(b.Head.NonStandard) - 1
+	}
+	b.Head.NonStandard[i].Value = latest.Commit
+	return nil
+}
 
-	for ci, com := range dst.Msgctxt.Comments.Text {
-		if com.Type != gettext.CommentTypeReference {
+// resolveChangedSources carries the translations of the messages of
+// replaced forward to the messages of added replacing them at the same
+// code reference, which changed their source text, as resolved by
+// conf.Resolve asking on stdin for fuzzy.Prompt.
+func resolveChangedSources(
+	conf *config.ConfigGenerate, stdin *bufio.Reader, locale string,
+	replaced []*gettext.Message, added []gettext.Message,
+) error {
+	// Messages are matched and prompted for in a deterministic order.
+	slices.SortFunc(added, func(a, b gettext.Message) int {
+		return strings.Compare(a.Msgctxt.Text.String(), b.Msgctxt.Text.String())
+	})
+	for i := range added {
+		nm := &added[i]
+		j := fuzzy.Match(replaced, nm)
+		if j == -1 {
 			continue
 		}
-		i := indexOfPos(com.Value)
-		if i == -1 {
-			// Reference comment is obsolete, remove it.
-			dst.Msgctxt.Comments.Text = slices.Delete(dst.Msgctxt.Comments.Text, ci, ci+1)
-		}
-	}
-	for _, pos := range m.Pos {
-		formatted := gettext.FmtCodeRef(pos.Filename, pos.Line)
-		i := indexOfComment(formatted)
-		if i == -1 {
-			// New position, add new reference comment.
-			dst.Msgctxt.Comments.Text = append(dst.Msgctxt.Comments.Text,
-				gettext.Comment{
-					Type:  gettext.CommentTypeReference,
-					Value: formatted,
-				})
+		previous := replaced[j]
+		replaced = slices.Delete(replaced, j, j+1)
+
+		r := conf.Resolve
+		if r == fuzzy.Prompt {
+			var err error
+			if r, err = promptResolution(stdin, os.Stderr, locale, previous, nm); err != nil {
+				return err
+			}
+		}
+		fuzzy.Carry(nm, previous, r)
+		sortCommentsByType(nm)
+		if r != fuzzy.Clear && !conf.QuietMode && conf.VerboseMode {
+			// Verbose log: the translation of a message whose source text
+			// changed is carried forward to the message replacing it.
+			fmt.Fprintf(os.Stderr,
+				console.Text("carry translation of %s forward to %s in locale %s")+"\n",
+				previous.Msgctxt.Text.String(), nm.Msgctxt.Text.String(), locale)
+		}
+		if len(replaced) < 1 {
+			break
+		}
+	}
+	return nil
+}
+
+// promptResolution asks on w for the resolution of the translation of
+// previous whose source text changed to that of nm, reading the answer
+// from in until it's valid.
+func promptResolution(
+	in *bufio.Reader, w io.Writer, locale string, previous, nm *gettext.Message,
+) (fuzzy.Resolution, error) {
+	// Header of a message whose source text changed, followed by
+	// the texts before and after the change and its translation.
+	_, _ = fmt.Fprintf(w, console.Text("%s: source text of a translated message changed")+"\n",
+		locale)
+	_, _ = fmt.Fprintf(w, "  - %q\n  + %q\n", sourceOf(previous), sourceOf(nm))
+	_, _ = fmt.Fprintf(w, "  %s: %q\n", locale, translationOf(previous))
+	for {
+		// Question asking how to resolve the translation of a message
+		// whose source text changed. k keeps the translation, f keeps it
+		// flagged as fuzzy and c clears it.
+		_, _ = fmt.Fprint(w, console.Text("keep, fuzzy or clear? [k/f/c] "))
+		answer, err := in.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || answer == "") {
+			return "", fmt.Errorf("reading answer: %w", err)
 		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "k", "keep":
+			return fuzzy.Keep, nil
+		case "f", "fuzzy":
+			return fuzzy.Fuzzy, nil
+		case "c", "clear":
+			return fuzzy.Clear, nil
+		}
+		if errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("reading answer: %w", io.ErrUnexpectedEOF)
+		}
+	}
+}
+
+// sourceOf returns the source text of m, form other of plural messages.
+func sourceOf(m *gettext.Message) string {
+	if len(m.MsgidPlural.Text.Lines) > 0 {
+		return m.MsgidPlural.Text.String()
+	}
+	return m.Msgid.Text.String()
+}
+
+// translationOf returns the translation of m, the last form of
+// plural messages.
+func translationOf(m *gettext.Message) string {
+	for _, s := range [...]*gettext.Msgstr{
+		&m.Msgstr5, &m.Msgstr4, &m.Msgstr3, &m.Msgstr2, &m.Msgstr1, &m.Msgstr0,
+	} {
+		if len(s.Text.Lines) > 0 {
+			return s.Text.String()
+		}
+	}
+	return m.Msgstr.Text.String()
+}
+
+// updateComments syncs the code reference comments in dst with the positions
+// from m and updates the edition and protected comments.
+func updateComments(dst *gettext.Message, m codeparser.MsgMeta) {
+	// Replace all reference comments to keep them ordered
+	// by file and line like m.Pos.
+	dst.Msgctxt.Comments.Text = slices.DeleteFunc(dst.Msgctxt.Comments.Text,
+		func(c gettext.Comment) bool { return c.Type == gettext.CommentTypeReference })
+	for _, ref := range m.References() {
+		dst.Msgctxt.Comments.Text = append(dst.Msgctxt.Comments.Text,
+			gettext.Comment{
+				Type:  gettext.CommentTypeReference,
+				Value: ref,
+			})
 	}
 
+	edition.Set(dst, m.Editions)
+	region.Set(dst, m.Regions)
+	errcode.Set(dst, m.ErrorCodes)
+	schedule.Set(dst, m.Schedule)
+	heading.Set(dst, m.Heading)
+	protect.Set(dst, m.Protected)
+	section.Set(dst, m.Section)
+	ordinal.Set(dst, m.Ordinals)
+
 	// Sort comments to enforce strict comment order by type.
 	sortCommentsByType(dst)
 }
 
+// commentsChanged returns true if the comments before differ from
+// the comments after, which are sorted by type, ignoring their positions.
+func commentsChanged(before, after []gettext.Comment) bool {
+	slices.SortStableFunc(before, func(a, b gettext.Comment) int {
+		return cmp.Compare(a.Type, b.Type)
+	})
+	return !slices.EqualFunc(before, after, func(a, b gettext.Comment) bool {
+		return a.Type == b.Type && a.Value == b.Value
+	})
+}
+
 func sortCommentsByType(m *gettext.Message) {
 	cmp := func(a, b gettext.Comment) int { return cmp.Compare(a.Type, b.Type) }
-	slices.SortFunc(m.Msgctxt.Comments.Text, cmp)
-	slices.SortFunc(m.Msgid.Comments.Text, cmp)
-	slices.SortFunc(m.MsgidPlural.Comments.Text, cmp)
-	slices.SortFunc(m.Msgstr.Comments.Text, cmp)
-	slices.SortFunc(m.Msgstr0.Comments.Text, cmp)
-	slices.SortFunc(m.Msgstr1.Comments.Text, cmp)
-	slices.SortFunc(m.Msgstr2.Comments.Text, cmp)
-	slices.SortFunc(m.Msgstr3.Comments.Text, cmp)
-	slices.SortFunc(m.Msgstr4.Comments.Text, cmp)
-	slices.SortFunc(m.Msgstr5.Comments.Text, cmp)
+	slices.SortStableFunc(m.Msgctxt.Comments.Text, cmp)
+	slices.SortStableFunc(m.Msgid.Comments.Text, cmp)
+	slices.SortStableFunc(m.MsgidPlural.Comments.Text, cmp)
+	slices.SortStableFunc(m.Msgstr.Comments.Text, cmp)
+	slices.SortStableFunc(m.Msgstr0.Comments.Text, cmp)
+	slices.SortStableFunc(m.Msgstr1.Comments.Text, cmp)
+	slices.SortStableFunc(m.Msgstr2.Comments.Text, cmp)
+	slices.SortStableFunc(m.Msgstr3.Comments.Text, cmp)
+	slices.SortStableFunc(m.Msgstr4.Comments.Text, cmp)
+	slices.SortStableFunc(m.Msgstr5.Comments.Text, cmp)
 }
@@ -1,11 +1,39 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/cmd/localize/internal/localizebundle"
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/audit"
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/clidoc"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/config"
+	"github.com/romshark/localize/internal/fuzzy"
+	"github.com/romshark/localize/internal/gengo"
+	"github.com/romshark/localize/internal/msglock"
+	"github.com/romshark/localize/internal/summary"
+	"github.com/romshark/localize/internal/untranslated"
+	"github.com/romshark/localize/localizetest"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
 )
 
 func TestExtract(t *testing.T) {
@@ -15,8 +43,35 @@ func TestExtract(t *testing.T) {
 	outDir := t.TempDir()
 	bundleDir := filepath.Join(outDir, "localizebundle")
 
-	err := run([]string{"extract", "generate", "-b", bundleDir, "-l", "en"})
+	err := run(context.Background(), []string{
+		"localize", "generate", "-b", bundleDir,
+		"-import-path", "example.com/localizebundle", "-l", "en",
+	})
+	require.NoError(t, err)
+}
+
+func TestConsoleReader(t *testing.T) {
+	require.Equal(t, "FEHLER:", consoleReader(language.German).Text("ERR:"))
+	require.Equal(t, "ERR:", consoleReader(language.Japanese).Text("ERR:"))
+
+	t.Setenv("LC_ALL", "de_CH.UTF-8")
+	require.Equal(t, "FEHLER:", consoleReader(language.Und).Text("ERR:"))
+	require.Equal(t, "ERR:", consoleReader(language.English).Text("ERR:"))
+}
+
+func TestConsoleBundleNew(t *testing.T) {
+	b, err := localizebundle.New()
+	require.NoError(t, err)
+	require.Equal(t, []language.Tag{language.English, language.German}, b.Locales())
+	require.Equal(t, language.English, b.Default().Locale())
+
+	b, err = localizebundle.New(language.German, language.German)
 	require.NoError(t, err)
+	require.Equal(t, []language.Tag{language.German}, b.Locales())
+	require.Equal(t, "FEHLER:", b.ForLocale(language.English).Text("ERR:"))
+
+	_, err = localizebundle.New(language.German, language.Japanese)
+	require.ErrorIs(t, err, localize.ErrNoReader)
 }
 
 func testSetup(t *testing.T) string {
@@ -47,6 +102,7 @@ gopkg.in/yaml.v3 v3.0.1/go.mod h1:K4uyk7z7BCEPqu6E+C64Yfv1cQ7kz7rIZviUmN+EgEM=
 		`main.go`: `package main
 
 import (
+	"context"
 	"fmt"
 
 	"example/subpack"
@@ -85,6 +141,7 @@ func main() {
 		`subpack/subpack.go`: `package subpack
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/romshark/localize"
@@ -121,3 +178,1268 @@ func CreateSetup(t *testing.T, fileMap map[string]string) string {
 
 	return root
 }
+
+func TestConsoleReaderMetadata(t *testing.T) {
+	m := localizebundle.CatalogDe{}.Metadata()
+	require.Equal(t, "de", m["Language"])
+	require.Equal(t, "nplurals=2; plural=n != 1;", m["Plural-Forms"])
+
+	localizetest.TestReaderConformance(t, localizebundle.CatalogDe{})
+}
+
+func TestWriteSourceErrorsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeSourceErrorsJSON(&buf, []codeparser.ErrorSrc{{
+		Position: token.Position{Filename: "/main.go", Line: 4, Column: 2},
+		Err:      codeparser.ErrSourceTextEmpty,
+	}})
+	require.NoError(t, err)
+	require.JSONEq(t, `[{
+		"file": "/main.go", "line": 4, "column": 2, "severity": "error",
+		"code": "text-empty", "message": "text empty"
+	}]`, buf.String())
+}
+
+func TestClassifySourceErrors(t *testing.T) {
+	srcErrs := func() []codeparser.ErrorSrc {
+		return []codeparser.ErrorSrc{
+			{Err: codeparser.ErrSourceTextEmpty},
+			{
+				Err:      codeparser.ErrDescriptionMissing,
+				Severity: codeparser.SeverityWarning,
+			},
+			{
+				Err:      codeparser.ErrSuspiciousPlaceholder,
+				Severity: codeparser.SeverityWarning,
+			},
+		}
+	}
+	severities := func(l []codeparser.ErrorSrc) (s []string) {
+		for _, e := range l {
+			s = append(s, e.Code()+":"+e.Severity.String())
+		}
+		return s
+	}
+
+	require.Equal(t, []string{
+		"text-empty:error",
+		"description-missing:warning",
+		"placeholder-suspicious:warning",
+	}, severities(classifySourceErrors(&config.ConfigGenerate{}, srcErrs())))
+
+	require.Equal(t, []string{
+		"text-empty:error",
+		"description-missing:error",
+		"placeholder-suspicious:error",
+	}, severities(classifySourceErrors(&config.ConfigGenerate{
+		WarningsAsErrorsAll: true,
+	}, srcErrs())))
+
+	require.Equal(t, []string{
+		"text-empty:error",
+		"placeholder-suspicious:error",
+	}, severities(classifySourceErrors(&config.ConfigGenerate{
+		WarningsAsErrors: []string{"placeholder-suspicious"},
+		WarningsIgnored:  []string{"description-missing"},
+	}, srcErrs())))
+}
+
+func TestFormatByteSize(t *testing.T) {
+	for _, tt := range []struct {
+		n      int64
+		expect string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{-2048, "-2.0 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+		{2048 << 30, "2048.0 GiB"},
+	} {
+		require.Equal(t, tt.expect, formatByteSize(tt.n), tt.n)
+	}
+}
+
+// BenchmarkGenerate50Locales benchmarks generate on this package
+// with a bundle of 50 translation catalogs.
+func BenchmarkGenerate50Locales(b *testing.B) {
+	bundleDir := filepath.Join(b.TempDir(), "localizebundle")
+	generate := func() {
+		err := run(context.Background(), []string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+		})
+		require.NoError(b, err)
+	}
+	generate() // Create the source catalog.
+
+	for _, l := range []string{
+		"af", "am", "ar", "az", "be", "bg", "bn", "bs", "ca", "cs",
+		"cy", "da", "de", "el", "es", "et", "eu", "fa", "fi", "fr",
+		"ga", "gl", "gu", "he", "hi", "hr", "hu", "hy", "id", "is",
+		"it", "ja", "ka", "kk", "km", "kn", "ko", "lt", "lv", "mk",
+		"ml", "mn", "mr", "ms", "nl", "pl", "pt", "ro", "ru", "uk",
+	} {
+		pf, ok := cldr.ByTagOrBase(language.MustParse(l))
+		require.True(b, ok, l)
+		err := os.WriteFile(filepath.Join(bundleDir, "catalog."+l+".po"), fmt.Appendf(nil,
+			"msgid \"\"\nmsgstr \"\"\n"+
+				"\"Language: %s\\n\"\n"+
+				"\"MIME-Version: 1.0\\n\"\n"+
+				"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+				"\"Content-Transfer-Encoding: 8bit\\n\"\n"+
+				"\"Plural-Forms: nplurals=%d; plural=%s;\\n\"\n",
+			l, len(pf.CardinalForms), pf.GettextFormula,
+		), 0o644)
+		require.NoError(b, err)
+	}
+	generate() // Add all messages to the new catalogs.
+
+	b.ResetTimer()
+	for b.Loop() {
+		generate()
+	}
+}
+
+func TestCopyDirDiffDirs(t *testing.T) {
+	src := CreateSetup(t, map[string]string{
+		"go.mod":               "module example\n",
+		"bundle/catalog.pot":   "msgid \"\"\n",
+		".git/HEAD":            "ref: refs/heads/main\n",
+		"bundle/bundle_gen.go": "package bundle\n",
+	})
+	a, b := t.TempDir(), t.TempDir()
+	require.NoError(t, copyDir(src, a))
+	require.NoError(t, copyDir(src, b))
+	require.NoDirExists(t, filepath.Join(a, ".git"))
+
+	differ, files, err := diffDirs(a, b)
+	require.NoError(t, err)
+	require.Empty(t, differ)
+	require.Equal(t, 3, files)
+
+	require.NoError(t, os.WriteFile(
+		filepath.Join(b, "bundle", "bundle_gen.go"), []byte("package x\n"), 0o644,
+	))
+	require.NoError(t, os.WriteFile(filepath.Join(a, "extra.txt"), nil, 0o644))
+	differ, _, err = diffDirs(a, b)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bundle/bundle_gen.go", "extra.txt"}, differ)
+
+	root, ok := config.FindModuleRoot(filepath.Join(a, "bundle"))
+	require.True(t, ok)
+	require.Equal(t, a, root)
+}
+
+func TestGenerateAudit(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	generate := func() {
+		t.Helper()
+		err := run(context.Background(), []string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q", "-audit",
+		})
+		require.NoError(t, err)
+	}
+	readEntries := func() (entries []audit.Entry) {
+		t.Helper()
+		f, err := os.Open(filepath.Join(bundleDir, audit.FileName))
+		require.NoError(t, err)
+		defer func() { _ = f.Close() }()
+		d := json.NewDecoder(f)
+		for d.More() {
+			var e audit.Entry
+			require.NoError(t, d.Decode(&e))
+			entries = append(entries, e)
+		}
+		return entries
+	}
+
+	generate()
+	entries := readEntries()
+	require.Len(t, entries, 1)
+	require.Zero(t, entries[0].Added)
+	require.Contains(t, entries[0].Files, "source.en.po")
+	require.Contains(t, entries[0].Files, "catalog.pot")
+	require.Contains(t, entries[0].Files, "localizebundle_gen.go")
+
+	err := os.WriteFile(filepath.Join(bundleDir, "catalog.de.po"), []byte(
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"Language: de\\n\"\n"+
+			"\"MIME-Version: 1.0\\n\"\n"+
+			"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+			"\"Content-Transfer-Encoding: 8bit\\n\"\n"+
+			"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n"+
+			"\n"+
+			"msgctxt \"gone\"\nmsgid \"Gone\"\nmsgstr \"Weg\"\n",
+	), 0o644)
+	require.NoError(t, err)
+	generate()
+	entries = readEntries()
+	require.Len(t, entries, 2)
+	require.Positive(t, entries[1].Added)
+	require.Equal(t, 1, entries[1].Obsoleted)
+	require.Contains(t, entries[1].Files, "catalog.de.po")
+
+	generate()
+	entries = readEntries()
+	require.Len(t, entries, 3)
+	require.Zero(t, entries[2].Added)
+	require.Zero(t, entries[2].Obsoleted)
+}
+
+func TestGenerateSourceMetadata(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	generate := func() string {
+		t.Helper()
+		require.NoError(t, run(context.Background(), []string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+		}))
+		b, err := os.ReadFile(goBundleFile(bundleDir))
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	// The metadata of the source catalog is available after the first run.
+	first := generate()
+	_, metadata, ok := strings.Cut(first, "var catalogEnMetadata = map[string]string{")
+	require.True(t, ok)
+	metadata, _, _ = strings.Cut(metadata, "}")
+	require.Contains(t, metadata, `"Language":`)
+	require.Contains(t, metadata, `"Plural-Forms":`)
+
+	require.Equal(t, first, generate(), "output must be deterministic")
+}
+
+func TestGenerateSummary(t *testing.T) {
+	dir := t.TempDir()
+	bundleDir := filepath.Join(dir, "localizebundle")
+	summaryPath := filepath.Join(dir, "localize-summary.json")
+	generate := func() (s summary.Summary) {
+		t.Helper()
+		err := run(context.Background(), []string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+			"-summary", summaryPath,
+		})
+		require.NoError(t, err)
+		b, err := os.ReadFile(summaryPath)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &s))
+		return s
+	}
+
+	s := generate()
+	require.Positive(t, s.Messages)
+	require.Empty(t, s.Locales)
+	require.Contains(t, s.Files, filepath.ToSlash(filepath.Join(bundleDir, "catalog.pot")))
+	phases := make([]string, len(s.Phases))
+	for i, p := range s.Phases {
+		phases[i] = p.Name
+	}
+	require.Equal(t, []string{
+		"analyze", "templates", "bundle", "catalogs", "plugins", "report",
+	}, phases)
+	require.Positive(t, s.TimeTotalNanoseconds)
+
+	err := os.WriteFile(filepath.Join(bundleDir, "catalog.de.po"), []byte(
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"Language: de\\n\"\n"+
+			"\"MIME-Version: 1.0\\n\"\n"+
+			"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+			"\"Content-Transfer-Encoding: 8bit\\n\"\n"+
+			"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n"+
+			"\n"+
+			"msgctxt \"gone\"\nmsgid \"Gone\"\nmsgstr \"Weg\"\n",
+	), 0o644)
+	require.NoError(t, err)
+	s = generate()
+	require.Equal(t, []summary.Locale{{
+		Locale: "de", New: int(s.Messages), Obsoleted: 1,
+	}}, s.Locales)
+	require.Contains(t, s.Files, filepath.ToSlash(filepath.Join(bundleDir, "catalog.de.po")))
+
+	s = generate()
+	require.Equal(t, []summary.Locale{{Locale: "de"}}, s.Locales)
+
+	// A stale code reference changes the message.
+	catalog := filepath.Join(bundleDir, "catalog.de.po")
+	b, err := os.ReadFile(catalog)
+	require.NoError(t, err)
+	b = bytes.Replace(b, []byte("#: "), []byte("#: /removed.go:1\n#: "), 1)
+	require.NoError(t, os.WriteFile(catalog, b, 0o644))
+	s = generate()
+	require.Equal(t, []summary.Locale{{Locale: "de", Changed: 1}}, s.Locales)
+}
+
+func TestGenerateStrictCatalogHashes(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	generate := func(flags ...string) error {
+		t.Helper()
+		return run(context.Background(), append([]string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+		}, flags...))
+	}
+	require.NoError(t, os.MkdirAll(bundleDir, 0o755))
+	catalog := filepath.Join(bundleDir, "catalog.de.po")
+	require.NoError(t, os.WriteFile(catalog, []byte(
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"Language: de\\n\"\n"+
+			"\"MIME-Version: 1.0\\n\"\n"+
+			"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+			"\"Content-Transfer-Encoding: 8bit\\n\"\n"+
+			"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n",
+	), 0o644))
+	require.NoError(t, generate())
+	require.NoError(t, generate("-strict"))
+
+	// Translating a message without regenerating the bundle.
+	b, err := os.ReadFile(catalog)
+	require.NoError(t, err)
+	i := bytes.Index(b, []byte("msgctxt"))
+	j := bytes.Index(b[i:], []byte("msgstr \"\""))
+	require.Positive(t, j)
+	b = slices.Concat(b[:i+j], []byte("msgstr \"Übersetzt\""), b[i+j+len("msgstr \"\""):])
+	require.NoError(t, os.WriteFile(catalog, b, 0o644))
+
+	require.ErrorIs(t, generate("-strict"), ErrCatalogModified)
+	require.NoError(t, generate())
+	require.NoError(t, generate("-strict"))
+}
+
+func TestGenerateSalvage(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	generate := func(flags ...string) error {
+		t.Helper()
+		return run(context.Background(), append([]string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+		}, flags...))
+	}
+	require.NoError(t, os.MkdirAll(bundleDir, 0o755))
+	catalog := filepath.Join(bundleDir, "catalog.de.po")
+	broken := []byte(
+		"msgid \"\"\nmsgstr \"\"\n" +
+			"\"Language: de\\n\"\n" +
+			"\"MIME-Version: 1.0\\n\"\n" +
+			"\"Content-Type: text/plain; charset=UTF-8\\n\"\n" +
+			"\"Content-Transfer-Encoding: 8bit\\n\"\n" +
+			"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n\n" +
+			"msgid \"Broken\n" +
+			"msgstr \"Kaputt\"\n",
+	)
+	require.NoError(t, os.WriteFile(catalog, broken, 0o644))
+	require.ErrorContains(t, generate(), "catalog.de.po:9:7")
+
+	require.NoError(t, generate("-salvage"))
+	// Catalogs with skipped entries are kept as is.
+	b, err := os.ReadFile(catalog)
+	require.NoError(t, err)
+	require.Equal(t, string(broken), string(b))
+}
+
+func TestGenerateTemplateDir(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	templateDir := t.TempDir()
+	generate := func() error {
+		t.Helper()
+		return run(context.Background(), []string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+			"-template-dir", templateDir,
+		})
+	}
+	tmpl := filepath.Join(templateDir, "reader.gotmpl")
+	require.NoError(t, os.WriteFile(tmpl, []byte(
+		`{{ define "readr" }}{{ end }}`), 0o644))
+	require.ErrorIs(t, generate(), gengo.ErrTemplateUnused)
+	// Nothing is written for invalid templates.
+	require.NoDirExists(t, bundleDir)
+
+	require.NoError(t, os.WriteFile(tmpl, []byte(`{{ define "reader" }}
+func (r {{ .TypeName.Exported }}) Custom() {}
+{{ end }}`), 0o644))
+	require.NoError(t, generate())
+	b, err := os.ReadFile(goBundleFile(bundleDir))
+	require.NoError(t, err)
+	require.Contains(t, string(b), "func (r CatalogEn) Custom() {}")
+}
+
+func TestSmoke(t *testing.T) {
+	bundleDir := filepath.Join("internal", "localizebundle")
+	err := run(context.Background(), []string{
+		"localize", "-q", "smoke", "-b", bundleDir, "-locale", "de", "-n", "0",
+	})
+	require.NoError(t, err)
+	// The harness is removed.
+	entries, err := os.ReadDir(bundleDir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		require.False(t, e.IsDir(), e.Name())
+	}
+
+	err = run(context.Background(), []string{
+		"localize", "-q", "smoke", "-b", bundleDir, "-locale", "fr",
+	})
+	require.ErrorContains(t, err, `no catalog for locale "fr"`)
+}
+
+func TestBadgeNoSourceCatalog(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	require.NoError(t, os.MkdirAll(bundleDir, 0o755))
+	err := run(context.Background(), []string{
+		"localize", "-q", "badge", "-b", bundleDir, "-locale", "de",
+	})
+	require.ErrorIs(t, err, ErrNoSourceCatalog)
+}
+
+func TestGenerateSplitFiles(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	require.NoError(t, os.MkdirAll(bundleDir, 0o755))
+	generate := func(flags ...string) {
+		t.Helper()
+		require.NoError(t, run(context.Background(), append([]string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+		}, flags...)))
+	}
+	catalogDE := filepath.Join(bundleDir, "catalog.de.po")
+	require.NoError(t, os.WriteFile(catalogDE, []byte(
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"Language: de\\n\"\n"+
+			"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n",
+	), 0o644))
+	// Hand-written files matching the names of locale files are kept.
+	handWritten := filepath.Join(bundleDir, "localizebundle_custom_gen.go")
+	require.NoError(t, os.WriteFile(handWritten, []byte("package localizebundle\n"), 0o644))
+
+	generate("-split-files")
+	fileDE := goBundleLocaleFile(bundleDir, language.German)
+	fileEN := goBundleLocaleFile(bundleDir, language.English)
+	require.Equal(t, filepath.Join(bundleDir, "localizebundle_de_gen.go"), fileDE)
+	files, err := goBundleFiles(bundleDir)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{goBundleFile(bundleDir), fileDE, fileEN}, files)
+	core, err := os.ReadFile(goBundleFile(bundleDir))
+	require.NoError(t, err)
+	require.Contains(t, string(core), "func New(")
+	require.NotContains(t, string(core), "type CatalogDe struct")
+	de, err := os.ReadFile(fileDE)
+	require.NoError(t, err)
+	_, ok := gengo.ContentHash(de)
+	require.True(t, ok)
+	require.Contains(t, string(de), "type CatalogDe struct")
+
+	// Files of removed locales are removed.
+	require.NoError(t, os.Remove(catalogDE))
+	generate("-split-files")
+	require.NoFileExists(t, fileDE)
+	require.FileExists(t, fileEN)
+
+	// Locale files are removed when the bundle is no longer split.
+	generate()
+	require.NoFileExists(t, fileEN)
+	require.FileExists(t, handWritten)
+	core, err = os.ReadFile(goBundleFile(bundleDir))
+	require.NoError(t, err)
+	require.Contains(t, string(core), "type CatalogEn struct")
+}
+
+func TestGenerateRemovedDomain(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	require.NoError(t, os.MkdirAll(bundleDir, 0o755))
+	generate := func(flags ...string) {
+		t.Helper()
+		require.NoError(t, run(context.Background(), append([]string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+		}, flags...)))
+	}
+	catalogDE := filepath.Join(bundleDir, "catalog.de.po")
+	require.NoError(t, os.WriteFile(catalogDE, []byte(
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"Language: de\\n\"\n"+
+			"\"MIME-Version: 1.0\\n\"\n"+
+			"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+			"\"Content-Transfer-Encoding: 8bit\\n\"\n"+
+			"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n",
+	), 0o644))
+	msgctxts := func(fileName string) (hashes []string) {
+		t.Helper()
+		b, err := os.ReadFile(fileName)
+		require.NoError(t, err)
+		po, err := gettext.NewDecoder().DecodePOBytes(fileName, b)
+		require.NoError(t, err)
+		for _, m := range po.Messages.List {
+			require.False(t, m.Obsolete)
+			hashes = append(hashes, m.Msgctxt.Text.String())
+		}
+		return hashes
+	}
+
+	generate("-domain", "cli=main.go")
+	templateCLI := filepath.Join(bundleDir, "catalog.cli.pot")
+	catalogCLI := filepath.Join(bundleDir, "catalog.cli.de.po")
+	require.FileExists(t, templateCLI)
+	require.FileExists(t, catalogCLI)
+	inCLI := msgctxts(catalogCLI)
+	require.NotEmpty(t, inCLI)
+	require.Empty(t, msgctxts(catalogDE))
+
+	// The files of removed domains are removed
+	// and their messages moved to the catalog of the default domain.
+	generate()
+	require.NoFileExists(t, templateCLI)
+	require.NoFileExists(t, catalogCLI)
+	require.FileExists(t, filepath.Join(bundleDir, "catalog.pot"))
+	require.ElementsMatch(t, inCLI, msgctxts(catalogDE))
+}
+
+func TestGenerateHashMigration(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	require.NoError(t, os.MkdirAll(bundleDir, 0o755))
+	generate := func(flags ...string) {
+		t.Helper()
+		require.NoError(t, run(context.Background(), append([]string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+			"-message-ids",
+		}, flags...)))
+	}
+	decode := func(path string) gettext.FilePO {
+		t.Helper()
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		po, err := gettext.NewDecoder().DecodePOBytes(path, b)
+		require.NoError(t, err)
+		return po
+	}
+	catalogPath := filepath.Join(bundleDir, "catalog.de.po")
+	require.NoError(t, os.WriteFile(catalogPath, []byte(
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"Language: de\\n\"\n"+
+			"\"MIME-Version: 1.0\\n\"\n"+
+			"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+			"\"Content-Transfer-Encoding: 8bit\\n\"\n"+
+			"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n",
+	), 0o644))
+	generate()
+
+	// Translate the first static message.
+	catalog := decode(catalogPath)
+	i := slices.IndexFunc(catalog.Messages.List, func(m gettext.Message) bool {
+		return len(m.MsgidPlural.Text.Lines) == 0
+	})
+	require.GreaterOrEqual(t, i, 0)
+	m := &catalog.Messages.List[i]
+	m.Msgstr.Text = gettext.StringLiterals{
+		Lines: []gettext.StringLiteral{{Value: "Übersetzt"}},
+	}
+	oldHash := m.Msgctxt.Text.String()
+	var buf bytes.Buffer
+	require.NoError(t, gettext.Encoder{}.EncodePO(catalog, &buf))
+	require.NoError(t, os.WriteFile(catalogPath, buf.Bytes(), 0o644))
+	ids, err := msglock.Load(filepath.Join(bundleDir, msglock.FileName))
+	require.NoError(t, err)
+	id, ok := ids.ID(oldHash)
+	require.True(t, ok)
+
+	generate("-hash", "fnv128a")
+	catalog = decode(catalogPath)
+	h, err := codeparser.HashFuncOf(catalog.Head)
+	require.NoError(t, err)
+	require.Equal(t, codeparser.HashFNV128a, h)
+	for _, m := range catalog.Messages.List {
+		require.False(t, m.Obsolete, m.Msgctxt.Text.String())
+		require.Len(t, m.Msgctxt.Text.String(), 32)
+	}
+	j := slices.IndexFunc(catalog.Messages.List, func(c gettext.Message) bool {
+		return c.Msgid.Text.String() == m.Msgid.Text.String()
+	})
+	require.GreaterOrEqual(t, j, 0)
+	newHash := catalog.Messages.List[j].Msgctxt.Text.String()
+	require.Equal(t, "Übersetzt", catalog.Messages.List[j].Msgstr.Text.String())
+
+	// Message IDs are kept.
+	ids, err = msglock.Load(filepath.Join(bundleDir, msglock.FileName))
+	require.NoError(t, err)
+	newID, ok := ids.ID(newHash)
+	require.True(t, ok)
+	require.Equal(t, id, newID)
+
+	source := decode(filepath.Join(bundleDir, "source.en.po"))
+	h, err = codeparser.HashFuncOf(source.Head)
+	require.NoError(t, err)
+	require.Equal(t, codeparser.HashFNV128a, h)
+}
+
+func TestReleaseCheck(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	bundleDir := filepath.Join(dir, "localizebundle")
+	require.NoError(t, os.MkdirAll(bundleDir, 0o755))
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=a", "GIT_AUTHOR_EMAIL=a@example.com",
+			"GIT_COMMITTER_NAME=a", "GIT_COMMITTER_EMAIL=a@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, locale string, messages ...string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(bundleDir, name), []byte(
+			"msgid \"\"\nmsgstr \"\"\n"+
+				"\"Language: "+locale+"\\n\"\n"+
+				"\"MIME-Version: 1.0\\n\"\n"+
+				"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+				"\"Content-Transfer-Encoding: 8bit\\n\"\n"+
+				"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n"+
+				strings.Join(messages, ""),
+		), 0o644))
+	}
+	releaseCheck := func(flags ...string) error {
+		t.Helper()
+		return run(context.Background(), append([]string{
+			"localize", "-q", "release-check", "-b", bundleDir,
+		}, flags...))
+	}
+
+	write("source.en.po", "en", "\nmsgctxt \"a\"\nmsgid \"A\"\nmsgstr \"A\"\n")
+	write("catalog.de.po", "de", "\nmsgctxt \"a\"\nmsgid \"A\"\nmsgstr \"\"\n")
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "release")
+	git("tag", "v1.0.0")
+
+	// Untranslated messages of the previous release don't fail the check.
+	require.NoError(t, releaseCheck("-since", "v1.0.0"))
+
+	write("source.en.po", "en",
+		"\nmsgctxt \"a\"\nmsgid \"A\"\nmsgstr \"A\"\n",
+		"\nmsgctxt \"b\"\nmsgid \"B\"\nmsgstr \"B\"\n")
+	require.ErrorIs(t, releaseCheck("-since", "v1.0.0"), ErrUntranslated)
+	require.NoError(t, releaseCheck("-since", "v1.0.0", "-locales", "en"))
+	require.ErrorContains(t, releaseCheck("-since", "v1.0.0", "-locales", "fr"),
+		`bundle has no catalog for locale "fr"`)
+	require.Error(t, releaseCheck("-since", "v9.9.9"))
+
+	write("catalog.de.po", "de",
+		"\nmsgctxt \"a\"\nmsgid \"A\"\nmsgstr \"\"\n",
+		"\nmsgctxt \"b\"\nmsgid \"B\"\nmsgstr \"B übersetzt\"\n")
+	require.NoError(t, releaseCheck("-since", "v1.0.0"))
+}
+
+func TestGeneratePostGenerate(t *testing.T) {
+	if _, err := exec.LookPath("tee"); err != nil {
+		t.Skip("tee not available")
+	}
+	dir := t.TempDir()
+	bundleDir := filepath.Join(dir, "localizebundle")
+	hookOutput := filepath.Join(dir, "hook.json")
+	err := run(context.Background(), []string{
+		"localize", "generate", "-b", bundleDir,
+		"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+		"-post-generate", "tee " + hookOutput,
+	})
+	require.NoError(t, err)
+	b, err := os.ReadFile(hookOutput)
+	require.NoError(t, err)
+	var s summary.Summary
+	require.NoError(t, json.Unmarshal(b, &s))
+	require.Positive(t, s.Messages)
+	require.Contains(t, s.Files, filepath.ToSlash(filepath.Join(bundleDir, "catalog.pot")))
+
+	err = run(context.Background(), []string{
+		"localize", "generate", "-b", bundleDir,
+		"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+		"-post-generate", "false",
+		"-post-generate", "tee " + hookOutput + ".2",
+	})
+	require.ErrorIs(t, err, ErrHookFailed)
+	require.NoFileExists(t, hookOutput+".2")
+}
+
+func TestGenerateAuxiliaryEntries(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	generate := func() {
+		t.Helper()
+		err := run(context.Background(), []string{
+			"localize", "generate", "-b", bundleDir,
+			"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+		})
+		require.NoError(t, err)
+	}
+	generate()
+
+	catalogPath := filepath.Join(bundleDir, "catalog.de.po")
+	err := os.WriteFile(catalogPath, []byte(
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"Language: de\\n\"\n"+
+			"\"MIME-Version: 1.0\\n\"\n"+
+			"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+			"\"Content-Transfer-Encoding: 8bit\\n\"\n"+
+			"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n"+
+			"\n"+
+			"msgctxt \"grammar:contraction\"\nmsgid \"zu dem\"\nmsgstr \"zum\"\n\n"+
+			"msgctxt \"register:informal\"\nmsgid \"ERR:\"\nmsgstr \"FEHLER (du):\"\n",
+	), 0o644)
+	require.NoError(t, err)
+	generate()
+
+	// Grammar entries and register variants aren't used in source code
+	// but must not become obsolete.
+	b, err := os.ReadFile(catalogPath)
+	require.NoError(t, err)
+	po, err := gettext.NewDecoder().DecodePOBytes(catalogPath, b)
+	require.NoError(t, err)
+	found := map[string]string{}
+	for _, m := range po.Messages.List {
+		switch ctx := m.Msgctxt.Text.String(); ctx {
+		case "grammar:contraction", "register:informal":
+			require.False(t, m.Obsolete)
+			found[ctx] = m.Msgstr.Text.String()
+		}
+	}
+	require.Equal(t, map[string]string{
+		"grammar:contraction": "zum",
+		"register:informal":   "FEHLER (du):",
+	}, found)
+
+	gen, err := os.ReadFile(filepath.Join(bundleDir, "localizebundle_gen.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(gen), `"grammar:contraction\x04zu dem": "zum",`)
+	require.Regexp(t, `localize.RegisterInformal: \{\s*"ERR:": "FEHLER \(du\):",`,
+		string(gen))
+}
+
+func TestExportImportUntranslated(t *testing.T) {
+	bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+	require.NoError(t, os.MkdirAll(bundleDir, 0o755))
+	catalogPath := filepath.Join(bundleDir, "catalog.de.po")
+	err := os.WriteFile(catalogPath, []byte(
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"Language: de\\n\"\n"+
+			"\"MIME-Version: 1.0\\n\"\n"+
+			"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+			"\"Content-Transfer-Encoding: 8bit\\n\"\n"+
+			"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n",
+	), 0o644)
+	require.NoError(t, err)
+	err = run(context.Background(), []string{
+		"localize", "generate", "-b", bundleDir,
+		"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+	})
+	require.NoError(t, err)
+
+	decode := func(path string) gettext.FilePO {
+		t.Helper()
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		po, err := gettext.NewDecoder().DecodePOBytes(path, b)
+		require.NoError(t, err)
+		return po
+	}
+
+	todoPath := filepath.Join(t.TempDir(), "todo.de.po")
+	err = run(context.Background(), []string{
+		"localize", "export-untranslated", "-b", bundleDir,
+		"-locale", "de", "-o", todoPath, "-q",
+	})
+	require.NoError(t, err)
+	todo := decode(todoPath)
+	require.Equal(t, len(decode(catalogPath).Messages.List), len(todo.Messages.List))
+
+	// Translate the first static message.
+	i := slices.IndexFunc(todo.Messages.List, func(m gettext.Message) bool {
+		return len(m.MsgidPlural.Text.Lines) == 0
+	})
+	require.GreaterOrEqual(t, i, 0)
+	m := &todo.Messages.List[i]
+	m.Msgstr.Text = gettext.StringLiterals{
+		Lines: []gettext.StringLiteral{{Value: "Übersetzt"}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gettext.Encoder{}.EncodePO(todo, &buf))
+	require.NoError(t, os.WriteFile(todoPath, buf.Bytes(), 0o644))
+
+	err = run(context.Background(), []string{
+		"localize", "import-untranslated", "-b", bundleDir,
+		"-locale", "de", "-q", todoPath,
+	})
+	require.NoError(t, err)
+	catalog := decode(catalogPath)
+	j := slices.IndexFunc(catalog.Messages.List, func(c gettext.Message) bool {
+		return c.Msgctxt.Text.String() == m.Msgctxt.Text.String()
+	})
+	require.GreaterOrEqual(t, j, 0)
+	require.Equal(t, "Übersetzt", catalog.Messages.List[j].Msgstr.Text.String())
+
+	// The imported message isn't exported anymore.
+	err = run(context.Background(), []string{
+		"localize", "export-untranslated", "-b", bundleDir,
+		"-locale", "de", "-o", todoPath, "-q",
+	})
+	require.NoError(t, err)
+	require.Len(t, decode(todoPath).Messages.List, len(todo.Messages.List)-1)
+
+	err = run(context.Background(), []string{
+		"localize", "import-untranslated", "-b", bundleDir,
+		"-locale", "fr", "-q", todoPath,
+	})
+	require.ErrorContains(t, err, `no catalog for locale "fr"`)
+
+	err = run(context.Background(), []string{
+		"localize", "export-untranslated", "-b", bundleDir,
+		"-locale", "de", "-o", todoPath, "-tokenize", "-q",
+	})
+	require.NoError(t, err)
+	require.True(t, untranslated.IsTokenized(decode(todoPath)))
+}
+
+func TestGenerateResolve(t *testing.T) {
+	for _, tt := range []struct {
+		resolve    string
+		translated string
+		fuzzy      bool
+	}{
+		{"clear", "", false},
+		{"keep", "Fehler von früher", false},
+		{"fuzzy", "Fehler von früher", true},
+	} {
+		t.Run(tt.resolve, func(t *testing.T) {
+			bundleDir := filepath.Join(t.TempDir(), "localizebundle")
+			generate := func(flags ...string) {
+				t.Helper()
+				err := run(context.Background(), append([]string{
+					"localize", "generate", "-b", bundleDir,
+					"-import-path", "example.com/localizebundle", "-l", "en", "-q",
+				}, flags...))
+				require.NoError(t, err)
+			}
+			generate()
+
+			// A translated message at the code reference of a message
+			// whose source text changed since.
+			sourcePath := filepath.Join(bundleDir, "source.en.po")
+			b, err := os.ReadFile(sourcePath)
+			require.NoError(t, err)
+			source, err := gettext.NewDecoder().DecodePOBytes(sourcePath, b)
+			require.NoError(t, err)
+			i := slices.IndexFunc(source.Messages.List, func(m gettext.Message) bool {
+				return len(m.MsgidPlural.Text.Lines) < 1
+			})
+			changed := source.Messages.List[i]
+			var refs string
+			for _, c := range changed.Msgctxt.Comments.Text {
+				if c.Type == gettext.CommentTypeReference {
+					refs += "#: " + c.Value + "\n"
+				}
+			}
+			catalogPath := filepath.Join(bundleDir, "catalog.de.po")
+			require.NoError(t, os.WriteFile(catalogPath, []byte(
+				"msgid \"\"\nmsgstr \"\"\n"+
+					"\"Language: de\\n\"\n"+
+					"\"MIME-Version: 1.0\\n\"\n"+
+					"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+					"\"Content-Transfer-Encoding: 8bit\\n\"\n"+
+					"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n"+
+					"\n"+refs+
+					"msgctxt \"0000000000000000\"\nmsgid \"Error from before\"\n"+
+					"msgstr \"Fehler von früher\"\n",
+			), 0o644))
+			generate("-resolve", tt.resolve)
+
+			b, err = os.ReadFile(catalogPath)
+			require.NoError(t, err)
+			po, err := gettext.NewDecoder().DecodePOBytes(catalogPath, b)
+			require.NoError(t, err)
+			i = slices.IndexFunc(po.Messages.List, func(m gettext.Message) bool {
+				return m.Msgctxt.Text.String() == changed.Msgctxt.Text.String()
+			})
+			require.NotEqual(t, -1, i)
+			m := po.Messages.List[i]
+			require.Equal(t, tt.translated, m.Msgstr.Text.String())
+			require.Equal(t, tt.fuzzy, fuzzy.Is(&m))
+
+			// Fuzzy translations aren't compiled into the Go bundle.
+			generate()
+			gen, err := os.ReadFile(filepath.Join(bundleDir, "localizebundle_gen.go"))
+			require.NoError(t, err)
+			require.Equal(t, tt.resolve == "keep", regexp.MustCompile(
+				regexp.QuoteMeta(strconv.Quote(changed.Msgid.Text.String()))+
+					`:\s+"Fehler von früher"`,
+			).Match(gen))
+		})
+	}
+}
+
+func TestPromptResolution(t *testing.T) {
+	var previous, nm gettext.Message
+	previous.Msgid.Text.Lines = []gettext.StringLiteral{{Value: "Error"}}
+	previous.Msgstr.Text.Lines = []gettext.StringLiteral{{Value: "Fehler"}}
+	nm.Msgid.Text.Lines = []gettext.StringLiteral{{Value: "Error!"}}
+
+	var w bytes.Buffer
+	r, err := promptResolution(
+		bufio.NewReader(strings.NewReader("x\nF\n")), &w, "de", &previous, &nm,
+	)
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Fuzzy, r)
+	require.Equal(t, "de: source text of a translated message changed\n"+
+		"  - \"Error\"\n  + \"Error!\"\n  de: \"Fehler\"\n"+
+		"keep, fuzzy or clear? [k/f/c] keep, fuzzy or clear? [k/f/c] ", w.String())
+
+	r, err = promptResolution(
+		bufio.NewReader(strings.NewReader("keep")), io.Discard, "de", &previous, &nm,
+	)
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Keep, r)
+
+	_, err = promptResolution(
+		bufio.NewReader(strings.NewReader("")), io.Discard, "de", &previous, &nm,
+	)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestUpdateCommentsReferenceOrder(t *testing.T) {
+	var m gettext.Message
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeTranslator, Value: "translator"},
+		{Type: gettext.CommentTypeReference, Value: "b.go:1"},
+		{Type: gettext.CommentTypeReference, Value: "removed.go:1"},
+		{Type: gettext.CommentTypeReference, Value: "gone.go:2"},
+		{Type: gettext.CommentTypeReference, Value: "a.go:12"},
+	}
+	updateComments(&m, codeparser.MsgMeta{Pos: []token.Position{
+		{Filename: "a.go", Line: 3, Column: 2},
+		{Filename: "a.go", Line: 3, Column: 9},
+		{Filename: "a.go", Line: 12, Column: 2},
+		{Filename: "b.go", Line: 1, Column: 2},
+	}})
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeTranslator, Value: "translator"},
+		{Type: gettext.CommentTypeReference, Value: "a.go:3"},
+		{Type: gettext.CommentTypeReference, Value: "a.go:12"},
+		{Type: gettext.CommentTypeReference, Value: "b.go:1"},
+	}, m.Msgctxt.Comments.Text)
+}
+
+func TestUpdateCommentsPreservesTranslatorComments(t *testing.T) {
+	var m gettext.Message
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeTranslator, Value: " first"},
+		{Type: gettext.CommentTypeTranslator, Value: ""},
+		{Type: gettext.CommentTypeExtracted, Value: "Greeting"},
+		{Type: gettext.CommentTypeReference, Value: "a.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy,go-format"},
+		{Type: gettext.CommentTypeFlag, Value: "no-wrap, edition:cloud"},
+		{Type: gettext.CommentTypeTranslator, Value: "after the flags"},
+	}
+	m.Msgstr.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeTranslator, Value: "on msgstr"},
+	}
+	meta := codeparser.MsgMeta{
+		Pos:      []token.Position{{Filename: "b.go", Line: 2, Column: 2}},
+		Editions: []string{"enterprise"},
+		Heading:  true,
+	}
+	expect := []gettext.Comment{
+		{Type: gettext.CommentTypeTranslator, Value: " first"},
+		{Type: gettext.CommentTypeTranslator, Value: ""},
+		{Type: gettext.CommentTypeTranslator, Value: "after the flags"},
+		{Type: gettext.CommentTypeExtracted, Value: "Greeting"},
+		{Type: gettext.CommentTypeReference, Value: "b.go:2"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy,go-format"},
+		{Type: gettext.CommentTypeFlag, Value: "no-wrap"},
+		{Type: gettext.CommentTypeFlag, Value: "edition:enterprise"},
+		{Type: gettext.CommentTypeFlag, Value: "heading"},
+	}
+	updateComments(&m, meta)
+	require.Equal(t, expect, m.Msgctxt.Comments.Text)
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeTranslator, Value: "on msgstr"},
+	}, m.Msgstr.Comments.Text)
+
+	// Repeated runs don't change the comments.
+	before := slices.Clone(m.Msgctxt.Comments.Text)
+	updateComments(&m, meta)
+	require.Equal(t, expect, m.Msgctxt.Comments.Text)
+	require.False(t, commentsChanged(before, m.Msgctxt.Comments.Text))
+}
+
+func TestConfigSchemaUpToDate(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, clidoc.WriteSchema(&buf, configSchemaTitle, config.Commands))
+	shipped, err := os.ReadFile("../../localize.schema.json")
+	require.NoError(t, err)
+	require.Equal(t, buf.String(), string(shipped),
+		"localize.schema.json is outdated, run go generate")
+}
+
+func TestWriteProfile(t *testing.T) {
+	console = consoleReader(language.English)
+	pkgs := []codeparser.PackageProfile{
+		{
+			PkgPath: "example/slow", Files: 12,
+			Parse: 3 * time.Millisecond, TypeCheck: 40 * time.Millisecond,
+			Inspect: 1500 * time.Microsecond,
+		},
+		{PkgPath: "fmt", Files: 9, Parse: 2 * time.Millisecond, TypeCheck: time.Millisecond},
+		{PkgPath: "errors", Files: 3, Parse: 100 * time.Microsecond},
+	}
+	var buf bytes.Buffer
+	writeProfile(&buf, 1234*time.Millisecond, pkgs, 2)
+	require.Equal(t, "Time by package (loading total 1.234s):\n"+
+		"   total  parse  type-check  inspect  files  package\n"+
+		"  44.5ms    3ms        40ms    1.5ms     12  example/slow\n"+
+		"     3ms    2ms         1ms       0s      9  fmt\n"+
+		"1 more package\n", buf.String())
+
+	buf.Reset()
+	writeProfile(&buf, time.Second, pkgs, 0)
+	require.Contains(t, buf.String(), "   100µs  100µs          0s       0s      3  errors\n")
+	require.NotContains(t, buf.String(), "more package")
+}
+
+func TestGettextUtilities(t *testing.T) {
+	// No Go module is required.
+	dir := t.TempDir()
+	t.Chdir(dir)
+	const head = `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+`
+	require.NoError(t, os.WriteFile("messages.pot", []byte(head+`
+#: app.py:1
+msgid "Save"
+msgstr ""
+
+#: app.py:2
+msgid "Open"
+msgstr ""
+`), 0o644))
+	require.NoError(t, os.WriteFile("de.po", []byte(`msgid ""
+msgstr ""
+"Language: de\n"
+`+strings.TrimPrefix(head, "msgid \"\"\nmsgstr \"\"\n")+`
+msgid   "Save"
+msgstr "Speichern"
+`), 0o644))
+
+	ctx := context.Background()
+	require.NoError(t, run(ctx, []string{"localize", "-q", "check", "de.po", "messages.pot"}))
+
+	require.NoError(t, run(ctx, []string{"localize", "fmt", "-w", "de.po"}))
+	b, err := os.ReadFile("de.po")
+	require.NoError(t, err)
+	require.Contains(t, string(b), "\nmsgid \"Save\"\n")
+
+	require.NoError(t, run(ctx, []string{
+		"localize", "-q", "merge", "-o", "de.po", "de.po", "messages.pot",
+	}))
+	f, _, err := decodeGettextFile("de.po")
+	require.NoError(t, err)
+	require.Len(t, f.Messages.List, 2)
+	require.Equal(t, "Speichern", f.Messages.List[0].Msgstr.Text.String())
+	require.Equal(t, "Open", f.Messages.List[1].Msgid.Text.String())
+
+	// Translations of plural messages require all plural forms.
+	require.NoError(t, os.WriteFile("fr.po", []byte(`msgid ""
+msgstr ""
+"Language: fr\n"
+`+strings.TrimPrefix(head, "msgid \"\"\nmsgstr \"\"\n")+`
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d fichier"
+`), 0o644))
+	err = run(ctx, []string{"localize", "-q", "check", "de.po", "fr.po"})
+	require.ErrorIs(t, err, ErrCatalogIssues)
+}
+
+// TestEndToEnd generates the bundle of a temporary module, translates
+// its catalogs, regenerates the bundle, builds the module using the Go
+// toolchain and runs it asserting the localized output of every locale.
+func TestEndToEnd(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a module using the Go toolchain")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+	repoRoot, err := filepath.Abs(filepath.Join("..", ".."))
+	require.NoError(t, err)
+	goSum, err := os.ReadFile(filepath.Join(repoRoot, "go.sum"))
+	require.NoError(t, err)
+
+	pluralForms := func(locale language.Tag) string {
+		p, ok := cldr.ByTagOrBase(locale)
+		require.True(t, ok)
+		return fmt.Sprintf("nplurals=%d; plural=%s;",
+			len(p.CardinalForms), p.GettextFormula)
+	}
+	catalog := func(locale language.Tag) string {
+		return `msgid ""
+msgstr ""
+"Language: ` + locale.String() + `\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: ` + pluralForms(locale) + `\n"
+`
+	}
+	dir := CreateSetup(t, map[string]string{
+		"go.mod": `module example.com/e2e
+
+go 1.24.1
+
+require github.com/romshark/localize v0.0.0-00010101000000-000000000000
+
+replace github.com/romshark/localize => ` + filepath.ToSlash(repoRoot) + `
+`,
+		"go.sum": string(goSum),
+		// main.go doesn't compile before the first run of generate,
+		// such that the texts are in a separate package.
+		"main.go": `package main
+
+import (
+	"os"
+
+	"example.com/e2e/localizebundle"
+	"example.com/e2e/texts"
+
+	"golang.org/x/text/language"
+)
+
+func main() {
+	bundle, err := localizebundle.New()
+	if err != nil {
+		panic(err)
+	}
+	for _, arg := range os.Args[1:] {
+		l, _ := bundle.MustMatch(language.MustParse(arg))
+		texts.Print(os.Stdout, l)
+	}
+}
+`,
+		"texts/texts.go": `package texts
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/romshark/localize"
+)
+
+func Print(w io.Writer, l localize.Reader) {
+	// Greeting.
+	fmt.Fprintln(w, l.Text("Hello, world!"))
+
+	for _, n := range []int{1, 3, 5} {
+		// Number of new messages in the inbox.
+		fmt.Fprintln(w, l.Plural(localize.Forms{
+			One:   "%d new message",
+			Other: "%d new messages",
+		}, n))
+	}
+}
+`,
+		"localizebundle/doc.go":        "package localizebundle\n",
+		"localizebundle/catalog.de.po": catalog(language.German),
+		"localizebundle/catalog.uk.po": catalog(language.Ukrainian),
+	})
+	t.Chdir(dir)
+	goCmd := func(args ...string) []byte {
+		t.Helper()
+		cmd := exec.Command("go", args...)
+		cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "go %s: %s", strings.Join(args, " "), out)
+		return out
+	}
+	goCmd("mod", "tidy")
+
+	generate := func() {
+		t.Helper()
+		require.NoError(t, run(context.Background(), []string{
+			"localize", "-q", "generate", "-l", "en", "-b", "localizebundle",
+		}))
+	}
+	generate()
+
+	// Translate the catalogs written by generate.
+	translations := map[string]map[string][]string{
+		"de": {
+			"Hello, world!":  {"Hallo, Welt!"},
+			"%d new message": {"%d neue Nachricht", "%d neue Nachrichten"},
+		},
+		"uk": {
+			"Hello, world!": {"Привіт, світе!"},
+			"%d new message": {
+				"%d нове повідомлення", "%d нові повідомлення",
+				"%d нових повідомлень",
+			},
+		},
+	}
+	for locale, texts := range translations {
+		path := filepath.Join("localizebundle", "catalog."+locale+".po")
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		po, err := gettext.NewDecoder().DecodePOBytes(path, b)
+		require.NoError(t, err)
+		require.Len(t, po.Messages.List, len(texts))
+		for i := range po.Messages.List {
+			m := &po.Messages.List[i]
+			forms, ok := texts[m.Msgid.Text.String()]
+			require.True(t, ok, m.Msgid.Text.String())
+			msgstrs := []*gettext.Msgstr{&m.Msgstr}
+			if len(m.MsgidPlural.Text.Lines) > 0 {
+				msgstrs = []*gettext.Msgstr{&m.Msgstr0, &m.Msgstr1, &m.Msgstr2}
+			}
+			for j, f := range forms {
+				msgstrs[j].Text = gettext.StringLiterals{
+					Lines: []gettext.StringLiteral{{Value: f}},
+				}
+			}
+		}
+		s, err := gettext.Encoder{}.EncodePOToString(po)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, []byte(s), 0o644))
+	}
+	generate()
+
+	exe := filepath.Join(t.TempDir(), "e2e")
+	goCmd("build", "-o", exe, ".")
+	out, err := exec.Command(exe, "en", "de-AT", "uk", "ja").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(t, `Hello, world!
+1 new message
+3 new messages
+5 new messages
+Hallo, Welt!
+1 neue Nachricht
+3 neue Nachrichten
+5 neue Nachrichten
+Привіт, світе!
+1 нове повідомлення
+3 нові повідомлення
+5 нових повідомлень
+Hello, world!
+1 new message
+3 new messages
+5 new messages
+`, string(out))
+}
@@ -0,0 +1,165 @@
+package localize
+
+import (
+	"math"
+	"strings"
+
+	"github.com/go-playground/locales"
+	"golang.org/x/text/language"
+)
+
+// compactUnit is a CLDR short compact decimal pattern used for numbers
+// of at least 10^min, which are divided by 10^div and substituted for
+// the "0" of pattern.
+type compactUnit struct {
+	min, div int
+	pattern  string
+}
+
+// compactUnits maps locales to their CLDR short compact decimal patterns
+// in ascending order. Like in CLDR, numbers are separated from units by
+// no-break spaces. Locales are matched by base language and script first
+// and by base language second, all others use the patterns of "root".
+var compactUnits = map[string][]compactUnit{
+	"root": {{3, 3, "0K"}, {6, 6, "0M"}, {9, 9, "0G"}, {12, 12, "0T"}},
+	"de": {
+		{3, 3, "0\u00a0Tsd."},
+		{6, 6, "0\u00a0Mio."},
+		{9, 9, "0\u00a0Mrd."},
+		{12, 12, "0\u00a0Bio."},
+	},
+	"en": {{3, 3, "0K"}, {6, 6, "0M"}, {9, 9, "0B"}, {12, 12, "0T"}},
+	"es": {
+		{3, 3, "0\u00a0mil"},
+		{6, 6, "0\u00a0M"},
+		{10, 9, "0\u00a0mil\u00a0M"},
+		{12, 12, "0\u00a0B"},
+	},
+	"fr": {
+		{3, 3, "0\u00a0k"},
+		{6, 6, "0\u00a0M"},
+		{9, 9, "0\u00a0Md"},
+		{12, 12, "0\u00a0Bn"},
+	},
+	"hi": {
+		{3, 3, "0\u00a0हज़ार"},
+		{5, 5, "0\u00a0लाख"},
+		{7, 7, "0\u00a0क॰"},
+		{9, 9, "0\u00a0अ॰"},
+		{11, 11, "0\u00a0ख॰"},
+	},
+	"it": {{6, 6, "0\u00a0Mln"}, {9, 9, "0\u00a0Mrd"}, {12, 12, "0\u00a0Bln"}},
+	"ja": {{4, 4, "0万"}, {8, 8, "0億"}, {12, 12, "0兆"}},
+	"ko": {{3, 3, "0천"}, {4, 4, "0만"}, {8, 8, "0억"}, {12, 12, "0조"}},
+	"nl": {
+		{3, 3, "0K"},
+		{6, 6, "0\u00a0mln."},
+		{9, 9, "0\u00a0mld."},
+		{12, 12, "0\u00a0bln."},
+	},
+	"pl": {
+		{3, 3, "0\u00a0tys."},
+		{6, 6, "0\u00a0mln"},
+		{9, 9, "0\u00a0mld"},
+		{12, 12, "0\u00a0bln"},
+	},
+	"pt": {
+		{3, 3, "0\u00a0mil"},
+		{6, 6, "0\u00a0mi"},
+		{9, 9, "0\u00a0bi"},
+		{12, 12, "0\u00a0tri"},
+	},
+	"ru": {
+		{3, 3, "0\u00a0тыс."},
+		{6, 6, "0\u00a0млн"},
+		{9, 9, "0\u00a0млрд"},
+		{12, 12, "0\u00a0трлн"},
+	},
+	"sv": {
+		{3, 3, "0\u00a0tn"},
+		{6, 6, "0\u00a0mn"},
+		{9, 9, "0\u00a0md"},
+		{12, 12, "0\u00a0bn"},
+	},
+	"tr": {
+		{3, 3, "0\u00a0B"},
+		{6, 6, "0\u00a0Mn"},
+		{9, 9, "0\u00a0Mr"},
+		{12, 12, "0\u00a0Tn"},
+	},
+	"uk": {
+		{3, 3, "0\u00a0тис."},
+		{6, 6, "0\u00a0млн"},
+		{9, 9, "0\u00a0млрд"},
+		{12, 12, "0\u00a0трлн"},
+	},
+	"zh":      {{4, 4, "0万"}, {8, 8, "0亿"}, {12, 12, "0万亿"}},
+	"zh-Hant": {{4, 4, "0萬"}, {8, 8, "0億"}, {12, 12, "0兆"}},
+}
+
+func compactUnitsOf(locale language.Tag) []compactUnit {
+	base, _ := locale.Base()
+	if script, c := locale.Script(); c != language.No {
+		if u, ok := compactUnits[base.String()+"-"+script.String()]; ok {
+			return u
+		}
+	}
+	if u, ok := compactUnits[base.String()]; ok {
+		return u
+	}
+	return compactUnits["root"]
+}
+
+// FormatCompact formats n in the CLDR short compact decimal notation of
+// locale using the decimal and grouping separators of t, such as "1.2K"
+// for "en", "1,2 Tsd." for "de" and "1.2万" for "zh". Like in CLDR, numbers
+// are rounded to two significant digits if less than 100 after division
+// and to integers otherwise, such that 1234 becomes "1.2K" and 123456
+// becomes "123K". Units are separated by no-break spaces where CLDR
+// separates them. Numbers below the smallest compact unit of locale,
+// such as 999 for "en", are rounded the same but not divided.
+func FormatCompact(locale language.Tag, t locales.Translator, n float64) string {
+	units := compactUnitsOf(locale)
+	abs := math.Abs(n)
+	i := -1 // Index of the unit, -1 for numbers below the smallest unit.
+	for i+1 < len(units) && abs >= math.Pow10(units[i+1].min) {
+		i++
+	}
+	var scaled float64
+	var v uint64
+	for {
+		div := 0
+		if i >= 0 {
+			div = units[i].div
+		}
+		scaled, v = roundCompact(abs / math.Pow10(div))
+		// Rounding may reach the next unit, like 999999 rounding to "1M"
+		// instead of "1000K".
+		if i+1 == len(units) || scaled*math.Pow10(div) < math.Pow10(units[i+1].min) {
+			break
+		}
+		i++
+	}
+	if n < 0 && scaled != 0 {
+		scaled = -scaled
+	}
+	s := t.FmtNumber(scaled, v)
+	if i < 0 {
+		return s
+	}
+	return strings.Replace(units[i].pattern, "0", s, 1)
+}
+
+// roundCompact rounds f to two significant digits if less than 100
+// and to an integer otherwise and returns the number of its visible
+// fraction digits.
+func roundCompact(f float64) (rounded float64, v uint64) {
+	if f >= 10 {
+		return math.Round(f), 0
+	}
+	rounded = math.Round(f*10) / 10
+	if rounded != math.Trunc(rounded) {
+		v = 1
+	}
+	return rounded, v
+}
@@ -0,0 +1,54 @@
+package localize_test
+
+import (
+	"testing"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/it"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/zh"
+	"github.com/go-playground/locales/zh_Hant"
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestFormatCompact(t *testing.T) {
+	for _, tt := range []struct {
+		locale string
+		tr     locales.Translator
+		n      float64
+		expect string
+	}{
+		{"en", en.New(), 0, "0"},
+		{"en", en.New(), 999, "999"},
+		{"en", en.New(), 9.96, "10"},
+		{"en", en.New(), 1.24, "1.2"},
+		{"en", en.New(), 1000, "1K"},
+		{"en", en.New(), 1234, "1.2K"},
+		{"en", en.New(), -1234, "-1.2K"},
+		{"en", en.New(), 12_345, "12K"},
+		{"en", en.New(), 123_456, "123K"},
+		{"en", en.New(), 999_999, "1M"},
+		{"en", en.New(), 1_500_000_000, "1.5B"},
+		{"en", en.New(), 1.2e15, "1,200T"},
+		{"de", de.New(), 1234, "1,2\u00a0Tsd."},
+		{"de", de.New(), 2_500_000, "2,5\u00a0Mio."},
+		{"es", es.New(), 1_200_000_000, "1.200\u00a0M"},
+		{"es", es.New(), 12_000_000_000, "12\u00a0mil\u00a0M"},
+		{"it", it.New(), 12_345, "12.345"},
+		{"ja", ja.New(), 12_000, "1.2万"},
+		{"zh", zh.New(), 120_000_000, "1.2亿"},
+		{"zh-TW", zh_Hant.New(), 12_000, "1.2萬"},
+		{"sw", en.New(), 2_000_000_000, "2G"},
+	} {
+		t.Run(tt.locale, func(t *testing.T) {
+			require.Equal(t, tt.expect, localize.FormatCompact(
+				language.MustParse(tt.locale), tt.tr, tt.n,
+			))
+		})
+	}
+}
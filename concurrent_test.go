@@ -0,0 +1,83 @@
+package localize_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// lazyTranslatorReader counts the calls to Translator.
+type lazyTranslatorReader struct {
+	MockReader
+	calls *atomic.Int32
+}
+
+func (r lazyTranslatorReader) Translator() locales.Translator {
+	r.calls.Add(1)
+	return de.New()
+}
+
+func TestBundleConcurrentReads(t *testing.T) {
+	b, err := localize.New(language.English,
+		MockReader{tag: language.English, static: map[string]string{"Hi": "Hi"}},
+		MockReader{tag: language.German, static: map[string]string{"Hi": "Hallo"}},
+		MockReader{tag: language.Russian, static: map[string]string{"Hi": "Привет"}},
+	)
+	require.NoError(t, err)
+
+	baseGerman, _ := language.German.Base()
+	read := func() string {
+		m, _ := b.Match(language.MustParse("de-AT"), language.English)
+		mm, _ := b.MustMatch(language.Japanese)
+		return m.Text("Hi") + mm.Text("Hi") +
+			b.ForLocale(language.Russian).Text("Hi") +
+			b.ForBase(baseGerman).Text("Hi") +
+			b.Default().Text("Hi") +
+			b.Locales()[2].String() +
+			b.Readers()[1].Text("Hi")
+	}
+
+	const goroutines = 16
+	results := make([]string, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				results[i] = read()
+			}
+			_ = b.Coverage()
+			_ = b.Credits()
+		}()
+	}
+	wg.Wait()
+
+	expect := "HalloHiПриветHalloHiruHallo"
+	for _, a := range results {
+		require.Equal(t, expect, a)
+	}
+}
+
+func TestEagerTranslators(t *testing.T) {
+	var calls atomic.Int32
+	r := lazyTranslatorReader{
+		MockReader: MockReader{tag: language.German},
+		calls:      &calls,
+	}
+
+	_, err := localize.New(language.German, r)
+	require.NoError(t, err)
+	require.Zero(t, calls.Load())
+
+	_, err = localize.NewWithOptions(language.German,
+		localize.Options{EagerTranslators: true}, r)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,61 @@
+package localize
+
+import "golang.org/x/text/language"
+
+// CoverageStats is the translation coverage of a reader's catalog.
+type CoverageStats struct {
+	// Total is the number of messages in the catalog.
+	Total int
+
+	// Translated is the number of translated messages in the catalog.
+	Translated int
+}
+
+// Percent returns the translated percentage in range [0, 100].
+// Returns 100 if there are no messages to translate.
+func (c CoverageStats) Percent() float64 {
+	if c.Total < 1 {
+		return 100
+	}
+	return float64(c.Translated) / float64(c.Total) * 100
+}
+
+// Coverage returns the translation coverage of all readers of the bundle
+// by locale. A plural message is considered translated if its form Other
+// is translated. Readers that neither implement Cataloger nor wrap
+// a reader implementing it are omitted.
+// Coverage iterates over all catalogs on every call.
+func (l *Bundle) Coverage() map[language.Tag]CoverageStats {
+	m := make(map[language.Tag]CoverageStats, len(l.readers))
+	for i, r := range l.readers {
+		if s, ok := coverageOf(r); ok {
+			m[l.locales[i]] = s
+		}
+	}
+	return m
+}
+
+// coverageOf returns the coverage of the catalog of r.
+// Returns false if r neither implements Cataloger nor wraps a reader
+// implementing it.
+func coverageOf(r Reader) (s CoverageStats, ok bool) {
+	c, ok := findCataloger(r)
+	if !ok {
+		return s, false
+	}
+	for _, t := range c.Messages() {
+		s.Total++
+		if isTranslated(t) {
+			s.Translated++
+		}
+	}
+	return s, true
+}
+
+// isTranslated returns true if t isn't empty.
+func isTranslated(t Translation) bool {
+	if t.Plural {
+		return t.Forms.Other != ""
+	}
+	return t.Text != ""
+}
@@ -0,0 +1,141 @@
+package localize_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestCoverage(t *testing.T) {
+	source := MockCatalogReader{
+		MockReader: MockReader{tag: language.English},
+		messages: []MockCatalogMessage{
+			{Translation: localize.Translation{Text: "Hello"}},
+			{Translation: localize.Translation{Text: "Untranslated"}},
+			{Translation: localize.Translation{Plural: true, Forms: localize.Forms{
+				One: "%d apple", Other: "%d apples",
+			}}},
+			{Translation: localize.Translation{Plural: true, Forms: localize.Forms{
+				One: "%d pear", Other: "%d pears",
+			}}},
+		},
+	}
+	b, err := localize.NewWithOptions(language.English, localize.Options{Strict: true},
+		source,
+		newStrictTestReader(),
+		MockReader{tag: language.French},
+	)
+	require.NoError(t, err)
+
+	c := b.Coverage()
+	require.Equal(t, map[language.Tag]localize.CoverageStats{
+		language.English: {Total: 4, Translated: 4},
+		language.German:  {Total: 4, Translated: 2},
+	}, c)
+	require.Equal(t, 100.0, c[language.English].Percent())
+	require.Equal(t, 50.0, c[language.German].Percent())
+	require.Equal(t, 100.0, localize.CoverageStats{}.Percent())
+}
+
+func TestMinimumCoverage(t *testing.T) {
+	source := MockCatalogReader{
+		MockReader: MockReader{tag: language.English},
+		messages: []MockCatalogMessage{
+			{Translation: localize.Translation{Text: "Hello"}},
+		},
+	}
+	newBundle := func(t *testing.T, minimum float64) *localize.Bundle {
+		t.Helper()
+		b, err := localize.NewWithOptions(language.English,
+			localize.Options{MinimumCoverage: minimum},
+			source,
+			newStrictTestReader(), // 50% coverage.
+			MockReader{tag: language.French},
+		)
+		require.NoError(t, err)
+		return b
+	}
+
+	b := newBundle(t, 0.8)
+	r, c := b.Match(language.German)
+	require.Nil(t, r)
+	require.Equal(t, language.No, c)
+	r, _ = b.MustMatch(language.German)
+	require.Equal(t, language.English, r.Locale())
+	require.Equal(t, language.English, b.ForLocale(language.German).Locale())
+	require.Equal(t, language.English, b.ForBase(language.MustParseBase("de")).Locale())
+	// Readers without catalog are never excluded.
+	require.Equal(t, language.French, b.ForLocale(language.French).Locale())
+	r, _ = b.Match(language.French)
+	require.Equal(t, language.French, r.Locale())
+	require.Len(t, b.Readers(), 3)
+
+	b = newBundle(t, 0.5)
+	require.Equal(t, language.German, b.ForLocale(language.German).Locale())
+	r, _ = b.Match(language.German)
+	require.Equal(t, language.German, r.Locale())
+}
+
+func TestMinimumCoverageDefault(t *testing.T) {
+	// The default reader is never excluded.
+	b, err := localize.NewWithOptions(language.German,
+		localize.Options{MinimumCoverage: 1},
+		newStrictTestReader(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, language.German, b.ForLocale(language.German).Locale())
+	r, _ := b.Match(language.German)
+	require.Equal(t, language.German, r.Locale())
+}
+
+// TestCatalogerUnwrap verifies that the Cataloger of a reader is found
+// through the Unwrap chain of all wrapper readers of package localize.
+func TestCatalogerUnwrap(t *testing.T) {
+	identity := localize.TransformerFunc(func(_ language.Tag, s string) string {
+		return s
+	})
+	source := MockCatalogReader{
+		MockReader: MockReader{tag: language.German},
+		messages: []MockCatalogMessage{{
+			Key:         localize.Key{Hash: "818274c2b2b715d5", Source: "Hello"},
+			Translation: localize.Translation{Text: "Hallo"},
+		}},
+	}
+	for name, wrap := range map[string]func(localize.Reader) localize.Reader{
+		"Chain":            func(r localize.Reader) localize.Reader { return localize.Chain(r, identity) },
+		"WithNativeDigits": localize.WithNativeDigits,
+		"WithQuantityFormatter": func(r localize.Reader) localize.Reader {
+			return localize.WithQuantityFormatter(r, localize.FormatQuantity)
+		},
+		"NewKeyedReader": func(r localize.Reader) localize.Reader {
+			return localize.NewKeyedReader(r)
+		},
+		"NewStrictReader": func(r localize.Reader) localize.Reader {
+			return localize.NewStrictReader(r, nil)
+		},
+		"MergeReader": func(r localize.Reader) localize.Reader {
+			return localize.MergeReader(r, MockReader{tag: language.German})
+		},
+		"Transliterate": func(r localize.Reader) localize.Reader {
+			return localize.Transliterate(r, language.German, identity)
+		},
+		"NewDebugReader": func(r localize.Reader) localize.Reader {
+			return localize.NewDebugReader(r)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Wrapped twice, such that the chain is followed beyond the first reader.
+			r := wrap(localize.WithNativeDigits(source))
+			b, err := localize.New(language.German, r)
+			require.NoError(t, err)
+			require.Equal(t, map[language.Tag]localize.CoverageStats{
+				language.German: {Total: 1, Translated: 1},
+			}, b.Coverage())
+
+			d := localize.NewDebugReader(r)
+			require.Contains(t, d.Text("Hello"), "[818274]")
+		})
+	}
+}
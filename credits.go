@@ -0,0 +1,56 @@
+package localize
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// HeaderTranslatorCredits is the catalog header listing the translators
+// credited for a catalog separated by commas, such as:
+//
+//	"X-Translator-Credits: Jane Doe <jane@example.com>, John Roe\n"
+const HeaderTranslatorCredits = "X-Translator-Credits"
+
+// Credits returns the translators credited by the X-Translator-Credits
+// header of the catalogs of all readers of the bundle by locale.
+// Readers without credits or that neither implement MetadataProvider
+// nor wrap a reader implementing it are omitted.
+func (l *Bundle) Credits() map[language.Tag][]string {
+	m := make(map[language.Tag][]string)
+	for i, r := range l.readers {
+		p, ok := findMetadataProvider(r)
+		if !ok {
+			continue
+		}
+		if c := parseCredits(p.Metadata()[HeaderTranslatorCredits]); len(c) > 0 {
+			m[l.locales[i]] = c
+		}
+	}
+	return m
+}
+
+// parseCredits returns the non-empty comma-separated names of s.
+func parseCredits(s string) (names []string) {
+	for n := range strings.SplitSeq(s, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// findMetadataProvider returns the first MetadataProvider
+// in the chain of wrapped readers.
+func findMetadataProvider(r Reader) (MetadataProvider, bool) {
+	for {
+		if p, ok := r.(MetadataProvider); ok {
+			return p, true
+		}
+		w, ok := r.(interface{ Unwrap() Reader })
+		if !ok {
+			return nil, false
+		}
+		r = w.Unwrap()
+	}
+}
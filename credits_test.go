@@ -0,0 +1,45 @@
+package localize_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+type MockMetadataReader struct {
+	MockReader
+	metadata map[string]string
+}
+
+func (r MockMetadataReader) Metadata() map[string]string {
+	return maps.Clone(r.metadata)
+}
+
+func TestCredits(t *testing.T) {
+	b, err := localize.New(language.English,
+		MockMetadataReader{
+			MockReader: MockReader{tag: language.English},
+			metadata:   map[string]string{"Language": "en"},
+		},
+		localize.NewDebugReader(MockMetadataReader{
+			MockReader: MockReader{tag: language.German},
+			metadata: map[string]string{
+				localize.HeaderTranslatorCredits: "Jane Doe <jane@example.com>, , John Roe ",
+			},
+		}),
+		MockMetadataReader{
+			MockReader: MockReader{tag: language.French},
+			metadata:   map[string]string{localize.HeaderTranslatorCredits: "Marie"},
+		},
+		MockReader{tag: language.Italian},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, map[language.Tag][]string{
+		language.German: {"Jane Doe <jane@example.com>", "John Roe"},
+		language.French: {"Marie"},
+	}, b.Credits())
+}
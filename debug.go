@@ -0,0 +1,267 @@
+package localize
+
+import (
+	"sync/atomic"
+
+	"github.com/romshark/localize/strfmt"
+)
+
+// DebugReader wraps a Reader and, while enabled, decorates every localized string
+// with the short hash of the message that produced it, such as:
+//
+//	"[818274] Hello"
+//
+// This allows QA to identify which catalog entry produced which text on screen.
+// The hash is "??????" if the message can't be found in the catalog or
+// neither the wrapped reader nor any reader wrapped by it implements Cataloger.
+// The results of Grammar, FormatCompact and Truncate, which aren't messages
+// of the catalog, are decorated with "[grammar]", "[number]" and "[trunc]".
+// DebugReader is safe for concurrent use.
+type DebugReader struct {
+	Reader
+
+	// enabled is shared with the readers returned by WithRegister
+	// and Section, such that SetEnabled toggles all of them.
+	enabled *atomic.Bool
+
+	// section is the section of the reader (see Reader.Section).
+	section string
+
+	// hashByStatic and hashByPlural map the source texts of messages
+	// to their hashes, identified by localize.SectionID if scoped.
+	hashByStatic map[string]string
+	hashByPlural map[string]string
+
+	// normalize normalizes source texts like the wrapped reader
+	// before they're looked up (see Normalizer).
+	normalize func(text string) string
+}
+
+var _ Reader = new(DebugReader)
+
+// DebugHashLen is the length of the message hash prefix
+// used to decorate strings by DebugReader.
+const DebugHashLen = 6
+
+// NewDebugReader creates a new enabled debug reader wrapping r.
+func NewDebugReader(r Reader) *DebugReader {
+	d := &DebugReader{
+		Reader:       r,
+		enabled:      new(atomic.Bool),
+		hashByStatic: map[string]string{},
+		hashByPlural: map[string]string{},
+		normalize:    func(text string) string { return text },
+	}
+	if n, ok := findWrapped[Normalizer](r); ok {
+		d.normalize = n.Normalize
+	}
+	if c, ok := findCataloger(r); ok {
+		for k, t := range c.Messages() {
+			id := k.Source
+			if k.Section != "" {
+				id = SectionID(k.Section, k.Source)
+			}
+			if t.Plural {
+				d.hashByPlural[id] = k.Hash
+				continue
+			}
+			d.hashByStatic[id] = k.Hash
+		}
+	}
+	d.enabled.Store(true)
+	return d
+}
+
+// findCataloger returns the first Cataloger in the chain of wrapped readers.
+func findCataloger(r Reader) (Cataloger, bool) { return findWrapped[Cataloger](r) }
+
+// findWrapped returns the first reader implementing T
+// in the chain of wrapped readers.
+func findWrapped[T any](r Reader) (T, bool) {
+	for {
+		if c, ok := r.(T); ok {
+			return c, true
+		}
+		w, ok := r.(interface{ Unwrap() Reader })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		r = w.Unwrap()
+	}
+}
+
+// SetEnabled enables or disables decoration at runtime, including
+// the decoration of the readers returned by WithRegister and Section.
+// A disabled DebugReader behaves like the wrapped reader.
+func (d *DebugReader) SetEnabled(enabled bool) { d.enabled.Store(enabled) }
+
+// Enabled returns true if decoration is enabled.
+func (d *DebugReader) Enabled() bool { return d.enabled.Load() }
+
+// Unwrap returns the wrapped reader.
+func (d *DebugReader) Unwrap() Reader { return d.Reader }
+
+// Text calls Text on the wrapped reader and decorates the result.
+func (d *DebugReader) Text(text string) (localized string) {
+	localized = d.Reader.Text(text)
+	if !d.Enabled() {
+		return localized
+	}
+	return decorate(d.hash(d.hashByStatic, text), localized)
+}
+
+// Block calls Block on the wrapped reader and decorates the result.
+func (d *DebugReader) Block(text string) (localized string) {
+	localized = d.Reader.Block(text)
+	if !d.Enabled() {
+		return localized
+	}
+	return decorate(d.hash(d.hashByStatic, d.blockKey(d.hashByStatic, text)), localized)
+}
+
+// Plural calls Plural on the wrapped reader and decorates the result.
+func (d *DebugReader) Plural(templates Forms, quantity any) (localized string) {
+	localized = d.Reader.Plural(templates, quantity)
+	if !d.Enabled() {
+		return localized
+	}
+	return decorate(d.hash(d.hashByPlural, templates.Other), localized)
+}
+
+// PluralBlock calls PluralBlock on the wrapped reader and decorates the result.
+func (d *DebugReader) PluralBlock(templates Forms, quantity any) (localized string) {
+	localized = d.Reader.PluralBlock(templates, quantity)
+	if !d.Enabled() {
+		return localized
+	}
+	return decorate(d.hash(d.hashByPlural, d.blockKey(d.hashByPlural, templates.Other)),
+		localized)
+}
+
+// Cardinal calls Cardinal on the wrapped reader and decorates the result.
+func (d *DebugReader) Cardinal(otherTemplate string, quantity any) (localized string) {
+	localized = d.Reader.Cardinal(otherTemplate, quantity)
+	if !d.Enabled() {
+		return localized
+	}
+	return decorate(d.hash(d.hashByPlural, otherTemplate), localized)
+}
+
+// PluralRange calls PluralRange on the wrapped reader and decorates the result.
+func (d *DebugReader) PluralRange(templates Forms, from, to any) (localized string) {
+	localized = d.Reader.PluralRange(templates, from, to)
+	if !d.Enabled() {
+		return localized
+	}
+	return decorate(d.hash(d.hashByPlural, templates.Other), localized)
+}
+
+// PluralOrdinal calls PluralOrdinal on the wrapped reader
+// and decorates the result.
+func (d *DebugReader) PluralOrdinal(
+	templates OrdinalForms, ordinal, quantity any,
+) (localized string) {
+	localized = d.Reader.PluralOrdinal(templates, ordinal, quantity)
+	if !d.Enabled() {
+		return localized
+	}
+	forms := templates.Forms(d.Translator(), ordinal)
+	return decorate(d.hash(d.hashByPlural, forms.Other), localized)
+}
+
+// Grammar calls Grammar on the wrapped reader and decorates the result.
+func (d *DebugReader) Grammar(key string, args ...string) (localized string) {
+	localized = d.Reader.Grammar(key, args...)
+	if !d.Enabled() {
+		return localized
+	}
+	return "[grammar] " + localized
+}
+
+// FormatCompact calls FormatCompact on the wrapped reader
+// and decorates the result.
+func (d *DebugReader) FormatCompact(n float64) (localized string) {
+	localized = d.Reader.FormatCompact(n)
+	if !d.Enabled() {
+		return localized
+	}
+	return "[number] " + localized
+}
+
+// Truncate calls Truncate on the wrapped reader and decorates the result.
+func (d *DebugReader) Truncate(s string, max int) (localized string) {
+	localized = d.Reader.Truncate(s, max)
+	if !d.Enabled() {
+		return localized
+	}
+	return "[trunc] " + localized
+}
+
+// WithRegister returns a debug reader wrapping the reader of register
+// of the wrapped reader. The returned reader is enabled while d is enabled.
+func (d *DebugReader) WithRegister(register Register) Reader {
+	w := *d
+	w.Reader = d.Reader.WithRegister(register)
+	return &w
+}
+
+// Section returns a debug reader wrapping the reader of section name
+// of the wrapped reader, decorating the messages of the section with
+// their hashes. The returned reader is enabled while d is enabled.
+func (d *DebugReader) Section(name string) Reader {
+	w := *d
+	w.Reader = d.Reader.Section(name)
+	w.section = SectionPath(d.section, name)
+	return &w
+}
+
+// hash returns the hash of the message with source text source in m,
+// which is the message of the section of d if any like in generated readers.
+func (d *DebugReader) hash(m map[string]string, source string) string {
+	source = d.normalize(source)
+	if d.section != "" {
+		if h, ok := m[SectionID(d.section, source)]; ok {
+			return h
+		}
+	}
+	return m[source]
+}
+
+// blockKey is like blockKey for the texts normalized like by the wrapped reader,
+// which normalizes the dedented text before looking it up.
+func (d *DebugReader) blockKey(m map[string]string, text string) string {
+	dedented := d.normalize(strfmt.Dedent(text))
+	if _, ok := m[dedented]; !ok {
+		if r := d.normalize(strfmt.Reflow(dedented)); r != dedented {
+			if _, ok := m[r]; ok {
+				return r
+			}
+		}
+	}
+	return dedented
+}
+
+// blockKey returns the key of the Block or PluralBlock text in m, which is
+// the reflowed text if the message was formatted with strfmt.DedentReflow.
+func blockKey[V any](m map[string]V, text string) string {
+	dedented := strfmt.Dedent(text)
+	if _, ok := m[dedented]; !ok {
+		if r := strfmt.Reflow(dedented); r != dedented {
+			if _, ok := m[r]; ok {
+				return r
+			}
+		}
+	}
+	return dedented
+}
+
+func decorate(hash, localized string) string {
+	if hash == "" {
+		hash = "??????"
+	}
+	if len(hash) > DebugHashLen {
+		hash = hash[:DebugHashLen]
+	}
+	return "[" + hash + "] " + localized
+}
@@ -0,0 +1,226 @@
+package localize_test
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/strfmt"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+type MockCatalogReader struct {
+	MockReader
+	messages []MockCatalogMessage
+}
+
+type MockCatalogMessage struct {
+	Key         localize.Key
+	Translation localize.Translation
+}
+
+var _ localize.Cataloger = MockCatalogReader{}
+
+func (r MockCatalogReader) Messages() iter.Seq2[localize.Key, localize.Translation] {
+	return func(yield func(localize.Key, localize.Translation) bool) {
+		for _, m := range r.messages {
+			if !yield(m.Key, m.Translation) {
+				return
+			}
+		}
+	}
+}
+
+func TestDebugReader(t *testing.T) {
+	r := MockCatalogReader{
+		MockReader: MockReader{
+			tag: language.German,
+			static: map[string]string{
+				"Hello":             "Hallo",
+				"Multi\n  line":     "Mehr\n  zeilig",
+				"Not in catalog":    "Nicht im Katalog",
+				"\tMulti\n\t  line": "Mehr\n  zeilig",
+			},
+		},
+		messages: []MockCatalogMessage{
+			{
+				Key:         localize.Key{Hash: "818274c2b2b715d5", Source: "Hello"},
+				Translation: localize.Translation{Text: "Hallo"},
+			},
+			{
+				Key:         localize.Key{Hash: "2167a000384d7a5b", Source: "Multi\n  line"},
+				Translation: localize.Translation{Text: "Mehr\n  zeilig"},
+			},
+			{
+				Key: localize.Key{Hash: "c2b9e5304ee8d192", Source: "%d apples"},
+				Translation: localize.Translation{Plural: true, Forms: localize.Forms{
+					One: "%d Apfel", Other: "%d Äpfel",
+				}},
+			},
+		},
+	}
+
+	d := localize.NewDebugReader(r)
+	require.True(t, d.Enabled())
+	require.Equal(t, r, d.Unwrap())
+	require.Equal(t, language.German, d.Locale())
+
+	require.Equal(t, "[818274] Hallo", d.Text("Hello"))
+	require.Equal(t, "[2167a0] Mehr\n  zeilig", d.Block("\tMulti\n\t  line"))
+	require.Equal(t, "[??????] Nicht im Katalog", d.Text("Not in catalog"))
+	require.Equal(t, "[c2b9e5] ", d.Plural(localize.Forms{
+		One: "%d apple", Other: "%d apples",
+	}, 2))
+
+	d.SetEnabled(false)
+	require.False(t, d.Enabled())
+	require.Equal(t, "Hallo", d.Text("Hello"))
+
+	d.SetEnabled(true)
+	require.Equal(t, "[818274] Hallo", d.Text("Hello"))
+}
+
+func TestDebugReaderNoCataloger(t *testing.T) {
+	d := localize.NewDebugReader(MockReader{
+		tag:    language.English,
+		static: map[string]string{"Hello": "Hello"},
+	})
+	require.Equal(t, "[??????] Hello", d.Text("Hello"))
+}
+
+func TestDebugReaderReflowed(t *testing.T) {
+	r := MockCatalogReader{
+		MockReader: MockReader{tag: language.German},
+		messages: []MockCatalogMessage{
+			{
+				Key:         localize.Key{Hash: "aac7fddcb9663534", Source: "Multi line"},
+				Translation: localize.Translation{Text: "Mehrzeilig"},
+			},
+			{
+				Key:         localize.Key{Hash: "c442446e1678eef2", Source: "Kept\nline"},
+				Translation: localize.Translation{Text: "Behalten"},
+			},
+		},
+	}
+
+	d := localize.NewDebugReader(r)
+	require.Equal(t, "[aac7fd] ", d.Block("\tMulti\n\tline"))
+	require.Equal(t, "[c44244] ", d.Block("\tKept\n\tline"))
+
+	var missing []error
+	s := localize.NewStrictReader(r, func(err error) { missing = append(missing, err) })
+	s.Block("\tMulti\n\tline")
+	s.Block("\tKept\n\tline")
+	require.Empty(t, missing)
+}
+
+// MockNormalizingReader normalizes source texts like generated readers.
+type MockNormalizingReader struct{ MockCatalogReader }
+
+var _ localize.Normalizer = MockNormalizingReader{}
+
+func (r MockNormalizingReader) Normalize(text string) string {
+	return strfmt.Normalize(text, strfmt.NormalizeNFC|strfmt.NormalizeSpaces)
+}
+
+func TestDebugReaderNormalized(t *testing.T) {
+	r := MockNormalizingReader{MockCatalogReader{
+		MockReader: MockReader{tag: language.German},
+		messages: []MockCatalogMessage{
+			{
+				Key:         localize.Key{Hash: "5a1b2c3d4e5f6071", Source: "Trailing "},
+				Translation: localize.Translation{Text: "Nachgestellt"},
+			},
+			{
+				Key:         localize.Key{Hash: "61c2d3e4f5061728", Source: "Caf\u00e9 au lait"},
+				Translation: localize.Translation{Text: "Milchkaffee"},
+			},
+			{
+				Key:         localize.Key{Hash: "7d3e4f5061728394", Source: "Multi line\n  text"},
+				Translation: localize.Translation{Text: "Mehrzeilig"},
+			},
+			{
+				Key: localize.Key{Hash: "8e4f506172839405", Source: "%d days left"},
+				Translation: localize.Translation{Plural: true, Forms: localize.Forms{
+					One: "%d Tag übrig", Other: "%d Tage übrig",
+				}},
+			},
+		},
+	}}
+
+	// Normalizers are found through wrapping readers too.
+	d := localize.NewDebugReader(localize.Chain(r, localize.TransformerFunc(
+		func(_ language.Tag, s string) string { return s },
+	)))
+	require.Equal(t, "[5a1b2c] ", d.Text("Trailing   "))
+	require.Equal(t, "[61c2d3] ", d.Text("Cafe\u0301 au lait"))
+	require.Equal(t, "[7d3e4f] ", d.Block("\n\t\tMulti  line\n\t\t  text\n\t"))
+	require.Equal(t, "[8e4f50] ", d.Cardinal("%d days   left", 2))
+	require.Equal(t, "[8e4f50] ", d.PluralBlock(localize.Forms{
+		One: "\n\t%d day  left\n", Other: "\n\t%d days  left\n",
+	}, 2))
+}
+
+func TestDebugReaderToggleThroughBundle(t *testing.T) {
+	d := localize.NewDebugReader(MockCatalogReader{
+		MockReader: MockReader{
+			tag:    language.German,
+			static: map[string]string{"Hello": "Hallo"},
+		},
+		messages: []MockCatalogMessage{{
+			Key:         localize.Key{Hash: "818274c2b2b715d5", Source: "Hello"},
+			Translation: localize.Translation{Text: "Hallo"},
+		}},
+	})
+	b, err := localize.NewWithOptions(language.German, localize.Options{
+		Register: localize.RegisterFormal,
+	}, d)
+	require.NoError(t, err)
+
+	r := b.ForLocale(language.German)
+	require.Equal(t, "[818274] Hallo", r.Text("Hello"))
+	s := r.Section("checkout")
+
+	d.SetEnabled(false)
+	require.Equal(t, "Hallo", r.Text("Hello"))
+	require.Equal(t, "Hallo", s.Text("Hello"))
+
+	d.SetEnabled(true)
+	require.Equal(t, "[818274] Hallo", r.Text("Hello"))
+	require.Equal(t, "[818274] Hallo", s.Text("Hello"))
+}
+
+func TestDebugReaderSection(t *testing.T) {
+	d := localize.NewDebugReader(MockCatalogReader{
+		MockReader: MockReader{tag: language.German},
+		messages: []MockCatalogMessage{
+			{
+				Key:         localize.Key{Hash: "818274c2b2b715d5", Source: "Save"},
+				Translation: localize.Translation{Text: "Speichern"},
+			},
+			{
+				Key: localize.Key{
+					Hash: "5a1e0c8c6b0e5b7d", Source: "Save", Section: "checkout",
+				},
+				Translation: localize.Translation{Text: "Sichern"},
+			},
+		},
+	})
+	require.Equal(t, "[818274] ", d.Text("Save"))
+	require.Equal(t, "[5a1e0c] ", d.Section("checkout").Text("Save"))
+	// Unscoped messages are found from sections not overriding them.
+	require.Equal(t, "[818274] ", d.Section("profile").Text("Save"))
+}
+
+func TestDebugReaderAuxiliary(t *testing.T) {
+	d := localize.NewDebugReader(MockReader{tag: language.English})
+	require.Equal(t, "[grammar] the cart", d.Grammar("definite", "the", "cart"))
+	require.Equal(t, "[number] 1500", d.FormatCompact(1500))
+	require.Equal(t, "[trunc] Hello", d.Truncate("Hello", 10))
+
+	d.SetEnabled(false)
+	require.Equal(t, "the cart", d.Grammar("definite", "the", "cart"))
+	require.Equal(t, "1500", d.FormatCompact(1500))
+	require.Equal(t, "Hello", d.Truncate("Hello", 10))
+}
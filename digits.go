@@ -0,0 +1,240 @@
+package localize
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/currency"
+	"golang.org/x/text/language"
+)
+
+// numberingSystems maps numbering system identifiers (CLDR "nu" values)
+// to their digit zero. Digits 1-9 follow zero consecutively.
+var numberingSystems = map[string]rune{
+	"latn":    '0',
+	"arab":    '٠',
+	"arabext": '۰',
+	"beng":    '০',
+	"deva":    '०',
+	"mymr":    '၀',
+	"tibt":    '༠',
+}
+
+// defaultNumberingSystems maps locales to their CLDR default numbering
+// system if it isn't "latn". Locales are matched exactly first
+// and by base language second.
+var defaultNumberingSystems = map[string]string{
+	"ar":    "arab",
+	"ar-DZ": "latn",
+	"ar-EH": "latn",
+	"ar-LY": "latn",
+	"ar-MA": "latn",
+	"ar-TN": "latn",
+	"as":    "beng",
+	"bn":    "beng",
+	"ckb":   "arab",
+	"dz":    "tibt",
+	"fa":    "arabext",
+	"ks":    "arabext",
+	"mr":    "deva",
+	"my":    "mymr",
+	"ne":    "deva",
+	"ps":    "arabext",
+	"sa":    "deva",
+	"ur-IN": "arabext",
+}
+
+// NumberingSystem returns the numbering system of locale, which is
+// either set explicitly by the Unicode extension "nu" (like "ar-u-nu-latn")
+// or the CLDR default numbering system of locale, such as "arab" for "ar"
+// and "latn" for "en". Only decimal numbering systems with digits known
+// to NativeDigits are returned, "latn" is returned for all others.
+func NumberingSystem(locale language.Tag) string {
+	if nu := locale.TypeForKey("nu"); nu != "" {
+		if _, ok := numberingSystems[nu]; ok {
+			return nu
+		}
+	}
+	locale, _ = locale.SetTypeForKey("nu", "")
+	if nu, ok := defaultNumberingSystems[locale.String()]; ok {
+		return nu
+	}
+	base, _ := locale.Base()
+	if nu, ok := defaultNumberingSystems[base.String()]; ok {
+		return nu
+	}
+	return "latn"
+}
+
+// NativeDigits replaces the ASCII digits of s with the digits of the
+// numbering system of locale (see NumberingSystem), skipping format verbs
+// like "%[1]d" and "%5.2f". Returns s unchanged for locales using
+// the "latn" numbering system.
+func NativeDigits(locale language.Tag, s string) string {
+	return replaceDigits(numberingSystems[NumberingSystem(locale)], s)
+}
+
+func replaceDigits(zero rune, s string) string {
+	if zero == '0' || !strings.ContainsAny(s, "0123456789") {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/2)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%':
+			// Copy the format verb as is.
+			end := i + 1
+			for end < len(s) && !isVerb(s[end]) {
+				end++
+			}
+			end = min(end+1, len(s))
+			b.WriteString(s[i:end])
+			i = end - 1
+		case c >= '0' && c <= '9':
+			b.WriteRune(zero + rune(c-'0'))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// isVerb returns true for bytes terminating a format verb.
+func isVerb(c byte) bool {
+	return c == '%' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+		c >= utf8.RuneSelf
+}
+
+// WithNativeDigits returns a reader rendering the digits of all localized
+// strings of r and the numbers, dates and times formatted by its translator
+// in the numbering system of its locale (see NativeDigits), such as
+// "٣ رسائل" instead of "3 رسائل" for "ar".
+// Returns r if its locale uses the "latn" numbering system.
+func WithNativeDigits(r Reader) Reader {
+	zero := numberingSystems[NumberingSystem(r.Locale())]
+	if zero == '0' {
+		return r
+	}
+	return &nativeDigitsReader{Reader: r, zero: zero}
+}
+
+type nativeDigitsReader struct {
+	Reader
+	zero rune
+}
+
+// Unwrap returns the wrapped reader.
+func (r *nativeDigitsReader) Unwrap() Reader { return r.Reader }
+
+func (r *nativeDigitsReader) Text(text string) string {
+	return replaceDigits(r.zero, r.Reader.Text(text))
+}
+
+func (r *nativeDigitsReader) Block(text string) string {
+	return replaceDigits(r.zero, r.Reader.Block(text))
+}
+
+func (r *nativeDigitsReader) Plural(templates Forms, quantity any) string {
+	return replaceDigits(r.zero, r.Reader.Plural(templates, quantity))
+}
+
+func (r *nativeDigitsReader) PluralBlock(templates Forms, quantity any) string {
+	return replaceDigits(r.zero, r.Reader.PluralBlock(templates, quantity))
+}
+
+func (r *nativeDigitsReader) Cardinal(otherTemplate string, quantity any) string {
+	return replaceDigits(r.zero, r.Reader.Cardinal(otherTemplate, quantity))
+}
+
+func (r *nativeDigitsReader) PluralRange(templates Forms, from, to any) string {
+	return replaceDigits(r.zero, r.Reader.PluralRange(templates, from, to))
+}
+
+func (r *nativeDigitsReader) PluralOrdinal(
+	templates OrdinalForms, ordinal, quantity any,
+) string {
+	return replaceDigits(r.zero, r.Reader.PluralOrdinal(templates, ordinal, quantity))
+}
+
+func (r *nativeDigitsReader) Grammar(key string, args ...string) string {
+	return replaceDigits(r.zero, r.Reader.Grammar(key, args...))
+}
+
+func (r *nativeDigitsReader) FormatCompact(n float64) string {
+	return replaceDigits(r.zero, r.Reader.FormatCompact(n))
+}
+
+func (r *nativeDigitsReader) WithRegister(register Register) Reader {
+	return &nativeDigitsReader{Reader: r.Reader.WithRegister(register), zero: r.zero}
+}
+
+func (r *nativeDigitsReader) Section(name string) Reader {
+	return &nativeDigitsReader{Reader: r.Reader.Section(name), zero: r.zero}
+}
+
+func (r *nativeDigitsReader) Translator() locales.Translator {
+	return nativeDigitsTranslator{Translator: r.Reader.Translator(), zero: r.zero}
+}
+
+// nativeDigitsTranslator renders the numbers, dates and times
+// formatted by Translator in native digits.
+type nativeDigitsTranslator struct {
+	locales.Translator
+	zero rune
+}
+
+func (t nativeDigitsTranslator) FmtNumber(num float64, v uint64) string {
+	return replaceDigits(t.zero, t.Translator.FmtNumber(num, v))
+}
+
+func (t nativeDigitsTranslator) FmtPercent(num float64, v uint64) string {
+	return replaceDigits(t.zero, t.Translator.FmtPercent(num, v))
+}
+
+func (t nativeDigitsTranslator) FmtCurrency(
+	num float64, v uint64, c currency.Type,
+) string {
+	return replaceDigits(t.zero, t.Translator.FmtCurrency(num, v, c))
+}
+
+func (t nativeDigitsTranslator) FmtAccounting(
+	num float64, v uint64, c currency.Type,
+) string {
+	return replaceDigits(t.zero, t.Translator.FmtAccounting(num, v, c))
+}
+
+func (t nativeDigitsTranslator) FmtDateShort(d time.Time) string {
+	return replaceDigits(t.zero, t.Translator.FmtDateShort(d))
+}
+
+func (t nativeDigitsTranslator) FmtDateMedium(d time.Time) string {
+	return replaceDigits(t.zero, t.Translator.FmtDateMedium(d))
+}
+
+func (t nativeDigitsTranslator) FmtDateLong(d time.Time) string {
+	return replaceDigits(t.zero, t.Translator.FmtDateLong(d))
+}
+
+func (t nativeDigitsTranslator) FmtDateFull(d time.Time) string {
+	return replaceDigits(t.zero, t.Translator.FmtDateFull(d))
+}
+
+func (t nativeDigitsTranslator) FmtTimeShort(d time.Time) string {
+	return replaceDigits(t.zero, t.Translator.FmtTimeShort(d))
+}
+
+func (t nativeDigitsTranslator) FmtTimeMedium(d time.Time) string {
+	return replaceDigits(t.zero, t.Translator.FmtTimeMedium(d))
+}
+
+func (t nativeDigitsTranslator) FmtTimeLong(d time.Time) string {
+	return replaceDigits(t.zero, t.Translator.FmtTimeLong(d))
+}
+
+func (t nativeDigitsTranslator) FmtTimeFull(d time.Time) string {
+	return replaceDigits(t.zero, t.Translator.FmtTimeFull(d))
+}
@@ -0,0 +1,87 @@
+package localize_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/ar"
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestNumberingSystem(t *testing.T) {
+	for _, tt := range []struct {
+		locale string
+		expect string
+	}{
+		{"en", "latn"},
+		{"ar", "arab"},
+		{"ar-EG", "arab"},
+		{"ar-MA", "latn"},
+		{"ar-u-nu-latn", "latn"},
+		{"en-u-nu-deva", "deva"},
+		{"en-u-nu-unknown", "latn"},
+		{"fa", "arabext"},
+		{"hi", "latn"},
+		{"mr", "deva"},
+		{"ur", "latn"},
+		{"ur-IN", "arabext"},
+	} {
+		t.Run(tt.locale, func(t *testing.T) {
+			require.Equal(t, tt.expect,
+				localize.NumberingSystem(language.MustParse(tt.locale)))
+		})
+	}
+}
+
+func TestNativeDigits(t *testing.T) {
+	require.Equal(t, "٣ رسائل، %d و %[2]s و %5.2f و ١٠٠",
+		localize.NativeDigits(language.Arabic, "3 رسائل، %d و %[2]s و %5.2f و 100"))
+	require.Equal(t, "۱۲:۳۰", localize.NativeDigits(language.Persian, "12:30"))
+	require.Equal(t, "12:30 %", localize.NativeDigits(language.English, "12:30 %"))
+	require.Equal(t, "١٢ %", localize.NativeDigits(language.Arabic, "12 %"))
+}
+
+type translatorReader struct {
+	MockReader
+	tr locales.Translator
+}
+
+func (r translatorReader) Translator() locales.Translator { return r.tr }
+
+func (r translatorReader) Plural(templates localize.Forms, quantity any) string {
+	return fmt.Sprintf(templates.Other, quantity)
+}
+
+func TestWithNativeDigits(t *testing.T) {
+	en := MockReader{tag: language.English}
+	require.Equal(t, localize.Reader(en), localize.WithNativeDigits(en))
+
+	r := translatorReader{
+		MockReader: MockReader{
+			tag:    language.Arabic,
+			static: map[string]string{"Top %d": "أفضل %d", "2FA": "2FA"},
+		},
+		tr: ar.New(),
+	}
+	n := localize.WithNativeDigits(r)
+	require.Equal(t, "أفضل %d", n.Text("Top %d"))
+	require.Equal(t, "٢FA", n.Text("2FA"))
+	require.Equal(t, "١٥ رسائل",
+		n.Plural(localize.Forms{Other: "%d رسائل"}, 15))
+	require.Equal(t, "١٥٠٠", n.FormatCompact(1500))
+	require.Equal(t, r, n.(interface{ Unwrap() localize.Reader }).Unwrap())
+
+	require.Equal(t, localize.NativeDigits(language.Arabic, r.tr.FmtNumber(1234.5, 1)),
+		n.Translator().FmtNumber(1234.5, 1))
+	require.NotContains(t, n.Translator().FmtDateShort(time.Now()), "2")
+
+	b, err := localize.NewWithOptions(language.Arabic, localize.Options{
+		NativeDigits: true,
+	}, r)
+	require.NoError(t, err)
+	require.Equal(t, "٢FA", b.Default().Text("2FA"))
+}
@@ -0,0 +1,177 @@
+package localize
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales"
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/pluralcheck"
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrUnsupportedLocale           = errors.New("unsupported locale")
+	ErrMissingPluralForm           = pluralcheck.ErrMissingPluralForm
+	ErrUnsupportedPluralForm       = pluralcheck.ErrUnsupportedPluralForm
+	ErrMissingQuantityPlaceholder  = pluralcheck.ErrMissingQuantityPlaceholder
+	ErrTooManyQuantityPlaceholders = pluralcheck.ErrTooManyQuantityPlaceholders
+	ErrWrongPlaceholderVerb        = pluralcheck.ErrWrongPlaceholderVerb
+)
+
+// ValidateForms checks forms f of a plural message against the CLDR plural
+// rules of locale the same way the source code extraction does.
+// Use it to verify programmatically constructed forms, such as texts
+// provided by a CMS, before passing them to Reader.Plural.
+// All issues found are joined into the returned error, which is nil
+// if f is valid.
+func ValidateForms(locale language.Tag, f Forms) error {
+	pluralForms, ok := cldr.ByTagOrBase(locale)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrUnsupportedLocale, locale)
+	}
+	errs, unsupported := pluralcheck.Check(locale, pluralForms, pluralcheck.Forms{
+		Zero: f.Zero, One: f.One, Two: f.Two,
+		Few: f.Few, Many: f.Many, Other: f.Other,
+	})
+	for _, form := range unsupported {
+		errs = append(errs, fmt.Errorf(
+			"%w: locale %q doesn't support plural form %s",
+			ErrUnsupportedPluralForm, locale.String(), form,
+		))
+	}
+	return errors.Join(errs...)
+}
+
+// String returns the filled forms of f named by their CLDR plural category,
+// like:
+//
+//	Forms{One: "%d apple", Other: "%d apples"}
+func (f Forms) String() string {
+	var b strings.Builder
+	b.WriteString("Forms{")
+	first := true
+	for _, c := range [...]struct{ name, text string }{
+		{"Zero", f.Zero},
+		{"One", f.One},
+		{"Two", f.Two},
+		{"Few", f.Few},
+		{"Many", f.Many},
+		{"Other", f.Other},
+	} {
+		if c.text == "" {
+			continue
+		}
+		if !first {
+			b.WriteString(", ")
+		}
+		first = false
+		b.WriteString(c.name)
+		b.WriteString(": ")
+		b.WriteString(strconv.Quote(c.text))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// Complete returns true if all forms required by the cardinal plural rules
+// of tr are filled, such as One and Other for English or
+// One, Few, Many and Other for Russian.
+// The translator of a reader is provided by Reader.Translator.
+func (f Forms) Complete(tr locales.Translator) bool {
+	for _, r := range tr.PluralsCardinal() {
+		if f.form(r) == "" {
+			return false
+		}
+	}
+	return f.Other != ""
+}
+
+// RangeForm returns the form of f for the range of quantities from and to
+// selected by the CLDR plural range rules of tr, such as Few for the Russian
+// range "1–3" while English ranges always use form Other. Locales without
+// plural range rules use the form of to. Falls back to form Other if either
+// quantity isn't supported (see Quantity) or if the selected form is empty.
+// Readers usually implement PluralRange as:
+//
+//	fmt.Sprintf(templates.RangeForm(r.Translator(), from, to), from, to)
+func (f Forms) RangeForm(tr locales.Translator, from, to any) string {
+	a, okFrom := Quantity(from)
+	b, okTo := Quantity(to)
+	if !okFrom || !okTo {
+		return f.Other
+	}
+	r := tr.RangePluralRule(a, 0, b, 0)
+	if r == locales.PluralRuleUnknown {
+		r = tr.CardinalPluralRule(b, 0)
+	}
+	if s := f.form(r); s != "" {
+		return s
+	}
+	return f.Other
+}
+
+// CardinalForm returns the form of f for quantity selected by the CLDR
+// cardinal plural rules of tr. Falls back to form Other if quantity isn't
+// supported (see Quantity) or if the selected form is empty.
+func (f Forms) CardinalForm(tr locales.Translator, quantity any) string {
+	q, ok := Quantity(quantity)
+	if !ok {
+		return f.Other
+	}
+	if s := f.form(tr.CardinalPluralRule(q, 0)); s != "" {
+		return s
+	}
+	return f.Other
+}
+
+// Forms returns the forms of f for the ordinal quantity ordinal selected by
+// the CLDR ordinal plural rules of tr, such as One for English "1st" and
+// "21st" while German ordinals always use Other. Falls back to Other if
+// ordinal isn't supported (see Quantity) or if the selected forms are empty.
+// Readers usually implement PluralOrdinal as:
+//
+//	f := templates.Forms(r.Translator(), ordinal)
+//	fmt.Sprintf(f.CardinalForm(r.Translator(), quantity), ordinal, quantity)
+func (f OrdinalForms) Forms(tr locales.Translator, ordinal any) Forms {
+	q, ok := Quantity(ordinal)
+	if !ok {
+		return f.Other
+	}
+	var forms Forms
+	switch tr.OrdinalPluralRule(q, 0) {
+	case locales.PluralRuleZero:
+		forms = f.Zero
+	case locales.PluralRuleOne:
+		forms = f.One
+	case locales.PluralRuleTwo:
+		forms = f.Two
+	case locales.PluralRuleFew:
+		forms = f.Few
+	case locales.PluralRuleMany:
+		forms = f.Many
+	}
+	if forms.Other != "" {
+		return forms
+	}
+	return f.Other
+}
+
+// form returns the form of plural rule r.
+func (f Forms) form(r locales.PluralRule) string {
+	switch r {
+	case locales.PluralRuleZero:
+		return f.Zero
+	case locales.PluralRuleOne:
+		return f.One
+	case locales.PluralRuleTwo:
+		return f.Two
+	case locales.PluralRuleFew:
+		return f.Few
+	case locales.PluralRuleMany:
+		return f.Many
+	}
+	return f.Other
+}
@@ -0,0 +1,120 @@
+package localize_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/locales/ar"
+	"github.com/go-playground/locales/br"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/ru"
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestFormsString(t *testing.T) {
+	require.Equal(t, "Forms{}", localize.Forms{}.String())
+	require.Equal(t, `Forms{One: "%d apple", Other: "%d apples"}`,
+		localize.Forms{One: "%d apple", Other: "%d apples"}.String())
+	require.Equal(t, `Forms{Zero: "z", Two: "t", Few: "f", Many: "m"}`,
+		fmt.Sprint(localize.Forms{Zero: "z", Two: "t", Few: "f", Many: "m"}))
+}
+
+func TestFormsComplete(t *testing.T) {
+	english := localize.Forms{One: "%d apple", Other: "%d apples"}
+	require.True(t, english.Complete(en.New()))
+	require.True(t, english.Complete(ja.New()))
+	require.False(t, english.Complete(ru.New()))
+	require.False(t, localize.Forms{One: "%d apple"}.Complete(en.New()))
+	require.False(t, localize.Forms{}.Complete(ja.New()))
+
+	require.True(t, localize.Forms{
+		One: "a", Few: "b", Many: "c", Other: "d",
+	}.Complete(ru.New()))
+	require.True(t, localize.CardinalForms("%d").Complete(ar.New()))
+}
+
+func TestFormsRangeForm(t *testing.T) {
+	russian := localize.Forms{
+		One: "%d–%d день", Few: "%d–%d дня", Many: "%d–%d дней", Other: "%d–%d дня",
+	}
+	require.Equal(t, russian.Few, russian.RangeForm(ru.New(), 1, 3))
+	require.Equal(t, russian.One, russian.RangeForm(ru.New(), 5, 21))
+	require.Equal(t, russian.Many, russian.RangeForm(ru.New(), 1, 5))
+	require.Equal(t, russian.Other, russian.RangeForm(ru.New(), 1, "x"))
+
+	// English ranges always use form Other.
+	english := localize.Forms{One: "%d–%d day", Other: "%d–%d days"}
+	require.Equal(t, english.Other, english.RangeForm(en.New(), 0, 1))
+
+	// Locales without plural range rules use the form of the end.
+	breton := localize.Forms{One: "one", Few: "few", Other: "other"}
+	require.Equal(t, breton.One, breton.RangeForm(br.New(), 2, 21))
+
+	// Empty forms fall back to form Other.
+	require.Equal(t, "%d–%d", localize.Forms{Other: "%d–%d"}.RangeForm(ru.New(), 1, 3))
+}
+
+func TestFormsCardinalForm(t *testing.T) {
+	english := localize.Forms{One: "%d day", Other: "%d days"}
+	require.Equal(t, english.One, english.CardinalForm(en.New(), 1))
+	require.Equal(t, english.Other, english.CardinalForm(en.New(), 2))
+	require.Equal(t, english.Other, english.CardinalForm(en.New(), "x"))
+
+	// Empty forms fall back to form Other.
+	require.Equal(t, "%d", localize.Forms{Other: "%d"}.CardinalForm(ru.New(), 1))
+}
+
+func TestOrdinalFormsForms(t *testing.T) {
+	english := localize.OrdinalForms{
+		One:   localize.Forms{One: "%dst of %d item", Other: "%dst of %d items"},
+		Two:   localize.Forms{One: "%dnd of %d item", Other: "%dnd of %d items"},
+		Few:   localize.Forms{One: "%drd of %d item", Other: "%drd of %d items"},
+		Other: localize.Forms{One: "%dth of %d item", Other: "%dth of %d items"},
+	}
+	require.Equal(t, english.One, english.Forms(en.New(), 21))
+	require.Equal(t, english.Two, english.Forms(en.New(), 2))
+	require.Equal(t, english.Few, english.Forms(en.New(), 3))
+	require.Equal(t, english.Other, english.Forms(en.New(), 11))
+	require.Equal(t, english.Other, english.Forms(en.New(), "x"))
+
+	// Empty forms fall back to Other.
+	require.Equal(t, english.Other, localize.OrdinalForms{
+		Other: english.Other,
+	}.Forms(en.New(), 1))
+
+	// Russian ordinals always use Other.
+	require.Equal(t, english.Other, english.Forms(ru.New(), 1))
+}
+
+func TestValidateForms(t *testing.T) {
+	require.NoError(t, localize.ValidateForms(language.English,
+		localize.Forms{One: "%d apple", Other: "%d apples"}))
+	require.NoError(t, localize.ValidateForms(language.Russian,
+		localize.Forms{One: "%d яблоко", Few: "%d яблока", Other: "%d яблок"}))
+
+	err := localize.ValidateForms(language.English, localize.Forms{One: "%d apple"})
+	require.ErrorIs(t, err, localize.ErrMissingPluralForm)
+
+	err = localize.ValidateForms(language.English,
+		localize.Forms{One: "%d apple", Other: "apples"})
+	require.ErrorIs(t, err, localize.ErrMissingQuantityPlaceholder)
+
+	err = localize.ValidateForms(language.English,
+		localize.Forms{One: "%d apple", Other: "%d apples %d"})
+	require.ErrorIs(t, err, localize.ErrTooManyQuantityPlaceholders)
+
+	err = localize.ValidateForms(language.English,
+		localize.Forms{One: "%s apple", Other: "%d apples"})
+	require.ErrorIs(t, err, localize.ErrWrongPlaceholderVerb)
+
+	err = localize.ValidateForms(language.Japanese,
+		localize.Forms{One: "%d apple", Other: "%d apples"})
+	require.ErrorIs(t, err, localize.ErrUnsupportedPluralForm)
+
+	err = localize.ValidateForms(language.Russian, localize.Forms{Other: "%d"})
+	require.ErrorIs(t, err, localize.ErrMissingPluralForm)
+	require.NotErrorIs(t, err, localize.ErrWrongPlaceholderVerb)
+}
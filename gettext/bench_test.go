@@ -0,0 +1,114 @@
+package gettext_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/stretchr/testify/require"
+)
+
+// makeLargePO returns a catalog of n messages mixing static, plural
+// and multi-line messages.
+func makeLargePO(n int) []byte {
+	var b bytes.Buffer
+	b.WriteString("msgid \"\"\nmsgstr \"\"\n" +
+		"\"Language: de\\n\"\n" +
+		"\"MIME-Version: 1.0\\n\"\n" +
+		"\"Content-Type: text/plain; charset=UTF-8\\n\"\n" +
+		"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n")
+	for i := range n {
+		fmt.Fprintf(&b, "\n#. Description of message %d.\n", i)
+		fmt.Fprintf(&b, "#: app/pkg%d/file.go:%d\n", i%50, i)
+		fmt.Fprintf(&b, "msgctxt \"%016x\"\n", i)
+		switch i % 5 {
+		case 0:
+			fmt.Fprintf(&b, "msgid \"%d item\"\nmsgid_plural \"%%d items\"\n", i)
+			fmt.Fprintf(&b, "msgstr[0] \"%d Element\"\nmsgstr[1] \"%%d Elemente\"\n", i)
+		case 1:
+			fmt.Fprintf(&b, "msgid \"\"\n\"First line %d.\\n\"\n\"Second \\\"line\\\".\"\n", i)
+			fmt.Fprintf(&b, "msgstr \"\"\n\"Erste Zeile %d.\\n\"\n\"Zweite Zeile.\"\n", i)
+		default:
+			fmt.Fprintf(&b, "msgid \"Message number %d\"\n", i)
+			fmt.Fprintf(&b, "msgstr \"Nachricht Nummer %d\"\n", i)
+		}
+	}
+	return b.Bytes()
+}
+
+func BenchmarkDecodePO(b *testing.B) {
+	src := makeLargePO(10_000)
+	d := gettext.NewDecoder()
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := d.DecodePO("bench.po", bytes.NewReader(src)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodePOBytes(b *testing.B) {
+	src := makeLargePO(10_000)
+	d := gettext.NewDecoder()
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := d.DecodePOBytes("bench.po", src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodePO(b *testing.B) {
+	src := makeLargePO(10_000)
+	po, err := gettext.NewDecoder().DecodePO("bench.po", bytes.NewReader(src))
+	require.NoError(b, err)
+	var buf bytes.Buffer
+	buf.Grow(len(src))
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+	for b.Loop() {
+		buf.Reset()
+		if err := (gettext.Encoder{}).EncodePO(po, &buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// raceEnabled is true when testing with -race, which allocates
+// more and would make allocation budgets fail.
+var raceEnabled bool
+
+// TestAllocsBudget fails if decoding or encoding allocates more per message
+// than budgeted, such that performance regressions are caught in CI.
+func TestAllocsBudget(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation budgets don't apply with -race")
+	}
+	const n = 1000
+	const budgetDecode, budgetEncode = 14, 4
+
+	src := makeLargePO(n)
+	d := gettext.NewDecoder()
+	var po gettext.FilePO
+	allocs := testing.AllocsPerRun(10, func() {
+		var err error
+		if po, err = d.DecodePOBytes("budget.po", src); err != nil {
+			t.Fatal(err)
+		}
+	})
+	require.LessOrEqual(t, allocs/n, float64(budgetDecode), "allocs per decoded message")
+
+	var buf bytes.Buffer
+	buf.Grow(len(src))
+	allocs = testing.AllocsPerRun(10, func() {
+		buf.Reset()
+		if err := (gettext.Encoder{}).EncodePO(po, &buf); err != nil {
+			t.Fatal(err)
+		}
+	})
+	require.LessOrEqual(t, allocs/n, float64(budgetEncode), "allocs per encoded message")
+	require.Equal(t, string(src), buf.String())
+}
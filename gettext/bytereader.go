@@ -0,0 +1,77 @@
+package gettext
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// byteReader reads from an in-memory buffer. It implements the subset of
+// the bufio.Reader methods used by the decoder without copying the input,
+// lines returned by ReadLine and Peek reference the buffer directly.
+type byteReader struct {
+	b []byte
+	i int
+}
+
+var errUnreadByte = errors.New("invalid use of UnreadByte")
+
+func (r *byteReader) Reset(b []byte) { r.b, r.i = b, 0 }
+
+func (r *byteReader) ReadByte() (byte, error) {
+	if r.i >= len(r.b) {
+		return 0, io.EOF
+	}
+	b := r.b[r.i]
+	r.i++
+	return b, nil
+}
+
+func (r *byteReader) UnreadByte() error {
+	if r.i < 1 {
+		return errUnreadByte
+	}
+	r.i--
+	return nil
+}
+
+// Peek returns the next n bytes without advancing the reader.
+// Returns the remaining bytes and io.EOF if there are less than n.
+func (r *byteReader) Peek(n int) ([]byte, error) {
+	rest := r.b[r.i:]
+	if len(rest) < n {
+		return rest, io.EOF
+	}
+	return rest[:n], nil
+}
+
+// Rest returns the unread bytes without advancing the reader.
+func (r *byteReader) Rest() []byte { return r.b[r.i:] }
+
+// AtLineStart returns true if the next byte starts a line.
+func (r *byteReader) AtLineStart() bool { return r.i == 0 || r.b[r.i-1] == '\n' }
+
+func (r *byteReader) Read(p []byte) (int, error) {
+	if r.i >= len(r.b) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.b[r.i:])
+	r.i += n
+	return n, nil
+}
+
+// ReadLine returns the next line excluding the line break
+// like bufio.Reader.ReadLine. isPrefix is always false.
+func (r *byteReader) ReadLine() (line []byte, isPrefix bool, err error) {
+	if r.i >= len(r.b) {
+		return nil, false, io.EOF
+	}
+	rest := r.b[r.i:]
+	i := bytes.IndexByte(rest, '\n')
+	if i == -1 {
+		r.i = len(r.b)
+		return rest, false, nil
+	}
+	r.i += i + 1
+	return rest[:i], false, nil
+}
@@ -0,0 +1,83 @@
+package gettext
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// contentTypeUTF8 is the only Content-Type of decoded and encoded files.
+// Files of other charsets are transcoded to UTF-8 on decode.
+const contentTypeUTF8 = "text/plain; charset=UTF-8"
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	crlf       = []byte("\r\n")
+	lf         = []byte("\n")
+)
+
+// regexCharset matches the charset parameter of the Content-Type header.
+var regexCharset = regexp.MustCompile(
+	`"Content-Type:\s*text/plain;\s*charset=([A-Za-z0-9._:+-]+)`,
+)
+
+// normalize transcodes b to UTF-8, removes the UTF-8 byte order mark
+// and replaces CRLF line endings with LF. b is never modified,
+// a copy is made if necessary.
+// The removed byte order mark and CRLF line endings are recorded in format.
+// charset is the name of the original charset or empty if b is UTF-8.
+func normalize(b []byte) (
+	normalized []byte, format Format, charset string, err error,
+) {
+	b, charset, err = toUTF8(b)
+	if err != nil {
+		return nil, format, "", err
+	}
+	if bytes.HasPrefix(b, bomUTF8) {
+		format.BOM = true
+		b = b[len(bomUTF8):]
+	}
+	if bytes.Contains(b, crlf) {
+		format.CRLF = true
+		b = bytes.ReplaceAll(b, crlf, lf)
+	}
+	return b, format, charset, nil
+}
+
+// toUTF8 transcodes b to UTF-8.
+// UTF-16 is detected by its byte order mark, all other charsets are detected
+// by the charset parameter of the Content-Type header of the head message.
+// charset is the name of the original charset or empty if b is UTF-8.
+func toUTF8(b []byte) (utf8 []byte, charset string, err error) {
+	var enc encoding.Encoding
+	switch {
+	case bytes.HasPrefix(b, bomUTF16BE):
+		charset = "UTF-16BE"
+		enc = unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)
+	case bytes.HasPrefix(b, bomUTF16LE):
+		charset = "UTF-16LE"
+		enc = unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+	default:
+		m := regexCharset.FindSubmatch(b)
+		if m == nil || strings.EqualFold(string(m[1]), "UTF-8") {
+			return b, "", nil
+		}
+		charset = string(m[1])
+		enc, err = ianaindex.IANA.Encoding(charset)
+		if err != nil || enc == nil {
+			return nil, "", fmt.Errorf("%w: %q", ErrUnsupportedCharset, charset)
+		}
+	}
+
+	if b, err = enc.NewDecoder().Bytes(b); err != nil {
+		return nil, "", fmt.Errorf("transcoding from %s: %w", charset, err)
+	}
+	return b, charset, nil
+}
@@ -1,7 +1,6 @@
 package gettext
 
 import (
-	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
@@ -10,6 +9,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
+	"unsafe"
 
 	"golang.org/x/text/language"
 )
@@ -19,7 +20,33 @@ func (d *Decoder) err(expected string) Error {
 }
 
 type Decoder struct {
-	reader *bufio.Reader
+	// SplitLines normalizes the texts of decoded messages to one string
+	// literal per line (see StringLiterals.SplitLines), such that files
+	// wrapped differently by other tools decode to the same literals.
+	SplitLines bool
+
+	// SplitReferences decodes reference comments listing multiple
+	// references separated by spaces, as written by GNU gettext tools,
+	// into one comment per reference.
+	SplitReferences bool
+
+	// Salvage skips malformed messages instead of failing to decode the
+	// whole file, such that all valid messages of a file broken by a single
+	// malformed entry are still decoded. A malformed message is skipped up to
+	// the next blank line or line starting another message. The errors of
+	// the skipped messages are returned by Skipped. Malformed heads still
+	// fail decoding.
+	Salvage bool
+
+	// skipped are the errors of the messages skipped by the last decode.
+	skipped []Error
+
+	// interrupted is the error of the malformed message following the last
+	// message read in salvage mode, which is skipped before reading
+	// the next message.
+	interrupted *Error
+
+	reader byteReader
 	pos    Position
 
 	// pending is the a pending directive that was successfuly read from
@@ -30,34 +57,82 @@ type Decoder struct {
 	pending directive
 
 	pluralsN uint8
-}
 
-func NewDecoder() *Decoder {
-	return &Decoder{
-		reader: bufio.NewReader(nil),
-	}
+	// charset is the charset the input was transcoded from to UTF-8
+	// or empty if the input is UTF-8.
+	charset string
 }
 
+func NewDecoder() *Decoder { return &Decoder{} }
+
+// Skipped returns the errors of the malformed messages skipped by
+// the last decode in the order of their occurrence (see Decoder.Salvage).
+func (d *Decoder) Skipped() []Error { return d.skipped }
+
 // DecodePO decodes a .po translation file from r.
 func (d *Decoder) DecodePO(fileName string, r io.Reader) (FilePO, error) {
-	f, err := d.decode(fileName, r, false)
-	return FilePO{File: f}, err
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return FilePO{}, Error{Pos: Position{Filename: fileName, Line: 1, Column: 1}, Err: err}
+	}
+	return d.DecodePOBytes(fileName, b)
 }
 
 // DecodePOT decodes a .pot template file from r.
 func (d *Decoder) DecodePOT(fileName string, r io.Reader) (FilePOT, error) {
-	f, err := d.decode(fileName, r, true)
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return FilePOT{}, Error{Pos: Position{Filename: fileName, Line: 1, Column: 1}, Err: err}
+	}
+	return d.DecodePOTBytes(fileName, b)
+}
+
+// DecodePOBytes is like DecodePO but decodes a .po translation file
+// that is already in memory avoiding an additional copy.
+// b isn't modified and isn't retained after DecodePOBytes returns.
+func (d *Decoder) DecodePOBytes(fileName string, b []byte) (FilePO, error) {
+	f, err := d.decode(fileName, b, false)
+	return FilePO{File: f}, err
+}
+
+// DecodePOTBytes is like DecodePOT but decodes a .pot template file
+// that is already in memory avoiding an additional copy.
+// b isn't modified and isn't retained after DecodePOTBytes returns.
+func (d *Decoder) DecodePOTBytes(fileName string, b []byte) (FilePOT, error) {
+	f, err := d.decode(fileName, b, true)
 	return FilePOT{File: f}, err
 }
 
-func (d *Decoder) decode(fileName string, r io.Reader, template bool) (*File, error) {
+// DecodePOString is like DecodePOBytes but decodes a .po translation file
+// from string s without copying it.
+func (d *Decoder) DecodePOString(fileName, s string) (FilePO, error) {
+	return d.DecodePOBytes(fileName, unsafe.Slice(unsafe.StringData(s), len(s)))
+}
+
+// DecodePOTString is like DecodePOTBytes but decodes a .pot template file
+// from string s without copying it.
+func (d *Decoder) DecodePOTString(fileName, s string) (FilePOT, error) {
+	return d.DecodePOTBytes(fileName, unsafe.Slice(unsafe.StringData(s), len(s)))
+}
+
+func (d *Decoder) decode(fileName string, b []byte, template bool) (*File, error) {
 	// Reset the decoder.
-	d.reader.Reset(r)
 	d.pos.Filename, d.pos.Index, d.pos.Line, d.pos.Column = fileName, 0, 1, 1
 	d.pending.directiveType = 0
+	d.skipped, d.interrupted = nil, nil
+	b, format, charset, err := normalize(b)
+	if err != nil {
+		return nil, Error{Pos: d.pos, Err: err}
+	}
+	d.charset = charset
+	d.reader.Reset(b)
+	defer d.reader.Reset(nil)
 
 	// Start by reading the head message.
-	var f File
+	f := File{Format: format}
+	// Every message has exactly one msgid, preallocate to avoid
+	// copying the large message structs while growing the list.
+	f.Messages.List = make([]Message, 0, bytes.Count(b, prefixMsgid))
 	mHead, err := d.readMessage()
 	if err != nil {
 		return nil, err
@@ -70,6 +145,10 @@ func (d *Decoder) decode(fileName string, r io.Reader, template bool) (*File, er
 	d.pluralsN = f.Head.PluralForms.N
 
 	for {
+		if d.interrupted != nil {
+			d.skip(*d.interrupted)
+			d.interrupted = nil
+		}
 		err := d.readOptionalWhitespace()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
@@ -80,32 +159,96 @@ func (d *Decoder) decode(fileName string, r io.Reader, template bool) (*File, er
 
 		m, err := d.readMessage()
 		if err != nil {
-			return nil, err
+			var e Error
+			if !d.Salvage || !errors.As(err, &e) {
+				return nil, err
+			}
+			d.skip(e)
+			continue
+		}
+		if d.SplitLines {
+			for _, t := range [...]*StringLiterals{
+				&m.Msgctxt.Text, &m.Msgid.Text, &m.MsgidPlural.Text,
+				&m.Msgstr.Text, &m.Msgstr0.Text, &m.Msgstr1.Text, &m.Msgstr2.Text,
+				&m.Msgstr3.Text, &m.Msgstr4.Text, &m.Msgstr5.Text,
+			} {
+				*t = t.SplitLines()
+			}
 		}
 		f.Messages.List = append(f.Messages.List, m)
 	}
 
 	// If a message is still pending then we encountered an unexpected EOF.
+	if err, ok := d.pendingErr(); ok {
+		if !d.Salvage {
+			return nil, err
+		}
+		d.skipped = append(d.skipped, err)
+	}
+
+	return &f, nil
+}
+
+// pendingErr returns the error of the message still pending at EOF.
+// ok is false if no message is pending.
+func (d *Decoder) pendingErr() (err Error, ok bool) {
 	switch d.pending.directiveType {
 	case 0:
 		// OK, no pending message.
+		return Error{}, false
 	case directiveTypeMsgctxt:
-		return nil, d.err("msgid")
+		return d.err("msgid"), true
 	case directiveTypeMsgid:
-		return nil, d.err("msgid_plural or msgstr")
+		return d.err("msgid_plural or msgstr"), true
 	case directiveTypeMsgidPlural:
-		return nil, d.err("msgstr[0]")
-	case directiveTypeMsgstr:
-		return nil, d.err("msgid or mstctxt")
+		return d.err("msgstr[0]"), true
 	case directiveTypeMsgstrIndexed:
 		if d.pending.pluralFormIndex < 5 {
-			return nil, d.err(fmt.Sprintf("msgstr[%d]",
-				d.pending.pluralFormIndex+1))
+			return d.err(fmt.Sprintf("msgstr[%d]", d.pending.pluralFormIndex+1)), true
 		}
-		return nil, d.err("msgid or mstctxt")
 	}
+	return d.err("msgid or mstctxt"), true
+}
 
-	return &f, nil
+// skip records err and skips the malformed message it occurred in.
+func (d *Decoder) skip(err Error) {
+	d.skipped = append(d.skipped, err)
+	d.skipMessage()
+}
+
+// skipMessage skips the rest of the current line and all following lines
+// up to the next blank line or line starting a message.
+func (d *Decoder) skipMessage() {
+	d.pending.directiveType = 0
+	if !d.reader.AtLineStart() {
+		d.skipLine()
+	}
+	for !startsMessage(d.reader.Rest()) {
+		d.skipLine()
+	}
+}
+
+// skipLine skips the rest of the current line.
+func (d *Decoder) skipLine() {
+	before := len(d.reader.Rest())
+	line, _, _ := d.reader.ReadLine()
+	if before-len(d.reader.Rest()) > len(line) {
+		d.pos.Index += uint32(len(line))
+		d.advanceLine()
+	} else {
+		d.advanceByte(uint32(len(line)))
+	}
+}
+
+// startsMessage returns true if b is empty or starts with a blank line
+// or a line starting a message.
+func startsMessage(b []byte) bool {
+	if i := bytes.IndexByte(b, '\n'); i != -1 {
+		b = b[:i]
+	}
+	b = bytes.TrimSpace(b)
+	return len(b) == 0 || b[0] == '#' ||
+		bytes.HasPrefix(b, prefixMsgctxt) || bytes.HasPrefix(b, prefixMsgid)
 }
 
 func (d *Decoder) advanceByte(n uint32) {
@@ -241,21 +384,36 @@ func (d *Decoder) readComment() (Comment, error) {
 func (d *Decoder) readComments(obsolete bool) (Comments, error) {
 	start := d.pos
 	var l Comments
+	if !obsolete && d.nextIsObsolete() {
+		// The comments belong to the following obsolete message.
+		return Comments{}, errEndOfMessage
+	}
 	for {
 		next, err := d.reader.Peek(4)
 		if err != nil {
 			return Comments{}, err
 		}
 		if obsolete {
-			if string(next) != "#~ #" {
+			switch {
+			case string(next) == "#~ #":
+				if err := d.readPrefixObsolete(); err != nil {
+					return Comments{}, err
+				}
+			case string(next[:3]) == "#~|":
+				// Previous is unsupported yet.
+				line, _, err := d.reader.ReadLine()
+				if err != nil {
+					return Comments{}, err
+				}
+				d.advanceByte(uint32(len(line)))
+				d.advanceLine()
+				continue
+			case string(next[:2]) == "#~" || next[0] != '#':
 				// Not a comment on an obsolete message.
 				return l, nil
 			}
-			if err := d.readPrefixObsolete(); err != nil {
-				return Comments{}, err
-			}
-		} else if len(next) > 1 && string(next[:2]) == "#~" {
-			return Comments{}, errEndOfMessage
+			// Comments preceding obsolete directives without the obsolete
+			// prefix are written by GNU gettext tools.
 		}
 
 		c, err := d.readComment()
@@ -265,12 +423,39 @@ func (d *Decoder) readComments(obsolete bool) (Comments, error) {
 		if c.Type == 0 {
 			break
 		}
+		if c.Type == CommentTypeReference && d.SplitReferences {
+			if refs := strings.Fields(c.Value); len(refs) > 1 {
+				for _, ref := range refs {
+					l.Text = append(l.Text, Comment{Span: c.Span, Type: c.Type, Value: ref})
+				}
+				continue
+			}
+		}
 		l.Text = append(l.Text, c)
 	}
 	l.Span = d.span(start)
 	return l, nil
 }
 
+// nextIsObsolete returns true if the next line that's not a comment
+// starts with the obsolete prefix "#~".
+func (d *Decoder) nextIsObsolete() bool {
+	rest := d.reader.Rest()
+	for {
+		if bytes.HasPrefix(rest, prefixObsolete) {
+			return true
+		}
+		if len(rest) < 1 || rest[0] != '#' {
+			return false
+		}
+		i := bytes.IndexByte(rest, '\n')
+		if i == -1 {
+			return false
+		}
+		rest = rest[i+1:]
+	}
+}
+
 func (d *Decoder) readPrefixObsolete() error {
 	b, err := d.reader.ReadByte()
 	if err != nil {
@@ -348,6 +533,7 @@ func (d *Decoder) parseHead(m Message, template bool) (h FileHead, err error) {
 		if err := checkHeaderDuplicate(pos, byName, name); err != nil {
 			return h, err
 		}
+		h.Order = append(h.Order, name)
 		switch name {
 		case "Project-Id-Version":
 			h.ProjectIdVersion = value
@@ -363,16 +549,17 @@ func (d *Decoder) parseHead(m Message, template bool) (h FileHead, err error) {
 			h.LanguageTeam = value
 		case "Language":
 			h.Language.Value = value
+			h.Language.Span = headerSpan(m.Msgstr, header)
 			if template && h.Language.Value != "" {
 				return h, Error{
-					Pos: pos,
+					Pos: h.Language.Position,
 					Err: ErrLanguageInTemplate,
 				}
 			} else {
 				locale, err := language.Parse(h.Language.Value)
 				if err != nil {
 					return h, Error{
-						Pos: pos,
+						Pos: h.Language.Position,
 						Err: ErrMalformedHeaderLanguage,
 					}
 				}
@@ -388,13 +575,18 @@ func (d *Decoder) parseHead(m Message, template bool) (h FileHead, err error) {
 			}
 		case "Content-Type":
 			h.ContentType = value
-			if _, _, err := mime.ParseMediaType(h.ContentType); err != nil {
+			mediaType, _, err := mime.ParseMediaType(h.ContentType)
+			if err != nil {
 				return h, Error{
 					Pos: pos,
 					Err: ErrMalformedHeaderContentType,
 				}
 			}
-			if h.ContentType != "text/plain; charset=UTF-8" {
+			if d.charset != "" && mediaType == "text/plain" {
+				// The input was transcoded to UTF-8.
+				h.ContentType = contentTypeUTF8
+			}
+			if h.ContentType != contentTypeUTF8 {
 				return h, Error{
 					Pos: pos,
 					Err: ErrUnsupportedContentType,
@@ -437,6 +629,7 @@ func (d *Decoder) parseHead(m Message, template bool) (h FileHead, err error) {
 		}
 	}
 
+	h.Span = m.Span
 	h.HeadComments = m.Msgid.Comments
 
 	return h, nil
@@ -448,6 +641,7 @@ var (
 	prefixMsgidPlural   = []byte("msgid_plural ")
 	prefixMsgstr        = []byte("msgstr ")
 	prefixMsgstrIndexed = []byte("msgstr[")
+	prefixObsolete      = []byte("#~")
 )
 
 func (d *Decoder) readMessage() (m Message, err error) {
@@ -494,20 +688,13 @@ func (d *Decoder) readMessage() (m Message, err error) {
 	}()
 
 	start := d.pos
-	next, err := d.reader.Peek(2)
-	m.Obsolete = err == nil && string(next) == "#~"
+	m.Obsolete = d.nextIsObsolete()
 
 LOOP:
 	for {
-		if m.Obsolete {
-			next, err := d.reader.Peek(2)
-			if err != nil {
-				return m, err
-			}
-			if string(next) != "#~" {
-				// End of obsolete message
-				return m, errEndOfMessage
-			}
+		if m.Obsolete && !d.nextIsObsolete() {
+			// End of obsolete message
+			return m, errEndOfMessage
 		}
 		if err := d.readOptionalWhitespace(); err != nil {
 			if !errors.Is(err, io.EOF) {
@@ -520,6 +707,17 @@ LOOP:
 		if d.pending.directiveType == 0 {
 			dir, err = d.readDirective(m.Obsolete)
 			if err != nil {
+				if d.Salvage && (previous == directiveTypeMsgstr ||
+					previous == directiveTypeMsgstrIndexed &&
+						previousPluralFormIndex+1 >= d.pluralsN) {
+					if e := new(Error); errors.As(err, e) {
+						// Keep the complete message, the malformed message
+						// following it is skipped by decode.
+						d.interrupted = e
+						m.Span = d.span(start)
+						return m, nil
+					}
+				}
 				return m, err
 			}
 		} else {
@@ -753,21 +951,19 @@ func (d *Decoder) readDirective(obsolete bool) (dir directive, err error) {
 	}
 
 	// Multi-line
-	b, err := d.peekByte()
-	if errors.Is(err, io.EOF) || err == nil && b != '"' {
+	multiLine, err := d.nextIsStringLiteral(obsolete)
+	if err != nil {
+		return directive{}, err
+	}
+	if !multiLine {
 		// Empty string
 		dir.text = StringLiterals{
-			Span: d.span(strStart),
-			Lines: []StringLiteral{{
-				Span:  d.span(strStart),
-				Value: "",
-			}},
+			Span:  d.span(strStart),
+			Lines: []StringLiteral{str},
 		}
+		dir.Span = d.span(start)
 		return dir, nil
 	}
-	if err != nil {
-		return directive{}, err
-	}
 
 	strings, err := d.readStringLiterals(obsolete)
 	if err != nil {
@@ -779,56 +975,52 @@ func (d *Decoder) readDirective(obsolete bool) (dir directive, err error) {
 	return dir, nil
 }
 
+// readStringLiterals reads the continuation lines of a multi-line string.
+// On obsolete messages each continuation line must be prefixed with "#~ ".
+// The obsolete prefix is only consumed if it's followed by a string literal
+// to not consume the prefix of the next directive or comment.
 func (d *Decoder) readStringLiterals(obsolete bool) (strings StringLiterals, err error) {
 	start := d.pos
-
-	if obsolete {
-		if err := d.readPrefixObsolete(); err != nil {
-			return strings, err
-		}
-	}
-
-	next, err := d.reader.Peek(1)
-	if err != nil {
-		return strings, err
-	}
-	if next[0] != '"' {
-		return strings, d.err("string literal")
-	}
-
 	for {
-		next, err := d.reader.Peek(1)
+		ok, err := d.nextIsStringLiteral(obsolete)
 		if err != nil {
 			return strings, err
 		}
-		if next[0] != '"' {
+		if !ok {
 			break
 		}
-		str, err := d.readStringLiteral()
-		if err != nil {
-			return strings, err
-		}
-		strings.Lines = append(strings.Lines, str)
-
 		if obsolete {
-			next, err := d.reader.Peek(2)
-			if err != nil {
-				return strings, err
-			}
-			if string(next) != "#~" {
-				// End of obsolete string literals.
-				return strings, nil
-			}
-
 			if err := d.readPrefixObsolete(); err != nil {
 				return strings, err
 			}
 		}
+		str, err := d.readStringLiteral()
+		if err != nil {
+			return strings, err
+		}
+		strings.Lines = append(strings.Lines, str)
+	}
+	if len(strings.Lines) < 1 {
+		return strings, d.err("string literal")
 	}
 	strings.Span = d.span(start)
 	return strings, nil
 }
 
+// nextIsStringLiteral returns true if the next line is a string literal
+// continuation line, which must be prefixed with "#~ " if obsolete.
+func (d *Decoder) nextIsStringLiteral(obsolete bool) (bool, error) {
+	prefix := `"`
+	if obsolete {
+		prefix = `#~ "`
+	}
+	next, err := d.reader.Peek(len(prefix))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	return string(next) == prefix, nil
+}
+
 func (d *Decoder) readStringLiteral() (StringLiteral, error) {
 	start := d.pos
 	line, _, err := d.reader.ReadLine()
@@ -837,14 +1029,19 @@ func (d *Decoder) readStringLiteral() (StringLiteral, error) {
 	}
 	var s StringLiteral
 
-	trimmed := strings.TrimSpace(string(line))
+	trimmed := bytes.TrimSpace(line)
 
 	if len(trimmed) < 2 || trimmed[0] != '"' || trimmed[len(trimmed)-1] != '"' {
 		return StringLiteral{}, d.err("string literal")
 	}
 
-	unquoted, err := strconv.Unquote(trimmed)
-	if err != nil {
+	var unquoted string
+	if inner := trimmed[1 : len(trimmed)-1]; bytes.IndexAny(inner, `\"`) == -1 &&
+		utf8.Valid(inner) {
+		// Fast path for literals without escape sequences,
+		// which strconv.Unquote would accept unchanged.
+		unquoted = string(inner)
+	} else if unquoted, err = strconv.Unquote(string(trimmed)); err != nil {
 		return StringLiteral{}, Error{
 			Pos:      d.pos,
 			Expected: "string literal",
@@ -920,6 +1117,17 @@ func checkHeaderDuplicate(p Position, byName map[string]struct{}, name string) e
 	return nil
 }
 
+// headerSpan returns the span of the string literal of head defining header,
+// or the span of head if no single literal defines it.
+func headerSpan(head Msgstr, header string) Span {
+	for _, l := range head.Text.Lines {
+		if strings.TrimSuffix(l.Value, "\n") == header {
+			return l.Span
+		}
+	}
+	return head.Span
+}
+
 func splitHeader(s string) (name, value string) {
 	i := strings.IndexByte(s, ':')
 	if i == -1 {
@@ -1,12 +1,55 @@
 package gettext
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
+	"slices"
+	"strconv"
 	"strings"
 )
 
-type Encoder struct{}
+type Encoder struct {
+	// BOM makes the encoder start files with a UTF-8 byte order mark.
+	BOM bool
+
+	// CRLF makes the encoder use CRLF instead of LF line endings.
+	CRLF bool
+
+	// PreserveFormat makes the encoder preserve the byte order mark
+	// and CRLF line endings of decoded files (see File.Format).
+	PreserveFormat bool
+
+	// SplitLines makes the encoder write texts containing line breaks
+	// as one string literal per line like GNU gettext does
+	// (see StringLiterals.SplitLines).
+	SplitLines bool
+
+	// Wrap is the maximum line width in bytes like the --width option of
+	// GNU gettext tools. Longer texts are written as multiple string literals
+	// broken after spaces. Words longer than Wrap aren't broken.
+	// Texts aren't wrapped if Wrap is 0.
+	Wrap int
+
+	// CompactReferences makes the encoder write consecutive reference
+	// comments separated by spaces on a single "#: " line like GNU gettext
+	// tools do. Lines are broken before exceeding Wrap if set.
+	CompactReferences bool
+
+	// SortHeaders makes the encoder write standard headers first in a fixed
+	// order followed by non-standard headers sorted by name instead of
+	// in the decoded order (see FileHead.Order).
+	SortHeaders bool
+
+	// KeepObsoleteInTemplates makes the encoder write obsolete (#~) messages
+	// of `.pot` files (see POTOptions.KeepObsolete),
+	// which are skipped by default.
+	KeepObsoleteInTemplates bool
+
+	// buf is reused to quote string literals.
+	buf []byte
+}
 
 // Encode encodes a `.po` translation file to w.
 func (e Encoder) EncodePO(f FilePO, w io.Writer) error {
@@ -18,74 +61,64 @@ func (e Encoder) EncodePOT(f FilePOT, w io.Writer) error {
 	return e.encode(f.File, w, true)
 }
 
-func (e Encoder) encode(f *File, w io.Writer, template bool) error {
-	if err := e.encodeComments(w, f.Head.HeadComments, false); err != nil {
-		return err
+// EncodePOToBytes is like EncodePO but returns the encoded file.
+func (e Encoder) EncodePOToBytes(f FilePO) ([]byte, error) {
+	var b bytes.Buffer
+	if err := e.encode(f.File, &b, false); err != nil {
+		return nil, err
 	}
+	return b.Bytes(), nil
+}
 
-	if _, err := fmt.Fprint(w, "msgid \"\"\nmsgstr \"\"\n"); err != nil {
-		return err
+// EncodePOTToBytes is like EncodePOT but returns the encoded file.
+func (e Encoder) EncodePOTToBytes(f FilePOT) ([]byte, error) {
+	var b bytes.Buffer
+	if err := e.encode(f.File, &b, true); err != nil {
+		return nil, err
 	}
+	return b.Bytes(), nil
+}
 
-	if f.Head.ProjectIdVersion != "" {
-		if _, err := fmt.Fprintf(w, "\"Project-Id-Version: %s\\n\"\n",
-			f.Head.ProjectIdVersion); err != nil {
-			return err
-		}
-	}
-	if f.Head.ReportMsgidBugsTo != "" {
-		if _, err := fmt.Fprintf(w, "\"Report-Msgid-Bugs-To: %s\\n\"\n",
-			f.Head.ReportMsgidBugsTo); err != nil {
-			return err
-		}
-	}
-	if f.Head.POTCreationDate != "" {
-		if _, err := fmt.Fprintf(w, "\"POT-Creation-Date: %s\\n\"\n",
-			f.Head.POTCreationDate); err != nil {
-			return err
-		}
-	}
-	if f.Head.PORevisionDate != "" {
-		if _, err := fmt.Fprintf(w, "\"PO-Revision-Date: %s\\n\"\n",
-			f.Head.PORevisionDate); err != nil {
-			return err
-		}
-	}
-	if f.Head.LastTranslator != "" {
-		if _, err := fmt.Fprintf(w, "\"Last-Translator: %s\\n\"\n",
-			f.Head.LastTranslator); err != nil {
-			return err
-		}
-	}
-	if f.Head.LanguageTeam != "" {
-		if _, err := fmt.Fprintf(w, "\"Language-Team: %s\\n\"\n",
-			f.Head.LanguageTeam); err != nil {
-			return err
-		}
-	}
-	if f.Head.Language.Value != "" {
-		if _, err := fmt.Fprintf(w, "\"Language: %s\\n\"\n",
-			f.Head.Language.Value); err != nil {
+// EncodePOToString is like EncodePO but returns the encoded file.
+func (e Encoder) EncodePOToString(f FilePO) (string, error) {
+	var b strings.Builder
+	err := e.encode(f.File, &b, false)
+	return b.String(), err
+}
+
+// EncodePOTToString is like EncodePOT but returns the encoded file.
+func (e Encoder) EncodePOTToString(f FilePOT) (string, error) {
+	var b strings.Builder
+	err := e.encode(f.File, &b, true)
+	return b.String(), err
+}
+
+func (e Encoder) encode(f *File, out io.Writer, template bool) error {
+	if e.BOM || e.PreserveFormat && f.Format.BOM {
+		if _, err := out.Write(bomUTF8); err != nil {
 			return err
 		}
 	}
-	if _, err := fmt.Fprintf(w, "\"MIME-Version: %s\\n\"\n",
-		f.Head.MIMEVersion); err != nil {
-		return err
+	if e.CRLF || e.PreserveFormat && f.Format.CRLF {
+		out = crlfWriter{out}
 	}
-	if _, err := fmt.Fprintf(w, "\"Content-Type: %s\\n\"\n",
-		f.Head.ContentType); err != nil {
+	w := bufio.NewWriter(out)
+	if err := e.encodeFile(f, w, template); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "\"Content-Transfer-Encoding: %s\\n\"\n",
-		f.Head.ContentTransferEncoding); err != nil {
+	return w.Flush()
+}
+
+func (e *Encoder) encodeFile(f *File, w *bufio.Writer, template bool) error {
+	if err := e.encodeComments(w, f.Head.HeadComments, false); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "\"Plural-Forms: nplurals=%d; plural=%s;\\n\"\n",
-		f.Head.PluralForms.N, f.Head.PluralForms.Expression); err != nil {
+
+	if _, err := fmt.Fprint(w, "msgid \"\"\nmsgstr \"\"\n"); err != nil {
 		return err
 	}
-	for _, h := range f.Head.NonStandard {
+
+	for _, h := range e.headers(f.Head) {
 		if _, err := fmt.Fprintf(w, "\"%s: %s\\n\"\n", h.Name, h.Value); err != nil {
 			return err
 		}
@@ -94,8 +127,9 @@ func (e Encoder) encode(f *File, w io.Writer, template bool) error {
 		return err
 	}
 
+	skipObsolete := template && !e.KeepObsoleteInTemplates
 	for i, m := range f.Messages.List {
-		if template && m.Obsolete {
+		if skipObsolete && m.Obsolete {
 			// Don't encode obsolete messages in .pot files
 			continue
 		}
@@ -150,7 +184,7 @@ func (e Encoder) encode(f *File, w io.Writer, template bool) error {
 		); err != nil {
 			return err
 		}
-		if hasNextNonObsolete(f.Messages.List[i+1:], template) {
+		if hasNextNonObsolete(f.Messages.List[i+1:], skipObsolete) {
 			if _, err := fmt.Fprintln(w); err != nil {
 				return err
 			}
@@ -160,8 +194,21 @@ func (e Encoder) encode(f *File, w io.Writer, template bool) error {
 	return nil
 }
 
-func (e *Encoder) encodeComments(w io.Writer, c Comments, obsolete bool) error {
-	for _, c := range c.Text {
+func (e *Encoder) encodeComments(w io.Writer, comments Comments, obsolete bool) error {
+	for i := 0; i < len(comments.Text); i++ {
+		c := comments.Text[i]
+		if c.Type == CommentTypeReference && e.CompactReferences {
+			n := 1
+			for i+n < len(comments.Text) &&
+				comments.Text[i+n].Type == CommentTypeReference {
+				n++
+			}
+			if err := e.printReferences(w, comments.Text[i:i+n], obsolete); err != nil {
+				return err
+			}
+			i += n - 1
+			continue
+		}
 		if obsolete {
 			if _, err := fmt.Fprint(w, "#~ "); err != nil {
 				return err
@@ -196,6 +243,31 @@ func (e *Encoder) encodeComments(w io.Writer, c Comments, obsolete bool) error {
 	return nil
 }
 
+// printReferences writes refs separated by spaces on as few lines as
+// possible without exceeding Wrap if set.
+func (e *Encoder) printReferences(w io.Writer, refs []Comment, obsolete bool) error {
+	prefix := "#: "
+	if obsolete {
+		prefix = "#~ #: "
+	}
+	line := prefix
+	for i, r := range refs {
+		if i > 0 {
+			if e.Wrap > 0 && len(line)+1+len(r.Value) > e.Wrap {
+				if _, err := io.WriteString(w, line+"\n"); err != nil {
+					return err
+				}
+				line = prefix
+			} else {
+				line += " "
+			}
+		}
+		line += r.Value
+	}
+	_, err := io.WriteString(w, line+"\n")
+	return err
+}
+
 func printLines(w io.Writer, prefix, s string) error {
 	for len(s) > 0 {
 		i := strings.IndexByte(s, '\n')
@@ -205,10 +277,13 @@ func printLines(w io.Writer, prefix, s string) error {
 		} else {
 			line, s = s[:i], s[i+1:]
 		}
-		if _, err := fmt.Fprint(w, prefix); err != nil {
+		if _, err := io.WriteString(w, prefix); err != nil {
 			return err
 		}
-		if _, err := fmt.Fprintln(w, line); err != nil {
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
 			return err
 		}
 	}
@@ -222,6 +297,21 @@ func (e *Encoder) printDirective(
 		// Nothing to write
 		return nil
 	}
+	if e.SplitLines {
+		text = text.SplitLines()
+	}
+	// fits is false if the text must start on its own line
+	// to not exceed the line width.
+	fits := true
+	if e.Wrap > 0 {
+		width := e.Wrap
+		if obsolete {
+			width -= len("#~ ")
+		}
+		text = wrap(text, width)
+		fits = len(text.Lines) == 1 &&
+			len(name)+1+len(strconv.Quote(text.Lines[0].Value)) <= width
+	}
 	if err := e.encodeComments(w, comments, obsolete); err != nil {
 		return err
 	}
@@ -233,12 +323,8 @@ func (e *Encoder) printDirective(
 	if _, err := fmt.Fprint(w, name); err != nil {
 		return err
 	}
-	if len(text.Lines) == 1 {
-		if _, err := fmt.Fprintf(w, " %q\n",
-			text.Lines[0].Value); err != nil {
-			return err
-		}
-		return nil
+	if len(text.Lines) == 1 && fits {
+		return e.printQuoted(w, " ", text.Lines[0].Value)
 	}
 
 	// Multi-line
@@ -251,18 +337,77 @@ func (e *Encoder) printDirective(
 				return err
 			}
 		}
-		if _, err := fmt.Fprintf(w, "%q\n", l.Value); err != nil {
+		if err := e.printQuoted(w, "", l.Value); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func hasNextNonObsolete(msgs []Message, template bool) bool {
+// printQuoted writes prefix followed by s quoted like %q and a line break.
+func (e *Encoder) printQuoted(w io.Writer, prefix, s string) error {
+	e.buf = append(e.buf[:0], prefix...)
+	e.buf = strconv.AppendQuote(e.buf, s)
+	e.buf = append(e.buf, '\n')
+	_, err := w.Write(e.buf)
+	return err
+}
+
+// wrap returns text with literals exceeding width when quoted
+// broken after spaces. Words exceeding width aren't broken.
+func wrap(text StringLiterals, width int) StringLiterals {
+	wrapped := make([]StringLiteral, 0, len(text.Lines))
+	for _, l := range text.Lines {
+		line := ""
+		for s := l.Value; s != ""; {
+			// word is the next word including its trailing spaces.
+			end := strings.IndexByte(s, ' ')
+			if end == -1 {
+				end = len(s)
+			}
+			for end < len(s) && s[end] == ' ' {
+				end++
+			}
+			word := s[:end]
+			s = s[end:]
+			if line != "" && len(strconv.Quote(line+word)) > width {
+				wrapped = append(wrapped, StringLiteral{Span: l.Span, Value: line})
+				line = ""
+			}
+			line += word
+		}
+		wrapped = append(wrapped, StringLiteral{Span: l.Span, Value: line})
+	}
+	return StringLiterals{Span: text.Span, Lines: wrapped}
+}
+
+// headers returns the headers of h in the order they're encoded in.
+// MIME-Version and Content-Type are always encoded.
+func (e *Encoder) headers(h FileHead) []XHeader {
+	l := h.headers(true)
+	if e.SortHeaders {
+		slices.SortStableFunc(l[len(l)-len(h.NonStandard):],
+			func(a, b XHeader) int { return strings.Compare(a.Name, b.Name) })
+		return l
+	}
+	return orderHeaders(l, h.Order)
+}
+
+func hasNextNonObsolete(msgs []Message, skipObsolete bool) bool {
 	for i := range msgs {
-		if !template || !msgs[i].Obsolete {
+		if !skipObsolete || !msgs[i].Obsolete {
 			return true
 		}
 	}
 	return false
 }
+
+// crlfWriter replaces LF line endings with CRLF.
+type crlfWriter struct{ w io.Writer }
+
+func (c crlfWriter) Write(p []byte) (n int, err error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, lf, crlf)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
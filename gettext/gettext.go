@@ -7,6 +7,7 @@ package gettext
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"golang.org/x/text/language"
@@ -128,6 +129,39 @@ func (l StringLiterals) String() string {
 	return b.String()
 }
 
+// SplitLines returns l with one literal per line where every literal except
+// the last ends with a line break, which is the convention of GNU gettext
+// for multi-line texts. New literals keep the span of the literal their line
+// starts in. l is returned unchanged if it's empty.
+func (l StringLiterals) SplitLines() StringLiterals {
+	var split []StringLiteral
+	var b strings.Builder
+	var start Span
+	for _, lit := range l.Lines {
+		for s := lit.Value; s != ""; {
+			if b.Len() == 0 {
+				start = lit.Span
+			}
+			i := strings.IndexByte(s, '\n')
+			if i == -1 {
+				b.WriteString(s)
+				break
+			}
+			b.WriteString(s[:i+1])
+			s = s[i+1:]
+			split = append(split, StringLiteral{Span: start, Value: b.String()})
+			b.Reset()
+		}
+	}
+	if b.Len() > 0 {
+		split = append(split, StringLiteral{Span: start, Value: b.String()})
+	}
+	if len(split) == 0 {
+		return l
+	}
+	return StringLiterals{Span: l.Span, Lines: split}
+}
+
 // Clone returns a deep copy of s.
 func (s StringLiterals) Clone() StringLiterals {
 	if s.Lines == nil {
@@ -146,28 +180,60 @@ type StringLiteral struct {
 // FilePO is a `.po` translation file.
 type FilePO struct{ *File }
 
-// MakePOT returns a new `.pot` template file from f.
-func (f FilePO) MakePOT() FilePOT {
+// POTOptions are the options of FilePO.MakePOTWithOptions.
+// Header names are matched case-insensitively, names ending with "*"
+// match all headers starting with the name like "X-*".
+type POTOptions struct {
+	// KeepObsolete keeps obsolete (#~) messages with their translations
+	// cleared, which some translation management systems use for translation
+	// memory leverage. Encoder.KeepObsoleteInTemplates must be set
+	// to encode them. Obsolete messages are removed by default.
+	KeepObsolete bool
+
+	// StripHeaders are the names of the headers to remove in addition to the
+	// translator headers, like "Report-Msgid-Bugs-To" or "X-*".
+	StripHeaders []string
+
+	// KeepHeaders are the names of the translator headers to keep,
+	// which are Language, Last-Translator, PO-Revision-Date and Language-Team.
+	KeepHeaders []string
+}
+
+// translatorHeaders are the headers MakePOT removes by default.
+var translatorHeaders = []string{
+	"Language", "Last-Translator", "PO-Revision-Date", "Language-Team",
+}
+
+// MakePOT returns a new `.pot` template file from f
+// without translations, translator headers and obsolete messages.
+func (f FilePO) MakePOT() FilePOT { return f.MakePOTWithOptions(POTOptions{}) }
+
+// MakePOTWithOptions is like MakePOT but allows keeping obsolete messages
+// and removing and keeping other headers.
+func (f FilePO) MakePOTWithOptions(o POTOptions) FilePOT {
 	cp := f.Clone()
-	cp.Head.Language = HeaderLanguage{}
-	cp.Head.LastTranslator = ""
-	cp.Head.PORevisionDate = ""
-	cp.Head.LanguageTeam = ""
-	for i, m := range f.Messages.List {
-		if m.Obsolete {
-			// Don't include obsolete (#~) messages in the .pot
-			continue
+	for _, name := range translatorHeaders {
+		if !matchHeader(o.KeepHeaders, name) {
+			cp.Head.deleteHeader(name)
 		}
-		resetMsgstr := func(m *Msgstr) {
-			if len(m.Text.Lines) > 0 {
-				m.Text = StringLiterals{
-					Lines: []StringLiteral{{Value: ""}},
-				}
-			} else {
-				m.Text = StringLiterals{}
+	}
+	for _, name := range o.StripHeaders {
+		cp.Head.deleteHeader(name)
+	}
+	resetMsgstr := func(m *Msgstr) {
+		if len(m.Text.Lines) > 0 {
+			m.Text = StringLiterals{
+				Lines: []StringLiteral{{Value: ""}},
 			}
+		} else {
+			m.Text = StringLiterals{}
+		}
+	}
+	msgs := cp.Messages.List[:0]
+	for _, m := range cp.Messages.List {
+		if m.Obsolete && !o.KeepObsolete {
+			continue
 		}
-
 		resetMsgstr(&m.Msgstr)
 		resetMsgstr(&m.Msgstr0)
 		resetMsgstr(&m.Msgstr1)
@@ -175,17 +241,46 @@ func (f FilePO) MakePOT() FilePOT {
 		resetMsgstr(&m.Msgstr3)
 		resetMsgstr(&m.Msgstr4)
 		resetMsgstr(&m.Msgstr5)
-		cp.Messages.List[i] = m
+		msgs = append(msgs, m)
 	}
+	cp.Messages.List = msgs
 	return FilePOT{File: cp}
 }
 
+// matchHeader returns true if name matches any of patterns (see POTOptions).
+func matchHeader(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+				return true
+			}
+		} else if strings.EqualFold(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // FilePOT is a `.pot` template file.
 type FilePOT struct{ *File }
 
 type File struct {
 	Head     FileHead
 	Messages Messages
+
+	// Format is the format the file was decoded from.
+	Format Format
+}
+
+// Format defines the byte order mark and line endings of a file.
+// Decoded files are normalized to LF line endings without a byte order mark.
+// Positions of decoded files refer to the normalized input.
+type Format struct {
+	// BOM is true for files starting with a UTF-8 byte order mark.
+	BOM bool
+
+	// CRLF is true for files with CRLF line endings.
+	CRLF bool
 }
 
 type Messages struct {
@@ -224,6 +319,11 @@ type FileHead struct {
 	ContentTransferEncoding string
 	PluralForms             HeaderPluralForms
 	NonStandard             []XHeader
+
+	// Order is the names of the headers in the order they were decoded in.
+	// Headers are encoded in this order, followed by the headers
+	// that aren't listed in the default order.
+	Order []string
 }
 
 // Clone returns a deep copy of h.
@@ -234,9 +334,93 @@ func (f FileHead) Clone() FileHead {
 		cp.NonStandard = make([]XHeader, len(f.NonStandard))
 		copy(cp.NonStandard, f.NonStandard)
 	}
+	cp.Order = slices.Clone(f.Order)
 	return cp
 }
 
+// Headers returns all headers of h that are set in the order they're encoded
+// in (see FileHead.Order).
+func (h FileHead) Headers() []XHeader {
+	return orderHeaders(h.headers(false), h.Order)
+}
+
+// headers returns the headers of h that are set in the default order,
+// the standard headers followed by the non-standard ones.
+// If required, MIME-Version and Content-Type are included even if not set.
+func (h FileHead) headers(required bool) []XHeader {
+	l := make([]XHeader, 0, 11+len(h.NonStandard))
+	add := func(name, value string, always bool) {
+		if value != "" || always {
+			l = append(l, XHeader{Name: name, Value: value})
+		}
+	}
+	add("Project-Id-Version", h.ProjectIdVersion, false)
+	add("Report-Msgid-Bugs-To", h.ReportMsgidBugsTo, false)
+	add("POT-Creation-Date", h.POTCreationDate, false)
+	add("PO-Revision-Date", h.PORevisionDate, false)
+	add("Last-Translator", h.LastTranslator, false)
+	add("Language-Team", h.LanguageTeam, false)
+	add("Language", h.Language.Value, false)
+	add("MIME-Version", h.MIMEVersion, required)
+	add("Content-Type", h.ContentType, required)
+	add("Content-Transfer-Encoding", h.ContentTransferEncoding, false)
+	if h.PluralForms.N != 0 {
+		add("Plural-Forms", fmt.Sprintf("nplurals=%d; plural=%s;",
+			h.PluralForms.N, h.PluralForms.Expression), false)
+	}
+	return append(l, h.NonStandard...)
+}
+
+// orderHeaders sorts l by the index of the names in order.
+// Headers not in order are moved to the end keeping their order.
+func orderHeaders(l []XHeader, order []string) []XHeader {
+	if len(order) < 1 {
+		return l
+	}
+	index := func(name string) int {
+		if i := slices.Index(order, name); i != -1 {
+			return i
+		}
+		return len(order)
+	}
+	slices.SortStableFunc(l, func(a, b XHeader) int {
+		return index(a.Name) - index(b.Name)
+	})
+	return l
+}
+
+// deleteHeader removes all headers matching pattern (see POTOptions).
+func (h *FileHead) deleteHeader(pattern string) {
+	p := []string{pattern}
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{
+		{"Project-Id-Version", &h.ProjectIdVersion},
+		{"Report-Msgid-Bugs-To", &h.ReportMsgidBugsTo},
+		{"POT-Creation-Date", &h.POTCreationDate},
+		{"PO-Revision-Date", &h.PORevisionDate},
+		{"Last-Translator", &h.LastTranslator},
+		{"Language-Team", &h.LanguageTeam},
+		{"MIME-Version", &h.MIMEVersion},
+		{"Content-Type", &h.ContentType},
+		{"Content-Transfer-Encoding", &h.ContentTransferEncoding},
+	} {
+		if matchHeader(p, f.name) {
+			*f.value = ""
+		}
+	}
+	if matchHeader(p, "Language") {
+		h.Language = HeaderLanguage{}
+	}
+	if matchHeader(p, "Plural-Forms") {
+		h.PluralForms = HeaderPluralForms{}
+	}
+	h.NonStandard = slices.DeleteFunc(h.NonStandard, func(x XHeader) bool {
+		return matchHeader(p, x.Name)
+	})
+}
+
 type XHeader struct{ Name, Value string }
 
 type HeaderPluralForms struct {
@@ -245,6 +429,9 @@ type HeaderPluralForms struct {
 }
 
 type HeaderLanguage struct {
+	// Span is the span of the string literal defining the header
+	// or the span of the head msgstr if the header spans multiple literals.
+	Span
 	Value  string
 	Locale language.Tag
 }
@@ -281,6 +468,9 @@ type Error struct {
 	Err      error
 }
 
+// Unwrap returns the underlying error, which is nil for unexpected tokens.
+func (e Error) Unwrap() error { return e.Err }
+
 func (e Error) Error() string {
 	err := e.Err
 	if err == nil {
@@ -309,6 +499,7 @@ var (
 		"unsupported Content-Transfer-Encoding")
 	ErrUnsupportedContentType = errors.New(
 		"unsupported Content-Type, use \"text/plain; charset=UTF-8\"")
+	ErrUnsupportedCharset     = errors.New("unsupported charset")
 	ErrUnsupportedMIMEVersion = errors.New(
 		"unsupported MIME-Version, use \"1.0\"")
 	ErrWrongPluralForm = errors.New(
@@ -3,7 +3,9 @@ package gettext_test
 import (
 	"bytes"
 	_ "embed"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/romshark/localize/gettext"
@@ -36,6 +38,10 @@ func TestDecodeEncode(t *testing.T) {
 			PO:  "testdata/deprecated.po",
 			POT: "testdata/deprecated.pot",
 		},
+		{
+			PO:  "testdata/obsolete.po",
+			POT: "testdata/obsolete.pot",
+		},
 	} {
 		t.Run(files.PO, func(t *testing.T) {
 			// Decode `.po` from original.
@@ -86,3 +92,698 @@ func TestDecodeEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeObsolete(t *testing.T) {
+	fd, err := os.Open("testdata/obsolete.po")
+	require.NoError(t, err)
+	defer func() { _ = fd.Close() }()
+	po, err := gettext.NewDecoder().DecodePO("testdata/obsolete.po", fd)
+	require.NoError(t, err)
+
+	type Expect struct {
+		Obsolete    bool
+		Msgctxt     string
+		Msgid       string
+		MsgidPlural string
+		Msgstr      string
+		Msgstr0     string
+		Msgstr1     string
+		Comments    []string
+	}
+	var actual []Expect
+	for _, m := range po.Messages.List {
+		e := Expect{
+			Obsolete:    m.Obsolete,
+			Msgctxt:     m.Msgctxt.Text.String(),
+			Msgid:       m.Msgid.Text.String(),
+			MsgidPlural: m.MsgidPlural.Text.String(),
+			Msgstr:      m.Msgstr.Text.String(),
+			Msgstr0:     m.Msgstr0.Text.String(),
+			Msgstr1:     m.Msgstr1.Text.String(),
+		}
+		for _, c := range m.Msgctxt.Comments.Text {
+			e.Comments = append(e.Comments, c.Value)
+		}
+		for _, c := range m.Msgid.Comments.Text {
+			e.Comments = append(e.Comments, c.Value)
+		}
+		actual = append(actual, e)
+	}
+
+	require.Equal(t, []Expect{
+		{Msgid: "Active", Msgstr: "Aktiv", Comments: []string{"Active message"}},
+		{
+			Obsolete: true,
+			Msgid:    "Obsolete multi-line\nmessage",
+			Msgstr:   "Veraltete mehrzeilige\nNachricht",
+			Comments: []string{
+				"translator comment", "extracted comment",
+				"foo/bar.go:12", "go-format",
+			},
+		},
+		{
+			Obsolete: true,
+			Msgctxt:  "context",
+			Msgid:    "Obsolete with context",
+			Msgstr:   "Veraltet mit Kontext",
+		},
+		{
+			Obsolete:    true,
+			Msgid:       "One obsolete item",
+			MsgidPlural: "%d obsolete\nitems",
+			Msgstr0:     "Ein veraltetes\nElement",
+			Msgstr1:     "%d veraltete\nElemente",
+		},
+		{Obsolete: true, Msgid: "Obsolete empty"},
+		{Msgid: "Second", Msgstr: "Zweite\nNachricht"},
+		{
+			Obsolete: true,
+			Msgid:    "Obsolete last",
+			Msgstr:   "Last\nline",
+			Comments: []string{"", "comment after empty comment"},
+		},
+	}, actual)
+}
+
+func TestDecodeObsoleteMalformed(t *testing.T) {
+	f := func(t *testing.T, input string) {
+		t.Helper()
+		_, err := gettext.NewDecoder().DecodePO("test.po", strings.NewReader(input))
+		require.Error(t, err)
+	}
+
+	const head = `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+`
+
+	// Missing msgstr.
+	f(t, head+"#~ msgid \"Obsolete\"\n")
+	// Continuation line missing the obsolete prefix.
+	f(t, head+"#~ msgid \"\"\n\"Obsolete\"\n#~ msgstr \"Veraltet\"\n")
+	// Multi-line msgid_plural missing msgstr[1].
+	f(t, head+"#~ msgid \"One\"\n#~ msgid_plural \"\"\n#~ \"Many\"\n"+
+		"#~ msgstr[0] \"Eins\"\n")
+	// Previous comments without obsolete directives.
+	f(t, head+"# comment\n#~| msgid \"Previous\"\n")
+}
+
+func TestDecodeSalvage(t *testing.T) {
+	const input = `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+msgid "First"
+msgstr "Erste"
+
+# Unterminated string literal.
+msgid "Broken
+msgstr "Kaputt"
+
+msgid "Second"
+msgstr "Zweite"
+msgstr "Duplicate"
+msgid "Third"
+msgstr "Dritte"
+
+msgid "Incomplete"
+`
+	_, err := gettext.NewDecoder().DecodePOString("test.po", input)
+	require.Error(t, err)
+
+	d := gettext.NewDecoder()
+	d.Salvage = true
+	f, err := d.DecodePOString("test.po", input)
+	require.NoError(t, err)
+	var ids []string
+	for _, m := range f.Messages.List {
+		ids = append(ids, m.Msgid.Text.String())
+	}
+	require.Equal(t, []string{"First", "Third"}, ids)
+
+	skipped := d.Skipped()
+	require.Len(t, skipped, 3)
+	require.Equal(t, uint32(12), skipped[0].Pos.Line)
+	require.Equal(t, uint32(17), skipped[1].Pos.Line)
+	require.Equal(t, uint32(21), skipped[2].Pos.Line)
+	for _, e := range skipped {
+		require.Equal(t, "test.po", e.Pos.Filename)
+	}
+
+	// Skipped errors are reset by the next decode.
+	_, err = d.DecodePOString("test.po", input[:strings.Index(input, "# Unter")])
+	require.NoError(t, err)
+	require.Empty(t, d.Skipped())
+}
+
+func TestDecodeObsoleteGNU(t *testing.T) {
+	const input = `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+msgid "Active"
+msgstr "Aktiv"
+# translator comment
+#. extracted comment
+#~| msgid "Previous"
+#~ msgid "Obsolete"
+#~ msgstr "Veraltet"
+
+#, fuzzy
+#~| msgctxt "previous context"
+#~| msgid "Previous item"
+#~| msgid_plural ""
+#~| "%d previous\n"
+#~| "items"
+#~ msgid "One obsolete item"
+#~ msgid_plural ""
+#~ "%d obsolete\n"
+#~ "items"
+#~ # interleaved comment
+#~ msgstr[0] "Ein veraltetes Element"
+#~ #. interleaved comment
+#~ msgstr[1] ""
+#~ "%d veraltete\n"
+#~ "Elemente"
+`
+	type Expect struct {
+		Obsolete    bool
+		Msgid       string
+		MsgidPlural string
+		Msgstr      string
+		Msgstr0     string
+		Msgstr1     string
+		Comments    []string
+	}
+	decode := func(t *testing.T, input string) (actual []Expect) {
+		t.Helper()
+		po, err := gettext.NewDecoder().DecodePOString("test.po", input)
+		require.NoError(t, err)
+		for _, m := range po.Messages.List {
+			e := Expect{
+				Obsolete:    m.Obsolete,
+				Msgid:       m.Msgid.Text.String(),
+				MsgidPlural: m.MsgidPlural.Text.String(),
+				Msgstr:      m.Msgstr.Text.String(),
+				Msgstr0:     m.Msgstr0.Text.String(),
+				Msgstr1:     m.Msgstr1.Text.String(),
+			}
+			for _, c := range [...]gettext.Comments{
+				m.Msgctxt.Comments, m.Msgid.Comments, m.MsgidPlural.Comments,
+				m.Msgstr0.Comments, m.Msgstr1.Comments,
+			} {
+				for _, c := range c.Text {
+					e.Comments = append(e.Comments, c.Value)
+				}
+			}
+			actual = append(actual, e)
+		}
+		return actual
+	}
+
+	expect := []Expect{
+		{Msgid: "Active", Msgstr: "Aktiv"},
+		{
+			Obsolete: true,
+			Msgid:    "Obsolete",
+			Msgstr:   "Veraltet",
+			Comments: []string{"translator comment", "extracted comment"},
+		},
+		{
+			Obsolete:    true,
+			Msgid:       "One obsolete item",
+			MsgidPlural: "%d obsolete\nitems",
+			Msgstr0:     "Ein veraltetes Element",
+			Msgstr1:     "%d veraltete\nElemente",
+			Comments: []string{
+				"fuzzy", "interleaved comment", "interleaved comment",
+			},
+		},
+	}
+	require.Equal(t, expect, decode(t, input))
+
+	// Encoded files decode identically.
+	po, err := gettext.NewDecoder().DecodePOString("test.po", input)
+	require.NoError(t, err)
+	encoded, err := gettext.Encoder{}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Equal(t, expect, decode(t, encoded))
+}
+
+func TestDecodeHeaderLanguagePosition(t *testing.T) {
+	po, err := gettext.NewDecoder().DecodePO("test.po", strings.NewReader(`msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Language: fr\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+`))
+	require.NoError(t, err)
+	require.Equal(t, "fr", po.Head.Language.Value)
+	require.Equal(t, uint32(4), po.Head.Language.Line)
+	require.Equal(t, uint32(1), po.Head.Language.Column)
+
+	_, err = gettext.NewDecoder().DecodePO("test.po", strings.NewReader(`msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Language: not a locale\n"
+`))
+	require.EqualError(t, err,
+		"test.po:4:1: "+gettext.ErrMalformedHeaderLanguage.Error())
+}
+
+func TestFileHeadHeaders(t *testing.T) {
+	po, err := gettext.NewDecoder().DecodePO("test.po", strings.NewReader(`msgid ""
+msgstr ""
+"Project-Id-Version: app 1.2\n"
+"PO-Revision-Date: 2025-01-02 03:04+0000\n"
+"Language: fr\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=n > 1;\n"
+"X-Generator: test\n"
+`))
+	require.NoError(t, err)
+	require.Equal(t, []gettext.XHeader{
+		{Name: "Project-Id-Version", Value: "app 1.2"},
+		{Name: "PO-Revision-Date", Value: "2025-01-02 03:04+0000"},
+		{Name: "Language", Value: "fr"},
+		{Name: "MIME-Version", Value: "1.0"},
+		{Name: "Content-Type", Value: "text/plain; charset=UTF-8"},
+		{Name: "Plural-Forms", Value: "nplurals=2; plural=n > 1;"},
+		{Name: "X-Generator", Value: "test"},
+	}, po.Head.Headers())
+}
+
+func TestDecodeCharset(t *testing.T) {
+	const po = `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Language: de\n"
+"Content-Type: text/plain; charset=%s\n"
+
+msgid "Cheese"
+msgstr "Käse"
+`
+	f := func(t *testing.T, input []byte) {
+		t.Helper()
+		f, err := gettext.NewDecoder().DecodePO("test.po", bytes.NewReader(input))
+		require.NoError(t, err)
+		require.Equal(t, "text/plain; charset=UTF-8", f.Head.ContentType)
+		require.Len(t, f.Messages.List, 1)
+		require.Equal(t, "Käse", f.Messages.List[0].Msgstr.Text.String())
+	}
+
+	latin1 := strings.ReplaceAll(fmt.Sprintf(po, "ISO-8859-1"), "ä", "\xe4")
+	f(t, []byte(latin1))
+
+	utf16 := func(s string, bigEndian bool) []byte {
+		b := []byte{0xFF, 0xFE}
+		if bigEndian {
+			b = []byte{0xFE, 0xFF}
+		}
+		for _, r := range s {
+			if bigEndian {
+				b = append(b, byte(r>>8), byte(r))
+			} else {
+				b = append(b, byte(r), byte(r>>8))
+			}
+		}
+		return b
+	}
+	f(t, utf16(fmt.Sprintf(po, "UTF-16"), false))
+	f(t, utf16(fmt.Sprintf(po, "UTF-16"), true))
+
+	_, err := gettext.NewDecoder().DecodePO("test.po",
+		strings.NewReader(fmt.Sprintf(po, "X-UNKNOWN")))
+	require.ErrorContains(t, err, gettext.ErrUnsupportedCharset.Error())
+}
+
+func TestDecodeEncodeFormat(t *testing.T) {
+	original, err := os.ReadFile("testdata/small.en.po")
+	require.NoError(t, err)
+	windows := append([]byte("\xef\xbb\xbf"),
+		bytes.ReplaceAll(original, []byte("\n"), []byte("\r\n"))...)
+
+	po, err := gettext.NewDecoder().DecodePO("test.po", bytes.NewReader(windows))
+	require.NoError(t, err)
+	require.Equal(t, gettext.Format{BOM: true, CRLF: true}, po.Format)
+
+	expect, err := gettext.NewDecoder().DecodePO("test.po", bytes.NewReader(original))
+	require.NoError(t, err)
+	require.Equal(t, gettext.Format{}, expect.Format)
+	require.Equal(t, len(expect.Messages.List), len(po.Messages.List))
+	for i := range expect.Messages.List {
+		require.Equal(t, expect.Messages.List[i].Msgid.Text.String(),
+			po.Messages.List[i].Msgid.Text.String())
+		require.Equal(t, expect.Messages.List[i].Msgstr.Text.String(),
+			po.Messages.List[i].Msgstr.Text.String())
+	}
+
+	f := func(t *testing.T, e gettext.Encoder, expect []byte) {
+		t.Helper()
+		var buf bytes.Buffer
+		require.NoError(t, e.EncodePO(po, &buf))
+		require.Equal(t, string(expect), buf.String())
+	}
+	f(t, gettext.Encoder{}, original)
+	f(t, gettext.Encoder{PreserveFormat: true}, windows)
+	f(t, gettext.Encoder{CRLF: true}, windows[3:])
+	f(t, gettext.Encoder{BOM: true}, append([]byte("\xef\xbb\xbf"), original...))
+}
+
+func TestSplitLines(t *testing.T) {
+	f := func(t *testing.T, expect []string, input ...string) {
+		t.Helper()
+		var l gettext.StringLiterals
+		for _, s := range input {
+			l.Lines = append(l.Lines, gettext.StringLiteral{Value: s})
+		}
+		var actual []string
+		for _, s := range l.SplitLines().Lines {
+			actual = append(actual, s.Value)
+		}
+		require.Equal(t, expect, actual)
+	}
+	f(t, nil)
+	f(t, []string{""}, "")
+	f(t, []string{"a"}, "a")
+	f(t, []string{"a\n"}, "a\n")
+	f(t, []string{"a\n", "b\n", "c"}, "a\nb\nc")
+	f(t, []string{"a\n", "\n", "b"}, "a\n\nb")
+	f(t, []string{"ab\n", "cd"}, "a", "b\nc", "d")
+	f(t, []string{"a\n", "b"}, "a\n", "", "b")
+}
+
+func TestDecodeEncodeSplitLines(t *testing.T) {
+	const head = "msgid \"\"\nmsgstr \"\"\n" +
+		"\"MIME-Version: 1.0\\n\"\n" +
+		"\"Content-Type: text/plain; charset=UTF-8\\n\"\n\n"
+	const joined = head +
+		"msgid \"One\\nTwo\"\n" +
+		"msgstr \"\"\n\"Eins\\nZw\"\n\"ei\\n\"\n"
+	const split = head +
+		"msgid \"\"\n\"One\\n\"\n\"Two\"\n" +
+		"msgstr \"Eins\\nZwei\\n\"\n"
+
+	po, err := gettext.NewDecoder().DecodePO("test.po", strings.NewReader(joined))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, gettext.Encoder{SplitLines: true}.EncodePO(po, &buf))
+	require.Equal(t, head+
+		"msgid \"\"\n\"One\\n\"\n\"Two\"\n"+
+		"msgstr \"\"\n\"Eins\\n\"\n\"Zwei\\n\"\n", buf.String())
+
+	// Differently wrapped files decode identically.
+	d := gettext.NewDecoder()
+	d.SplitLines = true
+	a, err := d.DecodePO("a.po", strings.NewReader(joined))
+	require.NoError(t, err)
+	b, err := d.DecodePO("b.po", strings.NewReader(split))
+	require.NoError(t, err)
+	for i, m := range a.Messages.List {
+		values := func(m gettext.Message) (v []string) {
+			for _, l := range append(m.Msgid.Text.Lines, m.Msgstr.Text.Lines...) {
+				v = append(v, l.Value)
+			}
+			return v
+		}
+		require.Equal(t, []string{"One\n", "Two", "Eins\n", "Zwei\n"}, values(m))
+		require.Equal(t, values(m), values(b.Messages.List[i]))
+	}
+}
+
+func TestDecodeEncodeString(t *testing.T) {
+	original, err := os.ReadFile("testdata/small.en.po")
+	require.NoError(t, err)
+
+	po, err := gettext.NewDecoder().DecodePOString("test.po", string(original))
+	require.NoError(t, err)
+	s, err := gettext.Encoder{}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Equal(t, string(original), s)
+	b, err := gettext.Encoder{}.EncodePOToBytes(po)
+	require.NoError(t, err)
+	require.Equal(t, original, b)
+
+	original, err = os.ReadFile("testdata/small.pot")
+	require.NoError(t, err)
+	pot, err := gettext.NewDecoder().DecodePOTString("test.pot", string(original))
+	require.NoError(t, err)
+	s, err = gettext.Encoder{}.EncodePOTToString(pot)
+	require.NoError(t, err)
+	require.Equal(t, string(original), s)
+	b, err = gettext.Encoder{}.EncodePOTToBytes(pot)
+	require.NoError(t, err)
+	require.Equal(t, original, b)
+}
+
+func TestEncodeWrap(t *testing.T) {
+	const head = "msgid \"\"\nmsgstr \"\"\n" +
+		"\"MIME-Version: 1.0\\n\"\n" +
+		"\"Content-Type: text/plain; charset=UTF-8\\n\"\n\n"
+	po, err := gettext.NewDecoder().DecodePOString("test.po", head+
+		"msgid \"short\"\n"+
+		"msgstr \"the quick brown fox jumps over the lazy dog\"\n\n"+
+		"msgid \"sixteen bytes!!\"\n"+
+		"msgstr \"\"\n")
+	require.NoError(t, err)
+
+	s, err := gettext.Encoder{Wrap: 20}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Equal(t, head+
+		"msgid \"short\"\n"+
+		"msgstr \"\"\n"+
+		"\"the quick brown \"\n"+
+		"\"fox jumps over \"\n"+
+		"\"the lazy dog\"\n\n"+
+		"msgid \"\"\n"+
+		"\"sixteen bytes!!\"\n"+
+		"msgstr \"\"\n", s)
+
+	// Wrapping is disabled by default.
+	s, err = gettext.Encoder{}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Contains(t, s, "msgstr \"the quick brown fox jumps over the lazy dog\"\n")
+}
+
+func TestDecodeEncodeReferences(t *testing.T) {
+	const head = "msgid \"\"\nmsgstr \"\"\n" +
+		"\"MIME-Version: 1.0\\n\"\n" +
+		"\"Content-Type: text/plain; charset=UTF-8\\n\"\n\n"
+	const compact = head +
+		"# translator\n" +
+		"#: a.go:1 a.go:12 b.go:3\n" +
+		"#: c.go:4\n" +
+		"msgid \"A\"\n" +
+		"msgstr \"\"\n\n" +
+		"#~ #: d.go:1 d.go:2\n" +
+		"#~ msgid \"B\"\n" +
+		"#~ msgstr \"\"\n"
+
+	// References are kept as written unless split.
+	po, err := gettext.NewDecoder().DecodePOString("test.po", compact)
+	require.NoError(t, err)
+	require.Equal(t, "a.go:1 a.go:12 b.go:3",
+		po.Messages.List[0].Msgid.Comments.Text[1].Value)
+
+	d := gettext.NewDecoder()
+	d.SplitReferences = true
+	po, err = d.DecodePOString("test.po", compact)
+	require.NoError(t, err)
+	var refs []string
+	for _, c := range po.Messages.List[0].Msgid.Comments.Text {
+		if c.Type == gettext.CommentTypeReference {
+			refs = append(refs, c.Value)
+		}
+	}
+	require.Equal(t, []string{"a.go:1", "a.go:12", "b.go:3", "c.go:4"}, refs)
+
+	s, err := gettext.Encoder{}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Equal(t, head+
+		"# translator\n"+
+		"#: a.go:1\n#: a.go:12\n#: b.go:3\n#: c.go:4\n"+
+		"msgid \"A\"\n"+
+		"msgstr \"\"\n\n"+
+		"#~ #: d.go:1\n#~ #: d.go:2\n"+
+		"#~ msgid \"B\"\n"+
+		"#~ msgstr \"\"\n", s)
+
+	s, err = gettext.Encoder{CompactReferences: true}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Equal(t, head+
+		"# translator\n"+
+		"#: a.go:1 a.go:12 b.go:3 c.go:4\n"+
+		"msgid \"A\"\n"+
+		"msgstr \"\"\n\n"+
+		"#~ #: d.go:1 d.go:2\n"+
+		"#~ msgid \"B\"\n"+
+		"#~ msgstr \"\"\n", s)
+
+	s, err = gettext.Encoder{CompactReferences: true, Wrap: 20}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Contains(t, s, "#: a.go:1 a.go:12\n#: b.go:3 c.go:4\n")
+}
+
+func TestEncodeSortHeaders(t *testing.T) {
+	po, err := gettext.NewDecoder().DecodePOString("test.po",
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"MIME-Version: 1.0\\n\"\n"+
+			"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+			"\"X-Zeta: z\\n\"\n"+
+			"\"X-Alpha: a\\n\"\n")
+	require.NoError(t, err)
+
+	s, err := gettext.Encoder{}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Less(t, strings.Index(s, "X-Zeta"), strings.Index(s, "X-Alpha"))
+
+	s, err = gettext.Encoder{SortHeaders: true}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Less(t, strings.Index(s, "X-Alpha"), strings.Index(s, "X-Zeta"))
+}
+
+func TestEncodeHeaderOrder(t *testing.T) {
+	const input = "msgid \"\"\nmsgstr \"\"\n" +
+		"\"X-Generator: Poedit 3.4\\n\"\n" +
+		"\"Language: de\\n\"\n" +
+		"\"Content-Type: text/plain; charset=UTF-8\\n\"\n" +
+		"\"MIME-Version: 1.0\\n\"\n" +
+		"\"Project-Id-Version: app 1.0\\n\"\n"
+	po, err := gettext.NewDecoder().DecodePOString("test.po", input)
+	require.NoError(t, err)
+
+	s, err := gettext.Encoder{}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Equal(t, input+"\n", s)
+
+	// New headers are appended.
+	po.Head.LastTranslator = "Jane"
+	s, err = gettext.Encoder{}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Equal(t, input+"\"Last-Translator: Jane\\n\"\n\n", s)
+	require.Equal(t, "Last-Translator", po.Head.Headers()[5].Name)
+
+	s, err = gettext.Encoder{SortHeaders: true}.EncodePOToString(po)
+	require.NoError(t, err)
+	require.Equal(t, "msgid \"\"\nmsgstr \"\"\n"+
+		"\"Project-Id-Version: app 1.0\\n\"\n"+
+		"\"Last-Translator: Jane\\n\"\n"+
+		"\"Language: de\\n\"\n"+
+		"\"MIME-Version: 1.0\\n\"\n"+
+		"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+		"\"X-Generator: Poedit 3.4\\n\"\n\n", s)
+}
+
+func TestMakePOTWithOptions(t *testing.T) {
+	po, err := gettext.NewDecoder().DecodePOString("test.po",
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"Project-Id-Version: app 1.0\\n\"\n"+
+			"\"Report-Msgid-Bugs-To: bugs@example.com\\n\"\n"+
+			"\"Last-Translator: Jane\\n\"\n"+
+			"\"Language-Team: German\\n\"\n"+
+			"\"Language: de\\n\"\n"+
+			"\"MIME-Version: 1.0\\n\"\n"+
+			"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+			"\"X-Generator: Poedit 3.4\\n\"\n"+
+			"\"X-Source-Language: en\\n\"\n\n"+
+			"msgid \"Active\"\nmsgstr \"Aktiv\"\n\n"+
+			"#~ msgid \"Gone\"\n#~ msgstr \"Weg\"\n")
+	require.NoError(t, err)
+
+	s, err := gettext.Encoder{}.EncodePOTToString(po.MakePOT())
+	require.NoError(t, err)
+	require.Equal(t, "msgid \"\"\nmsgstr \"\"\n"+
+		"\"Project-Id-Version: app 1.0\\n\"\n"+
+		"\"Report-Msgid-Bugs-To: bugs@example.com\\n\"\n"+
+		"\"MIME-Version: 1.0\\n\"\n"+
+		"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+		"\"X-Generator: Poedit 3.4\\n\"\n"+
+		"\"X-Source-Language: en\\n\"\n\n"+
+		"msgid \"Active\"\nmsgstr \"\"\n", s)
+
+	pot := po.MakePOTWithOptions(gettext.POTOptions{
+		KeepObsolete: true,
+		StripHeaders: []string{"report-msgid-bugs-to", "X-Gen*"},
+		KeepHeaders:  []string{"Language-Team"},
+	})
+	s, err = gettext.Encoder{KeepObsoleteInTemplates: true}.EncodePOTToString(pot)
+	require.NoError(t, err)
+	require.Equal(t, "msgid \"\"\nmsgstr \"\"\n"+
+		"\"Project-Id-Version: app 1.0\\n\"\n"+
+		"\"Language-Team: German\\n\"\n"+
+		"\"MIME-Version: 1.0\\n\"\n"+
+		"\"Content-Type: text/plain; charset=UTF-8\\n\"\n"+
+		"\"X-Source-Language: en\\n\"\n\n"+
+		"msgid \"Active\"\nmsgstr \"\"\n\n"+
+		"#~ msgid \"Gone\"\n#~ msgstr \"\"\n", s)
+
+	// Obsolete messages are skipped unless the encoder keeps them.
+	s, err = gettext.Encoder{}.EncodePOTToString(pot)
+	require.NoError(t, err)
+	require.NotContains(t, s, "Gone")
+
+	// The original is left untouched.
+	require.Equal(t, "Weg", po.Messages.List[1].Msgstr.Text.String())
+	require.Equal(t, "Jane", po.Head.LastTranslator)
+}
+
+func TestValidate(t *testing.T) {
+	for _, file := range []string{
+		"testdata/minimal.en.po", "testdata/small.en.po", "testdata/valid.en.po",
+		"testdata/utf8.uk.po", "testdata/obsolete.po",
+	} {
+		fd, err := os.Open(file)
+		require.NoError(t, err)
+		po, err := gettext.NewDecoder().DecodePO(file, fd)
+		_ = fd.Close()
+		require.NoError(t, err)
+		require.Empty(t, po.Validate(), file)
+	}
+
+	po, err := gettext.NewDecoder().DecodePO("test.po", strings.NewReader(`msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+msgid "Apple"
+msgstr "Apfel"
+
+msgid "%d pear"
+msgid_plural "%d pears"
+msgstr[0] "%d Birne"
+msgstr[1] "%d Birnen"
+`))
+	require.NoError(t, err)
+	require.Empty(t, po.Validate())
+
+	po.Head.MIMEVersion = ""
+	po.Messages.List[1].Msgstr1 = gettext.Msgstr{}
+	obsolete := po.Messages.List[0].Clone()
+	obsolete.Obsolete = true
+	po.Messages.List = append(po.Messages.List, obsolete, gettext.Message{
+		Msgstr: po.Messages.List[0].Msgstr,
+	})
+
+	var errs []error
+	for _, issue := range po.Validate() {
+		errs = append(errs, issue.Err)
+	}
+	require.Len(t, errs, 4)
+	require.ErrorIs(t, errs[0], gettext.ErrMissingHeader)
+	require.ErrorIs(t, errs[1], gettext.ErrMissingMsgstrIndex)
+	require.ErrorIs(t, errs[2], gettext.ErrDuplicateMessage)
+	require.ErrorContains(t, errs[2], "obsolete and active message at 7:1")
+	require.ErrorIs(t, errs[3], gettext.ErrEmptyMsgid)
+}
@@ -0,0 +1,5 @@
+//go:build race
+
+package gettext_test
+
+func init() { raceEnabled = true }
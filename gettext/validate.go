@@ -0,0 +1,114 @@
+package gettext
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrMissingHeader      = errors.New("missing header")
+	ErrEmptyMsgid         = errors.New("empty msgid is reserved for the head")
+	ErrMissingMsgstrIndex = errors.New("missing msgstr index")
+	ErrMixedMsgstr        = errors.New(
+		"plural messages must only use msgstr[n], other messages only msgstr")
+	ErrDuplicateMessage = errors.New("duplicate message")
+)
+
+// Validate performs semantic checks on f that the decoder doesn't perform,
+// which is relevant for files that were modified after decoding.
+// It returns all issues found in the order of their occurrence:
+//
+//   - Headers MIME-Version and Content-Type must be set and Plural-Forms
+//     must be set if f contains plural messages.
+//   - Only the head may have an empty msgid.
+//   - Plural messages must have a msgstr[n] for every plural form
+//     defined by the Plural-Forms header and no other msgstr.
+//   - Non-plural messages must have a msgstr and no msgstr[n].
+//   - Messages must be unique by msgctxt and msgid, including obsolete messages,
+//     since an obsolete message can't coexist with its active counterpart.
+func (f *File) Validate() (issues []Error) {
+	add := func(pos Position, err error) {
+		issues = append(issues, Error{Pos: pos, Err: err})
+	}
+	missingHeader := func(name string) {
+		add(f.Head.Position, fmt.Errorf("%w: %s", ErrMissingHeader, name))
+	}
+	if f.Head.MIMEVersion == "" {
+		missingHeader("MIME-Version")
+	}
+	if f.Head.ContentType == "" {
+		missingHeader("Content-Type")
+	}
+
+	type key struct{ msgctxt, msgid string }
+	type messagePos struct {
+		Position
+		Obsolete bool
+	}
+	byKey := make(map[key]messagePos, len(f.Messages.List))
+	pluralFormsChecked := false
+	for i := range f.Messages.List {
+		m := &f.Messages.List[i]
+		pos := m.Msgid.Position
+		if !m.Msgctxt.IsZero() {
+			pos = m.Msgctxt.Position
+		}
+		if m.Msgid.Text.String() == "" {
+			add(pos, ErrEmptyMsgid)
+		}
+
+		k := key{m.Msgctxt.Text.String(), m.Msgid.Text.String()}
+		if prev, ok := byKey[k]; ok {
+			err := fmt.Errorf("%w: first defined at %d:%d",
+				ErrDuplicateMessage, prev.Line, prev.Column)
+			if prev.Obsolete != m.Obsolete {
+				err = fmt.Errorf("%w: obsolete and active message at %d:%d",
+					ErrDuplicateMessage, prev.Line, prev.Column)
+			}
+			add(pos, err)
+		} else {
+			byKey[k] = messagePos{pos, m.Obsolete}
+		}
+
+		indexed := m.msgstrIndexed()
+		if len(m.MsgidPlural.Text.Lines) == 0 {
+			if len(m.Msgstr.Text.Lines) == 0 || indexed[0] != nil {
+				add(pos, ErrMixedMsgstr)
+			}
+			continue
+		}
+
+		if len(m.Msgstr.Text.Lines) > 0 {
+			add(pos, ErrMixedMsgstr)
+		}
+		if f.Head.PluralForms.N == 0 {
+			if !pluralFormsChecked {
+				missingHeader("Plural-Forms")
+				pluralFormsChecked = true
+			}
+			continue
+		}
+		for n, s := range indexed {
+			switch {
+			case n < int(f.Head.PluralForms.N) && s == nil:
+				add(pos, fmt.Errorf("%w: msgstr[%d]", ErrMissingMsgstrIndex, n))
+			case n >= int(f.Head.PluralForms.N) && s != nil:
+				add(s.Position, fmt.Errorf("%w: msgstr[%d]", ErrWrongPluralForm, n))
+			}
+		}
+	}
+	return issues
+}
+
+// msgstrIndexed returns msgstr[0] to msgstr[5] of m.
+// Undefined msgstr[n] are nil.
+func (m *Message) msgstrIndexed() (l [6]*Msgstr) {
+	for i, s := range [...]*Msgstr{
+		&m.Msgstr0, &m.Msgstr1, &m.Msgstr2, &m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+	} {
+		if len(s.Text.Lines) > 0 {
+			l[i] = s
+		}
+	}
+	return l
+}
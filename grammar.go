@@ -0,0 +1,28 @@
+package localize
+
+import "strings"
+
+// GrammarContextPrefix is the msgctxt prefix of grammar entries,
+// which are auxiliary messages of translation catalogs defining the
+// grammatical forms returned by Reader.Grammar. The msgctxt of an entry
+// is the prefix followed by the key, its msgid is the phrase
+// and its msgstr the grammatical form of the phrase:
+//
+//	msgctxt "grammar:contraction"
+//	msgid "de le"
+//	msgstr "du"
+//
+// Grammar entries are written by translators and kept by localize generate.
+const GrammarContextPrefix = "grammar:"
+
+// GrammarPhrase returns the phrase of the args of Reader.Grammar,
+// which are joined by spaces. The phrase is the msgid of grammar entries
+// and returned by Reader.Grammar if no entry is defined.
+func GrammarPhrase(args ...string) string { return strings.Join(args, " ") }
+
+// GrammarID returns the identifier of the grammar entry of key and args
+// for catalogs without message contexts, which is the msgctxt and msgid
+// separated by "\x04" like in GNU gettext MO files.
+func GrammarID(key string, args ...string) string {
+	return GrammarContextPrefix + key + "\x04" + GrammarPhrase(args...)
+}
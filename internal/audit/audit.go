@@ -0,0 +1,87 @@
+// Package audit maintains the audit log file (.localize-audit.jsonl)
+// recording the catalog changes of every generator run as one JSON object
+// per line, independent of version control.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the name of the audit log file in the bundle package directory.
+const FileName = ".localize-audit.jsonl"
+
+// Entry is a single generator run.
+type Entry struct {
+	Time time.Time `json:"time"`
+
+	// Added is the number of messages added to translation catalogs
+	// summed over all catalogs.
+	Added int `json:"added"`
+
+	// Obsoleted is the number of messages marked obsolete in translation
+	// catalogs summed over all catalogs.
+	Obsoleted int `json:"obsoleted"`
+
+	// Files are the SHA-256 hashes in hex of all written files
+	// by slash-separated path relative to the bundle package directory.
+	Files map[string]string `json:"files"`
+}
+
+// HashFiles returns the SHA-256 hashes of files by their slash-separated
+// path relative to dir, as expected by Entry.Files.
+func HashFiles(dir string, files []string) (map[string]string, error) {
+	m := make(map[string]string, len(files))
+	for _, name := range files {
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		h, err := hashFile(name)
+		if err != nil {
+			return nil, err
+		}
+		m[filepath.ToSlash(rel)] = h
+	}
+	return m, nil
+}
+
+func hashFile(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("reading file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Append appends e to the audit log file at path,
+// which is created if it doesn't exist.
+func Append(path string, e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding entry: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/romshark/localize/internal/audit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	a := filepath.Join(dir, "a.po")
+	b := filepath.Join(dir, "sub", "b.po")
+	require.NoError(t, os.WriteFile(a, []byte("hello"), 0o644))
+	require.NoError(t, os.WriteFile(b, nil, 0o644))
+
+	m, err := audit.HashFiles(dir, []string{a, b})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"a.po": "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		"sub/b.po": "e3b0c44298fc1c149afbf4c8996fb924" +
+			"27ae41e4649b934ca495991b7852b855",
+	}, m)
+
+	_, err = audit.HashFiles(dir, []string{filepath.Join(dir, "missing.po")})
+	require.Error(t, err)
+}
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), audit.FileName)
+	first := audit.Entry{
+		Time:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Added: 2,
+		Files: map[string]string{"catalog.de.po": "aa"},
+	}
+	second := audit.Entry{
+		Time:      time.Date(2025, 1, 3, 3, 4, 5, 0, time.UTC),
+		Obsoleted: 1,
+		Files:     map[string]string{"catalog.de.po": "bb"},
+	}
+	require.NoError(t, audit.Append(path, first))
+	require.NoError(t, audit.Append(path, second))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var entries []audit.Entry
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		var e audit.Entry
+		require.NoError(t, json.Unmarshal(s.Bytes(), &e))
+		entries = append(entries, e)
+	}
+	require.NoError(t, s.Err())
+	require.Equal(t, []audit.Entry{first, second}, entries)
+}
@@ -0,0 +1,86 @@
+// Package badge renders translation coverage badges.
+package badge
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/romshark/localize/internal/coverage"
+)
+
+//go:embed badge.svg.gotmpl
+var tmplSVGText string
+
+var tmplSVG = template.Must(template.New("badge").Parse(tmplSVGText))
+
+// Badge is a coverage badge.
+type Badge struct{ Label, Message, Color string }
+
+// Make creates the coverage badge for a catalog with label.
+func Make(label string, c coverage.Coverage) Badge {
+	return Badge{
+		Label:   label,
+		Message: fmt.Sprintf("%.0f%%", c.Percent()),
+		Color:   Color(c),
+	}
+}
+
+// Color returns the badge color for c.
+func Color(c coverage.Coverage) string {
+	switch p := c.Percent(); {
+	case p >= 100:
+		return "#4c1"
+	case p >= 80:
+		return "#dfb317"
+	}
+	return "#e05d44"
+}
+
+// Horizontal padding of each badge section in pixels.
+const padding = 10
+
+// textWidth approximates the rendered width of s in pixels
+// for 11px Verdana.
+func textWidth(s string) int { return utf8.RuneCountInString(s)*7 + padding }
+
+// WriteSVG writes b as an SVG image to w.
+func (b Badge) WriteSVG(w io.Writer) error {
+	lw, mw := textWidth(b.Label), textWidth(b.Message)
+	return tmplSVG.Execute(w, struct {
+		Label, Message, Color string
+		Width, LabelWidth     int
+		MessageWidth          int
+		LabelX, MessageX      float64
+	}{
+		Label:        b.Label,
+		Message:      b.Message,
+		Color:        b.Color,
+		Width:        lw + mw,
+		LabelWidth:   lw,
+		MessageWidth: mw,
+		LabelX:       float64(lw) / 2,
+		MessageX:     float64(lw) + float64(mw)/2,
+	})
+}
+
+// WriteJSON writes b to w in the shields.io endpoint format
+// (see https://shields.io/badges/endpoint-badge).
+func (b Badge) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(struct {
+		SchemaVersion int    `json:"schemaVersion"`
+		Label         string `json:"label"`
+		Message       string `json:"message"`
+		Color         string `json:"color"`
+	}{
+		SchemaVersion: 1,
+		Label:         b.Label,
+		Message:       b.Message,
+		Color:         b.Color,
+	})
+}
@@ -0,0 +1,44 @@
+package badge_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/romshark/localize/internal/badge"
+	"github.com/romshark/localize/internal/coverage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMake(t *testing.T) {
+	f := func(t *testing.T, c coverage.Coverage, expectMessage, expectColor string) {
+		t.Helper()
+		b := badge.Make("de", c)
+		require.Equal(t, "de", b.Label)
+		require.Equal(t, expectMessage, b.Message)
+		require.Equal(t, expectColor, b.Color)
+	}
+	f(t, coverage.Coverage{}, "100%", "#4c1")
+	f(t, coverage.Coverage{Total: 10, Translated: 10}, "100%", "#4c1")
+	f(t, coverage.Coverage{Total: 10, Translated: 8}, "80%", "#dfb317")
+	f(t, coverage.Coverage{Total: 3, Translated: 1}, "33%", "#e05d44")
+}
+
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	b := badge.Badge{Label: "a<b", Message: "50%", Color: "#e05d44"}
+	require.NoError(t, b.WriteSVG(&buf))
+	require.Contains(t, buf.String(), `aria-label="a&lt;b: 50%"`)
+	require.Contains(t, buf.String(), `fill="#e05d44"`)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	b := badge.Badge{Label: "de", Message: "50%", Color: "#e05d44"}
+	require.NoError(t, b.WriteJSON(&buf))
+	require.JSONEq(t, `{
+		"schemaVersion": 1,
+		"label": "de",
+		"message": "50%",
+		"color": "#e05d44"
+	}`, buf.String())
+}
@@ -0,0 +1,56 @@
+package cldr
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Root returns the plural forms of the CLDR root locale
+// consisting of form Other only.
+func Root() PluralForms {
+	return PluralForms{
+		NPlurals:           1,
+		CardinalForms:      []CLDRPluralForm{CLDRPluralFormOther},
+		GettextFormula:     "0",
+		GettextPluralForms: "nplurals=1; plural=0",
+		Cardinal:           CLDRForms{Other: true},
+		Range:              CLDRForms{Other: true},
+		OrdinalForms:       []CLDRPluralForm{CLDRPluralFormOther},
+		Ordinal:            CLDRForms{Other: true},
+	}
+}
+
+// Supported returns true if ByTagOrBase finds CLDR plural forms for locale
+// ignoring fallbacks set by SetFallback.
+func Supported(locale language.Tag) bool {
+	if _, ok := byTag[locale]; ok {
+		return true
+	}
+	base, _ := locale.Base()
+	_, ok := byBase[base]
+	return ok
+}
+
+// SetFallback makes ByTag and ByTagOrBase return the plural forms of fallback
+// for locale, which is supposed to be a locale without CLDR data.
+// Root is used if fallback is language.Und.
+// Fallbacks are removed by ResetOverrides.
+func SetFallback(locale, fallback language.Tag) error {
+	p := Root()
+	if fallback != language.Und {
+		var ok bool
+		if p, ok = ByTagOrBase(fallback); !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownLocale, fallback.String())
+		}
+	}
+
+	overridesLock.Lock()
+	defer overridesLock.Unlock()
+	if overrideByTag == nil {
+		overrideByTag = map[language.Tag]PluralForms{}
+		overrideByBase = map[language.Base]PluralForms{}
+	}
+	overrideByTag[locale] = p
+	return nil
+}
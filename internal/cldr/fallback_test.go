@@ -0,0 +1,40 @@
+package cldr_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestSetFallback(t *testing.T) {
+	t.Cleanup(cldr.ResetOverrides)
+
+	klingon := language.MustParse("tlh")
+	require.False(t, cldr.Supported(klingon))
+	require.True(t, cldr.Supported(language.English))
+	require.True(t, cldr.Supported(language.MustParse("de-CH")))
+
+	_, ok := cldr.ByTagOrBase(klingon)
+	require.False(t, ok)
+
+	require.NoError(t, cldr.SetFallback(klingon, language.Und))
+	forms, ok := cldr.ByTagOrBase(klingon)
+	require.True(t, ok)
+	require.Equal(t, cldr.Root(), forms)
+	require.False(t, cldr.Supported(klingon))
+
+	require.NoError(t, cldr.SetFallback(klingon, language.English))
+	forms, ok = cldr.ByTagOrBase(klingon)
+	require.True(t, ok)
+	english, _ := cldr.ByTag(language.English)
+	require.Equal(t, english, forms)
+
+	err := cldr.SetFallback(klingon, language.MustParse("tlh-x-test"))
+	require.ErrorIs(t, err, cldr.ErrUnknownLocale)
+
+	cldr.ResetOverrides()
+	_, ok = cldr.ByTagOrBase(klingon)
+	require.False(t, ok)
+}
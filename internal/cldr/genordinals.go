@@ -0,0 +1,99 @@
+//go:build ignore
+
+// genordinals generates ordinals.json from the CLDR ordinal plural categories
+// compiled into github.com/go-playground/locales, which is the data
+// the generated bundles select ordinal forms by.
+// Regional locales share the categories of their base language.
+// Locales without ordinal plural rules are omitted.
+//
+//	go run genordinals.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	reOrdinals = regexp.MustCompile(`pluralsOrdinal:\s+\[\]locales\.PluralRule\{([\d, ]+)\}`)
+	categories = [...]string{"", "zero", "one", "two", "few", "many", "other"}
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	out, err := exec.Command(
+		"go", "list", "-m", "-f", "{{.Dir}}", "github.com/go-playground/locales",
+	).Output()
+	if err != nil {
+		return fmt.Errorf("locating github.com/go-playground/locales: %w", err)
+	}
+	dir := strings.TrimSpace(string(out))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	ordinals := map[string][]string{}
+	for _, e := range entries {
+		if !e.IsDir() || strings.Contains(e.Name(), "_") {
+			continue // Regional locales share the rules of their base language.
+		}
+		if e.Name() == "root" {
+			continue // The root locale only uses category Other (see Root).
+		}
+		src, err := os.ReadFile(filepath.Join(dir, e.Name(), e.Name()+".go"))
+		if err != nil {
+			continue
+		}
+		m := reOrdinals.FindSubmatch(src)
+		if m == nil {
+			continue // No ordinal plural rules.
+		}
+		var l []string
+		for r := range strings.SplitSeq(string(m[1]), ",") {
+			var i int
+			if _, err := fmt.Sscan(r, &i); err != nil || i < 1 || i >= len(categories) {
+				return fmt.Errorf("%s: unexpected ordinal plural rule %q", e.Name(), r)
+			}
+			l = append(l, categories[i])
+		}
+		ordinals[e.Name()] = l
+	}
+
+	locales := make([]string, 0, len(ordinals))
+	for l := range ordinals {
+		locales = append(locales, l)
+	}
+	slices.Sort(locales)
+
+	var b bytes.Buffer
+	b.WriteString("{\n")
+	for i, l := range locales {
+		fmt.Fprintf(&b, "    %q: [", l)
+		for j, c := range ordinals[l] {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%q", c)
+		}
+		b.WriteString("]")
+		if i < len(locales)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	return os.WriteFile("ordinals.json", b.Bytes(), 0o644)
+}
@@ -0,0 +1,99 @@
+//go:build ignore
+
+// genranges generates pluralranges.json from the CLDR plural ranges
+// compiled into the RangePluralRule methods of github.com/go-playground/locales,
+// which is the data the generated bundles select range forms by.
+// Regional locales share the rules of their base language.
+//
+//	go run genranges.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	reFunc = regexp.MustCompile(`(?s)\) RangePluralRule\(num1 float64.*?\n}\n`)
+	reRule = regexp.MustCompile(`start == locales\.PluralRule(\w+) && ` +
+		`end == locales\.PluralRule(\w+) \{\s*return locales\.PluralRule(\w+)`)
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	out, err := exec.Command(
+		"go", "list", "-m", "-f", "{{.Dir}}", "github.com/go-playground/locales",
+	).Output()
+	if err != nil {
+		return fmt.Errorf("locating github.com/go-playground/locales: %w", err)
+	}
+	dir := strings.TrimSpace(string(out))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	rules := map[string][][3]string{}
+	for _, e := range entries {
+		if !e.IsDir() || strings.Contains(e.Name(), "_") {
+			continue // Regional locales share the rules of their base language.
+		}
+		src, err := os.ReadFile(filepath.Join(dir, e.Name(), e.Name()+".go"))
+		if err != nil {
+			continue
+		}
+		fn := reFunc.Find(src)
+		if fn == nil || bytes.Contains(fn, []byte("return locales.PluralRuleUnknown")) {
+			continue // No CLDR plural ranges.
+		}
+		l := [][3]string{}
+		for _, m := range reRule.FindAllSubmatch(fn, -1) {
+			l = append(l, [3]string{
+				strings.ToLower(string(m[1])),
+				strings.ToLower(string(m[2])),
+				strings.ToLower(string(m[3])),
+			})
+		}
+		rules[strings.ReplaceAll(e.Name(), "_", "-")] = l
+	}
+
+	locales := make([]string, 0, len(rules))
+	for l := range rules {
+		locales = append(locales, l)
+	}
+	slices.Sort(locales)
+
+	var b bytes.Buffer
+	b.WriteString("{\n")
+	for i, l := range locales {
+		fmt.Fprintf(&b, "    %q: [", l)
+		for j, r := range rules[l] {
+			if j > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(&b, "\n        [%q, %q, %q]", r[0], r[1], r[2])
+		}
+		if len(rules[l]) > 0 {
+			b.WriteString("\n    ")
+		}
+		b.WriteString("]")
+		if i < len(locales)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	return os.WriteFile("pluralranges.json", b.Bytes(), 0o644)
+}
@@ -0,0 +1,55 @@
+package cldr
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Generated by genordinals.go from the CLDR ordinal plural categories
+// of github.com/go-playground/locales.
+//
+//go:generate go run genordinals.go
+//go:embed ordinals.json
+var ordinalsJSON []byte
+
+// parseOrdinals parses the ordinal plural categories of ordinals.json.
+func parseOrdinals() map[language.Base][]CLDRPluralForm {
+	var m map[string][]string
+	if err := json.Unmarshal(ordinalsJSON, &m); err != nil {
+		// Should never happen.
+		panic(fmt.Errorf("unmarshaling ordinals.json: %w", err))
+	}
+	byBase := make(map[language.Base][]CLDRPluralForm, len(m))
+	for k, categories := range m {
+		base, err := language.ParseBase(k)
+		if err != nil {
+			panic(fmt.Errorf("parsing base language: %w", err))
+		}
+		l := make([]CLDRPluralForm, len(categories))
+		for i, c := range categories {
+			if l[i], err = ParsePluralForm(c); err != nil {
+				// Should never happen.
+				panic(fmt.Errorf("ordinals of %q: %w", k, err))
+			}
+		}
+		byBase[base] = l
+	}
+	return byBase
+}
+
+// withOrdinals returns p with the ordinal plural categories ordinals.
+// Locales without ordinal plural rules only use category Other.
+func (p PluralForms) withOrdinals(ordinals []CLDRPluralForm) PluralForms {
+	if ordinals == nil {
+		ordinals = []CLDRPluralForm{CLDRPluralFormOther}
+	}
+	p.OrdinalForms = ordinals
+	p.Ordinal = CLDRForms{}
+	for _, f := range ordinals {
+		p.Ordinal.Set(f)
+	}
+	return p
+}
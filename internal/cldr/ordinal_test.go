@@ -0,0 +1,35 @@
+package cldr_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestOrdinals(t *testing.T) {
+	t.Parallel()
+
+	f := func(t *testing.T, locale string, expect ...cldr.CLDRPluralForm) {
+		t.Helper()
+		forms, ok := cldr.ByTagOrBase(language.MustParse(locale))
+		require.True(t, ok)
+		require.Equal(t, expect, forms.OrdinalForms, locale)
+	}
+	f(t, "en", cldr.CLDRPluralFormOne, cldr.CLDRPluralFormTwo,
+		cldr.CLDRPluralFormFew, cldr.CLDRPluralFormOther)
+	f(t, "en-GB", cldr.CLDRPluralFormOne, cldr.CLDRPluralFormTwo,
+		cldr.CLDRPluralFormFew, cldr.CLDRPluralFormOther)
+	f(t, "it", cldr.CLDRPluralFormMany, cldr.CLDRPluralFormOther)
+	f(t, "fr", cldr.CLDRPluralFormOne, cldr.CLDRPluralFormOther)
+	f(t, "de", cldr.CLDRPluralFormOther)
+
+	// Locales without ordinal plural rules only use category Other.
+	f(t, "asa", cldr.CLDRPluralFormOther)
+
+	english, _ := cldr.ByTag(language.English)
+	require.Equal(t, cldr.CLDRForms{One: true, Two: true, Few: true, Other: true},
+		english.Ordinal)
+	require.Equal(t, cldr.CLDRForms{Other: true}, cldr.Root().Ordinal)
+}
@@ -0,0 +1,163 @@
+package cldr
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrUnknownLocale     = errors.New("unknown locale")
+	ErrInvalidPluralForm = errors.New("invalid plural form")
+	ErrInvalidOverride   = errors.New("invalid plural forms override")
+)
+
+var (
+	overridesLock  sync.RWMutex
+	overrideByTag  map[language.Tag]PluralForms
+	overrideByBase map[language.Base]PluralForms
+)
+
+// Override defines project-specific non-CLDR plural forms for a locale.
+type Override struct {
+	Locale language.Tag
+
+	// Merge maps plural forms to the forms they're merged into,
+	// for example {Few: Many} removes form Few such that quantities
+	// falling into category Few use form Many instead.
+	Merge map[CLDRPluralForm]CLDRPluralForm
+}
+
+// ParsePluralForm parses a case-insensitive plural form name like "few".
+func ParsePluralForm(s string) (CLDRPluralForm, error) {
+	switch strings.ToLower(s) {
+	case "zero":
+		return CLDRPluralFormZero, nil
+	case "one":
+		return CLDRPluralFormOne, nil
+	case "two":
+		return CLDRPluralFormTwo, nil
+	case "few":
+		return CLDRPluralFormFew, nil
+	case "many":
+		return CLDRPluralFormMany, nil
+	case "other":
+		return CLDRPluralFormOther, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidPluralForm, s)
+}
+
+// SetOverride overrides the plural forms of o.Locale returned by
+// ByTag, ByBase and ByTagOrBase. If o.Locale is a base-only locale like "ru"
+// then the base language is overridden as well.
+// The CLDR forms of the locale are resolved like in ByTagOrBase
+// and any previous override of the locale is replaced.
+func SetOverride(o Override) error {
+	original, ok := byTag[o.Locale]
+	if !ok {
+		base, _ := o.Locale.Base()
+		original, ok = byBase[base]
+	}
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownLocale, o.Locale.String())
+	}
+	p, err := original.merge(o.Merge)
+	if err != nil {
+		return fmt.Errorf("%w for locale %q: %w",
+			ErrInvalidOverride, o.Locale.String(), err)
+	}
+
+	overridesLock.Lock()
+	defer overridesLock.Unlock()
+	if overrideByTag == nil {
+		overrideByTag = map[language.Tag]PluralForms{}
+		overrideByBase = map[language.Base]PluralForms{}
+	}
+	overrideByTag[o.Locale] = p
+	base, _ := o.Locale.Base()
+	if o.Locale == language.Make(base.String()) {
+		overrideByBase[base] = p
+	}
+	return nil
+}
+
+// ResetOverrides removes all overrides set by SetOverride and SetFallback.
+func ResetOverrides() {
+	overridesLock.Lock()
+	defer overridesLock.Unlock()
+	overrideByTag, overrideByBase = nil, nil
+}
+
+// merge returns a copy of p with the forms merged according to m.
+func (p PluralForms) merge(m map[CLDRPluralForm]CLDRPluralForm) (PluralForms, error) {
+	for from, to := range m {
+		switch {
+		case from == CLDRPluralFormOther:
+			return PluralForms{}, errors.New("form Other can't be merged")
+		case from == to:
+			return PluralForms{}, fmt.Errorf("form %s merged into itself", from)
+		case !slices.Contains(p.CardinalForms, from):
+			return PluralForms{}, fmt.Errorf("form %s isn't supported", from)
+		case !slices.Contains(p.CardinalForms, to):
+			return PluralForms{}, fmt.Errorf("form %s isn't supported", to)
+		}
+		if _, ok := m[to]; ok {
+			return PluralForms{}, fmt.Errorf(
+				"form %s is merged into form %s which is merged itself", from, to,
+			)
+		}
+	}
+
+	r := PluralForms{
+		Merged:   make(map[CLDRPluralForm]CLDRPluralForm, len(m)),
+		Examples: p.Examples,
+	}
+	for _, f := range p.CardinalForms {
+		if to, ok := m[f]; ok {
+			r.Merged[f] = to
+			continue
+		}
+		r.CardinalForms = append(r.CardinalForms, f)
+		r.Cardinal.Set(f)
+	}
+
+	// Remap the indexes of the original gettext formula.
+	formula := "(" + p.GettextFormula + ")"
+	var b strings.Builder
+	for i, f := range p.CardinalForms[:len(p.CardinalForms)-1] {
+		b.WriteString(formula + " == " + strconv.Itoa(i) + " ? ")
+		b.WriteString(strconv.Itoa(slices.Index(r.CardinalForms, r.Resolve(f))))
+		b.WriteString(" : ")
+	}
+	last := p.CardinalForms[len(p.CardinalForms)-1]
+	b.WriteString(strconv.Itoa(slices.Index(r.CardinalForms, r.Resolve(last))))
+	r.NPlurals = len(r.CardinalForms)
+	r.GettextFormula = b.String()
+	r.GettextPluralForms = fmt.Sprintf(
+		"nplurals=%d; plural=%s", r.NPlurals, r.GettextFormula,
+	)
+	return r.withRanges(p.Ranges).withOrdinals(p.OrdinalForms), nil
+}
+
+// Set sets the field of form to true.
+func (f *CLDRForms) Set(form CLDRPluralForm) {
+	switch form {
+	case CLDRPluralFormZero:
+		f.Zero = true
+	case CLDRPluralFormOne:
+		f.One = true
+	case CLDRPluralFormTwo:
+		f.Two = true
+	case CLDRPluralFormFew:
+		f.Few = true
+	case CLDRPluralFormMany:
+		f.Many = true
+	case CLDRPluralFormOther:
+		f.Other = true
+	}
+}
@@ -0,0 +1,110 @@
+package cldr_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestSetOverride(t *testing.T) {
+	// Not parallel since overrides are global.
+	defer cldr.ResetOverrides()
+
+	original, ok := cldr.ByTag(language.Russian)
+	require.True(t, ok)
+	require.Equal(t, []cldr.CLDRPluralForm{
+		cldr.CLDRPluralFormOne,
+		cldr.CLDRPluralFormFew,
+		cldr.CLDRPluralFormOther,
+	}, original.CardinalForms)
+
+	err := cldr.SetOverride(cldr.Override{
+		Locale: language.Russian,
+		Merge: map[cldr.CLDRPluralForm]cldr.CLDRPluralForm{
+			cldr.CLDRPluralFormFew: cldr.CLDRPluralFormOther,
+		},
+	})
+	require.NoError(t, err)
+
+	formula := "(" + original.GettextFormula + ")"
+	expect := cldr.PluralForms{
+		Examples: original.Examples,
+		NPlurals: 2,
+		Cardinal: cldr.CLDRForms{One: true, Other: true},
+		CardinalForms: []cldr.CLDRPluralForm{
+			cldr.CLDRPluralFormOne,
+			cldr.CLDRPluralFormOther,
+		},
+		GettextFormula: formula + " == 0 ? 0 : " +
+			formula + " == 1 ? 1 : 1",
+		Merged: map[cldr.CLDRPluralForm]cldr.CLDRPluralForm{
+			cldr.CLDRPluralFormFew: cldr.CLDRPluralFormOther,
+		},
+		Ranges:       original.Ranges,
+		Range:        cldr.CLDRForms{One: true, Other: true},
+		OrdinalForms: []cldr.CLDRPluralForm{cldr.CLDRPluralFormOther},
+		Ordinal:      cldr.CLDRForms{Other: true},
+	}
+	expect.GettextPluralForms = "nplurals=2; plural=" + expect.GettextFormula
+
+	for _, get := range []func() (cldr.PluralForms, bool){
+		func() (cldr.PluralForms, bool) { return cldr.ByTag(language.Russian) },
+		func() (cldr.PluralForms, bool) { return cldr.ByTagOrBase(language.Russian) },
+		func() (cldr.PluralForms, bool) {
+			return cldr.ByTagOrBase(language.MustParse("ru-UA"))
+		},
+		func() (cldr.PluralForms, bool) {
+			base, _ := language.Russian.Base()
+			return cldr.ByBase(base)
+		},
+	} {
+		forms, ok := get()
+		require.True(t, ok)
+		require.Equal(t, expect, forms)
+	}
+	require.Equal(t, cldr.CLDRPluralFormOther, expect.Resolve(cldr.CLDRPluralFormFew))
+	require.Equal(t, cldr.CLDRPluralFormOne, expect.Resolve(cldr.CLDRPluralFormOne))
+
+	cldr.ResetOverrides()
+	forms, ok := cldr.ByTag(language.Russian)
+	require.True(t, ok)
+	require.Equal(t, original, forms)
+}
+
+func TestSetOverrideErr(t *testing.T) {
+	// Not parallel since overrides are global.
+	defer cldr.ResetOverrides()
+
+	f := func(t *testing.T, locale language.Tag, from, to cldr.CLDRPluralForm) {
+		t.Helper()
+		err := cldr.SetOverride(cldr.Override{
+			Locale: locale,
+			Merge:  map[cldr.CLDRPluralForm]cldr.CLDRPluralForm{from: to},
+		})
+		require.ErrorIs(t, err, cldr.ErrInvalidOverride)
+	}
+	f(t, language.Russian, cldr.CLDRPluralFormOther, cldr.CLDRPluralFormOne)
+	f(t, language.Russian, cldr.CLDRPluralFormFew, cldr.CLDRPluralFormFew)
+	f(t, language.Russian, cldr.CLDRPluralFormTwo, cldr.CLDRPluralFormOther)
+	f(t, language.English, cldr.CLDRPluralFormOne, cldr.CLDRPluralFormFew)
+
+	err := cldr.SetOverride(cldr.Override{
+		Locale: language.MustParse("ga"),
+		Merge: map[cldr.CLDRPluralForm]cldr.CLDRPluralForm{
+			cldr.CLDRPluralFormFew:  cldr.CLDRPluralFormMany,
+			cldr.CLDRPluralFormMany: cldr.CLDRPluralFormOther,
+		},
+	})
+	require.ErrorIs(t, err, cldr.ErrInvalidOverride)
+}
+
+func TestParsePluralForm(t *testing.T) {
+	f, err := cldr.ParsePluralForm("Few")
+	require.NoError(t, err)
+	require.Equal(t, cldr.CLDRPluralFormFew, f)
+
+	_, err = cldr.ParsePluralForm("several")
+	require.ErrorIs(t, err, cldr.ErrInvalidPluralForm)
+}
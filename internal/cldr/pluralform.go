@@ -6,6 +6,9 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"golang.org/x/text/language"
 )
@@ -18,13 +21,17 @@ var languagesJSON []byte
 var (
 	byBase map[language.Base]PluralForms
 	byTag  map[language.Tag]PluralForms
+
+	// supportedLocales are all locales of byTag sorted by BCP 47 notation.
+	supportedLocales []language.Tag
 )
 
 func init() {
 	var m map[string]struct {
-		Cases   []string `json:"cases"`
-		Plurals int      `json:"plurals"`
-		Formula string   `json:"formula"`
+		Cases    []string          `json:"cases"`
+		Plurals  int               `json:"plurals"`
+		Formula  string            `json:"formula"`
+		Examples map[string]string `json:"examples"`
 	}
 	if err := json.Unmarshal(languagesJSON, &m); err != nil {
 		// Should never happen. If this happens, it means
@@ -32,6 +39,7 @@ func init() {
 		panic(fmt.Errorf("unmarshaling languages.json: %w", err))
 	}
 
+	ranges, ordinals := parseRanges(), parseOrdinals()
 	byBase = make(map[language.Base]PluralForms, len(byTag))
 	byTag = make(map[language.Tag]PluralForms, len(byTag))
 	for k, v := range m {
@@ -40,12 +48,20 @@ func init() {
 			panic(fmt.Errorf("parsing language BCP 47: %w", err))
 		}
 
+		if v.Formula == "" || v.Plurals < 1 || v.Plurals != len(v.Cases) {
+			// Should never happen.
+			panic(fmt.Errorf("invalid plural forms for %q: nplurals=%d; "+
+				"plural=%s; cases: %v", k, v.Plurals, v.Formula, v.Cases))
+		}
+
 		p := PluralForms{
+			NPlurals:       v.Plurals,
 			GettextFormula: v.Formula,
 			GettextPluralForms: fmt.Sprintf(
 				"nplurals=%d; plural=%s", v.Plurals, v.Formula,
 			),
 			CardinalForms: make([]CLDRPluralForm, v.Plurals),
+			Examples:      make(map[CLDRPluralForm][]int, v.Plurals),
 		}
 		for i, c := range v.Cases {
 			switch c {
@@ -67,20 +83,101 @@ func init() {
 			case "other":
 				p.Cardinal.Other = true
 				p.CardinalForms[i] = CLDRPluralFormOther
+			default:
+				// Should never happen.
+				panic(fmt.Errorf("unknown plural form %q for %q", c, k))
 			}
+			p.Examples[p.CardinalForms[i]] = parseExamples(v.Examples[c])
 		}
-		byTag[t] = p
+		// Regional locales share the plural ranges and ordinal categories
+		// of their base language.
+		base, _ := t.Base()
+		byTag[t] = p.withRanges(ranges[base]).withOrdinals(ordinals[base])
+		supportedLocales = append(supportedLocales, t)
+	}
 
+	// Index by base language. The base-only locale like "pt" takes precedence
+	// over region-qualified ones like "pt-PT".
+	slices.SortFunc(supportedLocales, func(a, b language.Tag) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, t := range supportedLocales {
 		base, _ := t.Base()
-		byBase[base] = p
+		if _, ok := byBase[base]; !ok || t == language.Make(base.String()) {
+			byBase[base] = byTag[t]
+		}
 	}
 }
 
+// SupportedLocales returns all locales with CLDR plural rules sorted by
+// BCP 47 notation. Locales not listed are resolved by their base language
+// by ByTagOrBase if the base language is supported.
+func SupportedLocales() []language.Tag {
+	return slices.Clone(supportedLocales)
+}
+
 type PluralForms struct {
+	// NPlurals is the number of plural forms (nplurals) in GettextPluralForms
+	// and always equals len(CardinalForms).
+	NPlurals int
+
 	CardinalForms      []CLDRPluralForm
 	GettextFormula     string
 	GettextPluralForms string
 	Cardinal           CLDRForms
+
+	// Examples are the integer sample quantities of each form
+	// of the CLDR plural rules.
+	Examples map[CLDRPluralForm][]int
+
+	// Merged maps CLDR plural forms removed by an Override
+	// to the forms they're merged into. Nil if not overridden.
+	Merged map[CLDRPluralForm]CLDRPluralForm
+
+	// Ranges are the CLDR plural range rules (see RangeForm),
+	// nil if CLDR defines no plural ranges for the locale.
+	Ranges []PluralRange
+
+	// Range are the forms selected for ranges of quantities by RangeForm.
+	Range CLDRForms
+
+	// OrdinalForms are the CLDR ordinal plural categories, like
+	// One, Two, Few and Other for English "1st", "2nd", "3rd" and "4th".
+	// Overrides don't affect ordinal categories.
+	OrdinalForms []CLDRPluralForm
+	Ordinal      CLDRForms
+}
+
+// parseExamples parses CLDR sample lists like "0, 5~19, 100, 1c6, …"
+// into the integers they contain. Samples in compact decimal notation
+// like "1c6" are skipped.
+func parseExamples(s string) (l []int) {
+	for e := range strings.SplitSeq(s, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(e), "~")
+		a, err := strconv.Atoi(from)
+		if err != nil {
+			continue
+		}
+		b := a
+		if isRange {
+			if b, err = strconv.Atoi(to); err != nil {
+				continue
+			}
+		}
+		for n := a; n <= b; n++ {
+			l = append(l, n)
+		}
+	}
+	return l
+}
+
+// Resolve returns the form used for quantities in the CLDR category f,
+// which is f itself unless merged into another form by an Override.
+func (p PluralForms) Resolve(f CLDRPluralForm) CLDRPluralForm {
+	if to, ok := p.Merged[f]; ok {
+		return to
+	}
+	return f
 }
 
 type CLDRForms struct{ Zero, One, Two, Few, Many, Other bool }
@@ -118,12 +215,22 @@ func (p CLDRPluralForm) String() string {
 
 // ByBase returns the PluralForms corresponding to locale.
 func ByBase(base language.Base) (f PluralForms, ok bool) {
+	overridesLock.RLock()
+	defer overridesLock.RUnlock()
+	if f, ok = overrideByBase[base]; ok {
+		return f, ok
+	}
 	f, ok = byBase[base]
 	return f, ok
 }
 
 // ByTag returns the PluralForms corresponding to locale.
 func ByTag(locale language.Tag) (f PluralForms, ok bool) {
+	overridesLock.RLock()
+	defer overridesLock.RUnlock()
+	if f, ok = overrideByTag[locale]; ok {
+		return f, ok
+	}
 	f, ok = byTag[locale]
 	return f, ok
 }
@@ -131,11 +238,10 @@ func ByTag(locale language.Tag) (f PluralForms, ok bool) {
 // ByTagOrBase returns the PluralForms corresponding to locale.
 // If locale couldn't be found, the base language of locale is used.
 func ByTagOrBase(locale language.Tag) (f PluralForms, ok bool) {
-	f, ok = byTag[locale]
+	f, ok = ByTag(locale)
 	if !ok {
 		base, _ := locale.Base()
-		f, ok := byBase[base]
-		return f, ok
+		return ByBase(base)
 	}
 	return f, ok
 }
@@ -2,6 +2,9 @@ package cldr_test
 
 import (
 	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/romshark/localize/internal/cldr"
@@ -16,6 +19,9 @@ func TestPluralFormsTag(t *testing.T) {
 		t.Helper()
 		forms, ok := cldr.ByTag(lang)
 		require.True(t, ok)
+		// See TestPluralFormsExamples, TestPluralRanges and TestOrdinals.
+		forms.Examples, forms.Ranges, forms.Range = nil, nil, cldr.CLDRForms{}
+		forms.OrdinalForms, forms.Ordinal = nil, cldr.CLDRForms{}
 		require.Equal(t, expect, forms)
 	}
 
@@ -27,6 +33,7 @@ func TestPluralFormsTag(t *testing.T) {
 				cldr.CLDRPluralFormOther,
 			},
 			GettextFormula:     "n != 1",
+			NPlurals:           2,
 			GettextPluralForms: "nplurals=2; plural=n != 1",
 		}
 		f(t, language.Afrikaans, forms)
@@ -51,6 +58,7 @@ func TestPluralFormsTag(t *testing.T) {
 			},
 			GettextFormula: "(n % 10 == 1 && n % 100 != 11) ? 0 : " +
 				"((n % 10 >= 2 && n % 10 <= 4 && (n % 100 < 12 || n % 100 > 14)) ? 1 : 2)",
+			NPlurals: 3,
 			GettextPluralForms: "nplurals=3; plural=" +
 				"(n % 10 == 1 && n % 100 != 11) ? 0 : " +
 				"((n % 10 >= 2 && n % 10 <= 4 && (n % 100 < 12 || n % 100 > 14)) ? 1 : 2)",
@@ -71,6 +79,7 @@ func TestPluralFormsTag(t *testing.T) {
 			},
 			GettextFormula: "(n == 1) ? 0 : ((n % 10 >= 2 && n % 10 <= 4 && " +
 				"(n % 100 < 12 || n % 100 > 14)) ? 1 : 2)",
+			NPlurals: 3,
 			GettextPluralForms: "nplurals=3; plural=" +
 				"(n == 1) ? 0 : ((n % 10 >= 2 && n % 10 <= 4 && " +
 				"(n % 100 < 12 || n % 100 > 14)) ? 1 : 2)",
@@ -95,6 +104,9 @@ func TestPluralFormsBase(t *testing.T) {
 		base, _ := locale.Base()
 		forms, ok := cldr.ByBase(base)
 		require.True(t, ok)
+		// See TestPluralFormsExamples, TestPluralRanges and TestOrdinals.
+		forms.Examples, forms.Ranges, forms.Range = nil, nil, cldr.CLDRForms{}
+		forms.OrdinalForms, forms.Ordinal = nil, cldr.CLDRForms{}
 		require.Equal(t, expect, forms)
 	}
 
@@ -106,6 +118,7 @@ func TestPluralFormsBase(t *testing.T) {
 				cldr.CLDRPluralFormOther,
 			},
 			GettextFormula:     "n != 1",
+			NPlurals:           2,
 			GettextPluralForms: "nplurals=2; plural=n != 1",
 		}
 		f(t, forms, language.AmericanEnglish)
@@ -113,6 +126,28 @@ func TestPluralFormsBase(t *testing.T) {
 	}
 }
 
+func TestPluralFormsExamples(t *testing.T) {
+	t.Parallel()
+
+	forms, ok := cldr.ByTag(language.Russian)
+	require.True(t, ok)
+	require.Equal(t, map[cldr.CLDRPluralForm][]int{
+		cldr.CLDRPluralFormOne: {1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		cldr.CLDRPluralFormFew: {
+			2, 3, 4, 22, 23, 24, 32, 33, 34, 42, 43, 44, 52, 53, 54, 62, 102, 1002,
+		},
+		cldr.CLDRPluralFormOther: {
+			0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19,
+			100, 1000, 10000, 100000, 1000000,
+		},
+	}, forms.Examples)
+
+	// Samples in compact decimal notation are skipped.
+	forms, ok = cldr.ByTag(language.French)
+	require.True(t, ok)
+	require.Equal(t, []int{1000000}, forms.Examples[cldr.CLDRPluralFormMany])
+}
+
 func TestCLDRPluralFormString(t *testing.T) {
 	t.Parallel()
 	require.Equal(t, "", cldr.CLDRPluralForm(0).String())
@@ -123,3 +158,38 @@ func TestCLDRPluralFormString(t *testing.T) {
 	require.Equal(t, "Many", cldr.CLDRPluralFormMany.String())
 	require.Equal(t, "Other", cldr.CLDRPluralFormOther.String())
 }
+
+func TestSupportedLocales(t *testing.T) {
+	t.Parallel()
+	l := cldr.SupportedLocales()
+	require.Len(t, l, 216)
+	require.Contains(t, l, language.English)
+	require.Contains(t, l, language.MustParse("pt-PT"))
+	require.True(t, slices.IsSortedFunc(l, func(a, b language.Tag) int {
+		return strings.Compare(a.String(), b.String())
+	}))
+
+	for _, locale := range l {
+		forms, ok := cldr.ByTag(locale)
+		require.True(t, ok, locale)
+		require.NotZero(t, forms.NPlurals, locale)
+		require.Len(t, forms.CardinalForms, forms.NPlurals, locale)
+		require.NotEmpty(t, forms.GettextFormula, locale)
+		require.Equal(t,
+			fmt.Sprintf("nplurals=%d; plural=%s", forms.NPlurals, forms.GettextFormula),
+			forms.GettextPluralForms, locale)
+		require.True(t, forms.Cardinal.Other, locale)
+	}
+
+	// The returned slice is a copy.
+	l[0] = language.Und
+	require.NotEqual(t, language.Und, cldr.SupportedLocales()[0])
+
+	// Base-only locales take precedence over region-qualified ones.
+	pt, ok := cldr.ByTag(language.Portuguese)
+	require.True(t, ok)
+	ptBase, _ := language.Portuguese.Base()
+	byBase, ok := cldr.ByBase(ptBase)
+	require.True(t, ok)
+	require.Equal(t, pt, byBase)
+}
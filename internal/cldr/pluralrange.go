@@ -0,0 +1,82 @@
+package cldr
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+// Generated by genranges.go from the CLDR plural ranges
+// of github.com/go-playground/locales.
+//
+//go:generate go run genranges.go
+//go:embed pluralranges.json
+var pluralRangesJSON []byte
+
+// PluralRange is a CLDR plural range rule selecting form Result for ranges
+// of quantities like "1–3" starting in category Start and ending in End.
+type PluralRange struct{ Start, End, Result CLDRPluralForm }
+
+// parseRanges parses the plural range rules of pluralranges.json.
+func parseRanges() map[language.Base][]PluralRange {
+	var m map[string][][3]string
+	if err := json.Unmarshal(pluralRangesJSON, &m); err != nil {
+		// Should never happen.
+		panic(fmt.Errorf("unmarshaling pluralranges.json: %w", err))
+	}
+	byBase := make(map[language.Base][]PluralRange, len(m))
+	for k, rules := range m {
+		base, err := language.ParseBase(k)
+		if err != nil {
+			panic(fmt.Errorf("parsing base language: %w", err))
+		}
+		l := make([]PluralRange, len(rules))
+		for i, r := range rules {
+			for j, f := range []*CLDRPluralForm{&l[i].Start, &l[i].End, &l[i].Result} {
+				if *f, err = ParsePluralForm(r[j]); err != nil {
+					// Should never happen.
+					panic(fmt.Errorf("plural ranges of %q: %w", k, err))
+				}
+			}
+		}
+		byBase[base] = l
+	}
+	return byBase
+}
+
+// withRanges returns p with the plural range rules ranges
+// and the forms selected by them.
+func (p PluralForms) withRanges(ranges []PluralRange) PluralForms {
+	p.Ranges = ranges
+	p.Range = CLDRForms{}
+	// Quantities fall into the CLDR categories including merged ones.
+	categories := slices.Clone(p.CardinalForms)
+	for f := range p.Merged {
+		categories = append(categories, f)
+	}
+	for _, start := range categories {
+		for _, end := range categories {
+			p.Range.Set(p.RangeForm(start, end))
+		}
+	}
+	return p
+}
+
+// RangeForm returns the form used for ranges of quantities starting in
+// category start and ending in category end according to p.Ranges,
+// which is Other for ranges without a rule. If CLDR defines no plural ranges
+// for the locale then ranges use the form of their end.
+func (p PluralForms) RangeForm(start, end CLDRPluralForm) CLDRPluralForm {
+	if p.Ranges == nil {
+		return p.Resolve(end)
+	}
+	for _, r := range p.Ranges {
+		if r.Start == start && r.End == end {
+			return p.Resolve(r.Result)
+		}
+	}
+	return CLDRPluralFormOther
+}
@@ -0,0 +1,68 @@
+package cldr_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestPluralRanges(t *testing.T) {
+	t.Parallel()
+
+	const (
+		zero  = cldr.CLDRPluralFormZero
+		one   = cldr.CLDRPluralFormOne
+		few   = cldr.CLDRPluralFormFew
+		other = cldr.CLDRPluralFormOther
+	)
+	f := func(t *testing.T, locale string, start, end, expect cldr.CLDRPluralForm) {
+		t.Helper()
+		forms, ok := cldr.ByTagOrBase(language.MustParse(locale))
+		require.True(t, ok)
+		require.Equal(t, expect, forms.RangeForm(start, end),
+			"%s: %s–%s", locale, start, end)
+	}
+	f(t, "en", one, other, other)
+	f(t, "en", other, other, other)
+	f(t, "de", one, other, other)
+	f(t, "de", other, one, one)
+	f(t, "de-CH", other, one, one)
+	f(t, "ru", one, few, few)
+	f(t, "ru", few, one, one)
+	f(t, "ru", one, other, other)
+	f(t, "ar", zero, one, zero)
+	f(t, "ar", one, few, few)
+
+	english, _ := cldr.ByTag(language.English)
+	require.Equal(t, cldr.CLDRForms{Other: true}, english.Range)
+	russian, _ := cldr.ByTag(language.Russian)
+	require.Equal(t, cldr.CLDRForms{One: true, Few: true, Other: true}, russian.Range)
+
+	// Ranges use the form of their end if CLDR defines no plural ranges.
+	require.Equal(t, cldr.CLDRForms{Other: true}, cldr.Root().Range)
+	for _, l := range cldr.SupportedLocales() {
+		forms, _ := cldr.ByTag(l)
+		if forms.Ranges == nil {
+			require.Equal(t, forms.Cardinal, forms.Range, l.String())
+		}
+	}
+}
+
+func TestPluralRangesOverride(t *testing.T) {
+	// Not parallel since overrides are global.
+	defer cldr.ResetOverrides()
+
+	err := cldr.SetOverride(cldr.Override{
+		Locale: language.Russian,
+		Merge: map[cldr.CLDRPluralForm]cldr.CLDRPluralForm{
+			cldr.CLDRPluralFormFew: cldr.CLDRPluralFormOther,
+		},
+	})
+	require.NoError(t, err)
+	forms, _ := cldr.ByTag(language.Russian)
+	require.Equal(t, cldr.CLDRPluralFormOther,
+		forms.RangeForm(cldr.CLDRPluralFormOne, cldr.CLDRPluralFormFew))
+	require.Equal(t, cldr.CLDRForms{One: true, Other: true}, forms.Range)
+}
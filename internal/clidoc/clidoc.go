@@ -0,0 +1,320 @@
+// Package clidoc generates shell completion scripts and man pages
+// from the declarative CLI command tree.
+package clidoc
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/romshark/localize/internal/config"
+)
+
+// flagInfo is a flag of a command.
+type flagInfo struct {
+	Name    string
+	Usage   string
+	Default string
+	// Arg is the name of the value argument, empty for boolean flags.
+	Arg    string
+	Values []string
+}
+
+func flagsOf(c config.Command) []flagInfo {
+	var l []flagInfo
+	c.FlagSet().VisitAll(func(f *flag.Flag) {
+		arg, usage := flag.UnquoteUsage(f)
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			arg = ""
+		} else if arg == "" {
+			arg = "value"
+		}
+		l = append(l, flagInfo{
+			Name:    f.Name,
+			Usage:   usage,
+			Default: f.DefValue,
+			Arg:     arg,
+			Values:  c.FlagValues[f.Name],
+		})
+	})
+	return l
+}
+
+// summary returns the first sentence of s.
+func summary(s string) string {
+	if i := strings.Index(s, ". "); i != -1 {
+		return s[:i]
+	}
+	return strings.TrimSuffix(s, ".")
+}
+
+// WriteCompletions writes the completion script for shell to w.
+func WriteCompletions(
+	w io.Writer, shell, program string,
+	global config.Command, cmds []config.Command,
+) error {
+	switch shell {
+	case "bash":
+		return WriteBash(w, program, global, cmds)
+	case "zsh":
+		return WriteZsh(w, program, global, cmds)
+	case "fish":
+		return WriteFish(w, program, global, cmds)
+	}
+	return fmt.Errorf("unsupported shell: %q", shell)
+}
+
+// WriteBash writes the bash completion script to w.
+func WriteBash(
+	w io.Writer, program string, global config.Command, cmds []config.Command,
+) error {
+	var b strings.Builder
+	fn := "_" + identifier(program)
+	var words, globalWithArg []string
+	for _, c := range cmds {
+		words = append(words, c.Name)
+	}
+	for _, f := range flagsOf(global) {
+		words = append(words, "-"+f.Name)
+		if f.Arg != "" {
+			globalWithArg = append(globalWithArg, "-"+f.Name)
+		}
+	}
+
+	fmt.Fprintf(&b, "# bash completion for %s\n\n", program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("\tlocal cur prev cmd i\n")
+	b.WriteString("\tcur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("\tprev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	// Find the command skipping global flags and their values.
+	b.WriteString("\tfor ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("\t\tcase \"${COMP_WORDS[i]}\" in\n")
+	if len(globalWithArg) > 0 {
+		fmt.Fprintf(&b, "\t\t%s)\n\t\t\t((i++))\n\t\t\t;;\n",
+			strings.Join(globalWithArg, "|"))
+	}
+	b.WriteString("\t\t-*) ;;\n")
+	b.WriteString("\t\t*)\n\t\t\tcmd=\"${COMP_WORDS[i]}\"\n\t\t\tbreak\n\t\t\t;;\n")
+	b.WriteString("\t\tesac\n\tdone\n")
+	b.WriteString("\tif [ -z \"$cmd\" ]; then\n")
+	if len(globalWithArg) > 0 {
+		fmt.Fprintf(&b, "\t\tcase \"$prev\" in\n\t\t%s)\n\t\t\treturn\n\t\t\t;;\n\t\tesac\n",
+			strings.Join(globalWithArg, "|"))
+	}
+	fmt.Fprintf(&b, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n",
+		strings.Join(words, " "))
+	b.WriteString("\t\treturn\n\tfi\n")
+	b.WriteString("\tcase \"$cmd\" in\n")
+	for _, c := range cmds {
+		fmt.Fprintf(&b, "\t%s)\n", c.Name)
+		var names, withArg []string
+		var cases strings.Builder
+		for _, f := range flagsOf(c) {
+			names = append(names, "-"+f.Name)
+			if f.Arg == "" {
+				continue
+			}
+			if len(f.Values) > 0 {
+				fmt.Fprintf(&cases, "\t\t-%s)\n\t\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))"+
+					"\n\t\t\treturn\n\t\t\t;;\n", f.Name, strings.Join(f.Values, " "))
+				continue
+			}
+			withArg = append(withArg, "-"+f.Name)
+		}
+		if len(withArg) > 0 {
+			// Fall back to the default completion for flag values.
+			fmt.Fprintf(&cases, "\t\t%s)\n\t\t\treturn\n\t\t\t;;\n",
+				strings.Join(withArg, "|"))
+		}
+		if cases.Len() > 0 {
+			fmt.Fprintf(&b, "\t\tcase \"$prev\" in\n%s\t\tesac\n", cases.String())
+		}
+		words := append(names, c.Args...)
+		fmt.Fprintf(&b, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n",
+			strings.Join(words, " "))
+		b.WriteString("\t\t;;\n")
+	}
+	b.WriteString("\tesac\n}\n\n")
+	fmt.Fprintf(&b, "complete -o default -F %s %s\n", fn, program)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteZsh writes the zsh completion script to w.
+func WriteZsh(
+	w io.Writer, program string, global config.Command, cmds []config.Command,
+) error {
+	var b strings.Builder
+	fn := "_" + identifier(program)
+
+	fmt.Fprintf(&b, "#compdef %s\n\n", program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("\tlocal curcontext=\"$curcontext\" state line\n")
+	b.WriteString("\tlocal -a commands\n\tcommands=(\n")
+	for _, c := range cmds {
+		fmt.Fprintf(&b, "\t\t%s\n", zshQuote(c.Name+":"+summary(c.Description)))
+	}
+	b.WriteString("\t)\n")
+	b.WriteString("\t_arguments -C")
+	writeZshSpecs(&b, "\t\t", flagsOf(global))
+	b.WriteString(" \\\n\t\t'1:command:->command' \\\n\t\t'*::argument:->argument'\n")
+	b.WriteString("\tcase $state in\n")
+	b.WriteString("\tcommand)\n\t\t_describe 'command' commands\n\t\t;;\n")
+	b.WriteString("\targument)\n")
+	b.WriteString("\t\tcase $words[1] in\n")
+	for _, c := range cmds {
+		fmt.Fprintf(&b, "\t\t%s)\n\t\t\t_arguments", c.Name)
+		writeZshSpecs(&b, "\t\t\t\t", flagsOf(c))
+		if len(c.Args) > 0 {
+			fmt.Fprintf(&b, " \\\n\t\t\t\t%s",
+				zshQuote("1:argument:("+strings.Join(c.Args, " ")+")"))
+		}
+		b.WriteString("\n\t\t\t;;\n")
+	}
+	b.WriteString("\t\tesac\n\t\t;;\n\tesac\n}\n\n")
+	fmt.Fprintf(&b, "%s \"$@\"\n", fn)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeZshSpecs(b *strings.Builder, indent string, flags []flagInfo) {
+	for _, f := range flags {
+		spec := "-" + f.Name + "[" + zshEscape(summary(f.Usage)) + "]"
+		if f.Arg != "" {
+			spec += ":" + zshEscape(f.Arg) + ":"
+			if len(f.Values) > 0 {
+				spec += "(" + strings.Join(f.Values, " ") + ")"
+			} else {
+				spec += "_files"
+			}
+		}
+		fmt.Fprintf(b, " \\\n%s%s", indent, zshQuote(spec))
+	}
+}
+
+// WriteFish writes the fish completion script to w.
+func WriteFish(
+	w io.Writer, program string, global config.Command, cmds []config.Command,
+) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# fish completion for %s\n\n", program)
+	fmt.Fprintf(&b, "complete -c %s -f\n", program)
+	writeFishFlags(&b, program, "__fish_use_subcommand", global)
+	for _, c := range cmds {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a %s -d %s\n",
+			program, c.Name, fishQuote(summary(c.Description)))
+	}
+	for _, c := range cmds {
+		cond := fishQuote("__fish_seen_subcommand_from " + c.Name)
+		writeFishFlags(&b, program, cond, c)
+		if len(c.Args) > 0 {
+			fmt.Fprintf(&b, "complete -c %s -n %s -x -a %s\n",
+				program, cond, fishQuote(strings.Join(c.Args, " ")))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeFishFlags(b *strings.Builder, program, cond string, c config.Command) {
+	for _, f := range flagsOf(c) {
+		fmt.Fprintf(b, "complete -c %s -n %s -o %s -d %s",
+			program, cond, f.Name, fishQuote(summary(f.Usage)))
+		switch {
+		case len(f.Values) > 0:
+			fmt.Fprintf(b, " -x -a %s", fishQuote(strings.Join(f.Values, " ")))
+		case f.Arg != "":
+			b.WriteString(" -r -F")
+		}
+		b.WriteByte('\n')
+	}
+}
+
+// WriteMan writes the man page in roff format to w.
+func WriteMan(
+	w io.Writer, program, description string,
+	global config.Command, cmds []config.Command,
+) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, ".TH %s 1\n", strings.ToUpper(roffEscape(program)))
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n",
+		roffEscape(program), roffEscape(description))
+	fmt.Fprintf(&b, ".SH SYNOPSIS\n.B %s\n"+
+		"[\\fIglobal flags\\fR] \\fIcommand\\fR [\\fIflags\\fR]\n",
+		roffEscape(program))
+	if flags := flagsOf(global); len(flags) > 0 {
+		b.WriteString(".SH GLOBAL FLAGS\n")
+		writeManFlags(&b, flags)
+	}
+	b.WriteString(".SH COMMANDS\n")
+	for _, c := range cmds {
+		fmt.Fprintf(&b, ".SS %s\n%s\n", roffEscape(c.Name), roffEscape(c.Description))
+		if a := c.ArgUsage(); a != "" {
+			fmt.Fprintf(&b, ".PP\n.B %s %s\n\\fI%s\\fR\n",
+				roffEscape(program), roffEscape(c.Name), roffEscape(a))
+		}
+		writeManFlags(&b, flagsOf(c))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeManFlags(b *strings.Builder, flags []flagInfo) {
+	for _, f := range flags {
+		b.WriteString(".TP\n")
+		fmt.Fprintf(b, ".B \\-%s", roffEscape(f.Name))
+		if f.Arg != "" {
+			fmt.Fprintf(b, " \\fI%s\\fR", roffEscape(f.Arg))
+		}
+		b.WriteByte('\n')
+		b.WriteString(roffEscape(f.Usage))
+		switch f.Default {
+		case "", "false", "0", "0s":
+		default:
+			fmt.Fprintf(b, " (default: %s)", roffEscape(f.Default))
+		}
+		b.WriteByte('\n')
+	}
+}
+
+// identifier replaces all characters of s that aren't valid
+// in shell function names with underscores.
+func identifier(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' ||
+			r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// zshEscape escapes characters with special meaning in _arguments specs.
+func zshEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`, `[`, `\[`, `]`, `\]`, `:`, `\:`,
+	).Replace(s)
+}
+
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, `-`, `\-`).Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
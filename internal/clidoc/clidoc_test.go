@@ -0,0 +1,179 @@
+package clidoc_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/romshark/localize/internal/clidoc"
+	"github.com/romshark/localize/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testGlobal = config.Command{
+	Flags: func(cli *flag.FlagSet) {
+		cli.Bool("v", false, "verbose")
+		cli.String("config", "", "config file path")
+	},
+}
+
+var testCommands = []config.Command{
+	{
+		Name:        "run",
+		Description: "Run the thing. Details follow.",
+		FlagValues:  map[string][]string{"f": {"a", "b"}},
+		Flags: func(cli *flag.FlagSet) {
+			cli.String("f", "a", "output `format`")
+			cli.String("o", "", "output file path")
+			cli.Bool("q", false, "disable logging")
+		},
+	},
+	{Name: "completions", Description: "Print completions.", Args: config.Shells},
+}
+
+func TestWriteCompletions(t *testing.T) {
+	f := func(t *testing.T, shell string, expectContains ...string) {
+		t.Helper()
+		var buf bytes.Buffer
+		err := clidoc.WriteCompletions(&buf, shell, "prog", testGlobal, testCommands)
+		require.NoError(t, err)
+		for _, s := range expectContains {
+			require.Contains(t, buf.String(), s)
+		}
+	}
+
+	f(t, "bash",
+		"\t\t-config)\n\t\t\t((i++))",
+		`COMPREPLY=($(compgen -W "run completions -config -v" -- "$cur"))`,
+		"\t\t-f)\n\t\t\tCOMPREPLY=($(compgen -W \"a b\" -- \"$cur\"))",
+		"\t\t-o)\n\t\t\treturn",
+		`COMPREPLY=($(compgen -W "-f -o -q" -- "$cur"))`,
+		`COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))`,
+		"complete -o default -F _prog prog\n")
+	f(t, "zsh",
+		"#compdef prog\n",
+		"'run:Run the thing'",
+		"'-config[config file path]:string:_files'",
+		"'-f[output format]:format:(a b)'",
+		"'-o[output file path]:string:_files'",
+		"'-q[disable logging]'",
+		"'1:argument:(bash zsh fish)'")
+	f(t, "fish",
+		"complete -c prog -n __fish_use_subcommand -o v -d 'verbose'\n",
+		"complete -c prog -n __fish_use_subcommand -a run -d 'Run the thing'\n",
+		"complete -c prog -n '__fish_seen_subcommand_from run' "+
+			"-o f -d 'output format' -x -a 'a b'\n",
+		"complete -c prog -n '__fish_seen_subcommand_from run' "+
+			"-o o -d 'output file path' -r -F\n",
+		"complete -c prog -n '__fish_seen_subcommand_from run' "+
+			"-o q -d 'disable logging'\n",
+		"complete -c prog -n '__fish_seen_subcommand_from completions' "+
+			"-x -a 'bash zsh fish'\n")
+
+	var buf bytes.Buffer
+	err := clidoc.WriteCompletions(
+		&buf, "powershell", "prog", testGlobal, testCommands,
+	)
+	require.Error(t, err)
+}
+
+func TestWriteMan(t *testing.T) {
+	var buf bytes.Buffer
+	err := clidoc.WriteMan(&buf, "prog", "does things", testGlobal, testCommands)
+	require.NoError(t, err)
+	require.Equal(t, `.TH PROG 1
+.SH NAME
+prog \- does things
+.SH SYNOPSIS
+.B prog
+[\fIglobal flags\fR] \fIcommand\fR [\fIflags\fR]
+.SH GLOBAL FLAGS
+.TP
+.B \-config \fIstring\fR
+config file path
+.TP
+.B \-v
+verbose
+.SH COMMANDS
+.SS run
+Run the thing. Details follow.
+.TP
+.B \-f \fIformat\fR
+output format (default: a)
+.TP
+.B \-o \fIstring\fR
+output file path
+.TP
+.B \-q
+disable logging
+.SS completions
+Print completions.
+.PP
+.B prog completions
+\fIbash|zsh|fish\fR
+`, buf.String())
+}
+
+func TestWriteSchema(t *testing.T) {
+	var buf bytes.Buffer
+	err := clidoc.WriteSchema(&buf, "prog configuration", []config.Command{
+		testCommands[0],
+		{
+			Name:        "list",
+			Description: "List things.",
+			Flags: func(cli *flag.FlagSet) {
+				cli.Int("n", 10, "maximum number of things")
+				cli.Func("tag", "filter by tag (can be repeated)",
+					func(string) error { return nil })
+			},
+		},
+		testCommands[1],
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "prog configuration",
+		"type": "object",
+		"properties": {
+			"$schema": {"type": "string"},
+			"run": {
+				"description": "Run the thing. Details follow.",
+				"type": "object",
+				"properties": {
+					"f": {
+						"description": "output format", "type": "string",
+						"enum": ["a", "b"], "default": "a"
+					},
+					"o": {"description": "output file path", "type": "string"},
+					"q": {"description": "disable logging", "type": "boolean"}
+				},
+				"additionalProperties": false
+			},
+			"list": {
+				"description": "List things.",
+				"type": "object",
+				"properties": {
+					"n": {
+						"description": "maximum number of things",
+						"type": "integer", "default": 10
+					},
+					"tag": {
+						"description": "filter by tag (can be repeated)",
+						"anyOf": [
+							{"type": "string"},
+							{"type": "array", "items": {"type": "string"}}
+						]
+					}
+				},
+				"additionalProperties": false
+			},
+			"completions": {
+				"description": "Print completions.",
+				"type": "object",
+				"additionalProperties": false
+			}
+		},
+		"additionalProperties": false
+	}`, buf.String())
+}
@@ -0,0 +1,111 @@
+package clidoc
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/romshark/localize/internal/config"
+)
+
+// SchemaDraft is the JSON schema dialect of WriteSchema.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// schema is a JSON schema node.
+type schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	AnyOf                []*schema          `json:"anyOf,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// WriteSchema writes the JSON schema of the configuration file (-config)
+// defining flag defaults by command to w.
+func WriteSchema(w io.Writer, title string, cmds []config.Command) error {
+	no := false
+	s := &schema{
+		Schema:     SchemaDraft,
+		Title:      title,
+		Type:       "object",
+		Properties: map[string]*schema{config.SchemaKey: {Type: "string"}},
+
+		AdditionalProperties: &no,
+	}
+	for _, c := range cmds {
+		cs := &schema{
+			Description:          c.Description,
+			Type:                 "object",
+			Properties:           map[string]*schema{},
+			AdditionalProperties: &no,
+		}
+		if c.Flags != nil {
+			c.FlagSet().VisitAll(func(f *flag.Flag) {
+				cs.Properties[f.Name] = flagSchema(c, f)
+			})
+		}
+		s.Properties[c.Name] = cs
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.SetEscapeHTML(false)
+	return e.Encode(s)
+}
+
+// flagSchema returns the schema of the value of flag f of command c.
+// Repeatable flags also accept arrays of values.
+func flagSchema(c config.Command, f *flag.Flag) *schema {
+	_, usage := flag.UnquoteUsage(f)
+	s := &schema{Description: usage, Type: "string", Enum: c.FlagValues[f.Name]}
+	var v any
+	if g, ok := f.Value.(flag.Getter); ok {
+		v = g.Get()
+	} else if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		// Custom boolean flags like -Werror may also take values.
+		s.Type = []string{"boolean", "string"}
+	}
+	switch v := v.(type) {
+	case bool:
+		s.Type = "boolean"
+		if v {
+			s.Default = true
+		}
+		return s
+	case int, int64, uint, uint64:
+		s.Type = "integer"
+		if n, err := strconv.ParseInt(f.DefValue, 10, 64); err == nil && n != 0 {
+			s.Default = n
+		}
+	case float64:
+		s.Type = "number"
+		if v != 0 {
+			s.Default = v
+		}
+	case time.Duration:
+		if v != 0 {
+			s.Default = f.DefValue
+		}
+	default:
+		if f.DefValue != "" {
+			s.Default = f.DefValue
+		}
+	}
+	if !strings.Contains(usage, "can be repeated") {
+		return s
+	}
+	return &schema{
+		Description: s.Description,
+		AnyOf: []*schema{
+			{Type: s.Type, Enum: s.Enum, Default: s.Default},
+			{Type: "array", Items: &schema{Type: s.Type, Enum: s.Enum}},
+		},
+	}
+}
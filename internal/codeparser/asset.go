@@ -0,0 +1,66 @@
+package codeparser
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+)
+
+// AssetRefPrefix prefixes the reference comments of positions in asset files,
+// which are non-Go files like templates, SQL queries and configuration files,
+// distinguishing them from references to Go files.
+const AssetRefPrefix = "@"
+
+// AssetRoot is a directory of asset files messages are referenced in.
+type AssetRoot struct {
+	// Name is a single path element replacing Dir in trimmed paths
+	// (see AssetPosition).
+	Name string
+
+	// Dir is the path of the directory.
+	Dir string
+}
+
+// AssetPosition returns the position of line in the asset file at path
+// as reported in catalogs. If trimpath is true, the directory of the first
+// of roots containing the file is replaced by the name of the root,
+// such that "templates/email/welcome.html" in root
+// {Name: "email", Dir: "templates/email"} becomes "email/welcome.html".
+// Paths of files outside of all roots are kept.
+func AssetPosition(
+	roots []AssetRoot, path string, line int, trimpath bool,
+) (token.Position, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return token.Position{}, fmt.Errorf("getting absolute path: %w", err)
+	}
+	pos := token.Position{Filename: abs, Line: line}
+	if trimpath {
+		for _, r := range roots {
+			dir, err := filepath.Abs(r.Dir)
+			if err != nil {
+				return token.Position{}, fmt.Errorf("getting absolute path: %w", err)
+			}
+			rel, ok := cutPathPrefix(abs, dir+string(filepath.Separator),
+				caseInsensitivePaths)
+			if ok {
+				pos.Filename = r.Name + string(filepath.Separator) + rel
+				break
+			}
+		}
+	}
+	pos.Filename = filepath.ToSlash(pos.Filename)
+	return pos, nil
+}
+
+// FmtAssetRef formats the reference comment of an asset position
+// like "@email/welcome.html:3".
+func FmtAssetRef(pos token.Position) string {
+	return AssetRefPrefix + gettext.FmtCodeRef(pos.Filename, pos.Line)
+}
+
+// IsAssetRef returns true if reference comment ref refers to an asset file.
+func IsAssetRef(ref string) bool { return strings.HasPrefix(ref, AssetRefPrefix) }
@@ -0,0 +1,42 @@
+package codeparser_test
+
+import (
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetPosition(t *testing.T) {
+	dir := t.TempDir()
+	roots := []codeparser.AssetRoot{
+		{Name: "email", Dir: filepath.Join(dir, "templates", "email")},
+		{Name: "templates", Dir: filepath.Join(dir, "templates")},
+	}
+	welcome := filepath.Join(dir, "templates", "email", "welcome.html")
+
+	pos, err := codeparser.AssetPosition(roots, welcome, 3, true)
+	require.NoError(t, err)
+	require.Equal(t, token.Position{Filename: "email/welcome.html", Line: 3}, pos)
+	require.Equal(t, "@email/welcome.html:3", codeparser.FmtAssetRef(pos))
+	require.True(t, codeparser.IsAssetRef(codeparser.FmtAssetRef(pos)))
+	require.False(t, codeparser.IsAssetRef("/main.go:3"))
+
+	// The first root containing the file is used.
+	pos, err = codeparser.AssetPosition(roots,
+		filepath.Join(dir, "templates", "page.html"), 1, true)
+	require.NoError(t, err)
+	require.Equal(t, "templates/page.html", pos.Filename)
+
+	// Directories sharing a prefix with a root aren't in the root.
+	emails := filepath.Join(dir, "templates", "emails", "a.html")
+	pos, err = codeparser.AssetPosition(roots[:1], emails, 1, true)
+	require.NoError(t, err)
+	require.Equal(t, filepath.ToSlash(emails), pos.Filename)
+
+	pos, err = codeparser.AssetPosition(roots, welcome, 3, false)
+	require.NoError(t, err)
+	require.Equal(t, filepath.ToSlash(welcome), pos.Filename)
+}
@@ -1,69 +1,373 @@
 package codeparser
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/romshark/localize"
 	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/coverage"
 	"golang.org/x/text/language"
-	"golang.org/x/tools/go/packages"
 )
 
-func ParseBundle(pkg *packages.Package, collection *Collection) (*Bundle, error) {
-	bundle := &Bundle{Catalogs: make(map[language.Tag]POFile)}
+// ErrLanguageMismatch is wrapped by LanguageMismatchError.
+var ErrLanguageMismatch = errors.New(
+	"Language header doesn't match the locale of the file name",
+)
+
+// LanguageMismatchError is returned when the Language header of a `.po` file
+// doesn't match the locale of its file name, like `catalog.de.po`
+// with header `Language: fr`.
+type LanguageMismatchError struct {
+	Pos gettext.Position
+
+	// Header is the locale of the Language header.
+	Header language.Tag
+
+	// FileName is the locale of the file name.
+	FileName language.Tag
+}
+
+func (e *LanguageMismatchError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s (file name: %s)",
+		e.Pos.Filename, e.Pos.Line, e.Pos.Column,
+		ErrLanguageMismatch, e.Header, e.FileName)
+}
+
+func (e *LanguageMismatchError) Unwrap() error { return ErrLanguageMismatch }
+
+// checkLanguage returns a *LanguageMismatchError if the Language header of po
+// is set and doesn't match locale.
+func checkLanguage(po gettext.FilePO, locale language.Tag) error {
+	h := po.Head.Language
+	if h.Value == "" || h.Locale == locale {
+		return nil
+	}
+	return &LanguageMismatchError{
+		Pos: h.Position, Header: h.Locale, FileName: locale,
+	}
+}
+
+// ParseBundleDir parses all `.po` files in the bundle package directory dir.
+// Catalogs split into domains (`catalog.<domain>.<locale>.po`) are merged
+// into a single catalog per locale.
+// Returns an empty bundle if dir doesn't exist.
+func ParseBundleDir(dir string) (*Bundle, error) { return parseBundleDir(dir, false) }
+
+// ParseBundleDirSalvage is like ParseBundleDir but skips the malformed
+// messages of `.po` files instead of failing (see gettext.Decoder.Salvage)
+// and records their errors in POFile.Skipped.
+func ParseBundleDirSalvage(dir string) (*Bundle, error) { return parseBundleDir(dir, true) }
+
+func parseBundleDir(dir string, salvage bool) (*Bundle, error) {
+	bundle := &Bundle{
+		Catalogs:     make(map[language.Tag]POFile),
+		CatalogParts: make(map[language.Tag][]POFile),
+	}
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return bundle, nil
+	}
 	gettextDecoder := gettext.NewDecoder()
+	// References compacted by GNU gettext tools or -compact-refs
+	// are synced with the source code per reference.
+	gettextDecoder.SplitReferences = true
+	gettextDecoder.Salvage = salvage
 
-	err := findPOFiles(pkg.Dir, func(locale language.Tag, file string) error {
-		f, err := os.OpenFile(file, os.O_RDONLY, 0o644)
+	err := findPOFiles(dir, "catalog", func(
+		domain string, locale language.Tag, file string,
+	) error {
+		po, err := decodePOFile(gettextDecoder, file)
 		if err != nil {
-			return fmt.Errorf("opening .po file: %w", err)
+			return err
+		}
+		if err := checkLanguage(po, locale); err != nil {
+			return err
+		}
+		bundle.CatalogParts[locale] = append(bundle.CatalogParts[locale], POFile{
+			Path:    file,
+			Domain:  domain,
+			FilePO:  po,
+			Skipped: gettextDecoder.Skipped(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("discovering catalog .po files in bundle: %w", err)
+	}
+	for locale, parts := range bundle.CatalogParts {
+		// The default domain comes first.
+		slices.SortFunc(parts, func(a, b POFile) int {
+			return strings.Compare(a.Domain, b.Domain)
+		})
+		bundle.Catalogs[locale] = mergePOFiles(parts)
+	}
+
+	err = findPOFiles(dir, "source", func(
+		domain string, locale language.Tag, file string,
+	) error {
+		if domain != "" {
+			return nil // Source catalogs are never split.
 		}
-		po, err := gettextDecoder.DecodePO(file, f)
+		po, err := decodePOFile(gettextDecoder, file)
 		if err != nil {
-			return fmt.Errorf("decoding .po file (%q): %w", file, err)
+			return err
+		}
+		if err := checkLanguage(po, locale); err != nil {
+			return err
 		}
-		bundle.Catalogs[locale] = POFile{
+		bundle.SourceLocale = locale
+		bundle.Source = &POFile{
 			Path:   file,
 			FilePO: po,
 		}
 		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("discovering catalog .po files in bundle: %w", err)
+		return nil, fmt.Errorf("discovering source .po file in bundle: %w", err)
 	}
 
 	return bundle, nil
 }
 
+// ParseVendorCatalogs parses the `catalog.<locale>.po` files
+// in the directories dirs of externally supplied translations
+// (see Bundle.WithVendored). Catalogs are returned by locale in order of dirs.
+func ParseVendorCatalogs(dirs []string) (map[language.Tag][]POFile, error) {
+	gettextDecoder := gettext.NewDecoder()
+	catalogs := make(map[language.Tag][]POFile)
+	for _, dir := range dirs {
+		err := findPOFiles(dir, "catalog", func(
+			domain string, locale language.Tag, file string,
+		) error {
+			po, err := decodePOFile(gettextDecoder, file)
+			if err != nil {
+				return err
+			}
+			if err := checkLanguage(po, locale); err != nil {
+				return err
+			}
+			catalogs[locale] = append(catalogs[locale], POFile{
+				Path:   file,
+				Domain: domain,
+				FilePO: po,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("discovering vendored catalogs in %s: %w", dir, err)
+		}
+	}
+	return catalogs, nil
+}
+
+// WithVendored returns a copy of b whose catalogs are merged with the
+// translations of the vendored catalogs of their locale, which fill in
+// messages missing or untranslated in the catalogs of b.
+// Vendored catalogs of locales b has no catalog for are ignored.
+// CatalogParts aren't changed, such that vendored messages are never
+// written to the catalogs of b.
+func (b *Bundle) WithVendored(vendored map[language.Tag][]POFile) *Bundle {
+	cp := *b
+	cp.Catalogs = make(map[language.Tag]POFile, len(b.Catalogs))
+	for locale, catalog := range b.Catalogs {
+		if v := vendored[locale]; len(v) > 0 {
+			catalog = mergeVendored(catalog, v)
+		}
+		cp.Catalogs[locale] = catalog
+	}
+	return &cp
+}
+
+// vendorKey identifies the translation of a message in the Go bundle.
+type vendorKey struct {
+	// Context is the context of auxiliary entries, which are translated
+	// separately from messages sharing their source text.
+	Context string
+	Plural  bool
+	Source  string
+}
+
+func vendorKeyOf(m *gettext.Message) vendorKey {
+	k := vendorKey{Source: m.Msgid.Text.String()}
+	if len(m.MsgidPlural.Text.Lines) > 0 {
+		k.Plural, k.Source = true, m.MsgidPlural.Text.String()
+	}
+	if ctx := m.Msgctxt.Text.String(); strings.HasPrefix(
+		ctx, localize.RegisterContextPrefix,
+	) || strings.HasPrefix(ctx, localize.GrammarContextPrefix) {
+		k.Context = ctx
+	}
+	return k
+}
+
+// mergeVendored returns a copy of catalog with the translations of
+// vendored added to messages catalog doesn't translate.
+func mergeVendored(catalog POFile, vendored []POFile) POFile {
+	l := slices.Clone(catalog.Messages.List)
+	byKey := make(map[vendorKey]int, len(l))
+	for i := range l {
+		if !l[i].Obsolete {
+			byKey[vendorKeyOf(&l[i])] = i
+		}
+	}
+	for _, v := range vendored {
+		for _, m := range v.Messages.List {
+			if m.Obsolete || !coverage.IsTranslated(&m) {
+				continue
+			}
+			k := vendorKeyOf(&m)
+			i, ok := byKey[k]
+			if !ok {
+				byKey[k] = len(l)
+				l = append(l, m.Clone())
+				continue
+			}
+			if coverage.IsTranslated(&l[i]) {
+				continue
+			}
+			dst := l[i].Clone()
+			dst.Msgstr, dst.Msgstr0, dst.Msgstr1 = m.Msgstr, m.Msgstr0, m.Msgstr1
+			dst.Msgstr2, dst.Msgstr3 = m.Msgstr2, m.Msgstr3
+			dst.Msgstr4, dst.Msgstr5 = m.Msgstr4, m.Msgstr5
+			l[i] = dst
+		}
+	}
+	catalog.FilePO = gettext.FilePO{File: &gettext.File{
+		Head:     catalog.Head,
+		Messages: gettext.Messages{List: l},
+	}}
+	return catalog
+}
+
+// FixLanguageHeaders rewrites the Language header of every `.po` file
+// in the bundle package directory dir that doesn't match the locale
+// of the file name and returns the paths of the rewritten files.
+func FixLanguageHeaders(dir string) (fixed []string, err error) {
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	gettextDecoder := gettext.NewDecoder()
+	fix := func(domain string, locale language.Tag, file string) error {
+		po, err := decodePOFile(gettextDecoder, file)
+		if err != nil {
+			return err
+		}
+		if checkLanguage(po, locale) == nil {
+			return nil
+		}
+		po.Head.Language = gettext.HeaderLanguage{
+			Value: locale.String(), Locale: locale,
+		}
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return fmt.Errorf("opening .po file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		if err := (gettext.Encoder{PreserveFormat: true}).EncodePO(po, f); err != nil {
+			return fmt.Errorf("encoding .po file (%q): %w", file, err)
+		}
+		fixed = append(fixed, file)
+		return nil
+	}
+	for _, prefix := range []string{"catalog", "source"} {
+		if err := findPOFiles(dir, prefix, fix); err != nil {
+			return fixed, err
+		}
+	}
+	return fixed, nil
+}
+
 type Bundle struct {
+	// Catalogs are the translation catalogs by locale.
+	// Catalogs split into domains are merged.
 	Catalogs map[language.Tag]POFile
+
+	// CatalogParts are the individual catalog files by locale
+	// ordered by domain with the default domain first.
+	CatalogParts map[language.Tag][]POFile
+
+	// Source is the source catalog generated from the source code.
+	// Source is nil if the bundle doesn't contain a source catalog yet.
+	Source       *POFile
+	SourceLocale language.Tag
 }
 
 type POFile struct {
 	Path string
+
+	// Domain is the domain of the catalog file.
+	// Domain is empty for the default domain.
+	Domain string
+
+	// Skipped are the errors of the malformed messages skipped
+	// by ParseBundleDirSalvage.
+	Skipped []gettext.Error
+
 	gettext.FilePO
 }
 
-func findPOFiles(dir string, fn func(locale language.Tag, file string) error) error {
+func decodePOFile(d *gettext.Decoder, file string) (gettext.FilePO, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return gettext.FilePO{}, fmt.Errorf("reading .po file: %w", err)
+	}
+	po, err := d.DecodePOBytes(file, b)
+	if err != nil {
+		return gettext.FilePO{}, fmt.Errorf("decoding .po file (%q): %w", file, err)
+	}
+	return po, nil
+}
+
+// mergePOFiles returns a new file with the header and path of the first part
+// and the messages of all parts.
+func mergePOFiles(parts []POFile) POFile {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	var l []gettext.Message
+	for _, p := range parts {
+		l = append(l, p.Messages.List...)
+	}
+	return POFile{
+		Path: parts[0].Path,
+		FilePO: gettext.FilePO{File: &gettext.File{
+			Head:     parts[0].Head,
+			Messages: gettext.Messages{List: l},
+		}},
+	}
+}
+
+// findPOFiles calls fn for every `<prefix>.<locale>.po`
+// and `<prefix>.<domain>.<locale>.po` file found in dir.
+func findPOFiles(
+	dir, prefix string, fn func(domain string, locale language.Tag, file string) error,
+) error {
 	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
 		if err != nil || d.IsDir() {
 			return err
 		}
 
 		name := d.Name()
-		if len(name) < len("catalog.en.po") ||
-			!strings.HasPrefix(name, "catalog.") ||
+		if len(name) < len(prefix+".en.po") ||
+			!strings.HasPrefix(name, prefix+".") ||
 			!strings.HasSuffix(name, ".po") {
 			return nil
 		}
 
-		localeStr := name[len("catalog") : len(name)-len(".po")]
-		loc, err := language.Parse(localeStr[1:])
+		localeStr := name[len(prefix)+1 : len(name)-len(".po")]
+		var domain string
+		if i := strings.LastIndexByte(localeStr, '.'); i != -1 {
+			domain, localeStr = localeStr[:i], localeStr[i+1:]
+		}
+		loc, err := language.Parse(localeStr)
 		if err != nil {
 			return nil
 		}
-		return fn(loc, path)
+		return fn(domain, loc, path)
 	})
 }
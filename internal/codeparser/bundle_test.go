@@ -0,0 +1,137 @@
+package codeparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func writeCatalog(t *testing.T, dir, name, locale string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	err := os.WriteFile(p, []byte(`msgid ""
+msgstr ""
+"Language: `+locale+`\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+msgctxt "x"
+msgid "Hello"
+msgstr "Hallo"
+`), 0o644)
+	require.NoError(t, err)
+	return p
+}
+
+func TestParseBundleDirLanguageMismatch(t *testing.T) {
+	dir := t.TempDir()
+	writeCatalog(t, dir, "catalog.fr.po", "fr")
+	p := writeCatalog(t, dir, "catalog.de.po", "fr")
+
+	_, err := codeparser.ParseBundleDir(dir)
+	require.ErrorIs(t, err, codeparser.ErrLanguageMismatch)
+	var errMismatch *codeparser.LanguageMismatchError
+	require.ErrorAs(t, err, &errMismatch)
+	require.Equal(t, p, errMismatch.Pos.Filename)
+	require.Equal(t, uint32(3), errMismatch.Pos.Line)
+	require.Equal(t, language.French, errMismatch.Header)
+	require.Equal(t, language.German, errMismatch.FileName)
+}
+
+func TestFixLanguageHeaders(t *testing.T) {
+	dir := t.TempDir()
+	writeCatalog(t, dir, "catalog.fr.po", "fr")
+	p := writeCatalog(t, dir, "catalog.de.po", "fr")
+
+	fixed, err := codeparser.FixLanguageHeaders(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{p}, fixed)
+
+	b, err := codeparser.ParseBundleDir(dir)
+	require.NoError(t, err)
+	require.Len(t, b.Catalogs, 2)
+	require.Equal(t, language.German, b.Catalogs[language.German].Head.Language.Locale)
+	require.Equal(t, "Hallo",
+		b.Catalogs[language.German].Messages.List[0].Msgstr.Text.String())
+
+	fixed, err = codeparser.FixLanguageHeaders(dir)
+	require.NoError(t, err)
+	require.Empty(t, fixed)
+}
+
+func TestBundleWithVendored(t *testing.T) {
+	bundleDir, vendorDir := t.TempDir(), t.TempDir()
+	write := func(t *testing.T, dir, locale, body string) {
+		t.Helper()
+		p := filepath.Join(dir, "catalog."+locale+".po")
+		err := os.WriteFile(p, []byte(`msgid ""
+msgstr ""
+"Language: `+locale+`\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+`+body), 0o644)
+		require.NoError(t, err)
+	}
+	write(t, bundleDir, "de", `
+msgctxt "a"
+msgid "Hello"
+msgstr "Hallo"
+
+msgctxt "b"
+msgid "Goodbye"
+msgstr ""
+`)
+	write(t, vendorDir, "de", `
+msgctxt "lib1"
+msgid "Hello"
+msgstr "Servus"
+
+msgctxt "lib2"
+msgid "Goodbye"
+msgstr "Tschüss"
+
+msgctxt "lib3"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] "%d Dateien"
+
+msgctxt "lib4"
+msgid "Untranslated"
+msgstr ""
+`)
+	write(t, vendorDir, "fr", "")
+
+	b, err := codeparser.ParseBundleDir(bundleDir)
+	require.NoError(t, err)
+	vendored, err := codeparser.ParseVendorCatalogs([]string{vendorDir})
+	require.NoError(t, err)
+	require.Len(t, vendored, 2)
+
+	merged := b.WithVendored(vendored)
+	require.Len(t, merged.Catalogs, 1)
+	translations := map[string]string{}
+	for _, m := range merged.Catalogs[language.German].Messages.List {
+		translations[m.Msgctxt.Text.String()] = m.Msgstr.Text.String() +
+			m.Msgstr1.Text.String()
+	}
+	require.Equal(t, map[string]string{
+		"a":    "Hallo",
+		"b":    "Tschüss",
+		"lib3": "%d Dateien",
+	}, translations)
+
+	// The bundle isn't modified.
+	require.Len(t, b.Catalogs[language.German].Messages.List, 2)
+	require.Equal(t, "",
+		b.Catalogs[language.German].Messages.List[1].Msgstr.Text.String())
+	require.Len(t, merged.CatalogParts[language.German][0].Messages.List, 2)
+
+	_, err = codeparser.ParseVendorCatalogs([]string{filepath.Join(vendorDir, "x")})
+	require.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package codeparser
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
 	"go/ast"
@@ -12,20 +14,32 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
-	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/cespare/xxhash"
 	"github.com/romshark/localize"
 	"github.com/romshark/localize/gettext"
 	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/edition"
+	"github.com/romshark/localize/internal/errcode"
 	"github.com/romshark/localize/internal/fmtplaceholder"
+	"github.com/romshark/localize/internal/heading"
+	"github.com/romshark/localize/internal/ordinal"
+	"github.com/romshark/localize/internal/pluralcheck"
+	"github.com/romshark/localize/internal/protect"
+	"github.com/romshark/localize/internal/region"
+	"github.com/romshark/localize/internal/schedule"
+	"github.com/romshark/localize/internal/section"
+	"github.com/romshark/localize/internal/singular"
 	"github.com/romshark/localize/strfmt"
 	"golang.org/x/text/language"
+	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -37,24 +51,120 @@ const (
 	FuncTypeBlock       = "Block"
 	FuncTypePlural      = "Plural"
 	FuncTypePluralBlock = "PluralBlock"
+
+	// FuncTypeCardinal calls are extracted as FuncTypePlural messages
+	// (see cardinalMsg).
+	FuncTypeCardinal = "Cardinal"
+
+	// FuncTypePluralRange calls are extracted as FuncTypePlural messages
+	// (see pluralRangeMsg).
+	FuncTypePluralRange = "PluralRange"
+
+	// FuncTypePluralOrdinal calls are extracted as one FuncTypePlural message
+	// per ordinal category (see parseOrdinalForms).
+	FuncTypePluralOrdinal = "PluralOrdinal"
 )
 
+// Statistics are the statistics of a source code analysis.
 type Statistics struct {
-	TextTotal        atomic.Int64
-	BlockTotal       atomic.Int64
-	PluralTotal      atomic.Int64
-	PluralBlockTotal atomic.Int64
-	Merges           atomic.Int64
-	FilesTraversed   atomic.Int64
+	// TextTotal, BlockTotal, PluralTotal, PluralBlockTotal, CardinalTotal,
+	// PluralRangeTotal and PluralOrdinalTotal are the numbers of calls
+	// by function type.
+	TextTotal          int64 `json:"textTotal"`
+	BlockTotal         int64 `json:"blockTotal"`
+	PluralTotal        int64 `json:"pluralTotal"`
+	PluralBlockTotal   int64 `json:"pluralBlockTotal"`
+	CardinalTotal      int64 `json:"cardinalTotal"`
+	PluralRangeTotal   int64 `json:"pluralRangeTotal"`
+	PluralOrdinalTotal int64 `json:"pluralOrdinalTotal"`
+
+	// Messages is the number of unique messages.
+	Messages int64 `json:"messages"`
+
+	// Scheduled is the number of messages with a schedule, of which
+	// Embargoed haven't started and Expired have ended at the time of parsing.
+	Scheduled int64 `json:"scheduled"`
+	Embargoed int64 `json:"embargoed"`
+	Expired   int64 `json:"expired"`
+
+	// Merges is the number of calls merged into identical messages.
+	Merges         int64 `json:"merges"`
+	FilesTraversed int64 `json:"filesTraversed"`
+
+	// Packages are the statistics by package path.
+	// Packages without calls are omitted.
+	Packages map[string]*PackageStatistics `json:"packages"`
+}
+
+// PackageStatistics are the statistics of a single package.
+type PackageStatistics struct {
+	// Calls are the numbers of calls by function type (see FuncTypeText etc.).
+	Calls map[string]int64 `json:"calls"`
+}
+
+// addCall counts a call of funcType in package pkgPath.
+func (s *Statistics) addCall(pkgPath, funcType string) {
+	switch funcType {
+	case FuncTypeText:
+		s.TextTotal++
+	case FuncTypeBlock:
+		s.BlockTotal++
+	case FuncTypePlural:
+		s.PluralTotal++
+	case FuncTypePluralBlock:
+		s.PluralBlockTotal++
+	case FuncTypeCardinal:
+		s.CardinalTotal++
+	case FuncTypePluralRange:
+		s.PluralRangeTotal++
+	case FuncTypePluralOrdinal:
+		s.PluralOrdinalTotal++
+	}
+	p, ok := s.Packages[pkgPath]
+	if !ok {
+		p = &PackageStatistics{Calls: map[string]int64{}}
+		s.Packages[pkgPath] = p
+	}
+	p.Calls[funcType]++
 }
 
 // Collection is a collection of messages gathered from the
 type Collection struct {
 	GeneratorVersion int
 	Locale           language.Tag
-	// TODO: consider turning this into map[string]MsgWithMeta for faster hash lookups
-	// such that no new map needs to be created and copied over during catalog updates.
-	Messages map[Msg]MsgMeta
+	Messages         map[Msg]MsgMeta
+
+	// Normalization is the normalization applied to the source texts
+	// of Messages (see LoadOptions.Normalization).
+	Normalization strfmt.Normalization
+
+	// Hash is the hash function of the hashes of Messages
+	// (see LoadOptions.Hash).
+	Hash HashFunc
+
+	// hashesDescriptions is true if the hashes of Messages include
+	// their descriptions, which they don't if descriptions are merged.
+	hashesDescriptions bool
+
+	// byHash indexes Messages by hash, see ByHash.
+	byHash map[string]Msg
+}
+
+// ByHash returns the message with the given hash.
+// The index is built once on first use and maintained by the parser,
+// it's rebuilt if Messages was modified directly.
+func (c *Collection) ByHash(hash string) (Msg, MsgMeta, bool) {
+	if len(c.byHash) != len(c.Messages) {
+		c.byHash = make(map[string]Msg, len(c.Messages))
+		for m := range c.Messages {
+			c.byHash[m.Hash] = m
+		}
+	}
+	m, ok := c.byHash[hash]
+	if !ok {
+		return Msg{}, MsgMeta{}, false
+	}
+	return m, c.Messages[m], true
 }
 
 func (c *Collection) MakePO(headTxt []string) gettext.FilePO {
@@ -63,13 +173,14 @@ func (c *Collection) MakePO(headTxt []string) gettext.FilePO {
 	h.MIMEVersion = "1.0"
 	h.ContentType = "text/plain; charset=UTF-8"
 	h.ContentTransferEncoding = "8bit"
+	setHashHeader(&h, c.Hash)
 
 	pluralForms, ok := cldr.ByTagOrBase(c.Locale)
 	if !ok {
 		panic(fmt.Errorf("unsupported locale: %v", c.Locale))
 	}
 	h.PluralForms = gettext.HeaderPluralForms{
-		N:          h.PluralForms.N,
+		N:          uint8(len(pluralForms.CardinalForms)),
 		Expression: pluralForms.GettextFormula,
 	}
 
@@ -83,10 +194,11 @@ func (c *Collection) MakePO(headTxt []string) gettext.FilePO {
 
 	var m gettext.Messages
 	m.List = make([]gettext.Message, 0, len(c.Messages))
-	for msg, meta := range c.Messages {
+	for msg, meta := range c.Ordered() {
 		gm := MsgFromGettextMessage(pluralForms, msg, meta)
 		m.List = append(m.List, gm)
 	}
+	section.Group(m.List)
 
 	return gettext.FilePO{
 		File: &gettext.File{
@@ -124,10 +236,230 @@ type Msg struct {
 	Many        string
 	Other       string
 	FuncType    string
+
+	// Scope is the section path of the reader returned by Reader.Section
+	// the message was read from, which is "" for other readers.
+	// Scoped messages are translated separately from identical messages
+	// of other sections and outside of sections.
+	Scope string
+
+	// Reflow is true if the texts were reflowed (see strfmt.DedentReflow)
+	// and differ from the texts formatted with strfmt.DedentPreserve.
+	Reflow bool
+}
+
+// reflowMsg reflows the texts of msg formatted with strfmt.DedentPreserve.
+func reflowMsg(msg *Msg) {
+	for _, s := range []*string{
+		&msg.Zero, &msg.One, &msg.Two, &msg.Few, &msg.Many, &msg.Other,
+	} {
+		if r := strfmt.Reflow(*s); r != *s {
+			*s, msg.Reflow = r, true
+		}
+	}
+}
+
+// normalizeMsg normalizes the texts of msg according to n.
+func normalizeMsg(msg *Msg, n strfmt.Normalization) {
+	for _, s := range []*string{
+		&msg.Zero, &msg.One, &msg.Two, &msg.Few, &msg.Many, &msg.Other,
+	} {
+		*s = strfmt.Normalize(*s, n)
+	}
+}
+
+// DirectiveDedent is the prefix of the comment line selecting
+// the strfmt.DedentMode of a Block or PluralBlock call,
+// like "dedent: reflow".
+const DirectiveDedent = "dedent:"
+
+// directives are the directives of a message comment.
+type directives struct {
+	editions  []string
+	regions   []string
+	codes     []string
+	dedent    *strfmt.DedentMode
+	protected []string
+	schedule  localize.Schedule
+	heading   bool
+
+	// forms are the quoted templates of the plural form comments
+	// (see parseFormComment) applying to Cardinal calls.
+	forms localize.Forms
+}
+
+// parseDirectives removes all directives from the comment lines.
+func parseDirectives(lines []string) (
+	description []string, d directives, errs []error,
+) {
+	description = lines[:0:0]
+	for _, l := range lines {
+		if e, ok, err := edition.ParseDirective(l); ok {
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %w", ErrInvalidDirective, err))
+			}
+			d.editions = e
+			continue
+		}
+		if r, ok, err := region.ParseDirective(l); ok {
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %w", ErrInvalidDirective, err))
+			}
+			d.regions = r
+			continue
+		}
+		if c, ok, err := errcode.ParseDirective(l); ok {
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %w", ErrInvalidDirective, err))
+			}
+			d.codes = c
+			continue
+		}
+		if ok, err := schedule.ParseDirective(l, &d.schedule); ok {
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %w", ErrInvalidDirective, err))
+			}
+			continue
+		}
+		if heading.IsDirective(l) {
+			d.heading = true
+			continue
+		}
+		if v, ok := strings.CutPrefix(l, DirectiveDedent); ok {
+			m, err := strfmt.ParseDedentMode(strings.TrimSpace(v))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %w", ErrInvalidDirective, err))
+				continue
+			}
+			d.dedent = &m
+			continue
+		}
+		if _, ok, _ := section.ParseDirective("//" + l); ok {
+			continue // Applies to the whole file, see fileSection.
+		}
+		if t, ok, err := protect.ParseDirective(l); ok {
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %w", ErrInvalidDirective, err))
+				continue
+			}
+			d.protected = append(d.protected, t)
+			continue
+		}
+		if ok, err := parseFormComment(l, &d.forms); ok {
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %w", ErrInvalidDirective, err))
+			}
+			continue
+		}
+		description = append(description, l)
+	}
+	if err := schedule.Check(d.schedule); err != nil {
+		errs = append(errs, fmt.Errorf("%w: %w", ErrInvalidDirective, err))
+		d.schedule = localize.Schedule{}
+	}
+	return description, d, errs
 }
 
 type MsgMeta struct {
+	// Pos are the unique positions of the calls referencing the message
+	// sorted by file, line and column.
 	Pos []token.Position
+
+	// Assets are the unique positions in asset files referencing the message
+	// (see AssetPosition) sorted by file, line and column.
+	Assets []token.Position
+
+	// Editions are the sorted editions the message belongs to
+	// (see package edition). Editions is empty if the message
+	// belongs to all editions.
+	Editions []string
+
+	// Regions are the sorted region subtags the message applies to
+	// (see package region). Regions is empty if the message
+	// applies to all regions.
+	Regions []string
+
+	// ErrorCodes are the sorted codes of the API errors the message
+	// belongs to (see package errcode).
+	ErrorCodes []string
+
+	// Schedule is the period the message may be shown in
+	// (see package schedule). Schedule is zero if the message
+	// is always shown.
+	Schedule localize.Schedule
+
+	// Heading is true if any call marks the message as heading
+	// (see package heading).
+	Heading bool
+
+	// Protected are the sorted substrings of the message that must not be
+	// translated (see package protect).
+	Protected []string
+
+	// Section is the section of the first call referencing the message
+	// (see package section). Section is empty if the message belongs
+	// to no section.
+	Section string
+
+	// DerivedOne is true if any call referencing the message provides
+	// no form One, which is derived from form Other (see LoadOptions.DeriveOne).
+	DerivedOne bool
+
+	// CommentForms is true if any Cardinal call referencing the message
+	// provides templates using plural form comments (see parseFormComment).
+	CommentForms bool
+
+	// Ordinals are the CLDR ordinal categories in CLDR order of the
+	// PluralOrdinal calls referencing the message (see package ordinal).
+	// Ordinals is empty if the message isn't ordinal.
+	Ordinals []cldr.CLDRPluralForm
+
+	// Descriptions are the sorted unique non-empty descriptions of the calls
+	// referencing the message if calls with different descriptions
+	// are merged (see LoadOptions.MergeDescriptions).
+	Descriptions []string
+}
+
+// References returns the code reference comments of the message
+// in the order of Pos followed by Assets without duplicates
+// of references on the same line.
+func (m MsgMeta) References() []string {
+	refs := make([]string, 0, len(m.Pos)+len(m.Assets))
+	for _, pos := range m.Pos {
+		refs = append(refs, gettext.FmtCodeRef(pos.Filename, pos.Line))
+	}
+	for _, pos := range m.Assets {
+		refs = append(refs, FmtAssetRef(pos))
+	}
+	return slices.Compact(refs)
+}
+
+func comparePos(a, b token.Position) int {
+	return cmp.Or(
+		strings.Compare(a.Filename, b.Filename),
+		cmp.Compare(a.Line, b.Line),
+		cmp.Compare(a.Column, b.Column),
+	)
+}
+
+// mergeEditions returns the editions of a message referenced by
+// two calls with editions a and b. A message referenced by any call
+// without editions belongs to all editions.
+func mergeEditions(a, b []string) []string {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	return mergeSorted(a, b)
+}
+
+// mergeRegions is like mergeEditions for the regions of a message.
+func mergeRegions(a, b []string) []string { return mergeEditions(a, b) }
+
+// mergeSorted returns the sorted union of a and b.
+func mergeSorted(a, b []string) []string {
+	m := slices.Concat(a, b)
+	slices.Sort(m)
+	return slices.Compact(m)
 }
 
 var (
@@ -136,40 +468,231 @@ var (
 	ErrSourceArgType   = errors.New(
 		"non-literal argument (only string literals and constants are supported)",
 	)
-	ErrMissingPluralForm     = errors.New("missing required plural form")
+	ErrMissingPluralForm     = pluralcheck.ErrMissingPluralForm
 	ErrUnsupportedPluralForm = errors.New(
 		"plural form not supported by source language",
 	)
-	ErrMissingQuantityPlaceholder = errors.New(
-		"missing quantity placeholder \"%d\" in template",
+	ErrMissingQuantityPlaceholder  = pluralcheck.ErrMissingQuantityPlaceholder
+	ErrTooManyQuantityPlaceholders = pluralcheck.ErrTooManyQuantityPlaceholders
+	ErrWrongQuantityArgType        = errors.New(
+		"passing wrong type to quantity argument",
 	)
-	ErrTooManyQuantityPlaceholders = errors.New(
-		"plural template strings are expected to " +
-			`have only one quantity placeholder "%d"`,
+	ErrWrongPlaceholderVerb = pluralcheck.ErrWrongPlaceholderVerb
+	ErrRangePlaceholders    = pluralcheck.ErrRangePlaceholders
+	ErrOrdinalPlaceholders  = pluralcheck.ErrOrdinalPlaceholders
+	ErrUnsupportedLocale    = errors.New("unsupported locale")
+	ErrInvalidDirective     = errors.New("invalid directive")
+	ErrUnknownTerm          = errors.New("unknown term placeholder")
+	ErrDescriptionConflict  = errors.New(
+		"description differs from another call with identical text",
 	)
-	ErrWrongQuantityArgType = errors.New(
-		"passing wrong type to quantity argument",
+	ErrHashCollision = errors.New(
+		"message hash collides with a different message",
 	)
-	ErrWrongPlaceholderVerb = errors.New(
-		"wrong placeholder verb, use a numeric placeholder",
+
+	// Warnings.
+	ErrDescriptionMissing = errors.New(
+		"missing description comment directly above the call",
+	)
+	ErrSuspiciousPlaceholder = errors.New("suspicious placeholder")
+	ErrSentenceSplit         = errors.New(
+		"localized texts concatenated into a sentence, " +
+			"use a single message with placeholders instead",
 	)
-	ErrUnsupportedLocale = errors.New("unsupported locale")
+	ErrMessageExpired = errors.New(
+		"message expired, remove it or its not-after directive",
+	)
+)
+
+// Severity is the severity of a source error.
+type Severity uint8
+
+const (
+	// SeverityError fails the generation.
+	SeverityError Severity = iota
+
+	// SeverityWarning is reported but doesn't fail the generation
+	// unless escalated.
+	SeverityWarning
 )
 
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
 type ErrorSrc struct {
 	token.Position
-	Err error
+	Err      error
+	Severity Severity
+}
+
+// errorCodes are the stable codes of source errors by sentinel.
+var errorCodes = [...]struct {
+	err  error
+	code string
+}{
+	{ErrSourceTextEmpty, "text-empty"},
+	{ErrSourceArgType, "arg-type"},
+	{ErrMissingPluralForm, "plural-form-missing"},
+	{ErrUnsupportedPluralForm, "plural-form-unsupported"},
+	{ErrMissingQuantityPlaceholder, "quantity-placeholder-missing"},
+	{ErrTooManyQuantityPlaceholders, "quantity-placeholder-multiple"},
+	{ErrWrongPlaceholderVerb, "placeholder-verb"},
+	{ErrRangePlaceholders, "range-placeholders"},
+	{ErrOrdinalPlaceholders, "ordinal-placeholders"},
+	{ErrWrongQuantityArgType, "quantity-arg-type"},
+	{ErrInvalidDirective, "directive-invalid"},
+	{ErrUnknownTerm, "term-unknown"},
+	{ErrDescriptionConflict, "description-conflict"},
+	{ErrHashCollision, "hash-collision"},
+	{ErrDescriptionMissing, "description-missing"},
+	{ErrSuspiciousPlaceholder, "placeholder-suspicious"},
+	{ErrSentenceSplit, "sentence-split"},
+	{ErrMessageExpired, "message-expired"},
+}
+
+// WarningCodes are the codes of all source errors of SeverityWarning.
+var WarningCodes = []string{
+	"description-missing", "placeholder-suspicious", "sentence-split",
+	"message-expired",
+}
+
+// Code returns the stable code identifying the type of the error
+// like "text-empty" or "plural-form-missing" for machine consumption.
+// Returns "unknown" for errors of unknown type.
+func (e ErrorSrc) Code() string {
+	for _, c := range errorCodes {
+		if errors.Is(e.Err, c.err) {
+			return c.code
+		}
+	}
+	return "unknown"
+}
+
+// LoadOptions defines how packages are loaded.
+// The zero value loads all packages including all of their dependencies
+// from source at once, which is the fastest strategy but requires
+// the ASTs and type information of the entire dependency graph
+// to be kept in memory.
+type LoadOptions struct {
+	// BatchSize enables batched loading of at most BatchSize packages at a time.
+	// The ASTs of a batch are discarded once it's processed.
+	BatchSize int
+
+	// MaxMemory enables batched loading and limits the estimated memory
+	// required by each batch to MaxMemory bytes. MaxMemory is also set as the
+	// soft memory limit of the Go runtime. See runtime/debug.SetMemoryLimit.
+	MaxMemory int64
+
+	// OnlyImporters restricts loading to packages that directly or transitively
+	// import github.com/romshark/localize, which are the only packages
+	// that can contain messages.
+	OnlyImporters bool
+
+	// Only restricts message extraction to the packages in directories
+	// matching any of the patterns relative to the module path,
+	// like "./plugins/..." for plugins and all of its subdirectories.
+	// Other packages are still loaded to find forwarders.
+	// Messages of all packages are extracted if Only is empty.
+	Only []string
+
+	// Profile collects the time spent on each package if not nil.
+	Profile *Profile
+
+	// DeriveOne derives the template of form One of Plural and PluralBlock
+	// calls that provide no form One from form Other (see package singular),
+	// such that English source code can provide form Other only.
+	// Form One is still required if it can't be derived.
+	DeriveOne bool
+
+	// MergeDescriptions defines how calls with identical texts
+	// and different descriptions are merged.
+	MergeDescriptions DescriptionMerge
+
+	// Salvage skips malformed messages of the catalogs of the bundle
+	// instead of failing (see ParseBundleDirSalvage).
+	Salvage bool
+
+	// Normalization is applied to all source texts before they're hashed,
+	// such that visually identical texts are extracted as the same message.
+	// The Go bundle applies it to the texts it looks up.
+	Normalization strfmt.Normalization
+
+	// Hash is the hash function computing message hashes.
+	// Catalogs created using another hash function are migrated
+	// by generate (see MigrateHashes).
+	Hash HashFunc
+
+	// Unit restricts extraction to a single package type-checked against
+	// the export data of its dependencies instead of loading packages
+	// with the go command (see Unit). Batching options are ignored and
+	// no bundle is parsed.
+	Unit *Unit
+}
+
+// DescriptionMerge defines how the descriptions of calls with identical texts
+// are merged.
+type DescriptionMerge string
+
+const (
+	// DescriptionMergeNone keeps calls with different descriptions
+	// as separate messages.
+	DescriptionMergeNone DescriptionMerge = ""
+
+	// DescriptionMergeConcat merges calls with identical texts into one
+	// message described by the sorted unique descriptions of all calls.
+	DescriptionMergeConcat DescriptionMerge = "concat"
+
+	// DescriptionMergeError merges calls with identical texts into one
+	// message and reports ErrDescriptionConflict for calls with
+	// a description different from another call.
+	DescriptionMergeError DescriptionMerge = "error"
+)
+
+// extracts returns true if messages of the package in directory dir
+// are extracted according to o.Only. base is the absolute module path.
+func (o LoadOptions) extracts(base, dir string) bool {
+	if len(o.Only) < 1 {
+		return true
+	}
+	for _, p := range o.Only {
+		if matchDirPattern(base, p, dir) {
+			return true
+		}
+	}
+	return false
 }
 
+// batched returns true if o enables batched loading.
+func (o LoadOptions) batched() bool {
+	return o.BatchSize > 0 || o.MaxMemory > 0 || o.OnlyImporters
+}
+
+// estimatedBytesPerSourceByte is a rough heuristic of the memory required
+// for the AST and type information per byte of Go source code.
+const estimatedBytesPerSourceByte = 64
+
+// Parse parses all packages matching pathPattern and the bundle package
+// located in directory bundlePkg. If bundleImportPath isn't empty the bundle
+// package is identified by its import path instead of its directory.
+// dedent is the format of Block and PluralBlock texts unless
+// overridden by a dedent directive (see DirectiveDedent).
+// If load.Unit is set, pathPattern is the directory of the unit
+// and the returned bundle is nil.
+// Parse returns ctx.Err() if ctx is canceled before parsing completed.
 func Parse(
-	pathPattern, bundlePkg string,
-	locale language.Tag, trimpath, quiet, verbose bool,
+	ctx context.Context, pathPattern, bundlePkg, bundleImportPath string,
+	locale language.Tag, dedent strfmt.DedentMode, trimpath, quiet, verbose bool,
+	load LoadOptions,
 ) (
 	collection *Collection, bundle *Bundle, stats *Statistics,
 	srcErrs []ErrorSrc, err error,
 ) {
 	fileset := token.NewFileSet()
-	stats = new(Statistics)
+	stats = &Statistics{Packages: map[string]*PackageStatistics{}}
 
 	pluralForms, ok := cldr.ByTagOrBase(locale)
 	if !ok {
@@ -178,188 +701,546 @@ func Parse(
 		)
 	}
 
-	cfg := &packages.Config{
-		Mode: packages.NeedFiles |
-			packages.NeedSyntax |
-			packages.NeedTypes |
-			packages.NeedTypesInfo |
-			packages.NeedDeps |
-			packages.NeedName |
-			packages.NeedModule,
-		Fset: fileset,
-	}
-	pkgs, err := packages.Load(cfg, pathPattern+"/...")
-	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("loading packages: %w", err)
-	}
-
 	collection = &Collection{
 		Messages: make(map[Msg]MsgMeta),
 		Locale:   locale,
+		byHash:   make(map[string]Msg),
+
+		Normalization: load.Normalization,
+		Hash:          load.Hash,
+
+		hashesDescriptions: load.MergeDescriptions == DescriptionMergeNone,
 	}
+	forwarders := builtinForwarders()
+
+	// unsupported are validated once the catalogs of the bundle are known.
+	var unsupported []unsupportedForms
 
+	bundleDir, err := filepath.Abs(bundlePkg)
+	if err != nil {
+		return collection, bundle, stats, srcErrs, fmt.Errorf(
+			"getting absolute path: %w", err,
+		)
+	}
 	var pkgBundle *packages.Package
-	for _, pkg := range pkgs {
-		if isPkgLocalizeBundle(bundlePkg, pkg) {
-			if !quiet && verbose {
-				fmt.Fprintf(os.Stderr, "bundle detected: %s\n", pkg.Dir)
+	detectBundle := func(pkgs []*packages.Package) {
+		for _, pkg := range pkgs {
+			if isPkgLocalizeBundle(bundleDir, bundleImportPath, pkg) {
+				if !quiet && verbose {
+					fmt.Fprintf(os.Stderr, "bundle detected: %s\n", pkg.Dir)
+				}
+				pkgBundle = pkg
 			}
-			pkgBundle = pkg
-		}
-
-		for _, file := range pkg.Syntax {
-			stats.FilesTraversed.Add(1)
-			for _, decl := range file.Decls {
-				ast.Inspect(decl, func(node ast.Node) bool {
-					call, ok := node.(*ast.CallExpr)
-					if !ok {
-						return true
-					}
-
-					selector, ok := call.Fun.(*ast.SelectorExpr)
-					if !ok { // Not a function selector (method call).
-						return true
-					}
-					if len(call.Args) != 1 && len(call.Args) != 2 {
-						return true
-					}
-
-					obj := pkg.TypesInfo.Uses[selector.Sel]
-					if obj == nil { // Not the right package and type.
-						return true
-					}
-
-					methodType, ok := obj.Type().(*types.Signature)
-					if !ok {
-						return true
-					}
-
-					recv := methodType.Recv()
-					if recv == nil || recv.Type().String() != targetType {
-						return true // Not the right receiver type.
-					}
-
-					if obj.Pkg() == nil || obj.Pkg().Path() != targetPackage {
-						return true // Not from the target package.
-					}
-
-					funcType := selector.Sel.Name
-					switch funcType {
-					case FuncTypeText:
-						stats.TextTotal.Add(1)
-					case FuncTypeBlock:
-						stats.BlockTotal.Add(1)
-					case FuncTypePlural:
-						stats.PluralTotal.Add(1)
-					case FuncTypePluralBlock:
-						stats.PluralBlockTotal.Add(1)
-					default:
-						return true // Not the right methods.
-					}
-
-					pos := fileset.Position(call.Pos())
-					if trimpath {
-						pos.Filename = mustTrimPath(pathPattern, pos.Filename)
-					}
-					argType := pkg.TypesInfo.Types[call.Args[0]]
-
-					msg := Msg{
-						FuncType: funcType,
-					}
-
-					switch funcType {
-					case FuncTypePlural, FuncTypePluralBlock:
-						cl, ok := call.Args[0].(*ast.CompositeLit)
+		}
+	}
+
+	base, err := filepath.Abs(strings.TrimSuffix(pathPattern, "/..."))
+	if err != nil {
+		return collection, bundle, stats, srcErrs, fmt.Errorf(
+			"getting absolute path: %w", err,
+		)
+	}
+
+	// position returns the position of p as reported in catalogs.
+	position := func(p token.Pos) token.Position {
+		pos := fileset.Position(p)
+		if trimpath {
+			pos.Filename = mustTrimPath(pathPattern, pos.Filename)
+		}
+		pos.Filename = filepath.ToSlash(pos.Filename)
+		return pos
+	}
+
+	process := func(pkgs []*packages.Package) {
+		forwardingCalls := findForwarders(pkgs, forwarders)
+		for _, pkg := range pkgs {
+			if !load.extracts(base, pkg.Dir) {
+				continue
+			}
+			start := time.Now()
+			pkgSection := packageSection(pkg.Syntax, position, &srcErrs)
+			for _, file := range pkg.Syntax {
+				if ctx.Err() != nil {
+					return // Canceled, the error is returned by Parse.
+				}
+				stats.FilesTraversed++
+				fileSection := cmp.Or(
+					fileSection(file, position, &srcErrs), pkgSection,
+				)
+				// prevCall is the position of the previous message in file.
+				var prevCall token.Pos
+				concatenated := map[*ast.BinaryExpr]struct{}{}
+				rangeVars := rangeVarsOf(file, pkg.TypesInfo)
+				readerVars := readerVarsOf(file, pkg.TypesInfo)
+				for _, decl := range file.Decls {
+					ast.Inspect(decl, func(node ast.Node) bool {
+						for _, p := range sentenceFragments(
+							pkg.TypesInfo, forwarders, node, concatenated,
+						) {
+							appendSrcWarn(&srcErrs, position(p), ErrSentenceSplit)
+						}
+
+						call, ok := node.(*ast.CallExpr)
 						if !ok {
-							// Unsupported argument value type.
-							appendSrcErr(&srcErrs, pos, fmt.Errorf(
-								"%w: %s", ErrSourceArgType, typeKind(call.Args[0]),
-							))
-							return false
+							return true
 						}
-						f := parseForms(fileset, cl, pkg.TypesInfo, &srcErrs)
-						msg.Zero = mustFmtTemplate(funcType, f.Zero)
-						msg.One = mustFmtTemplate(funcType, f.One)
-						msg.Two = mustFmtTemplate(funcType, f.Two)
-						msg.Few = mustFmtTemplate(funcType, f.Few)
-						msg.Many = mustFmtTemplate(funcType, f.Many)
-						msg.Other = mustFmtTemplate(funcType, f.Other)
-
-						validateForms(&srcErrs, locale, pos, pluralForms, msg)
-
-						validateQuantityArgument(
-							&srcErrs, pos, call.Args[1], pkg.TypesInfo,
-						)
 
-					default:
-						var textValue string
-						switch k := call.Args[0].(type) {
-						case *ast.Ident:
-							v := argType.Value
-
-							if v != nil && v.Kind() == constant.String {
-								// Constants are supported.
-								textValue = constant.StringVal(v)
-							} else {
-								// Unsupported argument value type.
-								appendSrcErr(&srcErrs, pos, fmt.Errorf(
-									"%w: %s", ErrSourceArgType, typeKind(call.Args[0]),
-								))
+						if _, ok := forwardingCalls[call]; ok {
+							// Message arguments are extracted at call sites
+							// of the enclosing helper function instead.
+							return true
+						}
+
+						type callMsg struct {
+							funcType string
+							args     []ast.Expr
+							scope    string // See Msg.Scope.
+						}
+						var callMsgs []callMsg
+						if fw, ok := forwarders[forwarderKey(
+							calledFunc(pkg.TypesInfo, call),
+						)]; ok {
+							// Call to a helper function forwarding its parameters
+							// to Reader methods.
+							for _, fw := range append([]forwarder{fw}, fw.more...) {
+								if fw.argIndex >= len(call.Args) {
+									continue
+								}
+								args := []ast.Expr{call.Args[fw.argIndex]}
+								switch {
+								case fw.quantityIndex >= 0 && fw.quantityIndex < len(call.Args):
+									args = append(args, call.Args[fw.quantityIndex])
+								case fw.quantityIndex < 0:
+									// Quantity is validated inside the helper function.
+									args = append(args, nil)
+								}
+								callMsgs = append(callMsgs, callMsg{
+									funcType: fw.funcType, args: args,
+								})
+							}
+						} else {
+							if len(call.Args) < 1 || len(call.Args) > 3 {
 								return true
 							}
-						case *ast.BasicLit:
-							textValue = k.Value
-						default:
-							appendSrcErr(&srcErrs, pos, fmt.Errorf(
-								"%w: %s", ErrSourceArgType, typeKind(call.Args[0]),
-							))
-							return true
+							funcType, ok := readerMethod(pkg.TypesInfo, call)
+							if !ok {
+								return true
+							}
+							if funcType == methodSection {
+								validateSection(
+									&srcErrs, position(call.Pos()), call, pkg.TypesInfo,
+								)
+								return true
+							}
+							callMsgs = []callMsg{{
+								funcType, call.Args,
+								scopeOf(pkg.TypesInfo, readerVars, call),
+							}}
 						}
-						msg.Other = mustFmtTemplate(funcType, textValue)
-					}
 
-					if verbose && !quiet {
-						fmt.Fprintf(
-							os.Stderr, "%s:%d:%d\n",
-							pos.Filename, pos.Line, pos.Column,
+						descend := true
+						var (
+							commented    bool
+							commentLines []string
+							dirs         directives
 						)
-					}
+						for _, cm := range callMsgs {
+							funcType, args := cm.funcType, cm.args
+
+							switch funcType {
+							case FuncTypeText, FuncTypeBlock, FuncTypePlural,
+								FuncTypePluralBlock, FuncTypeCardinal, FuncTypePluralRange,
+								FuncTypePluralOrdinal:
+								stats.addCall(pkg.PkgPath, funcType)
+							default:
+								continue // Not the right methods.
+							}
+
+							pos := position(call.Pos())
+							argType := pkg.TypesInfo.Types[args[0]]
+
+							msg := Msg{
+								FuncType: funcType,
+								Scope:    cm.scope,
+							}
+							// msgs are the messages extracted from the call at positions,
+							// which are multiple if the argument is a range variable.
+							var msgs []Msg
+							var positions []token.Position
+							// derivedOne is true if form One is derived from Other.
+							var derivedOne bool
+							// ordinals are the ordinal categories of msgs.
+							var ordinals []cldr.CLDRPluralForm
+
+							switch funcType {
+							case FuncTypePluralOrdinal:
+								cl, ok := args[0].(*ast.CompositeLit)
+								if !ok {
+									// Unsupported argument value type.
+									appendSrcErr(&srcErrs, pos, fmt.Errorf(
+										"%w: %s", ErrSourceArgType, typeKind(args[0]),
+									))
+									descend = false
+									continue
+								}
+								var defined cldr.CLDRForms
+								for _, c := range parseOrdinalForms(
+									fileset, cl, pkg.TypesInfo, &srcErrs,
+								) {
+									if c.Forms == (localize.Forms{}) &&
+										c.Category != cldr.CLDRPluralFormOther {
+										continue
+									}
+									defined.Set(c.Category)
+									m := Msg{
+										FuncType: FuncTypePlural,
+										Scope:    cm.scope,
+										Zero:     mustFmtTemplate(funcType, c.Zero),
+										One:      mustFmtTemplate(funcType, c.One),
+										Two:      mustFmtTemplate(funcType, c.Two),
+										Few:      mustFmtTemplate(funcType, c.Few),
+										Many:     mustFmtTemplate(funcType, c.Many),
+										Other:    mustFmtTemplate(funcType, c.Other),
+									}
+									if u := validateForms(
+										&srcErrs, locale, pos, pluralForms, m,
+										pluralcheck.CheckOrdinal,
+									); u != nil {
+										unsupported = append(unsupported, unsupportedForms{
+											Pos: pos, Forms: u,
+										})
+									}
+									msgs = append(msgs, m)
+									positions = append(positions, pos)
+									ordinals = append(ordinals, c.Category)
+								}
+								for _, err := range pluralcheck.CheckOrdinalCategories(
+									locale, pluralForms, defined,
+								) {
+									appendSrcErr(&srcErrs, pos, err)
+								}
+								for _, q := range args[1:] {
+									if q != nil {
+										validateQuantityArgument(
+											&srcErrs, pos, q, pkg.TypesInfo,
+										)
+									}
+								}
+
+							case FuncTypePlural, FuncTypePluralBlock, FuncTypePluralRange:
+								cl, ok := args[0].(*ast.CompositeLit)
+								if !ok {
+									// Unsupported argument value type.
+									appendSrcErr(&srcErrs, pos, fmt.Errorf(
+										"%w: %s", ErrSourceArgType, typeKind(args[0]),
+									))
+									descend = false
+									continue
+								}
+								f := parseForms(fileset, cl, pkg.TypesInfo, &srcErrs)
+								msg.Zero = mustFmtTemplate(funcType, f.Zero)
+								msg.One = mustFmtTemplate(funcType, f.One)
+								msg.Two = mustFmtTemplate(funcType, f.Two)
+								msg.Few = mustFmtTemplate(funcType, f.Few)
+								msg.Many = mustFmtTemplate(funcType, f.Many)
+								msg.Other = mustFmtTemplate(funcType, f.Other)
+								if load.DeriveOne && funcType != FuncTypePluralRange &&
+									msg.One == "" && pluralForms.Cardinal.One {
+									msg.One, derivedOne = singular.Template(msg.Other)
+								}
+
+								check := pluralcheck.Check
+								if funcType == FuncTypePluralRange {
+									check = pluralcheck.CheckRange
+								}
+								if u := validateForms(
+									&srcErrs, locale, pos, pluralForms, msg, check,
+								); u != nil {
+									unsupported = append(unsupported, unsupportedForms{
+										Pos: pos, Forms: u,
+									})
+								}
+
+								// Range calls have two quantity arguments.
+								for _, q := range args[1:] {
+									if q != nil {
+										validateQuantityArgument(
+											&srcErrs, pos, q, pkg.TypesInfo,
+										)
+									}
+								}
+								if funcType == FuncTypePluralRange {
+									msg = pluralRangeMsg(pluralForms, msg)
+								}
+
+							default:
+								var textValue string
+								switch k := args[0].(type) {
+								case *ast.Ident:
+									v := argType.Value
+
+									if v != nil && v.Kind() == constant.String {
+										// Constants are supported.
+										textValue = constant.StringVal(v)
+									} else if rv, ok := rangeVars[pkg.TypesInfo.Uses[k]]; ok {
+										// Variables ranging over composite literals
+										// are extracted element by element.
+										l, err := rv.elements(pkg.TypesInfo)
+										if err != nil {
+											appendSrcErr(&srcErrs, pos, err)
+											continue
+										}
+										for _, e := range l {
+											p := fileset.Position(e.Pos())
+											if trimpath {
+												p.Filename = mustTrimPath(pathPattern, p.Filename)
+											}
+											p.Filename = filepath.ToSlash(p.Filename)
+											text, _ := literalText(e, pkg.TypesInfo)
+											m := msg
+											m.Other = mustFmtTemplate(funcType, text)
+											msgs = append(msgs, m)
+											positions = append(positions, p)
+										}
+									} else {
+										// Unsupported argument value type.
+										appendSrcErr(&srcErrs, pos, fmt.Errorf(
+											"%w: %s", ErrSourceArgType, typeKind(args[0]),
+										))
+										continue
+									}
+								case *ast.BasicLit:
+									textValue = k.Value
+								default:
+									appendSrcErr(&srcErrs, pos, fmt.Errorf(
+										"%w: %s", ErrSourceArgType, typeKind(args[0]),
+									))
+									continue
+								}
+								if msgs == nil {
+									msg.Other = mustFmtTemplate(funcType, textValue)
+								}
+							}
+							if msgs == nil {
+								msgs, positions = []Msg{msg}, []token.Position{pos}
+							}
+							if funcType == FuncTypeCardinal {
+								for i := range msgs {
+									msgs[i] = cardinalMsg(pluralForms, msgs[i])
+									if msgs[i].Other != "" {
+										validatePluralTemplate(
+											&srcErrs, positions[i], msgs[i].Other,
+										)
+									}
+								}
+								if len(args) > 1 && args[1] != nil {
+									validateQuantityArgument(
+										&srcErrs, pos, args[1], pkg.TypesInfo,
+									)
+								}
+							}
 
-					if msg.Other == "" {
-						appendSrcErr(&srcErrs, pos, ErrSourceTextEmpty)
-					}
+							if !commented {
+								// The comment of the call applies to all of its messages.
+								commented = true
+								var commentEnd token.Pos
+								for _, group := range file.Comments {
+									if group.Pos() < call.Pos() && group.End() < call.Pos() {
+										commentLines = extractComments(group)
+										commentEnd = group.End()
+									}
+								}
+								// Directives are not part of the description and only apply
+								// if no other message is between the comment and the call.
+								var dirErrs []error
+								commentLines, dirs, dirErrs = parseDirectives(commentLines)
+								if commentEnd < prevCall {
+									dirs, dirErrs = directives{}, nil
+								}
+								prevCall = call.Pos()
+								for _, err := range dirErrs {
+									appendSrcErr(&srcErrs, pos, err)
+								}
+								if len(commentLines) < 1 ||
+									!commentAttached(fileset, file, call, commentEnd) {
+									appendSrcWarn(&srcErrs, pos, ErrDescriptionMissing)
+								}
+							}
+							editions := dirs.editions
+							commentForms := dirs.forms != localize.Forms{}
+							if commentForms && funcType != FuncTypeCardinal {
+								appendSrcErr(&srcErrs, pos, fmt.Errorf(
+									"%w: plural form comments only apply to %s calls",
+									ErrInvalidDirective, FuncTypeCardinal,
+								))
+								commentForms = false
+							}
+							if commentForms {
+								for i := range msgs {
+									msgs[i] = commentFormsMsg(dirs.forms, msgs[i])
+									if u := validateForms(
+										&srcErrs, locale, positions[i], pluralForms,
+										msgs[i], pluralcheck.Check,
+									); u != nil {
+										unsupported = append(unsupported, unsupportedForms{
+											Pos: positions[i], Forms: u,
+										})
+									}
+								}
+							}
+
+							mode := dedent
+							if dirs.dedent != nil {
+								mode = *dirs.dedent
+							}
+
+							for i, msg := range msgs {
+								pos := positions[i]
+								var msgOrdinals []cldr.CLDRPluralForm
+								if ordinals != nil {
+									msgOrdinals = ordinals[i : i+1 : i+1]
+								}
+								if verbose && !quiet {
+									fmt.Fprintf(
+										os.Stderr, "%s:%d:%d\n",
+										pos.Filename, pos.Line, pos.Column,
+									)
+								}
+
+								if msg.Other == "" {
+									appendSrcErr(&srcErrs, pos, ErrSourceTextEmpty)
+								}
+								warnSuspiciousPlaceholders(&srcErrs, pos, msg)
+								validateProtected(&srcErrs, pos, msg, dirs.protected)
+
+								msg.Description = strings.Join(commentLines, "\n")
+								if mode == strfmt.DedentReflow &&
+									(funcType == FuncTypeBlock || funcType == FuncTypePluralBlock) {
+									reflowMsg(&msg)
+								}
+								normalizeMsg(&msg, load.Normalization)
+
+								var descriptions []string
+								if load.MergeDescriptions != DescriptionMergeNone {
+									// Descriptions are set once all calls are found.
+									if msg.Description != "" {
+										descriptions = []string{msg.Description}
+									}
+									msg.Description = ""
+								}
+								msg.Hash = msgHash(load.Hash, msg, true)
+
+								if m, ok := collection.Messages[msg]; ok {
+									// Identical message was already found in another place.
+									// Merge messages into one.
+									i, found := slices.BinarySearchFunc(m.Pos, pos, comparePos)
+									m.Ordinals = ordinal.Merge(m.Ordinals, msgOrdinals)
+									if found {
+										// Same call found again, e.g. in a test variant
+										// of the package, or identical forms used for
+										// multiple ordinal categories of the same call.
+										collection.Messages[msg] = m
+										continue
+									}
+									if load.MergeDescriptions == DescriptionMergeError &&
+										len(descriptions) > 0 && len(m.Descriptions) > 0 &&
+										!slices.Contains(m.Descriptions, descriptions[0]) {
+										appendSrcErr(&srcErrs, pos, fmt.Errorf(
+											"%w: %s:%d:%d", ErrDescriptionConflict,
+											m.Pos[0].Filename, m.Pos[0].Line, m.Pos[0].Column,
+										))
+									}
+									m.Pos = slices.Insert(m.Pos, i, pos)
+									m.Descriptions = mergeSorted(m.Descriptions, descriptions)
+									m.Editions = mergeEditions(m.Editions, editions)
+									m.Regions = mergeRegions(m.Regions, dirs.regions)
+									m.ErrorCodes = mergeSorted(m.ErrorCodes, dirs.codes)
+									m.Schedule = schedule.Merge(m.Schedule, dirs.schedule)
+									m.Heading = m.Heading || dirs.heading
+									m.Protected = mergeSorted(m.Protected, dirs.protected)
+									if i == 0 {
+										m.Section = cmp.Or(msg.Scope, fileSection)
+									}
+									m.DerivedOne = m.DerivedOne || derivedOne
+									m.CommentForms = m.CommentForms || commentForms
+									collection.Messages[msg] = m
+									stats.Merges++
+								} else if other, ok := collection.byHash[msg.Hash]; ok {
+									// A different message with the same hash
+									// would silently share its translations.
+									p := collection.Messages[other].Pos[0]
+									appendSrcErr(&srcErrs, pos, fmt.Errorf(
+										"%w: %s:%d:%d",
+										ErrHashCollision, p.Filename, p.Line, p.Column,
+									))
+								} else {
+									// New message found.
+									m.Pos = []token.Position{pos}
+									m.Editions = editions
+									m.Regions = dirs.regions
+									m.ErrorCodes = dirs.codes
+									m.Schedule = dirs.schedule
+									m.Heading = dirs.heading
+									m.Protected = mergeSorted(nil, dirs.protected)
+									m.Section = cmp.Or(msg.Scope, fileSection)
+									m.DerivedOne = derivedOne
+									m.CommentForms = commentForms
+									m.Ordinals = msgOrdinals
+									m.Descriptions = descriptions
+									collection.Messages[msg] = m
+									collection.byHash[msg.Hash] = msg
+								}
+							}
 
-					for _, group := range file.Comments {
-						if group.Pos() < call.Pos() && group.End() < call.Pos() {
-							commentLines := extractComments(group)
-							msg.Description = strings.Join(commentLines, "\n")
 						}
-					}
-
-					msg.Hash = messageHash(msg.Other, msg.Description)
-
-					if m, ok := collection.Messages[msg]; ok {
-						// Identical message was already found in another place.
-						// Merge messages into one.
-						m.Pos = append(m.Pos, pos)
-						collection.Messages[msg] = m
-						stats.Merges.Add(1)
-					} else {
-						// New message found.
-						m.Pos = []token.Position{pos}
-						collection.Messages[msg] = m
-					}
-
-					return true
-				})
+						return descend
+					})
+				}
+			}
+			if load.Profile != nil {
+				load.Profile.inspect(pkg, start)
 			}
 		}
 	}
 
-	bundle, err = ParseBundle(pkgBundle, collection)
+	switch {
+	case load.Unit != nil:
+		err = loadUnit(ctx, fileset, load.Unit, process)
+	case load.batched():
+		err = loadBatched(
+			ctx, fileset, pathPattern, load, quiet, verbose, detectBundle, process,
+		)
+	default:
+		err = loadAll(ctx, fileset, pathPattern, load.Profile, func(pkgs []*packages.Package) {
+			detectBundle(pkgs)
+			process(pkgs)
+		})
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("loading packages: %w", err)
+	}
+	if load.MergeDescriptions != DescriptionMergeNone {
+		collection.applyDescriptions()
+	}
+	stats.Messages = int64(len(collection.Messages))
+	reportSchedules(&srcErrs, stats, collection, time.Now())
+	if load.Unit != nil {
+		return collection, nil, stats, srcErrs, nil
+	}
+
+	if pkgBundle != nil {
+		bundle, err = parseBundleDir(pkgBundle.Dir, load.Salvage)
+	} else {
+		// The bundle package contains no Go files yet.
+		bundle, err = parseBundleDir(bundlePkg, load.Salvage)
+	}
 	if err != nil {
 		return collection, nil, stats, nil, fmt.Errorf("parsing bundle: %w", err)
 	}
+	validateUnsupportedForms(&srcErrs, locale, bundle, unsupported)
 	if !quiet && verbose {
 		for locale := range bundle.Catalogs {
 			fmt.Fprintf(os.Stderr, "catalog detected: %s\n", locale.String())
@@ -369,13 +1250,89 @@ func Parse(
 	return collection, bundle, stats, srcErrs, nil
 }
 
-func isPkgLocalizeBundle(bundlePkg string, pkg *packages.Package) bool {
-	if c, ok := strings.CutPrefix(pkg.Dir, pkg.Module.Dir); ok {
-		if len(c) > 1 && c[0] == '/' && strings.HasSuffix(c[1:], bundlePkg) {
-			return true
+// applyDescriptions sets the description of all messages merged from calls
+// with different descriptions to their descriptions separated by line breaks.
+// Their hashes don't depend on their descriptions, such that they're stable
+// when calls are added or removed.
+func (c *Collection) applyDescriptions() {
+	var described []Msg
+	for msg, meta := range c.Messages {
+		if len(meta.Descriptions) > 0 {
+			described = append(described, msg)
 		}
 	}
-	return false
+	for _, msg := range described {
+		meta := c.Messages[msg]
+		delete(c.Messages, msg)
+		msg.Description = strings.Join(meta.Descriptions, "\n")
+		c.Messages[msg] = meta
+		c.byHash[msg.Hash] = msg
+	}
+}
+
+// packageSection returns the section the package clause doc comments
+// of files assign all messages of their package to, which is empty
+// if there's none.
+func packageSection(
+	files []*ast.File, position func(token.Pos) token.Position,
+	srcErrs *[]ErrorSrc,
+) (name string) {
+	for _, file := range files {
+		if file.Doc != nil {
+			name = sectionDirective(file.Doc, name, position, srcErrs)
+		}
+	}
+	return name
+}
+
+// fileSection returns the section the comments of file outside
+// the package clause doc comment assign all messages of file to,
+// which is empty if there's none.
+func fileSection(
+	file *ast.File, position func(token.Pos) token.Position,
+	srcErrs *[]ErrorSrc,
+) (name string) {
+	for _, group := range file.Comments {
+		if group != file.Doc {
+			name = sectionDirective(group, name, position, srcErrs)
+		}
+	}
+	return name
+}
+
+// sectionDirective returns the section of the section directive in group
+// or current if group contains none. Directives contradicting current
+// are reported as invalid.
+func sectionDirective(
+	group *ast.CommentGroup, current string,
+	position func(token.Pos) token.Position, srcErrs *[]ErrorSrc,
+) string {
+	for _, c := range group.List {
+		name, ok, err := section.ParseDirective(c.Text)
+		if !ok {
+			continue
+		}
+		if err == nil && current != "" && name != current {
+			err = fmt.Errorf("conflicting sections %q and %q", current, name)
+		}
+		if err != nil {
+			appendSrcErr(srcErrs, position(c.Pos()),
+				fmt.Errorf("%w: %w", ErrInvalidDirective, err))
+			continue
+		}
+		current = name
+	}
+	return current
+}
+
+// isPkgLocalizeBundle returns true if pkg is the bundle package identified
+// by importPath or, if importPath is empty, by its absolute directory bundleDir.
+func isPkgLocalizeBundle(bundleDir, importPath string, pkg *packages.Package) bool {
+	if importPath != "" {
+		return pkg.PkgPath == importPath
+	}
+	rest, ok := cutPathPrefix(filepath.Clean(pkg.Dir), bundleDir, caseInsensitivePaths)
+	return ok && rest == ""
 }
 
 func extractComments(group *ast.CommentGroup) (lines []string) {
@@ -394,7 +1351,10 @@ func mustTrimPath(basePattern, s string) string {
 	if err != nil {
 		panic(fmt.Errorf("getting absolute path: %w", err))
 	}
-	return strings.TrimPrefix(s, abs)
+	if c, ok := cutPathPrefix(s, abs, caseInsensitivePaths); ok {
+		return c
+	}
+	return s
 }
 
 var ErrSyntax = errors.New("syntax error")
@@ -403,8 +1363,9 @@ var hasherPool = sync.Pool{
 	New: func() any { return xxhash.New() },
 }
 
-// messageHash computes a unique 64-bit XXHash for a message.
-func messageHash(text, description string) string {
+// MessageHash computes a unique 64-bit XXHash for a message from its text,
+// which is form Other of plural messages, and its description.
+func MessageHash(text, description string) string {
 	h := hasherPool.Get().(hash.Hash64)
 	defer hasherPool.Put(h)
 
@@ -500,10 +1461,149 @@ func parseForms(
 	return forms
 }
 
+// ordinalForms are the forms of an ordinal category of a PluralOrdinal call.
+type ordinalForms struct {
+	Category cldr.CLDRPluralForm
+	localize.Forms
+}
+
+// parseOrdinalForms parses the localize.OrdinalForms composite literal cl
+// and returns the forms of all categories in CLDR order. The forms of
+// each category are parsed by parseForms unless empty.
+func parseOrdinalForms(
+	fset *token.FileSet, cl *ast.CompositeLit, info *types.Info, srcErrs *[]ErrorSrc,
+) []ordinalForms {
+	l := []ordinalForms{
+		{Category: cldr.CLDRPluralFormZero},
+		{Category: cldr.CLDRPluralFormOne},
+		{Category: cldr.CLDRPluralFormTwo},
+		{Category: cldr.CLDRPluralFormFew},
+		{Category: cldr.CLDRPluralFormMany},
+		{Category: cldr.CLDRPluralFormOther},
+	}
+	for i, elt := range cl.Elts {
+		var fieldName string
+		var valExpr ast.Expr
+
+		switch v := elt.(type) {
+		case *ast.KeyValueExpr:
+			ident, ok := v.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			fieldName = ident.Name
+			valExpr = v.Value
+		default:
+			// Positional field.
+			if i >= len(l) {
+				continue
+			}
+			fieldName = l[i].Category.String()
+			valExpr = v
+		}
+
+		forms, ok := valExpr.(*ast.CompositeLit)
+		if !ok {
+			// Unsupported forms value type.
+			appendSrcErr(srcErrs, fset.Position(valExpr.Pos()), fmt.Errorf(
+				"%w: %s", ErrSourceArgType, typeKind(valExpr),
+			))
+			continue
+		}
+		for j := range l {
+			if l[j].Category.String() == fieldName {
+				l[j].Forms = parseForms(fset, forms, info, srcErrs)
+			}
+		}
+	}
+	return l
+}
+
 func appendSrcErr(s *[]ErrorSrc, pos token.Position, err error) {
 	*s = append(*s, ErrorSrc{Position: pos, Err: err})
 }
 
+// reportSchedules counts the scheduled, embargoed and expired messages
+// of collection at now and warns about expired messages.
+func reportSchedules(
+	srcErrs *[]ErrorSrc, stats *Statistics, collection *Collection, now time.Time,
+) {
+	for _, m := range collection.Ordered() {
+		if m.Schedule.IsZero() {
+			continue
+		}
+		stats.Scheduled++
+		switch {
+		case m.Schedule.Embargoed(now):
+			stats.Embargoed++
+		case m.Schedule.Expired(now):
+			stats.Expired++
+			appendSrcWarn(srcErrs, m.Pos[0], fmt.Errorf("%w: not-after %s",
+				ErrMessageExpired, schedule.Format(m.Schedule.NotAfter)))
+		}
+	}
+}
+
+func appendSrcWarn(s *[]ErrorSrc, pos token.Position, err error) {
+	*s = append(*s, ErrorSrc{Position: pos, Err: err, Severity: SeverityWarning})
+}
+
+// commentAttached returns true if a comment ending at commentEnd is directly
+// above call or the statement of file containing call.
+func commentAttached(
+	fset *token.FileSet, file *ast.File, call *ast.CallExpr, commentEnd token.Pos,
+) bool {
+	if !commentEnd.IsValid() {
+		return false
+	}
+	line := fset.Position(commentEnd).Line
+	if line == fset.Position(call.Pos()).Line-1 {
+		return true
+	}
+	path, _ := astutil.PathEnclosingInterval(file, call.Pos(), call.End())
+	for _, n := range path {
+		if stmt, ok := n.(ast.Stmt); ok {
+			return line == fset.Position(stmt.Pos()).Line-1
+		}
+	}
+	return false
+}
+
+// warnSuspiciousPlaceholders warns about suspicious placeholders
+// in all forms of msg.
+func warnSuspiciousPlaceholders(s *[]ErrorSrc, pos token.Position, msg Msg) {
+	var suspicious []string
+	for _, t := range [...]string{
+		msg.Zero, msg.One, msg.Two, msg.Few, msg.Many, msg.Other,
+	} {
+		for _, p := range fmtplaceholder.Suspicious(t) {
+			if !slices.Contains(suspicious, p) {
+				suspicious = append(suspicious, p)
+			}
+		}
+	}
+	for _, p := range suspicious {
+		appendSrcWarn(s, pos, fmt.Errorf("%w %q, "+
+			"use %%%% for a literal percent sign", ErrSuspiciousPlaceholder, p))
+	}
+}
+
+// validateProtected reports protected texts that aren't
+// contained in every non-empty text of msg.
+func validateProtected(s *[]ErrorSrc, pos token.Position, msg Msg, protected []string) {
+	for _, p := range protected {
+		for _, t := range [...]string{
+			msg.Zero, msg.One, msg.Two, msg.Few, msg.Many, msg.Other,
+		} {
+			if t != "" && !strings.Contains(t, p) {
+				appendSrcErr(s, pos, fmt.Errorf("%w: %w: %q",
+					ErrInvalidDirective, protect.ErrNotInText, p))
+				break
+			}
+		}
+	}
+}
+
 func mustFmtTemplate(funcType string, templateText string) string {
 	if templateText == "" {
 		return ""
@@ -522,122 +1622,183 @@ func mustFmtTemplate(funcType string, templateText string) string {
 	return templateText
 }
 
-func validateForms(
-	errs *[]ErrorSrc, locale language.Tag, pos token.Position,
-	pluralForms cldr.PluralForms, msg Msg,
-) {
-	// TODO returns the correct line:column for the particular line the error was
-	// detected at since currently it's the pos of the call.
-	if msg.Other == "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: all languages require form Other",
-			ErrMissingPluralForm,
-		))
-	}
-	validatePluralTemplate(errs, pos, msg.Other)
-
-	if pluralForms.Cardinal.Zero && msg.Zero == "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: locale %q requires plural form Zero",
-			ErrMissingPluralForm, locale.String(),
-		))
-	}
-	if !pluralForms.Cardinal.Zero && msg.Zero != "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: locale %q doesn't support plural form Zero",
-			ErrUnsupportedPluralForm, locale.String(),
-		))
+// cardinalMsg returns the plural message of a Cardinal call with
+// the template msg.Other used for all forms required by pluralForms,
+// which is identical to the message of the equivalent Plural call.
+func cardinalMsg(pluralForms cldr.PluralForms, msg Msg) Msg {
+	msg.FuncType = FuncTypePlural
+	if pluralForms.Cardinal.Zero {
+		msg.Zero = msg.Other
 	}
-	if msg.Zero != "" {
-		validatePluralTemplate(errs, pos, msg.Zero)
+	if pluralForms.Cardinal.One {
+		msg.One = msg.Other
 	}
-
-	if pluralForms.Cardinal.One && msg.One == "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: locale %q requires plural form One",
-			ErrMissingPluralForm, locale.String(),
-		))
+	if pluralForms.Cardinal.Two {
+		msg.Two = msg.Other
 	}
-	if !pluralForms.Cardinal.One && msg.One != "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: locale %q doesn't support plural form One",
-			ErrUnsupportedPluralForm, locale.String(),
-		))
+	if pluralForms.Cardinal.Few {
+		msg.Few = msg.Other
 	}
-	if msg.One != "" {
-		validatePluralTemplate(errs, pos, msg.One)
+	if pluralForms.Cardinal.Many {
+		msg.Many = msg.Other
 	}
+	return msg
+}
 
-	if pluralForms.Cardinal.Two && msg.Two == "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: locale %q requires plural form Two",
-			ErrMissingPluralForm, locale.String(),
-		))
+// parseFormComment parses plural form comment l like
+// `one: "You have %d unread email"` providing the template of a form
+// of a Cardinal call into forms. ok is false if l isn't a form comment,
+// which requires a quoted Go string after the name of the form.
+func parseFormComment(l string, forms *localize.Forms) (ok bool, err error) {
+	name, text, ok := strings.Cut(l, ":")
+	if !ok {
+		return false, nil
 	}
-	if !pluralForms.Cardinal.Two && msg.Two != "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: locale %q doesn't support plural form Two",
-			ErrUnsupportedPluralForm, locale.String(),
-		))
+	text = strings.TrimSpace(text)
+	if _, err := strconv.Unquote(text); err != nil {
+		return false, nil
 	}
-	if msg.Two != "" {
-		validatePluralTemplate(errs, pos, msg.Two)
+	var form *string
+	switch name {
+	case "zero":
+		form = &forms.Zero
+	case "one":
+		form = &forms.One
+	case "two":
+		form = &forms.Two
+	case "few":
+		form = &forms.Few
+	case "many":
+		form = &forms.Many
+	case "other":
+		return true, errors.New(
+			"plural form comment other: form Other is the argument of the call",
+		)
+	default:
+		return false, nil
 	}
-
-	if pluralForms.Cardinal.Few && msg.Few == "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: locale %q requires plural form Few",
-			ErrMissingPluralForm, locale.String(),
-		))
+	if *form != "" {
+		return true, fmt.Errorf("duplicate plural form comment %s", name)
 	}
-	if !pluralForms.Cardinal.Few && msg.Few != "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: locale %q doesn't support plural form Few",
-			ErrUnsupportedPluralForm, locale.String(),
-		))
+	*form = text
+	return true, nil
+}
+
+// commentFormsMsg returns the plural message of a Cardinal call with
+// the templates of the plural form comments forms replacing the
+// templates of msg derived from form Other.
+func commentFormsMsg(forms localize.Forms, msg Msg) Msg {
+	for _, f := range [...]struct{ comment, text *string }{
+		{&forms.Zero, &msg.Zero},
+		{&forms.One, &msg.One},
+		{&forms.Two, &msg.Two},
+		{&forms.Few, &msg.Few},
+		{&forms.Many, &msg.Many},
+	} {
+		if *f.comment != "" {
+			*f.text = mustFmtTemplate(FuncTypeCardinal, *f.comment)
+		}
 	}
-	if msg.Few != "" {
-		validatePluralTemplate(errs, pos, msg.Few)
+	return msg
+}
+
+// pluralRangeMsg returns the plural message of a PluralRange call with
+// the template msg.Other used for the forms required by pluralForms that
+// ranges never select, such that catalogs provide all forms to translators.
+func pluralRangeMsg(pluralForms cldr.PluralForms, msg Msg) Msg {
+	msg.FuncType = FuncTypePlural
+	for _, f := range [...]struct {
+		required, ranged bool
+		text             *string
+	}{
+		{pluralForms.Cardinal.Zero, pluralForms.Range.Zero, &msg.Zero},
+		{pluralForms.Cardinal.One, pluralForms.Range.One, &msg.One},
+		{pluralForms.Cardinal.Two, pluralForms.Range.Two, &msg.Two},
+		{pluralForms.Cardinal.Few, pluralForms.Range.Few, &msg.Few},
+		{pluralForms.Cardinal.Many, pluralForms.Range.Many, &msg.Many},
+	} {
+		if f.required && !f.ranged && *f.text == "" {
+			*f.text = msg.Other
+		}
 	}
+	return msg
+}
 
-	if pluralForms.Cardinal.Many && msg.Many == "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: locale %q requires plural form Many",
-			ErrMissingPluralForm, locale.String(),
-		))
+// validateForms validates the forms of plural message msg using check
+// (either pluralcheck.Check or pluralcheck.CheckRange)
+// and returns the forms not supported by locale.
+func validateForms(
+	errs *[]ErrorSrc, locale language.Tag, pos token.Position,
+	pluralForms cldr.PluralForms, msg Msg,
+	check func(language.Tag, cldr.PluralForms, pluralcheck.Forms) (
+		[]error, []cldr.CLDRPluralForm,
+	),
+) (unsupported []cldr.CLDRPluralForm) {
+	// TODO returns the correct line:column for the particular line the error was
+	// detected at since currently it's the pos of the call.
+	l, unsupported := check(locale, pluralForms, pluralcheck.Forms{
+		Zero: msg.Zero, One: msg.One, Two: msg.Two,
+		Few: msg.Few, Many: msg.Many, Other: msg.Other,
+	})
+	for _, err := range l {
+		appendSrcErr(errs, pos, err)
 	}
-	if !pluralForms.Cardinal.Many && msg.Many != "" {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: locale %q doesn't support plural form Many",
-			ErrUnsupportedPluralForm, locale.String(),
-		))
+	return unsupported
+}
+
+// unsupportedForms are plural forms of a message at Pos
+// that the source locale doesn't support.
+type unsupportedForms struct {
+	Pos   token.Position
+	Forms []cldr.CLDRPluralForm
+}
+
+// validateUnsupportedForms reports the forms in l that are required
+// by none of the catalog locales of bundle. Source code may provide
+// templates for forms the source locale doesn't support which are used
+// by the readers of catalog locales requiring them if not translated.
+func validateUnsupportedForms(
+	errs *[]ErrorSrc, locale language.Tag, bundle *Bundle, l []unsupportedForms,
+) {
+	required := map[cldr.CLDRPluralForm]bool{}
+	for tag := range bundle.CatalogParts {
+		pluralForms, ok := cldr.ByTagOrBase(tag)
+		if !ok {
+			continue
+		}
+		for _, f := range pluralForms.CardinalForms {
+			required[f] = true
+		}
 	}
-	if msg.Many != "" {
-		validatePluralTemplate(errs, pos, msg.Many)
+	for _, u := range l {
+		for _, f := range u.Forms {
+			if !required[f] {
+				appendSrcErr(errs, u.Pos, fmt.Errorf(
+					"%w: locale %q doesn't support plural form %s "+
+						"and no catalog locale requires it",
+					ErrUnsupportedPluralForm, locale.String(), f,
+				))
+			}
+		}
 	}
 }
 
 func validatePluralTemplate(errs *[]ErrorSrc, pos token.Position, s string) {
-	placeholders := fmtplaceholder.Extract(s)
-	if len(placeholders) < 1 {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w", ErrMissingQuantityPlaceholder,
-		))
-	} else if len(placeholders) > 1 {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: found %d", ErrTooManyQuantityPlaceholders, len(placeholders),
-		))
-	}
-	if !fmtplaceholder.Numeric(placeholders[0]) {
-		appendSrcErr(errs, pos, fmt.Errorf(
-			"%w: verb found: %q", ErrWrongPlaceholderVerb, placeholders[0],
-		))
+	for _, err := range pluralcheck.Template(s) {
+		appendSrcErr(errs, pos, err)
 	}
 }
 
 func validateQuantityArgument(
 	errs *[]ErrorSrc, pos token.Position, expr ast.Expr, info *types.Info,
 ) {
+	// Validate the value of quantities marked using localize.Sensitive.
+	if call, ok := ast.Unparen(expr).(*ast.CallExpr); ok && len(call.Args) == 1 {
+		if fn := calledFunc(info, call); fn != nil &&
+			fn.FullName() == targetPackage+".Sensitive" {
+			expr = call.Args[0]
+		}
+	}
 	tv := info.Types[expr]
 	basic, ok := tv.Type.Underlying().(*types.Basic)
 	if ok {
@@ -646,19 +1807,39 @@ func validateQuantityArgument(
 			return
 		}
 	}
+	// Accept implementations of localize.Quantifier and localize.Int64er.
+	if hasMethod(tv.Type, "Quantity", types.Float64) ||
+		hasMethod(tv.Type, "Int64", types.Int64) {
+		return
+	}
 	appendSrcErr(errs, pos, fmt.Errorf(
 		"%w: %s", ErrWrongQuantityArgType, tv.Type.String(),
 	))
 }
 
+// hasMethod returns true if the method set of t contains method name
+// without parameters and with a single result of basic kind result.
+func hasMethod(t types.Type, name string, result types.BasicKind) bool {
+	sel := types.NewMethodSet(t).Lookup(nil, name)
+	if sel == nil {
+		return false
+	}
+	sig, ok := sel.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	basic, ok := sig.Results().At(0).Type().(*types.Basic)
+	return ok && basic.Kind() == result
+}
+
 func MsgFromGettextMessage(
 	pluralForms cldr.PluralForms, msg Msg, meta MsgMeta,
 ) gettext.Message {
 	var comments gettext.Comments
-	for _, pos := range meta.Pos {
+	for _, ref := range meta.References() {
 		comments.Text = append(comments.Text, gettext.Comment{
 			Type:  gettext.CommentTypeReference,
-			Value: gettext.FmtCodeRef(pos.Filename, pos.Line),
+			Value: ref,
 		})
 	}
 	if msg.Description != "" {
@@ -675,6 +1856,15 @@ func MsgFromGettextMessage(
 			},
 		},
 	}
+	edition.Set(&gm, meta.Editions)
+	region.Set(&gm, meta.Regions)
+	errcode.Set(&gm, meta.ErrorCodes)
+	schedule.Set(&gm, meta.Schedule)
+	heading.Set(&gm, meta.Heading)
+	protect.Set(&gm, meta.Protected)
+	section.Set(&gm, meta.Section)
+	section.SetScoped(&gm, msg.Scope != "")
+	ordinal.Set(&gm, meta.Ordinals)
 
 	switch msg.FuncType {
 	case FuncTypePlural, FuncTypePluralBlock:
@@ -0,0 +1,526 @@
+package codeparser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/edition"
+	"github.com/romshark/localize/internal/errcode"
+	"github.com/romshark/localize/internal/pluralcheck"
+	"github.com/romshark/localize/internal/protect"
+	"github.com/romshark/localize/internal/region"
+	"github.com/romshark/localize/internal/schedule"
+	"github.com/romshark/localize/internal/section"
+	"github.com/romshark/localize/strfmt"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestParseDirectives(t *testing.T) {
+	description, d, errs := parseDirectives([]string{
+		"Title of the settings page.",
+		"editions: enterprise, cloud",
+		"regions: DE, AT",
+		"error-codes: not_found, gone",
+		"not-before: 2025-11-28",
+		"not-after: 2025-12-01T23:59:59+01:00",
+		"dedent: reflow",
+		"heading",
+		"do-not-translate: Acme Cloud",
+		"do-not-translate: https://acme.com, https://acme.org",
+		"localize:section Checkout",
+		"Keep it short.",
+	})
+	require.Empty(t, errs)
+	require.Equal(t, []string{"Title of the settings page.", "Keep it short."}, description)
+	require.Equal(t, []string{"cloud", "enterprise"}, d.editions)
+	require.Equal(t, []string{"AT", "DE"}, d.regions)
+	require.Equal(t, []string{"gone", "not_found"}, d.codes)
+	require.True(t, d.heading)
+	require.Equal(t, "2025-11-28 00:00:00 +0000 UTC", d.schedule.NotBefore.String())
+	require.Equal(t, "2025-12-01T22:59:59Z",
+		d.schedule.NotAfter.UTC().Format(time.RFC3339))
+	require.Equal(t, []string{"Acme Cloud", "https://acme.com, https://acme.org"}, d.protected)
+	require.NotNil(t, d.dedent)
+	require.Equal(t, strfmt.DedentReflow, *d.dedent)
+
+	description, d, errs = parseDirectives([]string{"Greeting."})
+	require.Empty(t, errs)
+	require.Equal(t, []string{"Greeting."}, description)
+	require.Equal(t, directives{}, d)
+
+	description, _, errs = parseDirectives([]string{
+		"editions: a b", "dedent: wrap", "do-not-translate: ", "regions: ZZ",
+		"not-after: tomorrow", "error-codes: not found", "Greeting.",
+	})
+	require.Len(t, errs, 6)
+	require.ErrorIs(t, errs[0], edition.ErrInvalidName)
+	require.ErrorIs(t, errs[0], ErrInvalidDirective)
+	require.ErrorIs(t, errs[1], ErrInvalidDirective)
+	require.ErrorIs(t, errs[2], protect.ErrEmpty)
+	require.ErrorIs(t, errs[3], region.ErrInvalidRegion)
+	require.ErrorIs(t, errs[3], ErrInvalidDirective)
+	require.ErrorIs(t, errs[4], schedule.ErrInvalidTime)
+	require.ErrorIs(t, errs[5], errcode.ErrInvalidCode)
+	require.Equal(t, []string{"Greeting."}, description)
+
+	// Schedules ending before they start are discarded.
+	_, d, errs = parseDirectives([]string{
+		"not-before: 2025-12-01", "not-after: 2025-11-28",
+	})
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], schedule.ErrEmptyPeriod)
+	require.True(t, d.schedule.IsZero())
+}
+
+func TestValidateProtected(t *testing.T) {
+	var errs []ErrorSrc
+	validateProtected(&errs, token.Position{}, Msg{
+		One: "%d Acme seat", Other: "%d Acme seats",
+	}, []string{"Acme", "seats"})
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0].Err, ErrInvalidDirective)
+	require.ErrorIs(t, errs[0].Err, protect.ErrNotInText)
+	require.Contains(t, errs[0].Err.Error(), `"seats"`)
+}
+
+func TestErrorSrcCode(t *testing.T) {
+	for _, tt := range []struct {
+		err    error
+		expect string
+	}{
+		{ErrSourceTextEmpty, "text-empty"},
+		{fmt.Errorf("%w: *ast.Ident", ErrSourceArgType), "arg-type"},
+		{fmt.Errorf("%w: form Other", ErrMissingPluralForm), "plural-form-missing"},
+		{ErrUnsupportedPluralForm, "plural-form-unsupported"},
+		{ErrMissingQuantityPlaceholder, "quantity-placeholder-missing"},
+		{ErrTooManyQuantityPlaceholders, "quantity-placeholder-multiple"},
+		{ErrWrongPlaceholderVerb, "placeholder-verb"},
+		{ErrRangePlaceholders, "range-placeholders"},
+		{ErrOrdinalPlaceholders, "ordinal-placeholders"},
+		{ErrWrongQuantityArgType, "quantity-arg-type"},
+		{ErrInvalidDirective, "directive-invalid"},
+		{ErrUnknownTerm, "term-unknown"},
+		{ErrDescriptionConflict, "description-conflict"},
+		{ErrHashCollision, "hash-collision"},
+		{errors.New("other"), "unknown"},
+	} {
+		require.Equal(t, tt.expect, ErrorSrc{Err: tt.err}.Code(), tt.err.Error())
+	}
+}
+
+func TestApplyDescriptions(t *testing.T) {
+	described := Msg{Hash: MessageHash("Save", ""), Other: "Save", FuncType: FuncTypeText}
+	plain := Msg{Hash: MessageHash("Open", ""), Other: "Open", FuncType: FuncTypeText}
+	c := &Collection{
+		Messages: map[Msg]MsgMeta{
+			described: {Descriptions: []string{"Button label.", "Menu item."}},
+			plain:     {},
+		},
+		byHash: map[string]Msg{described.Hash: described, plain.Hash: plain},
+	}
+	c.applyDescriptions()
+
+	msg, meta, ok := c.ByHash(described.Hash)
+	require.True(t, ok)
+	require.Equal(t, "Button label.\nMenu item.", msg.Description)
+	require.Equal(t, []string{"Button label.", "Menu item."}, meta.Descriptions)
+	require.Len(t, c.Messages, 2)
+	_, ok = c.Messages[plain]
+	require.True(t, ok)
+}
+
+func TestReflowMsg(t *testing.T) {
+	m := Msg{One: "one\nline", Other: "other\nlines"}
+	reflowMsg(&m)
+	require.Equal(t, Msg{One: "one line", Other: "other lines", Reflow: true}, m)
+
+	m = Msg{Other: "single line"}
+	reflowMsg(&m)
+	require.Equal(t, Msg{Other: "single line"}, m)
+}
+
+func TestNormalizeMsg(t *testing.T) {
+	m := Msg{One: "Ein  Caf\u0065\u0301", Other: "%d  Caf\u0065\u0301s"}
+	normalizeMsg(&m, strfmt.NormalizeNone)
+	require.Equal(t, Msg{One: "Ein  Caf\u0065\u0301", Other: "%d  Caf\u0065\u0301s"}, m)
+
+	normalizeMsg(&m, strfmt.NormalizeNFC|strfmt.NormalizeSpaces)
+	require.Equal(t, Msg{One: "Ein Caf\u00e9", Other: "%d Caf\u00e9s"}, m)
+}
+
+func TestCardinalMsg(t *testing.T) {
+	english, ok := cldr.ByTagOrBase(language.English)
+	require.True(t, ok)
+	arabic, ok := cldr.ByTagOrBase(language.Arabic)
+	require.True(t, ok)
+
+	m := Msg{FuncType: FuncTypeCardinal, Other: "%d items"}
+	require.Equal(t, Msg{
+		FuncType: FuncTypePlural, One: "%d items", Other: "%d items",
+	}, cardinalMsg(english, m))
+	require.Equal(t, Msg{
+		FuncType: FuncTypePlural,
+		Zero:     "%d items", One: "%d items", Two: "%d items",
+		Few: "%d items", Many: "%d items", Other: "%d items",
+	}, cardinalMsg(arabic, m))
+}
+
+func TestParseFormComment(t *testing.T) {
+	var forms localize.Forms
+	ok, err := parseFormComment(`one: "You have %d unread email"`, &forms)
+	require.True(t, ok)
+	require.NoError(t, err)
+	ok, err = parseFormComment("few:`%d emails (few)`", &forms)
+	require.True(t, ok)
+	require.NoError(t, err)
+	require.Equal(t, localize.Forms{
+		One: `"You have %d unread email"`, Few: "`%d emails (few)`",
+	}, forms)
+
+	for _, l := range []string{
+		"one: the first item", `first: "x"`, "Unread emails.", `one "x"`,
+	} {
+		ok, err = parseFormComment(l, &forms)
+		require.False(t, ok, l)
+		require.NoError(t, err, l)
+	}
+
+	ok, err = parseFormComment(`one: "%d email"`, &forms)
+	require.True(t, ok)
+	require.Error(t, err, "duplicate")
+	ok, err = parseFormComment(`other: "%d emails"`, &forms)
+	require.True(t, ok)
+	require.Error(t, err)
+}
+
+func TestCommentFormsMsg(t *testing.T) {
+	english, ok := cldr.ByTagOrBase(language.English)
+	require.True(t, ok)
+
+	m := cardinalMsg(english, Msg{FuncType: FuncTypeCardinal, Other: "%d emails"})
+	require.Equal(t, Msg{
+		FuncType: FuncTypePlural, One: "%d email", Other: "%d emails",
+	}, commentFormsMsg(localize.Forms{One: `"%d email"`}, m))
+}
+
+func TestPluralRangeMsg(t *testing.T) {
+	english, ok := cldr.ByTagOrBase(language.English)
+	require.True(t, ok)
+	russian, ok := cldr.ByTagOrBase(language.Russian)
+	require.True(t, ok)
+
+	// English ranges never select form One.
+	m := Msg{FuncType: FuncTypePluralRange, Other: "%d–%d days"}
+	require.Equal(t, Msg{
+		FuncType: FuncTypePlural, One: "%d–%d days", Other: "%d–%d days",
+	}, pluralRangeMsg(english, m))
+
+	m = Msg{
+		FuncType: FuncTypePluralRange, One: "%d–%d день", Few: "%d–%d дня",
+		Other: "%d–%d дней",
+	}
+	require.Equal(t, Msg{
+		FuncType: FuncTypePlural, One: "%d–%d день", Few: "%d–%d дня",
+		Other: "%d–%d дней",
+	}, pluralRangeMsg(russian, m))
+}
+
+func TestValidatePluralTemplate(t *testing.T) {
+	var errs []ErrorSrc
+	validatePluralTemplate(&errs, token.Position{}, "%d items")
+	require.Empty(t, errs)
+
+	validatePluralTemplate(&errs, token.Position{}, "items")
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0].Err, ErrMissingQuantityPlaceholder)
+}
+
+func TestMergeEditions(t *testing.T) {
+	require.Nil(t, mergeEditions(nil, []string{"cloud"}))
+	require.Nil(t, mergeEditions([]string{"cloud"}, nil))
+	require.Equal(t, []string{"cloud", "enterprise"},
+		mergeEditions([]string{"enterprise"}, []string{"cloud", "enterprise"}))
+}
+
+func TestValidateUnsupportedForms(t *testing.T) {
+	english, ok := cldr.ByTagOrBase(language.English)
+	require.True(t, ok)
+	var errs []ErrorSrc
+	pos := token.Position{Filename: "main.go", Line: 1, Column: 1}
+	unsupported := validateForms(&errs, language.English, pos, english, Msg{
+		One:   "%d item",
+		Few:   "%d items (few)",
+		Many:  "%d items (many)",
+		Other: "%d items",
+	}, pluralcheck.Check)
+	require.Empty(t, errs)
+	require.Equal(t, []cldr.CLDRPluralForm{
+		cldr.CLDRPluralFormFew, cldr.CLDRPluralFormMany,
+	}, unsupported)
+
+	// Arabic requires both forms.
+	validateUnsupportedForms(&errs, language.English, &Bundle{
+		CatalogParts: map[language.Tag][]POFile{language.Arabic: nil},
+	}, []unsupportedForms{{Pos: pos, Forms: unsupported}})
+	require.Empty(t, errs)
+
+	// German requires neither.
+	validateUnsupportedForms(&errs, language.English, &Bundle{
+		CatalogParts: map[language.Tag][]POFile{language.German: nil},
+	}, []unsupportedForms{{Pos: pos, Forms: unsupported}})
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, errs[0].Err, ErrUnsupportedPluralForm)
+	require.Equal(t, pos, errs[0].Position)
+}
+
+func TestCommentAttached(t *testing.T) {
+	const src = `package p
+
+func f() {
+	// Attached to the statement.
+	println(T("a"),
+		T("b"))
+
+	println(
+		// Attached to the call.
+		T("c"),
+	)
+
+	// Not attached.
+
+	println(T("d"))
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	require.NoError(t, err)
+
+	attached := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || call.Fun.(*ast.Ident).Name != "T" {
+			return true
+		}
+		var commentEnd token.Pos
+		for _, g := range file.Comments {
+			if g.End() < call.Pos() {
+				commentEnd = g.End()
+			}
+		}
+		text := call.Args[0].(*ast.BasicLit).Value
+		attached[text] = commentAttached(fset, file, call, commentEnd)
+		return true
+	})
+	require.Equal(t, map[string]bool{
+		`"a"`: true, `"b"`: true, `"c"`: true, `"d"`: false,
+	}, attached)
+}
+
+func TestSections(t *testing.T) {
+	parse := func(t *testing.T, name, src string) *ast.File {
+		t.Helper()
+		file, err := parser.ParseFile(token.NewFileSet(), name, src, parser.ParseComments)
+		require.NoError(t, err)
+		return file
+	}
+	position := func(p token.Pos) token.Position { return token.Position{Offset: int(p)} }
+
+	pkgDoc := parse(t, "a.go", `// Package p does things.
+//
+//localize:section Account
+package p
+`)
+	plain := parse(t, "b.go", "package p\n")
+	file := parse(t, "c.go", `package p
+
+import "fmt"
+
+//localize:section Checkout
+
+// Pay pays.
+func Pay() { fmt.Println() }
+`)
+
+	var srcErrs []ErrorSrc
+	files := []*ast.File{pkgDoc, plain, file}
+	require.Equal(t, "Account", packageSection(files, position, &srcErrs))
+	require.Equal(t, "", fileSection(pkgDoc, position, &srcErrs))
+	require.Equal(t, "", fileSection(plain, position, &srcErrs))
+	require.Equal(t, "Checkout", fileSection(file, position, &srcErrs))
+	require.Empty(t, srcErrs)
+
+	conflicting := parse(t, "d.go", `//localize:section Cart
+package p
+
+//localize:section Checkout
+//localize:section Payment
+//localize:section
+`)
+	require.Equal(t, "Account", packageSection(
+		[]*ast.File{pkgDoc, conflicting}, position, &srcErrs,
+	))
+	require.Len(t, srcErrs, 1)
+	require.Equal(t, "Checkout", fileSection(conflicting, position, &srcErrs))
+	require.Len(t, srcErrs, 3)
+	for _, err := range srcErrs {
+		require.ErrorIs(t, err.Err, ErrInvalidDirective)
+	}
+	require.ErrorIs(t, srcErrs[2].Err, section.ErrEmpty)
+}
+
+func TestMsgMetaReferences(t *testing.T) {
+	m := MsgMeta{Pos: []token.Position{
+		{Filename: "a.go", Line: 3, Column: 2},
+		{Filename: "a.go", Line: 3, Column: 20},
+		{Filename: "a.go", Line: 12, Column: 2},
+		{Filename: "b.go", Line: 1, Column: 2},
+	}}
+	require.Equal(t, []string{"a.go:3", "a.go:12", "b.go:1"}, m.References())
+
+	m.Assets = []token.Position{
+		{Filename: "email/welcome.html", Line: 3},
+		{Filename: "email/welcome.html", Line: 3},
+		{Filename: "queries/users.sql", Line: 1},
+	}
+	require.Equal(t, []string{
+		"a.go:3", "a.go:12", "b.go:1",
+		"@email/welcome.html:3", "@queries/users.sql:1",
+	}, m.References())
+}
+
+func TestComparePos(t *testing.T) {
+	p := []token.Position{
+		{Filename: "b.go", Line: 1, Column: 1},
+		{Filename: "a.go", Line: 10, Column: 1},
+		{Filename: "a.go", Line: 2, Column: 5},
+		{Filename: "a.go", Line: 2, Column: 1},
+	}
+	slices.SortFunc(p, comparePos)
+	require.Equal(t, []token.Position{
+		{Filename: "a.go", Line: 2, Column: 1},
+		{Filename: "a.go", Line: 2, Column: 5},
+		{Filename: "a.go", Line: 10, Column: 1},
+		{Filename: "b.go", Line: 1, Column: 1},
+	}, p)
+	require.Zero(t, comparePos(p[0], p[0]))
+}
+
+func TestValidateQuantityArgument(t *testing.T) {
+	const stub = `package localize
+type SensitiveArg struct{ value any }
+func Sensitive(value any) SensitiveArg { return SensitiveArg{value} }
+`
+	const src = `package p
+
+import "github.com/romshark/localize"
+
+func use(any) {}
+
+func f(n int, s string) {
+	use(n)
+	use(localize.Sensitive(n))
+	use((localize.Sensitive(n)))
+	use(s)
+	use(localize.Sensitive(s))
+	use(localize.SensitiveArg{})
+}
+`
+	fset := token.NewFileSet()
+	stubFile, err := parser.ParseFile(fset, "localize.go", stub, 0)
+	require.NoError(t, err)
+	localize, err := new(types.Config).Check(
+		targetPackage, fset, []*ast.File{stubFile}, nil,
+	)
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{Importer: importerFunc(func(string) (*types.Package, error) {
+		return localize, nil
+	})}
+	_, err = conf.Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	// valid maps the line of every use call to whether its argument is valid.
+	valid := map[int]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		if ident, ok := call.Fun.(*ast.Ident); !ok || ident.Name != "use" {
+			return true
+		}
+		var errs []ErrorSrc
+		pos := fset.Position(call.Pos())
+		validateQuantityArgument(&errs, pos, call.Args[0], info)
+		for _, e := range errs {
+			require.ErrorIs(t, e.Err, ErrWrongQuantityArgType)
+		}
+		valid[pos.Line] = len(errs) == 0
+		return true
+	})
+	require.Equal(t, map[int]bool{
+		8: true, 9: true, 10: true, 11: false, 12: false, 13: false,
+	}, valid)
+}
+
+func TestParseOrdinalForms(t *testing.T) {
+	const stub = `package localize
+type Forms struct{ Zero, One, Two, Few, Many, Other string }
+type OrdinalForms struct{ Zero, One, Two, Few, Many, Other Forms }
+`
+	const src = `package p
+
+import "github.com/romshark/localize"
+
+var _ = localize.OrdinalForms{
+	One:   localize.Forms{One: "%dst of %d item", Other: "%dst of %d items"},
+	Other: localize.Forms{Other: "%dth of %d items"},
+}
+`
+	fset := token.NewFileSet()
+	stubFile, err := parser.ParseFile(fset, "localize.go", stub, 0)
+	require.NoError(t, err)
+	localize, err := new(types.Config).Check(
+		targetPackage, fset, []*ast.File{stubFile}, nil,
+	)
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	conf := types.Config{Importer: importerFunc(func(string) (*types.Package, error) {
+		return localize, nil
+	})}
+	_, err = conf.Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	cl := file.Decls[1].(*ast.GenDecl).Specs[0].(*ast.ValueSpec).Values[0]
+	var errs []ErrorSrc
+	l := parseOrdinalForms(fset, cl.(*ast.CompositeLit), info, &errs)
+	require.Empty(t, errs)
+	require.Len(t, l, 6)
+	require.Equal(t, cldr.CLDRPluralFormOne, l[1].Category)
+	require.Equal(t, "%dst of %d item", l[1].One)
+	require.Equal(t, "%dst of %d items", l[1].Other)
+	require.Equal(t, cldr.CLDRPluralFormOther, l[5].Category)
+	require.Equal(t, "%dth of %d items", l[5].Other)
+	for _, c := range []ordinalForms{l[0], l[2], l[3], l[4]} {
+		require.Zero(t, c.Forms, c.Category)
+	}
+}
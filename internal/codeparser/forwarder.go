@@ -0,0 +1,347 @@
+package codeparser
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// forwarder is a helper function forwarding its parameters
+// to a localize.Reader method, such as:
+//
+//	func T[R localize.Reader](r R, s string) string { return r.Text(s) }
+//
+// Messages passed to forwarders are extracted at the call sites of the forwarder.
+type forwarder struct {
+	funcType string
+
+	// argIndex is the index of the parameter forwarded as the message argument.
+	argIndex int
+
+	// quantityIndex is the index of the parameter forwarded as the quantity
+	// argument of Plural and PluralBlock. quantityIndex is -1 if the quantity
+	// isn't a parameter of the forwarder.
+	quantityIndex int
+
+	// more are the further messages forwarded by builtin helpers
+	// with multiple message parameters. The messages share the description
+	// comment of the call.
+	more []forwarder
+}
+
+// mailPackage is the import path of package localizemail.
+const mailPackage = targetPackage + "/localizemail"
+
+// richPackage is the import path of package localizerich.
+const richPackage = targetPackage + "/localizerich"
+
+// timePackage is the import path of package localizetime.
+const timePackage = targetPackage + "/localizetime"
+
+// regionPackage is the import path of package localizeregion.
+const regionPackage = targetPackage + "/localizeregion"
+
+// apierrPackage is the import path of package localizeapierr.
+const apierrPackage = targetPackage + "/localizeapierr"
+
+// builtinForwarders returns the forwarders declared in package localize,
+// package localizemail, package localizerich, package localizetime,
+// package localizeregion and package localizeapierr.
+func builtinForwarders() map[string]forwarder {
+	return map[string]forwarder{
+		targetPackage + ".MustText": {
+			funcType: FuncTypeText, argIndex: 1, quantityIndex: -1,
+		},
+		targetPackage + ".MustBlock": {
+			funcType: FuncTypeBlock, argIndex: 1, quantityIndex: -1,
+		},
+		targetPackage + ".MustPlural": {
+			funcType: FuncTypePlural, argIndex: 1, quantityIndex: 2,
+		},
+		targetPackage + ".MustPluralBlock": {
+			funcType: FuncTypePluralBlock, argIndex: 1, quantityIndex: 2,
+		},
+		"(*" + targetPackage + ".KeyedReader).TextKeyed": {
+			funcType: FuncTypeText, argIndex: 0, quantityIndex: -1,
+		},
+		"(*" + targetPackage + ".KeyedReader).BlockKeyed": {
+			funcType: FuncTypeBlock, argIndex: 0, quantityIndex: -1,
+		},
+		"(*" + targetPackage + ".KeyedReader).PluralKeyed": {
+			funcType: FuncTypePlural, argIndex: 0, quantityIndex: 1,
+		},
+		"(*" + targetPackage + ".KeyedReader).PluralBlockKeyed": {
+			funcType: FuncTypePluralBlock, argIndex: 0, quantityIndex: 1,
+		},
+		"(*" + targetPackage + ".KeyedReader).CardinalKeyed": {
+			funcType: FuncTypeCardinal, argIndex: 0, quantityIndex: 1,
+		},
+		"(" + targetPackage + ".Printer).Sprintf": {
+			funcType: FuncTypeText, argIndex: 0, quantityIndex: -1,
+		},
+		"(" + targetPackage + ".Printer).Fprintf": {
+			funcType: FuncTypeText, argIndex: 1, quantityIndex: -1,
+		},
+		"(" + targetPackage + ".Printer).Errorf": {
+			funcType: FuncTypeText, argIndex: 0, quantityIndex: -1,
+		},
+		targetPackage + ".Scheduled": {
+			funcType: FuncTypeText, argIndex: 2, quantityIndex: -1,
+			more: []forwarder{{
+				funcType: FuncTypeText, argIndex: 3, quantityIndex: -1,
+			}},
+		},
+		mailPackage + ".New": {
+			funcType: FuncTypeText, argIndex: 1, quantityIndex: -1,
+			more: []forwarder{{
+				funcType: FuncTypeBlock, argIndex: 2, quantityIndex: -1,
+			}},
+		},
+		"(" + mailPackage + ".Mail).WithPreheader": {
+			funcType: FuncTypeText, argIndex: 1, quantityIndex: -1,
+		},
+		richPackage + ".Text": {
+			funcType: FuncTypeText, argIndex: 2, quantityIndex: -1,
+		},
+		richPackage + ".Plural": {
+			funcType: FuncTypePlural, argIndex: 2, quantityIndex: 3,
+		},
+		richPackage + ".Format": {
+			funcType: FuncTypeText, argIndex: 1, quantityIndex: -1,
+		},
+		richPackage + ".HTML": {
+			funcType: FuncTypeText, argIndex: 1, quantityIndex: -1,
+		},
+		timePackage + ".Weekly": {
+			funcType: FuncTypeText, argIndex: 1, quantityIndex: -1,
+		},
+		regionPackage + ".Text": {
+			funcType: FuncTypeText, argIndex: 2, quantityIndex: -1,
+		},
+		regionPackage + ".Block": {
+			funcType: FuncTypeBlock, argIndex: 2, quantityIndex: -1,
+		},
+		regionPackage + ".For": {
+			funcType: FuncTypeText, argIndex: 1, quantityIndex: -1,
+		},
+		regionPackage + ".ForBlock": {
+			funcType: FuncTypeBlock, argIndex: 1, quantityIndex: -1,
+		},
+		apierrPackage + ".Define": {
+			funcType: FuncTypeText, argIndex: 2, quantityIndex: -1,
+			more: []forwarder{{
+				funcType: FuncTypeText, argIndex: 3, quantityIndex: -1,
+			}},
+		},
+	}
+}
+
+// readerMethod returns the name of the localize.Reader method called by call.
+// Calls on values of type localize.Reader, interfaces embedding it and
+// type parameters constrained by it are all considered Reader method calls.
+func readerMethod(info *types.Info, call *ast.CallExpr) (funcType string, ok bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok { // Not a function selector (method call).
+		return "", false
+	}
+
+	fn, ok := info.Uses[selector.Sel].(*types.Func)
+	if !ok { // Not the right package and type.
+		return "", false
+	}
+	fn = fn.Origin()
+
+	if fn.Pkg() == nil || fn.Pkg().Path() != targetPackage {
+		return "", false // Not from the target package.
+	}
+
+	methodType, ok := fn.Type().(*types.Signature)
+	if !ok {
+		return "", false
+	}
+
+	recv := methodType.Recv()
+	if recv == nil {
+		return "", false
+	}
+	if recv.Type().String() != targetType &&
+		!isConstrainedByReader(info.Selections[selector]) {
+		return "", false // Not the right receiver type.
+	}
+
+	return selector.Sel.Name, true
+}
+
+// isConstrainedByReader returns true if sel is a method selection on
+// a type parameter whose constraint embeds localize.Reader.
+func isConstrainedByReader(sel *types.Selection) bool {
+	if sel == nil {
+		return false
+	}
+	tp, ok := types.Unalias(sel.Recv()).(*types.TypeParam)
+	if !ok {
+		return false
+	}
+	iface, ok := tp.Constraint().Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+	for t := range iface.EmbeddedTypes() {
+		if types.Unalias(t).String() == targetType {
+			return true
+		}
+	}
+	return false
+}
+
+// forwarderKey returns the key identifying fn in a forwarder set.
+// Function objects can't be used as keys since packages loaded in separate
+// batches don't share type-checker objects.
+// Returns "" if fn is nil.
+func forwarderKey(fn *types.Func) string {
+	if fn == nil {
+		return ""
+	}
+	return fn.FullName()
+}
+
+// calledFunc returns the origin of the function or method called by call,
+// unwrapping explicit generic instantiations like `T[localize.Reader](r, "x")`.
+// Returns nil if call doesn't call a declared function.
+func calledFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+	var ident *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	default:
+		return nil
+	}
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok {
+		return nil
+	}
+	return fn.Origin()
+}
+
+// findForwarders adds all forwarders declared in pkgs to forwarders including
+// forwarders forwarding to other forwarders, which may also be forwarders
+// previously found in other packages. The returned set of calls contains all
+// forwarding calls inside forwarders, whose message arguments must not be
+// extracted as they're parameters.
+func findForwarders(
+	pkgs []*packages.Package, forwarders map[string]forwarder,
+) (forwardingCalls map[*ast.CallExpr]struct{}) {
+	forwardingCalls = map[*ast.CallExpr]struct{}{}
+
+	// Repeat until no new forwarders are found since a forwarder may
+	// be forwarding to another forwarder declared after it.
+	for found := true; found; {
+		found = false
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Syntax {
+				for _, decl := range file.Decls {
+					fd, ok := decl.(*ast.FuncDecl)
+					if !ok || fd.Body == nil {
+						continue
+					}
+					fn, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func)
+					if !ok {
+						continue
+					}
+					if _, ok := forwarders[forwarderKey(fn)]; ok {
+						continue
+					}
+					sig := fn.Type().(*types.Signature)
+					paramIndex := func(e ast.Expr) int {
+						ident, ok := ast.Unparen(e).(*ast.Ident)
+						if !ok {
+							return -1
+						}
+						v, ok := pkg.TypesInfo.Uses[ident].(*types.Var)
+						if !ok {
+							return -1
+						}
+						for i := range sig.Params().Len() {
+							if sig.Params().At(i) == v {
+								return i
+							}
+						}
+						return -1
+					}
+
+					ast.Inspect(fd.Body, func(node ast.Node) bool {
+						call, ok := node.(*ast.CallExpr)
+						if !ok {
+							return true
+						}
+						fw := forwarder{argIndex: -1, quantityIndex: -1}
+						if inner, ok := forwarders[forwarderKey(
+							calledFunc(pkg.TypesInfo, call),
+						)]; ok {
+							if inner.argIndex >= len(call.Args) {
+								return true
+							}
+							forward := func(inner forwarder) forwarder {
+								fw := forwarder{
+									funcType:      inner.funcType,
+									argIndex:      -1,
+									quantityIndex: -1,
+								}
+								if inner.argIndex < len(call.Args) {
+									fw.argIndex = paramIndex(call.Args[inner.argIndex])
+								}
+								if inner.quantityIndex >= 0 &&
+									inner.quantityIndex < len(call.Args) {
+									fw.quantityIndex = paramIndex(
+										call.Args[inner.quantityIndex],
+									)
+								}
+								return fw
+							}
+							fw = forward(inner)
+							for _, m := range inner.more {
+								// All messages must be parameters, otherwise
+								// the call is extracted inside the helper.
+								if fw.argIndex == -1 {
+									break
+								}
+								mfw := forward(m)
+								if mfw.argIndex == -1 {
+									fw.argIndex = -1
+									break
+								}
+								fw.more = append(fw.more, mfw)
+							}
+						} else if funcType, ok := readerMethod(
+							pkg.TypesInfo, call,
+						); ok && len(call.Args) > 0 {
+							fw.funcType = funcType
+							fw.argIndex = paramIndex(call.Args[0])
+							if len(call.Args) > 1 {
+								fw.quantityIndex = paramIndex(call.Args[1])
+							}
+						}
+						if fw.argIndex == -1 {
+							return true
+						}
+						forwarders[forwarderKey(fn)] = fw
+						forwardingCalls[call] = struct{}{}
+						found = true
+						return false
+					})
+				}
+			}
+		}
+	}
+	return forwardingCalls
+}
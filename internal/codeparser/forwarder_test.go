@@ -0,0 +1,75 @@
+package codeparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/romshark/localize/strfmt"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestForwarders(t *testing.T) {
+	u := testUnit(t, `package app
+
+import (
+	"fmt"
+
+	"github.com/romshark/localize"
+)
+
+func T[R localize.Reader](r R, s string) string { return r.Text(s) }
+
+func P[R localize.Reader](r R, f localize.Forms, n any) string {
+	return r.Plural(f, n)
+}
+
+func C(r localize.Reader, s string, n any) string { return r.Cardinal(s, n) }
+
+// Forwards to a forwarder declared before it.
+func Hello(r localize.Reader, s string) string { return T(r, s) }
+
+func use(r localize.Reader, n int) {
+	// Greeting.
+	_ = T(r, "Hello")
+	// Instantiated explicitly.
+	_ = T[localize.Reader](r, "Bye")
+	// Files.
+	_ = P(r, localize.Forms{One: "%d file", Other: "%d files"}, n)
+	// Items.
+	_ = C(r, "%d items", n)
+	// Welcome.
+	_ = Hello(r, "Welcome")
+	// Not extractable.
+	_ = T(r, fmt.Sprint(n))
+	// Wrong quantity.
+	_ = C(r, "%d boxes", "three")
+}
+`)
+	collection, _, _, srcErrs, err := Parse(
+		context.Background(), u.Dir, "", "", language.English,
+		strfmt.DedentPreserve, true, true, false, LoadOptions{Unit: u},
+	)
+	require.NoError(t, err)
+
+	byText := map[string]Msg{}
+	for m := range collection.Messages {
+		byText[m.Other] = m
+	}
+	require.Len(t, byText, 6)
+	for _, text := range []string{"Hello", "Bye", "Welcome"} {
+		require.Equal(t, FuncTypeText, byText[text].FuncType, text)
+	}
+	require.Equal(t, "%d file", byText["%d files"].One)
+	require.Equal(t, FuncTypePlural, byText["%d files"].FuncType)
+	require.Equal(t, FuncTypePlural, byText["%d items"].FuncType)
+	require.Contains(t, byText, "%d boxes")
+
+	// Non-constant arguments are rejected at the call site and
+	// the forwarded quantity is validated at the call site.
+	require.Len(t, srcErrs, 2)
+	require.ErrorIs(t, srcErrs[0].Err, ErrSourceArgType)
+	require.Equal(t, 32, srcErrs[0].Line)
+	require.ErrorIs(t, srcErrs[1].Err, ErrWrongQuantityArgType)
+	require.Equal(t, 34, srcErrs[1].Line)
+}
@@ -0,0 +1,119 @@
+package codeparser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// sentenceFragments returns the positions of message calls that are
+// concatenated with a preceding message call by node, such as:
+//
+//	r.Text("Welcome back,") + " " + r.Text("friend!")
+//	fmt.Sprint(r.Text("Welcome back,"), r.Text("friend!"))
+//
+// Two message calls are adjacent if only constants are between them.
+// Texts joined like this are sentence fragments most languages can't
+// translate correctly, they should be a single message with placeholders.
+// The nested operands of a concatenation are added to concatenated
+// to report every concatenation once.
+func sentenceFragments(
+	info *types.Info, forwarders map[string]forwarder, node ast.Node,
+	concatenated map[*ast.BinaryExpr]struct{},
+) []token.Pos {
+	switch n := node.(type) {
+	case *ast.BinaryExpr:
+		if n.Op != token.ADD {
+			return nil
+		}
+		if _, ok := concatenated[n]; ok {
+			return nil
+		}
+		var operands []ast.Expr
+		var flatten func(e ast.Expr)
+		flatten = func(e ast.Expr) {
+			if b, ok := ast.Unparen(e).(*ast.BinaryExpr); ok && b.Op == token.ADD {
+				concatenated[b] = struct{}{}
+				flatten(b.X)
+				flatten(b.Y)
+				return
+			}
+			operands = append(operands, e)
+		}
+		flatten(n)
+		return adjacentMsgCalls(info, forwarders, operands)
+	case *ast.CallExpr:
+		fn := calledFunc(info, n)
+		if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != "fmt" {
+			return nil
+		}
+		// first is the index of the first printed argument.
+		var first int
+		switch fn.Name() {
+		case "Sprint", "Sprintln", "Print", "Println":
+			first = 0
+		case "Fprint", "Fprintln", "Append", "Appendln":
+			first = 1 // Skip the writer or buffer.
+		case "Sprintf", "Printf", "Errorf":
+			first = 1
+		case "Fprintf", "Appendf":
+			first = 2
+		default:
+			return nil
+		}
+		if len(n.Args) < first {
+			return nil
+		}
+		if strings.HasSuffix(fn.Name(), "f") &&
+			info.Types[n.Args[first-1]].Value == nil {
+			// The format isn't constant, it may be a message itself.
+			return nil
+		}
+		args := n.Args[first:]
+		return adjacentMsgCalls(info, forwarders, args)
+	}
+	return nil
+}
+
+// adjacentMsgCalls returns the positions of message calls in exprs
+// preceded by another message call with only constants between them.
+func adjacentMsgCalls(
+	info *types.Info, forwarders map[string]forwarder, exprs []ast.Expr,
+) (l []token.Pos) {
+	afterMsg := false
+	for _, e := range exprs {
+		switch {
+		case isMsgCall(info, forwarders, e):
+			if afterMsg {
+				l = append(l, e.Pos())
+			}
+			afterMsg = true
+		case info.Types[e].Value == nil:
+			// Non-constant value between the calls.
+			afterMsg = false
+		}
+	}
+	return l
+}
+
+// isMsgCall returns true if e is a Reader method or forwarder call.
+func isMsgCall(info *types.Info, forwarders map[string]forwarder, e ast.Expr) bool {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	if _, ok := forwarders[forwarderKey(calledFunc(info, call))]; ok {
+		return true
+	}
+	funcType, ok := readerMethod(info, call)
+	if !ok {
+		return false
+	}
+	switch funcType {
+	case FuncTypeText, FuncTypeBlock, FuncTypePlural, FuncTypePluralBlock,
+		FuncTypeCardinal, FuncTypePluralRange, FuncTypePluralOrdinal:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package codeparser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentenceFragments(t *testing.T) {
+	const src = `package p
+
+import "fmt"
+
+const Sep = ", "
+
+func T(s string) string { return s }
+
+func f(name string) {
+	_ = T("Welcome back,") + " " + T("friend!")
+	_ = T("A") + Sep + T("B") + T("C")
+	_ = T("Name") + ": " + name + "; " + T("Age")
+	_ = (T("A") + "!") + T("B")
+	_ = fmt.Sprint(T("A"), " ", T("B"))
+	_ = fmt.Sprintf("%s %s", T("A"), T("B"))
+	_ = fmt.Sprintf(T("%s and %s"), T("A"), T("B"))
+	_ = fmt.Sprintf("%s %d %s", T("A"), 1+len(name), T("B"))
+	_ = fmt.Sprintln(name)
+	_ = "a" + "b"
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	forwarders := map[string]forwarder{
+		"p.T": {funcType: FuncTypeText, argIndex: 0, quantityIndex: -1},
+	}
+	concatenated := map[*ast.BinaryExpr]struct{}{}
+	var reported []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		for _, p := range sentenceFragments(info, forwarders, n, concatenated) {
+			pos := fset.Position(p)
+			reported = append(reported, fmt.Sprintf("%d:%d", pos.Line, pos.Column))
+		}
+		return true
+	})
+	require.Equal(t, []string{
+		"10:33", "11:21", "11:30", "13:23", "14:30", "15:35",
+	}, reported)
+}
@@ -0,0 +1,145 @@
+package codeparser
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+)
+
+// HashFunc is the hash function computing the hashes of messages,
+// which identify messages in catalogs.
+type HashFunc string
+
+const (
+	// HashXXHash64 is the 64-bit XXHash (see MessageHash), the default.
+	HashXXHash64 HashFunc = ""
+
+	// HashFNV128a is the 128-bit FNV-1a hash for very large code bases
+	// where collisions of 64-bit hashes become likely.
+	HashFNV128a HashFunc = "fnv128a"
+)
+
+// HashHeader is the header of catalogs whose message hashes were computed
+// by a hash function other than HashXXHash64.
+const HashHeader = "X-Localize-Hash"
+
+var ErrHashFunc = errors.New("unknown hash function")
+
+// ParseHashFunc parses "xxhash64" or "fnv128a".
+func ParseHashFunc(s string) (HashFunc, error) {
+	switch s {
+	case "", "xxhash64":
+		return HashXXHash64, nil
+	case string(HashFNV128a):
+		return HashFNV128a, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrHashFunc, s)
+}
+
+func (h HashFunc) String() string {
+	if h == HashXXHash64 {
+		return "xxhash64"
+	}
+	return string(h)
+}
+
+// Sum computes the hash of a message from its text,
+// which is form Other of plural messages, and its description.
+func (h HashFunc) Sum(text, description string) string {
+	if h != HashFNV128a {
+		return MessageHash(text, description)
+	}
+	f := fnv.New128a()
+	_, _ = f.Write(unsafeS2B(text))
+	// The separator prevents collisions of texts and descriptions
+	// with the same concatenation.
+	_, _ = f.Write([]byte{0})
+	_, _ = f.Write(unsafeS2B(description))
+	return hex.EncodeToString(f.Sum(nil))
+}
+
+// msgHash computes the hash of msg using h. The description is omitted
+// if descriptions are merged (see LoadOptions.MergeDescriptions).
+func msgHash(h HashFunc, msg Msg, withDescription bool) string {
+	text := msg.Other
+	if msg.Scope != "" {
+		text = localize.SectionID(msg.Scope, msg.Other)
+	}
+	if !withDescription {
+		return h.Sum(text, "")
+	}
+	return h.Sum(text, msg.Description)
+}
+
+// Rehash returns the hashes of all messages of c by their hashes computed
+// using from, such that catalogs created using another hash function
+// can be migrated to c.Hash (see MigrateHashes).
+// Messages imported from libraries are omitted.
+func (c *Collection) Rehash(from HashFunc) (newByOld map[string]string) {
+	newByOld = make(map[string]string, len(c.Messages))
+	for msg := range c.Messages {
+		if msgHash(c.Hash, msg, c.hashesDescriptions) != msg.Hash {
+			// Imported with the hash of the library.
+			continue
+		}
+		newByOld[msgHash(from, msg, c.hashesDescriptions)] = msg.Hash
+	}
+	return newByOld
+}
+
+// HashFuncOf returns the hash function of the message hashes of a catalog
+// with header h (see HashHeader).
+func HashFuncOf(h gettext.FileHead) (HashFunc, error) {
+	for _, x := range h.NonStandard {
+		if strings.EqualFold(x.Name, HashHeader) {
+			return ParseHashFunc(x.Value)
+		}
+	}
+	return HashXXHash64, nil
+}
+
+// setHashHeader sets HashHeader of h to f, or removes it if f is HashXXHash64.
+func setHashHeader(h *gettext.FileHead, f HashFunc) {
+	// The headers may be shared with merged catalogs (see mergePOFiles).
+	h.NonStandard = slices.Clone(h.NonStandard)
+	i := slices.IndexFunc(h.NonStandard, func(x gettext.XHeader) bool {
+		return strings.EqualFold(x.Name, HashHeader)
+	})
+	switch {
+	case f == HashXXHash64 && i != -1:
+		h.NonStandard = slices.Delete(h.NonStandard, i, i+1)
+	case f == HashXXHash64:
+	case i != -1:
+		h.NonStandard[i].Value = string(f)
+	default:
+		h.NonStandard = append(h.NonStandard, gettext.XHeader{
+			Name: HashHeader, Value: string(f),
+		})
+	}
+}
+
+// MigrateHashes replaces the message contexts of f found in newByOld
+// (see Collection.Rehash) by their new hashes and sets HashHeader of f to to.
+// Returns the number of replaced message contexts.
+func MigrateHashes(f *gettext.File, to HashFunc, newByOld map[string]string) int {
+	n := 0
+	for i := range f.Messages.List {
+		m := &f.Messages.List[i]
+		h, ok := newByOld[m.Msgctxt.Text.String()]
+		if !ok || h == m.Msgctxt.Text.String() {
+			continue
+		}
+		m.Msgctxt.Text = gettext.StringLiterals{
+			Lines: []gettext.StringLiteral{{Value: h}},
+		}
+		n++
+	}
+	setHashHeader(&f.Head, to)
+	return n
+}
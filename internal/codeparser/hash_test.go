@@ -0,0 +1,109 @@
+package codeparser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestParseHashFunc(t *testing.T) {
+	for _, tt := range []struct {
+		input  string
+		expect codeparser.HashFunc
+	}{
+		{"", codeparser.HashXXHash64},
+		{"xxhash64", codeparser.HashXXHash64},
+		{"fnv128a", codeparser.HashFNV128a},
+	} {
+		h, err := codeparser.ParseHashFunc(tt.input)
+		require.NoError(t, err)
+		require.Equal(t, tt.expect, h)
+	}
+	_, err := codeparser.ParseHashFunc("md5")
+	require.ErrorIs(t, err, codeparser.ErrHashFunc)
+
+	require.Equal(t, "xxhash64", codeparser.HashXXHash64.String())
+	require.Equal(t, "fnv128a", codeparser.HashFNV128a.String())
+}
+
+func TestHashFuncSum(t *testing.T) {
+	require.Equal(t, codeparser.MessageHash("Save", "Button."),
+		codeparser.HashXXHash64.Sum("Save", "Button."))
+
+	h := codeparser.HashFNV128a.Sum("Save", "Button.")
+	require.Len(t, h, 32)
+	require.Equal(t, h, codeparser.HashFNV128a.Sum("Save", "Button."))
+	require.NotEqual(t, h, codeparser.HashFNV128a.Sum("Save", ""))
+	// Text and description are separated.
+	require.NotEqual(t, codeparser.HashFNV128a.Sum("ab", ""),
+		codeparser.HashFNV128a.Sum("a", "b"))
+}
+
+func TestMigrateHashes(t *testing.T) {
+	save := codeparser.Msg{
+		Hash:     codeparser.HashFNV128a.Sum("Save", ""),
+		Other:    "Save",
+		FuncType: codeparser.FuncTypeText,
+	}
+	total := codeparser.Msg{
+		Hash: codeparser.HashFNV128a.Sum(
+			localize.SectionID("Checkout", "Total"), "",
+		),
+		Other:    "Total",
+		Scope:    "Checkout",
+		FuncType: codeparser.FuncTypeText,
+	}
+	imported := codeparser.Msg{
+		Hash: "retry", Other: "Retry", FuncType: codeparser.FuncTypeText,
+	}
+	c := &codeparser.Collection{
+		Locale: language.English,
+		Hash:   codeparser.HashFNV128a,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			save: {}, total: {}, imported: {},
+		},
+	}
+	newByOld := c.Rehash(codeparser.HashXXHash64)
+	require.Equal(t, map[string]string{
+		codeparser.MessageHash("Save", ""): save.Hash,
+		codeparser.MessageHash(
+			localize.SectionID("Checkout", "Total"), "",
+		): total.Hash,
+	}, newByOld)
+
+	po, err := gettext.NewDecoder().DecodePO("catalog.de.po", strings.NewReader(`msgid ""
+msgstr ""
+"Language: de\n"
+
+msgctxt "`+codeparser.MessageHash("Save", "")+`"
+msgid "Save"
+msgstr "Speichern"
+
+msgctxt "retry"
+msgid "Retry"
+msgstr "Wiederholen"
+`))
+	require.NoError(t, err)
+	h, err := codeparser.HashFuncOf(po.Head)
+	require.NoError(t, err)
+	require.Equal(t, codeparser.HashXXHash64, h)
+
+	n := codeparser.MigrateHashes(po.File, codeparser.HashFNV128a, newByOld)
+	require.Equal(t, 1, n)
+	require.Equal(t, save.Hash, po.Messages.List[0].Msgctxt.Text.String())
+	require.Equal(t, "retry", po.Messages.List[1].Msgctxt.Text.String())
+	h, err = codeparser.HashFuncOf(po.Head)
+	require.NoError(t, err)
+	require.Equal(t, codeparser.HashFNV128a, h)
+
+	// Migrating back removes the header.
+	n = codeparser.MigrateHashes(po.File, codeparser.HashXXHash64,
+		map[string]string{save.Hash: codeparser.MessageHash("Save", "")})
+	require.Equal(t, 1, n)
+	require.Empty(t, po.Head.NonStandard)
+}
@@ -0,0 +1,204 @@
+package codeparser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/edition"
+	"github.com/romshark/localize/internal/errcode"
+	"github.com/romshark/localize/internal/heading"
+	"github.com/romshark/localize/internal/msglock"
+	"github.com/romshark/localize/internal/msgseen"
+	"github.com/romshark/localize/internal/ordinal"
+	"github.com/romshark/localize/internal/protect"
+	"github.com/romshark/localize/internal/region"
+	"github.com/romshark/localize/internal/schedule"
+	"github.com/romshark/localize/internal/section"
+	"golang.org/x/text/language"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	ErrImportNoSource = errors.New("imported bundle has no source catalog")
+	ErrImportLocale   = errors.New(
+		"source locale of imported bundle doesn't match the source locale",
+	)
+)
+
+// Library is the source catalog of the bundle package of a library
+// imported into the collection of an application.
+type Library struct {
+	// ImportPath is the import path of the bundle package of the library.
+	ImportPath string
+
+	// ModulePath is the path of the module of the library.
+	ModulePath string
+
+	// Source is the source catalog of the library.
+	Source       POFile
+	SourceLocale language.Tag
+}
+
+// LoadLibrary loads the source catalog of the bundle package with importPath
+// as seen by the module in directory dir.
+// Returns ErrImportNoSource if the bundle package has no source catalog.
+func LoadLibrary(ctx context.Context, dir, importPath string) (*Library, error) {
+	pkgs, err := load(ctx, &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedModule,
+		Dir:  dir,
+	}, importPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 || pkgs[0].Dir == "" {
+		return nil, fmt.Errorf("package %q not found", importPath)
+	}
+	pkg := pkgs[0]
+	bundle, err := ParseBundleDir(pkg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Source == nil {
+		return nil, fmt.Errorf("%w: %s", ErrImportNoSource, importPath)
+	}
+	l := &Library{
+		ImportPath:   importPath,
+		ModulePath:   importPath,
+		Source:       *bundle.Source,
+		SourceLocale: bundle.SourceLocale,
+	}
+	if pkg.Module != nil {
+		l.ModulePath = pkg.Module.Path
+	}
+	return l, nil
+}
+
+// Import adds all messages of the source catalog of lib that aren't
+// in c yet to c and returns the number of added messages.
+// References are prefixed with the module path of lib.
+// Returns ErrImportLocale if the source locale of lib differs from c.Locale
+// and ErrHashCollision if a message of lib has the hash of a message
+// of c with a different text.
+func (c *Collection) Import(lib *Library) (added int, err error) {
+	if lib.SourceLocale != c.Locale {
+		return 0, fmt.Errorf("%w: %s (%s)", ErrImportLocale,
+			lib.ImportPath, lib.SourceLocale)
+	}
+	pluralForms, ok := cldr.ByTagOrBase(lib.SourceLocale)
+	if !ok {
+		return 0, fmt.Errorf("%w: %v", ErrUnsupportedLocale, lib.SourceLocale)
+	}
+	for i := range lib.Source.Messages.List {
+		m := &lib.Source.Messages.List[i]
+		if m.Obsolete {
+			continue
+		}
+		hash := m.Msgctxt.Text.String()
+		msg, meta := msgFromSourceMessage(pluralForms.CardinalForms, m, lib.ModulePath)
+		msg.Hash = hash
+		if existing, _, ok := c.ByHash(hash); ok {
+			if existing.Other != msg.Other {
+				return added, fmt.Errorf("%w: %s %s (%q and %q)", ErrHashCollision,
+					lib.ImportPath, hash, existing.Other, msg.Other)
+			}
+			continue
+		}
+		c.Messages[msg] = meta
+		added++
+	}
+	return added, nil
+}
+
+// msgFromSourceMessage is the inverse of MsgFromGettextMessage
+// for messages of a source catalog. Block and PluralBlock messages
+// are imported as Text and Plural messages since their texts are
+// already formatted.
+func msgFromSourceMessage(
+	cardinalForms []cldr.CLDRPluralForm, m *gettext.Message, refPrefix string,
+) (msg Msg, meta MsgMeta) {
+	var description []string
+	for _, cm := range m.Msgctxt.Comments.Text {
+		switch cm.Type {
+		case gettext.CommentTypeExtracted:
+			if !IsMetadataComment(cm.Value) {
+				description = append(description, cm.Value)
+			}
+		case gettext.CommentTypeReference:
+			if ref, ok := strings.CutPrefix(cm.Value, AssetRefPrefix); ok {
+				meta.Assets = append(meta.Assets, referencePosition(refPrefix, ref))
+				continue
+			}
+			meta.Pos = append(meta.Pos, referencePosition(refPrefix, cm.Value))
+		}
+	}
+	msg.Description = strings.Join(description, "\n")
+	meta.Editions = edition.Of(m)
+	meta.Regions = region.Of(m)
+	meta.ErrorCodes = errcode.Of(m)
+	meta.Schedule = schedule.Of(m)
+	meta.Heading = heading.Is(m)
+	meta.Protected = protect.Of(m)
+	meta.Section = section.Of(m)
+	meta.Ordinals = ordinal.Of(m)
+
+	if len(m.MsgidPlural.Text.Lines) == 0 {
+		msg.FuncType = FuncTypeText
+		msg.Other = m.Msgstr.Text.String()
+		return msg, meta
+	}
+	msg.FuncType = FuncTypePlural
+	indexed := [...]*gettext.Msgstr{
+		&m.Msgstr0, &m.Msgstr1, &m.Msgstr2, &m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+	}
+	for i, f := range cardinalForms {
+		if i >= len(indexed) {
+			break
+		}
+		text := indexed[i].Text.String()
+		switch f {
+		case cldr.CLDRPluralFormZero:
+			msg.Zero = text
+		case cldr.CLDRPluralFormOne:
+			msg.One = text
+		case cldr.CLDRPluralFormTwo:
+			msg.Two = text
+		case cldr.CLDRPluralFormFew:
+			msg.Few = text
+		case cldr.CLDRPluralFormMany:
+			msg.Many = text
+		case cldr.CLDRPluralFormOther:
+			msg.Other = text
+		}
+	}
+	return msg, meta
+}
+
+// referencePosition parses reference comments like "/button.go:12"
+// prefixing the file name with prefix.
+func referencePosition(prefix, ref string) token.Position {
+	file, line := ref, 0
+	if i := strings.LastIndexByte(ref, ':'); i != -1 {
+		file = ref[:i]
+		line, _ = strconv.Atoi(ref[i+1:])
+	}
+	return token.Position{
+		Filename: prefix + "/" + strings.TrimPrefix(file, "/"),
+		Line:     line,
+	}
+}
+
+// IsMetadataComment returns true for extracted comments added by the
+// generator, which aren't part of the description.
+func IsMetadataComment(s string) bool {
+	return strings.HasPrefix(s, msglock.CommentPrefix) ||
+		strings.HasPrefix(s, msgseen.FirstSeenPrefix) ||
+		strings.HasPrefix(s, msgseen.LastSeenPrefix) ||
+		strings.HasPrefix(s, protect.CommentPrefix) ||
+		strings.HasPrefix(s, section.CommentPrefix)
+}
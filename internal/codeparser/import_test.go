@@ -0,0 +1,105 @@
+package codeparser_test
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestCollectionImport(t *testing.T) {
+	src, err := gettext.NewDecoder().DecodePO("source.en.po", strings.NewReader(`msgid ""
+msgstr ""
+"Language: en\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+#: /widgets.go:7
+#. Label of the retry button.
+#. X-Message-ID: 4
+msgctxt "retry"
+msgid "Retry"
+msgstr "Retry"
+
+#: /widgets.go:9
+#: /form.go:3
+#: @email/attempts.html:4
+msgctxt "attempts"
+msgid "%d attempt failed"
+msgid_plural "%d attempts failed"
+msgstr[0] "%d attempt failed"
+msgstr[1] "%d attempts failed"
+
+#: /app.go:1
+msgctxt "existing"
+msgid "Existing"
+msgstr "Existing"
+
+#~ msgctxt "old"
+#~ msgid "Old"
+#~ msgstr "Old"
+`))
+	require.NoError(t, err)
+
+	existing := codeparser.Msg{
+		Hash: "existing", Other: "Existing", FuncType: codeparser.FuncTypeText,
+	}
+	c := &codeparser.Collection{
+		Locale:   language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{existing: {}},
+	}
+	lib := &codeparser.Library{
+		ImportPath:   "example.com/widgets/localizebundle",
+		ModulePath:   "example.com/widgets",
+		Source:       codeparser.POFile{FilePO: src},
+		SourceLocale: language.English,
+	}
+	added, err := c.Import(lib)
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+	require.Equal(t, map[codeparser.Msg]codeparser.MsgMeta{
+		existing: {},
+		{
+			Hash:        "retry",
+			Description: "Label of the retry button.",
+			Other:       "Retry",
+			FuncType:    codeparser.FuncTypeText,
+		}: {Pos: []token.Position{
+			{Filename: "example.com/widgets/widgets.go", Line: 7},
+		}},
+		{
+			Hash:     "attempts",
+			One:      "%d attempt failed",
+			Other:    "%d attempts failed",
+			FuncType: codeparser.FuncTypePlural,
+		}: {Pos: []token.Position{
+			{Filename: "example.com/widgets/widgets.go", Line: 9},
+			{Filename: "example.com/widgets/form.go", Line: 3},
+		}, Assets: []token.Position{
+			{Filename: "example.com/widgets/email/attempts.html", Line: 4},
+		}},
+	}, c.Messages)
+
+	_, _, ok := c.ByHash("retry")
+	require.True(t, ok)
+
+	lib.SourceLocale = language.German
+	_, err = c.Import(lib)
+	require.ErrorIs(t, err, codeparser.ErrImportLocale)
+
+	lib.SourceLocale = language.English
+	c = &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{{
+			Hash: "retry", Other: "Try again", FuncType: codeparser.FuncTypeText,
+		}: {}},
+	}
+	_, err = c.Import(lib)
+	require.ErrorIs(t, err, codeparser.ErrHashCollision)
+}
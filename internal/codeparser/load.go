@@ -0,0 +1,197 @@
+package codeparser
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"maps"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const loadModeSyntax = packages.NeedFiles |
+	packages.NeedSyntax |
+	packages.NeedTypes |
+	packages.NeedTypesInfo |
+	packages.NeedName |
+	packages.NeedModule
+
+// loadAll loads all packages matching pathPattern
+// including all of their dependencies from source at once.
+func loadAll(
+	ctx context.Context, fileset *token.FileSet, pathPattern string,
+	profile *Profile, fn func([]*packages.Package),
+) error {
+	pkgs, err := profile.loadOrProfile(ctx, &packages.Config{
+		Mode: loadModeSyntax | packages.NeedDeps,
+		Fset: fileset,
+	}, pathPattern+"/...")
+	if err != nil {
+		return err
+	}
+	fn(pkgs)
+	return nil
+}
+
+// loadBatched loads the packages matching pathPattern in batches
+// with dependencies ordered before their importers such that forwarders
+// are always found before their call sites.
+// Only the ASTs of the packages of the current batch are processed,
+// the packages of previous batches are no longer referenced.
+// onIndex is called with all packages before any batch is loaded,
+// these packages only provide names, files and module information.
+func loadBatched(
+	ctx context.Context, fileset *token.FileSet, pathPattern string, opts LoadOptions,
+	quiet, verbose bool,
+	onIndex, onBatch func([]*packages.Package),
+) error {
+	index, err := opts.Profile.loadOrProfile(ctx, &packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedImports |
+			packages.NeedModule,
+	}, pathPattern+"/...")
+	if err != nil {
+		return err
+	}
+	onIndex(index)
+
+	if opts.OnlyImporters {
+		index = importers(index)
+	}
+	index = sortByImports(index)
+
+	if opts.MaxMemory > 0 {
+		debug.SetMemoryLimit(opts.MaxMemory)
+	}
+
+	var batch []string
+	var batchMemory int64
+	flush := func() error {
+		if len(batch) < 1 {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !quiet && verbose {
+			fmt.Fprintf(os.Stderr, "loading batch of %d packages\n", len(batch))
+		}
+		pkgs, err := opts.Profile.loadOrProfile(ctx, &packages.Config{
+			// Dependencies are type-checked from source as well since
+			// export data produced by newer toolchains can't necessarily
+			// be read, yet only the dependencies of a single batch
+			// are retained at a time.
+			Mode: loadModeSyntax | packages.NeedDeps,
+			Fset: fileset,
+		}, batch...)
+		if err != nil {
+			return err
+		}
+		// Process in the order of imports since the loader doesn't preserve it.
+		slices.SortFunc(pkgs, func(a, b *packages.Package) int {
+			return slices.Index(batch, a.PkgPath) - slices.Index(batch, b.PkgPath)
+		})
+		onBatch(pkgs)
+		batch, batchMemory = batch[:0], 0
+		if opts.MaxMemory > 0 {
+			// Release the ASTs of the processed batch before loading the next.
+			runtime.GC()
+		}
+		return nil
+	}
+
+	for _, pkg := range index {
+		memory := estimateMemory(pkg)
+		if len(batch) > 0 &&
+			(opts.BatchSize > 0 && len(batch) >= opts.BatchSize ||
+				opts.MaxMemory > 0 && batchMemory+memory > opts.MaxMemory) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		batch = append(batch, pkg.PkgPath)
+		batchMemory += memory
+	}
+	return flush()
+}
+
+// load calls packages.Load with ctx.
+// The loader doesn't wrap the context error when go list is interrupted,
+// load therefore returns ctx.Err() if ctx was canceled.
+func load(
+	ctx context.Context, conf *packages.Config, patterns ...string,
+) ([]*packages.Package, error) {
+	conf.Context = ctx
+	pkgs, err := packages.Load(conf, patterns...)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	return pkgs, err
+}
+
+// estimateMemory returns the estimated memory required for
+// the AST and type information of pkg.
+func estimateMemory(pkg *packages.Package) (bytes int64) {
+	for _, f := range pkg.GoFiles {
+		if fi, err := os.Stat(f); err == nil {
+			bytes += fi.Size()
+		}
+	}
+	return bytes * estimatedBytesPerSourceByte
+}
+
+// importers returns all packages of pkgs that directly or transitively
+// import the target package.
+func importers(pkgs []*packages.Package) []*packages.Package {
+	imports := map[string]bool{targetPackage: true}
+	for found := true; found; {
+		found = false
+		for _, pkg := range pkgs {
+			if imports[pkg.PkgPath] {
+				continue
+			}
+			for path := range pkg.Imports {
+				if imports[path] {
+					imports[pkg.PkgPath], found = true, true
+					break
+				}
+			}
+		}
+	}
+	return slices.DeleteFunc(slices.Clone(pkgs), func(p *packages.Package) bool {
+		return !imports[p.PkgPath]
+	})
+}
+
+// sortByImports returns pkgs ordered such that all packages come after
+// the packages of pkgs they import.
+func sortByImports(pkgs []*packages.Package) []*packages.Package {
+	byPath := make(map[string]*packages.Package, len(pkgs))
+	for _, p := range pkgs {
+		byPath[p.PkgPath] = p
+	}
+	sorted := make([]*packages.Package, 0, len(pkgs))
+	visited := make(map[string]bool, len(pkgs))
+	var visit func(p *packages.Package)
+	visit = func(p *packages.Package) {
+		if visited[p.PkgPath] {
+			return
+		}
+		visited[p.PkgPath] = true
+		for _, path := range slices.Sorted(maps.Keys(p.Imports)) {
+			if dep, ok := byPath[path]; ok {
+				visit(dep)
+			}
+		}
+		sorted = append(sorted, p)
+	}
+	for _, p := range pkgs {
+		visit(p)
+	}
+	return sorted
+}
@@ -0,0 +1,104 @@
+package codeparser
+
+import (
+	"context"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func testPackages(imports map[string][]string) []*packages.Package {
+	byPath := map[string]*packages.Package{}
+	get := func(path string) *packages.Package {
+		if p, ok := byPath[path]; ok {
+			return p
+		}
+		p := &packages.Package{PkgPath: path, Imports: map[string]*packages.Package{}}
+		byPath[path] = p
+		return p
+	}
+	var pkgs []*packages.Package
+	for _, path := range []string{"a", "b", "c", "d", "e"} {
+		deps, ok := imports[path]
+		if !ok {
+			continue
+		}
+		p := get(path)
+		for _, d := range deps {
+			p.Imports[d] = get(d)
+		}
+		pkgs = append(pkgs, p)
+	}
+	return pkgs
+}
+
+func pkgPaths(pkgs []*packages.Package) []string {
+	paths := make([]string, len(pkgs))
+	for i, p := range pkgs {
+		paths[i] = p.PkgPath
+	}
+	return paths
+}
+
+func TestSortByImports(t *testing.T) {
+	pkgs := testPackages(map[string][]string{
+		"a": {"b", "fmt"},
+		"b": {"c", "d"},
+		"c": {"d"},
+		"d": nil,
+		"e": {"a"},
+	})
+	require.Equal(t, []string{"d", "c", "b", "a", "e"}, pkgPaths(sortByImports(pkgs)))
+}
+
+func TestImporters(t *testing.T) {
+	pkgs := testPackages(map[string][]string{
+		"a": {"b"},
+		"b": {targetPackage},
+		"c": {"fmt"},
+		"d": {"a", "c"},
+		"e": {"c"},
+	})
+	require.Equal(t, []string{"a", "b", "d"}, pkgPaths(importers(pkgs)))
+}
+
+func TestLoadAllCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	err := loadAll(ctx, token.NewFileSet(), ".", nil, func([]*packages.Package) {
+		called = true
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, called)
+}
+
+func TestLoadProfile(t *testing.T) {
+	p := NewProfile()
+	var pkgs []*packages.Package
+	err := loadAll(context.Background(), token.NewFileSet(), "../fmtplaceholder", p,
+		func(l []*packages.Package) { pkgs = l })
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	require.Positive(t, p.Load)
+
+	byPath := map[string]PackageProfile{}
+	for _, pp := range p.Packages() {
+		byPath[pp.PkgPath] = pp
+	}
+	for _, path := range []string{pkgs[0].PkgPath, "regexp"} {
+		pp, ok := byPath[path]
+		require.True(t, ok, path)
+		require.Positive(t, pp.Files, path)
+		require.Positive(t, pp.Parse, path)
+		require.Positive(t, pp.TypeCheck, path)
+		require.Zero(t, pp.Inspect, path)
+	}
+
+	l := p.Packages()
+	for i := 1; i < len(l); i++ {
+		require.GreaterOrEqual(t, l[i-1].Total(), l[i].Total())
+	}
+}
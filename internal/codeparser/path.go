@@ -0,0 +1,52 @@
+package codeparser
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// caseInsensitivePaths is true on file systems where paths are
+// case-insensitive by default, such that "C:\Project" and "c:\project"
+// refer to the same directory.
+var caseInsensitivePaths = runtime.GOOS == "windows"
+
+// cutPathPrefix returns s without prefix and true if s starts with prefix,
+// otherwise returns s and false. Paths are compared case-insensitively
+// if caseInsensitive is true.
+func cutPathPrefix(s, prefix string, caseInsensitive bool) (string, bool) {
+	if len(s) < len(prefix) {
+		return s, false
+	}
+	if caseInsensitive {
+		if !strings.EqualFold(s[:len(prefix)], prefix) {
+			return s, false
+		}
+	} else if s[:len(prefix)] != prefix {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// matchDirPattern returns true if the absolute directory dir matches
+// the directory pattern relative to the absolute directory base.
+// Patterns ending with "/..." also match all subdirectories,
+// like "./plugins/..." matching "plugins" and "plugins/auth".
+func matchDirPattern(base, pattern, dir string) bool {
+	pattern, recursive := strings.CutSuffix(filepath.ToSlash(pattern), "...")
+	if recursive && pattern != "" && !strings.HasSuffix(pattern, "/") {
+		// Patterns like "./plug..." aren't supported.
+		return false
+	}
+	prefix := filepath.Join(base, filepath.FromSlash(pattern))
+	rest, ok := cutPathPrefix(dir, prefix, caseInsensitivePaths)
+	switch {
+	case !ok:
+		return false
+	case rest == "":
+		return true
+	}
+	return recursive && (rest[0] == os.PathSeparator ||
+		strings.HasSuffix(prefix, string(os.PathSeparator)))
+}
@@ -0,0 +1,93 @@
+package codeparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCutPathPrefix(t *testing.T) {
+	f := func(t *testing.T, s, prefix string, caseInsensitive bool, expect string) {
+		t.Helper()
+		actual, ok := cutPathPrefix(s, prefix, caseInsensitive)
+		require.True(t, ok)
+		require.Equal(t, expect, actual)
+	}
+	f(t, "/home/user/project/main.go", "/home/user/project", false, "/main.go")
+	f(t, `C:\Project\main.go`, `C:\Project`, false, `\main.go`)
+	f(t, `C:\Project\main.go`, `c:\project`, true, `\main.go`)
+	f(t, `C:\PROJECT\pkg\main.go`, `c:\Project`, true, `\pkg\main.go`)
+	f(t, "/same", "/same", false, "")
+
+	fNot := func(t *testing.T, s, prefix string, caseInsensitive bool) {
+		t.Helper()
+		actual, ok := cutPathPrefix(s, prefix, caseInsensitive)
+		require.False(t, ok)
+		require.Equal(t, s, actual)
+	}
+	fNot(t, `C:\Project\main.go`, `c:\project`, false)
+	fNot(t, "/home/user/project", "/home/user/project/main.go", false)
+	fNot(t, "/home/other/main.go", "/home/user", true)
+}
+
+func TestMatchDirPattern(t *testing.T) {
+	f := func(t *testing.T, pattern, dir string, expect bool) {
+		t.Helper()
+		require.Equal(t, expect, matchDirPattern("/project", pattern, dir))
+	}
+	f(t, "./plugins/...", "/project/plugins", true)
+	f(t, "./plugins/...", "/project/plugins/auth", true)
+	f(t, "plugins/...", "/project/plugins/auth/v2", true)
+	f(t, "./plugins/...", "/project/pluginsx", false)
+	f(t, "./plugins/...", "/project/main", false)
+	f(t, "./plugins/auth", "/project/plugins/auth", true)
+	f(t, "./plugins/auth", "/project/plugins/auth/v2", false)
+	f(t, "./...", "/project/any/pkg", true)
+	f(t, "...", "/project", true)
+	f(t, ".", "/project", true)
+	f(t, ".", "/project/sub", false)
+	f(t, "./plug...", "/project/plugins", false)
+}
+
+func TestIsPkgLocalizeBundle(t *testing.T) {
+	f := func(t *testing.T, bundleDir, importPath string, pkg *packages.Package, expect bool) {
+		t.Helper()
+		require.Equal(t, expect, isPkgLocalizeBundle(bundleDir, importPath, pkg))
+	}
+	pkg := &packages.Package{
+		PkgPath: "example.com/app/localizebundle", Dir: "/m/localizebundle",
+	}
+	f(t, "/m/localizebundle", "", pkg, true)
+	f(t, "/m/cmd/localizebundle", "", pkg, false)
+	f(t, "/m", "", pkg, false)
+	f(t, "/m/localize", "", pkg, false)
+	f(t, "/other", "example.com/app/localizebundle", pkg, true)
+	f(t, "/m/localizebundle", "example.com/other/localizebundle", pkg, false)
+
+	// Packages in nested directories ending with the bundle path aren't bundles.
+	nested := &packages.Package{
+		PkgPath: "example.com/app/cmd/localizebundle", Dir: "/m/cmd/localizebundle",
+	}
+	f(t, "/m/localizebundle", "", nested, false)
+
+	// Bundles in nested modules are identified by their import path
+	// regardless of the directory the bundle path refers to.
+	inModule := &packages.Package{
+		PkgPath: "example.com/app/tools/localizebundle",
+		Dir:     "/m/tools/localizebundle",
+	}
+	f(t, "/m/localizebundle", "example.com/app/tools/localizebundle", inModule, true)
+	f(t, "/m/tools/localizebundle", "example.com/app/localizebundle", inModule, false)
+	f(t, "/m/tools/localizebundle", "example.com/app/tools", inModule, false)
+
+	// Bundles in internal directories are identified by their full import path.
+	internal := &packages.Package{
+		PkgPath: "example.com/app/internal/localizebundle",
+		Dir:     "/m/internal/localizebundle",
+	}
+	f(t, "/m/internal/localizebundle",
+		"example.com/app/internal/localizebundle", internal, true)
+	f(t, "/m/internal/localizebundle", "example.com/app/localizebundle", internal, false)
+	f(t, "/m/internal/localizebundle", "internal/localizebundle", internal, false)
+}
@@ -0,0 +1,190 @@
+package codeparser
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Profile collects the time Parse spends on each package when set
+// in LoadOptions. The loader parses and type-checks packages concurrently
+// without reporting the time of either, so the files are timed while parsed
+// and each package is type-checked once more after loading to time it.
+// Profiling therefore slows Parse down.
+type Profile struct {
+	// Load is the total time spent loading packages, which includes
+	// listing, parsing and type-checking but not the repeated type-checks.
+	Load time.Duration
+
+	lock  sync.Mutex
+	byDir map[string]*PackageProfile
+}
+
+// PackageProfile is the time spent on a single package.
+type PackageProfile struct {
+	PkgPath string
+	Files   int
+
+	Parse     time.Duration
+	TypeCheck time.Duration
+
+	// Inspect is the time spent extracting messages,
+	// which is zero for dependencies messages aren't extracted from.
+	Inspect time.Duration
+}
+
+// Total returns the total time spent on p.
+func (p PackageProfile) Total() time.Duration {
+	return p.Parse + p.TypeCheck + p.Inspect
+}
+
+// NewProfile returns a new empty profile.
+func NewProfile() *Profile {
+	return &Profile{byDir: map[string]*PackageProfile{}}
+}
+
+// Packages returns the profiles of all packages loaded
+// in descending order of their total time.
+func (p *Profile) Packages() []PackageProfile {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	l := make([]PackageProfile, 0, len(p.byDir))
+	for _, pp := range p.byDir {
+		if pp.PkgPath != "" {
+			l = append(l, *pp)
+		}
+	}
+	slices.SortFunc(l, func(a, b PackageProfile) int {
+		if c := cmp.Compare(b.Total(), a.Total()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.PkgPath, b.PkgPath)
+	})
+	return l
+}
+
+// dir returns the profile of the package in directory dir.
+// p.lock must be held.
+func (p *Profile) dir(dir string) *PackageProfile {
+	pp, ok := p.byDir[dir]
+	if !ok {
+		pp = &PackageProfile{}
+		p.byDir[dir] = pp
+	}
+	return pp
+}
+
+// parseFile is packages.Config.ParseFile timing each file.
+// Files are attributed to packages by their directory.
+func (p *Profile) parseFile(
+	fset *token.FileSet, filename string, src []byte,
+) (*ast.File, error) {
+	start := time.Now()
+	f, err := parser.ParseFile(fset, filename, src,
+		parser.AllErrors|parser.ParseComments)
+	d := time.Since(start)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	pp := p.dir(filepath.Dir(filename))
+	pp.Files++
+	pp.Parse += d
+	return f, err
+}
+
+// load calls load and adds the time spent to p.Load, then type-checks
+// all packages with syntax once more to time them.
+func (p *Profile) load(
+	ctx context.Context, conf *packages.Config, patterns ...string,
+) ([]*packages.Package, error) {
+	conf.ParseFile = p.parseFile
+	// Imports are required to visit and type-check the dependencies.
+	conf.Mode |= packages.NeedImports
+	start := time.Now()
+	pkgs, err := load(ctx, conf, patterns...)
+	p.Load += time.Since(start)
+	if err != nil {
+		return pkgs, err
+	}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if pkg.Types == nil || len(pkg.Syntax) < 1 {
+			return
+		}
+		d := typeCheckDuration(conf.Fset, pkg)
+
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		pp := p.dir(pkg.Dir)
+		pp.PkgPath = pkg.PkgPath
+		pp.TypeCheck += d
+	})
+	return pkgs, nil
+}
+
+// loadOrProfile calls load or, if p isn't nil, p.load.
+func (p *Profile) loadOrProfile(
+	ctx context.Context, conf *packages.Config, patterns ...string,
+) ([]*packages.Package, error) {
+	if p == nil {
+		return load(ctx, conf, patterns...)
+	}
+	return p.load(ctx, conf, patterns...)
+}
+
+// inspect adds the time since start spent extracting messages from pkg.
+func (p *Profile) inspect(pkg *packages.Package, start time.Time) {
+	d := time.Since(start)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	pp := p.dir(pkg.Dir)
+	pp.PkgPath = pkg.PkgPath
+	pp.Inspect += d
+}
+
+// typeCheckDuration returns the time it takes to type-check pkg
+// against the already type-checked packages it imports.
+func typeCheckDuration(fset *token.FileSet, pkg *packages.Package) time.Duration {
+	conf := &types.Config{
+		Importer: profileImporter(func(path string) (*types.Package, error) {
+			if path == "unsafe" {
+				return types.Unsafe, nil
+			}
+			if imp, ok := pkg.Imports[path]; ok && imp.Types != nil {
+				return imp.Types, nil
+			}
+			return nil, fmt.Errorf("package %q not loaded", path)
+		}),
+		Error:       func(error) {}, // Errors are reported by the loader.
+		FakeImportC: true,
+	}
+	if pkg.Module != nil && pkg.Module.GoVersion != "" {
+		conf.GoVersion = "go" + pkg.Module.GoVersion
+	}
+	info := &types.Info{
+		Types:        map[ast.Expr]types.TypeAndValue{},
+		Instances:    map[*ast.Ident]types.Instance{},
+		Defs:         map[*ast.Ident]types.Object{},
+		Uses:         map[*ast.Ident]types.Object{},
+		Implicits:    map[ast.Node]types.Object{},
+		Selections:   map[*ast.SelectorExpr]*types.Selection{},
+		Scopes:       map[ast.Node]*types.Scope{},
+		FileVersions: map[*ast.File]string{},
+	}
+	start := time.Now()
+	_, _ = conf.Check(pkg.PkgPath, fset, pkg.Syntax, info)
+	return time.Since(start)
+}
+
+type profileImporter func(path string) (*types.Package, error)
+
+func (f profileImporter) Import(path string) (*types.Package, error) { return f(path) }
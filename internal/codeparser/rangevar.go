@@ -0,0 +1,96 @@
+package codeparser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+)
+
+// rangeVar is a key or value variable of a range statement, such as s in:
+//
+//	for _, s := range []string{"A", "B"} { l.Text(s) }
+//
+// Messages passed as range variables are extracted from the elements of
+// the composite literal ranged over.
+type rangeVar struct {
+	stmt *ast.RangeStmt
+
+	// key is true for the key variable and false for the value variable.
+	key bool
+}
+
+// rangeVarsOf returns the key and value variables of all range statements
+// in file by their type object.
+func rangeVarsOf(file *ast.File, info *types.Info) map[types.Object]rangeVar {
+	m := map[types.Object]rangeVar{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		r, ok := node.(*ast.RangeStmt)
+		if !ok || r.Tok != token.DEFINE {
+			return true
+		}
+		if k, ok := r.Key.(*ast.Ident); ok {
+			if o := info.Defs[k]; o != nil {
+				m[o] = rangeVar{stmt: r, key: true}
+			}
+		}
+		if v, ok := r.Value.(*ast.Ident); ok {
+			if o := info.Defs[v]; o != nil {
+				m[o] = rangeVar{stmt: r}
+			}
+		}
+		return true
+	})
+	return m
+}
+
+// elements returns the expressions v takes on, which are the elements of
+// a slice or array literal or the keys or values of a map literal.
+// Returns an error suggesting a refactor if v doesn't range over
+// a composite literal of string constants.
+func (v rangeVar) elements(info *types.Info) ([]ast.Expr, error) {
+	unsupported := func(reason string) error {
+		return fmt.Errorf(
+			"%w: range variable over %s (range over a composite literal "+
+				"of string constants or call the Reader method for each text)",
+			ErrSourceArgType, reason,
+		)
+	}
+	cl, ok := ast.Unparen(v.stmt.X).(*ast.CompositeLit)
+	if !ok {
+		return nil, unsupported(typeKind(v.stmt.X))
+	}
+	_, isMap := info.Types[cl].Type.Underlying().(*types.Map)
+	if v.key && !isMap {
+		return nil, unsupported("index")
+	}
+	l := make([]ast.Expr, len(cl.Elts))
+	for i, e := range cl.Elts {
+		if kv, ok := e.(*ast.KeyValueExpr); ok {
+			e = kv.Value
+			if v.key {
+				e = kv.Key
+			}
+		}
+		if _, ok := literalText(e, info); !ok {
+			return nil, unsupported("non-constant element " + typeKind(e))
+		}
+		l[i] = e
+	}
+	return l, nil
+}
+
+// literalText returns the text of string literal or constant e.
+// Literals are returned quoted (see mustFmtTemplate).
+func literalText(e ast.Expr, info *types.Info) (text string, ok bool) {
+	if b, ok := e.(*ast.BasicLit); ok {
+		return b.Value, info.Types[b].Value != nil &&
+			info.Types[b].Value.Kind() == constant.String
+	}
+	v := info.Types[e].Value
+	if v == nil || v.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(v), true
+}
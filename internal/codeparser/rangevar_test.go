@@ -0,0 +1,84 @@
+package codeparser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeVarElements(t *testing.T) {
+	const src = `package p
+
+const C = "C"
+
+func f(dynamic []string) {
+	for _, v := range []string{"A", C, ` + "`B`" + `} { _ = v }
+	for k, v := range map[string]string{"K1": "V1", "K2": C} { _, _ = k, v }
+	for i, v := range [...]string{1: "X"} { _, _ = i, v }
+	for _, v := range dynamic { _ = v }
+	for _, v := range []string{"A", dynamic[0]} { _ = v }
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Defs:  map[*ast.Ident]types.Object{},
+	}
+	_, err = new(types.Config).Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	vars := rangeVarsOf(file, info)
+	require.Len(t, vars, 10)
+
+	// texts returns the texts of the elements of the range variable
+	// of the n-th range statement.
+	texts := func(n int, key bool) (l []string, err error) {
+		for _, v := range vars {
+			if fset.Position(v.stmt.Pos()).Line != 6+n || v.key != key {
+				continue
+			}
+			elements, err := v.elements(info)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range elements {
+				s, ok := literalText(e, info)
+				require.True(t, ok)
+				l = append(l, s)
+			}
+			return l, nil
+		}
+		t.Fatalf("range statement %d not found", n)
+		return nil, nil
+	}
+
+	l, err := texts(0, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{`"A"`, "C", "`B`"}, l)
+
+	l, err = texts(1, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{`"K1"`, `"K2"`}, l)
+	l, err = texts(1, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{`"V1"`, "C"}, l)
+
+	l, err = texts(2, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{`"X"`}, l)
+	_, err = texts(2, true)
+	require.ErrorIs(t, err, ErrSourceArgType)
+
+	_, err = texts(3, false)
+	require.ErrorIs(t, err, ErrSourceArgType)
+	require.ErrorContains(t, err, "range over a composite literal")
+
+	_, err = texts(4, false)
+	require.ErrorIs(t, err, ErrSourceArgType)
+}
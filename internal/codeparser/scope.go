@@ -0,0 +1,146 @@
+package codeparser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/internal/section"
+)
+
+const (
+	methodSection      = "Section"
+	methodWithRegister = "WithRegister"
+)
+
+// maxScopeDepth limits the number of variables followed when resolving
+// the section of a reader.
+const maxScopeDepth = 32
+
+// readerVarsOf returns the expressions defining the variables of file
+// by their type object, such as the call defining c in:
+//
+//	c := r.Section("Checkout")
+//
+// Messages of readers returned by Reader.Section belong to their section
+// (see scopeOf), which is resolved through such variables.
+func readerVarsOf(file *ast.File, info *types.Info) map[types.Object]ast.Expr {
+	m := map[types.Object]ast.Expr{}
+	define := func(lhs []*ast.Ident, rhs []ast.Expr) {
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, id := range lhs {
+			if o := info.Defs[id]; o != nil {
+				m[o] = rhs[i]
+			}
+		}
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE {
+				return true
+			}
+			lhs := make([]*ast.Ident, 0, len(n.Lhs))
+			for _, e := range n.Lhs {
+				id, ok := e.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				lhs = append(lhs, id)
+			}
+			define(lhs, n.Rhs)
+		case *ast.ValueSpec:
+			define(n.Names, n.Values)
+		}
+		return true
+	})
+	return m
+}
+
+// scopeOf returns the section path of the reader call is a method call on,
+// which is "" unless the reader was returned by Reader.Section, like in:
+//
+//	r.Section("Checkout").Text("Pay")
+//
+// Sections are followed through calls to Reader.WithRegister and
+// variables defined by such calls (see readerVarsOf).
+func scopeOf(
+	info *types.Info, vars map[types.Object]ast.Expr, call *ast.CallExpr,
+) string {
+	selector, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	return readerScope(info, vars, selector.X, 0)
+}
+
+// readerScope returns the section path of reader expression e.
+func readerScope(
+	info *types.Info, vars map[types.Object]ast.Expr, e ast.Expr, depth int,
+) string {
+	if depth > maxScopeDepth {
+		return ""
+	}
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		if v, ok := vars[info.Uses[e]]; ok {
+			return readerScope(info, vars, v, depth+1)
+		}
+	case *ast.CallExpr:
+		method, ok := readerMethod(info, e)
+		if !ok {
+			return ""
+		}
+		recv := e.Fun.(*ast.SelectorExpr).X
+		switch method {
+		case methodWithRegister:
+			return readerScope(info, vars, recv, depth+1)
+		case methodSection:
+			name, ok := sectionName(info, e)
+			if !ok {
+				return ""
+			}
+			return localize.SectionPath(readerScope(info, vars, recv, depth+1), name)
+		}
+	}
+	return ""
+}
+
+// sectionName returns the name argument of Reader.Section call.
+// ok is false if the name isn't a non-empty string constant.
+func sectionName(info *types.Info, call *ast.CallExpr) (name string, ok bool) {
+	if len(call.Args) != 1 {
+		return "", false
+	}
+	v := info.Types[call.Args[0]].Value
+	if v == nil || v.Kind() != constant.String {
+		return "", false
+	}
+	name = strings.TrimSpace(constant.StringVal(v))
+	return name, name != ""
+}
+
+// validateSection reports names of Reader.Section call that aren't
+// non-empty string constants since their messages can't be extracted
+// into the section.
+func validateSection(
+	s *[]ErrorSrc, pos token.Position, call *ast.CallExpr, info *types.Info,
+) {
+	if _, ok := sectionName(info, call); ok {
+		return
+	}
+	v := info.Types[call.Args[0]].Value
+	if v != nil && v.Kind() == constant.String {
+		appendSrcErr(s, pos, section.ErrEmpty)
+		return
+	}
+	appendSrcErr(s, pos, fmt.Errorf(
+		"%w: section name %s", ErrSourceArgType, typeKind(call.Args[0]),
+	))
+}
@@ -0,0 +1,103 @@
+package codeparser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/romshark/localize/internal/section"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeOf(t *testing.T) {
+	const stub = `package localize
+type Register uint8
+type Reader interface {
+	Text(text string) string
+	Section(name string) Reader
+	WithRegister(register Register) Reader
+}
+`
+	const src = `package p
+
+import "github.com/romshark/localize"
+
+const Billing = "Billing"
+
+func f(r localize.Reader, dynamic string) {
+	checkout := r.Section("Checkout")
+	payment := checkout.Section(" Payment ").WithRegister(1)
+	var billing = r.Section(Billing)
+	r.Text("A")
+	r.Section("Checkout").Text("B")
+	checkout.Text("C")
+	payment.Text("D")
+	(billing).Text("E")
+	r.Section(dynamic).Text("F")
+	r.Section("").Text("G")
+}
+`
+	fset := token.NewFileSet()
+	stubFile, err := parser.ParseFile(fset, "localize.go", stub, 0)
+	require.NoError(t, err)
+	localize, err := new(types.Config).Check(
+		targetPackage, fset, []*ast.File{stubFile}, nil,
+	)
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+	}
+	conf := types.Config{Importer: importerFunc(func(string) (*types.Package, error) {
+		return localize, nil
+	})}
+	_, err = conf.Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	vars := readerVarsOf(file, info)
+
+	// scopes maps the lines of Text calls to their scope.
+	scopes := map[int]string{}
+	// errs maps the lines of Section calls to the errors reported.
+	errs := map[int][]ErrorSrc{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		pos := fset.Position(call.Pos())
+		switch method, _ := readerMethod(info, call); method {
+		case FuncTypeText:
+			scopes[pos.Line] = scopeOf(info, vars, call)
+		case methodSection:
+			var l []ErrorSrc
+			validateSection(&l, pos, call, info)
+			errs[pos.Line] = l
+		}
+		return true
+	})
+	require.Equal(t, map[int]string{
+		11: "",
+		12: "Checkout",
+		13: "Checkout",
+		14: "Checkout/Payment",
+		15: "Billing",
+		16: "",
+		17: "",
+	}, scopes)
+
+	for _, line := range []int{8, 9, 10, 12} {
+		require.Empty(t, errs[line], line)
+	}
+	require.Len(t, errs[16], 1)
+	require.ErrorIs(t, errs[16][0].Err, ErrSourceArgType)
+	require.Len(t, errs[17], 1)
+	require.ErrorIs(t, errs[17][0].Err, section.ErrEmpty)
+}
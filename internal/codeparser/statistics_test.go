@@ -0,0 +1,76 @@
+package codeparser
+
+import (
+	"encoding/json"
+	"go/token"
+	"testing"
+	"time"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatisticsAddCall(t *testing.T) {
+	s := &Statistics{Packages: map[string]*PackageStatistics{}}
+	s.addCall("example/a", FuncTypeText)
+	s.addCall("example/a", FuncTypeText)
+	s.addCall("example/a", FuncTypePlural)
+	s.addCall("example/b", FuncTypeBlock)
+	s.addCall("example/b", FuncTypePluralBlock)
+	s.addCall("example/b", FuncTypeCardinal)
+	s.addCall("example/b", FuncTypePluralRange)
+	s.addCall("example/b", FuncTypePluralOrdinal)
+
+	j, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"textTotal": 2,
+		"blockTotal": 1,
+		"pluralTotal": 1,
+		"pluralBlockTotal": 1,
+		"cardinalTotal": 1,
+		"pluralRangeTotal": 1,
+		"pluralOrdinalTotal": 1,
+		"messages": 0,
+		"scheduled": 0,
+		"embargoed": 0,
+		"expired": 0,
+		"merges": 0,
+		"filesTraversed": 0,
+		"packages": {
+			"example/a": {"calls": {"Text": 2, "Plural": 1}},
+			"example/b": {"calls": {"Block": 1, "PluralBlock": 1, "Cardinal": 1, "PluralRange": 1, "PluralOrdinal": 1}}
+		}
+	}`, string(j))
+}
+
+func TestReportSchedules(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 11, d, 0, 0, 0, 0, time.UTC) }
+	pos := func(line int) []token.Position {
+		return []token.Position{{Filename: "main.go", Line: line}}
+	}
+	collection := &Collection{Messages: map[Msg]MsgMeta{
+		{Hash: "a", Other: "Always"}: {Pos: pos(1)},
+		{Hash: "b", Other: "Soon"}: {
+			Pos: pos(2), Schedule: localize.Schedule{NotBefore: day(20)},
+		},
+		{Hash: "c", Other: "Now"}: {
+			Pos:      pos(3),
+			Schedule: localize.Schedule{NotBefore: day(1), NotAfter: day(20)},
+		},
+		{Hash: "d", Other: "Gone"}: {
+			Pos: pos(4), Schedule: localize.Schedule{NotAfter: day(5)},
+		},
+	}}
+	s := &Statistics{}
+	var srcErrs []ErrorSrc
+	reportSchedules(&srcErrs, s, collection, day(10))
+	require.Equal(t, int64(3), s.Scheduled)
+	require.Equal(t, int64(1), s.Embargoed)
+	require.Equal(t, int64(1), s.Expired)
+	require.Len(t, srcErrs, 1)
+	require.Equal(t, 4, srcErrs[0].Line)
+	require.Equal(t, SeverityWarning, srcErrs[0].Severity)
+	require.ErrorIs(t, srcErrs[0].Err, ErrMessageExpired)
+	require.Equal(t, "message-expired", srcErrs[0].Code())
+}
@@ -0,0 +1,34 @@
+package codeparser
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+var regexpTermPlaceholder = regexp.MustCompile(`\{([A-Za-z][A-Za-z0-9_]*)\}`)
+
+// CheckTerms returns an ErrUnknownTerm error for every position of
+// a message of c using a term placeholder like "{productName}"
+// with a name that isn't in names (see localize.NewTermTransformer).
+func (c *Collection) CheckTerms(names []string) []ErrorSrc {
+	var errs []ErrorSrc
+	for msg, meta := range c.Ordered() {
+		var unknown []string
+		for _, t := range [...]string{
+			msg.Zero, msg.One, msg.Two, msg.Few, msg.Many, msg.Other,
+		} {
+			for _, m := range regexpTermPlaceholder.FindAllStringSubmatch(t, -1) {
+				if !slices.Contains(names, m[1]) && !slices.Contains(unknown, m[0]) {
+					unknown = append(unknown, m[0])
+				}
+			}
+		}
+		for _, pos := range meta.Pos {
+			for _, u := range unknown {
+				appendSrcErr(&errs, pos, fmt.Errorf("%w %q", ErrUnknownTerm, u))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,42 @@
+package codeparser
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTerms(t *testing.T) {
+	p := func(line int) token.Position {
+		return token.Position{Filename: "/main.go", Line: line, Column: 2}
+	}
+	c := &Collection{Messages: map[Msg]MsgMeta{
+		{Hash: "a", FuncType: FuncTypeText, Other: "Welcome to {productName}"}: {
+			Pos: []token.Position{p(1)},
+		},
+		{Hash: "b", FuncType: FuncTypeText, Other: "{company} {produkt} {produkt}"}: {
+			Pos: []token.Position{p(2), p(3)},
+		},
+		{
+			Hash: "c", FuncType: FuncTypePlural,
+			One: "%d {seat}", Other: "%d {seats} of {productName}",
+		}: {Pos: []token.Position{p(4)}},
+		{Hash: "d", FuncType: FuncTypeText, Other: "JSON { } {1} {a-b}"}: {
+			Pos: []token.Position{p(5)},
+		},
+	}}
+
+	var actual []string
+	for _, e := range c.CheckTerms([]string{"productName", "company"}) {
+		require.ErrorIs(t, e.Err, ErrUnknownTerm)
+		require.Equal(t, "term-unknown", e.Code())
+		actual = append(actual, e.Err.Error()+" at "+e.Position.String())
+	}
+	require.Equal(t, []string{
+		`unknown term placeholder "{produkt}" at /main.go:2:2`,
+		`unknown term placeholder "{produkt}" at /main.go:3:2`,
+		`unknown term placeholder "{seat}" at /main.go:4:2`,
+		`unknown term placeholder "{seats}" at /main.go:4:2`,
+	}, actual)
+}
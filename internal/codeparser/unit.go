@@ -0,0 +1,225 @@
+package codeparser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var ErrUnitImport = errors.New("unresolved import")
+
+// Unit is a single package to extract messages from without invoking
+// the go command, which is required by hermetic build systems like Bazel
+// and Please that provide the sources of a package and the export data
+// of its dependencies but no module access during builds.
+// The JSON encoding is compatible with the unit config passed to
+// analysis tools by "go vet -vettool", such that build rules generating
+// it can be reused.
+type Unit struct {
+	// ImportPath is the package path of the unit.
+	ImportPath string
+
+	// Dir is the directory of the package. Positions are reported
+	// relative to Dir if paths are trimmed. Set to the directory
+	// of the first Go file by default.
+	Dir string `json:",omitempty"`
+
+	// GoFiles are the paths of the Go files of the package.
+	GoFiles []string
+
+	// ImportMap maps the import paths of the Go files to package paths,
+	// like vendored paths. Import paths are package paths by default.
+	ImportMap map[string]string `json:",omitempty"`
+
+	// PackageFile maps package paths to files of gc export data
+	// of the dependencies of the package, such as the .a files
+	// written by the compiler or "go list -export".
+	PackageFile map[string]string
+
+	// GoVersion is the Go language version of the package like "go1.24".
+	// The version of the toolchain is used by default.
+	GoVersion string `json:",omitempty"`
+}
+
+// ReadUnit reads the unit JSON file at path.
+func ReadUnit(path string) (*Unit, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	u := new(Unit)
+	if err := json.Unmarshal(b, u); err != nil {
+		return nil, fmt.Errorf("decoding unit %s: %w", path, err)
+	}
+	if u.ImportPath == "" {
+		return nil, fmt.Errorf("unit %s: missing ImportPath", path)
+	}
+	if len(u.GoFiles) < 1 {
+		return nil, fmt.Errorf("unit %s: missing GoFiles", path)
+	}
+	if u.Dir == "" {
+		u.Dir = filepath.Dir(u.GoFiles[0])
+	}
+	return u, nil
+}
+
+// loadUnit parses the Go files of u and type-checks them against the export
+// data of its dependencies instead of loading them from source.
+// Only forwarders declared in u are found since the dependencies
+// provide no syntax.
+func loadUnit(
+	ctx context.Context, fileset *token.FileSet, u *Unit,
+	fn func([]*packages.Package),
+) error {
+	dir, err := filepath.Abs(u.Dir)
+	if err != nil {
+		return fmt.Errorf("getting absolute path: %w", err)
+	}
+	pkg := &packages.Package{
+		ID:      u.ImportPath,
+		PkgPath: u.ImportPath,
+		Dir:     dir,
+		Fset:    fileset,
+		TypesInfo: &types.Info{
+			Types:        map[ast.Expr]types.TypeAndValue{},
+			Instances:    map[*ast.Ident]types.Instance{},
+			Defs:         map[*ast.Ident]types.Object{},
+			Uses:         map[*ast.Ident]types.Object{},
+			Implicits:    map[ast.Node]types.Object{},
+			Selections:   map[*ast.SelectorExpr]*types.Selection{},
+			Scopes:       map[ast.Node]*types.Scope{},
+			FileVersions: map[*ast.File]string{},
+		},
+	}
+	for _, name := range u.GoFiles {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		file, err := filepath.Abs(name)
+		if err != nil {
+			return fmt.Errorf("getting absolute path: %w", err)
+		}
+		f, err := parser.ParseFile(fileset, file, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		pkg.GoFiles = append(pkg.GoFiles, file)
+		pkg.Syntax = append(pkg.Syntax, f)
+	}
+	pkg.CompiledGoFiles = pkg.GoFiles
+	pkg.Name = pkg.Syntax[0].Name.Name
+
+	exportData := importer.ForCompiler(fileset, "gc", func(path string) (io.ReadCloser, error) {
+		file, ok := u.PackageFile[path]
+		if !ok {
+			return nil, fmt.Errorf("%w: no package file for %q", ErrUnitImport, path)
+		}
+		return os.Open(file)
+	})
+	// errImport is the first import error, which the type checker
+	// reports without wrapping.
+	var errImport error
+	conf := types.Config{
+		GoVersion: u.GoVersion,
+		Importer: importerFunc(func(importPath string) (*types.Package, error) {
+			if path, ok := u.ImportMap[importPath]; ok {
+				importPath = path
+			}
+			p, err := exportData.Import(importPath)
+			if err != nil && errImport == nil {
+				errImport = err
+			}
+			return p, err
+		}),
+	}
+	pkg.Types, err = conf.Check(u.ImportPath, fileset, pkg.Syntax, pkg.TypesInfo)
+	if errImport != nil {
+		return fmt.Errorf("type-checking %s: %w", u.ImportPath, errImport)
+	}
+	if err != nil {
+		return fmt.Errorf("type-checking %s: %w", u.ImportPath, err)
+	}
+	fn([]*packages.Package{pkg})
+	return nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// collectionJSON is the JSON encoding of a Collection.
+type collectionJSON struct {
+	Locale        string        `json:"locale"`
+	Normalization string        `json:"normalization"`
+	Messages      []messageJSON `json:"messages"`
+}
+
+type messageJSON struct {
+	Hash        string    `json:"hash"`
+	FuncType    string    `json:"funcType"`
+	Description string    `json:"description,omitempty"`
+	Scope       string    `json:"scope,omitempty"`
+	Section     string    `json:"section,omitempty"`
+	Forms       formsJSON `json:"forms"`
+	Reflow      bool      `json:"reflow,omitempty"`
+	Heading     bool      `json:"heading,omitempty"`
+	Editions    []string  `json:"editions,omitempty"`
+	Regions     []string  `json:"regions,omitempty"`
+	ErrorCodes  []string  `json:"errorCodes,omitempty"`
+	Protected   []string  `json:"protected,omitempty"`
+	References  []string  `json:"references"`
+}
+
+type formsJSON struct {
+	Zero  string `json:"zero,omitempty"`
+	One   string `json:"one,omitempty"`
+	Two   string `json:"two,omitempty"`
+	Few   string `json:"few,omitempty"`
+	Many  string `json:"many,omitempty"`
+	Other string `json:"other"`
+}
+
+// WriteJSON writes the messages of c to w as indented JSON
+// in the order of Ordered.
+func (c *Collection) WriteJSON(w io.Writer) error {
+	v := collectionJSON{
+		Locale:        c.Locale.String(),
+		Normalization: c.Normalization.String(),
+		Messages:      make([]messageJSON, 0, len(c.Messages)),
+	}
+	for msg, meta := range c.Ordered() {
+		v.Messages = append(v.Messages, messageJSON{
+			Hash:        msg.Hash,
+			FuncType:    msg.FuncType,
+			Description: msg.Description,
+			Scope:       msg.Scope,
+			Section:     meta.Section,
+			Forms: formsJSON{
+				Zero: msg.Zero, One: msg.One, Two: msg.Two,
+				Few: msg.Few, Many: msg.Many, Other: msg.Other,
+			},
+			Reflow:     msg.Reflow,
+			Heading:    meta.Heading,
+			Editions:   meta.Editions,
+			Regions:    meta.Regions,
+			ErrorCodes: meta.ErrorCodes,
+			Protected:  meta.Protected,
+			References: meta.References(),
+		})
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.SetEscapeHTML(false)
+	return e.Encode(v)
+}
@@ -0,0 +1,176 @@
+package codeparser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize/strfmt"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// testUnit writes src to a Go file and returns a unit referring to the export
+// data of github.com/romshark/localize and its dependencies.
+func testUnit(t *testing.T, src string) *Unit {
+	t.Helper()
+	out, err := exec.Command(
+		"go", "list", "-export", "-deps",
+		"-f", "{{.ImportPath}}={{.Export}}", targetPackage,
+	).Output()
+	require.NoError(t, err)
+	u := &Unit{
+		ImportPath:  "example.com/app",
+		GoFiles:     []string{filepath.Join(t.TempDir(), "app.go")},
+		PackageFile: map[string]string{},
+	}
+	for line := range strings.Lines(string(out)) {
+		path, file, _ := strings.Cut(strings.TrimSpace(line), "=")
+		if file != "" {
+			u.PackageFile[path] = file
+		}
+	}
+	require.NoError(t, os.WriteFile(u.GoFiles[0], []byte(src), 0o644))
+
+	b, err := json.Marshal(u)
+	require.NoError(t, err)
+	unitFile := filepath.Join(t.TempDir(), "unit.json")
+	require.NoError(t, os.WriteFile(unitFile, b, 0o644))
+	u, err = ReadUnit(unitFile)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Dir(u.GoFiles[0]), u.Dir)
+	return u
+}
+
+func TestParseUnit(t *testing.T) {
+	u := testUnit(t, `package app
+
+import "github.com/romshark/localize"
+
+func greet(r localize.Reader, n int) {
+	// Greeting shown on start.
+	_ = r.Text("Hello")
+	// Number of files.
+	_ = r.Plural(localize.Forms{One: "%d file", Other: "%d files"}, n)
+}
+`)
+	collection, bundle, _, srcErrs, err := Parse(
+		context.Background(), u.Dir, "", "", language.English,
+		strfmt.DedentPreserve, true, true, false, LoadOptions{Unit: u},
+	)
+	require.NoError(t, err)
+	require.Empty(t, srcErrs)
+	require.Nil(t, bundle)
+	require.Len(t, collection.Messages, 2)
+
+	var buf bytes.Buffer
+	require.NoError(t, collection.WriteJSON(&buf))
+	var v collectionJSON
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &v))
+	require.Equal(t, "en", v.Locale)
+	require.Len(t, v.Messages, 2)
+	for _, m := range v.Messages {
+		require.NotEmpty(t, m.Hash)
+		switch m.FuncType {
+		case FuncTypeText:
+			require.Equal(t, "Hello", m.Forms.Other)
+			require.Equal(t, "Greeting shown on start.", m.Description)
+			require.Equal(t, []string{"/app.go:7"}, m.References)
+		case FuncTypePlural:
+			require.Equal(t, formsJSON{One: "%d file", Other: "%d files"}, m.Forms)
+		}
+	}
+}
+
+func TestParseUnitMissingExportData(t *testing.T) {
+	u := testUnit(t, `package app
+
+import "github.com/romshark/localize"
+
+func greet(r localize.Reader) { _ = r.Text("Hello") }
+`)
+	delete(u.PackageFile, targetPackage)
+	_, _, _, _, err := Parse(
+		context.Background(), u.Dir, "", "", language.English,
+		strfmt.DedentPreserve, true, true, false, LoadOptions{Unit: u},
+	)
+	require.ErrorIs(t, err, ErrUnitImport)
+}
+
+func TestParseHashCollision(t *testing.T) {
+	u := testUnit(t, `package app
+
+import "github.com/romshark/localize"
+
+func files(r localize.Reader, n int) {
+	// Number of files.
+	_ = r.Text("%d files")
+	// Number of files.
+	_ = r.Plural(localize.Forms{One: "%d file", Other: "%d files"}, n)
+}
+`)
+	collection, _, _, srcErrs, err := Parse(
+		context.Background(), u.Dir, "", "", language.English,
+		strfmt.DedentPreserve, true, true, false,
+		LoadOptions{Unit: u, Hash: HashFNV128a},
+	)
+	require.NoError(t, err)
+	require.Len(t, collection.Messages, 1)
+	for m := range collection.Messages {
+		require.Equal(t, HashFNV128a.Sum("%d files", "Number of files."), m.Hash)
+	}
+	require.Len(t, srcErrs, 1)
+	require.ErrorIs(t, srcErrs[0].Err, ErrHashCollision)
+	require.True(t, strings.HasSuffix(srcErrs[0].Err.Error(), "/app.go:7:6"),
+		srcErrs[0].Err.Error())
+	require.Equal(t, 9, srcErrs[0].Line)
+}
+
+func TestParseCardinalFormComments(t *testing.T) {
+	u := testUnit(t, `package app
+
+import "github.com/romshark/localize"
+
+func inbox(r localize.Reader, n int) {
+	// Number of unread emails.
+	// one: "You have %d unread email"
+	_ = r.Cardinal("You have %d unread emails", n)
+	// Number of drafts.
+	// one: "%d draft"
+	_ = r.Text("%d drafts")
+	// Number of spam emails.
+	// one: "One spam email"
+	_ = r.Cardinal("%d spam emails", n)
+}
+`)
+	collection, _, _, srcErrs, err := Parse(
+		context.Background(), u.Dir, "", "", language.English,
+		strfmt.DedentPreserve, true, true, false, LoadOptions{Unit: u},
+	)
+	require.NoError(t, err)
+	require.Len(t, srcErrs, 2)
+	// Form comments only apply to Cardinal calls.
+	require.ErrorIs(t, srcErrs[0].Err, ErrInvalidDirective)
+	require.Equal(t, 11, srcErrs[0].Line)
+	// Comment forms are validated like the forms of Plural calls.
+	require.ErrorIs(t, srcErrs[1].Err, ErrMissingQuantityPlaceholder)
+	require.Equal(t, 14, srcErrs[1].Line)
+
+	for m, meta := range collection.Messages {
+		switch m.Other {
+		case "You have %d unread emails":
+			require.Equal(t, FuncTypePlural, m.FuncType)
+			require.Equal(t, "You have %d unread email", m.One)
+			require.Equal(t, "Number of unread emails.", m.Description)
+			require.True(t, meta.CommentForms)
+		case "%d drafts":
+			require.Equal(t, "Number of drafts.", m.Description)
+			require.False(t, meta.CommentForms)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/fuzzy"
+	"github.com/romshark/localize/internal/limits"
+	"github.com/romshark/localize/internal/termcolor"
+	"github.com/romshark/localize/internal/vcs"
+)
+
+// Command is a node of the declarative CLI command tree
+// used to parse arguments, print help and generate
+// shell completions and man pages.
+type Command struct {
+	Name        string
+	Description string
+
+	// Args lists all valid values of the positional argument if any.
+	Args []string
+
+	// ArgName is the name of the free-form positional argument if any.
+	ArgName string
+
+	// FlagValues lists all valid values of enum flags by flag name.
+	FlagValues map[string][]string
+
+	// Flags declares the flags of the command on cli.
+	Flags func(cli *flag.FlagSet)
+}
+
+// FlagSet returns a new flag set with all flags of the command declared.
+func (c Command) FlagSet() *flag.FlagSet {
+	cli := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	if c.Flags != nil {
+		c.Flags(cli)
+	}
+	return cli
+}
+
+// ArgUsage returns the usage of the positional argument
+// or an empty string if the command has none.
+func (c Command) ArgUsage() string {
+	if len(c.Args) > 0 {
+		return strings.Join(c.Args, "|")
+	}
+	if c.ArgName != "" {
+		return "<" + c.ArgName + ">"
+	}
+	return ""
+}
+
+// WriteUsage writes the help text of the command to w.
+func (c Command) WriteUsage(w io.Writer, program string) {
+	usage := program + " " + c.Name
+	if c.Flags != nil {
+		usage += " [flags]"
+	}
+	if a := c.ArgUsage(); a != "" {
+		usage += " " + a
+	}
+	fmt.Fprintf(w, "Usage: %s\n\n%s\n", usage, c.Description)
+	if c.Flags == nil {
+		return
+	}
+	fmt.Fprint(w, "\nFlags:\n")
+	cli := c.FlagSet()
+	cli.SetOutput(w)
+	cli.PrintDefaults()
+}
+
+// Shells lists all shells supported by command "completions".
+var Shells = []string{"bash", "zsh", "fish"}
+
+// GlobalFlags declares the global flags preceding the command.
+var GlobalFlags = Command{
+	FlagValues: map[string][]string{"color": termcolor.Modes},
+	Flags:      func(cli *flag.FlagSet) { flagsGlobal(cli, &Global{}) },
+}
+
+// Commands is the CLI command tree.
+var Commands = []Command{
+	{
+		Name: "generate",
+		Description: "Extract messages from the source code and generate " +
+			"the catalog template, translation catalogs and the Go bundle.",
+		FlagValues: map[string][]string{
+			"stats-format":         {"text", "json"},
+			"error-format":         {"text", "json"},
+			"report":               {"html"},
+			"Wignore":              codeparser.WarningCodes,
+			"blame":                vcs.Names(),
+			"track-seen":           append([]string{"time"}, vcs.Names()...),
+			"dedent":               {"preserve", "reflow"},
+			"normalize":            {"none", "nfc", "spaces", "nfc,spaces"},
+			"max-message-len-unit": limits.Units,
+			"resolve":              fuzzy.Resolutions,
+		},
+		Flags: func(cli *flag.FlagSet) { flagsGenerate(cli) },
+	},
+	{
+		Name:        "docs",
+		Description: "Render all messages of a bundle into a documentation site.",
+		FlagValues:  map[string][]string{"f": {"html", "markdown"}},
+		Flags:       func(cli *flag.FlagSet) { flagsDocs(cli) },
+	},
+	{
+		Name:        "badge",
+		Description: "Render the translation coverage of a catalog as a badge.",
+		FlagValues:  map[string][]string{"f": {"svg", "json"}},
+		Flags:       func(cli *flag.FlagSet) { flagsBadge(cli) },
+	},
+	{
+		Name: "release-check",
+		Description: "Verify that the messages added since a release are " +
+			"translated in the release-critical locales.",
+		Flags: func(cli *flag.FlagSet) { flagsReleaseCheck(cli) },
+	},
+	{
+		Name: "export-state",
+		Description: "Export the locales, headers, messages, translations " +
+			"and coverage of a bundle as canonical JSON.",
+		Flags: func(cli *flag.FlagSet) { flagsExportState(cli) },
+	},
+	{
+		Name: "export-untranslated",
+		Description: "Export the untranslated and fuzzy messages of a catalog " +
+			"into a catalog for translators.",
+		Flags: func(cli *flag.FlagSet) { flagsExportUntranslated(cli) },
+	},
+	{
+		Name: "import-untranslated",
+		Description: "Import the translations of a catalog written by " +
+			"export-untranslated into the catalog of its locale.",
+		ArgName: "file",
+		Flags:   func(cli *flag.FlagSet) { flagsImportUntranslated(cli) },
+	},
+	{
+		Name: "extract-unit",
+		Description: "Extract the messages of a single package from its Go files " +
+			"and the export data of its dependencies as JSON, " +
+			"for hermetic build systems like Bazel.",
+		ArgName: "unit",
+		FlagValues: map[string][]string{
+			"dedent":    {"preserve", "reflow"},
+			"normalize": {"none", "nfc", "spaces", "nfc,spaces"},
+		},
+		Flags: func(cli *flag.FlagSet) { flagsExtractUnit(cli) },
+	},
+	{
+		Name: "whereis",
+		Description: "Find the messages and code references of a text " +
+			"in all catalogs of a bundle.",
+		ArgName:    "text",
+		FlagValues: map[string][]string{"f": {"text", "json"}},
+		Flags:      func(cli *flag.FlagSet) { flagsWhereis(cli) },
+	},
+	{
+		Name: "import-go-i18n",
+		Description: "Convert go-i18n message files to translation catalogs " +
+			"and suggest the Reader calls replacing their message IDs.",
+		ArgName: "files",
+		Flags:   func(cli *flag.FlagSet) { flagsImportGoI18n(cli) },
+	},
+	{
+		Name: "import-x-text",
+		Description: "Convert the gotext catalogs of golang.org/x/text/message " +
+			"to translation catalogs and report the message.Printer calls to convert.",
+		ArgName: "files",
+		Flags:   func(cli *flag.FlagSet) { flagsImportXText(cli) },
+	},
+	{
+		Name: "migrate-strings",
+		Description: "Rewrite the string literals of existing code into " +
+			"Reader.Text calls inserting Reader parameters where needed.",
+		Flags: func(cli *flag.FlagSet) { flagsMigrateStrings(cli) },
+	},
+	{
+		Name: "dedup",
+		Description: "Merge duplicate messages of a .po or .pot file " +
+			"combining their references and translations.",
+		ArgName: "file",
+		Flags:   func(cli *flag.FlagSet) { flagsDedup(cli) },
+	},
+	{
+		Name: "check",
+		Description: "Check .po and .pot files for syntax errors, " +
+			"invalid headers, duplicates and broken translations.",
+		ArgName: "files",
+		Flags:   func(cli *flag.FlagSet) { flagsCheck(cli) },
+	},
+	{
+		Name:        "fmt",
+		Description: "Format .po and .pot files in the canonical format.",
+		ArgName:     "files",
+		Flags:       func(cli *flag.FlagSet) { flagsFmt(cli) },
+	},
+	{
+		Name: "merge",
+		Description: "Update the messages of a .po catalog to those of " +
+			"a .pot template keeping their translations like GNU msgmerge.",
+		ArgName: "catalog template",
+		Flags:   func(cli *flag.FlagSet) { flagsMerge(cli) },
+	},
+	{
+		Name: "plural-tests",
+		Description: "Generate tests asserting the plural forms selected " +
+			"by the readers of a bundle for CLDR sample quantities.",
+		Flags: func(cli *flag.FlagSet) { flagsPluralTests(cli) },
+	},
+	{
+		Name: "smoke",
+		Description: "Verify that the lookups of the compiled readers of a bundle " +
+			"return the translations of a catalog using a generated program.",
+		Flags: func(cli *flag.FlagSet) { flagsSmoke(cli) },
+	},
+	{
+		Name: "selftest",
+		Description: "Run generate twice on temporary copies of the module and " +
+			"verify that the outputs are identical and the bundle compiles.",
+		ArgName: "generate flags",
+		Flags:   func(cli *flag.FlagSet) { flagsSelftest(cli) },
+	},
+	{
+		Name: "trim",
+		Description: "Remove the translation catalogs of locales no longer " +
+			"shipped and regenerate the Go bundle.",
+		ArgName: "generate flags",
+		Flags:   func(cli *flag.FlagSet) { flagsTrim(cli) },
+	},
+	{
+		Name: "example",
+		Description: "Write a minimal runnable web app demonstrating the bundle " +
+			"setup, HTTP locale negotiation, plural messages and catalogs.",
+		Flags: func(cli *flag.FlagSet) { flagsExample(cli) },
+	},
+	{
+		Name:        "completions",
+		Description: "Print the shell completion script for bash, zsh or fish.",
+		Args:        Shells,
+	},
+	{
+		Name: "config",
+		Description: "Print the JSON schema of the configuration file (-config) " +
+			"or validate a configuration file.",
+		Args:  ConfigActions,
+		Flags: func(cli *flag.FlagSet) { flagsConfig(cli) },
+	},
+	{
+		Name:        "man",
+		Description: "Print the man page.",
+		Flags:       func(cli *flag.FlagSet) { flagsMan(cli) },
+	},
+	{
+		Name:        "help",
+		Description: "Print the list of all commands or the help text of a command.",
+	},
+}
+
+// CommandByName returns the command with the given name.
+func CommandByName(name string) (Command, bool) {
+	for _, c := range Commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
+
+// WriteUsage writes the help text listing all commands to w.
+func WriteUsage(w io.Writer, program string) {
+	fmt.Fprintf(w, "Usage: %s [global flags] <command> [flags]\n\nCommands:\n",
+		program)
+	width := 0
+	for _, c := range Commands {
+		width = max(width, len(c.Name))
+	}
+	for _, c := range Commands {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, c.Name, c.Description)
+	}
+	fmt.Fprint(w, "\nGlobal flags:\n")
+	cli := GlobalFlags.FlagSet()
+	cli.SetOutput(w)
+	cli.PrintDefaults()
+	fmt.Fprintf(w, "\nUse \"%s help <command>\" for more information about a command.\n",
+		program)
+}
+
+// newFlagSet returns a new flag set for the command with the given name
+// printing the help text of the command on -h.
+func newFlagSet(g Global, name string) *flag.FlagSet {
+	cli := flag.NewFlagSet(name, flag.ExitOnError)
+	cli.Usage = func() {
+		if c, ok := CommandByName(name); ok {
+			c.WriteUsage(cli.Output(), g.Program)
+		}
+	}
+	return cli
+}
+
+type ConfigCompletions struct {
+	Shell string
+}
+
+// ParseCLIArgsCompletions parses CLI arguments for command "completions"
+func ParseCLIArgsCompletions(g Global, args []string) (*ConfigCompletions, error) {
+	c := &ConfigCompletions{}
+
+	cli := newFlagSet(g, "completions")
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+
+	if cli.NArg() != 1 {
+		return nil, fmt.Errorf("please provide exactly one shell: %v", Shells)
+	}
+	c.Shell = cli.Arg(0)
+	switch c.Shell {
+	case "bash", "zsh", "fish":
+	default:
+		return nil, fmt.Errorf("unsupported shell (%q), use either of: %v",
+			c.Shell, Shells)
+	}
+
+	return c, nil
+}
+
+// ConfigActions lists all actions of command "config".
+var ConfigActions = []string{"schema", "validate"}
+
+type ConfigConfig struct {
+	// Action is either of ConfigActions.
+	Action string
+
+	// InPath is the path of the configuration file to validate.
+	InPath string
+
+	// OutPath is the output file path of the schema,
+	// the schema is written to stdout if empty.
+	OutPath string
+
+	QuietMode bool
+}
+
+// ParseCLIArgsConfig parses CLI arguments for command "config"
+func ParseCLIArgsConfig(g Global, args []string) (*ConfigConfig, error) {
+	cli := newFlagSet(g, "config")
+	c := flagsConfig(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+
+	if cli.NArg() < 1 {
+		return nil, fmt.Errorf("please provide an action: %v", ConfigActions)
+	}
+	c.Action = cli.Arg(0)
+	switch c.Action {
+	case "schema":
+		if cli.NArg() != 1 {
+			return nil, fmt.Errorf("unexpected arguments: %v", cli.Args()[1:])
+		}
+	case "validate":
+		if cli.NArg() != 2 {
+			return nil, fmt.Errorf("please provide exactly one configuration file")
+		}
+		c.InPath = cli.Arg(1)
+	default:
+		return nil, fmt.Errorf("unsupported action (%q), use either of: %v",
+			c.Action, ConfigActions)
+	}
+	return c, nil
+}
+
+// flagsConfig declares the flags of command "config" on cli.
+func flagsConfig(cli *flag.FlagSet) *ConfigConfig {
+	c := &ConfigConfig{}
+	cli.StringVar(&c.OutPath, "o", "",
+		"output file path of the schema. Set to stdout by default.")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+	return c
+}
+
+type ConfigMan struct {
+	OutPath string
+}
+
+// ParseCLIArgsMan parses CLI arguments for command "man"
+func ParseCLIArgsMan(g Global, args []string) (*ConfigMan, error) {
+	cli := newFlagSet(g, "man")
+	c := flagsMan(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// flagsMan declares the flags of command "man" on cli.
+func flagsMan(cli *flag.FlagSet) *ConfigMan {
+	c := &ConfigMan{}
+	cli.StringVar(&c.OutPath, "o", "", "output file path. Set to stdout by default.")
+	return c
+}
+
+type ConfigHelp struct {
+	// Command is the command to print the help text of.
+	// Command is nil if the list of all commands should be printed instead.
+	Command *Command
+}
+
+// ParseCLIArgsHelp parses CLI arguments for command "help"
+func ParseCLIArgsHelp(g Global, args []string) (*ConfigHelp, error) {
+	c := &ConfigHelp{}
+
+	cli := newFlagSet(g, "help")
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+
+	switch cli.NArg() {
+	case 0:
+	case 1:
+		cmd, ok := CommandByName(cli.Arg(0))
+		if !ok {
+			return nil, fmt.Errorf("unknown command: %q", cli.Arg(0))
+		}
+		c.Command = &cmd
+	default:
+		return nil, fmt.Errorf("expected at most one command, received: %v",
+			cli.Args())
+	}
+
+	return c, nil
+}
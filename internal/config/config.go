@@ -3,8 +3,25 @@ package config
 import (
 	"flag"
 	"fmt"
+	"go/token"
+	"path"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/domain"
+	"github.com/romshark/localize/internal/edition"
+	"github.com/romshark/localize/internal/fuzzy"
+	"github.com/romshark/localize/internal/limits"
+	"github.com/romshark/localize/internal/region"
+	"github.com/romshark/localize/internal/vcs"
+	"github.com/romshark/localize/strfmt"
+	"github.com/romshark/localize/typography"
+	"golang.org/x/mod/module"
 	"golang.org/x/text/language"
 )
 
@@ -16,15 +33,211 @@ type ConfigGenerate struct {
 	QuietMode              bool
 	VerboseMode            bool
 	BundlePkgPath          string
+	LockWait               time.Duration
+	LockStaleAfter         time.Duration
+
+	// ModulePath is the path of the module containing the bundle package.
+	ModulePath string
+
+	// ImportPath is the import path of the bundle package.
+	// If empty the bundle package is detected by its directory instead.
+	ImportPath string
+
+	// PackageName is the name of the generated Go bundle package.
+	PackageName string
+
+	// Typography is the set of locales to apply typographic post-processing to.
+	// TypographyAll is true if it's applied to all locales.
+	Typography    []language.Tag
+	TypographyAll bool
+
+	// HeadingCasing are the casings applied to the translations of
+	// messages flagged as heading in the generated Go bundle by locale.
+	HeadingCasing map[language.Tag]typography.Casing
+
+	// StatsFormat is either "text" or "json".
+	StatsFormat string
+
+	// ErrorFormat is the output format of source errors,
+	// either "text" or "json".
+	ErrorFormat string
+
+	// Report is the format of the QA report, either "html" or empty
+	// if no report is written. ReportOutPath is the report file path.
+	Report        string
+	ReportOutPath string
+
+	// WarningsAsErrors are the codes of warnings escalated to errors
+	// (see codeparser.WarningCodes). WarningsAsErrorsAll escalates all warnings.
+	WarningsAsErrors    []string
+	WarningsAsErrorsAll bool
+
+	// WarningsIgnored are the codes of warnings that aren't reported.
+	WarningsIgnored []string
+
+	// Load defines the package loading strategy.
+	Load codeparser.LoadOptions
+
+	// Profile enables printing the time spent on each package,
+	// limited to the ProfileTop slowest packages unless ProfileTop is 0.
+	Profile    bool
+	ProfileTop int
+
+	// CPUProfilePath is the path of the pprof CPU profile of the run,
+	// no profile is written if empty.
+	CPUProfilePath string
+
+	// Imports are the import paths of bundle packages of libraries whose
+	// source catalogs are imported into the collection.
+	Imports []string
+
+	// VendorCatalogs are directories of externally supplied translation
+	// catalogs merged into the Go bundle. They're never modified and
+	// translations of the bundle catalogs take precedence over them.
+	VendorCatalogs []string
+
+	// Terms are the names of the term placeholders like "{productName}"
+	// texts may use (see localize.NewTermTransformer).
+	// Term placeholders aren't validated if Terms is empty.
+	Terms []string
+
+	// PluralOverrides are project-specific plural forms overrides.
+	PluralOverrides []cldr.Override
+
+	// PluralFallback is the locale whose plural forms are used for catalogs
+	// of locales without CLDR data. language.Und stands for the CLDR root
+	// locale with form Other only.
+	PluralFallback language.Tag
+
+	// Fix rewrites catalog Language headers not matching their file names.
+	Fix bool
+
+	// Dedent is the default format of Block and PluralBlock texts.
+	Dedent strfmt.DedentMode
+
+	// Resolve is the resolution of translated messages whose source text
+	// changed, see package fuzzy.
+	Resolve fuzzy.Resolution
+
+	// Blame derives the Last-Translator and X-Translated-By-Commit headers
+	// of catalogs from the version control history if not nil.
+	Blame vcs.Blamer
+
+	// MessageIDs enables the message ID registry file (messages.lock).
+	MessageIDs bool
+
+	// TrackSeen enables recording the run each message was first and last
+	// seen in, identified by the time of the run if TrackSeenVCS is nil
+	// or by the current revision of TrackSeenVCS otherwise.
+	TrackSeen    bool
+	TrackSeenVCS vcs.Blamer
+
+	// PluralSamples enables listing sample quantities of every plural form
+	// in extracted comments of plural messages of translation catalogs.
+	PluralSamples bool
+
+	// HashIndex enables generating the SourceByHash and HashOf functions
+	// resolving message hashes in the Go bundle.
+	HashIndex bool
+
+	// IncludeObsolete compiles the translations of obsolete messages
+	// into the Go bundle.
+	IncludeObsolete bool
+
+	// TemplateDir is the directory of the templates customizing
+	// the Go bundle (see gengo.ParseTemplate).
+	TemplateDir string
+
+	// SplitFiles splits the Go bundle into a core file
+	// and one file per locale (see gengo.WriteSplit).
+	SplitFiles bool
+
+	// CompactReferences writes all code references of a message
+	// on a single "#:" line like GNU gettext tools.
+	CompactReferences bool
+
+	// Audit enables appending an entry to the audit log file
+	// (.localize-audit.jsonl) of the bundle package.
+	Audit bool
+
+	// Strict fails the generation if translation catalogs were modified
+	// after the Go bundle was last generated, which is a warning otherwise.
+	Strict bool
+
+	// SummaryPath is the path of the JSON summary of the run
+	// (localize-summary.json), no summary is written if empty.
+	SummaryPath string
+
+	// Domains splits the catalog template and translation catalogs
+	// into multiple files by domain.
+	Domains domain.Resolver
+
+	// Limits are the size limits of messages and catalogs.
+	// Exceeded limits are errors unless LimitsWarn is true.
+	Limits     limits.Limits
+	LimitsWarn bool
+
+	// Plugins are the output plugins run after the catalogs are updated
+	// (see package plugin).
+	Plugins []Plugin
+
+	// PostGenerate are the commands run in order after all files are
+	// written receiving the JSON summary of the run on stdin
+	// (see plugin.Hook). Each command is split into the executable
+	// and its arguments at white space.
+	PostGenerate [][]string
+}
+
+// Plugin is an output plugin of command "generate".
+type Plugin struct {
+	// Name is the name of the plugin resolved as executable
+	// "localize-gen-<name>" in PATH, or the path of the plugin executable
+	// if it contains a path separator.
+	Name string
+
+	// OutDir is the directory the files generated by the plugin are written to.
+	OutDir string
+}
+
+// werrorFlag is flag -Werror, which can be used both
+// as a boolean flag and with a warning code.
+type werrorFlag struct{ c *ConfigGenerate }
+
+func (f werrorFlag) String() string { return "" }
+
+func (f werrorFlag) IsBoolFlag() bool { return true }
+
+func (f werrorFlag) Set(s string) error {
+	switch {
+	case s == "true":
+		f.c.WarningsAsErrorsAll = true
+	case s == "false":
+		f.c.WarningsAsErrorsAll = false
+	case slices.Contains(codeparser.WarningCodes, s):
+		f.c.WarningsAsErrors = append(f.c.WarningsAsErrors, s)
+	default:
+		return fmt.Errorf("unknown warning code: %q", s)
+	}
+	return nil
 }
 
 // ParseCLIArgsGenerate parses CLI arguments for command "generate"
-func ParseCLIArgsGenerate(osArgs []string) (*ConfigGenerate, error) {
+func ParseCLIArgsGenerate(g Global, args []string) (*ConfigGenerate, error) {
+	cli := newFlagSet(g, "generate")
+	finish := flagsGenerate(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish()
+}
+
+// flagsGenerate declares the flags of command "generate" on cli.
+// finish must be called after parsing to validate the arguments.
+func flagsGenerate(cli *flag.FlagSet) (finish func() (*ConfigGenerate, error)) {
 	c := &ConfigGenerate{}
 
 	var locale string
 
-	cli := flag.NewFlagSet(osArgs[0], flag.ExitOnError)
 	cli.StringVar(&locale, "l", "",
 		"default locale of the original source code texts in BCP 47")
 	cli.StringVar(&c.SrcPathPattern, "p", ".", "path to Go module")
@@ -34,18 +247,442 @@ func ParseCLIArgsGenerate(osArgs []string) (*ConfigGenerate, error) {
 	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
 	cli.BoolVar(&c.VerboseMode, "v", false, "enables verbose console logging")
 	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
-		"path to generated Go bundle package relative to module path (-p)")
+		"path to generated Go bundle package inside the module (-p), "+
+			"absolute or relative to the working directory")
+	cli.StringVar(&c.ModulePath, "module-path", "",
+		"path of the module containing the bundle package (-b) "+
+			"for nested modules and vendored layouts")
+	cli.StringVar(&c.ImportPath, "import-path", "",
+		"import path of the bundle package. "+
+			"Set to module path (-module-path) joined with -b relative to its module by default.")
+	cli.DurationVar(&c.LockWait, "lock-wait", 0,
+		"maximum time to wait for a concurrent run to finish (fails fast by default)")
+	cli.DurationVar(&c.LockStaleAfter, "lock-stale", 5*time.Minute,
+		"age after which the bundle lock file is considered stale and removed")
+	flagHeadingCasing(cli, &c.HeadingCasing)
+	var typography string
+	cli.StringVar(&typography, "typography", "",
+		"comma-separated BCP 47 locales of translation catalogs to apply "+
+			"typographic post-processing to in the generated Go bundle, "+
+			"use * for all")
+	cli.Func("blame",
+		"version control system ("+strings.Join(vcs.Names(), ", ")+") "+
+			"used to set the Last-Translator and X-Translated-By-Commit "+
+			"headers of catalogs to the most recent translation change",
+		func(s string) (err error) {
+			c.Blame, err = vcs.ByName(s)
+			return err
+		})
+	cli.BoolVar(&c.Fix, "fix", false,
+		"rewrite the Language header of catalogs not matching the locale "+
+			"of their file name")
+	cli.BoolVar(&c.MessageIDs, "message-ids", false,
+		"assign stable numeric IDs to messages using the messages.lock "+
+			"registry file in the bundle package")
+	cli.Func("track-seen",
+		"record the generator run each message was first and last seen in "+
+			"as X-First-Seen and X-Last-Seen comments, identified by the "+
+			"time of the run (time) or the current revision of a version "+
+			"control system ("+strings.Join(vcs.Names(), ", ")+")",
+		func(s string) (err error) {
+			c.TrackSeen = true
+			if s == "time" {
+				return nil
+			}
+			c.TrackSeenVCS, err = vcs.ByName(s)
+			return err
+		})
+	cli.BoolVar(&c.PluralSamples, "plural-samples", false,
+		"list sample quantities of every plural form of the catalog locale "+
+			"as X-Plural-Sample comments of plural messages in translation catalogs")
+	cli.BoolVar(&c.HashIndex, "hash-index", false,
+		"generate the functions SourceByHash and HashOf in the Go bundle "+
+			"resolving message hashes to source texts and back, such that "+
+			"logs can record message hashes only")
+	cli.BoolVar(&c.IncludeObsolete, "include-obsolete", false,
+		"compile the translations of obsolete messages into the Go bundle "+
+			"such that texts recently removed from the source code, like those "+
+			"requested by long-lived clients of old API versions, "+
+			"stay localized")
+	cli.StringVar(&c.TemplateDir, "template-dir", "",
+		"directory of .gotmpl files customizing the generated Go bundle, "+
+			"which define extension points like \"reader\" adding methods "+
+			"to all reader types or replace it entirely with bundle.gotmpl")
+	cli.BoolVar(&c.SplitFiles, "split-files", false,
+		"split the generated Go bundle into a core file and one file per locale "+
+			"to speed up compilation and tooling for large bundles")
+	cli.BoolVar(&c.CompactReferences, "compact-refs", false,
+		"write all code references of a message on a single \"#:\" line "+
+			"like GNU gettext tools instead of one per line")
+	cli.BoolVar(&c.Strict, "strict", false,
+		"fail if translation catalogs were modified after the Go bundle was "+
+			"last generated instead of warning, for example in CI")
+	cli.BoolVar(&c.Audit, "audit", false,
+		"append the added and obsoleted message counts and the hashes of all "+
+			"written files to the .localize-audit.jsonl audit log in the "+
+			"bundle package")
+	cli.StringVar(&c.SummaryPath, "summary", "",
+		"write a JSON summary of the run to the given file path, like "+
+			"localize-summary.json: new, changed and obsoleted messages per "+
+			"locale, written files and durations of phases")
+	cli.Func("dedent",
+		"default format of Block and PluralBlock texts (preserve or reflow), "+
+			"preserve keeps line breaks, reflow joins the lines of paragraphs",
+		func(s string) (err error) {
+			c.Dedent, err = strfmt.ParseDedentMode(s)
+			return err
+		})
+	cli.Func("normalize",
+		"normalize source texts before hashing (comma-separated: nfc, spaces, "+
+			"or none), nfc converts them to Unicode NFC and spaces collapses "+
+			"runs of spaces, such that visually identical texts are one message",
+		func(s string) (err error) {
+			c.Load.Normalization, err = strfmt.ParseNormalization(s)
+			return err
+		})
+	cli.Func("hash",
+		"hash function identifying messages in catalogs (xxhash64 or fnv128a), "+
+			"fnv128a computes 128-bit hashes for very large code bases. "+
+			"Catalogs created using another hash function are migrated",
+		func(s string) (err error) {
+			c.Load.Hash, err = codeparser.ParseHashFunc(s)
+			return err
+		})
+	cli.StringVar(&c.StatsFormat, "stats-format", "text",
+		"statistics output format (text or json). "+
+			"JSON is printed to stdout even in quiet mode")
+	cli.StringVar(&c.ErrorFormat, "error-format", "text",
+		"source errors output format (text or json). "+
+			"JSON is printed to stdout as an array of objects with "+
+			"file, line, column, code and message")
+	cli.StringVar(&c.Report, "report", "",
+		"write a standalone report of all source code and catalog issues "+
+			"(html), even if the source code contains errors")
+	cli.StringVar(&c.ReportOutPath, "report-o", "localize-report.html",
+		"report output file path")
+	cli.Var(werrorFlag{c}, "Werror",
+		"report all warnings as errors, or only warnings of the given code "+
+			"with -Werror=code ("+strings.Join(codeparser.WarningCodes, ", ")+
+			", can be repeated)")
+	cli.Func("Wignore",
+		"don't report warnings of the given code (can be repeated)",
+		func(s string) error {
+			if !slices.Contains(codeparser.WarningCodes, s) {
+				return fmt.Errorf("unknown warning code: %q", s)
+			}
+			c.WarningsIgnored = append(c.WarningsIgnored, s)
+			return nil
+		})
+	cli.IntVar(&c.Load.BatchSize, "load-batch", 0,
+		"load at most this many packages at a time to reduce memory usage "+
+			"(0 loads all packages at once)")
+	cli.Func("max-memory",
+		"load packages in batches limited to an estimated memory usage "+
+			"like 512MiB or 2GiB",
+		func(s string) (err error) {
+			c.Load.MaxMemory, err = parseByteSize(s)
+			return err
+		})
+	cli.BoolVar(&c.Load.DeriveOne, "derive-one", false,
+		"derive form One of Plural and PluralBlock calls of English source code "+
+			"providing form Other only by singularizing it, like \"%d file\" "+
+			"from \"%d files\". Form One is still required if it can't be derived")
+	c.Resolve = fuzzy.Clear
+	cli.Func("resolve",
+		"resolution of translations of messages whose source text changed at "+
+			"the same code reference (clear, keep, fuzzy or prompt). clear "+
+			"leaves the translation in the obsolete message, keep carries it "+
+			"forward, fuzzy carries it forward flagged for review and prompt "+
+			"asks for every message",
+		func(s string) (err error) {
+			c.Resolve, err = fuzzy.ParseResolution(s)
+			return err
+		})
+	cli.BoolVar(&c.Load.Salvage, "salvage", false,
+		"skip malformed entries of translation catalogs reporting their "+
+			"positions instead of failing, such that a single broken entry "+
+			"doesn't make the whole locale unreadable. Catalogs with skipped "+
+			"entries aren't updated until the entries are fixed")
+	cli.StringVar((*string)(&c.Load.MergeDescriptions), "merge-descriptions", "",
+		"merge calls with identical texts and different descriptions into one "+
+			"message described by all of their descriptions (concat), or report "+
+			"different descriptions as errors (error). "+
+			"By default, they're separate messages")
+	cli.BoolVar(&c.Load.OnlyImporters, "only-importers", false,
+		"only load packages directly or transitively importing "+
+			"github.com/romshark/localize")
+	cli.BoolVar(&c.Profile, "profile", false,
+		"print the time spent parsing, type-checking and inspecting "+
+			"each package to diagnose slow runs. Packages are type-checked "+
+			"twice for timing, which slows the run down")
+	cli.IntVar(&c.ProfileTop, "profile-top", 20,
+		"number of the slowest packages printed by -profile (0 prints all)")
+	cli.StringVar(&c.CPUProfilePath, "cpuprofile", "",
+		"write a pprof CPU profile of the run to the given file path")
+	cli.Func("only",
+		"only extract messages from packages in directories matching the "+
+			"pattern relative to the module path like ./plugins/... "+
+			"(can be repeated), for bundles shipped separately by plugins",
+		func(s string) error {
+			c.Load.Only = append(c.Load.Only, s)
+			return nil
+		})
+	cli.Func("import",
+		"import path of the bundle package of a library whose messages are "+
+			"added to the catalogs (can be repeated)",
+		func(s string) error {
+			c.Imports = append(c.Imports, s)
+			return nil
+		})
+	cli.Func("vendor-catalogs",
+		"directory of read-only catalog.<locale>.po files filling in "+
+			"translations missing in the bundle catalogs (can be repeated)",
+		func(s string) error {
+			c.VendorCatalogs = append(c.VendorCatalogs, s)
+			return nil
+		})
+	cli.Func("term",
+		"name of a term placeholder like {name} replaced at runtime that texts "+
+			"may use (can be repeated), placeholders of other names are errors",
+		func(s string) error {
+			c.Terms = append(c.Terms, s)
+			return nil
+		})
+	cli.IntVar(&c.Limits.MaxMessageLen, "max-message-len", 0,
+		"maximum length of message texts and their translations "+
+			"in the unit of -max-message-len-unit (0 disables the limit)")
+	c.Limits.Unit = limits.UnitBytes
+	cli.Func("max-message-len-unit",
+		"unit of -max-message-len (bytes, graphemes or width), graphemes "+
+			"counts user-perceived characters and width counts columns with "+
+			"East Asian wide characters and emoji as two (default bytes)",
+		func(s string) (err error) {
+			c.Limits.Unit, err = limits.ParseUnit(s)
+			return err
+		})
+	cli.IntVar(&c.Limits.MaxMessages, "max-messages", 0,
+		"maximum number of messages per catalog file (0 disables the limit)")
+	cli.Func("max-catalog-size",
+		"maximum size of catalog files like 512KiB or 4MiB",
+		func(s string) (err error) {
+			c.Limits.MaxCatalogSize, err = parseByteSize(s)
+			return err
+		})
+	cli.BoolVar(&c.LimitsWarn, "limits-warn", false,
+		"report exceeded limits (-max-message-len, -max-messages, "+
+			"-max-catalog-size) as warnings instead of errors")
+	cli.Func("plural-override",
+		"merge CLDR plural forms of a locale in the format "+
+			"locale:form=into[,form=into] like ru:few=other (can be repeated)",
+		func(s string) error {
+			o, err := parsePluralOverride(s)
+			if err != nil {
+				return err
+			}
+			c.PluralOverrides = append(c.PluralOverrides, o)
+			return nil
+		})
+	flagPluralFallback(cli, &c.PluralFallback)
+	var splitPOT string
+	cli.StringVar(&splitPOT, "split-pot", "",
+		"split catalogs into one template per domain. "+
+			"Set to \"package\" to use top-level directories as domains")
+	cli.Func("domain",
+		"assign messages referenced in files with the given path prefix "+
+			"relative to the module to a domain in the format "+
+			"name=pathprefix (can be repeated)",
+		func(s string) error {
+			name, prefix, ok := strings.Cut(s, "=")
+			if !ok || prefix == "" {
+				return fmt.Errorf("expected format name=pathprefix, received: %q", s)
+			}
+			if !domain.ValidName(name) {
+				return fmt.Errorf("invalid domain name: %q", name)
+			}
+			c.Domains.Mappings = append(c.Domains.Mappings, domain.Mapping{
+				Name: name, PathPrefix: filepath.ToSlash(prefix),
+			})
+			return nil
+		})
+	cli.Func("plugin",
+		"run the output plugin executable localize-gen-<name> "+
+			"(or the executable at path <name>) and write its files to "+
+			"directory <dir> in the format name=dir (can be repeated)",
+		func(s string) error {
+			name, dir, ok := strings.Cut(s, "=")
+			if !ok || name == "" || dir == "" {
+				return fmt.Errorf("expected format name=dir, received: %q", s)
+			}
+			c.Plugins = append(c.Plugins, Plugin{Name: name, OutDir: dir})
+			return nil
+		})
+	cli.Func("post-generate",
+		"run the command after all files are written passing the JSON summary "+
+			"of the run (see -summary) to its stdin, like uploading the catalog "+
+			"template to a translation management system; the command is split "+
+			"into the executable and its arguments at white space "+
+			"(can be repeated)",
+		func(s string) error {
+			command := strings.Fields(s)
+			if len(command) == 0 {
+				return fmt.Errorf("expected a command, received: %q", s)
+			}
+			c.PostGenerate = append(c.PostGenerate, command)
+			return nil
+		})
+
+	return func() (*ConfigGenerate, error) {
+		return c.finish(locale, typography, splitPOT)
+	}
+}
+
+// checkBundleInModule returns ErrBundleOutsideModule if the bundle package
+// directory bundleDir isn't inside the module containing srcPathPattern,
+// including nested modules. Both are relative to the working directory.
+func checkBundleInModule(srcPathPattern, bundleDir string) error {
+	src, err := filepath.Abs(strings.TrimSuffix(filepath.ToSlash(srcPathPattern), "/..."))
+	if err != nil {
+		return err
+	}
+	root, ok := FindModuleRoot(src)
+	if !ok {
+		return nil // Reported when loading the packages.
+	}
+	dir, err := filepath.Abs(bundleDir)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(root, dir); err != nil || !filepath.IsLocal(rel) {
+		return fmt.Errorf("%w: bundle package %s (-b) isn't inside module %s (-p), "+
+			"provide the import path of bundles in other modules (-import-path)",
+			ErrBundleOutsideModule, dir, root)
+	}
+	return nil
+}
 
-	if err := cli.Parse(osArgs[2:]); err != nil {
-		return nil, fmt.Errorf("parsing: %w", err)
+// moduleRelPath returns the slash-separated path of directory dir relative
+// to the root of the module containing it, or dir if there is no module.
+func moduleRelPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	root, ok := FindModuleRoot(abs)
+	if !ok {
+		return filepath.ToSlash(dir), nil
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", err
 	}
+	return filepath.ToSlash(rel), nil
+}
 
+func (c *ConfigGenerate) finish(
+	locale, typography, splitPOT string,
+) (*ConfigGenerate, error) {
 	if c.OutPathCatalogTemplate == "" {
 		c.OutPathCatalogTemplate = catalogTemplateFileName(
 			c.BundlePkgPath,
 		)
 	}
 
+	if c.ImportPath == "" && c.ModulePath == "" {
+		// The bundle package is detected by its directory.
+		if err := checkBundleInModule(c.SrcPathPattern, c.BundlePkgPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.ModulePath != "" {
+		if err := module.CheckImportPath(c.ModulePath); err != nil {
+			return nil, fmt.Errorf(
+				"argument 'module-path' (%q) must be a valid module path: %w",
+				c.ModulePath, err,
+			)
+		}
+		if c.ImportPath == "" {
+			rel, err := moduleRelPath(c.BundlePkgPath)
+			if err != nil {
+				return nil, err
+			}
+			c.ImportPath = path.Join(c.ModulePath, rel)
+		}
+	}
+	if c.ImportPath != "" {
+		if err := module.CheckImportPath(c.ImportPath); err != nil {
+			return nil, fmt.Errorf(
+				"argument 'import-path' (%q) must be a valid import path: %w",
+				c.ImportPath, err,
+			)
+		}
+		c.PackageName = path.Base(c.ImportPath)
+	} else {
+		c.PackageName = filepath.Base(c.BundlePkgPath)
+	}
+	if !token.IsIdentifier(c.PackageName) {
+		return nil, fmt.Errorf(
+			"bundle package name (%q) must be a valid Go identifier, "+
+				"use a different bundle package path (-b) or import path (-import-path)",
+			c.PackageName,
+		)
+	}
+
+	switch c.StatsFormat {
+	case "text", "json":
+	default:
+		return nil, fmt.Errorf(
+			"argument 'stats-format' (%q) must be either text or json",
+			c.StatsFormat,
+		)
+	}
+
+	switch c.ErrorFormat {
+	case "text", "json":
+	default:
+		return nil, fmt.Errorf(
+			"argument 'error-format' (%q) must be either text or json",
+			c.ErrorFormat,
+		)
+	}
+
+	switch c.Report {
+	case "", "html":
+	default:
+		return nil, fmt.Errorf(
+			"argument 'report' (%q) must be either empty or html", c.Report,
+		)
+	}
+
+	if c.Limits.MaxMessageLen < 0 {
+		return nil, fmt.Errorf(
+			"argument 'max-message-len' (%d) must not be negative",
+			c.Limits.MaxMessageLen,
+		)
+	}
+	if c.Limits.MaxMessages < 0 {
+		return nil, fmt.Errorf(
+			"argument 'max-messages' (%d) must not be negative",
+			c.Limits.MaxMessages,
+		)
+	}
+	if c.ProfileTop < 0 {
+		return nil, fmt.Errorf(
+			"argument 'profile-top' (%d) must not be negative", c.ProfileTop,
+		)
+	}
+
+	switch splitPOT {
+	case "":
+	case "package":
+		c.Domains.ByPackage = true
+	default:
+		return nil, fmt.Errorf(
+			"argument 'split-pot' (%q) must be either empty or \"package\"",
+			splitPOT,
+		)
+	}
+
 	if locale == "" {
 		return nil, fmt.Errorf(
 			"please provide a valid BCP 47 locale for " +
@@ -60,10 +697,1326 @@ func ParseCLIArgsGenerate(osArgs []string) (*ConfigGenerate, error) {
 			"argument 'l' (%q) must be a valid BCP 47 locale: %w", locale, err,
 		)
 	}
+	if base, _ := c.Locale.Base(); c.Load.DeriveOne && base.String() != "en" {
+		return nil, fmt.Errorf(
+			"argument 'derive-one' requires an English source locale (-l), "+
+				"received: %q", locale,
+		)
+	}
+
+	switch c.Load.MergeDescriptions {
+	case codeparser.DescriptionMergeNone, codeparser.DescriptionMergeConcat,
+		codeparser.DescriptionMergeError:
+	default:
+		return nil, fmt.Errorf(
+			"argument 'merge-descriptions' (%q) must be either empty, concat or error",
+			c.Load.MergeDescriptions,
+		)
+	}
+
+	if c.Typography, c.TypographyAll, err = parseTypography(typography); err != nil {
+		return nil, err
+	}
 
 	return c, nil
 }
 
+// parseTypography parses the value of flag "typography", which is either
+// "*" for all locales or a comma-separated list of locales.
+func parseTypography(s string) (locales []language.Tag, all bool, err error) {
+	if s == "*" {
+		return nil, true, nil
+	}
+	if s == "" {
+		return nil, false, nil
+	}
+	for l := range strings.SplitSeq(s, ",") {
+		t, err := language.Parse(strings.TrimSpace(l))
+		if err != nil {
+			return nil, false, fmt.Errorf(
+				"argument 'typography' (%q) must be a list of valid "+
+					"BCP 47 locales: %w", s, err,
+			)
+		}
+		locales = append(locales, t)
+	}
+	return locales, false, nil
+}
+
+// parseByteSize parses sizes like "1024", "512KiB", "512MiB" or "2GiB".
+func parseByteSize(s string) (int64, error) {
+	multiplier := int64(1)
+	for _, u := range []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"KiB", 1 << 10},
+		{"MiB", 1 << 20},
+		{"GiB", 1 << 30},
+		{"KB", 1e3},
+		{"MB", 1e6},
+		{"GB", 1e9},
+	} {
+		if v, ok := strings.CutSuffix(s, u.suffix); ok {
+			s, multiplier = v, u.multiplier
+			break
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid size: %q", s)
+	}
+	return n * multiplier, nil
+}
+
+func parsePluralOverride(s string) (cldr.Override, error) {
+	localeStr, merges, ok := strings.Cut(s, ":")
+	if !ok || merges == "" {
+		return cldr.Override{}, fmt.Errorf(
+			"expected format locale:form=into[,form=into], received: %q", s,
+		)
+	}
+	locale, err := language.Parse(localeStr)
+	if err != nil {
+		return cldr.Override{}, fmt.Errorf(
+			"invalid BCP 47 locale (%q): %w", localeStr, err,
+		)
+	}
+	o := cldr.Override{
+		Locale: locale,
+		Merge:  map[cldr.CLDRPluralForm]cldr.CLDRPluralForm{},
+	}
+	for m := range strings.SplitSeq(merges, ",") {
+		fromStr, toStr, ok := strings.Cut(strings.TrimSpace(m), "=")
+		if !ok {
+			return cldr.Override{}, fmt.Errorf(
+				"expected format form=into, received: %q", m,
+			)
+		}
+		from, err := cldr.ParsePluralForm(fromStr)
+		if err != nil {
+			return cldr.Override{}, err
+		}
+		to, err := cldr.ParsePluralForm(toStr)
+		if err != nil {
+			return cldr.Override{}, err
+		}
+		o.Merge[from] = to
+	}
+	return o, nil
+}
+
 func catalogTemplateFileName(outPath string) string {
 	return filepath.Join(outPath, "catalog.pot")
 }
+
+type ConfigDocs struct {
+	BundlePkgPath string
+	OutPath       string
+	Format        string
+	QuietMode     bool
+
+	// PluralFallback is the same as ConfigGenerate.PluralFallback.
+	PluralFallback language.Tag
+
+	// Edition limits the documentation to the messages of an edition
+	// if not empty.
+	Edition string
+
+	// Region limits the documentation to the messages of a region
+	// if not zero.
+	Region language.Region
+}
+
+// ParseCLIArgsDocs parses CLI arguments for command "docs"
+func ParseCLIArgsDocs(g Global, args []string) (*ConfigDocs, error) {
+	cli := newFlagSet(g, "docs")
+	finish := flagsDocs(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish()
+}
+
+// flagsDocs declares the flags of command "docs" on cli.
+// finish must be called after parsing to validate the arguments.
+func flagsDocs(cli *flag.FlagSet) (finish func() (*ConfigDocs, error)) {
+	c := &ConfigDocs{}
+
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to generated Go bundle package")
+	cli.StringVar(&c.OutPath, "o", "docs", "documentation output directory path")
+	cli.StringVar(&c.Format, "f", "html", "output format (html or markdown)")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+	flagPluralFallback(cli, &c.PluralFallback)
+	flagEdition(cli, &c.Edition)
+	flagRegion(cli, &c.Region)
+
+	return c.finish
+}
+
+// flagHeadingCasing declares flag "heading-casing" on cli.
+func flagHeadingCasing(
+	cli *flag.FlagSet, casings *map[language.Tag]typography.Casing,
+) {
+	cli.Func("heading-casing",
+		"comma-separated locale=casing pairs like en=title,fr=sentence "+
+			"applied to the translations of headings in the generated Go bundle "+
+			"(none, title or sentence)",
+		func(s string) error {
+			m := map[language.Tag]typography.Casing{}
+			for p := range strings.SplitSeq(s, ",") {
+				l, c, ok := strings.Cut(strings.TrimSpace(p), "=")
+				if !ok {
+					return fmt.Errorf("%q isn't a locale=casing pair", p)
+				}
+				tag, err := language.Parse(l)
+				if err != nil {
+					return err
+				}
+				if m[tag], ok = typography.ParseCasing(c); !ok {
+					return fmt.Errorf(
+						"casing %q must be either none, title or sentence", c,
+					)
+				}
+			}
+			*casings = m
+			return nil
+		})
+}
+
+// flagPluralFallback declares flag "plural-fallback" on cli.
+func flagPluralFallback(cli *flag.FlagSet, fallback *language.Tag) {
+	cli.Func("plural-fallback",
+		"BCP 47 locale whose plural forms are used for catalogs of locales "+
+			"without CLDR data (form Other only by default)",
+		func(s string) (err error) {
+			*fallback, err = language.Parse(s)
+			return err
+		})
+}
+
+// flagEdition declares flag "edition" on cli.
+func flagEdition(cli *flag.FlagSet, name *string) {
+	cli.Func("edition",
+		"only include messages of the given edition and messages without editions",
+		func(s string) error {
+			*name = s
+			return edition.CheckName(s)
+		})
+}
+
+// flagRegion declares flag "region" on cli.
+func flagRegion(cli *flag.FlagSet, r *language.Region) {
+	cli.Func("region",
+		"only include messages of the given region subtag "+
+			"and messages without regions",
+		func(s string) (err error) {
+			*r, err = region.Parse(s)
+			return err
+		})
+}
+
+func (c *ConfigDocs) finish() (*ConfigDocs, error) {
+	switch c.Format {
+	case "html", "markdown":
+	default:
+		return nil, fmt.Errorf(
+			"argument 'f' (%q) must be either html or markdown", c.Format,
+		)
+	}
+
+	return c, nil
+}
+
+type ConfigBadge struct {
+	BundlePkgPath string
+	Locale        language.Tag
+	Label         string
+	OutPath       string
+	Format        string
+	QuietMode     bool
+
+	// Edition limits the coverage to the messages of an edition if not empty.
+	Edition string
+
+	// Region limits the coverage to the messages of a region if not zero.
+	Region language.Region
+}
+
+// ParseCLIArgsBadge parses CLI arguments for command "badge"
+func ParseCLIArgsBadge(g Global, args []string) (*ConfigBadge, error) {
+	cli := newFlagSet(g, "badge")
+	finish := flagsBadge(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish()
+}
+
+// flagsBadge declares the flags of command "badge" on cli.
+// finish must be called after parsing to validate the arguments.
+func flagsBadge(cli *flag.FlagSet) (finish func() (*ConfigBadge, error)) {
+	c := &ConfigBadge{}
+
+	var locale string
+
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to generated Go bundle package")
+	cli.StringVar(&locale, "locale", "", "BCP 47 locale of the catalog")
+	cli.StringVar(&c.Label, "label", "", "badge label. Set to locale by default.")
+	cli.StringVar(&c.OutPath, "o", "", "output file path. Set to stdout by default.")
+	cli.StringVar(&c.Format, "f", "svg",
+		"output format (svg or json for shields.io endpoint badges)")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+	flagEdition(cli, &c.Edition)
+	flagRegion(cli, &c.Region)
+
+	return func() (*ConfigBadge, error) { return c.finish(locale) }
+}
+
+func (c *ConfigBadge) finish(locale string) (*ConfigBadge, error) {
+	if locale == "" {
+		return nil, fmt.Errorf(
+			"please provide a valid BCP 47 locale of the catalog " +
+				"using the 'locale' parameter",
+		)
+	}
+	var err error
+	c.Locale, err = language.Parse(locale)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"argument 'locale' (%q) must be a valid BCP 47 locale: %w", locale, err,
+		)
+	}
+	if c.Label == "" {
+		c.Label = c.Locale.String()
+	}
+
+	switch c.Format {
+	case "svg", "json":
+	default:
+		return nil, fmt.Errorf(
+			"argument 'f' (%q) must be either svg or json", c.Format,
+		)
+	}
+
+	return c, nil
+}
+
+type ConfigReleaseCheck struct {
+	BundlePkgPath string
+
+	// Since is the git revision of the previous release, such as a tag.
+	Since string
+
+	// Locales are the release-critical locales whose catalogs must translate
+	// all messages added since the previous release.
+	// Set to the locales of all translation catalogs if empty.
+	Locales []language.Tag
+}
+
+// ParseCLIArgsReleaseCheck parses CLI arguments for command "release-check"
+func ParseCLIArgsReleaseCheck(g Global, args []string) (*ConfigReleaseCheck, error) {
+	cli := newFlagSet(g, "release-check")
+	finish := flagsReleaseCheck(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish()
+}
+
+// flagsReleaseCheck declares the flags of command "release-check" on cli.
+// finish must be called after parsing to validate the arguments.
+func flagsReleaseCheck(
+	cli *flag.FlagSet,
+) (finish func() (*ConfigReleaseCheck, error)) {
+	c := &ConfigReleaseCheck{}
+
+	var locales string
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to generated Go bundle package")
+	cli.StringVar(&c.Since, "since", "",
+		"git revision of the previous release, such as tag v1.4.0")
+	cli.StringVar(&locales, "locales", "",
+		"comma-separated BCP 47 release-critical locales. "+
+			"Set to the locales of all translation catalogs by default.")
+
+	return func() (*ConfigReleaseCheck, error) { return c.finish(locales) }
+}
+
+func (c *ConfigReleaseCheck) finish(locales string) (*ConfigReleaseCheck, error) {
+	if c.Since == "" {
+		return nil, fmt.Errorf(
+			"please provide the revision of the previous release " +
+				"using the 'since' parameter",
+		)
+	}
+	if locales == "" {
+		return c, nil
+	}
+	for s := range strings.SplitSeq(locales, ",") {
+		t, err := language.Parse(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf(
+				"argument 'locales' (%q) must be a list of valid "+
+					"BCP 47 locales: %w", locales, err,
+			)
+		}
+		c.Locales = append(c.Locales, t)
+	}
+	return c, nil
+}
+
+type ConfigExportState struct {
+	BundlePkgPath string
+	OutPath       string
+	QuietMode     bool
+
+	// PluralFallback is the same as ConfigGenerate.PluralFallback.
+	PluralFallback language.Tag
+}
+
+// ParseCLIArgsExportState parses CLI arguments for command "export-state"
+func ParseCLIArgsExportState(g Global, args []string) (*ConfigExportState, error) {
+	cli := newFlagSet(g, "export-state")
+	c := flagsExportState(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// flagsExportState declares the flags of command "export-state" on cli.
+func flagsExportState(cli *flag.FlagSet) *ConfigExportState {
+	c := &ConfigExportState{}
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to generated Go bundle package")
+	cli.StringVar(&c.OutPath, "o", "", "output file path. Set to stdout by default.")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+	flagPluralFallback(cli, &c.PluralFallback)
+	return c
+}
+
+type ConfigExportUntranslated struct {
+	BundlePkgPath string
+	Locale        language.Tag
+	OutPath       string
+	Tokenize      bool
+	QuietMode     bool
+}
+
+// ParseCLIArgsExportUntranslated parses CLI arguments
+// for command "export-untranslated"
+func ParseCLIArgsExportUntranslated(
+	g Global, args []string,
+) (*ConfigExportUntranslated, error) {
+	cli := newFlagSet(g, "export-untranslated")
+	finish := flagsExportUntranslated(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish()
+}
+
+// flagsExportUntranslated declares the flags of command
+// "export-untranslated" on cli.
+// finish must be called after parsing to validate the arguments.
+func flagsExportUntranslated(
+	cli *flag.FlagSet,
+) (finish func() (*ConfigExportUntranslated, error)) {
+	c := &ConfigExportUntranslated{}
+
+	var locale string
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to generated Go bundle package")
+	cli.StringVar(&locale, "locale", "", "BCP 47 locale of the catalog")
+	cli.StringVar(&c.OutPath, "o", "", "output file path. Set to stdout by default.")
+	cli.BoolVar(&c.Tokenize, "tokenize", false,
+		"replace placeholders with protected tokens like ⟦1⟧ "+
+			"for machine translation and CAT tools")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+
+	return func() (*ConfigExportUntranslated, error) {
+		var err error
+		c.Locale, err = parseCatalogLocale(locale)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+type ConfigImportUntranslated struct {
+	BundlePkgPath string
+	Locale        language.Tag
+	QuietMode     bool
+
+	// InPath is the path of the catalog written by export-untranslated
+	// and translated since.
+	InPath string
+}
+
+// ParseCLIArgsImportUntranslated parses CLI arguments
+// for command "import-untranslated"
+func ParseCLIArgsImportUntranslated(
+	g Global, args []string,
+) (*ConfigImportUntranslated, error) {
+	cli := newFlagSet(g, "import-untranslated")
+	finish := flagsImportUntranslated(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsImportUntranslated declares the flags of command
+// "import-untranslated" on cli.
+// finish must be called with the positional arguments after parsing
+// to validate the arguments.
+func flagsImportUntranslated(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigImportUntranslated, error)) {
+	c := &ConfigImportUntranslated{}
+
+	var locale string
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to generated Go bundle package")
+	cli.StringVar(&locale, "locale", "", "BCP 47 locale of the catalog")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+
+	return func(args []string) (*ConfigImportUntranslated, error) {
+		if len(args) != 1 || args[0] == "" {
+			return nil, fmt.Errorf(
+				"please provide exactly one translated catalog to import",
+			)
+		}
+		c.InPath = args[0]
+		var err error
+		c.Locale, err = parseCatalogLocale(locale)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+type ConfigExtractUnit struct {
+	// UnitPath is the path of the unit JSON file (see codeparser.Unit).
+	UnitPath string
+
+	// Locale is the same as ConfigGenerate.Locale.
+	Locale    language.Tag
+	OutPath   string
+	TrimPath  bool
+	QuietMode bool
+
+	// Dedent is the same as ConfigGenerate.Dedent.
+	Dedent strfmt.DedentMode
+
+	// DeriveOne, Normalization and Hash are the same as in ConfigGenerate.Load.
+	DeriveOne     bool
+	Normalization strfmt.Normalization
+	Hash          codeparser.HashFunc
+}
+
+// ParseCLIArgsExtractUnit parses CLI arguments for command "extract-unit"
+func ParseCLIArgsExtractUnit(g Global, args []string) (*ConfigExtractUnit, error) {
+	cli := newFlagSet(g, "extract-unit")
+	finish := flagsExtractUnit(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsExtractUnit declares the flags of command "extract-unit" on cli.
+// finish must be called with the positional arguments after parsing
+// to validate the arguments.
+func flagsExtractUnit(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigExtractUnit, error)) {
+	c := &ConfigExtractUnit{}
+
+	var locale string
+	cli.StringVar(&locale, "l", "",
+		"default locale of the original source code texts in BCP 47")
+	cli.StringVar(&c.OutPath, "o", "", "output file path. Set to stdout by default.")
+	cli.BoolVar(&c.TrimPath, "trimpath", true,
+		"enable source code path trimming relative to the directory of the unit")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+	cli.Func("dedent",
+		"default format of Block and PluralBlock texts (preserve or reflow)",
+		func(s string) (err error) {
+			c.Dedent, err = strfmt.ParseDedentMode(s)
+			return err
+		})
+	cli.Func("normalize",
+		"normalize source texts before hashing (comma-separated: nfc, spaces, "+
+			"or none), must match the normalization of generate",
+		func(s string) (err error) {
+			c.Normalization, err = strfmt.ParseNormalization(s)
+			return err
+		})
+	cli.BoolVar(&c.DeriveOne, "derive-one", false,
+		"derive form One of Plural and PluralBlock calls of English source code "+
+			"from form Other, must match -derive-one of generate")
+	cli.Func("hash",
+		"hash function identifying messages (xxhash64 or fnv128a), "+
+			"must match -hash of generate",
+		func(s string) (err error) {
+			c.Hash, err = codeparser.ParseHashFunc(s)
+			return err
+		})
+
+	return func(args []string) (*ConfigExtractUnit, error) {
+		if len(args) != 1 || args[0] == "" {
+			return nil, fmt.Errorf("please provide exactly one unit file")
+		}
+		c.UnitPath = args[0]
+		if locale == "" {
+			return nil, fmt.Errorf(
+				"please provide a valid BCP 47 locale for " +
+					"the default language of your original code base " +
+					"using the 'l' parameter",
+			)
+		}
+		var err error
+		c.Locale, err = language.Parse(locale)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"argument 'l' (%q) must be a valid BCP 47 locale: %w", locale, err,
+			)
+		}
+		if base, _ := c.Locale.Base(); c.DeriveOne && base.String() != "en" {
+			return nil, fmt.Errorf(
+				"argument 'derive-one' requires an English source locale (-l), "+
+					"received: %q", locale,
+			)
+		}
+		return c, nil
+	}
+}
+
+// parseCatalogLocale parses the required 'locale' parameter.
+func parseCatalogLocale(locale string) (language.Tag, error) {
+	if locale == "" {
+		return language.Tag{}, fmt.Errorf(
+			"please provide a valid BCP 47 locale of the catalog " +
+				"using the 'locale' parameter",
+		)
+	}
+	t, err := language.Parse(locale)
+	if err != nil {
+		return language.Tag{}, fmt.Errorf(
+			"argument 'locale' (%q) must be a valid BCP 47 locale: %w", locale, err,
+		)
+	}
+	return t, nil
+}
+
+type ConfigImportGoI18n struct {
+	// Locale is the locale of the source code texts, whose go-i18n
+	// message file provides the source texts of all messages.
+	Locale        language.Tag
+	BundlePkgPath string
+	QuietMode     bool
+
+	// OutPath is the file path the suggested code rewrites are written to.
+	// The rewrites are written to stdout if empty.
+	OutPath string
+
+	// Files are the paths of the go-i18n message files.
+	Files []string
+}
+
+// ParseCLIArgsImportGoI18n parses CLI arguments for command "import-go-i18n"
+func ParseCLIArgsImportGoI18n(g Global, args []string) (*ConfigImportGoI18n, error) {
+	cli := newFlagSet(g, "import-go-i18n")
+	finish := flagsImportGoI18n(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsImportGoI18n declares the flags of command "import-go-i18n" on cli.
+// finish must be called with the positional arguments after parsing
+// to validate the arguments.
+func flagsImportGoI18n(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigImportGoI18n, error)) {
+	c := &ConfigImportGoI18n{}
+
+	var locale string
+
+	cli.StringVar(&locale, "l", "",
+		"default locale of the original source code texts in BCP 47")
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to the Go bundle package the translation catalogs are written to")
+	cli.StringVar(&c.OutPath, "o", "",
+		"output file path of the suggested code rewrites. "+
+			"Set to stdout by default.")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+
+	return func(args []string) (*ConfigImportGoI18n, error) {
+		return c.finish(locale, args)
+	}
+}
+
+func (c *ConfigImportGoI18n) finish(
+	locale string, args []string,
+) (*ConfigImportGoI18n, error) {
+	if locale == "" {
+		return nil, fmt.Errorf(
+			"please provide a valid BCP 47 locale for " +
+				"the default language of your original code base " +
+				"using the 'l' parameter",
+		)
+	}
+	var err error
+	c.Locale, err = language.Parse(locale)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"argument 'l' (%q) must be a valid BCP 47 locale: %w", locale, err,
+		)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("please provide at least one go-i18n message file")
+	}
+	c.Files = args
+	return c, nil
+}
+
+type ConfigImportXText struct {
+	// Locale is the locale of the source code texts, whose gotext catalog
+	// provides the source texts of all messages.
+	Locale         language.Tag
+	SrcPathPattern string
+	BundlePkgPath  string
+	QuietMode      bool
+
+	// OutPath is the file path the report of call sites to convert
+	// is written to. The report is written to stdout if empty.
+	OutPath string
+
+	// Files are the paths of the gotext JSON catalogs.
+	Files []string
+}
+
+// ParseCLIArgsImportXText parses CLI arguments for command "import-x-text"
+func ParseCLIArgsImportXText(g Global, args []string) (*ConfigImportXText, error) {
+	cli := newFlagSet(g, "import-x-text")
+	finish := flagsImportXText(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsImportXText declares the flags of command "import-x-text" on cli.
+// finish must be called with the positional arguments after parsing
+// to validate the arguments.
+func flagsImportXText(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigImportXText, error)) {
+	c := &ConfigImportXText{}
+
+	var locale string
+
+	cli.StringVar(&locale, "l", "",
+		"default locale of the original source code texts in BCP 47")
+	cli.StringVar(&c.SrcPathPattern, "p", ".", "path to Go module")
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to the Go bundle package the translation catalogs are written to")
+	cli.StringVar(&c.OutPath, "o", "",
+		"output file path of the report of call sites to convert. "+
+			"Set to stdout by default.")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+
+	return func(args []string) (*ConfigImportXText, error) {
+		return c.finish(locale, args)
+	}
+}
+
+func (c *ConfigImportXText) finish(
+	locale string, args []string,
+) (*ConfigImportXText, error) {
+	if locale == "" {
+		return nil, fmt.Errorf(
+			"please provide a valid BCP 47 locale for " +
+				"the default language of your original code base " +
+				"using the 'l' parameter",
+		)
+	}
+	var err error
+	c.Locale, err = language.Parse(locale)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"argument 'l' (%q) must be a valid BCP 47 locale: %w", locale, err,
+		)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("please provide at least one gotext catalog file")
+	}
+	c.Files = args
+	return c, nil
+}
+
+type ConfigMigrateStrings struct {
+	// PkgPattern is the pattern of the packages whose strings are rewritten.
+	PkgPattern string
+
+	// ReaderName is the name of the Reader parameters inserted into
+	// functions without a Reader in scope.
+	ReaderName string
+
+	// AllowlistPath is the path of the file listing the texts to rewrite,
+	// one per line. Strings looking like human readable text are rewritten
+	// if empty.
+	AllowlistPath string
+
+	// Interactive enables confirming every string before rewriting it.
+	Interactive bool
+
+	// DryRun disables writing files and lists the strings to rewrite instead.
+	DryRun    bool
+	QuietMode bool
+}
+
+// ParseCLIArgsMigrateStrings parses CLI arguments for command "migrate-strings"
+func ParseCLIArgsMigrateStrings(g Global, args []string) (*ConfigMigrateStrings, error) {
+	cli := newFlagSet(g, "migrate-strings")
+	c := flagsMigrateStrings(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	if !token.IsIdentifier(c.ReaderName) {
+		return nil, fmt.Errorf(
+			"argument 'r' (%q) must be a valid Go identifier", c.ReaderName,
+		)
+	}
+	return c, nil
+}
+
+// flagsMigrateStrings declares the flags of command "migrate-strings" on cli.
+func flagsMigrateStrings(cli *flag.FlagSet) *ConfigMigrateStrings {
+	c := &ConfigMigrateStrings{}
+	cli.StringVar(&c.PkgPattern, "package", ".",
+		"pattern of the packages whose strings are rewritten")
+	cli.StringVar(&c.ReaderName, "r", "l",
+		"name of the Reader parameters inserted into functions")
+	cli.StringVar(&c.AllowlistPath, "allow", "",
+		"path to a file listing the texts to rewrite, one per line. "+
+			"Strings looking like human readable text are rewritten by default.")
+	cli.BoolVar(&c.Interactive, "i", false, "confirm every string before rewriting it")
+	cli.BoolVar(&c.DryRun, "n", false,
+		"list the strings to rewrite without changing any files")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+	return c
+}
+
+type ConfigWhereis struct {
+	BundlePkgPath string
+	Text          string
+	Format        string
+
+	// Locale limits the search to the catalog of a locale if not empty.
+	Locale string
+
+	// PluralFallback is the same as ConfigGenerate.PluralFallback.
+	PluralFallback language.Tag
+}
+
+// ParseCLIArgsWhereis parses CLI arguments for command "whereis"
+func ParseCLIArgsWhereis(g Global, args []string) (*ConfigWhereis, error) {
+	cli := newFlagSet(g, "whereis")
+	finish := flagsWhereis(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsWhereis declares the flags of command "whereis" on cli.
+// finish must be called with the positional arguments after parsing
+// to validate the arguments.
+func flagsWhereis(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigWhereis, error)) {
+	c := &ConfigWhereis{}
+
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to generated Go bundle package")
+	cli.StringVar(&c.Locale, "locale", "",
+		"BCP 47 locale of the catalog to search. Set to all catalogs by default.")
+	cli.StringVar(&c.Format, "f", "text", "output format (text or json)")
+	flagPluralFallback(cli, &c.PluralFallback)
+
+	return c.finish
+}
+
+func (c *ConfigWhereis) finish(args []string) (*ConfigWhereis, error) {
+	if len(args) != 1 || strings.TrimSpace(args[0]) == "" {
+		return nil, fmt.Errorf("please provide exactly one text to search for")
+	}
+	c.Text = args[0]
+
+	if c.Locale != "" {
+		locale, err := language.Parse(c.Locale)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"argument 'locale' (%q) must be a valid BCP 47 locale: %w",
+				c.Locale, err,
+			)
+		}
+		c.Locale = locale.String()
+	}
+
+	switch c.Format {
+	case "text", "json":
+	default:
+		return nil, fmt.Errorf(
+			"argument 'f' (%q) must be either text or json", c.Format,
+		)
+	}
+
+	return c, nil
+}
+
+type ConfigDedup struct {
+	// InPath is the path of the .po or .pot file to deduplicate.
+	InPath string
+
+	// OutPath is the output file path, the result is written to stdout if empty.
+	OutPath string
+}
+
+// ParseCLIArgsDedup parses CLI arguments for command "dedup"
+func ParseCLIArgsDedup(g Global, args []string) (*ConfigDedup, error) {
+	cli := newFlagSet(g, "dedup")
+	finish := flagsDedup(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsDedup declares the flags of command "dedup" on cli.
+// finish must be called with the positional arguments after parsing
+// to validate the arguments.
+func flagsDedup(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigDedup, error)) {
+	c := &ConfigDedup{}
+
+	cli.StringVar(&c.OutPath, "o", "",
+		"output file path, which may be the input file. "+
+			"Written to stdout by default.")
+
+	return c.finish
+}
+
+func (c *ConfigDedup) finish(args []string) (*ConfigDedup, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("please provide exactly one .po or .pot file")
+	}
+	c.InPath = args[0]
+	if err := checkGettextExt(c.InPath); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// checkGettextExt returns an error if path doesn't have extension .po or .pot.
+func checkGettextExt(path string) error {
+	switch filepath.Ext(path) {
+	case ".po", ".pot":
+		return nil
+	}
+	return fmt.Errorf("file %q must have extension .po or .pot", path)
+}
+
+type ConfigCheck struct {
+	// Paths are the paths of the .po and .pot files to check.
+	Paths []string
+}
+
+// ParseCLIArgsCheck parses CLI arguments for command "check"
+func ParseCLIArgsCheck(g Global, args []string) (*ConfigCheck, error) {
+	cli := newFlagSet(g, "check")
+	finish := flagsCheck(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsCheck declares the flags of command "check" on cli.
+// finish must be called with the positional arguments after parsing
+// to validate the arguments.
+func flagsCheck(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigCheck, error)) {
+	c := &ConfigCheck{}
+	return func(args []string) (*ConfigCheck, error) {
+		if len(args) < 1 {
+			return nil, fmt.Errorf("please provide at least one .po or .pot file")
+		}
+		for _, p := range args {
+			if err := checkGettextExt(p); err != nil {
+				return nil, err
+			}
+		}
+		c.Paths = args
+		return c, nil
+	}
+}
+
+type ConfigFmt struct {
+	// Paths are the paths of the .po and .pot files to format.
+	Paths []string
+
+	// Write writes the formatted files back instead of to stdout.
+	Write bool
+
+	// List lists the files whose formatting differs instead of
+	// printing them.
+	List bool
+
+	// Wrap and SortHeaders are the same as in gettext.Encoder.
+	Wrap        int
+	SortHeaders bool
+}
+
+// ParseCLIArgsFmt parses CLI arguments for command "fmt"
+func ParseCLIArgsFmt(g Global, args []string) (*ConfigFmt, error) {
+	cli := newFlagSet(g, "fmt")
+	finish := flagsFmt(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsFmt declares the flags of command "fmt" on cli.
+// finish must be called with the positional arguments after parsing
+// to validate the arguments.
+func flagsFmt(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigFmt, error)) {
+	c := &ConfigFmt{}
+
+	cli.BoolVar(&c.Write, "w", false,
+		"write the result to the files instead of stdout")
+	cli.BoolVar(&c.List, "l", false,
+		"list the files whose formatting differs instead of printing them")
+	cli.IntVar(&c.Wrap, "wrap", 0,
+		"maximum line width of texts like --width of GNU gettext tools "+
+			"(0 doesn't wrap)")
+	cli.BoolVar(&c.SortHeaders, "sort-headers", false,
+		"write standard headers first in a fixed order followed by "+
+			"non-standard headers sorted by name")
+
+	return func(args []string) (*ConfigFmt, error) {
+		if len(args) < 1 {
+			return nil, fmt.Errorf("please provide at least one .po or .pot file")
+		}
+		for _, p := range args {
+			if err := checkGettextExt(p); err != nil {
+				return nil, err
+			}
+		}
+		if c.Wrap < 0 {
+			return nil, fmt.Errorf("argument 'wrap' must not be negative")
+		}
+		c.Paths = args
+		return c, nil
+	}
+}
+
+type ConfigMerge struct {
+	// DefPath is the path of the .po catalog to update
+	// and RefPath the path of the .pot template to update it to.
+	DefPath, RefPath string
+
+	// OutPath is the output file path, the result is written to stdout if empty.
+	OutPath string
+}
+
+// ParseCLIArgsMerge parses CLI arguments for command "merge"
+func ParseCLIArgsMerge(g Global, args []string) (*ConfigMerge, error) {
+	cli := newFlagSet(g, "merge")
+	finish := flagsMerge(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsMerge declares the flags of command "merge" on cli.
+// finish must be called with the positional arguments after parsing
+// to validate the arguments.
+func flagsMerge(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigMerge, error)) {
+	c := &ConfigMerge{}
+
+	cli.StringVar(&c.OutPath, "o", "",
+		"output file path, which may be the catalog file. "+
+			"Written to stdout by default.")
+
+	return func(args []string) (*ConfigMerge, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf(
+				"please provide exactly one .po catalog and one .pot template",
+			)
+		}
+		c.DefPath, c.RefPath = args[0], args[1]
+		if filepath.Ext(c.DefPath) != ".po" {
+			return nil, fmt.Errorf("catalog %q must have extension .po", c.DefPath)
+		}
+		if filepath.Ext(c.RefPath) != ".pot" {
+			return nil, fmt.Errorf("template %q must have extension .pot", c.RefPath)
+		}
+		return c, nil
+	}
+}
+
+type ConfigTrim struct {
+	BundlePkgPath string
+
+	// Keep are the locales of the translation catalogs to keep.
+	Keep []language.Tag
+
+	// DryRun reports the catalogs that would be removed without removing them.
+	DryRun bool
+
+	// GenerateArgs are passed to command "generate" regenerating the bundle.
+	GenerateArgs []string
+}
+
+// ParseCLIArgsTrim parses CLI arguments for command "trim"
+func ParseCLIArgsTrim(g Global, args []string) (*ConfigTrim, error) {
+	cli := newFlagSet(g, "trim")
+	finish := flagsTrim(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsTrim declares the flags of command "trim" on cli.
+// finish must be called with the positional arguments after parsing
+// to validate the arguments.
+func flagsTrim(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigTrim, error)) {
+	c := &ConfigTrim{}
+
+	var keep string
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to generated Go bundle package")
+	cli.StringVar(&keep, "keep", "",
+		"comma-separated BCP 47 locales of the translation catalogs to keep")
+	cli.BoolVar(&c.DryRun, "dry-run", false,
+		"report the catalogs that would be removed without removing them")
+
+	return func(args []string) (*ConfigTrim, error) {
+		return c.finish(keep, args)
+	}
+}
+
+func (c *ConfigTrim) finish(keep string, args []string) (*ConfigTrim, error) {
+	if keep == "" {
+		return nil, fmt.Errorf(
+			"please provide the locales to keep using the 'keep' parameter",
+		)
+	}
+	for s := range strings.SplitSeq(keep, ",") {
+		t, err := language.Parse(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf(
+				"argument 'keep' (%q) must be a list of valid "+
+					"BCP 47 locales: %w", keep, err,
+			)
+		}
+		c.Keep = append(c.Keep, t)
+	}
+	c.GenerateArgs = args
+	return c, nil
+}
+
+type ConfigSelftest struct {
+	// KeepTemp keeps the temporary copies of the module for inspection.
+	KeepTemp bool
+
+	// GenerateArgs are the arguments passed to command "generate".
+	GenerateArgs []string
+}
+
+// ParseCLIArgsSelftest parses CLI arguments for command "selftest"
+func ParseCLIArgsSelftest(g Global, args []string) (*ConfigSelftest, error) {
+	cli := newFlagSet(g, "selftest")
+	finish := flagsSelftest(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish(cli.Args())
+}
+
+// flagsSelftest declares the flags of command "selftest" on cli.
+// finish must be called with the positional arguments after parsing
+// to complete the configuration.
+func flagsSelftest(
+	cli *flag.FlagSet,
+) (finish func(args []string) (*ConfigSelftest, error)) {
+	c := &ConfigSelftest{}
+	cli.BoolVar(&c.KeepTemp, "keep-temp", false,
+		"keep the temporary copies of the module for inspection")
+	return func(args []string) (*ConfigSelftest, error) {
+		c.GenerateArgs = args
+		return c, nil
+	}
+}
+
+type ConfigPluralTests struct {
+	BundlePkgPath string
+
+	// OutPath is the path of the generated test file.
+	OutPath string
+}
+
+// ParseCLIArgsPluralTests parses CLI arguments for command "plural-tests"
+func ParseCLIArgsPluralTests(g Global, args []string) (*ConfigPluralTests, error) {
+	cli := newFlagSet(g, "plural-tests")
+	finish := flagsPluralTests(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish()
+}
+
+// flagsPluralTests declares the flags of command "plural-tests" on cli.
+// finish must be called after parsing to complete the configuration.
+func flagsPluralTests(
+	cli *flag.FlagSet,
+) (finish func() (*ConfigPluralTests, error)) {
+	c := &ConfigPluralTests{}
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to generated Go bundle package")
+	cli.StringVar(&c.OutPath, "o", "",
+		"output file path (plural_gen_test.go in the bundle package by default)")
+	return func() (*ConfigPluralTests, error) {
+		if c.OutPath == "" {
+			c.OutPath = filepath.Join(c.BundlePkgPath, "plural_gen_test.go")
+		}
+		return c, nil
+	}
+}
+
+type ConfigSmoke struct {
+	BundlePkgPath string
+
+	// Locale is the locale of the translation catalog to check.
+	Locale language.Tag
+
+	// Max is the maximum number of messages to check, all if 0.
+	Max int
+
+	// Typography, TypographyAll and HeadingCasing are the post-processing
+	// settings the Go bundle was generated with (see ConfigGenerate).
+	Typography    []language.Tag
+	TypographyAll bool
+	HeadingCasing map[language.Tag]typography.Casing
+}
+
+// ParseCLIArgsSmoke parses CLI arguments for command "smoke"
+func ParseCLIArgsSmoke(g Global, args []string) (*ConfigSmoke, error) {
+	cli := newFlagSet(g, "smoke")
+	finish := flagsSmoke(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish()
+}
+
+// flagsSmoke declares the flags of command "smoke" on cli.
+// finish must be called after parsing to validate the arguments.
+func flagsSmoke(cli *flag.FlagSet) (finish func() (*ConfigSmoke, error)) {
+	c := &ConfigSmoke{}
+
+	var locale, typography string
+	cli.StringVar(&c.BundlePkgPath, "b", "localizebundle",
+		"path to generated Go bundle package")
+	cli.StringVar(&locale, "locale", "", "BCP 47 locale of the catalog")
+	cli.IntVar(&c.Max, "n", 100, "maximum number of messages to check, 0 for all")
+	flagHeadingCasing(cli, &c.HeadingCasing)
+	cli.StringVar(&typography, "typography", "",
+		"comma-separated BCP 47 locales the Go bundle was generated with "+
+			"typographic post-processing for, use * for all")
+
+	return func() (*ConfigSmoke, error) { return c.finish(locale, typography) }
+}
+
+func (c *ConfigSmoke) finish(locale, typography string) (*ConfigSmoke, error) {
+	if locale == "" {
+		return nil, fmt.Errorf(
+			"please provide a valid BCP 47 locale of the catalog " +
+				"using the 'locale' parameter",
+		)
+	}
+	var err error
+	c.Locale, err = language.Parse(locale)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"argument 'locale' (%q) must be a valid BCP 47 locale: %w", locale, err,
+		)
+	}
+	if c.Max < 0 {
+		return nil, fmt.Errorf("argument 'n' (%d) must not be negative", c.Max)
+	}
+	if c.Typography, c.TypographyAll, err = parseTypography(typography); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type ConfigExample struct {
+	// OutPath is the directory the example app is written to.
+	OutPath string
+
+	// Module is the module path of the example app.
+	Module string
+
+	QuietMode bool
+}
+
+// ParseCLIArgsExample parses CLI arguments for command "example"
+func ParseCLIArgsExample(g Global, args []string) (*ConfigExample, error) {
+	cli := newFlagSet(g, "example")
+	finish := flagsExample(cli)
+	if err := g.parse(cli, args); err != nil {
+		return nil, err
+	}
+	return finish()
+}
+
+// flagsExample declares the flags of command "example" on cli.
+// finish must be called after parsing to validate the arguments.
+func flagsExample(cli *flag.FlagSet) (finish func() (*ConfigExample, error)) {
+	c := &ConfigExample{}
+	cli.StringVar(&c.OutPath, "o", "example",
+		"output directory path, which must be empty or not exist")
+	cli.StringVar(&c.Module, "module", "example.com/localize-example",
+		"module path of the example app")
+	cli.BoolVar(&c.QuietMode, "q", false, "disable all console logging")
+	return func() (*ConfigExample, error) {
+		if err := module.CheckPath(c.Module); err != nil {
+			return nil, fmt.Errorf(
+				"argument 'module' (%q) must be a valid module path: %w", c.Module, err,
+			)
+		}
+		return c, nil
+	}
+}
@@ -0,0 +1,262 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/config"
+	"github.com/romshark/localize/strfmt"
+	"github.com/romshark/localize/typography"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestParseCLIArgsGenerateBundlePath(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"),
+		[]byte("module example.com/app\n"), 0o644))
+	sub := filepath.Join(root, "cmd", "app")
+	require.NoError(t, os.MkdirAll(sub, 0o755))
+	t.Chdir(root)
+
+	parse := func(args ...string) (*config.ConfigGenerate, error) {
+		return config.ParseCLIArgsGenerate(config.Global{},
+			append([]string{"-l", "en"}, args...))
+	}
+
+	_, err = parse("-b", filepath.Join(root, "localizebundle"))
+	require.NoError(t, err)
+	_, err = parse("-b", filepath.Join(t.TempDir(), "localizebundle"))
+	require.ErrorIs(t, err, config.ErrBundleOutsideModule)
+	_, err = parse("-b", "../localizebundle")
+	require.ErrorIs(t, err, config.ErrBundleOutsideModule)
+
+	// Bundles outside the module are accepted if identified by import path.
+	c, err := parse("-b", filepath.Join(t.TempDir(), "localizebundle"),
+		"-import-path", "example.com/other/localizebundle")
+	require.NoError(t, err)
+	require.Equal(t, "example.com/other/localizebundle", c.ImportPath)
+
+	// The module path is joined with the bundle path relative to the module.
+	t.Chdir(sub)
+	c, err = parse("-p", "../..", "-b", "../../internal/localizebundle",
+		"-module-path", "example.com/app")
+	require.NoError(t, err)
+	require.Equal(t, "example.com/app/internal/localizebundle", c.ImportPath)
+
+	_, err = parse("-p", "../..", "-b", "localizebundle")
+	require.NoError(t, err)
+	_, err = parse("-p", "../...", "-b", filepath.Join(t.TempDir(), "b"))
+	require.ErrorIs(t, err, config.ErrBundleOutsideModule)
+}
+
+func TestParseCLIArgsGenerateImportPath(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"),
+		[]byte("module example.com/app\n"), 0o644))
+	nested := filepath.Join(root, "tools")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(nested, "go.mod"),
+		[]byte("module example.com/app/tools\n"), 0o644))
+	t.Chdir(root)
+
+	parse := func(args ...string) (*config.ConfigGenerate, error) {
+		return config.ParseCLIArgsGenerate(config.Global{},
+			append([]string{"-l", "en"}, args...))
+	}
+
+	// The package name is the base of the bundle directory by default.
+	c, err := parse("-b", "internal/l10n")
+	require.NoError(t, err)
+	require.Empty(t, c.ImportPath)
+	require.Equal(t, "l10n", c.PackageName)
+
+	// The package name is the base of the import path if any.
+	c, err = parse("-b", "internal/l10n", "-import-path", "example.com/app/i18n")
+	require.NoError(t, err)
+	require.Equal(t, "example.com/app/i18n", c.ImportPath)
+	require.Equal(t, "i18n", c.PackageName)
+
+	// The import path takes precedence over the module path.
+	c, err = parse("-b", "internal/l10n", "-module-path", "example.com/app",
+		"-import-path", "example.com/app/i18n")
+	require.NoError(t, err)
+	require.Equal(t, "example.com/app/i18n", c.ImportPath)
+
+	// The bundle path is relative to the module containing it,
+	// which is the nested module for bundles inside of it.
+	c, err = parse("-b", "tools/internal/localizebundle",
+		"-module-path", "example.com/app/tools")
+	require.NoError(t, err)
+	require.Equal(t, "example.com/app/tools/internal/localizebundle", c.ImportPath)
+	require.Equal(t, "localizebundle", c.PackageName)
+
+	_, err = parse("-b", "localizebundle", "-module-path", "Example.com/app path")
+	require.ErrorContains(t, err, "module-path")
+	_, err = parse("-b", "localizebundle", "-import-path", "example.com/app/")
+	require.ErrorContains(t, err, "import-path")
+	_, err = parse("-b", "localizebundle", "-import-path", "example.com/app/l10n-bundle")
+	require.ErrorContains(t, err, "valid Go identifier")
+}
+
+func TestParseCLIArgsGenerateDeriveOne(t *testing.T) {
+	parse := func(locale string) (*config.ConfigGenerate, error) {
+		return config.ParseCLIArgsGenerate(config.Global{}, []string{
+			"-l", locale, "-derive-one", "-import-path", "example.com/localizebundle",
+		})
+	}
+	c, err := parse("en-GB")
+	require.NoError(t, err)
+	require.True(t, c.Load.DeriveOne)
+
+	_, err = parse("de")
+	require.ErrorContains(t, err, "derive-one")
+}
+
+func TestParseCLIArgsGenerateNormalize(t *testing.T) {
+	parse := func(args ...string) (*config.ConfigGenerate, error) {
+		return config.ParseCLIArgsGenerate(config.Global{}, append([]string{
+			"-l", "en", "-import-path", "example.com/localizebundle",
+		}, args...))
+	}
+	c, err := parse()
+	require.NoError(t, err)
+	require.Equal(t, strfmt.NormalizeNone, c.Load.Normalization)
+
+	c, err = parse("-normalize", "nfc,spaces")
+	require.NoError(t, err)
+	require.Equal(t, strfmt.NormalizeNFC|strfmt.NormalizeSpaces, c.Load.Normalization)
+}
+
+func TestParseCLIArgsGenerateHash(t *testing.T) {
+	parse := func(args ...string) (*config.ConfigGenerate, error) {
+		return config.ParseCLIArgsGenerate(config.Global{}, append([]string{
+			"-l", "en", "-import-path", "example.com/localizebundle",
+		}, args...))
+	}
+	c, err := parse()
+	require.NoError(t, err)
+	require.Equal(t, codeparser.HashXXHash64, c.Load.Hash)
+
+	c, err = parse("-hash", "fnv128a")
+	require.NoError(t, err)
+	require.Equal(t, codeparser.HashFNV128a, c.Load.Hash)
+}
+
+func TestParseCLIArgsGeneratePostGenerate(t *testing.T) {
+	c, err := config.ParseCLIArgsGenerate(config.Global{}, []string{
+		"-l", "en", "-import-path", "example.com/localizebundle",
+		"-post-generate", "upload-pot  --project web",
+		"-post-generate", "make bundles",
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"upload-pot", "--project", "web"}, {"make", "bundles"},
+	}, c.PostGenerate)
+}
+
+func TestParseCLIArgsGenerateMergeDescriptions(t *testing.T) {
+	parse := func(mode string) (*config.ConfigGenerate, error) {
+		return config.ParseCLIArgsGenerate(config.Global{}, []string{
+			"-l", "en", "-import-path", "example.com/localizebundle",
+			"-merge-descriptions", mode,
+		})
+	}
+	c, err := parse("concat")
+	require.NoError(t, err)
+	require.Equal(t, codeparser.DescriptionMergeConcat, c.Load.MergeDescriptions)
+
+	_, err = parse("first")
+	require.ErrorContains(t, err, "merge-descriptions")
+}
+
+func TestParseCLIArgsExtractUnit(t *testing.T) {
+	c, err := config.ParseCLIArgsExtractUnit(config.Global{}, []string{
+		"-l", "en", "-o", "messages.json", "-normalize", "nfc",
+		"-hash", "fnv128a", "unit.json",
+	})
+	require.NoError(t, err)
+	require.Equal(t, codeparser.HashFNV128a, c.Hash)
+	require.Equal(t, "unit.json", c.UnitPath)
+	require.Equal(t, "messages.json", c.OutPath)
+	require.Equal(t, strfmt.NormalizeNFC, c.Normalization)
+	require.True(t, c.TrimPath)
+
+	_, err = config.ParseCLIArgsExtractUnit(config.Global{}, []string{"-l", "en"})
+	require.ErrorContains(t, err, "unit file")
+	_, err = config.ParseCLIArgsExtractUnit(config.Global{}, []string{"unit.json"})
+	require.ErrorContains(t, err, "'l' parameter")
+	_, err = config.ParseCLIArgsExtractUnit(config.Global{}, []string{
+		"-l", "de", "-derive-one", "unit.json",
+	})
+	require.ErrorContains(t, err, "derive-one")
+}
+
+func TestParseCLIArgsSmoke(t *testing.T) {
+	c, err := config.ParseCLIArgsSmoke(config.Global{}, []string{
+		"-locale", "de", "-n", "0", "-typography", "de,fr",
+		"-heading-casing", "de=sentence",
+	})
+	require.NoError(t, err)
+	require.Equal(t, language.German, c.Locale)
+	require.Zero(t, c.Max)
+	require.Equal(t, []language.Tag{language.German, language.French}, c.Typography)
+	require.Equal(t, typography.CasingSentence, c.HeadingCasing[language.German])
+
+	c, err = config.ParseCLIArgsSmoke(config.Global{}, []string{
+		"-locale", "de", "-typography", "*",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 100, c.Max)
+	require.True(t, c.TypographyAll)
+
+	_, err = config.ParseCLIArgsSmoke(config.Global{}, nil)
+	require.ErrorContains(t, err, "'locale' parameter")
+	_, err = config.ParseCLIArgsSmoke(config.Global{}, []string{
+		"-locale", "de", "-n", "-1",
+	})
+	require.ErrorContains(t, err, "must not be negative")
+}
+
+func TestParseCLIArgsGettextFiles(t *testing.T) {
+	c, err := config.ParseCLIArgsCheck(config.Global{}, []string{
+		"catalog.de.po", "catalog.pot",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"catalog.de.po", "catalog.pot"}, c.Paths)
+	_, err = config.ParseCLIArgsCheck(config.Global{}, nil)
+	require.ErrorContains(t, err, "at least one")
+	_, err = config.ParseCLIArgsCheck(config.Global{}, []string{"catalog.txt"})
+	require.ErrorContains(t, err, "extension")
+
+	f, err := config.ParseCLIArgsFmt(config.Global{}, []string{
+		"-w", "-wrap", "79", "-sort-headers", "catalog.de.po",
+	})
+	require.NoError(t, err)
+	require.Equal(t, &config.ConfigFmt{
+		Paths: []string{"catalog.de.po"}, Write: true,
+		Wrap: 79, SortHeaders: true,
+	}, f)
+	_, err = config.ParseCLIArgsFmt(config.Global{}, []string{
+		"-wrap", "-1", "catalog.de.po",
+	})
+	require.ErrorContains(t, err, "must not be negative")
+
+	m, err := config.ParseCLIArgsMerge(config.Global{}, []string{
+		"-o", "catalog.de.po", "catalog.de.po", "catalog.pot",
+	})
+	require.NoError(t, err)
+	require.Equal(t, &config.ConfigMerge{
+		DefPath: "catalog.de.po", RefPath: "catalog.pot", OutPath: "catalog.de.po",
+	}, m)
+	_, err = config.ParseCLIArgsMerge(config.Global{}, []string{"catalog.de.po"})
+	require.ErrorContains(t, err, "exactly one")
+	_, err = config.ParseCLIArgsMerge(config.Global{}, []string{
+		"catalog.pot", "catalog.de.po",
+	})
+	require.ErrorContains(t, err, "must have extension .po")
+}
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/romshark/localize/internal/termcolor"
+	"golang.org/x/text/language"
+)
+
+var (
+	// ErrConfigFile is returned when the configuration file is invalid.
+	ErrConfigFile = errors.New("config file")
+
+	// ErrNoModule is returned by ParseCLIArgs if -modroot is set outside
+	// of a Go module.
+	ErrNoModule = errors.New("no go.mod found")
+
+	// ErrBundleOutsideModule is returned if the bundle package directory (-b)
+	// isn't inside the module of the source code (-p).
+	ErrBundleOutsideModule = errors.New("bundle outside of module")
+
+	// ErrEnv is returned when a LOCALIZE_* environment variable is invalid.
+	ErrEnv = errors.New("environment variable")
+)
+
+// EnvPrefix is the prefix of the environment variables setting flags.
+const EnvPrefix = "LOCALIZE_"
+
+// EnvName returns the name of the environment variable setting flag
+// of command, for example LOCALIZE_GENERATE_STATS_FORMAT for flag
+// "stats-format" of command "generate". command is empty for global flags,
+// for example LOCALIZE_LANG for flag "lang".
+func EnvName(command, flag string) string {
+	name := EnvPrefix + flag
+	if command != "" {
+		name = EnvPrefix + command + "_" + flag
+	}
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// setEnv sets all flags of cli that have their environment variable
+// (see EnvName) set. command is empty for global flags.
+// Values in the form of a JSON array of strings are set one after another
+// for repeatable flags.
+func setEnv(cli *flag.FlagSet, command string) (err error) {
+	cli.VisitAll(func(f *flag.Flag) {
+		name := EnvName(command, f.Name)
+		v, ok := os.LookupEnv(name)
+		if !ok || err != nil {
+			return
+		}
+		values := []string{v}
+		if strings.HasPrefix(strings.TrimSpace(v), "[") {
+			if e := json.Unmarshal([]byte(v), &values); e != nil {
+				err = fmt.Errorf("%w: %s: %w", ErrEnv, name, e)
+				return
+			}
+		}
+		for _, v := range values {
+			if e := cli.Set(f.Name, v); e != nil {
+				err = fmt.Errorf("%w: %s: %w", ErrEnv, name, e)
+				return
+			}
+		}
+	})
+	return err
+}
+
+// Global is the configuration shared by all commands.
+type Global struct {
+	// Program is the name of the executable.
+	Program     string
+	QuietMode   bool
+	VerboseMode bool
+
+	// Lang is the locale of the console output.
+	// Lang is language.Und if it's detected from the system locale.
+	Lang language.Tag
+
+	// Color defines when the console output is colored.
+	Color termcolor.Mode
+
+	// File holds the flag defaults loaded from the configuration file (-config).
+	File File
+
+	// ModuleRoot is set if the working directory was changed to the root
+	// of the Go module containing it (-modroot), such as when running
+	// `go tool localize` from a subdirectory of the module.
+	ModuleRoot bool
+}
+
+// File holds flag defaults by flag name by command name, for example:
+//
+//	{"generate": {"l": "en", "typography": ["de", "fr"]}}
+//
+// Array values are set one after another for repeatable flags.
+// The key SchemaKey may reference the JSON schema of the file for editors.
+type File map[string]map[string]any
+
+// SchemaKey is the key of the configuration file referencing its JSON schema.
+const SchemaKey = "$schema"
+
+// ParseCLIArgs parses the global CLI arguments preceding the command
+// and returns the name of the command and its arguments.
+// command is "" if no command was provided.
+func ParseCLIArgs(osArgs []string) (g Global, command string, args []string, err error) {
+	g.Program = filepath.Base(osArgs[0])
+
+	cli := flag.NewFlagSet(osArgs[0], flag.ExitOnError)
+	configPath := flagsGlobal(cli, &g)
+	cli.Usage = func() { WriteUsage(cli.Output(), g.Program) }
+	if err := setEnv(cli, ""); err != nil {
+		return g, "", nil, err
+	}
+	if err := cli.Parse(osArgs[1:]); err != nil {
+		return g, "", nil, fmt.Errorf("parsing: %w", err)
+	}
+
+	if g.ModuleRoot {
+		// Relative paths including -config are resolved against the root.
+		wd, err := os.Getwd()
+		if err != nil {
+			return g, "", nil, err
+		}
+		root, ok := FindModuleRoot(wd)
+		if !ok {
+			return g, "", nil, fmt.Errorf("%w in %s or any parent directory",
+				ErrNoModule, wd)
+		}
+		if err := os.Chdir(root); err != nil {
+			return g, "", nil, err
+		}
+	}
+
+	if *configPath != "" {
+		if g.File, err = readFile(*configPath); err != nil {
+			return g, "", nil, err
+		}
+	}
+
+	if cli.NArg() < 1 {
+		return g, "", nil, nil
+	}
+	return g, cli.Arg(0), cli.Args()[1:], nil
+}
+
+// flagsGlobal declares the global flags on cli.
+func flagsGlobal(cli *flag.FlagSet, g *Global) (configPath *string) {
+	cli.BoolVar(&g.QuietMode, "q", false, "disable all console logging")
+	cli.BoolVar(&g.VerboseMode, "v", false, "enables verbose console logging")
+	cli.Func("lang",
+		"BCP 47 locale of the console output (system locale by default)",
+		func(s string) (err error) {
+			g.Lang, err = language.Parse(s)
+			return err
+		})
+	cli.Func("color",
+		"color the console output: auto (on terminals unless NO_COLOR is set), "+
+			"always or never",
+		func(s string) (err error) {
+			g.Color, err = termcolor.ParseMode(s)
+			return err
+		})
+	cli.BoolVar(&g.ModuleRoot, "modroot", false,
+		"resolve relative paths against the root directory of the Go module "+
+			"containing the working directory instead of the working directory")
+	return cli.String("config", "",
+		"path to a JSON configuration file defining flag defaults by command")
+}
+
+// FindModuleRoot returns the closest directory containing a go.mod file
+// starting at dir.
+func FindModuleRoot(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func readFile(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConfigFile, err)
+	}
+	if errs := ValidateFile(data); len(errs) > 0 {
+		l := make([]error, len(errs))
+		for i, e := range errs {
+			l[i] = fmt.Errorf("%s:%w", path, e)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrConfigFile, errors.Join(l...))
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: decoding %s: %w", ErrConfigFile, path, err)
+	}
+	f := make(File, len(raw))
+	for name, v := range raw {
+		if name == SchemaKey {
+			continue
+		}
+		var flags map[string]any
+		d := json.NewDecoder(bytes.NewReader(v))
+		d.UseNumber() // Preserve the original formatting of integers.
+		if err := d.Decode(&flags); err != nil {
+			return nil, fmt.Errorf("%w: decoding %s: command %q: %w",
+				ErrConfigFile, path, name, err)
+		}
+		f[name] = flags
+	}
+	return f, nil
+}
+
+// parse parses the arguments of a command. The defaults from the configuration
+// file, the environment variables (see EnvName) and the global flags
+// are applied in this order before args, which take precedence.
+func (g Global) parse(cli *flag.FlagSet, args []string) error {
+	for name, value := range g.File[cli.Name()] {
+		if cli.Lookup(name) == nil {
+			return fmt.Errorf("%w: command %q has no flag %q",
+				ErrConfigFile, cli.Name(), name)
+		}
+		values, ok := value.([]any)
+		if !ok {
+			values = []any{value}
+		}
+		for _, v := range values {
+			if err := cli.Set(name, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("%w: flag %q of command %q: %w",
+					ErrConfigFile, name, cli.Name(), err)
+			}
+		}
+	}
+
+	if err := setEnv(cli, cli.Name()); err != nil {
+		return err
+	}
+
+	// Global flags only apply to commands supporting them.
+	if g.QuietMode && cli.Lookup("q") != nil {
+		_ = cli.Set("q", "true")
+	}
+	if g.VerboseMode && cli.Lookup("v") != nil {
+		_ = cli.Set("v", "true")
+	}
+
+	if err := cli.Parse(args); err != nil {
+		return fmt.Errorf("parsing: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package config_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/localize/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvName(t *testing.T) {
+	require.Equal(t, "LOCALIZE_LANG", config.EnvName("", "lang"))
+	require.Equal(t, "LOCALIZE_GENERATE_STATS_FORMAT",
+		config.EnvName("generate", "stats-format"))
+	require.Equal(t, "LOCALIZE_IMPORT_GO_I18N_L", config.EnvName("import-go-i18n", "l"))
+
+	// All flags of a command must have distinct environment variables.
+	for _, c := range append(config.Commands, config.GlobalFlags) {
+		names := map[string]string{}
+		c.FlagSet().VisitAll(func(f *flag.Flag) {
+			name := config.EnvName(c.Name, f.Name)
+			other, ok := names[name]
+			require.False(t, ok, "flags %q and %q of command %q share %s",
+				other, f.Name, c.Name, name)
+			names[name] = f.Name
+		})
+	}
+}
+
+func TestParseCLIArgsEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "localize.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"docs": {"f": "markdown", "o": "file-docs", "b": "filebundle"}
+	}`), 0o644))
+	t.Setenv("LOCALIZE_CONFIG", path)
+	t.Setenv("LOCALIZE_Q", "true")
+	t.Setenv("LOCALIZE_DOCS_O", "env-docs")
+	t.Setenv("LOCALIZE_DOCS_B", "envbundle")
+
+	// Flags take precedence over environment variables,
+	// which take precedence over the configuration file.
+	g, command, args, err := config.ParseCLIArgs(
+		[]string{"localize", "docs", "-b", "argbundle"},
+	)
+	require.NoError(t, err)
+	require.True(t, g.QuietMode)
+	require.Equal(t, "docs", command)
+	c, err := config.ParseCLIArgsDocs(g, args)
+	require.NoError(t, err)
+	require.Equal(t, "markdown", c.Format)
+	require.Equal(t, "env-docs", c.OutPath)
+	require.Equal(t, "argbundle", c.BundlePkgPath)
+	require.True(t, c.QuietMode)
+
+	t.Setenv("LOCALIZE_DOCS_Q", "maybe")
+	_, err = config.ParseCLIArgsDocs(g, nil)
+	require.ErrorIs(t, err, config.ErrEnv)
+	require.ErrorContains(t, err, "LOCALIZE_DOCS_Q")
+}
+
+func TestParseCLIArgsEnvArray(t *testing.T) {
+	t.Setenv("LOCALIZE_GENERATE_WIGNORE", `["sentence-split", "message-expired"]`)
+	g, _, args, err := config.ParseCLIArgs([]string{"localize", "generate", "-l", "en"})
+	require.NoError(t, err)
+	c, err := config.ParseCLIArgsGenerate(g, args)
+	require.NoError(t, err)
+	require.Equal(t, []string{"sentence-split", "message-expired"}, c.WarningsIgnored)
+
+	t.Setenv("LOCALIZE_GENERATE_WIGNORE", `["sentence-split"`)
+	_, err = config.ParseCLIArgsGenerate(g, args)
+	require.ErrorIs(t, err, config.ErrEnv)
+}
+
+func TestParseCLIArgsModuleRoot(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"),
+		[]byte("module example\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "localize.json"),
+		[]byte(`{"generate": {"l": "de"}}`), 0o644))
+	sub := filepath.Join(root, "internal", "app")
+	require.NoError(t, os.MkdirAll(sub, 0o755))
+	t.Chdir(sub)
+
+	g, command, _, err := config.ParseCLIArgs([]string{
+		"localize", "-modroot", "-config", "localize.json", "generate",
+	})
+	require.NoError(t, err)
+	require.True(t, g.ModuleRoot)
+	require.Equal(t, "generate", command)
+	require.Equal(t, "de", g.File["generate"]["l"])
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.Equal(t, root, wd)
+
+	t.Chdir(t.TempDir())
+	_, _, _, err = config.ParseCLIArgs([]string{"localize", "-modroot", "generate"})
+	require.ErrorIs(t, err, config.ErrNoModule)
+}
@@ -0,0 +1,262 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// FileError is an error at a position of a configuration file.
+type FileError struct {
+	// Line and Column are 1-based, Column counts bytes.
+	Line, Column int
+	Err          error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%d:%d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *FileError) Unwrap() error { return e.Err }
+
+// ValidateFile returns the errors of the configuration file data:
+// syntax errors, unknown commands and flags and flag values that are
+// rejected by the flag or not listed in the FlagValues of the command.
+// Validation stops at the first syntax error.
+func ValidateFile(data []byte) []*FileError {
+	v := &validator{data: data, d: json.NewDecoder(bytes.NewReader(data))}
+	v.d.UseNumber()
+	v.file()
+	return v.errs
+}
+
+// errSyntax stops validation at the first syntax error.
+var errSyntax = errors.New("syntax error")
+
+type validator struct {
+	data []byte
+	d    *json.Decoder
+	errs []*FileError
+}
+
+// errorf reports an error at byte offset off.
+func (v *validator) errorf(off int64, format string, args ...any) {
+	e := &FileError{Line: 1, Column: 1, Err: fmt.Errorf(format, args...)}
+	for _, c := range v.data[:min(off, int64(len(v.data)))] {
+		if c == '\n' {
+			e.Line++
+			e.Column = 1
+			continue
+		}
+		e.Column++
+	}
+	v.errs = append(v.errs, e)
+}
+
+// offset returns the offset of the next token.
+func (v *validator) offset() int64 {
+	off := v.d.InputOffset()
+	// InputOffset is the end of the previous token, skip the separators.
+	for off < int64(len(v.data)) && strings.IndexByte(" \t\r\n,:", v.data[off]) != -1 {
+		off++
+	}
+	return off
+}
+
+// token returns the next token and its offset.
+func (v *validator) token() (json.Token, int64, error) {
+	off := v.offset()
+	t, err := v.d.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		var s *json.SyntaxError
+		if errors.As(err, &s) {
+			off = s.Offset
+			if off > 0 && off < int64(len(v.data)) {
+				off-- // Offset is after the invalid character.
+			}
+		}
+		v.errorf(off, "%v", err)
+		return nil, off, errSyntax
+	}
+	return t, off, nil
+}
+
+// skip skips the value following a key.
+func (v *validator) skip() error {
+	t, _, err := v.token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); ok {
+		return v.skipRest(d)
+	}
+	return nil
+}
+
+func (v *validator) file() {
+	t, off, err := v.token()
+	if err != nil {
+		return
+	}
+	if t != json.Delim('{') {
+		v.errorf(off, "expected an object of flags by command")
+		return
+	}
+	seen := map[string]bool{}
+	for v.d.More() {
+		t, off, err := v.token()
+		if err != nil {
+			return
+		}
+		name := t.(string)
+		if seen[name] {
+			v.errorf(off, "duplicate command %q", name)
+		}
+		seen[name] = true
+		if name == SchemaKey {
+			if err := v.schemaRef(); err != nil {
+				return
+			}
+			continue
+		}
+		c, ok := CommandByName(name)
+		if !ok {
+			v.errorf(off, "unknown command %q", name)
+			if err := v.skip(); err != nil {
+				return
+			}
+			continue
+		}
+		if err := v.command(c); err != nil {
+			return
+		}
+	}
+	if _, _, err := v.token(); err != nil { // '}'
+		return
+	}
+	if v.d.More() {
+		v.errorf(v.offset(), "unexpected data after the configuration")
+	}
+}
+
+func (v *validator) schemaRef() error {
+	t, off, err := v.token()
+	if err != nil {
+		return err
+	}
+	if _, ok := t.(string); ok {
+		return nil
+	}
+	v.errorf(off, "%s must be a string", SchemaKey)
+	if d, ok := t.(json.Delim); ok {
+		return v.skipRest(d)
+	}
+	return nil
+}
+
+// skipRest skips the remainder of the object or array
+// opened by the preceding delimiter.
+func (v *validator) skipRest(json.Delim) error {
+	for depth := 1; depth > 0; {
+		t, _, err := v.token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+func (v *validator) command(c Command) error {
+	t, off, err := v.token()
+	if err != nil {
+		return err
+	}
+	if t != json.Delim('{') {
+		v.errorf(off, "command %q: expected an object of flags", c.Name)
+		if d, ok := t.(json.Delim); ok {
+			return v.skipRest(d)
+		}
+		return nil
+	}
+	cli := c.FlagSet()
+	cli.SetOutput(io.Discard)
+	seen := map[string]bool{}
+	for v.d.More() {
+		t, off, err := v.token()
+		if err != nil {
+			return err
+		}
+		name := t.(string)
+		if seen[name] {
+			v.errorf(off, "command %q: duplicate flag %q", c.Name, name)
+		}
+		seen[name] = true
+		if cli.Lookup(name) == nil {
+			v.errorf(off, "command %q has no flag %q", c.Name, name)
+			if err := v.skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := v.flag(c, cli.Set, name); err != nil {
+			return err
+		}
+	}
+	_, _, err = v.token() // '}'
+	return err
+}
+
+// flag validates the value of flag name by setting it using set.
+// Arrays are set one value after another.
+func (v *validator) flag(c Command, set func(name, value string) error, name string) error {
+	t, off, err := v.token()
+	if err != nil {
+		return err
+	}
+	array := t == json.Delim('[')
+	if array {
+		if t, off, err = v.token(); err != nil {
+			return err
+		}
+	}
+	for !array || t != json.Delim(']') {
+		switch t := t.(type) {
+		case json.Delim:
+			v.errorf(off, "flag %q of command %q: expected a string, number or boolean",
+				name, c.Name)
+			if err := v.skipRest(t); err != nil {
+				return err
+			}
+		case nil:
+			v.errorf(off, "flag %q of command %q: unexpected null", name, c.Name)
+		default:
+			s := fmt.Sprint(t)
+			if l := c.FlagValues[name]; len(l) > 0 && !slices.Contains(l, s) {
+				v.errorf(off, "flag %q of command %q: invalid value %q, use either of: %s",
+					name, c.Name, s, strings.Join(l, ", "))
+			} else if err := set(name, s); err != nil {
+				v.errorf(off, "flag %q of command %q: %v", name, c.Name, err)
+			}
+		}
+		if !array {
+			return nil
+		}
+		if t, off, err = v.token(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
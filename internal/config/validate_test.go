@@ -0,0 +1,61 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFile(t *testing.T) {
+	f := func(t *testing.T, data string, expect ...string) {
+		t.Helper()
+		errs := config.ValidateFile([]byte(data))
+		actual := make([]string, len(errs))
+		for i, e := range errs {
+			actual[i] = e.Error()
+		}
+		require.Equal(t, expect, actual)
+	}
+
+	f(t, `{}`, []string{}...)
+	f(t, `{
+  "$schema": "./localize.schema.json",
+  "generate": {"l": "en", "Wignore": ["description-missing"], "max-messages": 4},
+  "docs": {"f": "markdown"}
+}`, []string{}...)
+
+	f(t, `{
+  "$schema": 1,
+  "generate": {
+    "l": "en",
+    "nope": true,
+    "Wignore": ["description-missing", "unknown-code"],
+    "stats-format": "xml",
+    "max-memory": "lots"
+  },
+  "deploy": {"x": 1},
+  "docs": []
+}`,
+		"2:14: $schema must be a string",
+		`5:5: command "generate" has no flag "nope"`,
+		`6:40: flag "Wignore" of command "generate": invalid value "unknown-code", `+
+			`use either of: description-missing, placeholder-suspicious, `+
+			`sentence-split, message-expired`,
+		`7:21: flag "stats-format" of command "generate": `+
+			`invalid value "xml", use either of: text, json`,
+		`8:19: flag "max-memory" of command "generate": invalid size: "lots"`,
+		`10:3: unknown command "deploy"`,
+		`11:11: command "docs": expected an object of flags`,
+	)
+
+	f(t, `[]`, "1:1: expected an object of flags by command")
+	f(t, `{"docs": {"f": {"a": 1}}}`,
+		`1:16: flag "f" of command "docs": expected a string, number or boolean`)
+	f(t, `{"docs": {"f": "html"}} {}`,
+		"1:25: unexpected data after the configuration")
+	f(t, "{\n  \"docs\": {\"f\": \"html\"}\n  \"badge\": {}\n}",
+		"3:3: invalid character '\"' after object key:value pair")
+	f(t, `{"docs": {`, "1:11: unexpected end of JSON input")
+	f(t, ``, "1:1: unexpected EOF")
+}
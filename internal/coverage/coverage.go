@@ -0,0 +1,104 @@
+// Package coverage computes the translation coverage of catalogs.
+package coverage
+
+import "github.com/romshark/localize/gettext"
+
+// Coverage is the translation coverage of a single catalog.
+type Coverage struct {
+	// Total is the number of messages in the source catalog.
+	Total int
+
+	// Translated is the number of source messages translated by the catalog.
+	Translated int
+}
+
+// Percent returns the translated percentage in range [0, 100].
+// Returns 100 if there are no messages to translate.
+func (c Coverage) Percent() float64 {
+	if c.Total < 1 {
+		return 100
+	}
+	return float64(c.Translated) / float64(c.Total) * 100
+}
+
+// Of computes the coverage of catalog against the source catalog.
+func Of(source, catalog gettext.FilePO) Coverage {
+	byCtx := make(map[string]*gettext.Message, len(catalog.Messages.List))
+	for i := range catalog.Messages.List {
+		m := &catalog.Messages.List[i]
+		if m.Obsolete {
+			continue
+		}
+		byCtx[m.Msgctxt.Text.String()] = m
+	}
+	var c Coverage
+	for i := range source.Messages.List {
+		m := &source.Messages.List[i]
+		if m.Obsolete {
+			continue
+		}
+		c.Total++
+		if tm, ok := byCtx[m.Msgctxt.Text.String()]; ok && IsTranslated(tm) {
+			c.Translated++
+		}
+	}
+	return c
+}
+
+// Added returns the messages of source whose msgctxt isn't in previous,
+// which is the source catalog of an earlier revision.
+// Obsolete messages are ignored.
+func Added(previous, source gettext.FilePO) []*gettext.Message {
+	known := make(map[string]struct{}, len(previous.Messages.List))
+	for i := range previous.Messages.List {
+		if m := &previous.Messages.List[i]; !m.Obsolete {
+			known[m.Msgctxt.Text.String()] = struct{}{}
+		}
+	}
+	var added []*gettext.Message
+	for i := range source.Messages.List {
+		m := &source.Messages.List[i]
+		if m.Obsolete {
+			continue
+		}
+		if _, ok := known[m.Msgctxt.Text.String()]; !ok {
+			added = append(added, m)
+		}
+	}
+	return added
+}
+
+// Untranslated returns the messages that catalog doesn't translate.
+func Untranslated(messages []*gettext.Message, catalog gettext.FilePO) []*gettext.Message {
+	byCtx := make(map[string]*gettext.Message, len(catalog.Messages.List))
+	for i := range catalog.Messages.List {
+		if m := &catalog.Messages.List[i]; !m.Obsolete {
+			byCtx[m.Msgctxt.Text.String()] = m
+		}
+	}
+	var untranslated []*gettext.Message
+	for _, m := range messages {
+		if tm, ok := byCtx[m.Msgctxt.Text.String()]; !ok || !IsTranslated(tm) {
+			untranslated = append(untranslated, m)
+		}
+	}
+	return untranslated
+}
+
+// IsTranslated returns true if all msgstr directives of m are non-empty.
+func IsTranslated(m *gettext.Message) bool {
+	found := false
+	for _, s := range [...]*gettext.Msgstr{
+		&m.Msgstr, &m.Msgstr0, &m.Msgstr1, &m.Msgstr2,
+		&m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+	} {
+		if len(s.Text.Lines) < 1 {
+			continue
+		}
+		if s.Text.String() == "" {
+			return false
+		}
+		found = true
+	}
+	return found
+}
@@ -0,0 +1,115 @@
+package coverage_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/coverage"
+	"github.com/stretchr/testify/require"
+)
+
+const head = `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+`
+
+func decode(t *testing.T, s string) gettext.FilePO {
+	t.Helper()
+	po, err := gettext.NewDecoder().DecodePO("test.po", strings.NewReader(head+s))
+	require.NoError(t, err)
+	return po
+}
+
+func TestOf(t *testing.T) {
+	source := decode(t, `
+msgctxt "a"
+msgid "A"
+msgstr "A"
+
+msgctxt "b"
+msgid "%d B"
+msgid_plural "%d Bs"
+msgstr[0] "%d B"
+msgstr[1] "%d Bs"
+
+msgctxt "c"
+msgid "C"
+msgstr "C"
+`)
+	catalog := decode(t, `
+msgctxt "a"
+msgid "A"
+msgstr "A translated"
+
+msgctxt "b"
+msgid "%d B"
+msgid_plural "%d Bs"
+msgstr[0] "%d B translated"
+msgstr[1] ""
+
+#~ msgctxt "c"
+#~ msgid "C"
+#~ msgstr "C translated"
+`)
+
+	c := coverage.Of(source, catalog)
+	require.Equal(t, coverage.Coverage{Total: 3, Translated: 1}, c)
+	require.InDelta(t, 33.33, c.Percent(), 0.01)
+}
+
+func TestPercentEmpty(t *testing.T) {
+	require.Equal(t, float64(100), coverage.Coverage{}.Percent())
+}
+
+func TestAddedUntranslated(t *testing.T) {
+	previous := decode(t, `
+msgctxt "a"
+msgid "A"
+msgstr "A"
+
+#~ msgctxt "c"
+#~ msgid "C"
+#~ msgstr "C"
+`)
+	source := decode(t, `
+msgctxt "a"
+msgid "A"
+msgstr "A"
+
+msgctxt "b"
+msgid "B"
+msgstr "B"
+
+msgctxt "c"
+msgid "C"
+msgstr "C"
+
+#~ msgctxt "d"
+#~ msgid "D"
+#~ msgstr "D"
+`)
+	catalog := decode(t, `
+msgctxt "b"
+msgid "B"
+msgstr "B translated"
+
+msgctxt "c"
+msgid "C"
+msgstr ""
+`)
+
+	msgctxts := func(l []*gettext.Message) (s []string) {
+		for _, m := range l {
+			s = append(s, m.Msgctxt.Text.String())
+		}
+		return s
+	}
+	added := coverage.Added(previous, source)
+	require.Equal(t, []string{"b", "c"}, msgctxts(added))
+	require.Equal(t, []string{"c"}, msgctxts(coverage.Untranslated(added, catalog)))
+	require.Nil(t, coverage.Added(source, source))
+}
@@ -0,0 +1,108 @@
+// Package dedup merges duplicate messages of gettext files like GNU msguniq.
+package dedup
+
+import (
+	"github.com/romshark/localize/gettext"
+)
+
+// Duplicate is a message merged into a previous message
+// with the same msgctxt and msgid.
+type Duplicate struct {
+	// Pos is the position of the removed duplicate.
+	Pos gettext.Position
+
+	// First is the position of the message the duplicate was merged into.
+	First gettext.Position
+
+	// Conflict is true if both messages have different non-empty translations,
+	// in which case the translation of the first message is kept.
+	Conflict bool
+}
+
+// Merge merges all messages of f with the same msgctxt and msgid into the
+// first of them and returns the removed duplicates in the order of occurrence.
+// Comments, such as references and flags, are combined and empty translations
+// are filled with the translations of duplicates.
+// A merged message is obsolete only if all of its duplicates are obsolete.
+func Merge(f *gettext.File) []Duplicate {
+	type key struct{ msgctxt, msgid string }
+	byKey := make(map[key]int, len(f.Messages.List))
+	var duplicates []Duplicate
+	list := f.Messages.List[:0]
+	for _, m := range f.Messages.List {
+		k := key{m.Msgctxt.Text.String(), m.Msgid.Text.String()}
+		i, ok := byKey[k]
+		if !ok {
+			byKey[k] = len(list)
+			list = append(list, m)
+			continue
+		}
+		first := &list[i]
+		duplicates = append(duplicates, Duplicate{
+			Pos:      position(&m),
+			First:    position(first),
+			Conflict: merge(first, &m),
+		})
+	}
+	clear(f.Messages.List[len(list):])
+	f.Messages.List = list
+	return duplicates
+}
+
+// position returns the position of the first directive of m.
+func position(m *gettext.Message) gettext.Position {
+	if !m.Msgctxt.IsZero() {
+		return m.Msgctxt.Position
+	}
+	return m.Msgid.Position
+}
+
+// merge merges duplicate d into m and returns true if
+// their translations conflict.
+func merge(m, d *gettext.Message) (conflict bool) {
+	m.Obsolete = m.Obsolete && d.Obsolete
+	mergeComments(&m.Msgctxt.Comments, d.Msgctxt.Comments)
+	mergeComments(&m.Msgid.Comments, d.Msgid.Comments)
+	mergeComments(&m.MsgidPlural.Comments, d.MsgidPlural.Comments)
+
+	for _, s := range [...]struct{ dst, src *gettext.Msgstr }{
+		{&m.Msgstr, &d.Msgstr},
+		{&m.Msgstr0, &d.Msgstr0},
+		{&m.Msgstr1, &d.Msgstr1},
+		{&m.Msgstr2, &d.Msgstr2},
+		{&m.Msgstr3, &d.Msgstr3},
+		{&m.Msgstr4, &d.Msgstr4},
+		{&m.Msgstr5, &d.Msgstr5},
+	} {
+		mergeComments(&s.dst.Comments, s.src.Comments)
+		dst, src := s.dst.Text.String(), s.src.Text.String()
+		switch {
+		case src == "" || src == dst:
+		case dst == "" && len(s.dst.Text.Lines) > 0:
+			// Only fill existing msgstr directives
+			// to keep the message well-formed.
+			s.dst.Text = s.src.Text.Clone()
+		case dst != "":
+			conflict = true
+		}
+	}
+	return conflict
+}
+
+// mergeComments appends all comments of src missing in dst to dst.
+func mergeComments(dst *gettext.Comments, src gettext.Comments) {
+	for _, c := range src.Text {
+		if !containsComment(dst.Text, c) {
+			dst.Text = append(dst.Text, c)
+		}
+	}
+}
+
+func containsComment(l []gettext.Comment, c gettext.Comment) bool {
+	for _, x := range l {
+		if x.Type == c.Type && x.Value == c.Value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package dedup_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/dedup"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	const input = `msgid ""
+msgstr ""
+"Language: de\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+#: a.go:1
+msgctxt "a"
+msgid "Hello"
+msgstr ""
+
+#: b.go:2
+msgctxt "b"
+msgid "%d apple"
+msgid_plural "%d apples"
+msgstr[0] "%d Apfel"
+msgstr[1] ""
+
+#: a.go:9
+#, go-format
+msgctxt "a"
+msgid "Hello"
+msgstr "Hallo"
+
+#: c.go:3
+msgctxt "b"
+msgid "%d apple"
+msgid_plural "%d apples"
+msgstr[0] "%d Äpfelchen"
+msgstr[1] "%d Äpfel"
+
+#~ msgctxt "c"
+#~ msgid "Bye"
+#~ msgstr "Tschüss"
+
+#: a.go:1
+msgctxt "a"
+msgid "Hello"
+msgstr ""
+`
+	const expect = `msgid ""
+msgstr ""
+"Language: de\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+#: a.go:1
+#: a.go:9
+#, go-format
+msgctxt "a"
+msgid "Hello"
+msgstr "Hallo"
+
+#: b.go:2
+#: c.go:3
+msgctxt "b"
+msgid "%d apple"
+msgid_plural "%d apples"
+msgstr[0] "%d Apfel"
+msgstr[1] "%d Äpfel"
+
+#~ msgctxt "c"
+#~ msgid "Bye"
+#~ msgstr "Tschüss"
+`
+	f, err := gettext.NewDecoder().DecodePO("test.po", strings.NewReader(input))
+	require.NoError(t, err)
+
+	duplicates := dedup.Merge(f.File)
+	require.Len(t, duplicates, 3)
+	require.Equal(t, uint32(21), duplicates[0].Pos.Line)
+	require.Equal(t, uint32(9), duplicates[0].First.Line)
+	require.False(t, duplicates[0].Conflict)
+	require.Equal(t, uint32(27), duplicates[1].Pos.Line)
+	require.Equal(t, uint32(14), duplicates[1].First.Line)
+	require.True(t, duplicates[1].Conflict)
+	require.Equal(t, uint32(38), duplicates[2].Pos.Line)
+	require.False(t, duplicates[2].Conflict)
+	require.Empty(t, f.Validate())
+
+	var buf bytes.Buffer
+	require.NoError(t, gettext.Encoder{}.EncodePO(f, &buf))
+	require.Equal(t, expect, buf.String())
+
+	// Merging is idempotent.
+	require.Empty(t, dedup.Merge(f.File))
+}
+
+func TestMergeObsolete(t *testing.T) {
+	const input = `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#~ msgctxt "a"
+#~ msgid "Hello"
+#~ msgstr "Hallo"
+
+msgctxt "a"
+msgid "Hello"
+msgstr ""
+`
+	f, err := gettext.NewDecoder().DecodePO("test.po", strings.NewReader(input))
+	require.NoError(t, err)
+
+	require.Len(t, dedup.Merge(f.File), 1)
+	require.Len(t, f.Messages.List, 1)
+	require.False(t, f.Messages.List[0].Obsolete)
+	require.Equal(t, "Hallo", f.Messages.List[0].Msgstr.Text.String())
+}
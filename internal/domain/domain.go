@@ -0,0 +1,120 @@
+// Package domain assigns messages to catalog domains
+// allowing catalogs to be split into multiple files.
+package domain
+
+import (
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+)
+
+// Mapping assigns all messages referenced in files with PathPrefix
+// to the domain Name.
+type Mapping struct{ Name, PathPrefix string }
+
+// Resolver resolves the domains of messages.
+// The zero value resolves all messages to the default domain "".
+type Resolver struct {
+	// ByPackage assigns messages to domains named after the top-level
+	// directory of the file they're referenced in unless matched by Mappings.
+	// Messages referenced in files in the module root directory
+	// are assigned to the default domain.
+	ByPackage bool
+
+	// Mappings are checked in order, the first matching mapping is used.
+	Mappings []Mapping
+}
+
+// Enabled returns true if r splits messages into domains.
+func (r Resolver) Enabled() bool { return r.ByPackage || len(r.Mappings) > 0 }
+
+// OfPath returns the domain of a message referenced in file path.
+func (r Resolver) OfPath(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	for _, m := range r.Mappings {
+		if strings.HasPrefix(path, strings.TrimPrefix(m.PathPrefix, "/")) {
+			return m.Name
+		}
+	}
+	if r.ByPackage {
+		if i := strings.IndexByte(path, '/'); i != -1 {
+			return path[:i]
+		}
+	}
+	return ""
+}
+
+// Of returns the domain of m determined by its first reference comment.
+func (r Resolver) Of(m *gettext.Message) string {
+	if !r.Enabled() {
+		return ""
+	}
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeReference {
+			continue
+		}
+		// References to asset files like "@email/welcome.html:3"
+		// resolve like paths in the module starting with the name
+		// of their asset root.
+		path := strings.TrimPrefix(c.Value, "@")
+		if i := strings.LastIndexByte(path, ':'); i != -1 {
+			path = path[:i]
+		}
+		return r.OfPath(path)
+	}
+	return ""
+}
+
+// FileName returns the file name of the domain based on the default
+// domain's file name, for example "catalog.pot" becomes "catalog.ui.pot"
+// and "catalog.de.po" becomes "catalog.ui.de.po" for domain "ui".
+// Returns defaultName for the default domain "".
+func FileName(defaultName, domain string) string {
+	if domain == "" {
+		return defaultName
+	}
+	prefix, rest, ok := strings.Cut(defaultName, ".")
+	if !ok {
+		return defaultName + "." + domain
+	}
+	return prefix + "." + domain + "." + rest
+}
+
+// OfFileName returns the domain of the file named fileName based on the
+// default domain's file name, which is the inverse of FileName.
+// ok is false if fileName isn't a file of any domain.
+func OfFileName(defaultName, fileName string) (domain string, ok bool) {
+	if fileName == defaultName {
+		return "", true
+	}
+	prefix, rest, found := strings.Cut(defaultName, ".")
+	if !found {
+		domain, ok = strings.CutPrefix(fileName, defaultName+".")
+		if !ok || !ValidName(domain) {
+			return "", false
+		}
+		return domain, true
+	}
+	s, ok := strings.CutPrefix(fileName, prefix+".")
+	if !ok {
+		return "", false
+	}
+	domain, ok = strings.CutSuffix(s, "."+rest)
+	if !ok || !ValidName(domain) {
+		return "", false
+	}
+	return domain, true
+}
+
+// ValidName returns true if name can be used as a domain name.
+func ValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r == '.' || r == '/' || r == '\\' || r == ' ' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfPath(t *testing.T) {
+	r := domain.Resolver{
+		ByPackage: true,
+		Mappings: []domain.Mapping{
+			{Name: "checkout", PathPrefix: "/shop/checkout/"},
+			{Name: "web", PathPrefix: "web"},
+		},
+	}
+	require.Equal(t, "", r.OfPath("/main.go"))
+	require.Equal(t, "api", r.OfPath("/api/handler.go"))
+	require.Equal(t, "api", r.OfPath("/api/v1/handler.go"))
+	require.Equal(t, "shop", r.OfPath("/shop/cart.go"))
+	require.Equal(t, "checkout", r.OfPath("/shop/checkout/pay.go"))
+	require.Equal(t, "web", r.OfPath("/web/index.go"))
+
+	r.ByPackage = false
+	require.Equal(t, "", r.OfPath("/api/handler.go"))
+	require.Equal(t, "checkout", r.OfPath("/shop/checkout/pay.go"))
+}
+
+func TestOf(t *testing.T) {
+	m := &gettext.Message{Msgctxt: gettext.Msgctxt{Comments: gettext.Comments{
+		Text: []gettext.Comment{
+			{Type: gettext.CommentTypeExtracted, Value: "description"},
+			{Type: gettext.CommentTypeReference, Value: "/api/handler.go:12"},
+			{Type: gettext.CommentTypeReference, Value: "/web/index.go:5"},
+		},
+	}}}
+	require.Equal(t, "", domain.Resolver{}.Of(m))
+	require.Equal(t, "api", domain.Resolver{ByPackage: true}.Of(m))
+
+	// Asset references resolve by the name of their asset root.
+	m.Msgctxt.Comments.Text[1].Value = "@email/welcome.html:3"
+	require.Equal(t, "email", domain.Resolver{ByPackage: true}.Of(m))
+}
+
+func TestFileName(t *testing.T) {
+	require.Equal(t, "catalog.pot", domain.FileName("catalog.pot", ""))
+	require.Equal(t, "catalog.ui.pot", domain.FileName("catalog.pot", "ui"))
+	require.Equal(t, "catalog.ui.de.po", domain.FileName("catalog.de.po", "ui"))
+}
+
+func TestOfFileName(t *testing.T) {
+	f := func(t *testing.T, defaultName, fileName, expect string, expectOK bool) {
+		t.Helper()
+		d, ok := domain.OfFileName(defaultName, fileName)
+		require.Equal(t, expectOK, ok)
+		require.Equal(t, expect, d)
+	}
+	f(t, "catalog.pot", "catalog.pot", "", true)
+	f(t, "catalog.pot", "catalog.ui.pot", "ui", true)
+	f(t, "catalog.de.po", "catalog.ui.de.po", "ui", true)
+	f(t, "catalog", "catalog.ui", "ui", true)
+	f(t, "catalog.pot", "catalog.de.po", "", false)
+	f(t, "catalog.pot", "catalog.a.b.pot", "", false)
+	f(t, "catalog.pot", "source.ui.pot", "", false)
+	f(t, "catalog.pot", "catalog..pot", "", false)
+
+	for _, d := range []string{"", "ui", "api"} {
+		got, ok := domain.OfFileName("catalog.de.po", domain.FileName("catalog.de.po", d))
+		require.True(t, ok)
+		require.Equal(t, d, got)
+	}
+}
@@ -0,0 +1,127 @@
+// Package edition tags messages with the product editions they belong to
+// using the `// editions: enterprise,cloud` source code directive.
+// Editions are stored as `#, edition:<name>` flags in catalogs.
+// Messages without editions belong to all editions.
+package edition
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+)
+
+const (
+	// DirectivePrefix is the prefix of the source code comment line
+	// listing the editions of a message.
+	DirectivePrefix = "editions:"
+
+	// FlagPrefix is the prefix of catalog flags carrying an edition.
+	FlagPrefix = "edition:"
+)
+
+var ErrInvalidName = errors.New("invalid edition name")
+
+// ParseDirective parses a comment line like "editions: enterprise,cloud"
+// and returns the sorted and deduplicated editions.
+// ok is false if line isn't an editions directive.
+func ParseDirective(line string) (editions []string, ok bool, err error) {
+	list, ok := strings.CutPrefix(line, DirectivePrefix)
+	if !ok {
+		return nil, false, nil
+	}
+	for n := range strings.SplitSeq(list, ",") {
+		n = strings.TrimSpace(n)
+		if err := CheckName(n); err != nil {
+			return nil, true, err
+		}
+		editions = append(editions, n)
+	}
+	slices.Sort(editions)
+	return slices.Compact(editions), true, nil
+}
+
+// CheckName returns ErrInvalidName if n isn't a valid edition name.
+// Valid names are non-empty and consist of ASCII letters, digits,
+// dashes and underscores.
+func CheckName(n string) error {
+	if n == "" {
+		return fmt.Errorf("%w: empty", ErrInvalidName)
+	}
+	for _, r := range n {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '-', r == '_':
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidName, n)
+		}
+	}
+	return nil
+}
+
+// Of returns the editions of m in the order of their flags.
+func Of(m *gettext.Message) (editions []string) {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeFlag {
+			continue
+		}
+		for f := range strings.SplitSeq(c.Value, ",") {
+			if e, ok := strings.CutPrefix(strings.TrimSpace(f), FlagPrefix); ok {
+				editions = append(editions, e)
+			}
+		}
+	}
+	return editions
+}
+
+// Contains returns true if m belongs to edition,
+// which is always the case if m has no editions.
+func Contains(m *gettext.Message, edition string) bool {
+	editions := Of(m)
+	return len(editions) == 0 || slices.Contains(editions, edition)
+}
+
+// Set replaces the edition flags of m with editions preserving all other flags.
+func Set(m *gettext.Message, editions []string) {
+	l := m.Msgctxt.Comments.Text[:0]
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeFlag {
+			var flags []string
+			for f := range strings.SplitSeq(c.Value, ",") {
+				if f = strings.TrimSpace(f); !strings.HasPrefix(f, FlagPrefix) {
+					flags = append(flags, f)
+				}
+			}
+			if len(flags) == 0 {
+				continue // Remove comments containing only editions.
+			}
+			if len(flags) <= strings.Count(c.Value, ",") {
+				// Rewrite only comments containing editions,
+				// keeping other flags verbatim.
+				c.Value = strings.Join(flags, ", ")
+			}
+		}
+		l = append(l, c)
+	}
+	if len(editions) > 0 {
+		flags := make([]string, len(editions))
+		for i, e := range editions {
+			flags[i] = FlagPrefix + e
+		}
+		l = append(l, gettext.Comment{
+			Type:  gettext.CommentTypeFlag,
+			Value: strings.Join(flags, ", "),
+		})
+	}
+	m.Msgctxt.Comments.Text = l
+}
+
+// Filter returns a copy of po containing only the messages of edition.
+func Filter(po gettext.FilePO, edition string) gettext.FilePO {
+	f := *po.File
+	f.Messages.List = slices.DeleteFunc(slices.Clone(po.Messages.List),
+		func(m gettext.Message) bool { return !Contains(&m, edition) })
+	return gettext.FilePO{File: &f}
+}
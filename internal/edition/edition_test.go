@@ -0,0 +1,93 @@
+package edition_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/edition"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirective(t *testing.T) {
+	f := func(t *testing.T, line string, expect []string, expectOK bool) {
+		t.Helper()
+		e, ok, err := edition.ParseDirective(line)
+		require.NoError(t, err)
+		require.Equal(t, expectOK, ok)
+		require.Equal(t, expect, e)
+	}
+	f(t, "editions: enterprise,cloud", []string{"cloud", "enterprise"}, true)
+	f(t, "editions:cloud", []string{"cloud"}, true)
+	f(t, "editions: b, a, b", []string{"a", "b"}, true)
+	f(t, "Editions: cloud", nil, false)
+	f(t, "Greeting.", nil, false)
+
+	fErr := func(t *testing.T, line string) {
+		t.Helper()
+		_, ok, err := edition.ParseDirective(line)
+		require.True(t, ok)
+		require.ErrorIs(t, err, edition.ErrInvalidName)
+	}
+	fErr(t, "editions:")
+	fErr(t, "editions: cloud,")
+	fErr(t, "editions: cloud enterprise")
+	fErr(t, "editions: édition")
+}
+
+func TestSet(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy, edition:old"},
+		{Type: gettext.CommentTypeFlag, Value: "edition:other"},
+	}
+	require.Equal(t, []string{"old", "other"}, edition.Of(m))
+
+	edition.Set(m, []string{"cloud", "enterprise"})
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+		{Type: gettext.CommentTypeFlag, Value: "edition:cloud, edition:enterprise"},
+	}, m.Msgctxt.Comments.Text)
+	require.Equal(t, []string{"cloud", "enterprise"}, edition.Of(m))
+	require.True(t, edition.Contains(m, "cloud"))
+	require.False(t, edition.Contains(m, "community"))
+
+	edition.Set(m, nil)
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+	}, m.Msgctxt.Comments.Text)
+	require.True(t, edition.Contains(m, "community"))
+
+	// Flags without editions are kept verbatim.
+	m.Msgctxt.Comments.Text[1].Value = "fuzzy,go-format"
+	edition.Set(m, []string{"cloud"})
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy,go-format"},
+		{Type: gettext.CommentTypeFlag, Value: "edition:cloud"},
+	}, m.Msgctxt.Comments.Text)
+}
+
+func TestFilter(t *testing.T) {
+	msg := func(hash string, editions ...string) gettext.Message {
+		var m gettext.Message
+		m.Msgctxt.Text.Lines = []gettext.StringLiteral{{Value: hash}}
+		edition.Set(&m, editions)
+		return m
+	}
+	po := gettext.FilePO{File: &gettext.File{Messages: gettext.Messages{
+		List: []gettext.Message{msg("a"), msg("b", "cloud"), msg("c", "enterprise")},
+	}}}
+
+	hashes := func(po gettext.FilePO) (l []string) {
+		for _, m := range po.Messages.List {
+			l = append(l, m.Msgctxt.Text.String())
+		}
+		return l
+	}
+	require.Equal(t, []string{"a", "b"}, hashes(edition.Filter(po, "cloud")))
+	require.Equal(t, []string{"a"}, hashes(edition.Filter(po, "community")))
+	require.Equal(t, []string{"a", "b", "c"}, hashes(po), "original modified")
+}
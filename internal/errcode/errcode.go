@@ -0,0 +1,110 @@
+// Package errcode tags messages of API errors with the machine-readable
+// error codes they are returned with (see package localizeapierr)
+// using the `// error-codes: insufficient_funds` source code directive.
+// Codes are stored as `#, error-code:<code>` flags in catalogs such that
+// translators can look up the documentation of an error.
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+)
+
+const (
+	// DirectivePrefix is the prefix of the source code comment line
+	// listing the error codes of a message.
+	DirectivePrefix = "error-codes:"
+
+	// FlagPrefix is the prefix of catalog flags carrying an error code.
+	FlagPrefix = "error-code:"
+)
+
+var ErrInvalidCode = errors.New("invalid error code")
+
+// ParseDirective parses a comment line like "error-codes: not_found, gone"
+// and returns the sorted and deduplicated codes.
+// ok is false if line isn't an error codes directive.
+func ParseDirective(line string) (codes []string, ok bool, err error) {
+	list, ok := strings.CutPrefix(line, DirectivePrefix)
+	if !ok {
+		return nil, false, nil
+	}
+	for s := range strings.SplitSeq(list, ",") {
+		s = strings.TrimSpace(s)
+		if !Valid(s) {
+			return nil, true, fmt.Errorf("%w: %q", ErrInvalidCode, s)
+		}
+		codes = append(codes, s)
+	}
+	slices.Sort(codes)
+	return slices.Compact(codes), true, nil
+}
+
+// Valid returns true if code is a valid error code, which consists of
+// ASCII letters, digits and the characters "_", "-" and ".".
+func Valid(code string) bool {
+	if code == "" {
+		return false
+	}
+	for _, c := range code {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '_' || c == '-' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// Of returns the error codes of m in the order of their flags.
+func Of(m *gettext.Message) (codes []string) {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeFlag {
+			continue
+		}
+		for f := range strings.SplitSeq(c.Value, ",") {
+			if code, ok := strings.CutPrefix(strings.TrimSpace(f), FlagPrefix); ok {
+				codes = append(codes, code)
+			}
+		}
+	}
+	return codes
+}
+
+// Set replaces the error code flags of m with codes preserving all other flags.
+func Set(m *gettext.Message, codes []string) {
+	l := m.Msgctxt.Comments.Text[:0]
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeFlag {
+			var flags []string
+			for f := range strings.SplitSeq(c.Value, ",") {
+				if f = strings.TrimSpace(f); !strings.HasPrefix(f, FlagPrefix) {
+					flags = append(flags, f)
+				}
+			}
+			if len(flags) == 0 {
+				continue // Remove comments containing only error codes.
+			}
+			if len(flags) <= strings.Count(c.Value, ",") {
+				// Rewrite only comments containing error codes,
+				// keeping other flags verbatim.
+				c.Value = strings.Join(flags, ", ")
+			}
+		}
+		l = append(l, c)
+	}
+	if len(codes) > 0 {
+		flags := make([]string, len(codes))
+		for i, code := range codes {
+			flags[i] = FlagPrefix + code
+		}
+		l = append(l, gettext.Comment{
+			Type:  gettext.CommentTypeFlag,
+			Value: strings.Join(flags, ", "),
+		})
+	}
+	m.Msgctxt.Comments.Text = l
+}
@@ -0,0 +1,59 @@
+package errcode_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/errcode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirective(t *testing.T) {
+	f := func(t *testing.T, line string, expect []string, expectOK bool) {
+		t.Helper()
+		c, ok, err := errcode.ParseDirective(line)
+		require.NoError(t, err)
+		require.Equal(t, expectOK, ok)
+		require.Equal(t, expect, c)
+	}
+	f(t, "error-codes: not_found", []string{"not_found"}, true)
+	f(t, "error-codes:gone,not_found, gone", []string{"gone", "not_found"}, true)
+	f(t, "error-codes: payment.card-declined", []string{"payment.card-declined"}, true)
+	f(t, "Error-Codes: gone", nil, false)
+	f(t, "Title of errors.", nil, false)
+
+	fErr := func(t *testing.T, line string) {
+		t.Helper()
+		_, ok, err := errcode.ParseDirective(line)
+		require.True(t, ok)
+		require.ErrorIs(t, err, errcode.ErrInvalidCode)
+	}
+	fErr(t, "error-codes:")
+	fErr(t, "error-codes: gone,")
+	fErr(t, "error-codes: not found")
+	fErr(t, "error-codes: nicht_gefunden_ü")
+}
+
+func TestSet(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy, error-code:gone"},
+		{Type: gettext.CommentTypeFlag, Value: "error-code:not_found"},
+	}
+	require.Equal(t, []string{"gone", "not_found"}, errcode.Of(m))
+
+	errcode.Set(m, []string{"conflict"})
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+		{Type: gettext.CommentTypeFlag, Value: "error-code:conflict"},
+	}, m.Msgctxt.Comments.Text)
+
+	errcode.Set(m, nil)
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+	}, m.Msgctxt.Comments.Text)
+	require.Nil(t, errcode.Of(m))
+}
@@ -0,0 +1,122 @@
+// Package example writes a minimal runnable web app demonstrating
+// the setup of a bundle, HTTP locale negotiation, plural messages
+// and translation catalogs (see localize example).
+package example
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"text/template"
+
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/codeparser"
+	"golang.org/x/text/language"
+)
+
+// ModulePath is the module path of localize.
+const ModulePath = "github.com/romshark/localize"
+
+// ErrNotEmpty is returned when the output directory isn't empty.
+var ErrNotEmpty = errors.New("output directory not empty")
+
+//go:embed template
+var files embed.FS
+
+var funcs = template.FuncMap{
+	"base": path.Base,
+	// hash returns the message hash of text and its description.
+	"hash": codeparser.MessageHash,
+	// pluralForms returns the Plural-Forms header value of a locale.
+	"pluralForms": func(locale string) (string, error) {
+		p, ok := cldr.ByTagOrBase(language.MustParse(locale))
+		if !ok {
+			return "", fmt.Errorf("no plural rules for locale %s", locale)
+		}
+		return fmt.Sprintf("nplurals=%d; plural=%s;",
+			len(p.CardinalForms), p.GettextFormula), nil
+	},
+}
+
+// Options are the options of the example app.
+type Options struct {
+	// Module is the module path of the example app.
+	Module string
+
+	// Version is the version of localize required by the example app.
+	// The latest version is required by `go mod tidy` if empty.
+	Version string
+}
+
+// Write writes the example app to directory dir, which is created if
+// it doesn't exist, and returns the paths of the written files.
+// Returns ErrNotEmpty if dir exists and isn't empty.
+func Write(dir string, o Options) (written []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotEmpty, dir)
+	}
+	err = fs.WalkDir(files, "template", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := fs.ReadFile(files, p)
+		if err != nil {
+			return err
+		}
+		t, err := template.New(path.Base(p)).Funcs(funcs).Parse(string(b))
+		if err != nil {
+			return fmt.Errorf("parsing template %s: %w", p, err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, o); err != nil {
+			return fmt.Errorf("executing template %s: %w", p, err)
+		}
+		// Templates are suffixed to avoid them being built as Go files
+		// or go.mod making the directory a module of its own.
+		name := strings.TrimSuffix(strings.TrimPrefix(p, "template/"), ".gotmpl")
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+			return err
+		}
+		written = append(written, file)
+		return nil
+	})
+	return written, err
+}
+
+// Version returns the version of localize the running binary
+// is built from or an empty string if it's unknown, such as for
+// development builds.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	v := info.Main.Version
+	if info.Main.Path != ModulePath {
+		v = ""
+		for _, d := range info.Deps {
+			if d.Path == ModulePath {
+				v = d.Version
+			}
+		}
+	}
+	if v == "(devel)" {
+		return ""
+	}
+	return v
+}
@@ -0,0 +1,76 @@
+package example_test
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/example"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "example")
+	written, err := example.Write(dir, example.Options{
+		Module: "example.com/app", Version: "v1.2.3",
+	})
+	require.NoError(t, err)
+	rel := make([]string, len(written))
+	for i, f := range written {
+		rel[i], err = filepath.Rel(dir, f)
+		require.NoError(t, err)
+		rel[i] = filepath.ToSlash(rel[i])
+	}
+	require.ElementsMatch(t, []string{
+		"README.md", "go.mod", "main.go", "localizebundle/doc.go",
+		"localizebundle/catalog.de.po", "localizebundle/catalog.fr.po",
+	}, rel)
+
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+	require.Contains(t, string(goMod), "module example.com/app\n")
+	require.Contains(t, string(goMod), "require github.com/romshark/localize v1.2.3\n")
+
+	mainGo, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	require.NoError(t, err)
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", mainGo, parser.ImportsOnly)
+	require.NoError(t, err)
+	imports := make([]string, len(f.Imports))
+	for i, imp := range f.Imports {
+		imports[i], err = strconv.Unquote(imp.Path.Value)
+		require.NoError(t, err)
+	}
+	require.Contains(t, imports, "example.com/app/localizebundle")
+	_, err = parser.ParseFile(fset, filepath.Join(dir, "localizebundle", "doc.go"), nil, 0)
+	require.NoError(t, err)
+
+	// Translations match the texts and descriptions of main.go.
+	for _, locale := range []string{"de", "fr"} {
+		file := filepath.Join(dir, "localizebundle", "catalog."+locale+".po")
+		b, err := os.ReadFile(file)
+		require.NoError(t, err)
+		po, err := gettext.NewDecoder().DecodePOBytes(file, b)
+		require.NoError(t, err)
+		require.Len(t, po.Messages.List, 3)
+		for _, m := range po.Messages.List {
+			text := m.Msgid.Text.String()
+			if len(m.MsgidPlural.Text.Lines) > 0 {
+				text = m.MsgidPlural.Text.String()
+			}
+			description := m.Msgctxt.Comments.Text[0].Value
+			require.Contains(t, string(mainGo), strconv.Quote(text))
+			require.Contains(t, string(mainGo), "\t// "+description+"\n")
+			require.Equal(t, codeparser.MessageHash(text, description),
+				m.Msgctxt.Text.String())
+		}
+	}
+
+	_, err = example.Write(dir, example.Options{Module: "example.com/app"})
+	require.ErrorIs(t, err, example.ErrNotEmpty)
+}
@@ -0,0 +1,198 @@
+// Package exportstate exports the state of a bundle, which is its locales,
+// headers, messages, translations and coverage, as canonical JSON for
+// diffing the state between releases and feeding it to data warehouses.
+package exportstate
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/coverage"
+	"github.com/romshark/localize/internal/gendocs"
+)
+
+// SchemaVersion is the version of the schema of State.
+// It's incremented on every incompatible change of the schema.
+const SchemaVersion = 1
+
+// State is the state of a bundle. All lists are ordered, such that
+// the JSON encoding of the same bundle is always identical.
+type State struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	SourceLocale  string `json:"sourceLocale"`
+
+	// Locales are the source catalog followed by all translation catalogs
+	// ordered by locale.
+	Locales []Locale `json:"locales"`
+
+	// Messages are all messages of the source catalog ordered by hash.
+	Messages []Message `json:"messages"`
+}
+
+// Locale is a catalog of the bundle.
+type Locale struct {
+	Locale   string            `json:"locale"`
+	Headers  map[string]string `json:"headers"`
+	Coverage Coverage          `json:"coverage"`
+}
+
+// Coverage is the translation coverage of a catalog.
+type Coverage struct {
+	Total      int     `json:"total"`
+	Translated int     `json:"translated"`
+	Percent    float64 `json:"percent"`
+}
+
+// Message is a message of the source catalog and all of its translations.
+type Message struct {
+	Hash        string   `json:"hash"`
+	Description string   `json:"description,omitempty"`
+	Flags       []string `json:"flags,omitempty"`
+	References  []string `json:"references"`
+	Source      []Form   `json:"source"`
+
+	// Translations are the translations of the message ordered by locale.
+	Translations []Translation `json:"translations"`
+}
+
+// Translation is the translation of a message in a catalog.
+// Forms is empty if the catalog doesn't contain the message.
+type Translation struct {
+	Locale     string   `json:"locale"`
+	Translated bool     `json:"translated"`
+	Flags      []string `json:"flags,omitempty"`
+	Forms      []Form   `json:"forms,omitempty"`
+}
+
+// Form is either the text of a static message or a plural form.
+// Name is empty for static messages.
+type Form struct {
+	Name string `json:"name,omitempty"`
+	Text string `json:"text"`
+}
+
+// Make creates the state of bundle.
+func Make(bundle *codeparser.Bundle) (*State, error) {
+	site, err := gendocs.Make(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &State{
+		SchemaVersion: SchemaVersion,
+		SourceLocale:  site.SourceLocale,
+		Locales: []Locale{{
+			Locale:  site.SourceLocale,
+			Headers: headers(bundle.Source.Head),
+			Coverage: coverageOf(
+				coverage.Of(bundle.Source.FilePO, bundle.Source.FilePO),
+			),
+		}},
+		Messages: make([]Message, 0, len(site.Messages)),
+	}
+
+	flagsByLocale := map[string]map[string][]string{
+		site.SourceLocale: flagsByHash(bundle.Source.FilePO),
+	}
+	catalogs := make(map[string]codeparser.POFile, len(bundle.Catalogs))
+	for locale, c := range bundle.Catalogs {
+		catalogs[locale.String()] = c
+	}
+	for _, l := range site.Locales {
+		catalog := catalogs[l.Tag]
+		s.Locales = append(s.Locales, Locale{
+			Locale:   l.Tag,
+			Headers:  headers(catalog.Head),
+			Coverage: coverageOf(l.Coverage),
+		})
+		flagsByLocale[l.Tag] = flagsByHash(catalog.FilePO)
+	}
+
+	for _, m := range site.Messages {
+		e := Message{
+			Hash:         m.Hash,
+			Description:  m.Description,
+			Flags:        flagsByLocale[site.SourceLocale][m.Hash],
+			References:   m.References,
+			Source:       forms(m.Source),
+			Translations: make([]Translation, len(m.Translations)),
+		}
+		if e.References == nil {
+			e.References = []string{}
+		}
+		for i, t := range m.Translations {
+			e.Translations[i] = Translation{
+				Locale:     t.Locale,
+				Translated: t.Translated,
+				Flags:      flagsByLocale[t.Locale][m.Hash],
+				Forms:      forms(t.Forms),
+			}
+		}
+		s.Messages = append(s.Messages, e)
+	}
+	return s, nil
+}
+
+// WriteJSON writes s to w as indented JSON.
+func (s *State) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.SetEscapeHTML(false)
+	return e.Encode(s)
+}
+
+// headers returns all headers of h by name.
+func headers(h gettext.FileHead) map[string]string {
+	m := map[string]string{}
+	for _, x := range h.Headers() {
+		m[x.Name] = x.Value
+	}
+	return m
+}
+
+func coverageOf(c coverage.Coverage) Coverage {
+	return Coverage{Total: c.Total, Translated: c.Translated, Percent: c.Percent()}
+}
+
+// flagsByHash returns the sorted flags of all messages of po by hash.
+// Messages without flags are omitted.
+func flagsByHash(po gettext.FilePO) map[string][]string {
+	m := map[string][]string{}
+	for i := range po.Messages.List {
+		msg := &po.Messages.List[i]
+		if msg.Obsolete {
+			continue
+		}
+		var flags []string
+		for _, c := range msg.Msgctxt.Comments.Text {
+			if c.Type != gettext.CommentTypeFlag {
+				continue
+			}
+			for f := range strings.SplitSeq(c.Value, ",") {
+				if f = strings.TrimSpace(f); f != "" {
+					flags = append(flags, f)
+				}
+			}
+		}
+		if len(flags) > 0 {
+			slices.Sort(flags)
+			m[msg.Msgctxt.Text.String()] = slices.Compact(flags)
+		}
+	}
+	return m
+}
+
+func forms(l []gendocs.Form) []Form {
+	if len(l) == 0 {
+		return nil
+	}
+	f := make([]Form, len(l))
+	for i, x := range l {
+		f[i] = Form{Name: x.Name, Text: x.Text}
+	}
+	return f
+}
@@ -0,0 +1,170 @@
+package exportstate_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/exportstate"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+	require.NoError(t, err)
+}
+
+func TestWriteJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "source.en.po", `msgid ""
+msgstr ""
+"Language: en\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+#. Greeting on the home page.
+#: main.go:10
+#, heading, edition:pro
+msgctxt "b"
+msgid "Welcome"
+msgstr "Welcome"
+
+#: main.go:20
+#: inbox.go:5
+msgctxt "a"
+msgid "%d message"
+msgid_plural "%d messages"
+msgstr[0] "%d message"
+msgstr[1] "%d messages"
+`)
+	writeFile(t, dir, "catalog.de.po", `msgid ""
+msgstr ""
+"Language: de\n"
+"Last-Translator: Jane <jane@example.com>\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+#, fuzzy
+msgctxt "b"
+msgid "Welcome"
+msgstr "Willkommen"
+
+msgctxt "a"
+msgid "%d message"
+msgid_plural "%d messages"
+msgstr[0] "%d Nachricht"
+msgstr[1] ""
+`)
+	bundle, err := codeparser.ParseBundleDir(dir)
+	require.NoError(t, err)
+
+	s, err := exportstate.Make(bundle)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, s.WriteJSON(&buf))
+	require.Equal(t, `{
+  "schemaVersion": 1,
+  "sourceLocale": "en",
+  "locales": [
+    {
+      "locale": "en",
+      "headers": {
+        "Language": "en",
+        "Plural-Forms": "nplurals=2; plural=n != 1;"
+      },
+      "coverage": {
+        "total": 2,
+        "translated": 2,
+        "percent": 100
+      }
+    },
+    {
+      "locale": "de",
+      "headers": {
+        "Language": "de",
+        "Last-Translator": "Jane <jane@example.com>",
+        "Plural-Forms": "nplurals=2; plural=n != 1;"
+      },
+      "coverage": {
+        "total": 2,
+        "translated": 1,
+        "percent": 50
+      }
+    }
+  ],
+  "messages": [
+    {
+      "hash": "a",
+      "references": [
+        "main.go:20",
+        "inbox.go:5"
+      ],
+      "source": [
+        {
+          "name": "One",
+          "text": "%d message"
+        },
+        {
+          "name": "Other",
+          "text": "%d messages"
+        }
+      ],
+      "translations": [
+        {
+          "locale": "de",
+          "translated": false,
+          "forms": [
+            {
+              "name": "One",
+              "text": "%d Nachricht"
+            },
+            {
+              "name": "Other",
+              "text": ""
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "hash": "b",
+      "description": "Greeting on the home page.",
+      "flags": [
+        "edition:pro",
+        "heading"
+      ],
+      "references": [
+        "main.go:10"
+      ],
+      "source": [
+        {
+          "text": "Welcome"
+        }
+      ],
+      "translations": [
+        {
+          "locale": "de",
+          "translated": true,
+          "flags": [
+            "fuzzy"
+          ],
+          "forms": [
+            {
+              "text": "Willkommen"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`, buf.String())
+}
+
+func TestMakeNoSource(t *testing.T) {
+	bundle, err := codeparser.ParseBundleDir(t.TempDir())
+	require.NoError(t, err)
+	_, err = exportstate.Make(bundle)
+	require.Error(t, err)
+}
@@ -1,12 +1,16 @@
 package fmtplaceholder
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 var regexpGoFmtPlaceholders = regexp.MustCompile(
-	`%[#0\-+\s]*\d*(?:\.\d*)?[bcdeEfFgGopqstTvxXUO%]`,
+	`%[#0\-+\s]*\d*(?:\.\d*)?[bcdeEfFgGopqstTvwxXUO%]`,
 )
 
 // Extract returns all Go fmt placeholder like %s, %d, %v, %q, etc. from s.
@@ -24,3 +28,141 @@ func Numeric(s string) bool {
 	}
 	return strings.IndexByte(numericPlaceholders, s[len(s)-1]) != -1
 }
+
+// Pattern returns a regular expression matching the texts s formats to.
+// Placeholders match any non-empty text and %% matches %.
+func Pattern(s string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString(`(?s)^`)
+	last := 0
+	for _, loc := range regexpGoFmtPlaceholders.FindAllStringIndex(s, -1) {
+		b.WriteString(regexp.QuoteMeta(s[last:loc[0]]))
+		if s[loc[0]:loc[1]] == "%%" {
+			b.WriteString("%")
+		} else {
+			b.WriteString(".+?")
+		}
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(s[last:]))
+	b.WriteString(`$`)
+	return regexp.MustCompile(b.String())
+}
+
+// Suspicious returns the placeholders and stray percent signs of s that are
+// likely unintended, such as "% o" in "50% off", which fmt formats
+// as an octal number, or "%" in "100%".
+func Suspicious(s string) []string {
+	var l []string
+	last := 0
+	for _, loc := range regexpGoFmtPlaceholders.FindAllStringIndex(s, -1) {
+		l = appendStray(l, s[last:loc[0]])
+		if p := s[loc[0]:loc[1]]; strings.ContainsAny(p, " \t\n\v\f\r") {
+			l = append(l, p)
+		}
+		last = loc[1]
+	}
+	return appendStray(l, s[last:])
+}
+
+// regexpSegment matches the placeholders of rich segments like "%[link]s"
+// (see package localizerich).
+var regexpSegment = regexp.MustCompile(`^%\[[A-Za-z_]\w*\]s`)
+
+// appendStray appends all percent signs in s including
+// the character following them to l, except for segment placeholders.
+func appendStray(l []string, s string) []string {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		if loc := regexpSegment.FindStringIndex(s[i:]); loc != nil {
+			i += loc[1] - 1
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i+1:])
+		l = append(l, s[i:i+1+size])
+	}
+	return l
+}
+
+var (
+	ErrTokenUnknown   = errors.New("unknown placeholder token")
+	ErrTokenCorrupted = errors.New("corrupted placeholder token")
+	ErrTokenMissing   = errors.New("missing placeholder token")
+	ErrTokenRepeated  = errors.New("repeated placeholder token")
+	ErrUntokenized    = errors.New("placeholder not tokenized")
+)
+
+// TokenOpen and TokenClose enclose the number of protected tokens
+// like "⟦1⟧" (see Tokenize).
+const TokenOpen, TokenClose = "⟦", "⟧"
+
+// regexpTokenizable matches the placeholders replaced by Tokenize, which
+// are Go fmt placeholders including explicit argument indexes like "%[1]d"
+// and the segment placeholders of package localizerich like "%[link]s".
+var regexpTokenizable = regexp.MustCompile(
+	`%(?:\[\w+\])?[#0\-+ ]*\d*(?:\.\d*)?[bcdeEfFgGopqstTvwxXUO%]`,
+)
+
+// regexpToken matches protected tokens tolerating the spaces machine
+// translation tends to insert, like "⟦ 1 ⟧".
+var regexpToken = regexp.MustCompile(`⟦\s*(\d+)\s*⟧`)
+
+// Tokenize replaces the placeholders of s with protected tokens numbered
+// in order of appearance, like "Hello ⟦1⟧, you have ⟦2⟧ messages" for
+// "Hello %s, you have %d messages", such that tools unaware of Go fmt
+// placeholders can't corrupt them. Returns the placeholders by token
+// number starting with 1 at index 0.
+func Tokenize(s string) (tokenized string, placeholders []string) {
+	tokenized = regexpTokenizable.ReplaceAllStringFunc(s, func(p string) string {
+		placeholders = append(placeholders, p)
+		return TokenOpen + strconv.Itoa(len(placeholders)) + TokenClose
+	})
+	return tokenized, placeholders
+}
+
+// Detokenize replaces the protected tokens of s with placeholders
+// (see Tokenize). Every token may be used at most once since Go fmt
+// consumes one argument per placeholder. If all is true every placeholder
+// except "%%" must be used, which is required for complete translations
+// but not for plural forms that omit the quantity like "One file".
+// Returns an error wrapping ErrTokenUnknown, ErrTokenCorrupted,
+// ErrTokenMissing, ErrTokenRepeated or ErrUntokenized if s is invalid.
+func Detokenize(s string, placeholders []string, all bool) (string, error) {
+	used := make([]bool, len(placeholders))
+	var err error
+	restored := regexpToken.ReplaceAllStringFunc(s, func(t string) string {
+		n, _ := strconv.Atoi(regexpToken.FindStringSubmatch(t)[1])
+		switch {
+		case err != nil:
+		case n < 1 || n > len(placeholders):
+			err = fmt.Errorf("%w: %s", ErrTokenUnknown, t)
+		case used[n-1] && placeholders[n-1] != "%%":
+			err = fmt.Errorf("%w: %s", ErrTokenRepeated, t)
+		default:
+			used[n-1] = true
+			return placeholders[n-1]
+		}
+		return t
+	})
+	if err != nil {
+		return "", err
+	}
+	rest := regexpToken.ReplaceAllString(s, "")
+	if p := regexpTokenizable.FindString(rest); p != "" && p != "%%" {
+		return "", fmt.Errorf("%w: %s", ErrUntokenized, p)
+	}
+	if strings.Contains(rest, TokenOpen) || strings.Contains(rest, TokenClose) {
+		return "", ErrTokenCorrupted
+	}
+	if all {
+		for i, u := range used {
+			if !u && placeholders[i] != "%%" {
+				return "", fmt.Errorf("%w: %s%d%s (%s)",
+					ErrTokenMissing, TokenOpen, i+1, TokenClose, placeholders[i])
+			}
+		}
+	}
+	return restored, nil
+}
@@ -46,6 +46,8 @@ func TestPlaceholders(t *testing.T) {
 	)
 	// String / Slice / Pointer
 	f(t, []string{"%s", "%q", "%x", "%X", "%p"}, "%s, %q, %x, %X, %p")
+	// Wrapped errors of fmt.Errorf
+	f(t, []string{"%q", "%w"}, "opening %q: %w")
 }
 
 func TestNumeric(t *testing.T) {
@@ -111,3 +113,94 @@ func TestNumeric(t *testing.T) {
 	f(t, true, "%e")
 	f(t, true, "%E")
 }
+
+func TestPattern(t *testing.T) {
+	t.Parallel()
+	f := func(t *testing.T, expect bool, format, input string) {
+		t.Helper()
+		require.Equal(t, expect, fmtplaceholder.Pattern(format).MatchString(input))
+	}
+
+	f(t, true, "", "")
+	f(t, true, "Hallo (Welt)", "Hallo (Welt)")
+	f(t, false, "Hallo (Welt)", "Hallo Welt")
+	f(t, true, "Du hast %d Nachrichten", "Du hast 5 Nachrichten")
+	f(t, true, "%s hat %.2f%% erreicht", "Anna hat 99.50% erreicht")
+	f(t, false, "Du hast %d Nachrichten", "Du hast  Nachrichten")
+	f(t, false, "Du hast %d Nachrichten", "Sie haben 5 Nachrichten")
+	f(t, true, "Zeile 1\n%s", "Zeile 1\nZeile 2")
+}
+
+func TestSuspicious(t *testing.T) {
+	t.Parallel()
+	f := func(t *testing.T, expect []string, input string) {
+		t.Helper()
+		require.Equal(t, expect, fmtplaceholder.Suspicious(input))
+	}
+
+	f(t, nil, "")
+	f(t, nil, "No placeholders")
+	f(t, nil, "%d of %s done, %.2f%% remaining")
+	f(t, []string{"% o"}, "50% off")
+	f(t, []string{"%"}, "100%")
+	f(t, []string{"%z"}, "%z and %d")
+	f(t, []string{"%ä"}, "%ä")
+	f(t, []string{"%\nd"}, "100%\ndone")
+	f(t, []string{"% d", "%"}, "% d and %")
+	f(t, nil, "Accept the %[terms]s and %[privacy_policy]s.")
+	f(t, []string{"%["}, "%[1 link]s")
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+	f := func(t *testing.T, expect string, expectPlaceholders []string, input string) {
+		t.Helper()
+		tokenized, placeholders := fmtplaceholder.Tokenize(input)
+		require.Equal(t, expect, tokenized)
+		require.Equal(t, expectPlaceholders, placeholders)
+	}
+
+	f(t, "", nil, "")
+	f(t, "No placeholders", nil, "No placeholders")
+	f(t, "Hello ⟦1⟧, you have ⟦2⟧ messages",
+		[]string{"%s", "%d"}, "Hello %s, you have %d messages")
+	f(t, "⟦1⟧ reached ⟦2⟧⟦3⟧",
+		[]string{"%s", "%.2f", "%%"}, "%s reached %.2f%%")
+	f(t, "Accept the ⟦1⟧ and ⟦2⟧.",
+		[]string{"%[terms]s", "%[privacy_policy]s"},
+		"Accept the %[terms]s and %[privacy_policy]s.")
+	f(t, "⟦1⟧ of ⟦2⟧", []string{"%[2]d", "%[1]d"}, "%[2]d of %[1]d")
+}
+
+func TestDetokenize(t *testing.T) {
+	t.Parallel()
+	f := func(t *testing.T, expect string, placeholders []string, all bool, input string) {
+		t.Helper()
+		s, err := fmtplaceholder.Detokenize(input, placeholders, all)
+		require.NoError(t, err)
+		require.Equal(t, expect, s)
+	}
+	fErr := func(t *testing.T, expect error, placeholders []string, all bool, input string) {
+		t.Helper()
+		_, err := fmtplaceholder.Detokenize(input, placeholders, all)
+		require.ErrorIs(t, err, expect)
+	}
+
+	f(t, "", nil, true, "")
+	f(t, "Hallo %s, du hast %d Nachrichten", []string{"%s", "%d"}, true,
+		"Hallo ⟦1⟧, du hast ⟦2⟧ Nachrichten")
+	f(t, "%d Nachrichten für %s", []string{"%s", "%d"}, true,
+		"⟦ 2 ⟧ Nachrichten für ⟦1 ⟧")
+	f(t, "%s erreichte %.2f%%", []string{"%s", "%.2f", "%%"}, true,
+		"⟦1⟧ erreichte ⟦2⟧%%")
+	f(t, "%%%% von %s", []string{"%%", "%s"}, true, "⟦1⟧⟦1⟧ von ⟦2⟧")
+	f(t, "Eine Datei", []string{"%d"}, false, "Eine Datei")
+
+	fErr(t, fmtplaceholder.ErrTokenMissing, []string{"%d"}, true, "Eine Datei")
+	fErr(t, fmtplaceholder.ErrTokenUnknown, []string{"%d"}, true, "⟦1⟧ und ⟦2⟧")
+	fErr(t, fmtplaceholder.ErrTokenUnknown, []string{"%d"}, true, "⟦0⟧")
+	fErr(t, fmtplaceholder.ErrTokenRepeated, []string{"%s"}, true, "⟦1⟧ und ⟦1⟧")
+	fErr(t, fmtplaceholder.ErrTokenCorrupted, []string{"%d"}, false, "⟦1 Dateien")
+	fErr(t, fmtplaceholder.ErrTokenCorrupted, []string{"%d"}, false, "⟦eins⟧ Datei")
+	fErr(t, fmtplaceholder.ErrUntokenized, []string{"%d"}, false, "%d Dateien")
+}
@@ -0,0 +1,152 @@
+// Package fuzzy carries the translations of messages whose source text
+// changed forward to the messages replacing them. Like in GNU gettext,
+// translations that need review are flagged `#, fuzzy` in catalogs
+// and aren't used by the generated Go bundle until the flag is removed.
+package fuzzy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+)
+
+// Flag is the catalog flag of translations that need review.
+const Flag = "fuzzy"
+
+// Resolution is the resolution of a translated message whose
+// source text changed.
+type Resolution string
+
+const (
+	// Clear leaves the translation in the obsolete message only,
+	// such that the replacing message is untranslated.
+	Clear Resolution = "clear"
+
+	// Keep carries the translation forward as is.
+	Keep Resolution = "keep"
+
+	// Fuzzy carries the translation forward flagged as fuzzy.
+	Fuzzy Resolution = "fuzzy"
+
+	// Prompt asks for the resolution of every message.
+	// Prompt is a policy only, messages are never resolved as Prompt.
+	Prompt Resolution = "prompt"
+)
+
+// Resolutions lists all valid resolution policies.
+var Resolutions = []string{
+	string(Clear), string(Keep), string(Fuzzy), string(Prompt),
+}
+
+// ParseResolution parses a resolution policy.
+func ParseResolution(s string) (Resolution, error) {
+	if !slices.Contains(Resolutions, s) {
+		return "", fmt.Errorf("invalid resolution %q, use either of: %s",
+			s, strings.Join(Resolutions, ", "))
+	}
+	return Resolution(s), nil
+}
+
+// Is returns true if m is flagged as fuzzy.
+func Is(m *gettext.Message) bool {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeFlag {
+			continue
+		}
+		for f := range strings.SplitSeq(c.Value, ",") {
+			if strings.TrimSpace(f) == Flag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Set adds or removes the fuzzy flag of m preserving all other flags.
+func Set(m *gettext.Message, fuzzy bool) {
+	l := m.Msgctxt.Comments.Text[:0]
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeFlag {
+			var flags []string
+			for f := range strings.SplitSeq(c.Value, ",") {
+				if f = strings.TrimSpace(f); f != Flag {
+					flags = append(flags, f)
+				}
+			}
+			if len(flags) == 0 {
+				continue // Remove comments containing only the fuzzy flag.
+			}
+			if len(flags) <= strings.Count(c.Value, ",") {
+				c.Value = strings.Join(flags, ", ")
+			}
+		}
+		l = append(l, c)
+	}
+	if fuzzy {
+		l = append(l, gettext.Comment{Type: gettext.CommentTypeFlag, Value: Flag})
+	}
+	m.Msgctxt.Comments.Text = l
+}
+
+// Match returns the message of previous that src replaces, which is the
+// first message of previous of the same kind (plural or not) sharing a
+// code reference with src. Returns -1 if there is none.
+func Match(previous []*gettext.Message, src *gettext.Message) int {
+	plural := len(src.MsgidPlural.Text.Lines) > 0
+	refs := references(src)
+	return slices.IndexFunc(previous, func(m *gettext.Message) bool {
+		if plural != (len(m.MsgidPlural.Text.Lines) > 0) {
+			return false
+		}
+		for _, r := range references(m) {
+			if slices.Contains(refs, r) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func references(m *gettext.Message) (refs []string) {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeReference {
+			refs = append(refs, c.Value)
+		}
+	}
+	return refs
+}
+
+// Carry copies the translation of from to dst resolved as r.
+// dst isn't changed if r is Clear.
+func Carry(dst, from *gettext.Message, r Resolution) {
+	switch r {
+	case Keep, Fuzzy:
+	default:
+		return
+	}
+	dst.Msgstr, dst.Msgstr0, dst.Msgstr1 = from.Msgstr, from.Msgstr0, from.Msgstr1
+	dst.Msgstr2, dst.Msgstr3 = from.Msgstr2, from.Msgstr3
+	dst.Msgstr4, dst.Msgstr5 = from.Msgstr4, from.Msgstr5
+	if r == Fuzzy {
+		Set(dst, true)
+	}
+}
+
+// Untranslated returns a copy of m with empty translations if m is
+// flagged as fuzzy and m otherwise.
+func Untranslated(m gettext.Message) gettext.Message {
+	if !Is(&m) {
+		return m
+	}
+	for _, s := range [...]*gettext.Msgstr{
+		&m.Msgstr, &m.Msgstr0, &m.Msgstr1, &m.Msgstr2,
+		&m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+	} {
+		if len(s.Text.Lines) > 0 {
+			s.Text = gettext.StringLiterals{Lines: []gettext.StringLiteral{{}}}
+		}
+	}
+	return m
+}
@@ -0,0 +1,104 @@
+package fuzzy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/fuzzy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "heading"},
+	}
+	require.False(t, fuzzy.Is(m))
+
+	fuzzy.Set(m, true)
+	fuzzy.Set(m, true)
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "heading"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+	}, m.Msgctxt.Comments.Text)
+	require.True(t, fuzzy.Is(m))
+
+	m.Msgctxt.Comments.Text[1].Value = "fuzzy, heading"
+	fuzzy.Set(m, false)
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "heading"},
+	}, m.Msgctxt.Comments.Text)
+	require.False(t, fuzzy.Is(m))
+}
+
+func TestParseResolution(t *testing.T) {
+	r, err := fuzzy.ParseResolution("keep")
+	require.NoError(t, err)
+	require.Equal(t, fuzzy.Keep, r)
+
+	_, err = fuzzy.ParseResolution("merge")
+	require.ErrorContains(t, err, "use either of: clear, keep, fuzzy, prompt")
+}
+
+func decode(t *testing.T, s string) []gettext.Message {
+	t.Helper()
+	po, err := gettext.NewDecoder().DecodePO("test.po", strings.NewReader(
+		"msgid \"\"\nmsgstr \"\"\n"+
+			"\"Plural-Forms: nplurals=2; plural=n != 1;\\n\"\n"+s,
+	))
+	require.NoError(t, err)
+	return po.Messages.List
+}
+
+func TestMatchCarry(t *testing.T) {
+	previous := decode(t, `
+#: /a.go:1
+msgctxt "a"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] "%d Dateien"
+
+#: /a.go:1
+#: /b.go:2
+msgctxt "b"
+msgid "Hello"
+msgstr "Hallo"
+`)
+	added := decode(t, `
+#: /b.go:2
+msgctxt "c"
+msgid "Hello!"
+msgstr ""
+
+#: /c.go:3
+msgctxt "d"
+msgid "Bye"
+msgstr ""
+`)
+	l := []*gettext.Message{&previous[0], &previous[1]}
+	require.Equal(t, 1, fuzzy.Match(l, &added[0]))
+	require.Equal(t, -1, fuzzy.Match(l, &added[1]))
+
+	c := added[0].Clone()
+	fuzzy.Carry(&c, &previous[1], fuzzy.Clear)
+	require.Equal(t, "", c.Msgstr.Text.String())
+
+	fuzzy.Carry(&c, &previous[1], fuzzy.Keep)
+	require.Equal(t, "Hallo", c.Msgstr.Text.String())
+	require.False(t, fuzzy.Is(&c))
+
+	fuzzy.Carry(&added[0], &previous[1], fuzzy.Fuzzy)
+	require.Equal(t, "Hallo", added[0].Msgstr.Text.String())
+	require.True(t, fuzzy.Is(&added[0]))
+
+	u := fuzzy.Untranslated(added[0])
+	require.Equal(t, "", u.Msgstr.Text.String())
+	require.True(t, fuzzy.Is(&u))
+	require.Equal(t, "Hallo", added[0].Msgstr.Text.String())
+	require.Equal(t, previous[0], fuzzy.Untranslated(previous[0]))
+}
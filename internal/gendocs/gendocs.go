@@ -0,0 +1,244 @@
+// Package gendocs provides the static documentation site generator.
+package gendocs
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	ttemplate "text/template"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/badge"
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/coverage"
+	"golang.org/x/text/language"
+)
+
+//go:embed index.html.gotmpl
+var templateIndexHTML string
+
+//go:embed index.md.gotmpl
+var templateIndexMD string
+
+//go:embed locale.md.gotmpl
+var templateLocaleMD string
+
+// Format is the output format of the documentation.
+type Format string
+
+const (
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+)
+
+// Site is the documentation data model.
+type Site struct {
+	SourceLocale string
+	Locales      []Locale
+	Messages     []Message
+}
+
+// Locale is a translation catalog of the bundle.
+type Locale struct {
+	Tag      string
+	Coverage coverage.Coverage
+}
+
+// Percent returns the formatted coverage percentage.
+func (l Locale) Percent() string { return fmt.Sprintf("%.0f%%", l.Coverage.Percent()) }
+
+// BadgeColor returns the color of the coverage badge.
+func (l Locale) BadgeColor() string { return badge.Color(l.Coverage) }
+
+// Message is a single source message and all of its translations.
+type Message struct {
+	Hash         string
+	Description  string
+	Source       []Form
+	References   []string
+	Translations []Translation
+}
+
+// Translation is a translation of a message in a particular locale.
+type Translation struct {
+	Locale     string
+	Translated bool
+	Forms      []Form
+}
+
+// Form is either the text of a static message or a plural form.
+// Name is empty for static messages.
+type Form struct{ Name, Text string }
+
+// Make creates the documentation data model for bundle.
+func Make(bundle *codeparser.Bundle) (*Site, error) {
+	if bundle.Source == nil {
+		return nil, fmt.Errorf("bundle has no source catalog, run generate first")
+	}
+	sourceForms, ok := cldr.ByTagOrBase(bundle.SourceLocale)
+	if !ok {
+		return nil, fmt.Errorf("couldn't find plural forms for locale: %s",
+			bundle.SourceLocale.String())
+	}
+
+	s := &Site{SourceLocale: bundle.SourceLocale.String()}
+
+	type catalog struct {
+		locale      language.Tag
+		pluralForms cldr.PluralForms
+		byHash      map[string]*gettext.Message
+	}
+	catalogs := make([]catalog, 0, len(bundle.Catalogs))
+	for locale, c := range bundle.Catalogs {
+		pluralForms, ok := cldr.ByTagOrBase(locale)
+		if !ok {
+			return nil, fmt.Errorf("couldn't find plural forms for locale: %s",
+				locale.String())
+		}
+		byHash := make(map[string]*gettext.Message, len(c.Messages.List))
+		for i := range c.Messages.List {
+			if m := &c.Messages.List[i]; !m.Obsolete {
+				byHash[m.Msgctxt.Text.String()] = m
+			}
+		}
+		catalogs = append(catalogs, catalog{
+			locale:      locale,
+			pluralForms: pluralForms,
+			byHash:      byHash,
+		})
+		s.Locales = append(s.Locales, Locale{
+			Tag:      locale.String(),
+			Coverage: coverage.Of(bundle.Source.FilePO, c.FilePO),
+		})
+	}
+	slices.SortFunc(catalogs, func(a, b catalog) int {
+		return strings.Compare(a.locale.String(), b.locale.String())
+	})
+	slices.SortFunc(s.Locales, func(a, b Locale) int {
+		return strings.Compare(a.Tag, b.Tag)
+	})
+
+	for i := range bundle.Source.Messages.List {
+		src := &bundle.Source.Messages.List[i]
+		if src.Obsolete {
+			continue
+		}
+		hash := src.Msgctxt.Text.String()
+		m := Message{
+			Hash:   hash,
+			Source: forms(sourceForms.CardinalForms, src),
+		}
+		for _, c := range src.Msgctxt.Comments.Text {
+			switch c.Type {
+			case gettext.CommentTypeExtracted:
+				if !codeparser.IsMetadataComment(c.Value) {
+					m.Description = c.Value
+				}
+			case gettext.CommentTypeReference:
+				m.References = append(m.References, c.Value)
+			}
+		}
+		for _, c := range catalogs {
+			t := Translation{Locale: c.locale.String()}
+			if cm, ok := c.byHash[hash]; ok {
+				t.Translated = coverage.IsTranslated(cm)
+				t.Forms = forms(c.pluralForms.CardinalForms, cm)
+			}
+			m.Translations = append(m.Translations, t)
+		}
+		s.Messages = append(s.Messages, m)
+	}
+	slices.SortFunc(s.Messages, func(a, b Message) int {
+		return strings.Compare(a.Hash, b.Hash)
+	})
+	return s, nil
+}
+
+// forms returns the texts of m named by their CLDR plural forms.
+func forms(cardinalForms []cldr.CLDRPluralForm, m *gettext.Message) []Form {
+	if len(m.MsgidPlural.Text.Lines) == 0 {
+		return []Form{{Text: m.Msgstr.Text.String()}}
+	}
+	indexed := [...]*gettext.Msgstr{
+		&m.Msgstr0, &m.Msgstr1, &m.Msgstr2, &m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+	}
+	f := make([]Form, 0, len(cardinalForms))
+	for i, cf := range cardinalForms {
+		if i >= len(indexed) {
+			break
+		}
+		f = append(f, Form{Name: cf.String(), Text: indexed[i].Text.String()})
+	}
+	return f
+}
+
+// WriteDir writes the documentation site in format to directory dir.
+func WriteDir(dir string, format Format, site *Site) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	switch format {
+	case FormatHTML:
+		tmpl, err := template.New("index").Parse(templateIndexHTML)
+		if err != nil {
+			return fmt.Errorf("parsing template: %w", err)
+		}
+		return writeFile(filepath.Join(dir, "index.html"), func(w io.Writer) error {
+			return tmpl.Execute(w, site)
+		})
+	case FormatMarkdown:
+		tmplIndex, err := ttemplate.New("index").Parse(templateIndexMD)
+		if err != nil {
+			return fmt.Errorf("parsing template: %w", err)
+		}
+		tmplLocale, err := ttemplate.New("locale").Parse(templateLocaleMD)
+		if err != nil {
+			return fmt.Errorf("parsing template: %w", err)
+		}
+		err = writeFile(filepath.Join(dir, "index.md"), func(w io.Writer) error {
+			return tmplIndex.Execute(w, site)
+		})
+		if err != nil {
+			return err
+		}
+		for i, l := range site.Locales {
+			type localeInfo struct {
+				Locale   Locale
+				Index    int
+				Messages []Message
+			}
+			err := writeFile(
+				filepath.Join(dir, l.Tag+".md"), func(w io.Writer) error {
+					return tmplLocale.Execute(w, localeInfo{
+						Locale:   l,
+						Index:    i,
+						Messages: site.Messages,
+					})
+				},
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported format: %q", format)
+}
+
+func writeFile(path string, fn func(w io.Writer) error) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening output file: %w", err)
+	}
+	if err := fn(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("rendering %s: %w", filepath.Base(path), err)
+	}
+	return f.Close()
+}
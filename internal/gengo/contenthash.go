@@ -0,0 +1,107 @@
+package gengo
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cespare/xxhash"
+	"github.com/romshark/localize/gettext"
+)
+
+// contentHashPrefix prefixes the comment line carrying the content hash,
+// which is the second line of generated files.
+const contentHashPrefix = "// Content hash: "
+
+// SetContentHash inserts a comment line carrying the hash of src
+// after the first line of src. Generated files are only rewritten
+// if their content hash changed, see ContentHash.
+func SetContentHash(src []byte) (withHash []byte, hash string) {
+	hash = strconv.FormatUint(xxhash.Sum64(src), 16)
+	first, rest, _ := bytes.Cut(src, []byte("\n"))
+	withHash = make([]byte, 0, len(src)+len(contentHashPrefix)+len(hash)+1)
+	withHash = append(withHash, first...)
+	withHash = append(withHash, '\n')
+	withHash = append(withHash, contentHashPrefix...)
+	withHash = append(withHash, hash...)
+	withHash = append(withHash, '\n')
+	return append(withHash, rest...), hash
+}
+
+// ContentHash returns the content hash of src set by SetContentHash.
+// ok is false if src has no content hash.
+func ContentHash(src []byte) (hash string, ok bool) {
+	_, rest, _ := bytes.Cut(src, []byte("\n"))
+	line, _, _ := bytes.Cut(rest, []byte("\n"))
+	h, ok := bytes.CutPrefix(line, []byte(contentHashPrefix))
+	return string(h), ok
+}
+
+// catalogHashPrefix prefixes the comment lines carrying the hashes
+// of the catalogs the bundle was generated from.
+const catalogHashPrefix = "// Catalog hash "
+
+type catalogHash struct{ File, Hash string }
+
+// CatalogHash returns the hash of the translated messages of a catalog
+// by their contexts, source texts and translations independent of
+// their order. Changes made by generate, such as updated comments,
+// obsolete messages and new untranslated messages, don't change the hash.
+func CatalogHash(messages []gettext.Message) string {
+	entries := make([]string, 0, len(messages))
+	for i := range messages {
+		m := &messages[i]
+		msgstrs := [...]*gettext.Msgstr{
+			&m.Msgstr, &m.Msgstr0, &m.Msgstr1, &m.Msgstr2,
+			&m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+		}
+		var b strings.Builder
+		translated := false
+		for _, s := range []string{
+			m.Msgctxt.Text.String(), m.Msgid.Text.String(), m.MsgidPlural.Text.String(),
+		} {
+			b.WriteString(s)
+			b.WriteByte(0)
+		}
+		for _, s := range msgstrs {
+			t := s.Text.String()
+			translated = translated || t != ""
+			b.WriteString(t)
+			b.WriteByte(0)
+		}
+		if translated {
+			entries = append(entries, b.String())
+		}
+	}
+	slices.Sort(entries)
+	h := xxhash.New()
+	for _, e := range entries {
+		_, _ = h.Write([]byte(e))
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// CatalogHashes returns the hashes of the catalogs by file name
+// recorded in the generated bundle src. Returns nil if src records none.
+func CatalogHashes(src []byte) map[string]string {
+	var m map[string]string
+	for line := range bytes.Lines(src) {
+		if bytes.HasPrefix(line, []byte("package ")) {
+			break
+		}
+		rest, ok := bytes.CutPrefix(line, []byte(catalogHashPrefix))
+		if !ok {
+			continue
+		}
+		file, hash, ok := strings.Cut(strings.TrimSpace(string(rest)), ": ")
+		if !ok {
+			continue
+		}
+		if m == nil {
+			m = make(map[string]string)
+		}
+		m[file] = hash
+	}
+	return m
+}
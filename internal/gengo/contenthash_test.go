@@ -0,0 +1,71 @@
+package gengo_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/gengo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentHash(t *testing.T) {
+	src := []byte("// Code generated. DO NOT EDIT.\n\npackage bundle\n")
+	withHash, hash := gengo.SetContentHash(src)
+	require.NotEmpty(t, hash)
+	require.Equal(t, "// Code generated. DO NOT EDIT.\n// Content hash: "+hash+
+		"\n\npackage bundle\n", string(withHash))
+
+	h, ok := gengo.ContentHash(withHash)
+	require.True(t, ok)
+	require.Equal(t, hash, h)
+
+	_, ok = gengo.ContentHash(src)
+	require.False(t, ok)
+
+	_, other := gengo.SetContentHash([]byte("// Code generated. DO NOT EDIT.\n\npackage other\n"))
+	require.NotEqual(t, hash, other)
+}
+
+func TestCatalogHash(t *testing.T) {
+	msg := func(ctx, id, str string) gettext.Message {
+		var m gettext.Message
+		m.Msgctxt.Text.Lines = []gettext.StringLiteral{{Value: ctx}}
+		m.Msgid.Text.Lines = []gettext.StringLiteral{{Value: id}}
+		m.Msgstr.Text.Lines = []gettext.StringLiteral{{Value: str}}
+		return m
+	}
+	hash := gengo.CatalogHash([]gettext.Message{
+		msg("a", "Hello", "Hallo"), msg("b", "Bye", "Tschüss"),
+	})
+	require.NotEqual(t, gengo.CatalogHash(nil), hash)
+
+	// Order, comments, obsolete marks and untranslated messages are ignored.
+	commented := msg("b", "Bye", "Tschüss")
+	commented.Obsolete = true
+	commented.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+	}
+	require.Equal(t, hash, gengo.CatalogHash([]gettext.Message{
+		commented, msg("c", "New", ""), msg("a", "Hello", "Hallo"),
+	}))
+	require.Equal(t, gengo.CatalogHash(nil),
+		gengo.CatalogHash([]gettext.Message{msg("c", "New", "")}))
+
+	require.NotEqual(t, hash, gengo.CatalogHash([]gettext.Message{
+		msg("a", "Hello", "Servus"), msg("b", "Bye", "Tschüss"),
+	}))
+}
+
+func TestCatalogHashes(t *testing.T) {
+	src := []byte("// Code generated. DO NOT EDIT.\n" +
+		"// - De\n" +
+		"//\n" +
+		"// Catalog hash catalog.de.po: 1a2b\n" +
+		"// Catalog hash catalog.checkout.de.po: 3c4d\n" +
+		"\npackage bundle\n\n// Catalog hash catalog.fr.po: 5e6f\n")
+	require.Equal(t, map[string]string{
+		"catalog.de.po":          "1a2b",
+		"catalog.checkout.de.po": "3c4d",
+	}, gengo.CatalogHashes(src))
+	require.Nil(t, gengo.CatalogHashes([]byte("package bundle\n")))
+}
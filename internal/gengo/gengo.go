@@ -5,62 +5,223 @@ import (
 	_ "embed"
 	"fmt"
 	"io"
+	"maps"
+	"path/filepath"
+	"slices"
 	"strings"
-	"text/template"
+	"time"
 
 	"github.com/romshark/localize"
 	"github.com/romshark/localize/gettext"
 	"github.com/romshark/localize/internal/cldr"
 	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/fuzzy"
+	"github.com/romshark/localize/internal/heading"
+	"github.com/romshark/localize/internal/protect"
+	"github.com/romshark/localize/internal/section"
+	"github.com/romshark/localize/strfmt"
+	"github.com/romshark/localize/typography"
 	"golang.org/x/text/language"
 )
 
 //go:embed template.gotmpl
 var templateGotmpl string
 
+// Options are optional code generation settings.
+type Options struct {
+	// Transform is applied to all translated texts of the translation
+	// catalogs if not nil.
+	Transform func(locale language.Tag, s string) string
+
+	// HeadingCasing are the casings applied to the translations of messages
+	// flagged as heading (see package heading) by locale.
+	// Protected substrings (see package protect) are left untouched.
+	HeadingCasing map[language.Tag]typography.Casing
+
+	// HashIndex enables generating the functions SourceByHash and HashOf
+	// resolving the hashes of messages to their source texts and back.
+	HashIndex bool
+
+	// PluralFallback is the locale whose translator is used for locales
+	// without CLDR data (see cldr.SetFallback).
+	// language.Und selects the CLDR root locale.
+	PluralFallback language.Tag
+
+	// IncludeObsolete compiles the translations of obsolete messages
+	// into the lookups by source text unless a message in use shares
+	// the source text. Obsolete messages aren't listed by Messages.
+	IncludeObsolete bool
+
+	// TemplateDir is the directory of the templates customizing
+	// the generated code if not empty (see ParseTemplate).
+	TemplateDir string
+}
+
+type localeInfo struct {
+	Tag language.Tag
+	// GoPlaygroundPkg is the subpackage name of the repository
+	// "github.com/go-playground/locales
+	GoPlaygroundPkg string
+	// Str is necessary because regular BCP 47 notation can't
+	// be used in Go import aliases and type names.
+	Str string
+	// Forms maps CLDR plural form names to the names of the forms used,
+	// which differ only if forms are merged by a plural forms override.
+	Forms map[string]string
+}
+
+type typeName struct {
+	Exported   string
+	Unexported string
+}
+
+type pluralMsg struct {
+	SourceOther string
+	Translated  localize.Forms
+}
+
+type catalogMsg struct {
+	Key         localize.Key
+	Translation localize.Translation
+}
+
+type staticMsg struct {
+	Source     string
+	Translated string
+}
+
+type grammarMsg struct {
+	// ID is the grammar entry ID (see localize.GrammarID).
+	ID         string
+	Translated string
+}
+
+type variants struct {
+	// Register is the Go expression of the register of the variants.
+	Register       string
+	StaticMessages []staticMsg
+	PluralMessages []pluralMsg
+}
+
+// readerInfo is the data of template "reader" executed
+// after the methods of every reader type.
+type readerInfo struct {
+	TypeName typeName
+	Locale   localeInfo
+	// Source is true for the reader of the source locale.
+	Source bool
+}
+
+type catalogInfo struct {
+	TypeName        typeName
+	Locale          localeInfo
+	Reader          readerInfo
+	POFile          gettext.FilePO
+	StaticMessages  []staticMsg
+	PluralMessages  []pluralMsg
+	GrammarMessages []grammarMsg
+	Variants        []*variants
+	Messages        []catalogMsg
+	Metadata        []gettext.XHeader
+
+	// Summary is returned by the String method of the reader.
+	Summary string
+
+	// DerivedOne is true if any message has a derived form One.
+	DerivedOne bool
+
+	// CommentForms is true if any Cardinal call provides templates
+	// using plural form comments.
+	CommentForms bool
+}
+
+type scheduleInfo struct {
+	Source string
+	// NotBefore and NotAfter are the Go expressions of the bounds
+	// of the schedule. Empty if the schedule has no such bound.
+	NotBefore, NotAfter string
+}
+
+type goImport struct {
+	Alias string // Empty if the package name is used.
+	Path  string
+}
+
+type tmplInfo struct {
+	Package              string
+	BundleVersion        string
+	HeadComment          []string
+	GeneratorVersion     string
+	SourceTypeName       typeName
+	SourceLocale         localeInfo
+	SourceMessagesStatic []string
+	SourceMessagesPlural []codeparser.Msg
+	SourceMessages       []catalogMsg
+	SourceMetadata       []gettext.XHeader
+	SourceSummary        string
+	SourceReader         readerInfo
+	Catalogs             []catalogInfo
+
+	// Readers are the readers of the source locale
+	// and all catalogs in the order of their declaration.
+	Readers []readerInfo
+
+	// Imports are the non-standard library imports
+	// sorted by path and alias for reproducible output.
+	Imports []goImport
+
+	// Reflowed are the texts of all messages formatted with
+	// strfmt.DedentReflow.
+	Reflowed []string
+
+	// Schedules are the schedules of all time-limited messages.
+	Schedules []scheduleInfo
+
+	// DerivedOne are the messages whose form One is derived
+	// (see codeparser.LoadOptions.DeriveOne).
+	DerivedOne []codeparser.Msg
+
+	// CommentForms are the messages of Cardinal calls providing templates
+	// using plural form comments.
+	CommentForms []codeparser.Msg
+
+	// HashIndex is Options.HashIndex.
+	HashIndex bool
+
+	// Normalization is the Go expression of the normalization
+	// of the source texts, empty if they aren't normalized.
+	Normalization string
+
+	// HashesBySource are the keys of all messages sorted by source text
+	// with only the lowest hash of every source text.
+	HashesBySource []localize.Key
+
+	// CatalogHashes are the hashes of all catalog files
+	// sorted by file name (see CatalogHash).
+	CatalogHashes []catalogHash
+}
+
 func Write(
 	w io.Writer, sourceLocale language.Tag, headComment []string,
 	packageName string, collection *codeparser.Collection, bundle *codeparser.Bundle,
+	opts Options,
 ) error {
-	tmpl, err := template.New("gen").Parse(templateGotmpl)
+	tmpl, err := ParseTemplate(opts.TemplateDir)
 	if err != nil {
-		return fmt.Errorf("rendering template: %w", err)
-	}
-	type localeInfo struct {
-		Tag language.Tag
-		// GoPlaygroundPkg is the subpackage name of the repository
-		// "github.com/go-playground/locales
-		GoPlaygroundPkg string
-		// Str is necessary because regular BCP 47 notation can't
-		// be used in Go import aliases and type names.
-		Str string
-	}
-	type typeName struct {
-		Exported   string
-		Unexported string
-	}
-	type pluralMsg struct {
-		SourceOther string
-		Translated  localize.Forms
-	}
-	type catalogInfo struct {
-		TypeName       typeName
-		Locale         localeInfo
-		POFile         gettext.FilePO
-		PluralMessages []pluralMsg
-	}
-	type tmplInfo struct {
-		Package              string
-		BundleVersion        string
-		HeadComment          []string
-		GeneratorVersion     string
-		SourceTypeName       typeName
-		SourceLocale         localeInfo
-		SourceMessagesStatic []string
-		SourceMessagesPlural []codeparser.Msg
-		Catalogs             []catalogInfo
+		return err
 	}
+	info, err := makeInfo(headComment, packageName, collection, bundle, opts)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, info)
+}
 
+// makeInfo returns the template data of the bundle.
+func makeInfo(
+	headComment []string, packageName string,
+	collection *codeparser.Collection, bundle *codeparser.Bundle, opts Options,
+) (tmplInfo, error) {
 	tpNameSource := localizationTypeName(collection.Locale)
 	tpNameSourceUnexp := strings.ToLower(tpNameSource[:1]) + tpNameSource[1:]
 	info := tmplInfo{
@@ -74,31 +235,180 @@ func Write(
 		},
 		SourceLocale: localeInfo{
 			Tag:             collection.Locale,
-			GoPlaygroundPkg: goPlaygroundLocalesPkg(collection.Locale),
+			GoPlaygroundPkg: goPlaygroundLocalesPkg(collection.Locale, opts.PluralFallback),
 			Str:             safeLocaleStr(collection.Locale),
+			Forms:           formNames(collection.Locale),
 		},
-		Catalogs: make([]catalogInfo, 0, len(bundle.Catalogs)),
+		Catalogs:  make([]catalogInfo, 0, len(bundle.Catalogs)),
+		HashIndex: opts.HashIndex,
+
+		Normalization: normalizationExpr(collection.Normalization),
+	}
+	for _, parts := range bundle.CatalogParts {
+		for _, p := range parts {
+			info.CatalogHashes = append(info.CatalogHashes, catalogHash{
+				File: filepath.Base(p.Path),
+				Hash: CatalogHash(p.Messages.List),
+			})
+		}
+	}
+	slices.SortFunc(info.CatalogHashes, func(a, b catalogHash) int {
+		return strings.Compare(a.File, b.File)
+	})
+	if bundle.Source != nil {
+		info.SourceMetadata = bundle.Source.Head.Headers()
 	}
 	{
-		for loc, bundle := range bundle.Catalogs {
+		// Catalogs are ordered by locale for deterministic output.
+		locales := slices.SortedFunc(maps.Keys(bundle.Catalogs),
+			func(a, b language.Tag) int { return strings.Compare(a.String(), b.String()) })
+		for _, loc := range locales {
+			bundle := bundle.Catalogs[loc]
 			cldrData, ok := cldr.ByTagOrBase(loc)
 			if !ok {
-				return fmt.Errorf("resolving plural forms by locale: %s", loc.String())
+				return tmplInfo{}, fmt.Errorf(
+					"resolving plural forms by locale: %s", loc.String(),
+				)
 			}
 			tpName := localizationTypeName(loc)
 			tpNameUnexp := strings.ToLower(tpName[:1]) + tpName[1:]
 
+			transform := func(s string) string {
+				if opts.Transform == nil || s == "" {
+					return s
+				}
+				return opts.Transform(loc, s)
+			}
+
+			casing := opts.HeadingCasing[loc]
+			// transformMsg transforms s of msg and applies the heading casing
+			// if msg is a heading.
+			transformMsg := func(msg *gettext.Message, s string) string {
+				if casing != typography.CasingNone && s != "" && heading.Is(msg) {
+					s = casing.Apply(loc, s, protect.Of(msg)...)
+				}
+				return transform(s)
+			}
+
+			staticMessages := []staticMsg{}
 			pluralMessages := []pluralMsg{}
+			grammarMessages := []grammarMsg{}
+			variantsByRegister := map[localize.Register]*variants{}
+			messages := []catalogMsg{}
+			// inUse are the source texts of the messages that aren't obsolete,
+			// which take precedence over obsolete messages sharing them.
+			inUse := map[string]bool{}
+			if opts.IncludeObsolete {
+				for _, msg := range bundle.Messages.List {
+					if !msg.Obsolete {
+						inUse[sourceText(&msg)] = true
+					}
+				}
+			}
 			for _, msg := range bundle.Messages.List {
-				if msg.Obsolete || len(msg.MsgidPlural.Text.Lines) == 0 {
+				if msg.Obsolete {
+					if !opts.IncludeObsolete || inUse[sourceText(&msg)] ||
+						isAuxiliary(&msg) {
+						continue
+					}
+					inUse[sourceText(&msg)] = true
+				}
+				// Fuzzy translations need review and fall back to the source.
+				msg = fuzzy.Untranslated(msg)
+				if reg, ok := strings.CutPrefix(
+					msg.Msgctxt.Text.String(), localize.RegisterContextPrefix,
+				); ok {
+					// Register variants are auxiliary data, not messages.
+					register, ok := localize.ParseRegister(reg)
+					if !ok || register == localize.RegisterDefault {
+						continue
+					}
+					v := variantsByRegister[register]
+					if v == nil {
+						v = &variants{Register: registerExpr(register)}
+						variantsByRegister[register] = v
+					}
+					if len(msg.MsgidPlural.Text.Lines) == 0 {
+						if s := transform(msg.Msgstr.Text.String()); s != "" {
+							v.StaticMessages = append(v.StaticMessages, staticMsg{
+								Source:     msg.Msgid.Text.String(),
+								Translated: s,
+							})
+						}
+						continue
+					}
+					f := pluralFromGettextMsg(cldrData.CardinalForms, &msg)
+					if f.Other == "" {
+						continue
+					}
+					f.Zero, f.One, f.Two = transform(f.Zero), transform(f.One), transform(f.Two)
+					f.Few, f.Many, f.Other = transform(f.Few), transform(f.Many), transform(f.Other)
+					v.PluralMessages = append(v.PluralMessages, pluralMsg{
+						SourceOther: msg.MsgidPlural.Text.String(),
+						Translated:  f,
+					})
+					continue
+				}
+				if ctx := msg.Msgctxt.Text.String(); strings.HasPrefix(
+					ctx, localize.GrammarContextPrefix,
+				) {
+					// Grammar entries are auxiliary data, not messages.
+					if s := msg.Msgstr.Text.String(); s != "" {
+						grammarMessages = append(grammarMessages, grammarMsg{
+							ID:         ctx + "\x04" + msg.Msgid.Text.String(),
+							Translated: s,
+						})
+					}
+					continue
+				}
+				if len(msg.MsgidPlural.Text.Lines) == 0 {
+					translated := transformMsg(&msg, msg.Msgstr.Text.String())
+					if len(msg.Msgstr.Text.Lines) > 0 {
+						staticMessages = append(staticMessages, staticMsg{
+							Source:     section.ID(&msg, msg.Msgid.Text.String()),
+							Translated: translated,
+						})
+					}
+					if msg.Obsolete {
+						continue
+					}
+					messages = append(messages, catalogMsg{
+						Key: localize.Key{
+							Hash:    msg.Msgctxt.Text.String(),
+							Source:  msg.Msgid.Text.String(),
+							Section: scopedSection(&msg),
+						},
+						Translation: localize.Translation{Text: translated},
+					})
 					continue
 				}
 				f := pluralFromGettextMsg(cldrData.CardinalForms, &msg)
+				f.Zero, f.One = transformMsg(&msg, f.Zero), transformMsg(&msg, f.One)
+				f.Two, f.Few = transformMsg(&msg, f.Two), transformMsg(&msg, f.Few)
+				f.Many, f.Other = transformMsg(&msg, f.Many), transformMsg(&msg, f.Other)
 				pluralMessages = append(pluralMessages, pluralMsg{
-					SourceOther: msg.MsgidPlural.Text.String(),
+					SourceOther: section.ID(&msg, msg.MsgidPlural.Text.String()),
 					Translated:  f,
 				})
+				if msg.Obsolete {
+					continue
+				}
+				messages = append(messages, catalogMsg{
+					Key: localize.Key{
+						Hash:    msg.Msgctxt.Text.String(),
+						Source:  msg.MsgidPlural.Text.String(),
+						Section: scopedSection(&msg),
+					},
+					Translation: localize.Translation{Plural: true, Forms: f},
+				})
 			}
+			slices.SortFunc(messages, func(a, b catalogMsg) int {
+				return strings.Compare(a.Key.Hash, b.Key.Hash)
+			})
+			registerVariants := slices.SortedFunc(maps.Values(variantsByRegister),
+				func(a, b *variants) int {
+					return strings.Compare(a.Register, b.Register)
+				})
 
 			info.Catalogs = append(info.Catalogs, catalogInfo{
 				TypeName: typeName{
@@ -108,25 +418,144 @@ func Write(
 				Locale: localeInfo{
 					Tag:             loc,
 					Str:             safeLocaleStr(loc),
-					GoPlaygroundPkg: goPlaygroundLocalesPkg(loc),
+					GoPlaygroundPkg: goPlaygroundLocalesPkg(loc, opts.PluralFallback),
+					Forms:           formNames(loc),
 				},
-				POFile:         bundle.FilePO,
-				PluralMessages: pluralMessages,
+				POFile:          bundle.FilePO,
+				StaticMessages:  staticMessages,
+				PluralMessages:  pluralMessages,
+				GrammarMessages: grammarMessages,
+				Variants:        registerVariants,
+				Messages:        messages,
+				Metadata:        bundle.Head.Headers(),
 			})
+			translated := 0
+			for _, m := range messages {
+				if m.Translation.Text != "" || m.Translation.Forms.Other != "" {
+					translated++
+				}
+			}
+			info.Catalogs[len(info.Catalogs)-1].Summary = summary(
+				loc, info.BundleVersion, info.GeneratorVersion,
+				len(messages), translated,
+			)
 		}
 	}
 
-	for m := range collection.Ordered() {
+	info.Imports = []goImport{
+		{Path: "github.com/go-playground/locales"},
+		{Path: "github.com/romshark/localize"},
+		{Path: "github.com/romshark/localize/strfmt"},
+		{Path: "golang.org/x/text/language"},
+		{
+			Alias: "locales" + info.SourceLocale.Str,
+			Path:  info.SourceLocale.GoPlaygroundPkg,
+		},
+	}
+	for _, c := range info.Catalogs {
+		info.Imports = append(info.Imports, goImport{
+			Alias: "locales" + c.Locale.Str,
+			Path:  c.Locale.GoPlaygroundPkg,
+		})
+	}
+	slices.SortFunc(info.Imports, func(a, b goImport) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Alias, b.Alias)
+	})
+
+	for m, meta := range collection.Ordered() {
+		if m.Reflow {
+			info.Reflowed = append(info.Reflowed, m.Other)
+		}
+		if s := meta.Schedule; !s.IsZero() {
+			info.Schedules = append(info.Schedules, scheduleInfo{
+				Source:    m.Other,
+				NotBefore: timeExpr(s.NotBefore),
+				NotAfter:  timeExpr(s.NotAfter),
+			})
+		}
+		if meta.DerivedOne {
+			info.DerivedOne = append(info.DerivedOne, m)
+		}
+		if meta.CommentForms {
+			info.CommentForms = append(info.CommentForms, m)
+		}
+		key := localize.Key{Hash: m.Hash, Source: m.Other, Section: m.Scope}
 		switch m.FuncType {
 		case codeparser.FuncTypeText, codeparser.FuncTypeBlock:
 			info.SourceMessagesStatic = append(info.SourceMessagesStatic, m.Other)
+			info.SourceMessages = append(info.SourceMessages, catalogMsg{
+				Key:         key,
+				Translation: localize.Translation{Text: m.Other},
+			})
 		case codeparser.FuncTypePlural, codeparser.FuncTypePluralBlock:
 			info.SourceMessagesPlural = append(info.SourceMessagesPlural, m)
+			info.SourceMessages = append(info.SourceMessages, catalogMsg{
+				Key: key,
+				Translation: localize.Translation{Plural: true, Forms: localize.Forms{
+					Zero:  m.Zero,
+					One:   m.One,
+					Two:   m.Two,
+					Few:   m.Few,
+					Many:  m.Many,
+					Other: m.Other,
+				}},
+			})
 		default:
 			panic("normally unreachable")
 		}
 	}
-	return tmpl.Execute(w, info)
+	if info.HashIndex {
+		for _, m := range info.SourceMessages {
+			info.HashesBySource = append(info.HashesBySource, m.Key)
+		}
+		// The stable sort keeps the lowest hash first among equal sources.
+		slices.SortStableFunc(info.HashesBySource, func(a, b localize.Key) int {
+			return strings.Compare(a.Source, b.Source)
+		})
+		info.HashesBySource = slices.CompactFunc(info.HashesBySource,
+			func(a, b localize.Key) bool { return a.Source == b.Source })
+	}
+	info.SourceReader = readerInfo{
+		TypeName: info.SourceTypeName, Locale: info.SourceLocale, Source: true,
+	}
+	info.Readers = []readerInfo{info.SourceReader}
+	for i := range info.Catalogs {
+		c := &info.Catalogs[i]
+		c.Reader = readerInfo{TypeName: c.TypeName, Locale: c.Locale}
+		c.DerivedOne = len(info.DerivedOne) > 0
+		c.CommentForms = len(info.CommentForms) > 0
+		info.Readers = append(info.Readers, c.Reader)
+	}
+	info.SourceSummary = summary(
+		collection.Locale, info.BundleVersion, info.GeneratorVersion,
+		len(info.SourceMessages), len(info.SourceMessages),
+	)
+	return info, nil
+}
+
+// registerExpr returns the Go expression of register.
+func registerExpr(register localize.Register) string {
+	switch register {
+	case localize.RegisterFormal:
+		return "localize.RegisterFormal"
+	case localize.RegisterInformal:
+		return "localize.RegisterInformal"
+	}
+	return "localize.RegisterDefault"
+}
+
+// summary returns the summary of a catalog returned by the String
+// and GoString methods of its generated reader.
+func summary(
+	locale language.Tag, bundleVersion, generatorVersion string,
+	messages, translated int,
+) string {
+	return fmt.Sprintf("localize catalog %q (bundle version %s, "+
+		"generator version %s): %d messages, %d translated",
+		locale.String(), bundleVersion, generatorVersion, messages, translated)
 }
 
 func localizationTypeName(locale language.Tag) string {
@@ -149,11 +578,74 @@ func safeLocaleStr(t language.Tag) string {
 	return strings.ToUpper(s[:1]) + s[1:]
 }
 
-func goPlaygroundLocalesPkg(t language.Tag) string {
+// normalizationExpr returns the Go expression of n,
+// or "" for strfmt.NormalizeNone.
+func normalizationExpr(n strfmt.Normalization) string {
+	var l []string
+	if n&strfmt.NormalizeNFC != 0 {
+		l = append(l, "strfmt.NormalizeNFC")
+	}
+	if n&strfmt.NormalizeSpaces != 0 {
+		l = append(l, "strfmt.NormalizeSpaces")
+	}
+	return strings.Join(l, " | ")
+}
+
+// goPlaygroundLocalesPkg returns the import path of the translator of t,
+// or of fallback if there's no CLDR data for t.
+func goPlaygroundLocalesPkg(t, fallback language.Tag) string {
+	if !cldr.Supported(t) {
+		if fallback == language.Und {
+			return "github.com/go-playground/locales/root"
+		}
+		t = fallback
+	}
 	tag := strings.ReplaceAll(t.String(), "-", "_")
 	return "github.com/go-playground/locales/" + tag
 }
 
+// formNames maps the names of all CLDR plural forms
+// to the names of the forms used for locale.
+func formNames(locale language.Tag) map[string]string {
+	pluralForms, ok := cldr.ByTagOrBase(locale)
+	m := make(map[string]string, 6)
+	for f := cldr.CLDRPluralFormZero; f <= cldr.CLDRPluralFormOther; f++ {
+		r := pluralForms.Resolve(f)
+		if ok && !slices.Contains(pluralForms.CardinalForms, r) {
+			// Catalogs have no form for categories their gettext rules merge
+			// into Other, like Many for Russian.
+			r = cldr.CLDRPluralFormOther
+		}
+		m[f.String()] = r.String()
+	}
+	return m
+}
+
+// scopedSection returns the section of m if m is scoped and "" otherwise.
+func scopedSection(m *gettext.Message) string {
+	if section.IsScoped(m) {
+		return section.Of(m)
+	}
+	return ""
+}
+
+// sourceText returns the source text of m, which is form Other
+// of plural messages, identified by the section of scoped messages
+// (see section.ID).
+func sourceText(m *gettext.Message) string {
+	if len(m.MsgidPlural.Text.Lines) > 0 {
+		return section.ID(m, m.MsgidPlural.Text.String())
+	}
+	return section.ID(m, m.Msgid.Text.String())
+}
+
+// isAuxiliary returns true for grammar entries and register variants.
+func isAuxiliary(m *gettext.Message) bool {
+	ctx := m.Msgctxt.Text.String()
+	return strings.HasPrefix(ctx, localize.GrammarContextPrefix) ||
+		strings.HasPrefix(ctx, localize.RegisterContextPrefix)
+}
+
 // pluralFromGettextMsg translates GNU gettext indexed messages to CLDR forms.
 func pluralFromGettextMsg(
 	formsCLDR []cldr.CLDRPluralForm,
@@ -197,3 +689,11 @@ func pluralFromGettextMsg(
 	}
 	return f
 }
+
+// timeExpr returns the Go expression of t or an empty string if t is zero.
+func timeExpr(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("time.Unix(%d, %d)", t.Unix(), t.Nanosecond())
+}
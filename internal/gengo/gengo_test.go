@@ -0,0 +1,391 @@
+package gengo_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/gengo"
+	"github.com/romshark/localize/strfmt"
+	"github.com/romshark/localize/typography"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestWriteDeterministic(t *testing.T) {
+	collection := &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Hello"}: {},
+			{Hash: "h2", FuncType: codeparser.FuncTypeText, Other: "Bye"}:   {},
+		},
+	}
+	bundle := &codeparser.Bundle{
+		Catalogs:     map[language.Tag]codeparser.POFile{},
+		SourceLocale: language.English,
+	}
+	locales := []string{"uk", "de", "fr", "pt-BR", "ar", "ja", "de-CH", "ru"}
+	d := gettext.NewDecoder()
+	for _, l := range locales {
+		tag := language.MustParse(l)
+		po, err := d.DecodePOBytes(l+".po", []byte(`msgid ""
+msgstr ""
+"Language: `+l+`\n"
+
+msgctxt "h1"
+msgid "Hello"
+msgstr "Hello `+l+`"
+`))
+		require.NoError(t, err)
+		bundle.Catalogs[tag] = codeparser.POFile{Path: l + ".po", FilePO: po}
+	}
+
+	write := func() []byte {
+		var buf bytes.Buffer
+		err := gengo.Write(&buf, language.English, nil, "localizebundle",
+			collection, bundle, gengo.Options{})
+		require.NoError(t, err)
+		return buf.Bytes()
+	}
+	first := write()
+	for range 10 {
+		require.Equal(t, string(first), string(write()))
+	}
+
+	f, err := parser.ParseFile(token.NewFileSet(), "bundle_gen.go", first,
+		parser.ImportsOnly)
+	require.NoError(t, err)
+	var thirdParty []string
+	for _, imp := range f.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		require.NoError(t, err)
+		if strings.Contains(path, ".") {
+			thirdParty = append(thirdParty, path)
+		}
+	}
+	require.True(t, slices.IsSorted(thirdParty), "imports not sorted: %v", thirdParty)
+
+	// Catalog types are declared in the order of their locales.
+	var last int
+	for _, name := range []string{
+		"CatalogAr", "CatalogDe", "CatalogDeCH", "CatalogFr",
+		"CatalogJa", "CatalogPtBR", "CatalogRu", "CatalogUk",
+	} {
+		i := bytes.Index(first, []byte("type "+name+" struct"))
+		require.Greater(t, i, last, name)
+		last = i
+	}
+}
+
+func TestWriteSchedules(t *testing.T) {
+	collection := &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Sale!"}: {
+				Schedule: localize.Schedule{
+					NotBefore: time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC),
+					NotAfter:  time.Date(2025, 12, 1, 23, 59, 59, 0, time.UTC),
+				},
+			},
+			{Hash: "h2", FuncType: codeparser.FuncTypeText, Other: "Hello"}: {},
+		},
+	}
+	bundle := &codeparser.Bundle{
+		Catalogs:     map[language.Tag]codeparser.POFile{},
+		SourceLocale: language.English,
+	}
+	var buf bytes.Buffer
+	err := gengo.Write(&buf, language.English, nil, "localizebundle",
+		collection, bundle, gengo.Options{})
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), `"Sale!": {NotBefore: time.Unix(1764288000, 0),`+
+		`NotAfter: time.Unix(1764633599, 0),},`)
+	require.Contains(t, buf.String(), "\n\t\"time\"\n")
+	_, err = parser.ParseFile(token.NewFileSet(), "bundle_gen.go", buf.Bytes(), 0)
+	require.NoError(t, err)
+
+	// Bundles without schedules don't import package time.
+	delete(collection.Messages, codeparser.Msg{
+		Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Sale!",
+	})
+	buf.Reset()
+	err = gengo.Write(&buf, language.English, nil, "localizebundle",
+		collection, bundle, gengo.Options{})
+	require.NoError(t, err)
+	require.NotContains(t, buf.String(), `"time"`)
+}
+
+func TestWriteHeadingCasing(t *testing.T) {
+	collection := &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Account Settings"}: {
+				Heading: true,
+			},
+			{Hash: "h2", FuncType: codeparser.FuncTypeText, Other: "Save Changes"}: {},
+		},
+	}
+	po, err := gettext.NewDecoder().DecodePOBytes("fr.po", []byte(`msgid ""
+msgstr ""
+"Language: fr\n"
+
+#, heading
+msgctxt "h1"
+msgid "Account Settings"
+msgstr "Paramètres Du Compte"
+
+msgctxt "h2"
+msgid "Save Changes"
+msgstr "Enregistrer Les Modifications"
+`))
+	require.NoError(t, err)
+	bundle := &codeparser.Bundle{
+		Catalogs: map[language.Tag]codeparser.POFile{
+			language.French: {Path: "fr.po", FilePO: po},
+		},
+		SourceLocale: language.English,
+	}
+	var buf bytes.Buffer
+	err = gengo.Write(&buf, language.English, nil, "localizebundle",
+		collection, bundle, gengo.Options{
+			HeadingCasing: map[language.Tag]typography.Casing{
+				language.French: typography.CasingSentence,
+			},
+		})
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), `"Account Settings": "Paramètres du compte",`)
+	// Only headings are affected.
+	require.Contains(t, buf.String(), `"Save Changes": "Enregistrer Les Modifications",`)
+}
+
+func TestWriteHashIndex(t *testing.T) {
+	collection := &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Open"}:  {},
+			{Hash: "h0", FuncType: codeparser.FuncTypeText, Other: "Open"}:  {},
+			{Hash: "h2", FuncType: codeparser.FuncTypeText, Other: "Close"}: {},
+		},
+	}
+	bundle := &codeparser.Bundle{
+		Catalogs:     map[language.Tag]codeparser.POFile{},
+		SourceLocale: language.English,
+	}
+	write := func(hashIndex bool) string {
+		var buf bytes.Buffer
+		err := gengo.Write(&buf, language.English, nil, "localizebundle",
+			collection, bundle, gengo.Options{HashIndex: hashIndex})
+		require.NoError(t, err)
+		_, err = parser.ParseFile(token.NewFileSet(), "bundle_gen.go", buf.Bytes(), 0)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	require.NotContains(t, write(false), "func SourceByHash")
+
+	s := write(true)
+	require.Contains(t, s, "func SourceByHash(hash string) (source string, ok bool)")
+	require.Contains(t, s, "func HashOf(source string) (hash string, ok bool)")
+	require.Regexp(t, `var sourceByHash = map\[string\]string\{\s*`+
+		`"h0": "Open",\s*"h1": "Open",\s*"h2": "Close",\s*\}`, s)
+	// The lowest hash of messages sharing a source text is used.
+	require.Regexp(t, `var hashBySource = map\[string\]string\{\s*`+
+		`"Close": "h2",\s*"Open": "h0",\s*\}`, s)
+}
+
+func TestWriteIncludeObsolete(t *testing.T) {
+	collection := &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Save"}: {},
+		},
+	}
+	po, err := gettext.NewDecoder().DecodePOBytes("de.po", []byte(`msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgctxt "h1"
+msgid "Save"
+msgstr "Speichern"
+
+#~ msgctxt "h0"
+#~ msgid "Save"
+#~ msgstr "Sichern"
+
+#~ msgctxt "h2"
+#~ msgid "Upload"
+#~ msgstr "Hochladen"
+
+#~ msgctxt "h3"
+#~ msgid "One file"
+#~ msgid_plural "%d files"
+#~ msgstr[0] "Eine Datei"
+#~ msgstr[1] "%d Dateien"
+`))
+	require.NoError(t, err)
+	bundle := &codeparser.Bundle{
+		Catalogs: map[language.Tag]codeparser.POFile{
+			language.German: {Path: "de.po", FilePO: po},
+		},
+		SourceLocale: language.English,
+	}
+	write := func(includeObsolete bool) string {
+		var buf bytes.Buffer
+		err := gengo.Write(&buf, language.English, nil, "localizebundle",
+			collection, bundle, gengo.Options{IncludeObsolete: includeObsolete})
+		require.NoError(t, err)
+		_, err = parser.ParseFile(token.NewFileSet(), "bundle_gen.go", buf.Bytes(), 0)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	s := write(false)
+	require.NotContains(t, s, "Hochladen")
+	require.NotContains(t, s, "Dateien")
+
+	s = write(true)
+	require.Contains(t, s, `"Upload": "Hochladen",`)
+	require.Contains(t, s, `"%d files": localize.Forms {`)
+	// Messages in use take precedence.
+	require.Contains(t, s, `"Save": "Speichern",`)
+	require.NotContains(t, s, "Sichern")
+	// Obsolete messages aren't listed.
+	require.NotRegexp(t, `Hash:\s+"h2"`, s)
+	require.Contains(t, s, "1 messages, 1 translated")
+}
+
+func TestWriteSections(t *testing.T) {
+	collection := &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Total"}: {},
+			{
+				Hash: "h2", FuncType: codeparser.FuncTypeText,
+				Other: "Total", Scope: "Checkout",
+			}: {Section: "Checkout"},
+		},
+	}
+	po, err := gettext.NewDecoder().DecodePOBytes("de.po", []byte(`msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgctxt "h1"
+msgid "Total"
+msgstr "Summe"
+
+#. Section: Checkout
+#, scoped
+msgctxt "h2"
+msgid "Total"
+msgstr "Gesamtbetrag"
+
+#. Section: Checkout
+#, scoped
+msgctxt "h3"
+msgid "%d item"
+msgid_plural "%d items"
+msgstr[0] "%d Artikel"
+msgstr[1] "%d Artikel"
+`))
+	require.NoError(t, err)
+	bundle := &codeparser.Bundle{
+		Catalogs: map[language.Tag]codeparser.POFile{
+			language.German: {Path: "de.po", FilePO: po},
+		},
+		SourceLocale: language.English,
+	}
+	var buf bytes.Buffer
+	err = gengo.Write(&buf, language.English, nil, "localizebundle",
+		collection, bundle, gengo.Options{})
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "bundle_gen.go", buf.Bytes(), 0)
+	require.NoError(t, err)
+	s := buf.String()
+	require.Contains(t, s, `"Total": "Summe",`)
+	require.Contains(t, s, strconv.Quote(localize.SectionID("Checkout", "Total"))+
+		`: "Gesamtbetrag",`)
+	require.Contains(t, s, strconv.Quote(localize.SectionID("Checkout", "%d items"))+
+		`: localize.Forms {`)
+	require.Contains(t, s, "func (r CatalogDe) Section(name string) localize.Reader {")
+}
+
+func TestWriteDerivedOne(t *testing.T) {
+	collection := &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			{
+				Hash: "h1", FuncType: codeparser.FuncTypePlural,
+				One: "%d file", Other: "%d files",
+			}: {DerivedOne: true},
+			{
+				Hash: "h2", FuncType: codeparser.FuncTypePlural,
+				One: "One item", Other: "%d items",
+			}: {},
+		},
+	}
+	bundle := &codeparser.Bundle{
+		Catalogs:     map[language.Tag]codeparser.POFile{},
+		SourceLocale: language.English,
+	}
+	write := func() string {
+		var buf bytes.Buffer
+		err := gengo.Write(&buf, language.English, nil, "localizebundle",
+			collection, bundle, gengo.Options{})
+		require.NoError(t, err)
+		_, err = parser.ParseFile(token.NewFileSet(), "bundle_gen.go", buf.Bytes(), 0)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	s := write()
+	require.Regexp(t, `var derivedOne = map\[string\]string\{\s*`+
+		`"%d files": "%d file",\s*\}`, s)
+	require.Contains(t, s, "\ttemplates = withDerivedOne(templates)\n\tvar q float64")
+
+	// Bundles without derived forms don't look them up.
+	collection.Messages = map[codeparser.Msg]codeparser.MsgMeta{
+		{
+			Hash: "h2", FuncType: codeparser.FuncTypePlural,
+			One: "One item", Other: "%d items",
+		}: {},
+	}
+	require.NotContains(t, write(), "withDerivedOne")
+}
+
+func TestWriteNormalization(t *testing.T) {
+	collection := &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Café"}: {},
+		},
+	}
+	bundle := &codeparser.Bundle{
+		Catalogs:     map[language.Tag]codeparser.POFile{},
+		SourceLocale: language.English,
+	}
+	write := func() string {
+		var buf bytes.Buffer
+		err := gengo.Write(&buf, language.English, nil, "localizebundle",
+			collection, bundle, gengo.Options{})
+		require.NoError(t, err)
+		_, err = parser.ParseFile(token.NewFileSet(), "bundle_gen.go", buf.Bytes(), 0)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	require.Contains(t, write(), "func normalize(text string) string { return text }")
+
+	collection.Normalization = strfmt.NormalizeNFC | strfmt.NormalizeSpaces
+	require.Contains(t, write(), "\treturn strfmt.Normalize(text, "+
+		"strfmt.NormalizeNFC | strfmt.NormalizeSpaces)\n")
+}
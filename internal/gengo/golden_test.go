@@ -0,0 +1,296 @@
+package gengo_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/gengo"
+	"github.com/romshark/localize/strfmt"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/tools/go/packages"
+	"mvdan.cc/gofumpt/format"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// goldenCatalogs decodes the catalog sources by locale.
+func goldenCatalogs(t *testing.T, src map[string]string) map[language.Tag]codeparser.POFile {
+	t.Helper()
+	catalogs := map[language.Tag]codeparser.POFile{}
+	for locale, s := range src {
+		name := "catalog." + locale + ".po"
+		po, err := gettext.NewDecoder().DecodePOBytes(name, []byte(s))
+		require.NoError(t, err)
+		catalogs[language.MustParse(locale)] = codeparser.POFile{Path: name, FilePO: po}
+	}
+	return catalogs
+}
+
+// TestWriteGolden compares the formatted bundles of representative
+// collections and catalogs with the golden files in testdata and
+// type-checks them, such that template changes can neither silently
+// change the generated code nor produce bundles that don't compile.
+// Run with -update to rewrite the golden files after intended changes.
+func TestWriteGolden(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		collection *codeparser.Collection
+		catalogs   map[string]string
+		opts       gengo.Options
+	}{
+		{
+			name: "empty",
+			collection: &codeparser.Collection{
+				Locale:   language.English,
+				Messages: map[codeparser.Msg]codeparser.MsgMeta{},
+			},
+			catalogs: map[string]string{
+				"de": `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+`,
+			},
+		},
+		{
+			name: "plurals",
+			collection: &codeparser.Collection{
+				Locale: language.English,
+				Messages: map[codeparser.Msg]codeparser.MsgMeta{
+					{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Save"}: {},
+					{
+						Hash: "h2", FuncType: codeparser.FuncTypeBlock,
+						Other: "Your changes\nwere saved.",
+					}: {},
+					{
+						Hash: "h3", FuncType: codeparser.FuncTypePlural,
+						One: "%d file", Other: "%d files",
+					}: {},
+					{
+						Hash: "h4", FuncType: codeparser.FuncTypePluralBlock,
+						One: "One day\nleft.", Other: "%d days\nleft.",
+					}: {},
+				},
+			},
+			catalogs: map[string]string{
+				"de": `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgctxt "h1"
+msgid "Save"
+msgstr "Speichern"
+
+msgctxt "h2"
+msgid "Your changes\nwere saved."
+msgstr "Deine Änderungen\nwurden gespeichert."
+
+msgctxt "h3"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] "%d Dateien"
+
+msgctxt "h4"
+msgid "One day\nleft."
+msgid_plural "%d days\nleft."
+msgstr[0] ""
+msgstr[1] ""
+`,
+				"ru": `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgctxt "h1"
+msgid "Save"
+msgstr "Сохранить"
+
+msgctxt "h3"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+`,
+				"ja": `msgid ""
+msgstr ""
+"Language: ja\n"
+"Plural-Forms: nplurals=1; plural=0;\n"
+
+msgctxt "h3"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d個のファイル"
+`,
+			},
+		},
+		{
+			name: "options",
+			collection: &codeparser.Collection{
+				Locale:        language.English,
+				Normalization: strfmt.NormalizeNFC,
+				Messages: map[codeparser.Msg]codeparser.MsgMeta{
+					{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Sale!"}: {
+						Schedule: localize.Schedule{
+							NotBefore: time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC),
+						},
+					},
+					{
+						Hash: "h2", FuncType: codeparser.FuncTypeText,
+						Other: "Total", Scope: "Checkout",
+					}: {Section: "Checkout"},
+					{
+						Hash: "h3", FuncType: codeparser.FuncTypePlural,
+						One: "%d item", Other: "%d items",
+					}: {DerivedOne: true},
+					{
+						Hash: "h4", FuncType: codeparser.FuncTypePlural,
+						One: "%d unread email", Other: "%d unread emails",
+					}: {CommentForms: true},
+				},
+			},
+			catalogs: map[string]string{
+				"fr": `msgid ""
+msgstr ""
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+msgctxt "h1"
+msgid "Sale!"
+msgstr "Soldes !"
+
+#. Section: Checkout
+#, scoped
+msgctxt "h2"
+msgid "Total"
+msgstr "Montant total"
+
+#~ msgctxt "h0"
+#~ msgid "Upload"
+#~ msgstr "Téléverser"
+`,
+			},
+			opts: gengo.Options{HashIndex: true, IncludeObsolete: true},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := &codeparser.Bundle{
+				Catalogs:     goldenCatalogs(t, tt.catalogs),
+				SourceLocale: language.English,
+			}
+			var buf bytes.Buffer
+			err := gengo.Write(&buf, language.English, []string{"Golden file."},
+				"localizebundle", tt.collection, bundle, tt.opts)
+			require.NoError(t, err)
+			src, err := format.Source(buf.Bytes(), format.Options{})
+			require.NoError(t, err)
+
+			typeCheck(t, src)
+
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(golden, src, 0o644))
+			}
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			if line, ok := firstDiff(string(expected), string(src)); ok {
+				t.Fatalf("generated bundle differs from %s at line %d "+
+					"(run with -update if intended):\nwant: %q\n got: %q",
+					golden, line.number, line.want, line.got)
+			}
+		})
+	}
+}
+
+type diffLine struct {
+	number    int
+	want, got string
+}
+
+// firstDiff returns the first line differing between want and got.
+func firstDiff(want, got string) (diffLine, bool) {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := range max(len(w), len(g)) {
+		l := diffLine{number: i + 1}
+		if i < len(w) {
+			l.want = w[i]
+		}
+		if i < len(g) {
+			l.got = g[i]
+		}
+		if i >= len(w) || i >= len(g) || l.want != l.got {
+			return l, true
+		}
+	}
+	return diffLine{}, false
+}
+
+// typeCheck type-checks the bundle package of the source files srcs
+// against the export data of their imports.
+func typeCheck(t *testing.T, srcs ...[]byte) *types.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	var files []*ast.File
+	var paths []string
+	for i, src := range srcs {
+		f, err := parser.ParseFile(fset, fmt.Sprintf("bundle%d_gen.go", i), src, 0)
+		require.NoError(t, err)
+		files = append(files, f)
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			require.NoError(t, err)
+			if !slices.Contains(paths, path) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes |
+			packages.NeedImports | packages.NeedDeps,
+		Fset: fset,
+	}, paths...)
+	require.NoError(t, err)
+
+	// Dependencies are imported from the same packages as the imports
+	// referring to them, such that their types are identical.
+	byPath := map[string]*types.Package{}
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		for _, err := range p.Errors {
+			t.Errorf("loading %s: %v", p.PkgPath, err)
+		}
+		byPath[p.PkgPath] = p.Types
+	})
+	conf := types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if p, ok := byPath[path]; ok {
+				return p, nil
+			}
+			return nil, os.ErrNotExist
+		}),
+	}
+	pkg, err := conf.Check("example.com/localizebundle", fset, files, nil)
+	require.NoError(t, err)
+	return pkg
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
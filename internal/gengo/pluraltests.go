@@ -0,0 +1,56 @@
+package gengo
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/romshark/localize/internal/cldr"
+	"golang.org/x/text/language"
+)
+
+//go:embed pluraltests.gotmpl
+var pluralTestsGotmpl string
+
+// WritePluralTests writes a Go test file for the bundle package packageName
+// asserting that the readers of all locales select the plural form of
+// the CLDR plural rules of their locale for the CLDR sample quantities.
+// Locales without CLDR data are skipped.
+func WritePluralTests(w io.Writer, packageName string, locales []language.Tag) error {
+	tmpl, err := template.New("pluraltests").Parse(pluralTestsGotmpl)
+	if err != nil {
+		return fmt.Errorf("rendering template: %w", err)
+	}
+	type sample struct {
+		Quantity int
+		Form     string
+	}
+	type readerInfo struct {
+		TypeName string
+		Samples  []sample
+	}
+	info := struct {
+		Package string
+		Readers []readerInfo
+	}{Package: packageName}
+	for _, loc := range locales {
+		pluralForms, ok := cldr.ByTagOrBase(loc)
+		if !ok {
+			continue
+		}
+		r := readerInfo{TypeName: localizationTypeName(loc)}
+		// Samples of forms merged by an override expect the form they're
+		// merged into.
+		for f := cldr.CLDRPluralFormZero; f <= cldr.CLDRPluralFormOther; f++ {
+			for _, q := range pluralForms.Examples[f] {
+				r.Samples = append(r.Samples, sample{
+					Quantity: q,
+					Form:     pluralForms.Resolve(f).String(),
+				})
+			}
+		}
+		info.Readers = append(info.Readers, r)
+	}
+	return tmpl.Execute(w, info)
+}
@@ -0,0 +1,28 @@
+package gengo_test
+
+import (
+	"bytes"
+	"go/format"
+	"testing"
+
+	"github.com/romshark/localize/internal/gengo"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestWritePluralTests(t *testing.T) {
+	var buf bytes.Buffer
+	err := gengo.WritePluralTests(&buf, "localizebundle", []language.Tag{
+		language.English, language.Russian,
+	})
+	require.NoError(t, err)
+	formatted, err := format.Source(buf.Bytes())
+	require.NoError(t, err)
+	s := string(formatted)
+	require.Contains(t, s, "package localizebundle\n")
+	require.Contains(t, s, `{CatalogEn{}, 1, "One 1"},`)
+	require.Contains(t, s, `{CatalogEn{}, 0, "Other 0"},`)
+	require.Contains(t, s, `{CatalogRu{}, 21, "One 21"},`)
+	require.Contains(t, s, `{CatalogRu{}, 22, "Few 22"},`)
+	require.Contains(t, s, `{CatalogRu{}, 5, "Other 5"},`)
+}
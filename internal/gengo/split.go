@@ -0,0 +1,128 @@
+package gengo
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"text/template"
+
+	"github.com/romshark/localize/internal/codeparser"
+	"golang.org/x/text/language"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// File is a file of a bundle generated by WriteSplit.
+type File struct {
+	// Locale is the locale of the reader declared by the file,
+	// or language.Und for the core file.
+	Locale language.Tag
+
+	// Src is the unformatted Go source code of the file.
+	Src []byte
+}
+
+// splitTemplates are the templates WriteSplit executes to compose files.
+var splitTemplates = []string{"header", "partHeader", "core", "source", "catalog", "extra"}
+
+// WriteSplit generates the same package as Write but splits it into a core
+// file, declaring the head comment and everything shared by the readers,
+// followed by one file per reader in the order of Readers, such that
+// large bundles don't end up in a single file slowing down compilation
+// and tooling. Imports a file doesn't use are removed, hence imports added
+// by the "imports" extension point must be aliased if the package name
+// differs from the last element of the import path.
+//
+// Returns ErrTemplateUndefined if a customized bundle template
+// (see TemplateFileBundle) doesn't define the templates the files
+// are composed of.
+func WriteSplit(
+	sourceLocale language.Tag, headComment []string,
+	packageName string, collection *codeparser.Collection, bundle *codeparser.Bundle,
+	opts Options,
+) ([]File, error) {
+	tmpl, err := ParseTemplate(opts.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range splitTemplates {
+		if t := tmpl.Lookup(name); t == nil || t.Tree == nil {
+			return nil, fmt.Errorf("%w: %q is required for split files",
+				ErrTemplateUndefined, name)
+		}
+	}
+	info, err := makeInfo(headComment, packageName, collection, bundle, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0, len(info.Catalogs)+2)
+	add := func(locale language.Tag, parts ...filePart) error {
+		src, err := executeFile(tmpl, parts...)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Locale: locale, Src: src})
+		return nil
+	}
+	if err := add(language.Und,
+		filePart{"header", info}, filePart{"core", info}, filePart{"extra", info},
+	); err != nil {
+		return nil, err
+	}
+	if err := add(info.SourceLocale.Tag,
+		filePart{"partHeader", info}, filePart{"source", info},
+	); err != nil {
+		return nil, err
+	}
+	for _, c := range info.Catalogs {
+		if err := add(c.Locale.Tag,
+			filePart{"partHeader", info}, filePart{"catalog", c},
+		); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+type filePart struct {
+	template string
+	data     any
+}
+
+// executeFile executes the templates of parts in order and returns
+// the resulting source code without unused imports.
+func executeFile(tmpl *template.Template, parts ...filePart) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, p := range parts {
+		if err := tmpl.ExecuteTemplate(&buf, p.template, p.data); err != nil {
+			return nil, err
+		}
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", buf.Bytes(), parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing generated code: %w", err)
+	}
+	for _, imp := range slices.Clone(f.Imports) {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing import path: %w", err)
+		}
+		if !astutil.UsesImport(f, path) {
+			var name string
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			astutil.DeleteNamedImport(fset, f, name, path)
+		}
+	}
+	buf.Reset()
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, fmt.Errorf("printing generated code: %w", err)
+	}
+	return buf.Bytes(), nil
+}
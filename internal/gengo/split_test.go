@@ -0,0 +1,120 @@
+package gengo_test
+
+import (
+	"bytes"
+	"go/types"
+	"testing"
+	"time"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/gengo"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"mvdan.cc/gofumpt/format"
+)
+
+func TestWriteSplit(t *testing.T) {
+	collection := &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Sale!"}: {
+				Schedule: localize.Schedule{
+					NotBefore: time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			{
+				Hash: "h2", FuncType: codeparser.FuncTypePlural,
+				One: "%d file", Other: "%d files",
+			}: {DerivedOne: true},
+		},
+	}
+	bundle := &codeparser.Bundle{
+		Catalogs: goldenCatalogs(t, map[string]string{
+			"de": `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgctxt "h1"
+msgid "Sale!"
+msgstr "Ausverkauf!"
+`,
+			"ja": `msgid ""
+msgstr ""
+"Language: ja\n"
+"Plural-Forms: nplurals=1; plural=0;\n"
+`,
+		}),
+		SourceLocale: language.English,
+	}
+	opts := gengo.Options{HashIndex: true}
+
+	files, err := gengo.WriteSplit(language.English, []string{"Split."},
+		"localizebundle", collection, bundle, opts)
+	require.NoError(t, err)
+	locales := make([]language.Tag, len(files))
+	srcs := make([][]byte, len(files))
+	for i, f := range files {
+		locales[i] = f.Locale
+		srcs[i], err = format.Source(f.Src, format.Options{})
+		require.NoError(t, err, f.Locale)
+		require.Contains(t, string(srcs[i]), "// Code generated by "+
+			"github.com/romshark/localize/cmd/localize. DO NOT EDIT.\n")
+	}
+	require.Equal(t, []language.Tag{
+		language.Und, language.English, language.German, language.Japanese,
+	}, locales)
+
+	core, en, de := string(srcs[0]), string(srcs[1]), string(srcs[2])
+	require.Contains(t, core, "// Split.\n")
+	require.Contains(t, core, "func New(")
+	require.Contains(t, core, `"time"`)
+	require.NotContains(t, core, "type CatalogEn struct")
+	require.Contains(t, en, "type CatalogEn struct")
+	require.NotContains(t, en, "// Package localizebundle")
+	require.Contains(t, de, "type CatalogDe struct")
+	require.Contains(t, de, `"Sale!": "Ausverkauf!"`)
+	require.NotContains(t, de, `"time"`)
+	require.NotContains(t, de, "CatalogJa")
+
+	// The split package must declare exactly what the single file declares.
+	var buf bytes.Buffer
+	require.NoError(t, gengo.Write(&buf, language.English, []string{"Split."},
+		"localizebundle", collection, bundle, opts))
+	single, err := format.Source(buf.Bytes(), format.Options{})
+	require.NoError(t, err)
+	require.Equal(t, declarations(typeCheck(t, single)), declarations(typeCheck(t, srcs...)))
+}
+
+func TestWriteSplitTemplateUndefined(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		gengo.TemplateFileBundle: `package {{ .Package }}`,
+	})
+	collection := &codeparser.Collection{
+		Locale:   language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{},
+	}
+	bundle := &codeparser.Bundle{SourceLocale: language.English}
+	_, err := gengo.WriteSplit(language.English, nil, "localizebundle",
+		collection, bundle, gengo.Options{TemplateDir: dir})
+	require.ErrorIs(t, err, gengo.ErrTemplateUndefined)
+	require.ErrorContains(t, err, `"header"`)
+}
+
+// declarations returns the package-level declarations of pkg
+// and the methods of its named types.
+func declarations(pkg *types.Package) []string {
+	var l []string
+	for _, name := range pkg.Scope().Names() {
+		l = append(l, name)
+		if tn, ok := pkg.Scope().Lookup(name).(*types.TypeName); ok {
+			if n, ok := tn.Type().(*types.Named); ok {
+				for m := range n.Methods() {
+					l = append(l, name+"."+m.Name())
+				}
+			}
+		}
+	}
+	return l
+}
@@ -0,0 +1,155 @@
+package gengo
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+var (
+	ErrTemplateUndefined = errors.New("template not defined")
+	ErrTemplateUnused    = errors.New("template never executed")
+)
+
+// TemplateFileBundle is the name of the template file replacing
+// the built-in template of the generated Go bundle.
+const TemplateFileBundle = "bundle.gotmpl"
+
+// ExtensionPoints are the templates executed by the built-in template that
+// are empty unless defined by customizing templates:
+//
+//   - "imports" is executed inside the import declaration with the
+//     bundle data and adds import specs.
+//   - "reader" is executed after the methods of every reader type with
+//     the reader data (.TypeName.Exported, .TypeName.Unexported,
+//     .Locale.Tag, .Locale.Str and .Source) and adds methods or declarations.
+//   - "extra" is executed at the end of the file with the bundle data
+//     (.Package, .SourceReader, .Readers and more) and adds declarations.
+var ExtensionPoints = []string{"imports", "reader", "extra"}
+
+// ParseTemplate returns the template of the generated Go bundle customized
+// by the .gotmpl files in dir, or the built-in template if dir is empty.
+// TemplateFileBundle replaces the built-in template, all other files may
+// only define templates, which override the templates of the same name
+// like ExtensionPoints, "pluralForms" and "catalogMessage", or are helpers
+// executed by other templates.
+//
+// Returns ErrTemplateUndefined if a template is executed but not defined
+// and ErrTemplateUnused if a file defines a template that's never executed,
+// such as a misspelled extension point.
+func ParseTemplate(dir string) (*template.Template, error) {
+	src := templateGotmpl
+	var files []string
+	if dir != "" {
+		var err error
+		if files, err = filepath.Glob(filepath.Join(dir, "*.gotmpl")); err != nil {
+			return nil, fmt.Errorf("listing templates: %w", err)
+		}
+		if len(files) < 1 {
+			return nil, fmt.Errorf("no .gotmpl files in template directory %s", dir)
+		}
+		if i := slices.IndexFunc(files, func(f string) bool {
+			return filepath.Base(f) == TemplateFileBundle
+		}); i >= 0 {
+			b, err := os.ReadFile(files[i])
+			if err != nil {
+				return nil, fmt.Errorf("reading template: %w", err)
+			}
+			src, files = string(b), slices.Delete(files, i, i+1)
+		}
+	}
+
+	tmpl, err := template.New("gen").Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	// defined are the templates defined by the files other than the bundle.
+	defined := map[string]string{}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading template: %w", err)
+		}
+		t, err := tmpl.New(filepath.Base(f)).Parse(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("parsing template: %w", err)
+		}
+		if t.Tree != nil && !isBlank(t.Tree.Root) {
+			return nil, fmt.Errorf(
+				"template %s: text outside of define actions is never executed", f,
+			)
+		}
+		for _, d := range t.Templates() {
+			if d.Name() != t.Name() && d.Tree != nil && d.Tree.ParseName == t.Name() {
+				defined[d.Name()] = f
+			}
+		}
+	}
+
+	executed := map[string]bool{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		for name := range executedTemplates(t.Tree.Root) {
+			executed[name] = true
+			if d := tmpl.Lookup(name); d == nil || d.Tree == nil {
+				return nil, fmt.Errorf("%w: %q executed by %q",
+					ErrTemplateUndefined, name, t.Name())
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(defined)) {
+		if !executed[name] {
+			return nil, fmt.Errorf("%w: %q defined in %s, the extension points are: %s",
+				ErrTemplateUnused, name, defined[name], strings.Join(ExtensionPoints, ", "))
+		}
+	}
+	return tmpl, nil
+}
+
+// isBlank returns true if n contains nothing but white space.
+func isBlank(n *parse.ListNode) bool {
+	for _, n := range n.Nodes {
+		t, ok := n.(*parse.TextNode)
+		if !ok || strings.TrimSpace(string(t.Text)) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// executedTemplates returns the names of the templates executed in n.
+func executedTemplates(n parse.Node) map[string]bool {
+	names := map[string]bool{}
+	var walk func(n parse.Node)
+	walk = func(n parse.Node) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n != nil {
+				for _, c := range n.Nodes {
+					walk(c)
+				}
+			}
+		case *parse.TemplateNode:
+			names[n.Name] = true
+		case *parse.IfNode:
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.List)
+			walk(n.ElseList)
+		}
+	}
+	walk(n)
+	return names
+}
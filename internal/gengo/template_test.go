@@ -0,0 +1,133 @@
+package gengo_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/gengo"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"mvdan.cc/gofumpt/format"
+)
+
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644))
+	}
+	return dir
+}
+
+func TestWriteTemplateDir(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"imports.gotmpl": `{{ define "imports" }}"strings"{{ end }}`,
+		"reader.gotmpl": `{{ define "reader" }}
+// LocaleName returns the name of the locale of the reader.
+func (r {{ .TypeName.Exported }}) LocaleName() string {
+	return {{ template "quoted" .Locale.Tag.String }}
+}
+{{ end }}
+
+{{ define "quoted" }}{{ printf "%q" . }}{{ end }}`,
+		"extra.gotmpl": `{{ define "extra" }}
+// LocaleNames returns the names of the locales of all readers.
+func LocaleNames() string {
+	return strings.Join([]string{
+		{{- range .Readers }}{{ template "quoted" .Locale.Tag.String }},{{ end -}}
+	}, ",")
+}
+{{ end }}`,
+	})
+	collection := &codeparser.Collection{
+		Locale: language.English,
+		Messages: map[codeparser.Msg]codeparser.MsgMeta{
+			{Hash: "h1", FuncType: codeparser.FuncTypeText, Other: "Hello"}: {},
+		},
+	}
+	bundle := &codeparser.Bundle{
+		Catalogs: goldenCatalogs(t, map[string]string{
+			"de": "msgid \"\"\nmsgstr \"\"\n\"Language: de\\n\"\n",
+		}),
+		SourceLocale: language.English,
+	}
+	var buf bytes.Buffer
+	err := gengo.Write(&buf, language.English, nil, "localizebundle",
+		collection, bundle, gengo.Options{TemplateDir: dir})
+	require.NoError(t, err)
+	src, err := format.Source(buf.Bytes(), format.Options{})
+	require.NoError(t, err)
+	typeCheck(t, src)
+
+	s := string(src)
+	require.Contains(t, s, "func (r CatalogEn) LocaleName() string {\n\treturn \"en\"\n}")
+	require.Contains(t, s, "func (r CatalogDe) LocaleName() string {\n\treturn \"de\"\n}")
+	require.Contains(t, s, `return strings.Join([]string{"en", "de"}, ",")`)
+}
+
+func TestParseTemplate(t *testing.T) {
+	tmpl, err := gengo.ParseTemplate("")
+	require.NoError(t, err)
+	for _, name := range gengo.ExtensionPoints {
+		require.NotNil(t, tmpl.Lookup(name), name)
+	}
+
+	// Replacing the bundle template.
+	dir := writeTemplates(t, map[string]string{
+		gengo.TemplateFileBundle: `package {{ .Package }}{{ template "extra" . }}`,
+		"extra.gotmpl":           `{{ define "extra" }} // Custom.{{ end }}`,
+	})
+	tmpl, err = gengo.ParseTemplate(dir)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, map[string]string{"Package": "bundle"}))
+	require.Equal(t, "package bundle // Custom.", buf.String())
+
+	for _, tt := range []struct {
+		name   string
+		files  map[string]string
+		expect error
+		msg    string
+	}{
+		{
+			name:   "misspelled extension point",
+			files:  map[string]string{"x.gotmpl": `{{ define "readr" }}{{ end }}`},
+			expect: gengo.ErrTemplateUnused,
+			msg:    `"readr"`,
+		},
+		{
+			name: "undefined in bundle",
+			files: map[string]string{
+				gengo.TemplateFileBundle: `package x{{ template "header" . }}`,
+			},
+			expect: gengo.ErrTemplateUndefined,
+			msg:    `"header"`,
+		},
+		{
+			name:  "text outside define",
+			files: map[string]string{"x.gotmpl": `func X() {}`},
+			msg:   "outside of define actions",
+		},
+		{
+			name:  "no templates",
+			files: map[string]string{"x.txt": ``},
+			msg:   "no .gotmpl files",
+		},
+		{
+			name:  "syntax error",
+			files: map[string]string{"x.gotmpl": `{{ define "reader" }}`},
+			msg:   "parsing template",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := gengo.ParseTemplate(writeTemplates(t, tt.files))
+			if tt.expect != nil {
+				require.ErrorIs(t, err, tt.expect)
+			}
+			require.ErrorContains(t, err, tt.msg)
+		})
+	}
+}
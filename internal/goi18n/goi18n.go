@@ -0,0 +1,431 @@
+// Package goi18n converts go-i18n v2 (github.com/nicksnyder/go-i18n)
+// message files to localize translation catalogs and suggests the
+// Reader calls replacing the go-i18n message IDs in the source code.
+//
+// go-i18n templates like "Hello {{.Name}}" are converted to Go fmt templates
+// like "Hello %s" with the template variables passed as arguments.
+// The plural count variable (PluralCount or Count) of plural messages is
+// converted to the quantity placeholder "%d". Templates that can't be
+// converted, such as conditionals, are kept and reported in Conversion.Notes.
+package goi18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/msgimport"
+	"github.com/romshark/localize/internal/pluralcheck"
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported file format (expected .toml or .json)")
+	ErrNoLocale          = errors.New("file name contains no locale")
+	ErrInvalidMessage    = errors.New("invalid message")
+)
+
+// Message is a message of a go-i18n message file.
+type Message struct {
+	ID          string
+	Description string
+
+	// LeftDelim and RightDelim are the custom template delimiters
+	// of the message, which aren't supported.
+	LeftDelim, RightDelim string
+
+	Zero, One, Two, Few, Many, Other string
+}
+
+// IsPlural returns true if m has any form other than Other.
+func (m Message) IsPlural() bool {
+	return m.Zero != "" || m.One != "" || m.Two != "" || m.Few != "" || m.Many != ""
+}
+
+// LocaleOfFile returns the locale of a go-i18n message file, which is
+// the last dot-separated part of the file name preceding the extension
+// that's a valid BCP 47 locale, like "de" in "active.de.toml".
+func LocaleOfFile(path string) (language.Tag, error) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	parts := strings.Split(name, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if t, err := language.Parse(parts[i]); err == nil {
+			return t, nil
+		}
+	}
+	return language.Und, fmt.Errorf("%w: %q", ErrNoLocale, path)
+}
+
+// ParseFile parses the go-i18n message file at path with contents data.
+// The format is selected by the file extension.
+// Messages are returned ordered by ID.
+func ParseFile(path string, data []byte) ([]Message, error) {
+	var m map[string]any
+	switch filepath.Ext(path) {
+	case ".json":
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("decoding JSON: %w", err)
+		}
+	case ".toml":
+		var err error
+		if m, err = decodeTOML(string(data)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, path)
+	}
+	var l []Message
+	if err := appendMessages(&l, "", m); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(l, func(a, b Message) int { return strings.Compare(a.ID, b.ID) })
+	return l, nil
+}
+
+// reservedKeys are the keys of go-i18n message tables.
+var reservedKeys = []string{
+	"id", "description", "hash", "leftdelim", "rightdelim",
+	"zero", "one", "two", "few", "many", "other",
+}
+
+// appendMessages appends the messages of table m to l. Tables that aren't
+// messages are nested messages whose IDs are prefixed with the table key
+// like go-i18n does.
+func appendMessages(l *[]Message, prefix string, m map[string]any) error {
+	for k, v := range m {
+		id := prefix + k
+		switch v := v.(type) {
+		case string:
+			*l = append(*l, Message{ID: id, Other: v})
+		case map[string]any:
+			if !isMessage(v) {
+				if err := appendMessages(l, id+".", v); err != nil {
+					return err
+				}
+				continue
+			}
+			msg, err := messageOf(id, v)
+			if err != nil {
+				return err
+			}
+			*l = append(*l, msg)
+		default:
+			return fmt.Errorf("%w %q: unsupported value type %T", ErrInvalidMessage, id, v)
+		}
+	}
+	return nil
+}
+
+// isMessage returns true if m contains any reserved key with a string value.
+func isMessage(m map[string]any) bool {
+	for k, v := range m {
+		if _, ok := v.(string); ok &&
+			slices.Contains(reservedKeys, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+func messageOf(id string, m map[string]any) (Message, error) {
+	msg := Message{ID: id}
+	for k, v := range m {
+		s, ok := v.(string)
+		if !ok {
+			return Message{}, fmt.Errorf("%w %q: value of %q must be a string",
+				ErrInvalidMessage, id, k)
+		}
+		switch strings.ToLower(k) {
+		case "id":
+			msg.ID = s
+		case "description":
+			msg.Description = s
+		case "leftdelim":
+			msg.LeftDelim = s
+		case "rightdelim":
+			msg.RightDelim = s
+		case "zero":
+			msg.Zero = s
+		case "one":
+			msg.One = s
+		case "two":
+			msg.Two = s
+		case "few":
+			msg.Few = s
+		case "many":
+			msg.Many = s
+		case "other":
+			msg.Other = s
+		}
+	}
+	return msg, nil
+}
+
+// Conversion is a message of the source locale converted to a localize message.
+type Conversion struct {
+	Message
+
+	// Hash is the hash of the converted message.
+	Hash string
+
+	// Plural is true for messages converted to Reader.Plural calls.
+	Plural bool
+
+	// Forms are the converted texts. Only Other is set for static messages.
+	Forms Forms
+
+	// Args are the names of the template variables passed as fmt arguments
+	// of static messages in order.
+	Args []string
+
+	// Quantity is the name of the plural count variable of plural messages.
+	// Quantity is empty if the templates don't contain it.
+	Quantity string
+
+	// Notes are the issues requiring manual work.
+	Notes []string
+}
+
+// Forms are the texts of a message by CLDR plural form.
+type Forms = msgimport.Forms
+
+// quantityVars are the names of the template variables go-i18n
+// plural counts are commonly passed as.
+var quantityVars = []string{"PluralCount", "Count"}
+
+// templateVar matches template actions printing a variable like "{{.Name}}".
+var templateVar = regexp.MustCompile(`{{-?\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*-?}}`)
+
+// Convert converts the messages of the source locale.
+// Conversions are returned in the order of source.
+func Convert(source []Message) []Conversion {
+	l := make([]Conversion, len(source))
+	for i, m := range source {
+		c := Conversion{Message: m, Plural: m.IsPlural()}
+		if m.LeftDelim != "" || m.RightDelim != "" {
+			c.Notes = append(c.Notes, "custom template delimiters aren't supported")
+		}
+		for _, match := range templateVar.FindAllStringSubmatch(
+			strings.Join([]string{m.Zero, m.One, m.Two, m.Few, m.Many, m.Other}, "\n"),
+			-1,
+		) {
+			name := match[1]
+			if c.Plural && slices.Contains(quantityVars, name) {
+				c.Quantity = name
+			} else if !slices.Contains(c.Args, name) {
+				c.Args = append(c.Args, name)
+			}
+		}
+		if c.Plural && len(c.Args) > 0 {
+			c.Notes = append(c.Notes, fmt.Sprintf(
+				"template variables %s aren't supported in plural messages",
+				strings.Join(c.Args, ", "),
+			))
+			c.Args = nil
+		}
+		var notes []string
+		c.Forms, notes = c.convertForms(m)
+		c.Notes = append(c.Notes, notes...)
+		if c.Plural {
+			for _, s := range c.Forms.List() {
+				for _, err := range pluralcheck.Template(s) {
+					c.Notes = append(c.Notes, fmt.Sprintf("%q: %v", s, err))
+				}
+			}
+		}
+		c.Hash = codeparser.MessageHash(c.Forms.Other, m.Description)
+		l[i] = c
+	}
+	return l
+}
+
+// convertForms converts the templates of m, which is either the source
+// message of c or a translation of it.
+func (c *Conversion) convertForms(m Message) (f Forms, notes []string) {
+	convert := func(s string) string {
+		s, n := c.convertTemplate(s)
+		for _, note := range n {
+			if !slices.Contains(notes, note) {
+				notes = append(notes, note)
+			}
+		}
+		return s
+	}
+	if !c.Plural {
+		return Forms{Other: convert(m.Other)}, notes
+	}
+	return Forms{
+		Zero:  convert(m.Zero),
+		One:   convert(m.One),
+		Two:   convert(m.Two),
+		Few:   convert(m.Few),
+		Many:  convert(m.Many),
+		Other: convert(m.Other),
+	}, notes
+}
+
+// convertTemplate converts go-i18n template s to a Go fmt template.
+func (c *Conversion) convertTemplate(s string) (converted string, notes []string) {
+	formatted := c.Plural || len(c.Args) > 0
+
+	// indexes are the argument indexes of the placeholders in order.
+	var indexes []int
+	for _, match := range templateVar.FindAllStringSubmatch(s, -1) {
+		if i := slices.Index(c.Args, match[1]); i >= 0 {
+			indexes = append(indexes, i+1)
+		}
+	}
+	// Explicit argument indexes are necessary unless
+	// all arguments are used once in order.
+	explicit := len(indexes) != len(c.Args)
+	for i, index := range indexes {
+		explicit = explicit || index != i+1
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range templateVar.FindAllStringSubmatchIndex(s, -1) {
+		text := s[last:loc[0]]
+		if formatted {
+			text = strings.ReplaceAll(text, "%", "%%")
+		}
+		b.WriteString(text)
+		last = loc[1]
+		name := s[loc[2]:loc[3]]
+		switch i := slices.Index(c.Args, name); {
+		case c.Plural && name == c.Quantity:
+			b.WriteString("%d")
+		case i >= 0 && explicit:
+			b.WriteString("%[" + strconv.Itoa(i+1) + "]s")
+		case i >= 0:
+			b.WriteString("%s")
+		default:
+			b.WriteString(s[loc[0]:loc[1]])
+			if !c.Plural {
+				notes = append(notes, fmt.Sprintf(
+					"template variable %s isn't used by the source text", name,
+				))
+			}
+		}
+	}
+	text := s[last:]
+	if formatted {
+		text = strings.ReplaceAll(text, "%", "%%")
+	}
+	b.WriteString(text)
+	converted = b.String()
+
+	if rest := templateVar.ReplaceAllString(s, ""); strings.Contains(rest, "{{") {
+		notes = append(notes, "template actions other than variables aren't supported")
+	}
+	return converted, notes
+}
+
+// Catalog returns the translation catalog of locale for the messages
+// of conversions with the translations of translations,
+// which are go-i18n messages by ID. Messages without translation are
+// left untranslated. notes are the issues of the converted translations.
+func Catalog(
+	locale language.Tag, conversions []Conversion, translations []Message,
+) (po gettext.FilePO, notes []string, err error) {
+	messages := make([]msgimport.Message, len(conversions))
+	byHash := make(map[string]*Conversion, len(conversions))
+	for i := range conversions {
+		c := &conversions[i]
+		messages[i] = msgimport.Message{
+			Hash: c.Hash, Description: c.Description,
+			Plural: c.Plural, Forms: c.Forms,
+		}
+		byHash[c.Hash] = c
+	}
+	byID := make(map[string]Message, len(translations))
+	for _, t := range translations {
+		byID[t.ID] = t
+	}
+
+	po, err = msgimport.Catalog(locale, messages, func(hash string) Forms {
+		c := byHash[hash]
+		t, translated := byID[c.ID]
+		if !translated {
+			return Forms{}
+		}
+		f, n := c.convertForms(t)
+		for _, note := range n {
+			notes = append(notes, c.ID+": "+note)
+		}
+		return f
+	})
+	return po, notes, err
+}
+
+// WriteRewrites writes the Reader calls replacing the go-i18n
+// message IDs of conversions to w, such as:
+//
+//	PersonCats:
+//		// Number of cats of a person.
+//		r.Plural(localize.Forms{
+//			One:   "%d cat",
+//			Other: "%d cats",
+//		}, pluralCount)
+//
+// Notes are written as lines starting with "!" preceding the call.
+func WriteRewrites(w io.Writer, conversions []Conversion) error {
+	var b strings.Builder
+	for i, c := range conversions {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(c.ID + ":\n")
+		for _, n := range c.Notes {
+			b.WriteString("\t! " + n + "\n")
+		}
+		if c.Description != "" {
+			for l := range strings.SplitSeq(c.Description, "\n") {
+				b.WriteString(strings.TrimRight("\t// "+l, " ") + "\n")
+			}
+		}
+		if !c.Plural {
+			call := "r.Text(" + strconv.Quote(c.Forms.Other) + ")"
+			if len(c.Args) == 0 {
+				b.WriteString("\t" + call + "\n")
+				continue
+			}
+			args := make([]string, len(c.Args))
+			for j, a := range c.Args {
+				args[j] = identifier(a)
+			}
+			b.WriteString("\tfmt.Sprintf(" + call + ", " +
+				strings.Join(args, ", ") + ")\n")
+			continue
+		}
+		quantity := "count"
+		if c.Quantity != "" {
+			quantity = identifier(c.Quantity)
+		}
+		b.WriteString("\t" + msgimport.PluralCall(c.Forms, quantity) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// identifier returns the Go identifier of the argument
+// passed as template variable name.
+func identifier(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	id := string(unicode.ToLower(r)) + name[size:]
+	if token.IsKeyword(id) {
+		id += "Arg"
+	}
+	return id
+}
@@ -0,0 +1,212 @@
+package goi18n_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/goi18n"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestLocaleOfFile(t *testing.T) {
+	f := func(t *testing.T, path string, expect language.Tag) {
+		t.Helper()
+		l, err := goi18n.LocaleOfFile(path)
+		require.NoError(t, err)
+		require.Equal(t, expect, l)
+	}
+	f(t, "active.de.toml", language.German)
+	f(t, "locales/translate.pt-BR.json", language.BrazilianPortuguese)
+	f(t, "en.json", language.English)
+
+	_, err := goi18n.LocaleOfFile("messages.json")
+	require.ErrorIs(t, err, goi18n.ErrNoLocale)
+}
+
+func TestParseFileTOML(t *testing.T) {
+	messages, err := goi18n.ParseFile("active.en.toml", []byte(`
+# Simple messages are strings.
+Greeting = "Hello {{.Name}}!"
+"Quoted.Key" = 'C:\path'
+
+[PersonCats]
+description = "Number of cats of a person."
+one = "{{.Name}} has {{.Count}} cat."
+other = """
+{{.Name}} has \
+  {{.Count}} cats."""
+
+[Nested.Deep]
+other = '''
+Line 1
+Line 2'''
+`))
+	require.NoError(t, err)
+	require.Equal(t, []goi18n.Message{
+		{ID: "Greeting", Other: "Hello {{.Name}}!"},
+		{ID: "Nested.Deep", Other: "Line 1\nLine 2"},
+		{
+			ID:          "PersonCats",
+			Description: "Number of cats of a person.",
+			One:         "{{.Name}} has {{.Count}} cat.",
+			Other:       "{{.Name}} has {{.Count}} cats.",
+		},
+		{ID: "Quoted.Key", Other: `C:\path`},
+	}, messages)
+}
+
+func TestParseFileTOMLErr(t *testing.T) {
+	f := func(t *testing.T, input string) {
+		t.Helper()
+		_, err := goi18n.ParseFile("active.en.toml", []byte(input))
+		require.ErrorIs(t, err, goi18n.ErrTOMLSyntax)
+	}
+	f(t, `Key = 42`)
+	f(t, `Key = "unterminated`)
+	f(t, `Key "value"`)
+	f(t, "Key = \"a\"\nKey = \"b\"")
+	f(t, `[[Array]]`)
+	f(t, `Key = "\x"`)
+}
+
+func TestParseFileJSON(t *testing.T) {
+	messages, err := goi18n.ParseFile("active.en.json", []byte(`{
+		"Greeting": "Hello",
+		"Emails": {
+			"Unread": {"one": "{{.Count}} unread email", "other": "{{.Count}} unread emails"}
+		}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, []goi18n.Message{
+		{
+			ID:    "Emails.Unread",
+			One:   "{{.Count}} unread email",
+			Other: "{{.Count}} unread emails",
+		},
+		{ID: "Greeting", Other: "Hello"},
+	}, messages)
+
+	_, err = goi18n.ParseFile("active.en.yaml", nil)
+	require.ErrorIs(t, err, goi18n.ErrUnsupportedFormat)
+	_, err = goi18n.ParseFile("active.en.json", []byte(`{"Count": 5}`))
+	require.ErrorIs(t, err, goi18n.ErrInvalidMessage)
+}
+
+func TestConvert(t *testing.T) {
+	c := goi18n.Convert([]goi18n.Message{
+		{ID: "Static", Other: "100% done"},
+		{ID: "Args", Other: "{{.From}} sent {{ .Amount }} to {{.From}}'s friend, 5% fee"},
+		{ID: "Cats", Description: "Cats.", One: "{{.PluralCount}} cat", Other: "{{.PluralCount}} cats"},
+		{ID: "Named", One: "{{.Name}} has {{.Count}} cat", Other: "{{.Name}} has {{.Count}} cats"},
+		{ID: "Cond", Other: "{{if .Admin}}Admin{{end}}"},
+	})
+	require.Len(t, c, 5)
+
+	require.False(t, c[0].Plural)
+	require.Equal(t, goi18n.Forms{Other: "100% done"}, c[0].Forms)
+	require.Empty(t, c[0].Notes)
+	require.Equal(t, codeparser.MessageHash("100% done", ""), c[0].Hash)
+
+	require.Equal(t, []string{"From", "Amount"}, c[1].Args)
+	require.Equal(t, goi18n.Forms{
+		Other: "%[1]s sent %[2]s to %[1]s's friend, 5%% fee",
+	}, c[1].Forms)
+
+	require.True(t, c[2].Plural)
+	require.Equal(t, "PluralCount", c[2].Quantity)
+	require.Equal(t, goi18n.Forms{One: "%d cat", Other: "%d cats"}, c[2].Forms)
+	require.Empty(t, c[2].Notes)
+	require.Equal(t, codeparser.MessageHash("%d cats", "Cats."), c[2].Hash)
+
+	require.Nil(t, c[3].Args)
+	require.Equal(t, goi18n.Forms{
+		One: "{{.Name}} has %d cat", Other: "{{.Name}} has %d cats",
+	}, c[3].Forms)
+	require.Equal(t, []string{
+		"template variables Name aren't supported in plural messages",
+	}, c[3].Notes)
+
+	require.Equal(t, []string{
+		"template actions other than variables aren't supported",
+	}, c[4].Notes)
+}
+
+func TestCatalog(t *testing.T) {
+	c := goi18n.Convert([]goi18n.Message{
+		{ID: "Greeting", Description: "Home page.", Other: "Hello {{.Name}}!"},
+		{ID: "Order", Other: "{{.First}} before {{.Second}}"},
+		{ID: "Cats", One: "{{.Count}} cat", Other: "{{.Count}} cats"},
+		{ID: "Untranslated", Other: "Bye"},
+	})
+	po, notes, err := goi18n.Catalog(language.German, c, []goi18n.Message{
+		{ID: "Greeting", Other: "Hallo {{.Name}}!"},
+		{ID: "Order", Other: "{{.Second}} nach {{.First}} und {{.Third}}"},
+		{ID: "Cats", One: "{{.Count}} Katze", Other: "{{.Count}} Katzen"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"Order: template variable Third isn't used by the source text",
+	}, notes)
+
+	var buf bytes.Buffer
+	require.NoError(t, gettext.Encoder{}.EncodePO(po, &buf))
+	require.Equal(t, `msgid ""
+msgstr ""
+"Language: de\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+#. Home page.
+msgctxt "361dd2e0faf33142"
+msgid "Hello %s!"
+msgstr "Hallo %s!"
+
+msgctxt "41b3d28b8deb6584"
+msgid "%d cat"
+msgid_plural "%d cats"
+msgstr[0] "%d Katze"
+msgstr[1] "%d Katzen"
+
+msgctxt "58b2205c5e96dc0b"
+msgid "%s before %s"
+msgstr "%[2]s nach %[1]s und {{.Third}}"
+
+msgctxt "f07932a934038771"
+msgid "Bye"
+msgstr ""
+`, buf.String())
+}
+
+func TestWriteRewrites(t *testing.T) {
+	c := goi18n.Convert([]goi18n.Message{
+		{ID: "Greeting", Description: "Home page.\nShown once.", Other: "Hello!"},
+		{ID: "Transfer", Other: "{{.Type}} to {{.Name}}"},
+		{
+			ID: "Cats", Description: "Cats.",
+			One: "{{.Name}} has {{.Count}} cat", Other: "{{.Name}} has {{.Count}} cats",
+		},
+	})
+	var buf bytes.Buffer
+	require.NoError(t, goi18n.WriteRewrites(&buf, c))
+	require.Equal(t, `Greeting:
+	// Home page.
+	// Shown once.
+	r.Text("Hello!")
+
+Transfer:
+	fmt.Sprintf(r.Text("%s to %s"), typeArg, name)
+
+Cats:
+	! template variables Name aren't supported in plural messages
+	// Cats.
+	r.Plural(localize.Forms{
+		One:   "{{.Name}} has %d cat",
+		Other: "{{.Name}} has %d cats",
+	}, count)
+`, buf.String())
+}
@@ -0,0 +1,349 @@
+package goi18n
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrTOMLSyntax = errors.New("TOML syntax error")
+
+// decodeTOML decodes the subset of TOML used by go-i18n message files,
+// which are tables and key/value pairs with string values.
+// Keys may be bare, quoted or dotted. Values of other types are rejected.
+func decodeTOML(data string) (map[string]any, error) {
+	d := &tomlDecoder{s: data, line: 1}
+	root := map[string]any{}
+	table := root
+	for {
+		d.skipSpaceAndComments()
+		if d.eof() {
+			return root, nil
+		}
+		if d.peek() == '[' {
+			d.pos++
+			if strings.HasPrefix(d.s[d.pos:], "[") {
+				return nil, d.errorf("arrays of tables aren't supported")
+			}
+			path, err := d.key()
+			if err != nil {
+				return nil, err
+			}
+			d.skipSpace()
+			if d.eof() || d.peek() != ']' {
+				return nil, d.errorf("expected ]")
+			}
+			d.pos++
+			if table, err = d.table(root, path); err != nil {
+				return nil, err
+			}
+		} else {
+			path, err := d.key()
+			if err != nil {
+				return nil, err
+			}
+			d.skipSpace()
+			if d.eof() || d.peek() != '=' {
+				return nil, d.errorf("expected =")
+			}
+			d.pos++
+			d.skipSpace()
+			v, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			t, err := d.table(table, path[:len(path)-1])
+			if err != nil {
+				return nil, err
+			}
+			k := path[len(path)-1]
+			if _, ok := t[k]; ok {
+				return nil, d.errorf("duplicate key %q", k)
+			}
+			t[k] = v
+		}
+		d.skipSpace()
+		if d.eof() {
+			return root, nil
+		}
+		if c := d.peek(); c == '#' {
+			d.skipComment()
+		} else if c != '\n' && c != '\r' {
+			return nil, d.errorf("expected end of line")
+		}
+	}
+}
+
+type tomlDecoder struct {
+	s    string
+	pos  int
+	line int
+}
+
+func (d *tomlDecoder) errorf(format string, a ...any) error {
+	return fmt.Errorf("%w: line %d: %s", ErrTOMLSyntax, d.line, fmt.Sprintf(format, a...))
+}
+
+func (d *tomlDecoder) eof() bool  { return d.pos >= len(d.s) }
+func (d *tomlDecoder) peek() byte { return d.s[d.pos] }
+
+func (d *tomlDecoder) skipSpace() {
+	for !d.eof() && (d.peek() == ' ' || d.peek() == '\t') {
+		d.pos++
+	}
+}
+
+func (d *tomlDecoder) skipComment() {
+	for !d.eof() && d.peek() != '\n' {
+		d.pos++
+	}
+}
+
+func (d *tomlDecoder) skipSpaceAndComments() {
+	for !d.eof() {
+		switch d.peek() {
+		case ' ', '\t', '\r':
+			d.pos++
+		case '\n':
+			d.pos++
+			d.line++
+		case '#':
+			d.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// table returns the table at path in root creating missing tables.
+func (d *tomlDecoder) table(root map[string]any, path []string) (map[string]any, error) {
+	t := root
+	for _, k := range path {
+		v, ok := t[k]
+		if !ok {
+			n := map[string]any{}
+			t[k] = n
+			t = n
+			continue
+		}
+		if t, ok = v.(map[string]any); !ok {
+			return nil, d.errorf("key %q is not a table", k)
+		}
+	}
+	return t, nil
+}
+
+// key parses a bare, quoted or dotted key.
+func (d *tomlDecoder) key() (path []string, err error) {
+	for {
+		d.skipSpace()
+		if d.eof() {
+			return nil, d.errorf("expected key")
+		}
+		var k string
+		switch d.peek() {
+		case '"':
+			if k, err = d.basicString(); err != nil {
+				return nil, err
+			}
+		case '\'':
+			if k, err = d.literalString(); err != nil {
+				return nil, err
+			}
+		default:
+			start := d.pos
+			for !d.eof() && isBareKeyChar(d.peek()) {
+				d.pos++
+			}
+			if start == d.pos {
+				return nil, d.errorf("expected key")
+			}
+			k = d.s[start:d.pos]
+		}
+		path = append(path, k)
+		d.skipSpace()
+		if d.eof() || d.peek() != '.' {
+			return path, nil
+		}
+		d.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+		c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// value parses a string value.
+func (d *tomlDecoder) value() (string, error) {
+	switch {
+	case strings.HasPrefix(d.s[d.pos:], `"""`):
+		return d.multilineBasicString()
+	case strings.HasPrefix(d.s[d.pos:], "'''"):
+		return d.multilineLiteralString()
+	case !d.eof() && d.peek() == '"':
+		return d.basicString()
+	case !d.eof() && d.peek() == '\'':
+		return d.literalString()
+	}
+	return "", d.errorf("only string values are supported")
+}
+
+func (d *tomlDecoder) basicString() (string, error) {
+	d.pos++ // Opening quote.
+	var b strings.Builder
+	for {
+		if d.eof() || d.peek() == '\n' {
+			return "", d.errorf("unterminated string")
+		}
+		switch c := d.peek(); c {
+		case '"':
+			d.pos++
+			return b.String(), nil
+		case '\\':
+			if err := d.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			d.pos++
+		}
+	}
+}
+
+func (d *tomlDecoder) multilineBasicString() (string, error) {
+	d.pos += 3
+	d.trimLeadingNewline()
+	var b strings.Builder
+	for {
+		if d.eof() {
+			return "", d.errorf("unterminated string")
+		}
+		if strings.HasPrefix(d.s[d.pos:], `"""`) {
+			// Up to two quotes may directly precede the closing delimiter.
+			for strings.HasPrefix(d.s[d.pos+1:], `"""`) {
+				b.WriteByte('"')
+				d.pos++
+			}
+			d.pos += 3
+			return b.String(), nil
+		}
+		switch c := d.peek(); c {
+		case '\\':
+			// A backslash at the end of a line trims all following whitespace.
+			rest := strings.TrimLeft(d.s[d.pos+1:], " \t\r")
+			if strings.HasPrefix(rest, "\n") {
+				d.pos = len(d.s) - len(rest)
+				for !d.eof() && strings.ContainsRune(" \t\r\n", rune(d.peek())) {
+					if d.peek() == '\n' {
+						d.line++
+					}
+					d.pos++
+				}
+				continue
+			}
+			if err := d.escape(&b); err != nil {
+				return "", err
+			}
+		case '\r':
+			d.pos++ // Normalize CRLF line endings.
+		default:
+			if c == '\n' {
+				d.line++
+			}
+			b.WriteByte(c)
+			d.pos++
+		}
+	}
+}
+
+func (d *tomlDecoder) literalString() (string, error) {
+	d.pos++ // Opening quote.
+	start := d.pos
+	for !d.eof() && d.peek() != '\'' {
+		if d.peek() == '\n' {
+			return "", d.errorf("unterminated string")
+		}
+		d.pos++
+	}
+	if d.eof() {
+		return "", d.errorf("unterminated string")
+	}
+	s := d.s[start:d.pos]
+	d.pos++
+	return s, nil
+}
+
+func (d *tomlDecoder) multilineLiteralString() (string, error) {
+	d.pos += 3
+	d.trimLeadingNewline()
+	end := strings.Index(d.s[d.pos:], "'''")
+	if end < 0 {
+		return "", d.errorf("unterminated string")
+	}
+	// Up to two quotes may directly precede the closing delimiter.
+	for strings.HasPrefix(d.s[d.pos+end+1:], "'''") {
+		end++
+	}
+	s := d.s[d.pos : d.pos+end]
+	d.line += strings.Count(s, "\n")
+	d.pos += end + 3
+	return strings.ReplaceAll(s, "\r\n", "\n"), nil
+}
+
+// trimLeadingNewline skips a newline directly following
+// the opening delimiter of a multi-line string.
+func (d *tomlDecoder) trimLeadingNewline() {
+	if strings.HasPrefix(d.s[d.pos:], "\r\n") {
+		d.pos += 2
+		d.line++
+	} else if strings.HasPrefix(d.s[d.pos:], "\n") {
+		d.pos++
+		d.line++
+	}
+}
+
+// escape decodes the escape sequence at the current position into b.
+func (d *tomlDecoder) escape(b *strings.Builder) error {
+	d.pos++ // Backslash.
+	if d.eof() {
+		return d.errorf("unterminated escape sequence")
+	}
+	c := d.peek()
+	d.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"':
+		b.WriteByte('"')
+	case '\\':
+		b.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if d.pos+n > len(d.s) {
+			return d.errorf("invalid unicode escape sequence")
+		}
+		r, err := strconv.ParseUint(d.s[d.pos:d.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(r)) {
+			return d.errorf("invalid unicode escape sequence")
+		}
+		b.WriteRune(rune(r))
+		d.pos += n
+	default:
+		return d.errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Package grapheme segments texts into extended grapheme clusters, which
+// are the user-perceived characters of Unicode Standard Annex #29, such
+// that combining marks, emoji ZWJ sequences, emoji modifiers, flags and
+// Hangul syllable sequences aren't split. Prepend characters and Indic
+// conjuncts are segmented like other characters.
+package grapheme
+
+import (
+	"iter"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// property is the grapheme cluster break property of a rune
+// relevant to segmentation.
+type property uint8
+
+const (
+	propOther property = iota
+	propCR
+	propLF
+	propControl
+	propExtend
+	propZWJ
+	propSpacingMark
+	propRegionalIndicator
+	propL
+	propV
+	propT
+	propLV
+	propLVT
+)
+
+const (
+	zwnj = '\u200c'
+	zwj  = '\u200d'
+
+	// emojiPresentation is variation selector 16 requesting
+	// the emoji presentation of the preceding character.
+	emojiPresentation = '\ufe0f'
+)
+
+func propertyOf(r rune) property {
+	switch {
+	case r == '\r':
+		return propCR
+	case r == '\n':
+		return propLF
+	case r == zwj:
+		return propZWJ
+	case r == zwnj,
+		r >= 0x1f3fb && r <= 0x1f3ff, // Emoji modifiers.
+		r >= 0xe0020 && r <= 0xe007f, // Tags.
+		unicode.In(r, unicode.Mn, unicode.Me):
+		return propExtend
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return propControl
+	case unicode.Is(unicode.Mc, r):
+		return propSpacingMark
+	case r >= 0x1f1e6 && r <= 0x1f1ff:
+		return propRegionalIndicator
+	case r >= 0x1100 && r <= 0x115f, r >= 0xa960 && r <= 0xa97c:
+		return propL
+	case r >= 0x1160 && r <= 0x11a7, r >= 0xd7b0 && r <= 0xd7c6:
+		return propV
+	case r >= 0x11a8 && r <= 0x11ff, r >= 0xd7cb && r <= 0xd7fb:
+		return propT
+	case r >= 0xac00 && r <= 0xd7a3:
+		if (r-0xac00)%28 == 0 {
+			return propLV
+		}
+		return propLVT
+	}
+	return propOther
+}
+
+// isPictographic reports whether r is an extended pictographic character
+// like an emoji, approximated by the blocks containing them.
+func isPictographic(r rune) bool {
+	switch {
+	case r >= 0x1f000 && r <= 0x1faff,
+		r >= 0x2600 && r <= 0x27bf,
+		r >= 0x2300 && r <= 0x23ff,
+		r >= 0x2b00 && r <= 0x2bff,
+		r >= 0x2190 && r <= 0x21ff:
+		return true
+	}
+	switch r {
+	case 0xa9, 0xae, 0x203c, 0x2049, 0x2122, 0x2139, 0x3030, 0x303d, 0x3297, 0x3299:
+		return true
+	}
+	return false
+}
+
+// Next returns the first grapheme cluster of s and the rest of s.
+func Next(s string) (cluster, rest string) {
+	if s == "" {
+		return "", ""
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	prev := propertyOf(r)
+	// emoji is true while the cluster is an extended pictographic
+	// character followed by Extend characters (GB11).
+	emoji := isPictographic(r)
+	regional := 0
+	if prev == propRegionalIndicator {
+		regional = 1
+	}
+	i := size
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		p := propertyOf(r)
+		if !joins(prev, p, emoji, regional, r) {
+			break
+		}
+		switch {
+		case p == propRegionalIndicator:
+			regional++
+		case p == propExtend, p == propZWJ:
+		default:
+			emoji = isPictographic(r)
+		}
+		prev = p
+		i += size
+	}
+	return s[:i], s[i:]
+}
+
+// joins reports whether there's no cluster boundary between a rune
+// of property prev and rune r of property p.
+func joins(prev, p property, emoji bool, regional int, r rune) bool {
+	switch {
+	case prev == propCR && p == propLF: // GB3
+		return true
+	case prev == propCR, prev == propLF, prev == propControl: // GB4
+		return false
+	case p == propCR, p == propLF, p == propControl: // GB5
+		return false
+	case prev == propL && (p == propL || p == propV || p == propLV || p == propLVT): // GB6
+		return true
+	case (prev == propLV || prev == propV) && (p == propV || p == propT): // GB7
+		return true
+	case (prev == propLVT || prev == propT) && p == propT: // GB8
+		return true
+	case p == propExtend, p == propZWJ, p == propSpacingMark: // GB9, GB9a
+		return true
+	case prev == propZWJ && emoji && isPictographic(r): // GB11
+		return true
+	case prev == propRegionalIndicator && p == propRegionalIndicator: // GB12, GB13
+		return regional%2 == 1
+	}
+	return false
+}
+
+// Clusters returns an iterator over the grapheme clusters of s.
+func Clusters(s string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for s != "" {
+			var c string
+			c, s = Next(s)
+			if !yield(c) {
+				return
+			}
+		}
+	}
+}
+
+// Count returns the number of grapheme clusters of s.
+func Count(s string) (n int) {
+	for s != "" {
+		_, s = Next(s)
+		n++
+	}
+	return n
+}
+
+// Width returns the display width of s in columns of a monospace font,
+// which counts East Asian wide and fullwidth characters, emoji and flags
+// as two columns, control characters like line breaks as zero columns and all
+// other grapheme clusters as one column.
+func Width(s string) (n int) {
+	for c := range Clusters(s) {
+		n += clusterWidth(c)
+	}
+	return n
+}
+
+func clusterWidth(c string) int {
+	r, _ := utf8.DecodeRuneInString(c)
+	switch p := propertyOf(r); {
+	case p == propCR, p == propLF, p == propControl:
+		return 0
+	case p == propRegionalIndicator, strings.ContainsRune(c, emojiPresentation):
+		// Flags and emoji presentation.
+		return 2
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
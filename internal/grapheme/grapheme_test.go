@@ -0,0 +1,75 @@
+package grapheme_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/romshark/localize/internal/grapheme"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusters(t *testing.T) {
+	t.Parallel()
+	f := func(t *testing.T, expect []string, input string) {
+		t.Helper()
+		require.Equal(t, expect, slices.Collect(grapheme.Clusters(input)))
+		require.Equal(t, len(expect), grapheme.Count(input))
+	}
+
+	f(t, nil, "")
+	f(t, []string{"a", "b", "c"}, "abc")
+	f(t, []string{"\r\n", "a", "\n", "\n"}, "\r\na\n\n")
+	// Combining marks.
+	f(t, []string{"C", "a", "f", "é", "!"}, "Café!")
+	// Devanagari virama and spacing vowel signs.
+	f(t, []string{"न", "म", "स्", "ते"}, "नमस्ते")
+	// CJK.
+	f(t, []string{"日", "本", "語"}, "日本語")
+	// Hangul syllables and conjoining jamo.
+	f(t, []string{"한", "국"}, "한국")
+	f(t, []string{"한", "국"},
+		"한국")
+	// Emoji modifiers, ZWJ sequences, variation selectors and flags.
+	f(t, []string{"\U0001f44d\U0001f3fd", "!"}, "\U0001f44d\U0001f3fd!")
+	family := "\U0001f469‍\U0001f469‍\U0001f467‍\U0001f466"
+	f(t, []string{family, "a"}, family+"a")
+	f(t, []string{"❤️", "x"}, "❤️x")
+	f(t, []string{"\U0001f1e9\U0001f1ea", "\U0001f1eb\U0001f1f7", "\U0001f1fa"},
+		"\U0001f1e9\U0001f1ea\U0001f1eb\U0001f1f7\U0001f1fa")
+	// ZWJ between non-pictographic characters doesn't join.
+	f(t, []string{"a‍", "b"}, "a‍b")
+	// Controls aren't extended.
+	f(t, []string{"\t", "́"}, "\t́")
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+	c, rest := grapheme.Next("étude")
+	require.Equal(t, "é", c)
+	require.Equal(t, "tude", rest)
+
+	c, rest = grapheme.Next("")
+	require.Equal(t, "", c)
+	require.Equal(t, "", rest)
+}
+
+func TestWidth(t *testing.T) {
+	t.Parallel()
+	f := func(t *testing.T, expect int, input string) {
+		t.Helper()
+		require.Equal(t, expect, grapheme.Width(input))
+	}
+
+	f(t, 0, "")
+	f(t, 5, "Hello")
+	f(t, 4, "Café")
+	f(t, 6, "日本語")
+	f(t, 4, "한국")
+	f(t, 6, "ＡＢＣ") // Fullwidth.
+	f(t, 3, "ｱｲｳ") // Halfwidth.
+	f(t, 2, "\U0001f469‍\U0001f469‍\U0001f467")
+	f(t, 2, "❤️")
+	f(t, 1, "❤")
+	f(t, 2, "\U0001f1e9\U0001f1ea")
+	f(t, 3, "a\nb\tc")
+}
@@ -0,0 +1,65 @@
+// Package heading marks messages as headings using the `// heading`
+// source code directive. Headings are stored as `#, heading` flags in
+// catalogs and have the heading casing of their locale applied
+// in the generated Go bundle (see typography.Casing).
+package heading
+
+import (
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+)
+
+const (
+	// Directive is the source code comment line marking a message as heading.
+	Directive = "heading"
+
+	// Flag is the catalog flag of headings.
+	Flag = "heading"
+)
+
+// IsDirective returns true if line is the heading directive.
+func IsDirective(line string) bool { return line == Directive }
+
+// Is returns true if m is flagged as heading.
+func Is(m *gettext.Message) bool {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeFlag {
+			continue
+		}
+		for f := range strings.SplitSeq(c.Value, ",") {
+			if strings.TrimSpace(f) == Flag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Set adds or removes the heading flag of m preserving all other flags.
+func Set(m *gettext.Message, heading bool) {
+	l := m.Msgctxt.Comments.Text[:0]
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeFlag {
+			var flags []string
+			for f := range strings.SplitSeq(c.Value, ",") {
+				if f = strings.TrimSpace(f); f != Flag {
+					flags = append(flags, f)
+				}
+			}
+			if len(flags) == 0 {
+				continue // Remove comments containing only the heading flag.
+			}
+			if len(flags) <= strings.Count(c.Value, ",") {
+				// Rewrite only comments containing the heading flag,
+				// keeping other flags verbatim.
+				c.Value = strings.Join(flags, ", ")
+			}
+		}
+		l = append(l, c)
+	}
+	if heading {
+		l = append(l, gettext.Comment{Type: gettext.CommentTypeFlag, Value: Flag})
+	}
+	m.Msgctxt.Comments.Text = l
+}
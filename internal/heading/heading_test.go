@@ -0,0 +1,35 @@
+package heading_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/heading"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+	}
+	require.False(t, heading.Is(m))
+
+	heading.Set(m, true)
+	heading.Set(m, true)
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+		{Type: gettext.CommentTypeFlag, Value: "heading"},
+	}, m.Msgctxt.Comments.Text)
+	require.True(t, heading.Is(m))
+
+	m.Msgctxt.Comments.Text[1].Value = "fuzzy, heading"
+	heading.Set(m, false)
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+	}, m.Msgctxt.Comments.Text)
+	require.False(t, heading.Is(m))
+}
@@ -0,0 +1,204 @@
+// Package limits provides size limits for messages and catalogs
+// protecting against runaway catalogs, such as catalogs bloated by
+// embedded blobs that were accidentally extracted as texts.
+package limits
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/token"
+	"slices"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/grapheme"
+)
+
+var (
+	ErrMessageTooLong  = errors.New("message too long")
+	ErrTooManyMessages = errors.New("too many messages")
+	ErrCatalogTooLarge = errors.New("catalog file too large")
+)
+
+// Unit is the unit the lengths of texts are measured in.
+type Unit string
+
+const (
+	// UnitBytes measures texts in bytes of UTF-8.
+	UnitBytes Unit = "bytes"
+
+	// UnitGraphemes measures texts in grapheme clusters, which are
+	// user-perceived characters like "é" or an emoji ZWJ sequence.
+	UnitGraphemes Unit = "graphemes"
+
+	// UnitWidth measures texts in columns of a monospace font, which
+	// counts East Asian wide characters and emoji as two columns.
+	UnitWidth Unit = "width"
+)
+
+// Units are the names of all units.
+var Units = []string{string(UnitBytes), string(UnitGraphemes), string(UnitWidth)}
+
+// ParseUnit parses the name of a unit.
+func ParseUnit(s string) (Unit, error) {
+	if !slices.Contains(Units, s) {
+		return "", fmt.Errorf("unknown unit %q, use bytes, graphemes or width", s)
+	}
+	return Unit(s), nil
+}
+
+// Len returns the length of s in u.
+func (u Unit) Len(s string) int {
+	switch u {
+	case UnitGraphemes:
+		return grapheme.Count(s)
+	case UnitWidth:
+		return grapheme.Width(s)
+	}
+	return len(s)
+}
+
+// noun returns the plural noun of u used in violations.
+func (u Unit) noun() string {
+	switch u {
+	case UnitGraphemes:
+		return "characters"
+	case UnitWidth:
+		return "columns"
+	}
+	return "bytes"
+}
+
+// Limits are size limits. Zero limits are disabled.
+type Limits struct {
+	// MaxMessageLen is the maximum length in Unit of any text
+	// of a message and of any of its translations.
+	MaxMessageLen int
+
+	// Unit is the unit of MaxMessageLen, UnitBytes if empty.
+	Unit Unit
+
+	// MaxMessages is the maximum number of messages per catalog file.
+	MaxMessages int
+
+	// MaxCatalogSize is the maximum size in bytes of a catalog file.
+	MaxCatalogSize int64
+}
+
+// Violation is an exceeded limit.
+// Pos is the position of the first reference of a message in the source code
+// or only has Filename set to the catalog file path.
+type Violation struct {
+	Pos token.Position
+	Err error
+}
+
+func (v Violation) Error() string {
+	if v.Pos.Line == 0 {
+		return v.Pos.Filename + ": " + v.Err.Error()
+	}
+	return fmt.Sprintf("%s:%d:%d: %v",
+		v.Pos.Filename, v.Pos.Line, v.Pos.Column, v.Err)
+}
+
+func (v Violation) Unwrap() error { return v.Err }
+
+// Messages returns violations of MaxMessageLen in c
+// ordered by their position.
+func (l Limits) Messages(c *codeparser.Collection) (violations []Violation) {
+	if l.MaxMessageLen < 1 {
+		return nil
+	}
+	u := cmp.Or(l.Unit, UnitBytes)
+	for msg, meta := range c.Messages {
+		n := max(u.Len(msg.Zero), u.Len(msg.One), u.Len(msg.Two),
+			u.Len(msg.Few), u.Len(msg.Many), u.Len(msg.Other))
+		if n <= l.MaxMessageLen {
+			continue
+		}
+		var pos token.Position
+		if len(meta.Pos) > 0 {
+			pos = meta.Pos[0]
+		}
+		violations = append(violations, Violation{Pos: pos, Err: fmt.Errorf(
+			"%w: %d %s exceed the limit of %d %[3]s, make sure no data "+
+				"is passed as text or load long texts from embedded files "+
+				"and raise the limit (-max-message-len) if intended",
+			ErrMessageTooLong, n, u.noun(), l.MaxMessageLen,
+		)})
+	}
+	slices.SortFunc(violations, func(a, b Violation) int {
+		return cmp.Or(
+			cmp.Compare(a.Pos.Filename, b.Pos.Filename),
+			cmp.Compare(a.Pos.Offset, b.Pos.Offset),
+		)
+	})
+	return violations
+}
+
+// Translations returns violations of MaxMessageLen by the translations
+// of catalog, which is the catalog file at path, ordered by their position.
+// Obsolete messages are ignored.
+func (l Limits) Translations(path string, catalog gettext.FilePO) (violations []Violation) {
+	if l.MaxMessageLen < 1 {
+		return nil
+	}
+	u := cmp.Or(l.Unit, UnitBytes)
+	for i := range catalog.Messages.List {
+		m := &catalog.Messages.List[i]
+		if m.Obsolete {
+			continue
+		}
+		n := 0
+		for _, s := range []*gettext.Msgstr{
+			&m.Msgstr, &m.Msgstr0, &m.Msgstr1, &m.Msgstr2,
+			&m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+		} {
+			n = max(n, u.Len(s.Text.String()))
+		}
+		if n <= l.MaxMessageLen {
+			continue
+		}
+		pos := m.Msgid.Position
+		if !m.Msgctxt.IsZero() {
+			pos = m.Msgctxt.Position
+		}
+		violations = append(violations, Violation{
+			Pos: token.Position{
+				Filename: path, Line: int(pos.Line), Column: int(pos.Column),
+			},
+			Err: fmt.Errorf(
+				"%w: translation of %s is %d %s long, exceeding the limit "+
+					"of %d %[4]s, shorten the translation or raise the limit "+
+					"(-max-message-len) if intended",
+				ErrMessageTooLong, m.Msgctxt.Text.String(), n, u.noun(),
+				l.MaxMessageLen,
+			),
+		})
+	}
+	return violations
+}
+
+// Catalog returns violations of MaxMessages and MaxCatalogSize by the catalog
+// file at path with the given number of messages and size in bytes.
+// size is ignored if it's negative.
+func (l Limits) Catalog(path string, messages int, size int64) (violations []Violation) {
+	pos := token.Position{Filename: path}
+	if l.MaxMessages > 0 && messages > l.MaxMessages {
+		violations = append(violations, Violation{Pos: pos, Err: fmt.Errorf(
+			"%w: %d messages exceed the limit of %d, split the catalog into "+
+				"domains (-split-pot, -domain) or raise the limit (-max-messages)",
+			ErrTooManyMessages, messages, l.MaxMessages,
+		)})
+	}
+	if l.MaxCatalogSize > 0 && size > l.MaxCatalogSize {
+		violations = append(violations, Violation{Pos: pos, Err: fmt.Errorf(
+			"%w: %d bytes exceed the limit of %d bytes, look for unexpectedly "+
+				"long messages (-max-message-len) or raise the limit "+
+				"(-max-catalog-size)",
+			ErrCatalogTooLarge, size, l.MaxCatalogSize,
+		)})
+	}
+	return violations
+}
@@ -0,0 +1,120 @@
+package limits_test
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/limits"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessages(t *testing.T) {
+	pos := func(line int) []token.Position {
+		return []token.Position{{Filename: "a.go", Offset: line * 10, Line: line, Column: 2}}
+	}
+	c := &codeparser.Collection{Messages: map[codeparser.Msg]codeparser.MsgMeta{
+		{Hash: "1", Other: "short"}:                                   {Pos: pos(1)},
+		{Hash: "2", Other: strings.Repeat("x", 11)}:                   {Pos: pos(3)},
+		{Hash: "3", One: strings.Repeat("x", 12), Other: "x"}:         {Pos: pos(2)},
+		{Hash: "4", Other: strings.Repeat("x", 10), Description: "d"}: {Pos: pos(4)},
+	}}
+
+	require.Empty(t, limits.Limits{}.Messages(c))
+
+	v := limits.Limits{MaxMessageLen: 10}.Messages(c)
+	require.Len(t, v, 2)
+	require.ErrorIs(t, v[0], limits.ErrMessageTooLong)
+	require.Equal(t, 2, v[0].Pos.Line)
+	require.ErrorContains(t, v[0], "12 bytes exceed the limit of 10 bytes")
+	require.Equal(t, 3, v[1].Pos.Line)
+	require.True(t, strings.HasPrefix(v[1].Error(), "a.go:3:2: "))
+}
+
+func TestMessagesUnit(t *testing.T) {
+	c := &codeparser.Collection{Messages: map[codeparser.Msg]codeparser.MsgMeta{
+		// 10 bytes, 4 characters, 7 columns.
+		{Hash: "1", Other: "日本語!"}: {},
+		// 13 bytes, 3 characters, 4 columns.
+		{Hash: "2", Other: "a\U0001f469\u200d\U0001f469b"}: {},
+	}}
+
+	require.Len(t, limits.Limits{MaxMessageLen: 9}.Messages(c), 2)
+	require.Empty(t, limits.Limits{
+		MaxMessageLen: 4, Unit: limits.UnitGraphemes,
+	}.Messages(c))
+
+	v := limits.Limits{MaxMessageLen: 6, Unit: limits.UnitWidth}.Messages(c)
+	require.Len(t, v, 1)
+	require.ErrorContains(t, v[0], "7 columns exceed the limit of 6 columns")
+}
+
+func TestParseUnit(t *testing.T) {
+	for _, name := range limits.Units {
+		u, err := limits.ParseUnit(name)
+		require.NoError(t, err)
+		require.Equal(t, name, string(u))
+	}
+	_, err := limits.ParseUnit("runes")
+	require.Error(t, err)
+}
+
+func TestTranslations(t *testing.T) {
+	po, err := gettext.NewDecoder().DecodePOBytes("catalog.ja.po", []byte(`msgid ""
+msgstr ""
+"Language: ja\n"
+"Plural-Forms: nplurals=1; plural=0;\n"
+
+msgctxt "h1"
+msgid "Save"
+msgstr "保存"
+
+msgctxt "h2"
+msgid "Save changes"
+msgstr "変更を保存する"
+
+msgctxt "h3"
+msgid "%d files"
+msgid_plural "%d files"
+msgstr[0] "%d個のファイル"
+
+#~ msgctxt "h4"
+#~ msgid "Discard all changes"
+#~ msgstr "すべての変更を破棄する"
+`))
+	require.NoError(t, err)
+
+	require.Empty(t, limits.Limits{}.Translations("catalog.ja.po", po))
+	require.Empty(t, limits.Limits{
+		MaxMessageLen: 8, Unit: limits.UnitGraphemes,
+	}.Translations("catalog.ja.po", po))
+
+	v := limits.Limits{
+		MaxMessageLen: 12, Unit: limits.UnitWidth,
+	}.Translations("catalog.ja.po", po)
+	require.Len(t, v, 2)
+	require.ErrorIs(t, v[0], limits.ErrMessageTooLong)
+	require.ErrorContains(t, v[0], "translation of h2 is 14 columns long")
+	require.True(t, strings.HasPrefix(v[0].Error(), "catalog.ja.po:10:"), v[0].Error())
+	require.ErrorContains(t, v[1], "translation of h3 is 14 columns long")
+
+	// Byte lengths of CJK translations are three times their character count.
+	require.Len(t, limits.Limits{MaxMessageLen: 12}.Translations("catalog.ja.po", po), 2)
+}
+
+func TestCatalog(t *testing.T) {
+	l := limits.Limits{MaxMessages: 2, MaxCatalogSize: 100}
+	require.Empty(t, l.Catalog("catalog.de.po", 2, 100))
+	require.Empty(t, l.Catalog("catalog.pot", 2, -1))
+	require.Empty(t, limits.Limits{}.Catalog("catalog.de.po", 1000, 1e9))
+
+	v := l.Catalog("catalog.de.po", 3, 101)
+	require.Len(t, v, 2)
+	require.ErrorIs(t, v[0], limits.ErrTooManyMessages)
+	require.ErrorIs(t, v[1], limits.ErrCatalogTooLarge)
+	require.True(t, strings.HasPrefix(v[0].Error(), "catalog.de.po: "))
+	require.ErrorContains(t, v[0], "-max-messages")
+	require.ErrorContains(t, v[1], "-max-catalog-size")
+}
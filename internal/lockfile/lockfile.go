@@ -0,0 +1,189 @@
+// Package lockfile provides advisory lock files
+// preventing concurrent processes from modifying the same files.
+package lockfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrLocked = errors.New("locked by another process")
+
+// Lock is an acquired lock file.
+type Lock struct {
+	path    string
+	content []byte
+
+	// stop stops refreshing the modification time of the lock file.
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Options are lock acquisition options.
+type Options struct {
+	// Wait is the maximum time to wait for the lock to be released by
+	// another process. Acquire fails immediately if Wait is 0.
+	Wait time.Duration
+
+	// PollInterval is the interval in which the lock file is checked
+	// while waiting. Defaults to 100ms.
+	PollInterval time.Duration
+
+	// StaleAfter is the age after which a lock file is considered stale,
+	// for example because the process holding it crashed. Stale lock files
+	// are removed. Lock files never go stale if StaleAfter is 0.
+	// The holder of the lock refreshes the modification time of the lock
+	// file every StaleAfter/3 such that locks held longer than StaleAfter
+	// by live processes don't go stale.
+	StaleAfter time.Duration
+}
+
+// Acquire creates the lock file at path.
+// Returns an error wrapping ErrLocked if the lock is held by another
+// process for longer than o.Wait.
+func Acquire(path string, o Options) (*Lock, error) {
+	if o.PollInterval == 0 {
+		o.PollInterval = 100 * time.Millisecond
+	}
+	deadline := time.Now().Add(o.Wait)
+	for {
+		content, err := create(path)
+		if err == nil {
+			return newLock(path, content, o.StaleAfter), nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("creating lock file: %w", err)
+		}
+
+		stat, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // Released in the meantime, try again.
+		} else if err != nil {
+			return nil, fmt.Errorf("checking lock file: %w", err)
+		}
+		if o.StaleAfter > 0 && time.Since(stat.ModTime()) > o.StaleAfter {
+			// The process holding the lock most likely crashed.
+			if err := removeStale(path, o.StaleAfter); err != nil {
+				return nil, fmt.Errorf("removing stale lock file: %w", err)
+			}
+			continue
+		}
+
+		if time.Now().Add(o.PollInterval).After(deadline) {
+			return nil, fmt.Errorf("%w (%s, remove %q if it's stale)",
+				ErrLocked, holder(path), path)
+		}
+		time.Sleep(o.PollInterval)
+	}
+}
+
+// Release stops refreshing and removes the lock file.
+// Returns an error if the lock file was replaced by another process.
+func (l *Lock) Release() error {
+	if l.stop != nil {
+		close(l.stop)
+		l.wg.Wait()
+	}
+	content, err := os.ReadFile(l.path)
+	if err != nil {
+		return fmt.Errorf("checking lock file: %w", err)
+	}
+	if !bytes.Equal(content, l.content) {
+		return fmt.Errorf("lock file %q was replaced by another process", l.path)
+	}
+	if err := os.Remove(l.path); err != nil {
+		return fmt.Errorf("removing lock file: %w", err)
+	}
+	return nil
+}
+
+func newLock(path string, content []byte, staleAfter time.Duration) *Lock {
+	l := &Lock{path: path, content: content}
+	if staleAfter > 0 {
+		l.stop = make(chan struct{})
+		l.wg.Add(1)
+		go l.refresh(staleAfter / 3)
+	}
+	return l
+}
+
+// refresh updates the modification time of the lock file every interval
+// until the lock is released, such that it never goes stale while held.
+func (l *Lock) refresh(interval time.Duration) {
+	defer l.wg.Done()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-t.C:
+			now := time.Now()
+			// Errors are reported by Release in case the lock file is gone.
+			_ = os.Chtimes(l.path, now, now)
+		}
+	}
+}
+
+// removeStale removes the stale lock file at path.
+// The lock file is atomically renamed before it's checked again, such that
+// a lock file that was refreshed or replaced by a new lock in the meantime
+// is never removed but restored instead.
+func removeStale(path string, staleAfter time.Duration) error {
+	tmp := path + ".stale." + strconv.Itoa(os.Getpid())
+	if err := os.Rename(path, tmp); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil // Removed by another process in the meantime.
+		}
+		return err
+	}
+	moved, err := os.Stat(tmp)
+	if err != nil {
+		return err
+	}
+	if time.Since(moved.ModTime()) > staleAfter {
+		return os.Remove(tmp)
+	}
+	// Not stale anymore, restore the lock file unless a new one was created.
+	if err := os.Link(tmp, path); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	return os.Remove(tmp)
+}
+
+// create creates the lock file at path and returns its content,
+// which identifies the lock.
+func create(path string) ([]byte, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	content := fmt.Appendf(nil, "pid: %d\nhost: %s\ncreated: %s\n",
+		os.Getpid(), host, time.Now().Format(time.RFC3339Nano))
+	_, err = f.Write(content)
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+	return content, nil
+}
+
+// holder returns a description of the process holding the lock at path.
+func holder(path string) string {
+	c, err := os.ReadFile(path)
+	if err != nil {
+		return "held by unknown process"
+	}
+	return "held by " + strings.Join(strings.Fields(string(c)), " ")
+}
@@ -0,0 +1,109 @@
+package lockfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/romshark/localize/internal/lockfile"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+
+	l, err := lockfile.Acquire(path, lockfile.Options{})
+	require.NoError(t, err)
+	require.FileExists(t, path)
+
+	_, err = lockfile.Acquire(path, lockfile.Options{})
+	require.ErrorIs(t, err, lockfile.ErrLocked)
+
+	require.NoError(t, l.Release())
+	require.NoFileExists(t, path)
+
+	l, err = lockfile.Acquire(path, lockfile.Options{})
+	require.NoError(t, err)
+	require.NoError(t, l.Release())
+}
+
+func TestAcquireWait(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+
+	l, err := lockfile.Acquire(path, lockfile.Options{})
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = l.Release()
+	}()
+
+	l2, err := lockfile.Acquire(path, lockfile.Options{
+		Wait:         5 * time.Second,
+		PollInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, l2.Release())
+}
+
+func TestAcquireWaitTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+
+	l, err := lockfile.Acquire(path, lockfile.Options{})
+	require.NoError(t, err)
+	defer func() { _ = l.Release() }()
+
+	_, err = lockfile.Acquire(path, lockfile.Options{
+		Wait:         30 * time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+	})
+	require.ErrorIs(t, err, lockfile.ErrLocked)
+}
+
+func TestAcquireStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+	require.NoError(t, os.WriteFile(path, []byte("pid: 1\n"), 0o644))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	l, err := lockfile.Acquire(path, lockfile.Options{StaleAfter: time.Minute})
+	require.NoError(t, err)
+	require.NoError(t, l.Release())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Empty(t, entries, "the renamed stale lock file must be removed")
+}
+
+func TestAcquireRefreshed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+	o := lockfile.Options{StaleAfter: 60 * time.Millisecond}
+
+	l, err := lockfile.Acquire(path, o)
+	require.NoError(t, err)
+
+	// The lock is held for longer than StaleAfter but never goes stale.
+	time.Sleep(200 * time.Millisecond)
+	_, err = lockfile.Acquire(path, o)
+	require.ErrorIs(t, err, lockfile.ErrLocked)
+
+	require.NoError(t, l.Release())
+	require.NoFileExists(t, path)
+}
+
+func TestReleaseReplaced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+
+	l, err := lockfile.Acquire(path, lockfile.Options{})
+	require.NoError(t, err)
+
+	// Another process removed the lock as stale and acquired it.
+	require.NoError(t, os.Remove(path))
+	l2, err := lockfile.Acquire(path, lockfile.Options{})
+	require.NoError(t, err)
+
+	require.Error(t, l.Release())
+	require.FileExists(t, path)
+	require.NoError(t, l2.Release())
+}
@@ -0,0 +1,189 @@
+// Package markup checks that translations preserve the HTML tags,
+// markdown markers and URLs of their source texts.
+package markup
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+)
+
+// ErrMarkup is wrapped by all issues reported by Check and CheckFile.
+var ErrMarkup = errors.New("markup differs from source")
+
+var (
+	regexpTag = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9-]*)(?:\s[^<>]*)?(/?)>`)
+	regexpURL = regexp.MustCompile(`(?:https?|ftp)://[^\s<>"'` + "`" + `]+|mailto:[^\s<>"'` + "`" + `]+`)
+)
+
+// voidElements are the HTML elements that have no closing tag.
+var voidElements = []string{
+	"area", "base", "br", "col", "embed", "hr", "img", "input",
+	"link", "meta", "param", "source", "track", "wbr",
+}
+
+// markdownMarkers are the markdown markers that must occur
+// as often in the translation as they do in the source text.
+// Single "*" and "_" are excluded since they're common in plain text.
+var markdownMarkers = []string{"**", "__", "~~", "`", "]("}
+
+// tag is an HTML tag, attributes are ignored.
+type tag struct {
+	name        string
+	closing     bool
+	selfClosing bool
+}
+
+func (t tag) String() string {
+	switch {
+	case t.closing:
+		return "</" + t.name + ">"
+	case t.selfClosing:
+		return "<" + t.name + "/>"
+	}
+	return "<" + t.name + ">"
+}
+
+func tags(s string) []tag {
+	var l []tag
+	for _, m := range regexpTag.FindAllStringSubmatch(s, -1) {
+		l = append(l, tag{
+			name:        strings.ToLower(m[2]),
+			closing:     m[1] != "",
+			selfClosing: m[3] != "",
+		})
+	}
+	return l
+}
+
+// unbalanced returns the first closing tag of l that doesn't close
+// the last open tag, or the first unclosed tag.
+// Returns false if the tags of l are balanced.
+func unbalanced(l []tag) (tag, bool) {
+	var open []tag
+	for _, t := range l {
+		switch {
+		case t.selfClosing || slices.Contains(voidElements, t.name):
+		case !t.closing:
+			open = append(open, t)
+		case len(open) == 0 || open[len(open)-1].name != t.name:
+			return t, true
+		default:
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		return open[0], true
+	}
+	return tag{}, false
+}
+
+// urls returns the URLs and mailto links in s.
+func urls(s string) []string {
+	l := regexpURL.FindAllString(s, -1)
+	for i, u := range l {
+		l[i] = strings.TrimRight(u, ".,;:!?)]")
+	}
+	return l
+}
+
+// Check returns the issues of translation that doesn't preserve
+// the markup of source:
+//
+//   - HTML tags of source must occur in translation as often as in source,
+//     attributes are ignored. Translation must not contain other tags.
+//   - Translation tags must be balanced if the source tags are.
+//     Balance is only checked if the tags don't differ otherwise.
+//   - Markdown markers "**", "__", "~~", "`" and links must occur
+//     as often as in source.
+//   - URLs of source must occur verbatim in translation.
+func Check(source, translation string) (issues []error) {
+	add := func(format string, a ...any) {
+		issues = append(issues, fmt.Errorf("%w: "+format, append([]any{ErrMarkup}, a...)...))
+	}
+
+	srcTags, trTags := tags(source), tags(translation)
+	count := map[tag]int{}
+	for _, t := range srcTags {
+		count[t]++
+	}
+	for _, t := range trTags {
+		count[t]--
+	}
+	reported := map[tag]bool{}
+	tagsDiffer := false
+	for _, t := range slices.Concat(srcTags, trTags) {
+		if reported[t] {
+			continue
+		}
+		reported[t] = true
+		switch c := count[t]; {
+		case c > 0:
+			add("tag %s missing", t)
+			tagsDiffer = true
+		case c < 0:
+			add("tag %s not in source", t)
+			tagsDiffer = true
+		}
+	}
+	if _, ok := unbalanced(srcTags); !ok && !tagsDiffer {
+		if t, ok := unbalanced(trTags); ok {
+			add("unbalanced tag %s", t)
+		}
+	}
+
+	for _, m := range markdownMarkers {
+		if s, t := strings.Count(source, m), strings.Count(translation, m); s != t {
+			add("markdown %q used %d times instead of %d", m, t, s)
+		}
+	}
+
+	for _, u := range urls(source) {
+		if !strings.Contains(translation, u) {
+			add("URL %s missing", u)
+		}
+	}
+	return issues
+}
+
+// CheckFile returns the issues of all translated active messages of f
+// found by Check. Plural forms preserving the markup of msgid pass,
+// others are checked against msgid_plural.
+func CheckFile(f *gettext.File) (issues []gettext.Error) {
+	for i := range f.Messages.List {
+		m := &f.Messages.List[i]
+		if m.Obsolete {
+			continue
+		}
+		msgid := m.Msgid.Text.String()
+		if len(m.MsgidPlural.Text.Lines) == 0 {
+			issues = appendIssues(issues, m.Msgstr, msgid)
+			continue
+		}
+		msgidPlural := m.MsgidPlural.Text.String()
+		for _, s := range [...]gettext.Msgstr{
+			m.Msgstr0, m.Msgstr1, m.Msgstr2, m.Msgstr3, m.Msgstr4, m.Msgstr5,
+		} {
+			if len(Check(msgid, s.Text.String())) == 0 {
+				continue
+			}
+			issues = appendIssues(issues, s, msgidPlural)
+		}
+	}
+	return issues
+}
+
+func appendIssues(issues []gettext.Error, s gettext.Msgstr, source string) []gettext.Error {
+	translation := s.Text.String()
+	if translation == "" {
+		return issues
+	}
+	for _, err := range Check(source, translation) {
+		issues = append(issues, gettext.Error{Pos: s.Position, Err: err})
+	}
+	return issues
+}
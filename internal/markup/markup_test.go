@@ -0,0 +1,86 @@
+package markup_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/markup"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	f := func(t *testing.T, source, translation string, expect ...string) {
+		t.Helper()
+		var actual []string
+		for _, err := range markup.Check(source, translation) {
+			require.ErrorIs(t, err, markup.ErrMarkup)
+			actual = append(actual, err.Error())
+		}
+		require.Equal(t, expect, actual)
+	}
+
+	f(t, "plain", "schlicht")
+	f(t, `Click <a href="/x">here</a>`, `Klicke <a href="/y">hier</a>`)
+	f(t, "Line<br>break", "Zeilen<BR>umbruch")
+	f(t, "<b>bold</b> and <i>italic</i>", "<i>kursiv</i> und <b>fett</b>")
+	f(t, "**bold** and `code`", "**fett** und `code`")
+	f(t, "See [docs](https://example.com/docs).",
+		"Siehe [Doku](https://example.com/docs).")
+	f(t, "Mail mailto:a@example.com", "E-Mail an mailto:a@example.com")
+
+	f(t, "<b>bold</b>", "fett",
+		"markup differs from source: tag <b> missing",
+		"markup differs from source: tag </b> missing")
+	f(t, "bold", "<b>fett",
+		"markup differs from source: tag <b> not in source")
+	f(t, "<b>bold</b>", "</b>fett<b>",
+		"markup differs from source: unbalanced tag </b>")
+	f(t, "<b>a</b> <i>b</i>", "<b>a <i>b</b></i>",
+		"markup differs from source: unbalanced tag </b>")
+	f(t, "a<br/>b", "a<br>b",
+		"markup differs from source: tag <br/> missing",
+		"markup differs from source: tag <br> not in source")
+	f(t, "**bold**", "**fett*",
+		`markup differs from source: markdown "**" used 1 times instead of 2`)
+	f(t, "Visit https://example.com/a?b=c.", "Besuche https://example.de/a?b=c.",
+		"markup differs from source: URL https://example.com/a?b=c missing")
+}
+
+func TestCheckFile(t *testing.T) {
+	src := `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "<b>Hello</b>"
+msgstr "<b>Hallo</b>"
+
+msgid "Visit https://example.com"
+msgstr "Besuche https://example.de"
+
+msgid "Untranslated <b>"
+msgstr ""
+
+msgid "<b>%d</b> file"
+msgid_plural "<b>%d</b> files"
+msgstr[0] "<b>%d</b> Datei"
+msgstr[1] "%d Dateien"
+
+#~ msgid "**Obsolete**"
+#~ msgstr "Veraltet"
+`
+	po, err := gettext.NewDecoder().DecodePOBytes("catalog.de.po", []byte(src))
+	require.NoError(t, err)
+
+	var actual []string
+	for _, e := range markup.CheckFile(po.File) {
+		actual = append(actual, e.Error())
+	}
+	require.Equal(t, []string{
+		"catalog.de.po:11:1: markup differs from source: " +
+			"URL https://example.com missing",
+		"catalog.de.po:19:1: markup differs from source: tag <b> missing",
+		"catalog.de.po:19:1: markup differs from source: tag </b> missing",
+	}, actual)
+}
@@ -0,0 +1,455 @@
+// Package migrate rewrites string literals of existing Go code
+// into Reader.Text calls, inserting Reader parameters where needed.
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// targetPackage is the import path of package localize.
+const targetPackage = "github.com/romshark/localize"
+
+// Candidate is a string literal that can be rewritten to a Reader.Text call.
+type Candidate struct {
+	Pos  token.Position
+	Text string
+}
+
+// Note is an issue requiring manual work.
+type Note struct {
+	Pos     token.Position
+	Message string
+}
+
+func (n Note) String() string { return n.Pos.String() + ": " + n.Message }
+
+// Options configure Rewrite.
+type Options struct {
+	// ReaderName is the name of inserted Reader parameters.
+	ReaderName string
+
+	// Select reports whether a candidate should be rewritten.
+	// All candidates that look like human readable text are rewritten if nil.
+	Select func(Candidate) (bool, error)
+}
+
+// File is the result of rewriting a file.
+type File struct {
+	Path string
+
+	// Src is the rewritten source code.
+	Src []byte
+
+	// Migrated are the rewritten literals.
+	Migrated []Candidate
+}
+
+// Result is the result of Run.
+type Result struct {
+	// Files are all changed files ordered by path.
+	Files []File
+	Notes []Note
+}
+
+// Run rewrites the string literals of all packages matching pathPattern.
+// Test files and generated files are skipped. Files aren't written.
+func Run(ctx context.Context, pathPattern string, opts Options) (*Result, error) {
+	fset := token.NewFileSet()
+	pkgs, err := packages.Load(&packages.Config{
+		Context: ctx,
+		Mode: packages.NeedFiles |
+			packages.NeedSyntax |
+			packages.NeedTypes |
+			packages.NeedTypesInfo |
+			packages.NeedName |
+			packages.NeedDeps,
+		Fset: fset,
+	}, pathPattern)
+	if err != nil {
+		return nil, err
+	}
+	r := &Result{}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, pkg.Errors[0]
+		}
+		if pkg.PkgPath == targetPackage {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			if ast.IsGenerated(file) {
+				continue
+			}
+			path := fset.File(file.Pos()).Name()
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("reading source file: %w", err)
+			}
+			out, migrated, notes, err := Rewrite(
+				fset, file, src, pkg.TypesInfo, opts,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("rewriting %s: %w", path, err)
+			}
+			r.Notes = append(r.Notes, notes...)
+			if len(migrated) > 0 {
+				r.Files = append(r.Files, File{Path: path, Src: out, Migrated: migrated})
+			}
+		}
+	}
+	slices.SortFunc(r.Files, func(a, b File) int { return strings.Compare(a.Path, b.Path) })
+	if wd, err := os.Getwd(); err == nil {
+		// Report positions relative to the working directory.
+		rel := func(p *token.Position) {
+			if r, err := filepath.Rel(wd, p.Filename); err == nil {
+				p.Filename = r
+			}
+		}
+		for i := range r.Notes {
+			rel(&r.Notes[i].Pos)
+		}
+		for _, f := range r.Files {
+			for i := range f.Migrated {
+				rel(&f.Migrated[i].Pos)
+			}
+		}
+	}
+	return r, nil
+}
+
+// edit replaces src[start:end] with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// Rewrite rewrites the string literals of file with source code src
+// into Reader.Text calls using the Reader in scope or a Reader parameter
+// inserted into the enclosing function. Literals that can't be rewritten
+// without changing the semantics are skipped, such as constants, operands
+// of binary expressions, map keys and arguments of errors, log and strings
+// functions. Returns the formatted source code, the rewritten literals
+// and the notes on skipped literals and inserted parameters.
+func Rewrite(
+	fset *token.FileSet, file *ast.File, src []byte, info *types.Info, opts Options,
+) (out []byte, migrated []Candidate, notes []Note, err error) {
+	readerName := opts.ReaderName
+	if readerName == "" {
+		readerName = "l"
+	}
+	tokFile := fset.File(file.Pos())
+	localizeName := importName(file)
+
+	var edits []edit
+	insertedParams := map[*ast.FuncDecl]bool{}
+	var stack []ast.Node
+	var walkErr error
+	ast.Inspect(file, func(n ast.Node) bool {
+		if walkErr != nil {
+			return false
+		}
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		text, err := strconv.Unquote(lit.Value)
+		if err != nil || skipped(stack, info) || !isString(info, lit) {
+			return true
+		}
+		c := Candidate{Pos: fset.Position(lit.Pos()), Text: text}
+		if opts.Select == nil {
+			if !LooksLikeText(text) {
+				return true
+			}
+		} else if ok, err := opts.Select(c); err != nil {
+			walkErr = err
+			return false
+		} else if !ok {
+			return true
+		}
+
+		name, fn, note := reader(stack, info, lit, readerName)
+		if note != "" {
+			notes = append(notes, Note{Pos: c.Pos, Message: note})
+			return true
+		}
+		if fn != nil && !insertedParams[fn] {
+			insertedParams[fn] = true
+			param := readerName + " " + localizeName + ".Reader"
+			if len(fn.Type.Params.List) > 0 {
+				param += ", "
+			}
+			at := tokFile.Offset(fn.Type.Params.Opening) + 1
+			edits = append(edits, edit{start: at, end: at, text: param})
+			notes = append(notes, Note{
+				Pos: fset.Position(fn.Pos()),
+				Message: fmt.Sprintf(
+					"parameter %s inserted into %s, update its callers",
+					readerName, fn.Name.Name,
+				),
+			})
+		}
+		edits = append(edits, edit{
+			start: tokFile.Offset(lit.Pos()),
+			end:   tokFile.Offset(lit.End()),
+			text:  name + ".Text(" + lit.Value + ")",
+		})
+		migrated = append(migrated, c)
+		return true
+	})
+	if walkErr != nil {
+		return nil, nil, nil, walkErr
+	}
+	if len(migrated) == 0 {
+		return src, nil, notes, nil
+	}
+
+	slices.SortFunc(edits, func(a, b edit) int { return b.start - a.start })
+	out = slices.Clone(src)
+	for _, e := range edits {
+		out = slices.Concat(out[:e.start], []byte(e.text), out[e.end:])
+	}
+
+	if len(insertedParams) > 0 && !imports(file) {
+		fs := token.NewFileSet()
+		f, err := parser.ParseFile(fs, tokFile.Name(), out, parser.ParseComments)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("parsing rewritten source: %w", err)
+		}
+		astutil.AddImport(fs, f, targetPackage)
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fs, f); err != nil {
+			return nil, nil, nil, fmt.Errorf("formatting rewritten source: %w", err)
+		}
+		out = buf.Bytes()
+	}
+	if out, err = format.Source(out); err != nil {
+		return nil, nil, nil, fmt.Errorf("formatting rewritten source: %w", err)
+	}
+	return out, migrated, notes, nil
+}
+
+// LooksLikeText reports whether s looks like human readable text,
+// which is at least two words of letters, rather than an identifier,
+// a path, a URL or a format string.
+func LooksLikeText(s string) bool {
+	if strings.Contains(s, "://") || strings.Contains(s, "{{") {
+		return false
+	}
+	words := 0
+	for w := range strings.FieldsSeq(s) {
+		letters := 0
+		for _, r := range w {
+			if unicode.IsLetter(r) {
+				letters++
+			}
+		}
+		if letters > 1 || letters == 1 && len([]rune(w)) == 1 {
+			words++
+		}
+	}
+	return words > 1
+}
+
+// skippedPackages are the packages whose function arguments aren't
+// rewritten since they aren't shown to users.
+var skippedPackages = []string{
+	targetPackage, "errors", "log", "log/slog", "os", "os/exec", "path",
+	"path/filepath", "reflect", "regexp", "strconv", "strings", "testing",
+	"time",
+}
+
+// skipped reports whether the string literal at the top of stack
+// can't be rewritten without changing the semantics or isn't user facing.
+func skipped(stack []ast.Node, info *types.Info) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.ImportSpec, *ast.Field, *ast.CaseClause:
+			return true
+		case *ast.GenDecl:
+			return n.Tok == token.CONST
+		case *ast.BinaryExpr:
+			return true
+		case *ast.IndexExpr:
+			return n.Index == child(stack, i)
+		case *ast.KeyValueExpr:
+			if n.Key == child(stack, i) {
+				return true
+			}
+		case *ast.CallExpr:
+			if skippedCall(n, info) {
+				return true
+			}
+			if tv, ok := info.Types[n.Fun]; ok && tv.IsType() {
+				// Conversions like []byte("text").
+				return true
+			}
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		}
+	}
+	return false
+}
+
+// child returns the node in stack below stack[i].
+func child(stack []ast.Node, i int) ast.Node { return stack[i+1] }
+
+// skippedCall reports whether call calls panic or a function
+// of the skipped packages.
+func skippedCall(call *ast.CallExpr, info *types.Info) bool {
+	var ident *ast.Ident
+	switch f := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	default:
+		return false
+	}
+	switch obj := info.Uses[ident].(type) {
+	case *types.Builtin:
+		return obj.Name() == "panic"
+	case *types.Func:
+		if obj.Pkg() != nil && slices.Contains(skippedPackages, obj.Pkg().Path()) {
+			return true
+		}
+		// Methods of types like *log.Logger, *slog.Logger or localize.Reader.
+		sig, _ := obj.Type().(*types.Signature)
+		if sig != nil && sig.Recv() != nil {
+			if named := namedOf(sig.Recv().Type()); named != nil &&
+				named.Obj().Pkg() != nil &&
+				slices.Contains(skippedPackages, named.Obj().Pkg().Path()) {
+				return true
+			}
+		}
+		if obj.Name() == "Errorf" && obj.Pkg() != nil && obj.Pkg().Path() == "fmt" {
+			return true
+		}
+	}
+	return false
+}
+
+func namedOf(t types.Type) *types.Named {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, _ := t.(*types.Named)
+	return n
+}
+
+// isString reports whether lit is of type string, such that it can be replaced
+// by a call returning a string, unlike literals of named string types.
+func isString(info *types.Info, lit *ast.BasicLit) bool {
+	tv, ok := info.Types[lit]
+	if !ok {
+		return false
+	}
+	b, ok := tv.Type.(*types.Basic)
+	return ok && (b.Kind() == types.String || b.Kind() == types.UntypedString)
+}
+
+// reader returns the name of the Reader in scope of lit. If there is none,
+// reader returns readerName and the function declaration the parameter
+// must be inserted into. note is set if lit can't be rewritten.
+func reader(
+	stack []ast.Node, info *types.Info, lit *ast.BasicLit, readerName string,
+) (name string, insertInto *ast.FuncDecl, note string) {
+	var fn *ast.FuncDecl
+	var scope *types.Scope
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncDecl:
+			fn = n
+			if scope == nil {
+				scope = info.Scopes[n.Type]
+			}
+		case *ast.FuncLit:
+			if scope == nil {
+				scope = info.Scopes[n.Type]
+			}
+		}
+		if fn != nil {
+			break
+		}
+	}
+	if fn == nil || scope == nil {
+		return "", nil, "string outside of a function, rewrite manually"
+	}
+	scope = scope.Innermost(lit.Pos())
+	for s := scope; s != nil && s != types.Universe; s = s.Parent() {
+		for _, n := range s.Names() {
+			v, ok := s.Lookup(n).(*types.Var)
+			if ok && v.Pos() < lit.Pos() && isReader(v.Type()) {
+				return n, nil, ""
+			}
+		}
+	}
+	if fn.Recv == nil && (fn.Name.Name == "main" || fn.Name.Name == "init") {
+		return "", nil, fmt.Sprintf(
+			"no Reader in scope and func %s can't take parameters", fn.Name.Name,
+		)
+	}
+	if l := fn.Type.Params.List; len(l) > 0 && len(l[0].Names) == 0 {
+		return "", nil, fmt.Sprintf(
+			"no Reader in scope and the parameters of func %s are unnamed",
+			fn.Name.Name,
+		)
+	}
+	if _, obj := scope.LookupParent(readerName, lit.Pos()); obj != nil {
+		return "", nil, fmt.Sprintf(
+			"no Reader in scope and %s is already declared", readerName,
+		)
+	}
+	return readerName, fn, ""
+}
+
+// isReader reports whether t is localize.Reader.
+func isReader(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil &&
+		n.Obj().Pkg().Path() == targetPackage && n.Obj().Name() == "Reader"
+}
+
+// importName returns the name package localize is imported as in file,
+// which is "localize" if it isn't imported.
+func importName(file *ast.File) string {
+	for _, s := range file.Imports {
+		if path, _ := strconv.Unquote(s.Path.Value); path == targetPackage &&
+			s.Name != nil && s.Name.Name != "_" && s.Name.Name != "." {
+			return s.Name.Name
+		}
+	}
+	return "localize"
+}
+
+// imports reports whether file imports package localize.
+func imports(file *ast.File) bool {
+	for _, s := range file.Imports {
+		if path, _ := strconv.Unquote(s.Path.Value); path == targetPackage &&
+			(s.Name == nil || s.Name.Name != "_") {
+			return true
+		}
+	}
+	return false
+}
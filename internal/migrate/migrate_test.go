@@ -0,0 +1,212 @@
+package migrate_test
+
+import (
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/romshark/localize/internal/migrate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLooksLikeText(t *testing.T) {
+	f := func(t *testing.T, s string, expect bool) {
+		t.Helper()
+		require.Equal(t, expect, migrate.LooksLikeText(s), "%q", s)
+	}
+	f(t, "Hello world", true)
+	f(t, "Welcome back, %s!", true)
+	f(t, "I agree", true)
+	f(t, "Save", false)
+	f(t, "user_id", false)
+	f(t, "%s: %d", false)
+	f(t, "https://example.com/some path", false)
+	f(t, "{{.Name}} is here", false)
+	f(t, "", false)
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+const stub = `package localize
+type Reader interface{ Text(string) string }
+`
+
+func rewrite(
+	t *testing.T, src string, opts migrate.Options,
+) (string, []migrate.Candidate, []migrate.Note, error) {
+	t.Helper()
+	fset := token.NewFileSet()
+	stubFile, err := parser.ParseFile(fset, "localize.go", stub, 0)
+	require.NoError(t, err)
+	localize, err := new(types.Config).Check(
+		"github.com/romshark/localize", fset, []*ast.File{stubFile}, nil,
+	)
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	require.NoError(t, err)
+	info := &types.Info{
+		Types:  map[ast.Expr]types.TypeAndValue{},
+		Uses:   map[*ast.Ident]types.Object{},
+		Defs:   map[*ast.Ident]types.Object{},
+		Scopes: map[ast.Node]*types.Scope{},
+	}
+	std := importer.Default()
+	conf := types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+		if path == "github.com/romshark/localize" {
+			return localize, nil
+		}
+		return std.Import(path)
+	})}
+	_, err = conf.Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	out, migrated, notes, err := migrate.Rewrite(fset, file, []byte(src), info, opts)
+	return string(out), migrated, notes, err
+}
+
+func TestRewrite(t *testing.T) {
+	out, migrated, notes, err := rewrite(t, `package p
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Status string
+
+const title = "Constant title text"
+
+var global = "Package level text"
+
+func greet(name string) string {
+	if name == "nobody at all" {
+		return fmt.Sprintf("Welcome back, %s!", name)
+	}
+	m := map[string]string{"map key text": "map value text"}
+	_ = m
+	_ = errors.New("some error text")
+	_ = Status("named string text")
+	return "Hello world" // Greeting.
+}
+
+func none() {
+	_ = func() string { return "Inside a closure" }
+}
+
+func main() {
+	println("Text in main")
+}
+`, migrate.Options{})
+	require.NoError(t, err)
+	require.Equal(t, `package p
+
+import (
+	"errors"
+	"fmt"
+	"github.com/romshark/localize"
+)
+
+type Status string
+
+const title = "Constant title text"
+
+var global = "Package level text"
+
+func greet(l localize.Reader, name string) string {
+	if name == "nobody at all" {
+		return fmt.Sprintf(l.Text("Welcome back, %s!"), name)
+	}
+	m := map[string]string{"map key text": l.Text("map value text")}
+	_ = m
+	_ = errors.New("some error text")
+	_ = Status("named string text")
+	return l.Text("Hello world") // Greeting.
+}
+
+func none(l localize.Reader) {
+	_ = func() string { return l.Text("Inside a closure") }
+}
+
+func main() {
+	println("Text in main")
+}
+`, out)
+
+	texts := make([]string, len(migrated))
+	for i, c := range migrated {
+		texts[i] = c.Text
+	}
+	require.Equal(t, []string{
+		"Welcome back, %s!", "map value text", "Hello world", "Inside a closure",
+	}, texts)
+
+	messages := make([]string, len(notes))
+	for i, n := range notes {
+		messages[i] = n.String()
+	}
+	require.Equal(t, []string{
+		"p.go:12:14: string outside of a function, rewrite manually",
+		"p.go:14:1: parameter l inserted into greet, update its callers",
+		"p.go:25:1: parameter l inserted into none, update its callers",
+		"p.go:30:10: no Reader in scope and func main can't take parameters",
+	}, messages)
+}
+
+func TestRewriteReaderInScope(t *testing.T) {
+	out, _, notes, err := rewrite(t, `package p
+
+import loc "github.com/romshark/localize"
+
+func a(tr loc.Reader) string { return "Hello world" }
+
+func b(l int) string { return "Hello world" }
+
+func c() string { return "Hello world" }
+`, migrate.Options{})
+	require.NoError(t, err)
+	require.Equal(t, `package p
+
+import loc "github.com/romshark/localize"
+
+func a(tr loc.Reader) string { return tr.Text("Hello world") }
+
+func b(l int) string { return "Hello world" }
+
+func c(l loc.Reader) string { return l.Text("Hello world") }
+`, out)
+	require.Len(t, notes, 2)
+	require.Equal(t, "no Reader in scope and l is already declared", notes[0].Message)
+}
+
+func TestRewriteSelect(t *testing.T) {
+	src := `package p
+
+func f(s string) []string { return []string{"Save", "Hello world", s} }
+`
+	out, migrated, _, err := rewrite(t, src, migrate.Options{
+		ReaderName: "r",
+		Select: func(c migrate.Candidate) (bool, error) {
+			return c.Text == "Save", nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []migrate.Candidate{{
+		Pos:  token.Position{Filename: "p.go", Offset: 55, Line: 3, Column: 45},
+		Text: "Save",
+	}}, migrated)
+	require.Contains(t, out,
+		`func f(r localize.Reader, s string) []string { return []string{r.Text("Save"), "Hello world", s} }`)
+
+	errStop := errors.New("stop")
+	_, _, _, err = rewrite(t, src, migrate.Options{
+		Select: func(migrate.Candidate) (bool, error) { return false, errStop },
+	})
+	require.ErrorIs(t, err, errStop)
+}
@@ -0,0 +1,152 @@
+// Package msgimport converts messages imported from other localization
+// libraries (see packages goi18n and xtextimport) to translation catalogs
+// and suggested Reader calls.
+package msgimport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/codeparser"
+	"golang.org/x/text/language"
+)
+
+// Forms are the texts of a message by CLDR plural form.
+type Forms struct{ Zero, One, Two, Few, Many, Other string }
+
+// ByCLDR returns the text of CLDR plural form.
+func (f Forms) ByCLDR(form cldr.CLDRPluralForm) string {
+	switch form {
+	case cldr.CLDRPluralFormZero:
+		return f.Zero
+	case cldr.CLDRPluralFormOne:
+		return f.One
+	case cldr.CLDRPluralFormTwo:
+		return f.Two
+	case cldr.CLDRPluralFormFew:
+		return f.Few
+	case cldr.CLDRPluralFormMany:
+		return f.Many
+	}
+	return f.Other
+}
+
+// List returns the non-empty texts of f in CLDR order.
+func (f Forms) List() []string {
+	var l []string
+	for _, s := range []string{f.Zero, f.One, f.Two, f.Few, f.Many, f.Other} {
+		if s != "" {
+			l = append(l, s)
+		}
+	}
+	return l
+}
+
+// PluralCall returns the source code of the Reader.Plural call
+// of forms f and quantity argument quantity indented by one tab, such as:
+//
+//	r.Plural(localize.Forms{
+//			One:   "%d cat",
+//			Other: "%d cats",
+//		}, count)
+func PluralCall(f Forms, quantity string) string {
+	type form struct{ name, text string }
+	all := [...]form{
+		{"Zero", f.Zero},
+		{"One", f.One},
+		{"Two", f.Two},
+		{"Few", f.Few},
+		{"Many", f.Many},
+		{"Other", f.Other},
+	}
+	var forms []form
+	width := 0
+	for _, x := range all {
+		if x.text != "" {
+			forms = append(forms, x)
+			width = max(width, len(x.name))
+		}
+	}
+	var b strings.Builder
+	b.WriteString("r.Plural(localize.Forms{\n")
+	for _, x := range forms {
+		fmt.Fprintf(&b, "\t\t%-*s %s,\n", width+1, x.name+":", strconv.Quote(x.text))
+	}
+	b.WriteString("\t}, " + quantity + ")")
+	return b.String()
+}
+
+// Message is a converted message of the source locale.
+type Message struct {
+	Hash, Description string
+
+	// Plural is true for messages converted to Reader.Plural calls.
+	Plural bool
+
+	// Forms are the converted texts. Only Other is set for static messages.
+	Forms Forms
+}
+
+// Catalog returns the translation catalog of locale for messages.
+// translation returns the translated forms of the message with hash,
+// which are left empty for untranslated messages.
+// Only Other is used for static messages.
+func Catalog(
+	locale language.Tag, messages []Message,
+	translation func(hash string) Forms,
+) (gettext.FilePO, error) {
+	pluralForms, ok := cldr.ByTagOrBase(locale)
+	if !ok {
+		return gettext.FilePO{}, fmt.Errorf(
+			"couldn't find plural forms for locale: %s", locale.String())
+	}
+
+	collection := &codeparser.Collection{
+		Locale:   locale,
+		Messages: make(map[codeparser.Msg]codeparser.MsgMeta, len(messages)),
+	}
+	plural := make(map[string]bool, len(messages))
+	for _, m := range messages {
+		msg := codeparser.Msg{
+			Hash:        m.Hash,
+			Description: m.Description,
+			FuncType:    codeparser.FuncTypeText,
+			Other:       m.Forms.Other,
+		}
+		if m.Plural {
+			msg.FuncType = codeparser.FuncTypePlural
+			msg.Zero, msg.One, msg.Two = m.Forms.Zero, m.Forms.One, m.Forms.Two
+			msg.Few, msg.Many = m.Forms.Few, m.Forms.Many
+		}
+		collection.Messages[msg] = codeparser.MsgMeta{}
+		plural[m.Hash] = m.Plural
+	}
+
+	po := collection.MakePO(nil)
+	for i := range po.Messages.List {
+		m := &po.Messages.List[i]
+		hash := m.Msgctxt.Text.String()
+		f := translation(hash)
+		if !plural[hash] {
+			m.Msgstr.Text = text(f.Other)
+			continue
+		}
+		msgstrs := [...]*gettext.Msgstr{
+			&m.Msgstr0, &m.Msgstr1, &m.Msgstr2, &m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+		}
+		for j, form := range pluralForms.CardinalForms {
+			if j >= len(msgstrs) {
+				break
+			}
+			msgstrs[j].Text = text(f.ByCLDR(form))
+		}
+	}
+	return po, nil
+}
+
+func text(s string) gettext.StringLiterals {
+	return gettext.StringLiterals{Lines: []gettext.StringLiteral{{Value: s}}}
+}
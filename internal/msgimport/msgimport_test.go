@@ -0,0 +1,65 @@
+package msgimport_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/msgimport"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestCatalog(t *testing.T) {
+	messages := []msgimport.Message{
+		{Hash: "hello", Forms: msgimport.Forms{Other: "Hello"}},
+		{Hash: "files", Plural: true, Description: "Number of files.", Forms: msgimport.Forms{
+			One: "%d file", Other: "%d files",
+		}},
+		{Hash: "untranslated", Forms: msgimport.Forms{Other: "Untranslated"}},
+	}
+	po, err := msgimport.Catalog(language.Polish, messages,
+		func(hash string) msgimport.Forms {
+			switch hash {
+			case "hello":
+				return msgimport.Forms{Other: "Cześć"}
+			case "files":
+				return msgimport.Forms{
+					One: "%d plik", Few: "%d pliki", Many: "%d plików", Other: "%d pliku",
+				}
+			}
+			return msgimport.Forms{}
+		})
+	require.NoError(t, err)
+	require.Empty(t, po.Validate())
+
+	byHash := map[string][]string{}
+	for _, m := range po.Messages.List {
+		l := []string{m.Msgstr.Text.String()}
+		if len(m.MsgidPlural.Text.Lines) > 0 {
+			l = []string{
+				m.Msgstr0.Text.String(), m.Msgstr1.Text.String(),
+				m.Msgstr2.Text.String(),
+			}
+		}
+		byHash[m.Msgctxt.Text.String()] = l
+	}
+	require.Equal(t, map[string][]string{
+		"hello": {"Cześć"},
+		// Polish has the gettext plural forms one, few and other.
+		"files":        {"%d plik", "%d pliki", "%d pliku"},
+		"untranslated": {""},
+	}, byHash)
+
+	_, err = msgimport.Catalog(language.MustParse("tlh"), messages, nil)
+	require.ErrorContains(t, err, "couldn't find plural forms")
+}
+
+func TestPluralCall(t *testing.T) {
+	require.Equal(t, `r.Plural(localize.Forms{
+		One:   "%d cat",
+		Other: "%d cats",
+	}, count)`, msgimport.PluralCall(msgimport.Forms{
+		One: "%d cat", Other: "%d cats",
+	}, "count"))
+	require.Equal(t, []string{"%d cat", "%d cats"},
+		msgimport.Forms{One: "%d cat", Other: "%d cats"}.List())
+}
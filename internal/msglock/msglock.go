@@ -0,0 +1,145 @@
+// Package msglock maintains the message ID registry file (messages.lock)
+// assigning stable, monotonically increasing numeric IDs to messages.
+// IDs are never reassigned, not even after a message is removed.
+package msglock
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+)
+
+// FileName is the name of the registry file in the bundle package directory.
+const FileName = "messages.lock"
+
+// CommentPrefix is the prefix of the extracted comment carrying the message ID.
+const CommentPrefix = "X-Message-ID: "
+
+var ErrMalformed = errors.New("malformed registry file")
+
+// Registry maps message hashes to message IDs.
+type Registry struct {
+	idByHash map[string]uint64
+	last     uint64
+}
+
+// Load reads the registry from path.
+// Returns an empty registry if path doesn't exist.
+func Load(path string) (*Registry, error) {
+	r := &Registry{idByHash: map[string]uint64{}}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening registry file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	s := bufio.NewScanner(f)
+	for line := 1; s.Scan(); line++ {
+		t := strings.TrimSpace(s.Text())
+		if t == "" || strings.HasPrefix(t, "#") {
+			continue
+		}
+		idStr, hash, ok := strings.Cut(t, " ")
+		if !ok {
+			return nil, fmt.Errorf("%w (%s:%d): expected <id> <hash>",
+				ErrMalformed, path, line)
+		}
+		id, err := strconv.ParseUint(idStr, 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("%w (%s:%d): invalid id %q",
+				ErrMalformed, path, line, idStr)
+		}
+		if _, ok := r.idByHash[hash]; ok {
+			return nil, fmt.Errorf("%w (%s:%d): duplicate hash %q",
+				ErrMalformed, path, line, hash)
+		}
+		r.idByHash[hash] = id
+		r.last = max(r.last, id)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("reading registry file: %w", err)
+	}
+	return r, nil
+}
+
+// ID returns the ID of the message identified by hash.
+func (r *Registry) ID(hash string) (id uint64, ok bool) {
+	id, ok = r.idByHash[hash]
+	return id, ok
+}
+
+// Assign returns the ID of the message identified by hash
+// assigning a new one if it doesn't have one yet, in which case added is true.
+func (r *Registry) Assign(hash string) (id uint64, added bool) {
+	if id, ok := r.idByHash[hash]; ok {
+		return id, false
+	}
+	r.last++
+	r.idByHash[hash] = r.last
+	return r.last, true
+}
+
+// Rehash replaces the hashes of r found in newByOld by their new hashes
+// keeping their IDs, such that messages keep their IDs when their hashes
+// are migrated to another hash function.
+func (r *Registry) Rehash(newByOld map[string]string) {
+	idByHash := make(map[string]uint64, len(r.idByHash))
+	for h, id := range r.idByHash {
+		if n, ok := newByOld[h]; ok {
+			h = n
+		}
+		idByHash[h] = id
+	}
+	r.idByHash = idByHash
+}
+
+// WriteFile writes the registry to path ordered by ID.
+func (r *Registry) WriteFile(path string) error {
+	hashes := make([]string, 0, len(r.idByHash))
+	for h := range r.idByHash {
+		hashes = append(hashes, h)
+	}
+	slices.SortFunc(hashes, func(a, b string) int {
+		return cmp.Compare(r.idByHash[a], r.idByHash[b])
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("# generated by github.com/romshark/localize/cmd/localize.\n")
+	buf.WriteString("# Commit this file and never edit it manually.\n")
+	for _, h := range hashes {
+		buf.WriteString(strconv.FormatUint(r.idByHash[h], 10))
+		buf.WriteByte(' ')
+		buf.WriteString(h)
+		buf.WriteByte('\n')
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// SetComment sets the message ID comment of m to id
+// replacing any existing message ID comment.
+func SetComment(m *gettext.Message, id uint64) {
+	value := CommentPrefix + strconv.FormatUint(id, 10)
+	for i, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeExtracted &&
+			strings.HasPrefix(c.Value, CommentPrefix) {
+			m.Msgctxt.Comments.Text[i].Value = value
+			return
+		}
+	}
+	m.Msgctxt.Comments.Text = append(m.Msgctxt.Comments.Text, gettext.Comment{
+		Type:  gettext.CommentTypeExtracted,
+		Value: value,
+	})
+}
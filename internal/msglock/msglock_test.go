@@ -0,0 +1,86 @@
+package msglock_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/msglock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssign(t *testing.T) {
+	path := filepath.Join(t.TempDir(), msglock.FileName)
+
+	r, err := msglock.Load(path)
+	require.NoError(t, err)
+
+	id, added := r.Assign("bbbb")
+	require.True(t, added)
+	require.Equal(t, uint64(1), id)
+	id, added = r.Assign("aaaa")
+	require.True(t, added)
+	require.Equal(t, uint64(2), id)
+	id, added = r.Assign("bbbb")
+	require.False(t, added)
+	require.Equal(t, uint64(1), id)
+
+	require.NoError(t, r.WriteFile(path))
+
+	r, err = msglock.Load(path)
+	require.NoError(t, err)
+	id, ok := r.ID("aaaa")
+	require.True(t, ok)
+	require.Equal(t, uint64(2), id)
+	_, ok = r.ID("cccc")
+	require.False(t, ok)
+
+	// IDs are never reused.
+	id, added = r.Assign("cccc")
+	require.True(t, added)
+	require.Equal(t, uint64(3), id)
+}
+
+func TestRehash(t *testing.T) {
+	r, err := msglock.Load(filepath.Join(t.TempDir(), msglock.FileName))
+	require.NoError(t, err)
+	r.Assign("aaaa")
+	r.Assign("bbbb")
+
+	r.Rehash(map[string]string{"aaaa": "aaaaaaaa", "cccc": "cccccccc"})
+	id, ok := r.ID("aaaaaaaa")
+	require.True(t, ok)
+	require.Equal(t, uint64(1), id)
+	_, ok = r.ID("aaaa")
+	require.False(t, ok)
+	id, ok = r.ID("bbbb")
+	require.True(t, ok)
+	require.Equal(t, uint64(2), id)
+	_, ok = r.ID("cccccccc")
+	require.False(t, ok)
+}
+
+func TestLoadErrMalformed(t *testing.T) {
+	f := func(t *testing.T, contents string) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), msglock.FileName)
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+		_, err := msglock.Load(path)
+		require.ErrorIs(t, err, msglock.ErrMalformed)
+	}
+	f(t, "1\n")
+	f(t, "x aaaa\n")
+	f(t, "0 aaaa\n")
+	f(t, "1 aaaa\n2 aaaa\n")
+}
+
+func TestSetComment(t *testing.T) {
+	var m gettext.Message
+	msglock.SetComment(&m, 1)
+	msglock.SetComment(&m, 2)
+	require.Equal(t, []gettext.Comment{{
+		Type:  gettext.CommentTypeExtracted,
+		Value: "X-Message-ID: 2",
+	}}, m.Msgctxt.Comments.Text)
+}
@@ -0,0 +1,143 @@
+// Package msgmerge updates translation catalogs from templates
+// like GNU msgmerge.
+package msgmerge
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/romshark/localize/gettext"
+)
+
+// Result is the result of Merge.
+type Result struct {
+	// Kept is the number of messages of the template found in the catalog,
+	// Added the number of messages added to the catalog
+	// and Obsoleted the number of messages of the catalog made obsolete.
+	Kept, Added, Obsoleted int
+}
+
+// Merge updates catalog def to the messages of template ref.
+// Messages are matched by msgctxt and msgid and ordered like in ref.
+// Matched messages keep their translations, translator comments and flags
+// of def and take their msgid_plural, extracted comments and references
+// from ref, obsolete messages of def are revived.
+// Messages missing in def are added untranslated with as many plural forms
+// as the Plural-Forms header of def defines. Messages of def missing in ref
+// are made obsolete and moved to the end.
+// The head of def is kept.
+func Merge(def, ref *gettext.File) Result {
+	type key struct{ msgctxt, msgid string }
+	byKey := make(map[key]int, len(def.Messages.List))
+	for i, m := range def.Messages.List {
+		byKey[key{m.Msgctxt.Text.String(), m.Msgid.Text.String()}] = i
+	}
+
+	var r Result
+	list := make([]gettext.Message, 0, len(ref.Messages.List))
+	matched := make([]bool, len(def.Messages.List))
+	for _, m := range ref.Messages.List {
+		if m.Obsolete {
+			continue
+		}
+		merged := m.Clone()
+		i, ok := byKey[key{m.Msgctxt.Text.String(), m.Msgid.Text.String()}]
+		if ok && isPlural(&def.Messages.List[i]) == isPlural(&m) {
+			matched[i] = true
+			mergeMessage(&merged, &def.Messages.List[i])
+			r.Kept++
+		} else {
+			clearMsgstrs(&merged, int(def.Head.PluralForms.N))
+			r.Added++
+		}
+		list = append(list, merged)
+	}
+	for i, m := range def.Messages.List {
+		if matched[i] {
+			continue
+		}
+		if !m.Obsolete {
+			m.Obsolete = true
+			r.Obsoleted++
+		}
+		list = append(list, m)
+	}
+	def.Messages.List = list
+	return r
+}
+
+func isPlural(m *gettext.Message) bool { return len(m.MsgidPlural.Text.Lines) > 0 }
+
+// leadingComments returns the comments of the first directive of m.
+func leadingComments(m *gettext.Message) *gettext.Comments {
+	if len(m.Msgctxt.Text.Lines) > 0 {
+		return &m.Msgctxt.Comments
+	}
+	return &m.Msgid.Comments
+}
+
+// msgstrs returns the msgstr directives of m.
+func msgstrs(m *gettext.Message) []*gettext.Msgstr {
+	return []*gettext.Msgstr{
+		&m.Msgstr, &m.Msgstr0, &m.Msgstr1, &m.Msgstr2,
+		&m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+	}
+}
+
+// mergeMessage sets the translations, translator comments and flags
+// of template message m to those of catalog message d.
+func mergeMessage(m, d *gettext.Message) {
+	dst, src := msgstrs(m), msgstrs(d)
+	for i := range dst {
+		*dst[i] = *src[i]
+		dst[i].Text = src[i].Text.Clone()
+		dst[i].Comments = src[i].Comments.Clone()
+	}
+
+	c := leadingComments(m)
+	var l []gettext.Comment
+	for _, x := range leadingComments(d).Text {
+		if x.Type == gettext.CommentTypeTranslator || x.Type == gettext.CommentTypeFlag {
+			l = append(l, x)
+		}
+	}
+	for _, x := range c.Text {
+		switch x.Type {
+		case gettext.CommentTypeExtracted, gettext.CommentTypeReference:
+			l = append(l, x)
+		case gettext.CommentTypeFlag:
+			if !slices.ContainsFunc(l, func(y gettext.Comment) bool {
+				return y.Type == x.Type && y.Value == x.Value
+			}) {
+				l = append(l, x)
+			}
+		}
+	}
+	// Translator, extracted, reference and flag comments
+	// in the order GNU gettext writes them.
+	slices.SortStableFunc(l, func(a, b gettext.Comment) int {
+		return cmp.Compare(a.Type, b.Type)
+	})
+	c.Text = l
+}
+
+// clearMsgstrs sets the msgstr directives of m to empty translations,
+// one for each of n plural forms if m is plural.
+func clearMsgstrs(m *gettext.Message, n int) {
+	empty := gettext.StringLiterals{Lines: []gettext.StringLiteral{{}}}
+	l := msgstrs(m)
+	for _, s := range l {
+		*s = gettext.Msgstr{}
+	}
+	if !isPlural(m) {
+		m.Msgstr.Text = empty
+		return
+	}
+	if n < 1 {
+		// Plural-Forms defaults to nplurals=2 like in GNU gettext.
+		n = 2
+	}
+	for _, s := range l[1 : min(n, 6)+1] {
+		s.Text = empty
+	}
+}
@@ -0,0 +1,102 @@
+package msgmerge_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/msgmerge"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	const catalog = `msgid ""
+msgstr ""
+"Language: de\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+# Keep it short.
+#. Old description.
+#: old.go:1
+#, fuzzy
+msgid "Save"
+msgstr "Speichern"
+
+#: old.go:2
+msgid "Removed"
+msgstr "Entfernt"
+
+#~ msgctxt "menu"
+#~ msgid "Open"
+#~ msgstr "Öffnen"
+`
+	const template = `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+#: app.go:3
+#, go-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+#. Button label.
+#: app.go:1
+msgid "Save"
+msgstr ""
+
+#: app.go:2
+msgctxt "menu"
+msgid "Open"
+msgstr ""
+`
+	d := gettext.NewDecoder()
+	def, err := d.DecodePOBytes("catalog.de.po", []byte(catalog))
+	require.NoError(t, err)
+	ref, err := d.DecodePOTBytes("catalog.pot", []byte(template))
+	require.NoError(t, err)
+
+	r := msgmerge.Merge(def.File, ref.File)
+	require.Equal(t, msgmerge.Result{Kept: 2, Added: 1, Obsoleted: 1}, r)
+	require.Empty(t, def.Validate())
+
+	out, err := gettext.Encoder{}.EncodePOToString(def)
+	require.NoError(t, err)
+	require.Equal(t, `msgid ""
+msgstr ""
+"Language: de\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+#: app.go:3
+#, go-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+# Keep it short.
+#. Button label.
+#: app.go:1
+#, fuzzy
+msgid "Save"
+msgstr "Speichern"
+
+#: app.go:2
+msgctxt "menu"
+msgid "Open"
+msgstr "Öffnen"
+
+#~ #: old.go:2
+#~ msgid "Removed"
+#~ msgstr "Entfernt"
+`, out)
+}
@@ -0,0 +1,77 @@
+// Package msgseen tracks the generator runs a message was first and last
+// seen in the source code using extracted comments of the catalogs.
+// A run is identified either by its timestamp or by a VCS revision.
+package msgseen
+
+import (
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+)
+
+// Prefixes of the extracted comments carrying the runs.
+const (
+	FirstSeenPrefix = "X-First-Seen: "
+	LastSeenPrefix  = "X-Last-Seen: "
+)
+
+// Seen are the runs a message was first and last seen in.
+type Seen struct{ First, Last string }
+
+// Of returns the runs recorded in the comments of m.
+// Fields are empty if the respective comment is missing.
+func Of(m *gettext.Message) (s Seen) {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeExtracted {
+			continue
+		}
+		if v, ok := strings.CutPrefix(c.Value, FirstSeenPrefix); ok {
+			s.First = v
+		} else if v, ok := strings.CutPrefix(c.Value, LastSeenPrefix); ok {
+			s.Last = v
+		}
+	}
+	return s
+}
+
+// Index returns the runs a message was first seen in by msgctxt.
+// For messages contained in multiple files the first recorded run
+// in the order of files is used.
+func Index(files ...*gettext.File) map[string]string {
+	firstSeen := map[string]string{}
+	for _, f := range files {
+		for i := range f.Messages.List {
+			m := &f.Messages.List[i]
+			h := m.Msgctxt.Text.String()
+			if _, ok := firstSeen[h]; ok {
+				continue
+			}
+			if s := Of(m); s.First != "" {
+				firstSeen[h] = s.First
+			}
+		}
+	}
+	return firstSeen
+}
+
+// SetComments sets the comments of m to s
+// replacing any existing comments.
+func SetComments(m *gettext.Message, s Seen) {
+	set(m, FirstSeenPrefix, s.First)
+	set(m, LastSeenPrefix, s.Last)
+}
+
+func set(m *gettext.Message, prefix, value string) {
+	value = prefix + value
+	for i, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeExtracted &&
+			strings.HasPrefix(c.Value, prefix) {
+			m.Msgctxt.Comments.Text[i].Value = value
+			return
+		}
+	}
+	m.Msgctxt.Comments.Text = append(m.Msgctxt.Comments.Text, gettext.Comment{
+		Type:  gettext.CommentTypeExtracted,
+		Value: value,
+	})
+}
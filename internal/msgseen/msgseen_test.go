@@ -0,0 +1,46 @@
+package msgseen_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/msgseen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetComments(t *testing.T) {
+	var m gettext.Message
+	require.Zero(t, msgseen.Of(&m))
+
+	msgseen.SetComments(&m, msgseen.Seen{First: "a", Last: "a"})
+	msgseen.SetComments(&m, msgseen.Seen{First: "a", Last: "b"})
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeExtracted, Value: "X-First-Seen: a"},
+		{Type: gettext.CommentTypeExtracted, Value: "X-Last-Seen: b"},
+	}, m.Msgctxt.Comments.Text)
+	require.Equal(t, msgseen.Seen{First: "a", Last: "b"}, msgseen.Of(&m))
+}
+
+func TestIndex(t *testing.T) {
+	msg := func(hash, first string) gettext.Message {
+		var m gettext.Message
+		m.Msgctxt.Text = gettext.StringLiterals{
+			Lines: []gettext.StringLiteral{{Value: hash}},
+		}
+		if first != "" {
+			msgseen.SetComments(&m, msgseen.Seen{First: first, Last: first})
+		}
+		return m
+	}
+	source := &gettext.File{}
+	source.Messages.List = []gettext.Message{msg("a", "r1"), msg("b", "")}
+	catalog := &gettext.File{}
+	catalog.Messages.List = []gettext.Message{
+		msg("a", "r0"), msg("b", "r2"), msg("c", ""),
+	}
+
+	require.Equal(t, map[string]string{
+		"a": "r1",
+		"b": "r2",
+	}, msgseen.Index(source, catalog))
+}
@@ -0,0 +1,99 @@
+// Package ordinal tags the messages of Reader.PluralOrdinal calls with the
+// CLDR ordinal plural categories they're used for, such as "1st" and "21st"
+// for English category one. Each category of a call is extracted as a plural
+// message of its own, selected by the cardinal quantity.
+// Categories are stored as `#, ordinal:<category>` flags in catalogs.
+// Messages without categories aren't ordinal.
+package ordinal
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/cldr"
+)
+
+// FlagPrefix is the prefix of catalog flags carrying an ordinal category.
+const FlagPrefix = "ordinal:"
+
+// Of returns the ordinal categories of m in the order of their flags.
+// Flags of unknown categories are ignored.
+func Of(m *gettext.Message) (categories []cldr.CLDRPluralForm) {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeFlag {
+			continue
+		}
+		for f := range strings.SplitSeq(c.Value, ",") {
+			s, ok := strings.CutPrefix(strings.TrimSpace(f), FlagPrefix)
+			if !ok {
+				continue
+			}
+			if category, err := cldr.ParsePluralForm(s); err == nil {
+				categories = append(categories, category)
+			}
+		}
+	}
+	return categories
+}
+
+// Set replaces the ordinal category flags of m with categories
+// preserving all other flags.
+func Set(m *gettext.Message, categories []cldr.CLDRPluralForm) {
+	l := m.Msgctxt.Comments.Text[:0]
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeFlag {
+			var flags []string
+			for f := range strings.SplitSeq(c.Value, ",") {
+				if f = strings.TrimSpace(f); !strings.HasPrefix(f, FlagPrefix) {
+					flags = append(flags, f)
+				}
+			}
+			if len(flags) == 0 {
+				continue // Remove comments containing only ordinal categories.
+			}
+			if len(flags) <= strings.Count(c.Value, ",") {
+				// Rewrite only comments containing ordinal categories,
+				// keeping other flags verbatim.
+				c.Value = strings.Join(flags, ", ")
+			}
+		}
+		l = append(l, c)
+	}
+	if len(categories) > 0 {
+		flags := make([]string, len(categories))
+		for i, c := range categories {
+			flags[i] = FlagPrefix + strings.ToLower(c.String())
+		}
+		l = append(l, gettext.Comment{
+			Type:  gettext.CommentTypeFlag,
+			Value: strings.Join(flags, ", "),
+		})
+	}
+	m.Msgctxt.Comments.Text = l
+}
+
+// Merge returns the categories of a and b sorted in CLDR order
+// and deduplicated.
+func Merge(a, b []cldr.CLDRPluralForm) []cldr.CLDRPluralForm {
+	m := slices.Concat(a, b)
+	slices.Sort(m)
+	return slices.Compact(m)
+}
+
+// Used returns true if the catalogs of a locale with the plural rules p
+// need a message used for the ordinal categories, which is the case
+// if the message isn't ordinal, is used for category Other that
+// categories unused by the source code fall back to, or is used for
+// any ordinal category of p.
+func Used(categories []cldr.CLDRPluralForm, p cldr.PluralForms) bool {
+	if len(categories) == 0 || slices.Contains(categories, cldr.CLDRPluralFormOther) {
+		return true
+	}
+	for _, c := range categories {
+		if slices.Contains(p.OrdinalForms, c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package ordinal_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/ordinal"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestSet(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "go-format, ordinal:other"},
+	}
+	require.Equal(t, []cldr.CLDRPluralForm{cldr.CLDRPluralFormOther}, ordinal.Of(m))
+
+	ordinal.Set(m, []cldr.CLDRPluralForm{cldr.CLDRPluralFormOne, cldr.CLDRPluralFormFew})
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "go-format"},
+		{Type: gettext.CommentTypeFlag, Value: "ordinal:one, ordinal:few"},
+	}, m.Msgctxt.Comments.Text)
+	require.Equal(t, []cldr.CLDRPluralForm{
+		cldr.CLDRPluralFormOne, cldr.CLDRPluralFormFew,
+	}, ordinal.Of(m))
+
+	ordinal.Set(m, nil)
+	require.Nil(t, ordinal.Of(m))
+	require.Len(t, m.Msgctxt.Comments.Text, 2)
+}
+
+func TestMerge(t *testing.T) {
+	require.Equal(t, []cldr.CLDRPluralForm{
+		cldr.CLDRPluralFormOne, cldr.CLDRPluralFormFew, cldr.CLDRPluralFormOther,
+	}, ordinal.Merge(
+		[]cldr.CLDRPluralForm{cldr.CLDRPluralFormOther, cldr.CLDRPluralFormOne},
+		[]cldr.CLDRPluralForm{cldr.CLDRPluralFormFew, cldr.CLDRPluralFormOne},
+	))
+}
+
+func TestUsed(t *testing.T) {
+	english, _ := cldr.ByTag(language.English)
+	german, _ := cldr.ByTag(language.German)
+	f := func(expect bool, p cldr.PluralForms, categories ...cldr.CLDRPluralForm) {
+		t.Helper()
+		require.Equal(t, expect, ordinal.Used(categories, p))
+	}
+	f(true, german)
+	f(true, german, cldr.CLDRPluralFormOther)
+	f(true, german, cldr.CLDRPluralFormOne, cldr.CLDRPluralFormOther)
+	f(false, german, cldr.CLDRPluralFormOne)
+	f(true, english, cldr.CLDRPluralFormOne)
+	f(false, english, cldr.CLDRPluralFormMany)
+}
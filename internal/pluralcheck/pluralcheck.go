@@ -0,0 +1,187 @@
+// Package pluralcheck validates the templates of plural messages
+// against the CLDR plural rules of a locale. It's shared by the source code
+// extraction and localize.ValidateForms for runtime-constructed messages.
+package pluralcheck
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/fmtplaceholder"
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrMissingPluralForm          = errors.New("missing required plural form")
+	ErrUnsupportedPluralForm      = errors.New("plural form not supported by locale")
+	ErrMissingQuantityPlaceholder = errors.New(
+		"missing quantity placeholder \"%d\" in template",
+	)
+	ErrTooManyQuantityPlaceholders = errors.New(
+		"plural template strings are expected to " +
+			`have only one quantity placeholder "%d"`,
+	)
+	ErrWrongPlaceholderVerb = errors.New(
+		"wrong placeholder verb, use a numeric placeholder",
+	)
+	ErrRangePlaceholders = errors.New(
+		"plural range template strings are expected to have two quantity " +
+			`placeholders "%d" for the start and the end of the range`,
+	)
+	ErrOrdinalPlaceholders = errors.New(
+		"ordinal plural template strings are expected to have two quantity " +
+			`placeholders "%d" for the ordinal and the cardinal quantity`,
+	)
+)
+
+// Forms are the templates of a plural message by CLDR plural form.
+type Forms struct{ Zero, One, Two, Few, Many, Other string }
+
+// Check returns the issues of the forms f of a plural message in locale
+// with the plural rules p and the non-empty forms p doesn't support.
+// Every non-empty form is checked by Template.
+func Check(
+	locale language.Tag, p cldr.PluralForms, f Forms,
+) (errs []error, unsupported []cldr.CLDRPluralForm) {
+	return check(locale, p, p.Cardinal, f, Template)
+}
+
+// CheckRange is like Check for plural range messages, which only require
+// the forms selected for ranges of quantities by the plural range rules of p.
+// Every non-empty form is checked by RangeTemplate.
+func CheckRange(
+	locale language.Tag, p cldr.PluralForms, f Forms,
+) (errs []error, unsupported []cldr.CLDRPluralForm) {
+	return check(locale, p, p.Range, f, RangeTemplate)
+}
+
+// CheckOrdinal is like Check for the forms of a single ordinal category
+// of an ordinal plural message. Every non-empty form is checked
+// by OrdinalTemplate.
+func CheckOrdinal(
+	locale language.Tag, p cldr.PluralForms, f Forms,
+) (errs []error, unsupported []cldr.CLDRPluralForm) {
+	return check(locale, p, p.Cardinal, f, OrdinalTemplate)
+}
+
+// CheckOrdinalCategories returns the issues of the ordinal categories
+// defined by an ordinal plural message in locale with the plural rules p,
+// which must define forms for every ordinal category of p.
+// Category Other is checked by CheckOrdinal.
+func CheckOrdinalCategories(
+	locale language.Tag, p cldr.PluralForms, defined cldr.CLDRForms,
+) (errs []error) {
+	for _, c := range [...]struct {
+		form              cldr.CLDRPluralForm
+		required, defined bool
+	}{
+		{cldr.CLDRPluralFormZero, p.Ordinal.Zero, defined.Zero},
+		{cldr.CLDRPluralFormOne, p.Ordinal.One, defined.One},
+		{cldr.CLDRPluralFormTwo, p.Ordinal.Two, defined.Two},
+		{cldr.CLDRPluralFormFew, p.Ordinal.Few, defined.Few},
+		{cldr.CLDRPluralFormMany, p.Ordinal.Many, defined.Many},
+	} {
+		if c.required && !c.defined {
+			errs = append(errs, fmt.Errorf(
+				"%w: locale %q requires forms for ordinal category %s",
+				ErrMissingPluralForm, locale.String(), c.form,
+			))
+		}
+	}
+	return errs
+}
+
+func check(
+	locale language.Tag, p cldr.PluralForms, required cldr.CLDRForms, f Forms,
+	template func(string) []error,
+) (errs []error, unsupported []cldr.CLDRPluralForm) {
+	if f.Other == "" {
+		errs = append(errs, fmt.Errorf(
+			"%w: all languages require form Other", ErrMissingPluralForm,
+		))
+	}
+	errs = append(errs, template(f.Other)...)
+
+	for _, c := range [...]struct {
+		form                cldr.CLDRPluralForm
+		required, supported bool
+		text                string
+	}{
+		{cldr.CLDRPluralFormZero, required.Zero, p.Cardinal.Zero, f.Zero},
+		{cldr.CLDRPluralFormOne, required.One, p.Cardinal.One, f.One},
+		{cldr.CLDRPluralFormTwo, required.Two, p.Cardinal.Two, f.Two},
+		{cldr.CLDRPluralFormFew, required.Few, p.Cardinal.Few, f.Few},
+		{cldr.CLDRPluralFormMany, required.Many, p.Cardinal.Many, f.Many},
+	} {
+		if c.required && c.text == "" {
+			errs = append(errs, fmt.Errorf(
+				"%w: locale %q requires plural form %s",
+				ErrMissingPluralForm, locale.String(), c.form,
+			))
+		}
+		if c.text == "" {
+			continue
+		}
+		if !c.supported {
+			unsupported = append(unsupported, c.form)
+		}
+		errs = append(errs, template(c.text)...)
+	}
+	return errs, unsupported
+}
+
+// Template returns the issues of plural template s, which must contain
+// exactly one numeric placeholder for the quantity.
+func Template(s string) []error {
+	placeholders := fmtplaceholder.Extract(s)
+	if len(placeholders) < 1 {
+		return []error{ErrMissingQuantityPlaceholder}
+	}
+	var errs []error
+	if len(placeholders) > 1 {
+		errs = append(errs, fmt.Errorf(
+			"%w: found %d", ErrTooManyQuantityPlaceholders, len(placeholders),
+		))
+	}
+	if !fmtplaceholder.Numeric(placeholders[0]) {
+		errs = append(errs, fmt.Errorf(
+			"%w: verb found: %q", ErrWrongPlaceholderVerb, placeholders[0],
+		))
+	}
+	return errs
+}
+
+// RangeTemplate returns the issues of plural range template s, which must
+// contain exactly two numeric placeholders for the start and the end
+// of the range.
+func RangeTemplate(s string) []error {
+	return twoQuantities(s, ErrRangePlaceholders)
+}
+
+// OrdinalTemplate returns the issues of ordinal plural template s, which must
+// contain exactly two numeric placeholders for the ordinal and the cardinal
+// quantity.
+func OrdinalTemplate(s string) []error {
+	return twoQuantities(s, ErrOrdinalPlaceholders)
+}
+
+// twoQuantities returns the issues of template s, which must contain
+// exactly two numeric placeholders, reporting errCount otherwise.
+func twoQuantities(s string, errCount error) []error {
+	placeholders := fmtplaceholder.Extract(s)
+	if len(placeholders) != 2 {
+		return []error{fmt.Errorf(
+			"%w: found %d", errCount, len(placeholders),
+		)}
+	}
+	var errs []error
+	for _, p := range placeholders {
+		if !fmtplaceholder.Numeric(p) {
+			errs = append(errs, fmt.Errorf(
+				"%w: verb found: %q", ErrWrongPlaceholderVerb, p,
+			))
+		}
+	}
+	return errs
+}
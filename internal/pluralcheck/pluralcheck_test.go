@@ -0,0 +1,132 @@
+package pluralcheck_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/pluralcheck"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	en, ok := cldr.ByTagOrBase(language.English)
+	require.True(t, ok)
+
+	errs, unsupported := pluralcheck.Check(language.English, en, pluralcheck.Forms{
+		One: "%d apple", Other: "%d apples",
+	})
+	require.Empty(t, errs)
+	require.Empty(t, unsupported)
+
+	errs, unsupported = pluralcheck.Check(language.English, en, pluralcheck.Forms{
+		Zero: "no apples %d", Other: "apples",
+	})
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, errs[0], pluralcheck.ErrMissingQuantityPlaceholder)
+	require.ErrorIs(t, errs[1], pluralcheck.ErrMissingPluralForm)
+	require.Equal(t, []cldr.CLDRPluralForm{cldr.CLDRPluralFormZero}, unsupported)
+}
+
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+	require.Empty(t, pluralcheck.Template("%d apples"))
+	require.Empty(t, pluralcheck.Template("%.2f apples"))
+
+	errs := pluralcheck.Template("apples")
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], pluralcheck.ErrMissingQuantityPlaceholder)
+
+	errs = pluralcheck.Template("%s apples %d")
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, errs[0], pluralcheck.ErrTooManyQuantityPlaceholders)
+	require.ErrorIs(t, errs[1], pluralcheck.ErrWrongPlaceholderVerb)
+}
+
+func TestCheckRange(t *testing.T) {
+	t.Parallel()
+	en, ok := cldr.ByTagOrBase(language.English)
+	require.True(t, ok)
+
+	// English ranges always use form Other.
+	errs, unsupported := pluralcheck.CheckRange(language.English, en, pluralcheck.Forms{
+		Other: "%d–%d apples",
+	})
+	require.Empty(t, errs)
+	require.Empty(t, unsupported)
+
+	ru, ok := cldr.ByTagOrBase(language.Russian)
+	require.True(t, ok)
+	errs, unsupported = pluralcheck.CheckRange(language.Russian, ru, pluralcheck.Forms{
+		Zero: "%d–%d", One: "%d–%d яблоко", Other: "%d яблок",
+	})
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, errs[0], pluralcheck.ErrRangePlaceholders)
+	require.ErrorIs(t, errs[1], pluralcheck.ErrMissingPluralForm)
+	require.ErrorContains(t, errs[1], "Few")
+	require.Equal(t, []cldr.CLDRPluralForm{cldr.CLDRPluralFormZero}, unsupported)
+}
+
+func TestRangeTemplate(t *testing.T) {
+	t.Parallel()
+	require.Empty(t, pluralcheck.RangeTemplate("%d–%d apples"))
+
+	errs := pluralcheck.RangeTemplate("%d apples")
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], pluralcheck.ErrRangePlaceholders)
+
+	errs = pluralcheck.RangeTemplate("%d–%s apples")
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], pluralcheck.ErrWrongPlaceholderVerb)
+}
+
+func TestCheckOrdinal(t *testing.T) {
+	t.Parallel()
+	en, ok := cldr.ByTagOrBase(language.English)
+	require.True(t, ok)
+
+	errs, unsupported := pluralcheck.CheckOrdinal(language.English, en, pluralcheck.Forms{
+		One: "the %dnd of %d apple", Other: "the %dnd of %d apples",
+	})
+	require.Empty(t, errs)
+	require.Empty(t, unsupported)
+
+	errs, _ = pluralcheck.CheckOrdinal(language.English, en, pluralcheck.Forms{
+		Other: "%d apples",
+	})
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, errs[0], pluralcheck.ErrOrdinalPlaceholders)
+	require.ErrorIs(t, errs[1], pluralcheck.ErrMissingPluralForm)
+	require.ErrorContains(t, errs[1], "One")
+}
+
+func TestCheckOrdinalCategories(t *testing.T) {
+	t.Parallel()
+	en, ok := cldr.ByTagOrBase(language.English)
+	require.True(t, ok)
+
+	require.Empty(t, pluralcheck.CheckOrdinalCategories(language.English, en,
+		cldr.CLDRForms{One: true, Two: true, Few: true, Other: true}))
+
+	errs := pluralcheck.CheckOrdinalCategories(language.English, en,
+		cldr.CLDRForms{One: true, Many: true, Other: true})
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, errs[0], pluralcheck.ErrMissingPluralForm)
+	require.ErrorContains(t, errs[0], "ordinal category Two")
+	require.ErrorContains(t, errs[1], "ordinal category Few")
+
+	de, ok := cldr.ByTagOrBase(language.German)
+	require.True(t, ok)
+	require.Empty(t, pluralcheck.CheckOrdinalCategories(language.German, de,
+		cldr.CLDRForms{Other: true}))
+}
+
+func TestOrdinalTemplate(t *testing.T) {
+	t.Parallel()
+	require.Empty(t, pluralcheck.OrdinalTemplate("the %dth of %d apples"))
+
+	errs := pluralcheck.OrdinalTemplate("%d apples")
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], pluralcheck.ErrOrdinalPlaceholders)
+}
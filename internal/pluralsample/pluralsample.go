@@ -0,0 +1,109 @@
+// Package pluralsample annotates plural messages of translation catalogs
+// with extracted comments naming the CLDR plural category of every msgstr
+// index and listing its sample quantities according to the plural rules
+// of the catalog locale, giving translators concrete guidance on what
+// each plural form is used for.
+package pluralsample
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/cldr"
+)
+
+// CommentPrefix is the prefix of the extracted comments carrying the samples.
+const CommentPrefix = "X-Plural-Sample: "
+
+// MaxSamples is the maximum number of sample quantities listed per form.
+const MaxSamples = 8
+
+// Comments returns the sample comments of the plural forms p, one per
+// msgstr index, like:
+//
+//	msgstr[0] (One): 1, 21, 31, 41, 51, 61, 71, 81, …
+//
+// Samples of forms merged by an override are listed under the form
+// they're merged into.
+func Comments(p cldr.PluralForms) []string {
+	l := make([]string, len(p.CardinalForms))
+	for i, form := range p.CardinalForms {
+		var samples []int
+		for f := cldr.CLDRPluralFormZero; f <= cldr.CLDRPluralFormOther; f++ {
+			if p.Resolve(f) == form {
+				samples = append(samples, p.Examples[f]...)
+			}
+		}
+		slices.Sort(samples)
+		samples = slices.Compact(samples)
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "msgstr[%d] (%s):", i, form)
+		for j, n := range samples {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			if j == MaxSamples {
+				b.WriteString(" …")
+				break
+			}
+			b.WriteByte(' ')
+			b.WriteString(strconv.Itoa(n))
+		}
+		l[i] = b.String()
+	}
+	return l
+}
+
+// categoriesPrefix is the prefix of the category comment.
+const categoriesPrefix = "msgstr[0]="
+
+// Categories returns the comment naming the CLDR plural category
+// of every msgstr index of the plural forms p, like:
+//
+//	msgstr[0]=one, msgstr[1]=few, msgstr[2]=other
+func Categories(p cldr.PluralForms) string {
+	var b strings.Builder
+	for i, form := range p.CardinalForms {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "msgstr[%d]=%s", i, strings.ToLower(form.String()))
+	}
+	return b.String()
+}
+
+// SetCategories sets the category comment of m to text
+// replacing any existing category comment.
+func SetCategories(m *gettext.Message, text string) {
+	for i, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeExtracted &&
+			strings.HasPrefix(c.Value, categoriesPrefix) {
+			m.Msgctxt.Comments.Text[i].Value = text
+			return
+		}
+	}
+	m.Msgctxt.Comments.Text = append(m.Msgctxt.Comments.Text, gettext.Comment{
+		Type:  gettext.CommentTypeExtracted,
+		Value: text,
+	})
+}
+
+// Set sets the sample comments of m to texts replacing
+// any existing sample comments.
+func Set(m *gettext.Message, texts []string) {
+	m.Msgctxt.Comments.Text = slices.DeleteFunc(m.Msgctxt.Comments.Text,
+		func(c gettext.Comment) bool {
+			return c.Type == gettext.CommentTypeExtracted &&
+				strings.HasPrefix(c.Value, CommentPrefix)
+		})
+	for _, t := range texts {
+		m.Msgctxt.Comments.Text = append(m.Msgctxt.Comments.Text, gettext.Comment{
+			Type:  gettext.CommentTypeExtracted,
+			Value: CommentPrefix + t,
+		})
+	}
+}
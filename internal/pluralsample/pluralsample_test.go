@@ -0,0 +1,79 @@
+package pluralsample_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/pluralsample"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestComments(t *testing.T) {
+	en, ok := cldr.ByTagOrBase(language.English)
+	require.True(t, ok)
+	require.Equal(t, []string{
+		"msgstr[0] (One): 1",
+		"msgstr[1] (Other): 0, 2, 3, 4, 5, 6, 7, 8, …",
+	}, pluralsample.Comments(en))
+
+	ja, ok := cldr.ByTagOrBase(language.Japanese)
+	require.True(t, ok)
+	require.Len(t, pluralsample.Comments(ja), 1)
+}
+
+func TestCommentsMerged(t *testing.T) {
+	t.Cleanup(cldr.ResetOverrides)
+	require.NoError(t, cldr.SetOverride(cldr.Override{
+		Locale: language.Russian,
+		Merge: map[cldr.CLDRPluralForm]cldr.CLDRPluralForm{
+			cldr.CLDRPluralFormFew: cldr.CLDRPluralFormOther,
+		},
+	}))
+	ru, ok := cldr.ByTagOrBase(language.Russian)
+	require.True(t, ok)
+	c := pluralsample.Comments(ru)
+	require.Len(t, c, len(ru.CardinalForms))
+	require.Contains(t, c[len(c)-1], " 2,", "samples of Few listed under Other")
+}
+
+func TestSet(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeExtracted, Value: "Files."},
+		{Type: gettext.CommentTypeExtracted, Value: "X-Plural-Sample: old"},
+	}
+	pluralsample.Set(m, []string{"a", "b"})
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeExtracted, Value: "Files."},
+		{Type: gettext.CommentTypeExtracted, Value: "X-Plural-Sample: a"},
+		{Type: gettext.CommentTypeExtracted, Value: "X-Plural-Sample: b"},
+	}, m.Msgctxt.Comments.Text)
+}
+
+func TestCategories(t *testing.T) {
+	en, ok := cldr.ByTagOrBase(language.English)
+	require.True(t, ok)
+	require.Equal(t, "msgstr[0]=one, msgstr[1]=other", pluralsample.Categories(en))
+
+	ja, ok := cldr.ByTagOrBase(language.Japanese)
+	require.True(t, ok)
+	require.Equal(t, "msgstr[0]=other", pluralsample.Categories(ja))
+}
+
+func TestSetCategories(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeExtracted, Value: "Files."},
+	}
+	pluralsample.SetCategories(m, "msgstr[0]=one, msgstr[1]=other")
+	pluralsample.SetCategories(m, "msgstr[0]=one, msgstr[1]=few, msgstr[2]=other")
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeExtracted, Value: "Files."},
+		{
+			Type:  gettext.CommentTypeExtracted,
+			Value: "msgstr[0]=one, msgstr[1]=few, msgstr[2]=other",
+		},
+	}, m.Msgctxt.Comments.Text)
+}
@@ -0,0 +1,108 @@
+// Package protect marks substrings of messages such as URLs, product names
+// and email addresses as non-translatable using the
+// `// do-not-translate: Acme Cloud` source code directive.
+// Protected substrings are stored as `#. Do not translate: <text>`
+// extracted comments in catalogs.
+package protect
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+)
+
+const (
+	// DirectivePrefix is the prefix of the source code comment line
+	// protecting a substring of a message. Every line protects one substring.
+	DirectivePrefix = "do-not-translate:"
+
+	// CommentPrefix is the prefix of extracted catalog comments
+	// carrying a protected substring.
+	CommentPrefix = "Do not translate: "
+)
+
+var (
+	ErrEmpty     = errors.New("empty protected text")
+	ErrNotInText = errors.New("protected text not found in message")
+	ErrMissing   = errors.New("protected text missing in translation")
+)
+
+// ParseDirective parses a comment line like "do-not-translate: Acme Cloud"
+// and returns the protected text trimmed of surrounding spaces.
+// ok is false if line isn't a do-not-translate directive.
+func ParseDirective(line string) (text string, ok bool, err error) {
+	text, ok = strings.CutPrefix(line, DirectivePrefix)
+	if !ok {
+		return "", false, nil
+	}
+	if text = strings.TrimSpace(text); text == "" {
+		return "", true, ErrEmpty
+	}
+	return text, true, nil
+}
+
+// Of returns the protected texts of m in the order of their comments.
+func Of(m *gettext.Message) (texts []string) {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeExtracted {
+			continue
+		}
+		if t, ok := strings.CutPrefix(c.Value, CommentPrefix); ok {
+			texts = append(texts, t)
+		}
+	}
+	return texts
+}
+
+// Set replaces the protected text comments of m with texts
+// preserving all other comments.
+func Set(m *gettext.Message, texts []string) {
+	m.Msgctxt.Comments.Text = slices.DeleteFunc(m.Msgctxt.Comments.Text,
+		func(c gettext.Comment) bool {
+			return c.Type == gettext.CommentTypeExtracted &&
+				strings.HasPrefix(c.Value, CommentPrefix)
+		})
+	for _, t := range texts {
+		m.Msgctxt.Comments.Text = append(m.Msgctxt.Comments.Text, gettext.Comment{
+			Type:  gettext.CommentTypeExtracted,
+			Value: CommentPrefix + t,
+		})
+	}
+}
+
+// CheckFile returns an ErrMissing issue for every protected text of
+// a translated active message of f that its translation doesn't contain
+// verbatim. Every non-empty plural form must contain all protected texts.
+func CheckFile(f *gettext.File) (issues []gettext.Error) {
+	for i := range f.Messages.List {
+		m := &f.Messages.List[i]
+		if m.Obsolete {
+			continue
+		}
+		texts := Of(m)
+		if len(texts) == 0 {
+			continue
+		}
+		for _, s := range [...]*gettext.Msgstr{
+			&m.Msgstr, &m.Msgstr0, &m.Msgstr1, &m.Msgstr2,
+			&m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+		} {
+			translation := s.Text.String()
+			if translation == "" {
+				continue
+			}
+			for _, t := range texts {
+				if !strings.Contains(translation, t) {
+					issues = append(issues, gettext.Error{
+						Pos: s.Position,
+						Err: fmt.Errorf("%w: %q", ErrMissing, t),
+					})
+				}
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,97 @@
+package protect_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/protect"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirective(t *testing.T) {
+	f := func(t *testing.T, line, expect string, expectOK bool) {
+		t.Helper()
+		text, ok, err := protect.ParseDirective(line)
+		require.NoError(t, err)
+		require.Equal(t, expectOK, ok)
+		require.Equal(t, expect, text)
+	}
+	f(t, "do-not-translate: Acme Cloud", "Acme Cloud", true)
+	f(t, "do-not-translate:support@acme.com", "support@acme.com", true)
+	f(t, "do-not-translate: a, b ", "a, b", true)
+	f(t, "Do not translate: Acme", "", false)
+	f(t, "Greeting.", "", false)
+
+	_, ok, err := protect.ParseDirective("do-not-translate:  ")
+	require.True(t, ok)
+	require.ErrorIs(t, err, protect.ErrEmpty)
+}
+
+func TestSet(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeExtracted, Value: "Greeting."},
+		{Type: gettext.CommentTypeExtracted, Value: "Do not translate: Old"},
+	}
+	require.Equal(t, []string{"Old"}, protect.Of(m))
+
+	protect.Set(m, []string{"Acme", "https://acme.com"})
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeExtracted, Value: "Greeting."},
+		{Type: gettext.CommentTypeExtracted, Value: "Do not translate: Acme"},
+		{Type: gettext.CommentTypeExtracted, Value: "Do not translate: https://acme.com"},
+	}, m.Msgctxt.Comments.Text)
+	require.Equal(t, []string{"Acme", "https://acme.com"}, protect.Of(m))
+
+	protect.Set(m, nil)
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeExtracted, Value: "Greeting."},
+	}, m.Msgctxt.Comments.Text)
+	require.Nil(t, protect.Of(m))
+}
+
+func TestCheckFile(t *testing.T) {
+	src := `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+#. Do not translate: Acme Cloud
+msgctxt "a"
+msgid "Welcome to Acme Cloud"
+msgstr "Willkommen bei Acme Wolke"
+
+#. Do not translate: Acme
+msgctxt "b"
+msgid "Untranslated Acme"
+msgstr ""
+
+#. Do not translate: Acme
+msgctxt "c"
+msgid "%d Acme seat"
+msgid_plural "%d Acme seats"
+msgstr[0] "%d Acme-Platz"
+msgstr[1] "%d Plätze"
+
+#. Do not translate: Acme
+#~ msgctxt "d"
+#~ msgid "Obsolete Acme"
+#~ msgstr "Veraltet"
+`
+	po, err := gettext.NewDecoder().DecodePOBytes("catalog.de.po", []byte(src))
+	require.NoError(t, err)
+
+	var actual []string
+	for _, e := range protect.CheckFile(po.File) {
+		require.ErrorIs(t, e, protect.ErrMissing)
+		actual = append(actual, e.Error())
+	}
+	require.Equal(t, []string{
+		`catalog.de.po:10:1: protected text missing in translation: "Acme Cloud"`,
+		`catalog.de.po:22:1: protected text missing in translation: "Acme"`,
+	}, actual)
+}
@@ -0,0 +1,178 @@
+// Package qareport renders the results of the quality checks
+// of the generate command as a standalone HTML report.
+package qareport
+
+import (
+	"cmp"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/gendocs"
+	"github.com/romshark/localize/internal/markup"
+	"github.com/romshark/localize/internal/protect"
+)
+
+//go:embed report.html.gotmpl
+var tmplHTMLText string
+
+var tmplHTML = template.Must(template.New("report").Parse(tmplHTMLText))
+
+// Issue codes of catalog issues.
+const (
+	CodeUntranslated   = "untranslated"
+	CodeCatalogInvalid = "catalog-invalid"
+	CodeMarkup         = "markup"
+	CodeProtected      = "protected"
+)
+
+// Report is the data model of the QA report.
+type Report struct {
+	SourceLocale string
+	Locales      []Locale
+	Packages     []Package
+	Issues       []Issue
+}
+
+// Locale summarizes the issues of a translation catalog.
+type Locale struct {
+	gendocs.Locale
+	Issues int
+}
+
+// Package summarizes the issues of a Go package or bundle directory.
+type Package struct {
+	Path     string
+	Errors   int
+	Warnings int
+}
+
+// Issue is a single finding of the quality checks.
+type Issue struct {
+	// Locale is empty for source code issues.
+	Locale string
+
+	// Package is the directory of the file the issue was found in.
+	Package string
+
+	// Position is either "file:line:col" or "file:line" for references.
+	Position string
+
+	Severity codeparser.Severity
+	Code     string
+	Message  string
+}
+
+// Make creates the report from the source code issues srcErrs,
+// the issues of the translation catalogs by locale and site.
+// site may be nil if there is no source catalog yet,
+// in which case untranslated messages aren't reported.
+func Make(
+	site *gendocs.Site,
+	srcErrs []codeparser.ErrorSrc,
+	catalogIssues map[string][]gettext.Error,
+) *Report {
+	r := &Report{}
+	for _, e := range srcErrs {
+		r.Issues = append(r.Issues, Issue{
+			Package:  path.Dir(e.Filename),
+			Position: fmt.Sprintf("%s:%d:%d", e.Filename, e.Line, e.Column),
+			Severity: e.Severity,
+			Code:     e.Code(),
+			Message:  e.Err.Error(),
+		})
+	}
+	for locale, issues := range catalogIssues {
+		for _, e := range issues {
+			msg := e.Error()
+			if e.Err != nil && e.Expected == "" {
+				msg = e.Err.Error()
+			}
+			code := CodeCatalogInvalid
+			switch {
+			case errors.Is(e.Err, markup.ErrMarkup):
+				code = CodeMarkup
+			case errors.Is(e.Err, protect.ErrMissing):
+				code = CodeProtected
+			}
+			r.Issues = append(r.Issues, Issue{
+				Locale:  locale,
+				Package: path.Dir(e.Pos.Filename),
+				Position: fmt.Sprintf("%s:%d:%d",
+					e.Pos.Filename, e.Pos.Line, e.Pos.Column),
+				Severity: codeparser.SeverityWarning,
+				Code:     code,
+				Message:  msg,
+			})
+		}
+	}
+	if site != nil {
+		r.SourceLocale = site.SourceLocale
+		for _, m := range site.Messages {
+			var ref string
+			if len(m.References) > 0 {
+				ref, _, _ = strings.Cut(m.References[0], " ")
+			}
+			file, _, _ := strings.Cut(ref, ":")
+			for _, t := range m.Translations {
+				if t.Translated {
+					continue
+				}
+				r.Issues = append(r.Issues, Issue{
+					Locale:   t.Locale,
+					Package:  path.Dir(file),
+					Position: ref,
+					Severity: codeparser.SeverityWarning,
+					Code:     CodeUntranslated,
+					Message:  m.Source[len(m.Source)-1].Text,
+				})
+			}
+		}
+		for _, l := range site.Locales {
+			r.Locales = append(r.Locales, Locale{Locale: l})
+		}
+	}
+	slices.SortStableFunc(r.Issues, func(a, b Issue) int {
+		return cmp.Or(
+			strings.Compare(a.Locale, b.Locale),
+			strings.Compare(a.Package, b.Package),
+			strings.Compare(a.Position, b.Position),
+		)
+	})
+
+	byPackage := map[string]*Package{}
+	for _, is := range r.Issues {
+		for i := range r.Locales {
+			if r.Locales[i].Tag == is.Locale {
+				r.Locales[i].Issues++
+			}
+		}
+		p, ok := byPackage[is.Package]
+		if !ok {
+			p = &Package{Path: is.Package}
+			byPackage[is.Package] = p
+		}
+		if is.Severity == codeparser.SeverityError {
+			p.Errors++
+		} else {
+			p.Warnings++
+		}
+	}
+	for _, p := range byPackage {
+		r.Packages = append(r.Packages, *p)
+	}
+	slices.SortFunc(r.Packages, func(a, b Package) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return r
+}
+
+// WriteHTML writes r to w as a standalone HTML document.
+func (r *Report) WriteHTML(w io.Writer) error { return tmplHTML.Execute(w, r) }
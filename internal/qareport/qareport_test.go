@@ -0,0 +1,141 @@
+package qareport_test
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/coverage"
+	"github.com/romshark/localize/internal/gendocs"
+	"github.com/romshark/localize/internal/markup"
+	"github.com/romshark/localize/internal/qareport"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMake(t *testing.T) {
+	site := &gendocs.Site{
+		SourceLocale: "en",
+		Locales: []gendocs.Locale{
+			{Tag: "de", Coverage: coverage.Coverage{Total: 2, Translated: 1}},
+		},
+		Messages: []gendocs.Message{
+			{
+				Hash:         "a",
+				Source:       []gendocs.Form{{Text: "Hello"}},
+				References:   []string{"app/main.go:12"},
+				Translations: []gendocs.Translation{{Locale: "de", Translated: true}},
+			},
+			{
+				Hash: "b",
+				Source: []gendocs.Form{
+					{Name: "One", Text: "%d item"}, {Name: "Other", Text: "%d items"},
+				},
+				References:   []string{"app/list/list.go:4 app/list/list.go:9"},
+				Translations: []gendocs.Translation{{Locale: "de"}},
+			},
+		},
+	}
+	srcErrs := []codeparser.ErrorSrc{
+		{
+			Position: pos("app/main.go", 3, 2),
+			Err:      codeparser.ErrSourceTextEmpty,
+		},
+		{
+			Position: pos("app/list/list.go", 7, 5),
+			Err:      codeparser.ErrDescriptionMissing,
+			Severity: codeparser.SeverityWarning,
+		},
+	}
+	catalogIssues := map[string][]gettext.Error{
+		"de": {
+			{
+				Pos: gettext.Position{Filename: "localizebundle/catalog.de.po", Line: 1, Column: 1},
+				Err: gettext.ErrMissingHeader,
+			},
+			{
+				Pos: gettext.Position{Filename: "localizebundle/catalog.de.po", Line: 9, Column: 1},
+				Err: markup.Check("<b>Hello</b>", "Hallo")[0],
+			},
+		},
+	}
+
+	r := qareport.Make(site, srcErrs, catalogIssues)
+	require.Equal(t, "en", r.SourceLocale)
+	require.Equal(t, []qareport.Locale{{Locale: site.Locales[0], Issues: 3}}, r.Locales)
+	require.Equal(t, []qareport.Package{
+		{Path: "app", Errors: 1},
+		{Path: "app/list", Warnings: 2},
+		{Path: "localizebundle", Warnings: 2},
+	}, r.Packages)
+	require.Equal(t, []qareport.Issue{
+		{
+			Package:  "app",
+			Position: "app/main.go:3:2",
+			Severity: codeparser.SeverityError,
+			Code:     "text-empty",
+			Message:  codeparser.ErrSourceTextEmpty.Error(),
+		},
+		{
+			Package:  "app/list",
+			Position: "app/list/list.go:7:5",
+			Severity: codeparser.SeverityWarning,
+			Code:     "description-missing",
+			Message:  codeparser.ErrDescriptionMissing.Error(),
+		},
+		{
+			Locale:   "de",
+			Package:  "app/list",
+			Position: "app/list/list.go:4",
+			Severity: codeparser.SeverityWarning,
+			Code:     qareport.CodeUntranslated,
+			Message:  "%d items",
+		},
+		{
+			Locale:   "de",
+			Package:  "localizebundle",
+			Position: "localizebundle/catalog.de.po:1:1",
+			Severity: codeparser.SeverityWarning,
+			Code:     qareport.CodeCatalogInvalid,
+			Message:  gettext.ErrMissingHeader.Error(),
+		},
+		{
+			Locale:   "de",
+			Package:  "localizebundle",
+			Position: "localizebundle/catalog.de.po:9:1",
+			Severity: codeparser.SeverityWarning,
+			Code:     qareport.CodeMarkup,
+			Message:  "markup differs from source: tag <b> missing",
+		},
+	}, r.Issues)
+}
+
+func TestMakeNoSite(t *testing.T) {
+	r := qareport.Make(nil, nil, nil)
+	require.Zero(t, r.SourceLocale)
+	require.Empty(t, r.Locales)
+	require.Empty(t, r.Issues)
+}
+
+func TestWriteHTML(t *testing.T) {
+	r := qareport.Make(nil, []codeparser.ErrorSrc{{
+		Position: pos("main.go", 1, 1),
+		Err:      codeparser.ErrSourceTextEmpty,
+	}}, map[string][]gettext.Error{
+		"de": {{
+			Pos: gettext.Position{Filename: "catalog.de.po", Line: 2, Column: 1},
+			Err: gettext.ErrMissingHeader,
+		}},
+	})
+	r.Issues[1].Message = "<script>"
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteHTML(&buf))
+	require.Contains(t, buf.String(), "<title>Localization QA Report</title>")
+	require.Contains(t, buf.String(), `<tr class="error"><td></td><td class="position">.</td>`)
+	require.Contains(t, buf.String(), "&lt;script&gt;")
+}
+
+func pos(file string, line, column int) token.Position {
+	return token.Position{Filename: file, Line: line, Column: column}
+}
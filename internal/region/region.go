@@ -0,0 +1,130 @@
+// Package region tags messages that vary by region rather than language,
+// such as legal disclaimers of a country, with the regions they apply to
+// using the `// regions: DE, AT` source code directive.
+// Regions are stored as `#, region:<subtag>` flags in catalogs.
+// Messages without regions apply to all regions.
+package region
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+	"golang.org/x/text/language"
+)
+
+const (
+	// DirectivePrefix is the prefix of the source code comment line
+	// listing the regions of a message.
+	DirectivePrefix = "regions:"
+
+	// FlagPrefix is the prefix of catalog flags carrying a region.
+	FlagPrefix = "region:"
+)
+
+var ErrInvalidRegion = errors.New("invalid region")
+
+// ParseDirective parses a comment line like "regions: DE, AT"
+// and returns the sorted and deduplicated canonical region subtags.
+// ok is false if line isn't a regions directive.
+func ParseDirective(line string) (regions []string, ok bool, err error) {
+	list, ok := strings.CutPrefix(line, DirectivePrefix)
+	if !ok {
+		return nil, false, nil
+	}
+	for s := range strings.SplitSeq(list, ",") {
+		r, err := Parse(strings.TrimSpace(s))
+		if err != nil {
+			return nil, true, err
+		}
+		regions = append(regions, r.String())
+	}
+	slices.Sort(regions)
+	return slices.Compact(regions), true, nil
+}
+
+// Parse parses the region subtag s, which must be either a country like "DE"
+// or a group of countries like "EU" or "150" (Europe).
+// Returns ErrInvalidRegion if s is neither.
+func Parse(s string) (language.Region, error) {
+	r, err := language.ParseRegion(s)
+	if err != nil || !r.IsCountry() && !r.IsGroup() {
+		return language.Region{}, fmt.Errorf("%w: %q", ErrInvalidRegion, s)
+	}
+	return r, nil
+}
+
+// Of returns the regions of m in the order of their flags.
+func Of(m *gettext.Message) (regions []string) {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeFlag {
+			continue
+		}
+		for f := range strings.SplitSeq(c.Value, ",") {
+			if r, ok := strings.CutPrefix(strings.TrimSpace(f), FlagPrefix); ok {
+				regions = append(regions, r)
+			}
+		}
+	}
+	return regions
+}
+
+// Contains returns true if m applies to region, which is the case if
+// m has no regions or any of its regions is or contains region.
+func Contains(m *gettext.Message, region language.Region) bool {
+	regions := Of(m)
+	if len(regions) == 0 {
+		return true
+	}
+	for _, s := range regions {
+		if r, err := language.ParseRegion(s); err == nil && r.Contains(region) {
+			return true
+		}
+	}
+	return false
+}
+
+// Set replaces the region flags of m with regions preserving all other flags.
+func Set(m *gettext.Message, regions []string) {
+	l := m.Msgctxt.Comments.Text[:0]
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeFlag {
+			var flags []string
+			for f := range strings.SplitSeq(c.Value, ",") {
+				if f = strings.TrimSpace(f); !strings.HasPrefix(f, FlagPrefix) {
+					flags = append(flags, f)
+				}
+			}
+			if len(flags) == 0 {
+				continue // Remove comments containing only regions.
+			}
+			if len(flags) <= strings.Count(c.Value, ",") {
+				// Rewrite only comments containing regions,
+				// keeping other flags verbatim.
+				c.Value = strings.Join(flags, ", ")
+			}
+		}
+		l = append(l, c)
+	}
+	if len(regions) > 0 {
+		flags := make([]string, len(regions))
+		for i, r := range regions {
+			flags[i] = FlagPrefix + r
+		}
+		l = append(l, gettext.Comment{
+			Type:  gettext.CommentTypeFlag,
+			Value: strings.Join(flags, ", "),
+		})
+	}
+	m.Msgctxt.Comments.Text = l
+}
+
+// Filter returns a copy of po containing only the messages applying to region.
+func Filter(po gettext.FilePO, region language.Region) gettext.FilePO {
+	f := *po.File
+	f.Messages.List = slices.DeleteFunc(slices.Clone(po.Messages.List),
+		func(m gettext.Message) bool { return !Contains(&m, region) })
+	return gettext.FilePO{File: &f}
+}
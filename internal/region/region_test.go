@@ -0,0 +1,92 @@
+package region_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/region"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestParseDirective(t *testing.T) {
+	f := func(t *testing.T, line string, expect []string, expectOK bool) {
+		t.Helper()
+		r, ok, err := region.ParseDirective(line)
+		require.NoError(t, err)
+		require.Equal(t, expectOK, ok)
+		require.Equal(t, expect, r)
+	}
+	f(t, "regions: DE, AT", []string{"AT", "DE"}, true)
+	f(t, "regions:ch", []string{"CH"}, true)
+	f(t, "regions: EU, 150, DE, DE", []string{"150", "DE", "EU"}, true)
+	f(t, "regions: 276", []string{"DE"}, true)
+	f(t, "Regions: DE", nil, false)
+	f(t, "Disclaimer.", nil, false)
+
+	fErr := func(t *testing.T, line string) {
+		t.Helper()
+		_, ok, err := region.ParseDirective(line)
+		require.True(t, ok)
+		require.ErrorIs(t, err, region.ErrInvalidRegion)
+	}
+	fErr(t, "regions:")
+	fErr(t, "regions: DE,")
+	fErr(t, "regions: DE AT")
+	fErr(t, "regions: ZZ")
+	fErr(t, "regions: germany")
+}
+
+func TestSet(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy, region:FR"},
+		{Type: gettext.CommentTypeFlag, Value: "region:BE"},
+	}
+	require.Equal(t, []string{"FR", "BE"}, region.Of(m))
+
+	region.Set(m, []string{"AT", "EU"})
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+		{Type: gettext.CommentTypeFlag, Value: "region:AT, region:EU"},
+	}, m.Msgctxt.Comments.Text)
+	require.Equal(t, []string{"AT", "EU"}, region.Of(m))
+	require.True(t, region.Contains(m, language.MustParseRegion("AT")))
+	require.True(t, region.Contains(m, language.MustParseRegion("DE")), "EU")
+	require.False(t, region.Contains(m, language.MustParseRegion("CH")))
+
+	region.Set(m, nil)
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+	}, m.Msgctxt.Comments.Text)
+	require.True(t, region.Contains(m, language.MustParseRegion("CH")))
+}
+
+func TestFilter(t *testing.T) {
+	msg := func(hash string, regions ...string) gettext.Message {
+		var m gettext.Message
+		m.Msgctxt.Text.Lines = []gettext.StringLiteral{{Value: hash}}
+		region.Set(&m, regions)
+		return m
+	}
+	po := gettext.FilePO{File: &gettext.File{Messages: gettext.Messages{
+		List: []gettext.Message{msg("a"), msg("b", "CH"), msg("c", "EU")},
+	}}}
+
+	hashes := func(po gettext.FilePO) (l []string) {
+		for _, m := range po.Messages.List {
+			l = append(l, m.Msgctxt.Text.String())
+		}
+		return l
+	}
+	require.Equal(t, []string{"a", "b"},
+		hashes(region.Filter(po, language.MustParseRegion("CH"))))
+	require.Equal(t, []string{"a", "c"},
+		hashes(region.Filter(po, language.MustParseRegion("DE"))))
+	require.Equal(t, []string{"a"},
+		hashes(region.Filter(po, language.MustParseRegion("US"))))
+	require.Equal(t, []string{"a", "b", "c"}, hashes(po), "original modified")
+}
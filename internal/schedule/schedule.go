@@ -0,0 +1,168 @@
+// Package schedule limits messages such as promotional copy to a period
+// of time using the `// not-before: 2025-11-28` and
+// `// not-after: 2025-12-01T23:59:59Z` source code directives.
+// Schedules are stored as `#, not-before:<time>, not-after:<time>`
+// flags in catalogs. Messages without schedules are always active.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+)
+
+const (
+	// DirectiveNotBefore is the prefix of the source code comment line
+	// setting the start of the schedule of a message.
+	DirectiveNotBefore = "not-before:"
+
+	// DirectiveNotAfter is the prefix of the source code comment line
+	// setting the end of the schedule of a message.
+	DirectiveNotAfter = "not-after:"
+
+	// FlagNotBefore and FlagNotAfter are the prefixes of catalog flags
+	// carrying the start and end of a schedule.
+	FlagNotBefore = "not-before:"
+	FlagNotAfter  = "not-after:"
+)
+
+// dateLayout is the layout of times without time of day.
+const dateLayout = time.DateOnly
+
+var (
+	ErrInvalidTime = errors.New("invalid time (expected 2006-01-02 or RFC 3339)")
+	ErrEmptyPeriod = errors.New("not-after before not-before")
+)
+
+// ParseDirective parses a comment line like "not-before: 2025-11-28" or
+// "not-after: 2025-12-01T23:59:59Z" and sets the respective bound of s.
+// ok is false if line is neither a not-before nor a not-after directive.
+func ParseDirective(line string, s *localize.Schedule) (ok bool, err error) {
+	v, isStart := strings.CutPrefix(line, DirectiveNotBefore)
+	if !isStart {
+		if v, ok = strings.CutPrefix(line, DirectiveNotAfter); !ok {
+			return false, nil
+		}
+	}
+	t, err := Parse(strings.TrimSpace(v))
+	if err != nil {
+		return true, err
+	}
+	if isStart {
+		s.NotBefore = t
+	} else {
+		s.NotAfter = t
+	}
+	return true, nil
+}
+
+// Parse parses either a date like "2025-11-28", which is midnight UTC
+// at the start of the day, or an RFC 3339 time like "2025-11-28T09:00:00+01:00".
+func Parse(s string) (time.Time, error) {
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidTime, s)
+	}
+	return t, nil
+}
+
+// Format formats t as a date if it's midnight UTC or in RFC 3339 otherwise.
+func Format(t time.Time) string {
+	if t.Location() == time.UTC && t.Equal(t.Truncate(24*time.Hour)) {
+		return t.Format(dateLayout)
+	}
+	return t.Format(time.RFC3339)
+}
+
+// Check returns ErrEmptyPeriod if s ends before it starts.
+func Check(s localize.Schedule) error {
+	if !s.NotBefore.IsZero() && !s.NotAfter.IsZero() &&
+		s.NotAfter.Before(s.NotBefore) {
+		return ErrEmptyPeriod
+	}
+	return nil
+}
+
+// Merge returns the schedule of a message referenced by two calls with
+// schedules a and b, which spans both periods. A message referenced by
+// any call without a start or end has none.
+func Merge(a, b localize.Schedule) localize.Schedule {
+	var s localize.Schedule
+	if !a.NotBefore.IsZero() && !b.NotBefore.IsZero() {
+		s.NotBefore = a.NotBefore
+		if b.NotBefore.Before(a.NotBefore) {
+			s.NotBefore = b.NotBefore
+		}
+	}
+	if !a.NotAfter.IsZero() && !b.NotAfter.IsZero() {
+		s.NotAfter = a.NotAfter
+		if b.NotAfter.After(a.NotAfter) {
+			s.NotAfter = b.NotAfter
+		}
+	}
+	return s
+}
+
+// Of returns the schedule of m. Invalid flags are ignored.
+func Of(m *gettext.Message) (s localize.Schedule) {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeFlag {
+			continue
+		}
+		for f := range strings.SplitSeq(c.Value, ",") {
+			f = strings.TrimSpace(f)
+			if v, ok := strings.CutPrefix(f, FlagNotBefore); ok {
+				s.NotBefore, _ = Parse(v)
+			} else if v, ok := strings.CutPrefix(f, FlagNotAfter); ok {
+				s.NotAfter, _ = Parse(v)
+			}
+		}
+	}
+	return s
+}
+
+// Set replaces the schedule flags of m with s preserving all other flags.
+func Set(m *gettext.Message, s localize.Schedule) {
+	l := m.Msgctxt.Comments.Text[:0]
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeFlag {
+			var flags []string
+			for f := range strings.SplitSeq(c.Value, ",") {
+				if f = strings.TrimSpace(f); !strings.HasPrefix(f, FlagNotBefore) &&
+					!strings.HasPrefix(f, FlagNotAfter) {
+					flags = append(flags, f)
+				}
+			}
+			if len(flags) == 0 {
+				continue // Remove comments containing only schedules.
+			}
+			if len(flags) <= strings.Count(c.Value, ",") {
+				// Rewrite only comments containing schedules,
+				// keeping other flags verbatim.
+				c.Value = strings.Join(flags, ", ")
+			}
+		}
+		l = append(l, c)
+	}
+	var flags []string
+	if !s.NotBefore.IsZero() {
+		flags = append(flags, FlagNotBefore+Format(s.NotBefore))
+	}
+	if !s.NotAfter.IsZero() {
+		flags = append(flags, FlagNotAfter+Format(s.NotAfter))
+	}
+	if len(flags) > 0 {
+		l = append(l, gettext.Comment{
+			Type:  gettext.CommentTypeFlag,
+			Value: strings.Join(flags, ", "),
+		})
+	}
+	m.Msgctxt.Comments.Text = l
+}
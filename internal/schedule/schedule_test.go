@@ -0,0 +1,111 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/schedule"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirective(t *testing.T) {
+	var s localize.Schedule
+	ok, err := schedule.ParseDirective("not-before: 2025-11-28", &s)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = schedule.ParseDirective("not-after:2025-12-01T23:59:59+01:00", &s)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, s.NotBefore.Equal(time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)))
+	require.True(t, s.NotAfter.Equal(time.Date(2025, 12, 1, 22, 59, 59, 0, time.UTC)))
+
+	ok, err = schedule.ParseDirective("Black Friday banner.", &s)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	for _, line := range []string{
+		"not-before:", "not-after: tomorrow", "not-after: 2025-13-01",
+		"not-before: 2025-11-28 09:00",
+	} {
+		ok, err := schedule.ParseDirective(line, &s)
+		require.True(t, ok, line)
+		require.ErrorIs(t, err, schedule.ErrInvalidTime, line)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	f := func(t *testing.T, s string) {
+		t.Helper()
+		v, err := schedule.Parse(s)
+		require.NoError(t, err)
+		require.Equal(t, s, schedule.Format(v))
+	}
+	f(t, "2025-11-28")
+	f(t, "2025-11-28T09:00:00Z")
+	f(t, "2025-11-28T00:00:00+01:00")
+}
+
+func TestCheck(t *testing.T) {
+	d := func(day int) time.Time { return time.Date(2025, 11, day, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, schedule.Check(localize.Schedule{}))
+	require.NoError(t, schedule.Check(localize.Schedule{NotAfter: d(1)}))
+	require.NoError(t, schedule.Check(localize.Schedule{NotBefore: d(1), NotAfter: d(1)}))
+	require.ErrorIs(t, schedule.Check(localize.Schedule{
+		NotBefore: d(2), NotAfter: d(1),
+	}), schedule.ErrEmptyPeriod)
+}
+
+func TestMerge(t *testing.T) {
+	d := func(day int) time.Time { return time.Date(2025, 11, day, 0, 0, 0, 0, time.UTC) }
+	require.Equal(t,
+		localize.Schedule{NotBefore: d(1), NotAfter: d(20)},
+		schedule.Merge(
+			localize.Schedule{NotBefore: d(5), NotAfter: d(20)},
+			localize.Schedule{NotBefore: d(1), NotAfter: d(10)},
+		))
+	// A call without end removes the end.
+	require.Equal(t,
+		localize.Schedule{NotBefore: d(1)},
+		schedule.Merge(
+			localize.Schedule{NotBefore: d(5), NotAfter: d(20)},
+			localize.Schedule{NotBefore: d(1)},
+		))
+	require.Equal(t, localize.Schedule{}, schedule.Merge(
+		localize.Schedule{NotBefore: d(5), NotAfter: d(20)}, localize.Schedule{},
+	))
+}
+
+func TestSet(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy, not-after:2024-01-01"},
+	}
+	require.Equal(t, localize.Schedule{
+		NotAfter: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, schedule.Of(m))
+
+	s := localize.Schedule{
+		NotBefore: time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC),
+		NotAfter:  time.Date(2025, 12, 1, 23, 59, 59, 0, time.UTC),
+	}
+	schedule.Set(m, s)
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+		{
+			Type:  gettext.CommentTypeFlag,
+			Value: "not-before:2025-11-28, not-after:2025-12-01T23:59:59Z",
+		},
+	}, m.Msgctxt.Comments.Text)
+	require.Equal(t, s, schedule.Of(m))
+
+	schedule.Set(m, localize.Schedule{})
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeFlag, Value: "fuzzy"},
+	}, m.Msgctxt.Comments.Text)
+	require.True(t, schedule.Of(m).IsZero())
+}
@@ -0,0 +1,170 @@
+// Package section groups messages into sections using the
+// `//localize:section Checkout` source code directive such that translators
+// can work through related messages together. The directive applies to all
+// messages of a package if it's part of the package clause doc comment and
+// to all messages of its file otherwise, which takes precedence.
+// Sections are stored as `#. Section: <name>` extracted comments in catalogs
+// and the first message of every section carries a `# == <name> ==`
+// translator comment as section header.
+//
+// Messages of readers returned by localize.Reader.Section belong to the
+// section of the reader and are flagged `#, scoped` since they're
+// translated separately from identical texts of other sections.
+package section
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+)
+
+const (
+	// Directive is the prefix of the source code comment
+	// assigning messages to a section.
+	Directive = "//localize:section"
+
+	// CommentPrefix is the prefix of the extracted catalog comment
+	// carrying the section of a message.
+	CommentPrefix = "Section: "
+
+	// ScopedFlag is the catalog flag of messages of readers returned
+	// by localize.Reader.Section.
+	ScopedFlag = "scoped"
+
+	headerPrefix, headerSuffix = "== ", " =="
+)
+
+var ErrEmpty = errors.New("empty section name")
+
+// ParseDirective parses a comment like "//localize:section Checkout"
+// and returns the section name trimmed of surrounding spaces.
+// ok is false if comment isn't a section directive.
+func ParseDirective(comment string) (name string, ok bool, err error) {
+	name, ok = strings.CutPrefix(strings.TrimSpace(comment), Directive)
+	if !ok || name != "" && name[0] != ' ' && name[0] != '\t' {
+		return "", false, nil
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		return "", true, ErrEmpty
+	}
+	return name, true, nil
+}
+
+// Of returns the section of m or "" if m belongs to no section.
+func Of(m *gettext.Message) string {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeExtracted {
+			continue
+		}
+		if s, ok := strings.CutPrefix(c.Value, CommentPrefix); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// Set replaces the section comment of m with name.
+// The comment is removed if name is empty.
+func Set(m *gettext.Message, name string) {
+	m.Msgctxt.Comments.Text = slices.DeleteFunc(m.Msgctxt.Comments.Text,
+		func(c gettext.Comment) bool {
+			return c.Type == gettext.CommentTypeExtracted &&
+				strings.HasPrefix(c.Value, CommentPrefix)
+		})
+	if name != "" {
+		m.Msgctxt.Comments.Text = append(m.Msgctxt.Comments.Text, gettext.Comment{
+			Type:  gettext.CommentTypeExtracted,
+			Value: CommentPrefix + name,
+		})
+	}
+}
+
+// IsScoped returns true if m is flagged as scoped.
+func IsScoped(m *gettext.Message) bool {
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type != gettext.CommentTypeFlag {
+			continue
+		}
+		for f := range strings.SplitSeq(c.Value, ",") {
+			if strings.TrimSpace(f) == ScopedFlag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetScoped adds or removes the scoped flag of m preserving all other flags.
+func SetScoped(m *gettext.Message, scoped bool) {
+	l := m.Msgctxt.Comments.Text[:0]
+	for _, c := range m.Msgctxt.Comments.Text {
+		if c.Type == gettext.CommentTypeFlag {
+			var flags []string
+			for f := range strings.SplitSeq(c.Value, ",") {
+				if f = strings.TrimSpace(f); f != ScopedFlag {
+					flags = append(flags, f)
+				}
+			}
+			if len(flags) == 0 {
+				continue // Remove comments containing only the scoped flag.
+			}
+			if len(flags) <= strings.Count(c.Value, ",") {
+				c.Value = strings.Join(flags, ", ")
+			}
+		}
+		l = append(l, c)
+	}
+	if scoped {
+		l = append(l, gettext.Comment{Type: gettext.CommentTypeFlag, Value: ScopedFlag})
+	}
+	m.Msgctxt.Comments.Text = l
+}
+
+// ID returns the identifier the generated Go bundle looks m up by, which
+// is the localize.SectionID of source for scoped messages and source
+// otherwise.
+func ID(m *gettext.Message, source string) string {
+	if IsScoped(m) {
+		return localize.SectionID(Of(m), source)
+	}
+	return source
+}
+
+// Group orders msgs by section keeping the order of messages within
+// a section. Messages without a section come first, followed by
+// the sections in lexical order. The section header comment is moved
+// to the first active message of every section.
+// Group has no effect on messages that don't belong to any section.
+func Group(msgs []gettext.Message) {
+	slices.SortStableFunc(msgs, func(a, b gettext.Message) int {
+		return cmp.Compare(Of(&a), Of(&b))
+	})
+	var prev string
+	for i := range msgs {
+		m := &msgs[i]
+		m.Msgctxt.Comments.Text = slices.DeleteFunc(m.Msgctxt.Comments.Text, isHeader)
+		if m.Obsolete {
+			continue
+		}
+		if s := Of(m); s != prev {
+			prev = s
+			m.Msgctxt.Comments.Text = slices.Insert(m.Msgctxt.Comments.Text, 0,
+				gettext.Comment{
+					Type:  gettext.CommentTypeTranslator,
+					Value: headerPrefix + s + headerSuffix,
+				})
+		}
+	}
+}
+
+// isHeader returns true if c is a section header comment.
+func isHeader(c gettext.Comment) bool {
+	return c.Type == gettext.CommentTypeTranslator &&
+		len(c.Value) > len(headerPrefix)+len(headerSuffix) &&
+		strings.HasPrefix(c.Value, headerPrefix) &&
+		strings.HasSuffix(c.Value, headerSuffix)
+}
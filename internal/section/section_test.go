@@ -0,0 +1,115 @@
+package section_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/section"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirective(t *testing.T) {
+	f := func(t *testing.T, comment, expect string, expectOK bool) {
+		t.Helper()
+		name, ok, err := section.ParseDirective(comment)
+		require.NoError(t, err)
+		require.Equal(t, expectOK, ok)
+		require.Equal(t, expect, name)
+	}
+	f(t, "//localize:section Checkout", "Checkout", true)
+	f(t, "//localize:section  User settings ", "User settings", true)
+	f(t, "//localize:sections Checkout", "", false)
+	f(t, "// localize:section Checkout", "", false)
+	f(t, "// Checkout.", "", false)
+
+	_, ok, err := section.ParseDirective("//localize:section")
+	require.True(t, ok)
+	require.ErrorIs(t, err, section.ErrEmpty)
+}
+
+func TestSet(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeExtracted, Value: "Greeting."},
+		{Type: gettext.CommentTypeExtracted, Value: "Section: Old"},
+	}
+	require.Equal(t, "Old", section.Of(m))
+
+	section.Set(m, "Checkout")
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeReference, Value: "/main.go:1"},
+		{Type: gettext.CommentTypeExtracted, Value: "Greeting."},
+		{Type: gettext.CommentTypeExtracted, Value: "Section: Checkout"},
+	}, m.Msgctxt.Comments.Text)
+	require.Equal(t, "Checkout", section.Of(m))
+
+	section.Set(m, "")
+	require.Equal(t, "", section.Of(m))
+	require.Len(t, m.Msgctxt.Comments.Text, 2)
+}
+
+func TestSetScoped(t *testing.T) {
+	m := &gettext.Message{}
+	m.Msgctxt.Comments.Text = []gettext.Comment{
+		{Type: gettext.CommentTypeExtracted, Value: "Section: Checkout/Payment"},
+		{Type: gettext.CommentTypeFlag, Value: "go-format"},
+	}
+	require.False(t, section.IsScoped(m))
+	require.Equal(t, "Pay", section.ID(m, "Pay"))
+
+	section.SetScoped(m, true)
+	require.True(t, section.IsScoped(m))
+	require.Equal(t, localize.SectionID("Checkout/Payment", "Pay"), section.ID(m, "Pay"))
+	require.Equal(t, []gettext.Comment{
+		{Type: gettext.CommentTypeExtracted, Value: "Section: Checkout/Payment"},
+		{Type: gettext.CommentTypeFlag, Value: "go-format"},
+		{Type: gettext.CommentTypeFlag, Value: "scoped"},
+	}, m.Msgctxt.Comments.Text)
+
+	section.SetScoped(m, false)
+	require.False(t, section.IsScoped(m))
+	require.Len(t, m.Msgctxt.Comments.Text, 2)
+}
+
+func TestGroup(t *testing.T) {
+	msg := func(id, name string, obsolete bool, header ...string) gettext.Message {
+		var m gettext.Message
+		m.Msgid.Text.Lines = []gettext.StringLiteral{{Value: id}}
+		m.Obsolete = obsolete
+		for _, h := range header {
+			m.Msgctxt.Comments.Text = append(m.Msgctxt.Comments.Text,
+				gettext.Comment{Type: gettext.CommentTypeTranslator, Value: h})
+		}
+		section.Set(&m, name)
+		return m
+	}
+	msgs := []gettext.Message{
+		msg("pay", "Checkout", false, "== Cart =="),
+		msg("hello", "", false),
+		msg("add", "Cart", false, "Keep it short"),
+		msg("old", "Checkout", true),
+		msg("bye", "", false),
+		msg("confirm", "Checkout", false),
+	}
+	section.Group(msgs)
+	require.Equal(t, []gettext.Message{
+		msg("hello", "", false),
+		msg("bye", "", false),
+		msg("add", "Cart", false, "== Cart ==", "Keep it short"),
+		msg("pay", "Checkout", false, "== Checkout =="),
+		msg("old", "Checkout", true),
+		msg("confirm", "Checkout", false),
+	}, msgs)
+
+	// Grouping is idempotent.
+	before := msgs[2].Msgctxt.Comments.Text
+	section.Group(msgs)
+	require.Equal(t, before, msgs[2].Msgctxt.Comments.Text)
+
+	// Messages without sections are left untouched.
+	plain := []gettext.Message{msg("b", "", false), msg("a", "", false)}
+	section.Group(plain)
+	require.Equal(t, []gettext.Message{msg("b", "", false), msg("a", "", false)}, plain)
+}
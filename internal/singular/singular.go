@@ -0,0 +1,204 @@
+// Package singular derives the template of plural form One of English
+// messages from their template of form Other, such that English source code
+// can provide form Other only (see localize generate -derive-one).
+package singular
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/romshark/localize/internal/fmtplaceholder"
+)
+
+// maxNounDistance is the maximum number of words between the quantity
+// placeholder and the noun it counts, like "%d new unread messages".
+const maxNounDistance = 2
+
+var irregular = map[string]string{
+	"children": "child",
+	"feet":     "foot",
+	"geese":    "goose",
+	"men":      "man",
+	"mice":     "mouse",
+	"people":   "person",
+	"teeth":    "tooth",
+	"women":    "woman",
+}
+
+// invariable are words ending in s that aren't plural nouns.
+var invariable = map[string]struct{}{
+	"always": {}, "does": {}, "has": {}, "its": {}, "less": {}, "news": {},
+	"plus": {}, "series": {}, "species": {}, "this": {}, "was": {}, "yes": {},
+}
+
+// agreement are the verbs agreeing with the quantity in plural
+// and their singular forms.
+var agreement = map[string]string{
+	"are":  "is",
+	"were": "was",
+	"have": "has",
+}
+
+// Template returns the template of form One derived from template other
+// by singularizing the noun counted by the quantity placeholder and the verbs
+// directly before the placeholder and after the noun agreeing with it,
+// like "%d files were deleted" becoming "%d file was deleted".
+// ok is false if other contains no quantity placeholder followed by
+// a plural noun.
+func Template(other string) (one string, ok bool) {
+	i := quantityPlaceholderEnd(other)
+	if i < 0 {
+		return "", false
+	}
+	before, after := other[:i], other[i:]
+
+	words := splitWords(after)
+	noun := -1
+	for j, w := range words {
+		if j > maxNounDistance || w.end < 0 {
+			break
+		}
+		if _, ok := singularNoun(after[w.start:w.end]); ok {
+			noun = j
+			break
+		}
+	}
+	if noun < 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.Grow(len(other))
+	b.WriteString(singularizeLastVerb(before))
+	prev := 0
+	replace := func(w word, s string) {
+		b.WriteString(after[prev:w.start])
+		b.WriteString(s)
+		prev = w.end
+	}
+	n := words[noun]
+	s, _ := singularNoun(after[n.start:n.end])
+	replace(n, s)
+	if noun+1 < len(words) && words[noun+1].end >= 0 {
+		v := words[noun+1]
+		if s, ok := singularVerb(after[v.start:v.end]); ok {
+			replace(v, s)
+		}
+	}
+	b.WriteString(after[prev:])
+	return b.String(), true
+}
+
+// quantityPlaceholderEnd returns the index of the end of the first
+// numeric placeholder of s or -1 if there is none.
+func quantityPlaceholderEnd(s string) int {
+	offset := 0
+	for _, p := range fmtplaceholder.Extract(s) {
+		i := strings.Index(s[offset:], p) + offset
+		offset = i + len(p)
+		if p != "%%" && fmtplaceholder.Numeric(p) {
+			return offset
+		}
+	}
+	return -1
+}
+
+// word is a word of a text at [start, end).
+// end is -1 for words following punctuation ending the phrase.
+type word struct{ start, end int }
+
+// splitWords returns the words of s up to the first punctuation
+// or placeholder ending the phrase.
+func splitWords(s string) (words []word) {
+	start := -1
+	for i, r := range s {
+		if unicode.IsLetter(r) || r == '\'' && start >= 0 {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, word{start, i})
+			start = -1
+		}
+		if !unicode.IsSpace(r) {
+			return append(words, word{i, -1})
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{start, len(s)})
+	}
+	return words
+}
+
+// singularNoun returns the singular of the English plural noun w
+// preserving its case. ok is false if w isn't a plural noun.
+func singularNoun(w string) (s string, ok bool) {
+	l := strings.ToLower(w)
+	if s, ok := irregular[l]; ok {
+		return matchCase(w, s), true
+	}
+	if _, ok := invariable[l]; ok {
+		return "", false
+	}
+	switch {
+	case len(l) < 3 || !strings.HasSuffix(l, "s") || strings.HasSuffix(l, "ss") ||
+		strings.HasSuffix(l, "us") || strings.HasSuffix(l, "is") ||
+		strings.HasSuffix(l, "'s"):
+		return "", false
+	case strings.HasSuffix(l, "ies") && len(l) > 4:
+		return w[:len(w)-3] + matchCase(w[len(w)-3:], "y"), true
+	case strings.HasSuffix(l, "sses"), strings.HasSuffix(l, "xes"),
+		strings.HasSuffix(l, "shes"),
+		strings.HasSuffix(l, "ches") && !strings.HasSuffix(l, "aches"):
+		return w[:len(w)-2], true
+	}
+	return w[:len(w)-1], true
+}
+
+// singularVerb returns the singular of the verb w agreeing with
+// a plural subject preserving its case. ok is false if w isn't such a verb.
+func singularVerb(w string) (s string, ok bool) {
+	s, ok = agreement[strings.ToLower(w)]
+	if !ok {
+		return "", false
+	}
+	return matchCase(w, s), true
+}
+
+// singularizeLastVerb singularizes the form of "to be" directly before
+// the quantity placeholder at the end of s like in "There are %d".
+// Other verbs before the placeholder agree with another subject
+// like in "You have %d".
+func singularizeLastVerb(s string) string {
+	trimmed := strings.TrimRightFunc(s[:len(s)-len(lastPlaceholder(s))], unicode.IsSpace)
+	i := strings.LastIndexFunc(trimmed, func(r rune) bool { return !unicode.IsLetter(r) })
+	v, ok := singularVerb(trimmed[i+1:])
+	if !ok || strings.EqualFold(v, "has") {
+		return s
+	}
+	return trimmed[:i+1] + v + s[len(trimmed):]
+}
+
+// lastPlaceholder returns the placeholder at the end of s.
+func lastPlaceholder(s string) string {
+	i := strings.LastIndexByte(s, '%')
+	if i < 0 {
+		return ""
+	}
+	return s[i:]
+}
+
+// matchCase returns s in the case of w, which is all upper case
+// or capitalized.
+func matchCase(w, s string) string {
+	if len(w) > 1 && strings.ToUpper(w) == w {
+		return strings.ToUpper(s)
+	}
+	if r, _ := utf8.DecodeRuneInString(w); unicode.IsUpper(r) {
+		return strings.ToUpper(s[:1]) + s[1:]
+	}
+	return s
+}
@@ -0,0 +1,48 @@
+package singular_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/singular"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate(t *testing.T) {
+	f := func(t *testing.T, other, expect string) {
+		t.Helper()
+		one, ok := singular.Template(other)
+		require.True(t, ok)
+		require.Equal(t, expect, one)
+	}
+	f(t, "%d messages", "%d message")
+	f(t, "%d files were deleted.", "%d file was deleted.")
+	f(t, "There are %d new messages", "There is %d new message")
+	f(t, "You have %d unread messages!", "You have %d unread message!")
+	f(t, "%d messages have been sent", "%d message has been sent")
+	f(t, "%d categories", "%d category")
+	f(t, "%d boxes, %d items", "%d box, %d items")
+	f(t, "%d matches", "%d match")
+	f(t, "%d caches", "%d cache")
+	f(t, "%d classes", "%d class")
+	f(t, "%d people", "%d person")
+	f(t, "%d Children", "%d Child")
+	f(t, "%d FILES", "%d FILE")
+	f(t, "Deleted %.1f GBs", "Deleted %.1f GB")
+	f(t, "100%% done: %d tasks", "100%% done: %d task")
+	f(t, "\n\t%d messages are being processed.\n", "\n\t%d message is being processed.\n")
+
+	for _, other := range []string{
+		"Hello",
+		"Items: %d",
+		"%d×",
+		"%d-day trial",
+		"%d of %d selected",
+		"%s has %d",
+		"%d news",
+		"%d status",
+		"%d is the answer",
+	} {
+		_, ok := singular.Template(other)
+		require.False(t, ok, other)
+	}
+}
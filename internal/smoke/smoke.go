@@ -0,0 +1,247 @@
+// Package smoke verifies that the readers of a generated Go bundle return
+// the translations of the catalogs they were generated from. A harness
+// program performs the lookups of a sample of catalog messages through
+// the compiled readers and the results are compared with the translations
+// expected from the catalog, which catches drift between the code generator
+// and the catalogs independent of the generator's own data.
+package smoke
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/cldr"
+	"github.com/romshark/localize/internal/fuzzy"
+	"github.com/romshark/localize/internal/section"
+	"golang.org/x/text/language"
+)
+
+//go:embed harness.gotmpl
+var harnessGotmpl string
+
+// Check is a lookup performed by the harness and its expected result.
+type Check struct {
+	// Ref is the position of the message in its catalog like "catalog.de.po:12".
+	Ref string
+
+	// Section is the section of scoped messages, empty otherwise.
+	Section string
+
+	// Text is the source text of static messages.
+	Text string
+
+	// Plural is true for plural messages, which are looked up
+	// with Forms and Quantity.
+	Plural   bool
+	Forms    localize.Forms
+	Quantity int
+
+	// Expect is the expected result of the lookup.
+	Expect string
+}
+
+// Options are optional settings of Checks.
+type Options struct {
+	// Max is the maximum number of messages to check,
+	// all messages are checked if Max is 0.
+	Max int
+
+	// Transform is applied to the translations of the catalog like
+	// the generator applies typography and heading casing if not nil.
+	Transform func(m *gettext.Message, s string) string
+}
+
+// Checks returns the lookups of the messages of the catalog of locale
+// and the translations the readers are expected to return. Plural messages
+// are checked once per plural form with a CLDR sample quantity of the form.
+// The sample consists of the first opts.Max messages ordered by hash, which
+// is random but stable. Obsolete messages, grammar entries, register variants
+// and messages sharing their lookup key with a differing translation
+// aren't checked, neither are plural messages missing translated forms.
+func Checks(locale language.Tag, messages []gettext.Message, opts Options) ([]Check, error) {
+	pluralForms, ok := cldr.ByTagOrBase(locale)
+	if !ok {
+		return nil, fmt.Errorf("resolving plural forms by locale: %s", locale)
+	}
+	transform := func(m *gettext.Message, s string) string {
+		if opts.Transform == nil || s == "" {
+			return s
+		}
+		return opts.Transform(m, s)
+	}
+
+	var l []*gettext.Message
+	for i := range messages {
+		m := &messages[i]
+		ctx := m.Msgctxt.Text.String()
+		if m.Obsolete || m.Msgid.Text.String() == "" ||
+			strings.HasPrefix(ctx, localize.GrammarContextPrefix) ||
+			strings.HasPrefix(ctx, localize.RegisterContextPrefix) {
+			continue
+		}
+		l = append(l, m)
+	}
+	slices.SortStableFunc(l, func(a, b *gettext.Message) int {
+		return strings.Compare(a.Msgctxt.Text.String(), b.Msgctxt.Text.String())
+	})
+
+	// checksByKey are the checks of every message by lookup key.
+	checksByKey := map[string][][]Check{}
+	var keys []string
+	for _, m := range l {
+		untranslated := fuzzy.Untranslated(*m)
+		pos := m.Msgid.Position
+		if !m.Msgctxt.IsZero() {
+			pos = m.Msgctxt.Position
+		}
+		ref := fmt.Sprintf("%s:%d", pos.Filename, pos.Line)
+		var sec string
+		if section.IsScoped(m) {
+			sec = section.Of(m)
+		}
+		var checks []Check
+		if len(m.MsgidPlural.Text.Lines) == 0 {
+			source := m.Msgid.Text.String()
+			expect := transform(m, untranslated.Msgstr.Text.String())
+			if expect == "" {
+				expect = source // Untranslated messages fall back to the source.
+			}
+			checks = append(checks, Check{
+				Ref: ref, Section: sec, Text: source, Expect: expect,
+			})
+		} else {
+			checks = pluralChecks(pluralForms, &untranslated, ref, sec, transform)
+			if checks == nil {
+				continue
+			}
+		}
+		key := section.ID(m, sourceText(m))
+		if _, ok := checksByKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		checksByKey[key] = append(checksByKey[key], checks)
+	}
+
+	var checks []Check
+	checked := 0
+	for _, key := range keys {
+		if opts.Max > 0 && checked == opts.Max {
+			break
+		}
+		l := checksByKey[key]
+		if slices.ContainsFunc(l[1:], func(c []Check) bool {
+			return !slices.EqualFunc(c, l[0], func(a, b Check) bool {
+				return a.Expect == b.Expect
+			})
+		}) {
+			continue // The key is ambiguous.
+		}
+		checks = append(checks, l[0]...)
+		checked++
+	}
+	return checks, nil
+}
+
+// pluralChecks returns the checks of the plural message m, or nil if
+// a form is untranslated or the locale provides no sample quantity for it.
+func pluralChecks(
+	pluralForms cldr.PluralForms, m *gettext.Message, ref, sec string,
+	transform func(m *gettext.Message, s string) string,
+) []Check {
+	msgstrs := [...]*gettext.Msgstr{
+		&m.Msgstr0, &m.Msgstr1, &m.Msgstr2, &m.Msgstr3, &m.Msgstr4, &m.Msgstr5,
+	}
+	source := localize.Forms{
+		One: m.Msgid.Text.String(), Other: m.MsgidPlural.Text.String(),
+	}
+	var checks []Check
+	for i, form := range pluralForms.CardinalForms {
+		translated := transform(m, msgstrs[i].Text.String())
+		if translated == "" {
+			return nil
+		}
+		q, ok := sample(pluralForms, form)
+		if !ok {
+			return nil
+		}
+		checks = append(checks, Check{
+			Ref: ref, Section: sec, Plural: true, Forms: source, Quantity: q,
+			Expect: fmt.Sprintf(translated, q),
+		})
+	}
+	return checks
+}
+
+// sample returns the lowest sample quantity of the CLDR plural rules
+// selecting form, including the quantities of forms merged into form.
+func sample(p cldr.PluralForms, form cldr.CLDRPluralForm) (q int, ok bool) {
+	for f := cldr.CLDRPluralFormZero; f <= cldr.CLDRPluralFormOther; f++ {
+		r := p.Resolve(f)
+		if !slices.Contains(p.CardinalForms, r) {
+			// Catalogs have no form for categories their gettext rules merge
+			// into Other, like Many for Russian.
+			r = cldr.CLDRPluralFormOther
+		}
+		if r != form {
+			continue
+		}
+		for _, n := range p.Examples[f] {
+			if !ok || n < q {
+				q, ok = n, true
+			}
+		}
+	}
+	return q, ok
+}
+
+// sourceText returns the text the readers look m up by.
+func sourceText(m *gettext.Message) string {
+	if len(m.MsgidPlural.Text.Lines) > 0 {
+		return m.MsgidPlural.Text.String()
+	}
+	return m.Msgid.Text.String()
+}
+
+// WriteHarness writes the Go program performing the lookups of checks
+// through the reader of locale of the bundle package importPath and
+// printing their results as a JSON array of strings.
+func WriteHarness(
+	w io.Writer, importPath string, locale language.Tag, checks []Check,
+) error {
+	tmpl, err := template.New("harness").Parse(harnessGotmpl)
+	if err != nil {
+		return fmt.Errorf("rendering template: %w", err)
+	}
+	return tmpl.Execute(w, struct {
+		ImportPath string
+		Locale     string
+		Checks     []Check
+	}{ImportPath: importPath, Locale: locale.String(), Checks: checks})
+}
+
+// Mismatch is a check whose lookup didn't return the expected result.
+type Mismatch struct {
+	Check
+	Got string
+}
+
+// Compare returns the checks whose results differ from their expectation.
+// results are the results printed by the harness in the order of checks.
+func Compare(checks []Check, results []string) ([]Mismatch, error) {
+	if len(results) != len(checks) {
+		return nil, fmt.Errorf("expected %d results, got %d", len(checks), len(results))
+	}
+	var l []Mismatch
+	for i, c := range checks {
+		if results[i] != c.Expect {
+			l = append(l, Mismatch{Check: c, Got: results[i]})
+		}
+	}
+	return l, nil
+}
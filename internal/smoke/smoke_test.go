@@ -0,0 +1,141 @@
+package smoke_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/smoke"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+const catalogDE = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgctxt "h1"
+msgid "Save"
+msgstr "Speichern"
+
+msgctxt "h2"
+msgid "Cancel"
+msgstr ""
+
+#, fuzzy
+msgctxt "h3"
+msgid "Delete"
+msgstr "Löschen?"
+
+#. Section: Checkout
+#, scoped
+msgctxt "h4"
+msgid "Total"
+msgstr "Gesamtbetrag"
+
+msgctxt "h5"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] "%d Dateien"
+
+msgctxt "h6"
+msgid "%d day"
+msgid_plural "%d days"
+msgstr[0] "%d Tag"
+msgstr[1] ""
+
+msgctxt "h7"
+msgid "Open"
+msgstr "Öffnen"
+
+msgctxt "h8"
+msgid "Open"
+msgstr "Offen"
+
+msgctxt "grammar:case"
+msgid "dative\u0004Haus"
+msgstr "Hause"
+
+#~ msgctxt "h0"
+#~ msgid "Upload"
+#~ msgstr "Hochladen"
+`
+
+func decode(t *testing.T, src string) []gettext.Message {
+	t.Helper()
+	po, err := gettext.NewDecoder().DecodePOBytes("catalog.de.po", []byte(src))
+	require.NoError(t, err)
+	return po.Messages.List
+}
+
+func TestChecks(t *testing.T) {
+	checks, err := smoke.Checks(language.German, decode(t, catalogDE), smoke.Options{})
+	require.NoError(t, err)
+	files := localize.Forms{One: "%d file", Other: "%d files"}
+	require.Equal(t, []smoke.Check{
+		{Ref: "catalog.de.po:6", Text: "Save", Expect: "Speichern"},
+		{Ref: "catalog.de.po:10", Text: "Cancel", Expect: "Cancel"},
+		{Ref: "catalog.de.po:14", Text: "Delete", Expect: "Delete"},
+		{
+			Ref: "catalog.de.po:19", Section: "Checkout",
+			Text: "Total", Expect: "Gesamtbetrag",
+		},
+		{
+			Ref: "catalog.de.po:25", Plural: true, Forms: files,
+			Quantity: 1, Expect: "1 Datei",
+		},
+		{
+			Ref: "catalog.de.po:25", Plural: true, Forms: files,
+			Quantity: 0, Expect: "0 Dateien",
+		},
+	}, checks)
+
+	checks, err = smoke.Checks(language.German, decode(t, catalogDE), smoke.Options{
+		Max: 2,
+		Transform: func(m *gettext.Message, s string) string {
+			return strings.ToUpper(s)
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []smoke.Check{
+		{Ref: "catalog.de.po:6", Text: "Save", Expect: "SPEICHERN"},
+		{Ref: "catalog.de.po:10", Text: "Cancel", Expect: "Cancel"},
+	}, checks)
+}
+
+func TestWriteHarness(t *testing.T) {
+	checks, err := smoke.Checks(language.German, decode(t, catalogDE), smoke.Options{})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	err = smoke.WriteHarness(&buf, "example.com/app/localizebundle",
+		language.German, checks)
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "main.go", buf.Bytes(), 0)
+	require.NoError(t, err)
+
+	s := buf.String()
+	require.Contains(t, s, `bundle "example.com/app/localizebundle"`)
+	require.Contains(t, s, `r.Text("Save"),`)
+	require.Contains(t, s, `r.Section("Checkout").Text("Total"),`)
+	require.Contains(t, s,
+		`r.Plural(localize.Forms{One: "%d file", Other: "%d files"}, 1),`)
+}
+
+func TestCompare(t *testing.T) {
+	checks := []smoke.Check{
+		{Ref: "catalog.de.po:6", Text: "Save", Expect: "Speichern"},
+		{Ref: "catalog.de.po:10", Text: "Cancel", Expect: "Abbrechen"},
+	}
+	mismatches, err := smoke.Compare(checks, []string{"Speichern", "Cancel"})
+	require.NoError(t, err)
+	require.Equal(t, []smoke.Mismatch{{Check: checks[1], Got: "Cancel"}}, mismatches)
+
+	_, err = smoke.Compare(checks, []string{"Speichern"})
+	require.ErrorContains(t, err, "expected 2 results, got 1")
+}
@@ -0,0 +1,80 @@
+// Package summary writes the machine-readable summary of a generator run
+// (-summary) for CI pipelines reporting the localization impact of changes
+// and post-generate hooks (-post-generate).
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/romshark/localize/plugin"
+)
+
+// Summary is the summary of a generator run, which post-generate hooks
+// receive on stdin.
+type Summary = plugin.Summary
+
+// Locale are the changes of the translation catalogs of a locale.
+type Locale = plugin.LocaleSummary
+
+// Phase is a timed phase of a generator run.
+type Phase = plugin.Phase
+
+// Timer measures consecutive phases.
+type Timer struct {
+	start  time.Time
+	phases []Phase
+}
+
+// NewTimer returns a timer starting the first phase at start.
+func NewTimer(start time.Time) *Timer { return &Timer{start: start} }
+
+// End ends the current phase with name and starts the next one.
+func (t *Timer) End(name string) {
+	now := time.Now()
+	t.phases = append(t.phases, Phase{
+		Name:                name,
+		DurationNanoseconds: now.Sub(t.start).Nanoseconds(),
+	})
+	t.start = now
+}
+
+// Phases returns all ended phases.
+func (t *Timer) Phases() []Phase { return t.phases }
+
+// Write writes s to the file at path as indented JSON (see Encode).
+func Write(path string, s Summary) error {
+	b, err := Encode(s)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return fmt.Errorf("writing summary: %w", err)
+	}
+	return nil
+}
+
+// Encode returns s as indented JSON terminated by a newline.
+// Paths of s.Files are converted to slash-separated paths.
+func Encode(s Summary) ([]byte, error) {
+	files := make([]string, len(s.Files))
+	for i, f := range s.Files {
+		files[i] = filepath.ToSlash(f)
+	}
+	s.Files = files
+	// Empty lists are encoded as [] rather than null for consumers.
+	if s.Locales == nil {
+		s.Locales = []Locale{}
+	}
+	if s.Phases == nil {
+		s.Phases = []Phase{}
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding summary: %w", err)
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,53 @@
+package summary_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/romshark/localize/internal/summary"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimer(t *testing.T) {
+	timer := summary.NewTimer(time.Now().Add(-time.Second))
+	timer.End("analyze")
+	timer.End("bundle")
+	p := timer.Phases()
+	require.Len(t, p, 2)
+	require.Equal(t, "analyze", p[0].Name)
+	require.GreaterOrEqual(t, p[0].DurationNanoseconds, time.Second.Nanoseconds())
+	require.Equal(t, "bundle", p[1].Name)
+	require.Less(t, p[1].DurationNanoseconds, time.Second.Nanoseconds())
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "localize-summary.json")
+	require.NoError(t, summary.Write(path, summary.Summary{}))
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"messages": 0, "locales": [], "files": [], "phases": [],
+		"timeTotalNanoseconds": 0
+	}`, string(b))
+
+	require.NoError(t, summary.Write(path, summary.Summary{
+		Messages: 3,
+		Locales: []summary.Locale{
+			{Locale: "de", New: 1, Changed: 2, Obsoleted: 1},
+		},
+		Files:                []string{filepath.Join("localizebundle", "catalog.de.po")},
+		Phases:               []summary.Phase{{Name: "analyze", DurationNanoseconds: 5}},
+		TimeTotalNanoseconds: 7,
+	}))
+	b, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"messages": 3,
+		"locales": [{"locale": "de", "new": 1, "changed": 2, "obsoleted": 1}],
+		"files": ["localizebundle/catalog.de.po"],
+		"phases": [{"name": "analyze", "durationNanoseconds": 5}],
+		"timeTotalNanoseconds": 7
+	}`, string(b))
+}
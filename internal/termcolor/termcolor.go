@@ -0,0 +1,85 @@
+// Package termcolor colors console output using ANSI escape sequences
+// if the output is a terminal, respecting the NO_COLOR convention
+// (see https://no-color.org).
+package termcolor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Mode defines when output is colored.
+type Mode uint8
+
+const (
+	// ModeAuto colors output written to a terminal unless
+	// the environment variable NO_COLOR is set or TERM is "dumb".
+	ModeAuto Mode = iota
+
+	// ModeAlways always colors output.
+	ModeAlways
+
+	// ModeNever never colors output.
+	ModeNever
+)
+
+// Modes are the names of all modes accepted by ParseMode.
+var Modes = []string{"auto", "always", "never"}
+
+var ErrInvalidMode = errors.New("invalid color mode")
+
+// ParseMode parses a mode name (see Modes).
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "auto":
+		return ModeAuto, nil
+	case "always":
+		return ModeAlways, nil
+	case "never":
+		return ModeNever, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidMode, s)
+}
+
+func (m Mode) String() string { return Modes[m] }
+
+// Palette colors strings if Enabled is true, otherwise it returns them as is.
+type Palette struct{ Enabled bool }
+
+// New returns the palette for output written to f in mode m.
+func New(m Mode, f *os.File) Palette {
+	switch m {
+	case ModeAlways:
+		return Palette{Enabled: true}
+	case ModeNever:
+		return Palette{}
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return Palette{}
+	}
+	info, err := f.Stat()
+	return Palette{Enabled: err == nil && info.Mode()&os.ModeCharDevice != 0}
+}
+
+func (p Palette) wrap(code, s string) string {
+	if !p.Enabled || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// Bold returns s in bold.
+func (p Palette) Bold(s string) string { return p.wrap("1", s) }
+
+// Red returns s in bold red, used for errors.
+func (p Palette) Red(s string) string { return p.wrap("1;31", s) }
+
+// Yellow returns s in yellow, used for warnings.
+func (p Palette) Yellow(s string) string { return p.wrap("33", s) }
+
+// Green returns s in green, used for success.
+func (p Palette) Green(s string) string { return p.wrap("32", s) }
+
+// Cyan returns s in cyan, used for source positions.
+func (p Palette) Cyan(s string) string { return p.wrap("36", s) }
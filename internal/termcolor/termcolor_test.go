@@ -0,0 +1,48 @@
+package termcolor_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/localize/internal/termcolor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMode(t *testing.T) {
+	for i, name := range termcolor.Modes {
+		m, err := termcolor.ParseMode(name)
+		require.NoError(t, err)
+		require.Equal(t, termcolor.Mode(i), m)
+		require.Equal(t, name, m.String())
+	}
+	_, err := termcolor.ParseMode("yes")
+	require.ErrorIs(t, err, termcolor.ErrInvalidMode)
+}
+
+func TestNew(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	t.Setenv("NO_COLOR", "")
+	require.False(t, termcolor.New(termcolor.ModeAuto, f).Enabled, "not a terminal")
+	require.True(t, termcolor.New(termcolor.ModeAlways, f).Enabled)
+	require.False(t, termcolor.New(termcolor.ModeNever, f).Enabled)
+
+	t.Setenv("NO_COLOR", "1")
+	require.False(t, termcolor.New(termcolor.ModeAuto, os.Stderr).Enabled)
+	require.True(t, termcolor.New(termcolor.ModeAlways, os.Stderr).Enabled)
+}
+
+func TestPalette(t *testing.T) {
+	p := termcolor.Palette{Enabled: true}
+	require.Equal(t, "\x1b[1;31mERR:\x1b[0m", p.Red("ERR:"))
+	require.Equal(t, "\x1b[33mWARNING\x1b[0m", p.Yellow("WARNING"))
+	require.Equal(t, "\x1b[1mx\x1b[0m", p.Bold("x"))
+	require.Equal(t, "\x1b[32mok\x1b[0m", p.Green("ok"))
+	require.Equal(t, "\x1b[36m/main.go:1:2\x1b[0m", p.Cyan("/main.go:1:2"))
+	require.Equal(t, "", p.Red(""))
+
+	require.Equal(t, "ERR:", termcolor.Palette{}.Red("ERR:"))
+}
@@ -0,0 +1,270 @@
+// Package untranslated exports the messages of translation catalogs that
+// need translation into focused catalogs for translators and imports
+// their translations back. Messages are matched by msgctxt (the message
+// hash) such that translations are only imported into the messages
+// they were made for.
+package untranslated
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/coverage"
+	"github.com/romshark/localize/internal/fmtplaceholder"
+	"github.com/romshark/localize/internal/fuzzy"
+)
+
+var ErrLocaleMismatch = errors.New("catalog of another locale")
+
+// HeaderPlaceholders is the non-standard header marking catalogs
+// whose placeholders were replaced by protected tokens (see Tokenize).
+const HeaderPlaceholders, PlaceholdersTokens = "X-Localize-Placeholders", "tokens"
+
+// Export returns a copy of catalog containing only the messages that aren't
+// translated or are flagged as fuzzy, including their references and
+// descriptions. Obsolete messages, register variants and grammar entries
+// are left out.
+func Export(catalog gettext.FilePO) gettext.FilePO {
+	f := gettext.File{Head: catalog.Head.Clone(), Format: catalog.Format}
+	for i := range catalog.Messages.List {
+		m := &catalog.Messages.List[i]
+		if m.Obsolete || isAuxiliary(m) {
+			continue
+		}
+		if !coverage.IsTranslated(m) || fuzzy.Is(m) {
+			f.Messages.List = append(f.Messages.List, m.Clone())
+		}
+	}
+	return gettext.FilePO{File: &f}
+}
+
+// Tokenize replaces the placeholders in the source texts and translations
+// of exported, which is a catalog returned by Export, with protected tokens
+// like "⟦1⟧" for machine translation and CAT tools unaware of Go fmt
+// placeholders, and marks it with HeaderPlaceholders such that Import
+// restores and validates them.
+func Tokenize(exported gettext.FilePO) {
+	if IsTokenized(exported) {
+		return
+	}
+	exported.Head.NonStandard = append(exported.Head.NonStandard, gettext.XHeader{
+		Name: HeaderPlaceholders, Value: PlaceholdersTokens,
+	})
+	for i := range exported.Messages.List {
+		m := &exported.Messages.List[i]
+		for _, text := range texts(m) {
+			if text.Lines == nil {
+				continue
+			}
+			tokenized, _ := fmtplaceholder.Tokenize(text.String())
+			*text = gettext.StringLiterals{
+				Lines: []gettext.StringLiteral{{Value: tokenized}},
+			}.SplitLines()
+		}
+	}
+}
+
+// IsTokenized returns true if the placeholders of exported were replaced
+// by protected tokens (see Tokenize).
+func IsTokenized(exported gettext.FilePO) bool {
+	for _, h := range exported.Head.NonStandard {
+		if strings.EqualFold(h.Name, HeaderPlaceholders) {
+			return h.Value == PlaceholdersTokens
+		}
+	}
+	return false
+}
+
+// Result is the result of Import.
+type Result struct {
+	// Imported is the number of translations imported.
+	Imported int
+
+	// Untranslated is the number of exported messages that are still
+	// untranslated or flagged as fuzzy and were skipped.
+	Untranslated int
+
+	// Missing are the hashes of translated messages that aren't
+	// in the catalog anymore, such as messages removed from the source code.
+	Missing []string
+
+	// Changed are the hashes of translated messages whose source text
+	// in the catalog differs from the exported source text.
+	Changed []string
+
+	// Conflicts are the hashes of translated messages that were translated
+	// differently in the catalog since the export and were left as is.
+	Conflicts []string
+
+	// Invalid are the translations of a tokenized export (see Tokenize)
+	// whose placeholder tokens are corrupted, missing or unknown.
+	Invalid []Invalid
+}
+
+// Invalid is an invalid translation that wasn't imported.
+type Invalid struct {
+	Hash string
+	Err  error
+}
+
+// Import copies the translations of exported, which is a catalog written
+// by Export and translated since, into catalogs of the same locale, which
+// are the parts of a catalog split into domains. Only messages that are
+// still untranslated or flagged as fuzzy in catalogs are updated and
+// imported messages lose their fuzzy flag. Messages of exported that are
+// still untranslated or fuzzy are skipped. Returns the modified catalogs
+// by index, catalogs aren't modified if an error is returned.
+// If exported is tokenized (see Tokenize) the placeholders of its
+// translations are restored from the source texts in catalogs.
+func Import(
+	catalogs []gettext.FilePO, exported gettext.FilePO,
+) (result Result, modified []int, err error) {
+	if len(catalogs) < 1 {
+		return Result{}, nil, nil
+	}
+	if a, b := exported.Head.Language, catalogs[0].Head.Language; a.Value != "" &&
+		b.Value != "" && a.Locale != b.Locale {
+		return Result{}, nil, fmt.Errorf("%w: %s (catalog: %s)",
+			ErrLocaleMismatch, a.Locale, b.Locale)
+	}
+
+	type target struct {
+		catalog int
+		m       *gettext.Message
+	}
+	byCtx := map[string]target{}
+	for c := range catalogs {
+		for i := range catalogs[c].Messages.List {
+			m := &catalogs[c].Messages.List[i]
+			if !m.Obsolete {
+				byCtx[m.Msgctxt.Text.String()] = target{catalog: c, m: m}
+			}
+		}
+	}
+
+	tokenized := IsTokenized(exported)
+	isModified := make([]bool, len(catalogs))
+	for i := range exported.Messages.List {
+		src := &exported.Messages.List[i]
+		if src.Obsolete || isAuxiliary(src) {
+			continue
+		}
+		if !coverage.IsTranslated(src) || fuzzy.Is(src) {
+			result.Untranslated++
+			continue
+		}
+		hash := src.Msgctxt.Text.String()
+		t, ok := byCtx[hash]
+		switch {
+		case !ok:
+			result.Missing = append(result.Missing, hash)
+			continue
+		case sourceText(t.m.Msgid.Text, tokenized) != src.Msgid.Text.String() ||
+			sourceText(t.m.MsgidPlural.Text, tokenized) !=
+				src.MsgidPlural.Text.String():
+			result.Changed = append(result.Changed, hash)
+			continue
+		}
+		if tokenized {
+			restored, err := detokenize(t.m, src)
+			if err != nil {
+				result.Invalid = append(result.Invalid, Invalid{Hash: hash, Err: err})
+				continue
+			}
+			src = restored
+		}
+		if coverage.IsTranslated(t.m) && !fuzzy.Is(t.m) {
+			if !sameTranslation(t.m, src) {
+				result.Conflicts = append(result.Conflicts, hash)
+			}
+			continue
+		}
+		fuzzy.Carry(t.m, src, fuzzy.Keep)
+		fuzzy.Set(t.m, false)
+		isModified[t.catalog] = true
+		result.Imported++
+	}
+	for i, ok := range isModified {
+		if ok {
+			modified = append(modified, i)
+		}
+	}
+	return result, modified, nil
+}
+
+// sourceText returns the source text l, tokenized if tokenized is true.
+func sourceText(l gettext.StringLiterals, tokenized bool) string {
+	if !tokenized {
+		return l.String()
+	}
+	s, _ := fmtplaceholder.Tokenize(l.String())
+	return s
+}
+
+// detokenize returns a copy of src, which is a message of a tokenized export,
+// with the placeholders of its translations restored from the source text
+// of m. The translation of static
+// messages and the last plural form (other) must use all placeholders,
+// while other plural forms may omit them, like "One file".
+func detokenize(m, src *gettext.Message) (*gettext.Message, error) {
+	// Plural forms are restored from msgid_plural since the other form
+	// carries all placeholders.
+	source := m.Msgid.Text.String()
+	if m.MsgidPlural.Text.Lines != nil {
+		source = m.MsgidPlural.Text.String()
+	}
+	_, placeholders := fmtplaceholder.Tokenize(source)
+	restored := src.Clone()
+	l := texts(&restored)[2:]
+	last := 0
+	for i, text := range l {
+		if text.Lines != nil {
+			last = i
+		}
+	}
+	for i, text := range l {
+		if text.Lines == nil {
+			continue
+		}
+		s, err := fmtplaceholder.Detokenize(text.String(), placeholders, i == last)
+		if err != nil {
+			return nil, err
+		}
+		*text = gettext.StringLiterals{
+			Lines: []gettext.StringLiteral{{Value: s}},
+		}.SplitLines()
+	}
+	return &restored, nil
+}
+
+// texts returns the source texts (msgid and msgid_plural) followed
+// by the translations of m.
+func texts(m *gettext.Message) []*gettext.StringLiterals {
+	return []*gettext.StringLiterals{
+		&m.Msgid.Text, &m.MsgidPlural.Text,
+		&m.Msgstr.Text, &m.Msgstr0.Text, &m.Msgstr1.Text, &m.Msgstr2.Text,
+		&m.Msgstr3.Text, &m.Msgstr4.Text, &m.Msgstr5.Text,
+	}
+}
+
+// sameTranslation returns true if a and b have identical translations.
+func sameTranslation(a, b *gettext.Message) bool {
+	return a.Msgstr.Text.String() == b.Msgstr.Text.String() &&
+		a.Msgstr0.Text.String() == b.Msgstr0.Text.String() &&
+		a.Msgstr1.Text.String() == b.Msgstr1.Text.String() &&
+		a.Msgstr2.Text.String() == b.Msgstr2.Text.String() &&
+		a.Msgstr3.Text.String() == b.Msgstr3.Text.String() &&
+		a.Msgstr4.Text.String() == b.Msgstr4.Text.String() &&
+		a.Msgstr5.Text.String() == b.Msgstr5.Text.String()
+}
+
+// isAuxiliary returns true for grammar entries and register variants,
+// which translators write themselves.
+func isAuxiliary(m *gettext.Message) bool {
+	ctx := m.Msgctxt.Text.String()
+	return strings.HasPrefix(ctx, localize.GrammarContextPrefix) ||
+		strings.HasPrefix(ctx, localize.RegisterContextPrefix)
+}
@@ -0,0 +1,217 @@
+package untranslated_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/fmtplaceholder"
+	"github.com/romshark/localize/internal/fuzzy"
+	"github.com/romshark/localize/internal/untranslated"
+	"github.com/stretchr/testify/require"
+)
+
+const catalogDE = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+#: /main.go:1
+#. Save button.
+msgctxt "h1"
+msgid "Save"
+msgstr "Speichern"
+
+#: /main.go:2
+#. Cancel button.
+msgctxt "h2"
+msgid "Cancel"
+msgstr ""
+
+#: /main.go:3
+#, fuzzy
+msgctxt "h3"
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Eine Datei"
+msgstr[1] "%d Datei"
+
+msgctxt "h4"
+msgid "Close"
+msgstr ""
+
+msgctxt "register:informal"
+msgid "Save"
+msgstr ""
+
+#~ msgctxt "h0"
+#~ msgid "Upload"
+#~ msgstr ""
+`
+
+func decode(t *testing.T, name, src string) gettext.FilePO {
+	t.Helper()
+	po, err := gettext.NewDecoder().DecodePOBytes(name, []byte(src))
+	require.NoError(t, err)
+	return po
+}
+
+func hashes(po gettext.FilePO) (l []string) {
+	for _, m := range po.Messages.List {
+		l = append(l, m.Msgctxt.Text.String())
+	}
+	return l
+}
+
+func TestExport(t *testing.T) {
+	catalog := decode(t, "catalog.de.po", catalogDE)
+	exported := untranslated.Export(catalog)
+	require.Equal(t, []string{"h2", "h3", "h4"}, hashes(exported))
+	require.Equal(t, "de", exported.Head.Language.Value)
+
+	s, err := gettext.Encoder{}.EncodePOToString(exported)
+	require.NoError(t, err)
+	require.Contains(t, s, "#: /main.go:2\n")
+	require.Contains(t, s, "#. Cancel button.\n")
+	require.Contains(t, s, "#, fuzzy\n")
+
+	// The catalog is left as is.
+	require.Len(t, catalog.Messages.List, 6)
+}
+
+func TestImport(t *testing.T) {
+	catalog := decode(t, "catalog.de.po", catalogDE)
+	// Close was translated since the export.
+	catalog.Messages.List[3].Msgstr.Text = gettext.StringLiterals{
+		Lines: []gettext.StringLiteral{{Value: "Schließen"}},
+	}
+	exported := decode(t, "todo.de.po", `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgctxt "h2"
+msgid "Cancel"
+msgstr "Abbrechen"
+
+msgctxt "h3"
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Eine Datei"
+msgstr[1] "%d Dateien"
+
+msgctxt "h4"
+msgid "Close"
+msgstr "Zumachen"
+
+msgctxt "h5"
+msgid "Removed"
+msgstr "Entfernt"
+
+msgctxt "h1"
+msgid "Save changes"
+msgstr "Änderungen speichern"
+
+msgctxt "h6"
+msgid "Pending"
+msgstr ""
+`)
+
+	result, modified, err := untranslated.Import([]gettext.FilePO{catalog}, exported)
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, modified)
+	require.Equal(t, untranslated.Result{
+		Imported:     2,
+		Untranslated: 1,
+		Missing:      []string{"h5"},
+		Changed:      []string{"h1"},
+		Conflicts:    []string{"h4"},
+	}, result)
+
+	l := catalog.Messages.List
+	require.Equal(t, "Speichern", l[0].Msgstr.Text.String())
+	require.Equal(t, "Abbrechen", l[1].Msgstr.Text.String())
+	require.Equal(t, "%d Dateien", l[2].Msgstr1.Text.String())
+	require.False(t, fuzzy.Is(&l[2]))
+	require.Equal(t, "Schließen", l[3].Msgstr.Text.String())
+	require.Empty(t, untranslated.Export(catalog).Messages.List)
+}
+
+func TestImportLocaleMismatch(t *testing.T) {
+	catalog := decode(t, "catalog.de.po", catalogDE)
+	exported := decode(t, "todo.fr.po", `msgid ""
+msgstr ""
+"Language: fr\n"
+
+msgctxt "h2"
+msgid "Cancel"
+msgstr "Annuler"
+`)
+	_, modified, err := untranslated.Import([]gettext.FilePO{catalog}, exported)
+	require.ErrorIs(t, err, untranslated.ErrLocaleMismatch)
+	require.Empty(t, modified)
+	require.Equal(t, "", catalog.Messages.List[1].Msgstr.Text.String())
+}
+
+func TestImportTokenized(t *testing.T) {
+	catalog := decode(t, "catalog.de.po", `msgid ""
+msgstr ""
+"Language: de\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgctxt "h1"
+msgid "Hello %s, you have %d messages"
+msgstr ""
+
+msgctxt "h2"
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+msgctxt "h3"
+msgid "%s reached %.2f%%"
+msgstr ""
+
+msgctxt "h4"
+msgid "Welcome, %s"
+msgstr ""
+`)
+	exported := untranslated.Export(catalog)
+	untranslated.Tokenize(exported)
+	require.True(t, untranslated.IsTokenized(exported))
+
+	s, err := gettext.Encoder{}.EncodePOToString(exported)
+	require.NoError(t, err)
+	require.Contains(t, s, `"X-Localize-Placeholders: tokens\n"`)
+	require.Contains(t, s, `msgid "Hello ⟦1⟧, you have ⟦2⟧ messages"`)
+	require.Contains(t, s, `msgid_plural "⟦1⟧ files"`)
+
+	// Translated by a tool unaware of Go fmt placeholders.
+	translated := decode(t, "todo.de.po", s)
+	l := translated.Messages.List
+	set := func(m *gettext.Msgstr, s string) {
+		m.Text = gettext.StringLiterals{Lines: []gettext.StringLiteral{{Value: s}}}
+	}
+	set(&l[0].Msgstr, "Hallo ⟦1⟧, du hast ⟦ 2 ⟧ Nachrichten")
+	set(&l[1].Msgstr0, "Eine Datei")
+	set(&l[1].Msgstr1, "⟦1⟧ Dateien")
+	set(&l[2].Msgstr, "⟦1⟧ erreichte ⟦2⟧⟦3⟧")
+	set(&l[3].Msgstr, "Willkommen, ⟦1")
+
+	result, modified, err := untranslated.Import([]gettext.FilePO{catalog}, translated)
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, modified)
+	require.Equal(t, 3, result.Imported)
+	require.Len(t, result.Invalid, 1)
+	require.Equal(t, "h4", result.Invalid[0].Hash)
+	require.ErrorIs(t, result.Invalid[0].Err, fmtplaceholder.ErrTokenCorrupted)
+
+	c := catalog.Messages.List
+	require.Equal(t, "Hallo %s, du hast %d Nachrichten", c[0].Msgstr.Text.String())
+	require.Equal(t, "Eine Datei", c[1].Msgstr0.Text.String())
+	require.Equal(t, "%d Dateien", c[1].Msgstr1.Text.String())
+	require.Equal(t, "%s erreichte %.2f%%", c[2].Msgstr.Text.String())
+	require.Equal(t, "", c[3].Msgstr.Text.String())
+}
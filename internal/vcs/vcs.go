@@ -0,0 +1,203 @@
+// Package vcs provides version control system integrations deriving
+// the provenance of translations from the history of catalog files.
+package vcs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknown   = errors.New("unknown version control system")
+	ErrMalformed = errors.New("malformed blame output")
+	ErrNotFound  = errors.New("file not found at revision")
+)
+
+// Origin is the commit a line was last changed in.
+type Origin struct {
+	Commit      string
+	Author      string
+	AuthorEmail string
+	Time        time.Time
+}
+
+// Committed returns false for lines that were changed but not committed yet.
+func (o Origin) Committed() bool {
+	return strings.Trim(o.Commit, "0") != ""
+}
+
+// Translator returns the author in the format of the gettext
+// Last-Translator header, like "Jane Doe <jane@example.com>".
+func (o Origin) Translator() string {
+	if o.AuthorEmail == "" {
+		return o.Author
+	}
+	return o.Author + " <" + o.AuthorEmail + ">"
+}
+
+// Blamer is implemented by version control systems.
+type Blamer interface {
+	// Blame returns the origins of all lines of file
+	// where index 0 is the origin of line 1.
+	Blame(file string) ([]Origin, error)
+
+	// Revision returns the current revision of the repository
+	// containing directory dir.
+	Revision(dir string) (string, error)
+}
+
+var blamers = map[string]Blamer{
+	"git": Git{},
+}
+
+// Names returns the names of all supported version control systems.
+func Names() []string {
+	names := make([]string, 0, len(blamers))
+	for n := range blamers {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ByName returns the Blamer of the version control system name.
+func ByName(name string) (Blamer, error) {
+	b, ok := blamers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknown, name)
+	}
+	return b, nil
+}
+
+// Latest returns the origin of the most recent committed change
+// to any of the given lines (starting at 1).
+// Returns false if none of the lines were committed yet.
+func Latest(origins []Origin, lines []int) (latest Origin, ok bool) {
+	for _, l := range lines {
+		if l < 1 || l > len(origins) {
+			continue
+		}
+		o := origins[l-1]
+		if o.Committed() && (!ok || o.Time.After(latest.Time)) {
+			latest, ok = o, true
+		}
+	}
+	return latest, ok
+}
+
+// Git blames files using the git executable.
+type Git struct{}
+
+var _ Blamer = Git{}
+
+func (Git) Blame(file string) ([]Origin, error) {
+	cmd := exec.Command("git", "blame", "--line-porcelain", "--", filepath.Base(file))
+	cmd.Dir = filepath.Dir(file)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git blame %s: %w: %s",
+			file, err, strings.TrimSpace(stderr.String()))
+	}
+	return parseLinePorcelain(bytes.NewReader(out))
+}
+
+func (Git) Revision(dir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse HEAD: %w: %s",
+			err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Show returns the contents of file at revision, such as a tag or a commit.
+// Returns an error wrapping ErrNotFound if file didn't exist at revision.
+func (Git) Show(file, revision string) ([]byte, error) {
+	dir, base := filepath.Dir(file), filepath.Base(file)
+	ls, err := git(dir, "ls-tree", "--name-only", revision, "--", base)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(ls)) < 1 {
+		return nil, fmt.Errorf("%w: %s at %s", ErrNotFound, file, revision)
+	}
+	// "./" makes the path relative to dir instead of the repository root.
+	return git(dir, "show", revision+":./"+base)
+}
+
+// git runs git with args in dir and returns its standard output.
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s",
+			args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// parseLinePorcelain parses the output of `git blame --line-porcelain`.
+func parseLinePorcelain(r io.Reader) ([]Origin, error) {
+	var origins []Origin
+	var current Origin
+	inHeader := false
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		line := s.Text()
+		if !inHeader {
+			// The first header line is "<commit> <orig line> <final line> [<n>]".
+			commit, _, _ := strings.Cut(line, " ")
+			if len(commit) < 40 {
+				return nil, fmt.Errorf("%w: expected commit, received: %q",
+					ErrMalformed, line)
+			}
+			current, inHeader = Origin{Commit: commit}, true
+			continue
+		}
+		if strings.HasPrefix(line, "\t") {
+			// The line content terminates the header.
+			origins = append(origins, current)
+			inHeader = false
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.AuthorEmail = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case "author-time":
+			sec, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: author-time: %w", ErrMalformed, err)
+			}
+			current.Time = time.Unix(sec, 0)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("reading blame output: %w", err)
+	}
+	if inHeader {
+		return nil, fmt.Errorf("%w: unexpected end of output", ErrMalformed)
+	}
+	return origins, nil
+}
@@ -0,0 +1,134 @@
+package vcs_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/romshark/localize/internal/vcs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByName(t *testing.T) {
+	b, err := vcs.ByName("git")
+	require.NoError(t, err)
+	require.Equal(t, vcs.Git{}, b)
+
+	_, err = vcs.ByName("svn")
+	require.ErrorIs(t, err, vcs.ErrUnknown)
+
+	require.Equal(t, []string{"git"}, vcs.Names())
+}
+
+func TestLatest(t *testing.T) {
+	uncommitted := vcs.Origin{
+		Commit: "0000000000000000000000000000000000000000",
+		Author: "Not Committed Yet",
+		Time:   time.Unix(300, 0),
+	}
+	a := vcs.Origin{Commit: "a", Author: "A", Time: time.Unix(100, 0)}
+	b := vcs.Origin{Commit: "b", Author: "B", Time: time.Unix(200, 0)}
+	origins := []vcs.Origin{a, b, uncommitted, a}
+
+	l, ok := vcs.Latest(origins, []int{1, 2, 3, 4})
+	require.True(t, ok)
+	require.Equal(t, b, l)
+
+	l, ok = vcs.Latest(origins, []int{1, 4, 99})
+	require.True(t, ok)
+	require.Equal(t, a, l)
+
+	_, ok = vcs.Latest(origins, []int{3})
+	require.False(t, ok)
+	_, ok = vcs.Latest(origins, nil)
+	require.False(t, ok)
+}
+
+// gitIn returns a function running git in dir as author.
+func gitIn(t *testing.T, dir string) func(author string, args ...string) {
+	return func(author string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL="+author+"@example.com",
+			"GIT_COMMITTER_NAME="+author, "GIT_COMMITTER_EMAIL="+author+"@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+}
+
+func TestGitBlame(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := gitIn(t, dir)
+	file := filepath.Join(dir, "catalog.de.po")
+	write := func(content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	}
+
+	git("", "init", "-q")
+	write("first\nsecond\n")
+	git("alice", "add", ".")
+	git("alice", "commit", "-q", "-m", "first")
+	write("first\nchanged\n")
+	git("bob", "commit", "-q", "-a", "-m", "second")
+	write("first\nchanged\nnot committed\n")
+
+	origins, err := vcs.Git{}.Blame(file)
+	require.NoError(t, err)
+	require.Len(t, origins, 3)
+	require.Equal(t, "alice", origins[0].Author)
+	require.Equal(t, "alice@example.com", origins[0].AuthorEmail)
+	require.Equal(t, "bob", origins[1].Author)
+	require.Equal(t, "bob <bob@example.com>", origins[1].Translator())
+	require.True(t, origins[1].Committed())
+	require.NotEqual(t, origins[0].Commit, origins[1].Commit)
+	require.False(t, origins[2].Committed())
+
+	rev, err := vcs.Git{}.Revision(dir)
+	require.NoError(t, err)
+	require.Equal(t, origins[1].Commit, rev)
+
+	_, err = vcs.Git{}.Blame(filepath.Join(dir, "untracked.po"))
+	require.Error(t, err)
+}
+
+func TestGitShow(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := gitIn(t, dir)
+	sub := filepath.Join(dir, "localizebundle")
+	require.NoError(t, os.Mkdir(sub, 0o755))
+	file := filepath.Join(sub, "source.en.po")
+
+	git("", "init", "-q")
+	require.NoError(t, os.WriteFile(file, []byte("first\n"), 0o644))
+	git("alice", "add", ".")
+	git("alice", "commit", "-q", "-m", "first")
+	git("alice", "tag", "v1.0.0")
+	require.NoError(t, os.WriteFile(file, []byte("second\n"), 0o644))
+	git("alice", "commit", "-q", "-a", "-m", "second")
+
+	b, err := vcs.Git{}.Show(file, "v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "first\n", string(b))
+	b, err = vcs.Git{}.Show(file, "HEAD")
+	require.NoError(t, err)
+	require.Equal(t, "second\n", string(b))
+
+	_, err = vcs.Git{}.Show(filepath.Join(sub, "catalog.de.po"), "v1.0.0")
+	require.ErrorIs(t, err, vcs.ErrNotFound)
+	_, err = vcs.Git{}.Show(file, "v9.9.9")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, vcs.ErrNotFound)
+}
@@ -0,0 +1,74 @@
+// Package whereis finds the source messages of translated texts,
+// for example texts reported from production, by searching all catalogs.
+package whereis
+
+import (
+	"strings"
+
+	"github.com/romshark/localize/internal/fmtplaceholder"
+	"github.com/romshark/localize/internal/gendocs"
+)
+
+// Match is a text of a catalog matching the query.
+type Match struct {
+	// Locale is the locale of the catalog the text was found in.
+	Locale string
+
+	// Form is the matching text of the message.
+	// Form.Name is empty for static messages.
+	Form gendocs.Form
+
+	// Message is the source message of the matching text.
+	Message *gendocs.Message
+}
+
+// Find returns all texts of the catalogs of site matching query
+// ordered by message hash and locale with the source locale first.
+// If locale isn't empty only the catalog of locale is searched.
+//
+// Texts match if they contain query or format to query, such that
+// "Du hast 5 Nachrichten" matches "Du hast %d Nachrichten".
+// Matching ignores case and differences in whitespace.
+func Find(site *gendocs.Site, query, locale string) []Match {
+	query = normalize(query)
+	if query == "" {
+		return nil
+	}
+	var matches []Match
+	find := func(m *gendocs.Message, locale string, forms []gendocs.Form) {
+		for _, f := range forms {
+			if matchText(query, f.Text) {
+				matches = append(matches, Match{Locale: locale, Form: f, Message: m})
+			}
+		}
+	}
+	for i := range site.Messages {
+		m := &site.Messages[i]
+		if locale == "" || locale == site.SourceLocale {
+			find(m, site.SourceLocale, m.Source)
+		}
+		for _, t := range m.Translations {
+			if t.Translated && (locale == "" || locale == t.Locale) {
+				find(m, t.Locale, t.Forms)
+			}
+		}
+	}
+	return matches
+}
+
+// matchText returns true if text contains normalized query
+// or formats to normalized query.
+func matchText(query, text string) bool {
+	text = normalize(text)
+	if text == "" {
+		return false
+	}
+	return strings.Contains(text, query) ||
+		fmtplaceholder.Pattern(text).MatchString(query)
+}
+
+// normalize lower-cases s and replaces all whitespace sequences
+// with a single space.
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
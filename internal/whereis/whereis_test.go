@@ -0,0 +1,65 @@
+package whereis_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/gendocs"
+	"github.com/romshark/localize/internal/whereis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	site := &gendocs.Site{
+		SourceLocale: "en",
+		Messages: []gendocs.Message{
+			{
+				Hash:       "a",
+				Source:     []gendocs.Form{{Text: "Welcome back"}},
+				References: []string{"main.go:10"},
+				Translations: []gendocs.Translation{
+					{Locale: "de", Translated: true, Forms: []gendocs.Form{
+						{Text: "Willkommen zurück"},
+					}},
+					{Locale: "fr"},
+				},
+			},
+			{
+				Hash: "b",
+				Source: []gendocs.Form{
+					{Name: "one", Text: "You have %d message"},
+					{Name: "other", Text: "You have %d messages"},
+				},
+				References: []string{"main.go:20", "inbox.go:5"},
+				Translations: []gendocs.Translation{
+					{Locale: "de", Translated: true, Forms: []gendocs.Form{
+						{Name: "one", Text: "Du hast %d Nachricht"},
+						{Name: "other", Text: "Du hast %d Nachrichten"},
+					}},
+				},
+			},
+		},
+	}
+
+	type match struct{ Locale, Hash, Form string }
+	find := func(query, locale string) (l []match) {
+		for _, m := range whereis.Find(site, query, locale) {
+			l = append(l, match{m.Locale, m.Message.Hash, m.Form.Name})
+		}
+		return l
+	}
+
+	require.Equal(t, []match{{"de", "a", ""}}, find("Willkommen zurück", ""))
+	require.Equal(t, []match{{"de", "a", ""}}, find("  willkommen\tZURÜCK ", ""))
+	require.Equal(t, []match{{"de", "a", ""}}, find("Willkommen", "de"))
+	require.Nil(t, find("Willkommen", "fr"))
+	require.Equal(t, []match{{"en", "a", ""}}, find("welcome back", ""))
+	require.Equal(t, []match{{"de", "b", "other"}}, find("Du hast 42 Nachrichten", ""))
+	require.Equal(t, []match{
+		{"de", "b", "one"}, {"de", "b", "other"},
+	}, find("Du hast", ""))
+	require.Equal(t, []match{
+		{"en", "b", "one"}, {"en", "b", "other"},
+	}, find("You have", "en"))
+	require.Nil(t, find("", ""))
+	require.Nil(t, find("Hallo", ""))
+}
@@ -0,0 +1,524 @@
+// Package xtextimport converts the message catalogs of golang.org/x/text/message
+// to localize translation catalogs and finds the message.Printer calls
+// to convert to Reader calls.
+//
+// Catalogs are read from the gotext JSON files (`messages.gotext.json` and
+// `out.gotext.json`) of the gotext tool. Messages whose translations select
+// plural cases become plural messages, all other messages become static
+// messages formatted with fmt.
+package xtextimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/fmtplaceholder"
+	"github.com/romshark/localize/internal/msgimport"
+	"github.com/romshark/localize/internal/pluralcheck"
+	"golang.org/x/text/language"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	ErrNoLocale       = errors.New("catalog has no language")
+	ErrInvalidCatalog = errors.New("invalid gotext catalog")
+)
+
+// messagePackage is the import path of the x/text message package.
+const messagePackage = "golang.org/x/text/message"
+
+// Catalog is a gotext JSON catalog.
+type Catalog struct {
+	Locale   language.Tag
+	Messages []Message
+}
+
+// Message is a message of a gotext JSON catalog.
+type Message struct {
+	// Key is the format string of the message in the source code.
+	Key string `json:"key"`
+
+	// Message is the source text with placeholders like "{N}".
+	Message Text `json:"message"`
+
+	// Translation is the translation with placeholders like "{N}".
+	Translation Text `json:"translation"`
+
+	// Comment is the comment of the call site in the source code.
+	Comment string `json:"comment"`
+
+	Placeholders []Placeholder `json:"placeholders"`
+}
+
+// Placeholder is a placeholder of a message.
+type Placeholder struct {
+	// ID is the name of the placeholder used in texts like "N" for "{N}".
+	ID string `json:"id"`
+
+	// String is the Go fmt placeholder like "%[1]d".
+	String string `json:"string"`
+
+	// ArgNum is the index of the argument starting at 1.
+	ArgNum int `json:"argNum"`
+}
+
+// Text is either a plain text or a selection of texts by plural case.
+type Text struct {
+	Msg    string  `json:"msg"`
+	Select *Select `json:"select"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler for texts
+// that are either strings or objects.
+func (t *Text) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		return json.Unmarshal(b, &t.Msg)
+	}
+	type rawText Text
+	return json.Unmarshal(b, (*rawText)(t))
+}
+
+// Select selects a text by the feature of an argument.
+type Select struct {
+	// Feature is "plural" for selections by plural case.
+	Feature string `json:"feature"`
+
+	// Arg is the placeholder ID of the argument the selection depends on.
+	Arg string `json:"arg"`
+
+	// Cases are the texts by case like "one", "other" or "=0".
+	Cases map[string]Text `json:"cases"`
+}
+
+// ParseCatalog parses the gotext JSON catalog at path with contents data.
+// The locale is read from the catalog or, if the catalog has none, from
+// the name of the directory containing it like "de" in "locales/de/out.gotext.json".
+func ParseCatalog(path string, data []byte) (*Catalog, error) {
+	var f struct {
+		Language string    `json:"language"`
+		Messages []Message `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCatalog, err)
+	}
+	lang := f.Language
+	if lang == "" {
+		lang = filepath.Base(filepath.Dir(path))
+	}
+	locale, err := language.Parse(lang)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrNoLocale, path)
+	}
+	return &Catalog{Locale: locale, Messages: f.Messages}, nil
+}
+
+// Forms are the texts of a message by CLDR plural form.
+type Forms = msgimport.Forms
+
+// Conversion is a message of the source locale converted to a localize message.
+type Conversion struct {
+	// Key is the format string of the message in the source code.
+	Key string
+
+	// Description is the comment of the call site.
+	Description string
+
+	// Hash is the hash of the converted message.
+	Hash string
+
+	// Plural is true for messages converted to Reader.Plural calls.
+	Plural bool
+
+	// Forms are the converted texts. Only Other is set for static messages.
+	Forms Forms
+
+	// Notes are the issues requiring manual work.
+	Notes []string
+}
+
+// Convert converts the messages of the source catalog. translations
+// are the catalogs of all other locales, a message becomes a plural message
+// if its translation in any catalog selects a plural case.
+// Conversions are returned ordered by key.
+func Convert(source *Catalog, translations []*Catalog) []Conversion {
+	plural := map[string]bool{}
+	for _, c := range append([]*Catalog{source}, translations...) {
+		for _, m := range c.Messages {
+			if isPlural(m.Translation) {
+				plural[keyOf(m)] = true
+			}
+		}
+	}
+
+	var l []Conversion
+	for _, m := range source.Messages {
+		c := Conversion{
+			Key:         keyOf(m),
+			Description: m.Comment,
+			Plural:      plural[keyOf(m)],
+		}
+		text := m.Translation
+		if text.Msg == "" && text.Select == nil {
+			text = m.Message
+		}
+		if c.Plural && len(m.Placeholders) != 1 {
+			c.Notes = append(c.Notes,
+				"plural messages must have exactly one argument, the quantity")
+			c.Plural = false
+		}
+		if c.Plural {
+			c.Forms, c.Notes = convertForms(m, text)
+			if c.Forms.Other == "" {
+				c.Forms.Other = quantityPlaceholder(c.Key)
+			}
+			if text.Select == nil {
+				c.Notes = append(c.Notes,
+					"the source catalog has no plural cases, add the source forms")
+			}
+			for _, s := range []string{
+				c.Forms.Zero, c.Forms.One, c.Forms.Two,
+				c.Forms.Few, c.Forms.Many, c.Forms.Other,
+			} {
+				if s == "" {
+					continue
+				}
+				for _, err := range pluralcheck.Template(s) {
+					c.Notes = append(c.Notes, fmt.Sprintf("%q: %v", s, err))
+				}
+			}
+		} else {
+			c.Forms = Forms{Other: c.Key}
+		}
+		c.Hash = codeparser.MessageHash(c.Forms.Other, c.Description)
+		l = append(l, c)
+	}
+	slices.SortFunc(l, func(a, b Conversion) int { return strings.Compare(a.Key, b.Key) })
+	return slices.CompactFunc(l, func(a, b Conversion) bool { return a.Key == b.Key })
+}
+
+// keyOf returns the format string of m.
+func keyOf(m Message) string {
+	if m.Key != "" {
+		return m.Key
+	}
+	return expand(m.Message.Msg, m.Placeholders, false)
+}
+
+func isPlural(t Text) bool {
+	return t.Select != nil && t.Select.Feature == "plural"
+}
+
+// placeholderRef matches placeholder references like "{N}".
+var placeholderRef = regexp.MustCompile(`{([A-Za-z_][A-Za-z0-9_]*)}`)
+
+// expand replaces the placeholder references in s with their Go fmt
+// placeholders. The quantity placeholder of plural messages is "%d".
+func expand(s string, placeholders []Placeholder, plural bool) string {
+	return placeholderRef.ReplaceAllStringFunc(s, func(ref string) string {
+		id := ref[1 : len(ref)-1]
+		i := slices.IndexFunc(placeholders, func(p Placeholder) bool {
+			return p.ID == id
+		})
+		if i < 0 {
+			return ref
+		}
+		if plural {
+			return "%d"
+		}
+		return placeholders[i].String
+	})
+}
+
+// quantityPlaceholder returns key with its only placeholder replaced by "%d".
+func quantityPlaceholder(key string) string {
+	p := fmtplaceholder.Extract(key)
+	for _, s := range p {
+		if s != "%%" {
+			return strings.Replace(key, s, "%d", 1)
+		}
+	}
+	return key
+}
+
+// convertForms returns the forms of the plural message m with text t.
+func convertForms(m Message, t Text) (f Forms, notes []string) {
+	if t.Select == nil {
+		return Forms{Other: expand(t.Msg, m.Placeholders, true)}, nil
+	}
+	for c, text := range t.Select.Cases {
+		s := expand(text.Msg, m.Placeholders, true)
+		switch c {
+		case "zero":
+			f.Zero = s
+		case "one":
+			f.One = s
+		case "two":
+			f.Two = s
+		case "few":
+			f.Few = s
+		case "many":
+			f.Many = s
+		case "other":
+			f.Other = s
+		default:
+			notes = append(notes, fmt.Sprintf("plural case %q isn't supported", c))
+		}
+	}
+	slices.Sort(notes)
+	return f, notes
+}
+
+// MakeCatalog returns the translation catalog of c for the messages
+// of conversions. Messages without translation are left untranslated.
+// notes are the issues of the converted translations.
+func MakeCatalog(
+	c *Catalog, conversions []Conversion,
+) (po gettext.FilePO, notes []string, err error) {
+	messages := make([]msgimport.Message, len(conversions))
+	byHash := make(map[string]*Conversion, len(conversions))
+	for i := range conversions {
+		conv := &conversions[i]
+		messages[i] = msgimport.Message{
+			Hash: conv.Hash, Description: conv.Description,
+			Plural: conv.Plural, Forms: conv.Forms,
+		}
+		byHash[conv.Hash] = conv
+	}
+	byKey := make(map[string]Message, len(c.Messages))
+	for _, m := range c.Messages {
+		byKey[keyOf(m)] = m
+	}
+
+	po, err = msgimport.Catalog(c.Locale, messages, func(hash string) Forms {
+		conv := byHash[hash]
+		t := byKey[conv.Key]
+		if !conv.Plural {
+			if isPlural(t.Translation) {
+				notes = append(notes, conv.Key+": plural cases aren't supported")
+			}
+			return Forms{Other: expand(t.Translation.Msg, t.Placeholders, false)}
+		}
+		f, n := convertForms(t, t.Translation)
+		for _, note := range n {
+			notes = append(notes, conv.Key+": "+note)
+		}
+		return f
+	})
+	return po, notes, err
+}
+
+// CallSite is a call of a translating message.Printer method
+// (Printf, Sprintf or Fprintf).
+type CallSite struct {
+	Pos token.Position
+
+	// Method is the name of the called method.
+	Method string
+
+	// Receiver, Writer and Args are the source code of the receiver,
+	// the writer argument of Fprintf and the formatted arguments.
+	Receiver, Writer string
+	Args             []string
+
+	// Key is the format string. Key is empty if the format string isn't
+	// a constant string or a message.Key call with constant arguments.
+	Key string
+}
+
+// Scan returns the translating message.Printer method calls of all packages
+// matching pathPattern and their subpackages ordered by position.
+func Scan(ctx context.Context, pathPattern string) ([]CallSite, error) {
+	fset := token.NewFileSet()
+	pkgs, err := packages.Load(&packages.Config{
+		Context: ctx,
+		Mode: packages.NeedFiles |
+			packages.NeedSyntax |
+			packages.NeedTypes |
+			packages.NeedTypesInfo |
+			packages.NeedName |
+			packages.NeedDeps,
+		Fset: fset,
+	}, pathPattern+"/...")
+	if err != nil {
+		return nil, err
+	}
+	var l []CallSite
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, pkg.Errors[0]
+		}
+		l = append(l, FindCallSites(fset, pkg.Syntax, pkg.TypesInfo)...)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		// Report paths relative to the working directory.
+		for i := range l {
+			if rel, err := filepath.Rel(wd, l[i].Pos.Filename); err == nil {
+				l[i].Pos.Filename = rel
+			}
+		}
+	}
+	slices.SortFunc(l, compareCallSites)
+	return l, nil
+}
+
+// FindCallSites returns all translating message.Printer method calls
+// in files ordered by position.
+func FindCallSites(
+	fset *token.FileSet, files []*ast.File, info *types.Info,
+) []CallSite {
+	var l []CallSite
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			fn, ok := info.Uses[sel.Sel].(*types.Func)
+			if !ok {
+				return true
+			}
+			keyIndex := 0
+			switch fn.FullName() {
+			case "(*" + messagePackage + ".Printer).Printf",
+				"(*" + messagePackage + ".Printer).Sprintf":
+			case "(*" + messagePackage + ".Printer).Fprintf":
+				keyIndex = 1
+			default:
+				return true
+			}
+			if len(call.Args) <= keyIndex {
+				return true
+			}
+			c := CallSite{
+				Pos:      fset.Position(call.Pos()),
+				Method:   fn.Name(),
+				Receiver: types.ExprString(sel.X),
+				Key:      keyOfExpr(call.Args[keyIndex], info),
+			}
+			if keyIndex > 0 {
+				c.Writer = types.ExprString(call.Args[0])
+			}
+			for _, a := range call.Args[keyIndex+1:] {
+				c.Args = append(c.Args, types.ExprString(a))
+			}
+			l = append(l, c)
+			return true
+		})
+	}
+	slices.SortFunc(l, compareCallSites)
+	return l
+}
+
+func compareCallSites(a, b CallSite) int {
+	if c := strings.Compare(a.Pos.Filename, b.Pos.Filename); c != 0 {
+		return c
+	}
+	return a.Pos.Offset - b.Pos.Offset
+}
+
+// keyOfExpr returns the format string of key expression e.
+// The fallback text is the format string of message.Key calls.
+func keyOfExpr(e ast.Expr, info *types.Info) string {
+	if call, ok := ast.Unparen(e).(*ast.CallExpr); ok && len(call.Args) == 2 {
+		if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+			if fn, ok := info.Uses[sel.Sel].(*types.Func); ok &&
+				fn.FullName() == messagePackage+".Key" {
+				e = call.Args[1]
+			}
+		}
+	}
+	tv := info.Types[e]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
+
+// WriteReport writes the call sites to convert with the suggested Reader
+// calls replacing them to w, such as:
+//
+//	main.go:12:2: p.Printf
+//		fmt.Print(r.Plural(localize.Forms{
+//			One:   "%d file",
+//			Other: "%d files",
+//		}, n))
+//
+// Notes of the converted messages are written as lines starting with "!".
+func WriteReport(w io.Writer, sites []CallSite, conversions []Conversion) error {
+	byKey := make(map[string]*Conversion, len(conversions))
+	for i := range conversions {
+		byKey[conversions[i].Key] = &conversions[i]
+	}
+	var b strings.Builder
+	for i, s := range sites {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s: %s.%s\n", s.Pos, s.Receiver, s.Method)
+		if s.Key == "" {
+			b.WriteString("\t! the format string isn't constant\n")
+			continue
+		}
+		c, ok := byKey[s.Key]
+		if !ok {
+			c = &Conversion{Key: s.Key, Forms: Forms{Other: s.Key}}
+			b.WriteString("\t! the message isn't in the catalog of the source locale\n")
+		}
+		for _, n := range c.Notes {
+			b.WriteString("\t! " + n + "\n")
+		}
+		writeCall(&b, s, c)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCall writes the call replacing s using the converted message c.
+func writeCall(b *strings.Builder, s CallSite, c *Conversion) {
+	if c.Plural && len(s.Args) == 1 {
+		call := msgimport.PluralCall(c.Forms, s.Args[0])
+		switch s.Method {
+		case "Printf":
+			b.WriteString("\tfmt.Print(" + call + ")\n")
+		case "Fprintf":
+			b.WriteString("\tfmt.Fprint(" + s.Writer + ", " + call + ")\n")
+		default:
+			b.WriteString("\t" + call + "\n")
+		}
+		return
+	}
+	text := "r.Text(" + strconv.Quote(c.Forms.Other) + ")"
+	args := ""
+	if len(s.Args) > 0 {
+		args = ", " + strings.Join(s.Args, ", ")
+	}
+	switch {
+	case s.Method == "Printf":
+		b.WriteString("\tfmt.Printf(" + text + args + ")\n")
+	case s.Method == "Fprintf":
+		b.WriteString("\tfmt.Fprintf(" + s.Writer + ", " + text + args + ")\n")
+	case args == "":
+		b.WriteString("\t" + text + "\n")
+	default:
+		b.WriteString("\tfmt.Sprintf(" + text + args + ")\n")
+	}
+}
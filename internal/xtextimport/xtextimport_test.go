@@ -0,0 +1,291 @@
+package xtextimport_test
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/romshark/localize/gettext"
+	"github.com/romshark/localize/internal/codeparser"
+	"github.com/romshark/localize/internal/xtextimport"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+const sourceCatalog = `{
+	"language": "en",
+	"messages": [
+		{
+			"id": "Hello {Name}!",
+			"key": "Hello %s!",
+			"message": "Hello {Name}!",
+			"translation": "",
+			"comment": "Home page.",
+			"placeholders": [
+				{"id": "Name", "string": "%[1]s", "type": "string", "argNum": 1, "expr": "name"}
+			]
+		},
+		{
+			"id": "{Files} files",
+			"key": "%d files",
+			"message": "{Files} files",
+			"translation": {
+				"select": {
+					"feature": "plural",
+					"arg": "Files",
+					"cases": {
+						"one": {"msg": "{Files} file"},
+						"other": {"msg": "{Files} files"}
+					}
+				}
+			},
+			"placeholders": [
+				{"id": "Files", "string": "%[1]d", "type": "int", "argNum": 1, "expr": "n"}
+			]
+		},
+		{
+			"id": "Bye",
+			"message": "Bye",
+			"translation": ""
+		}
+	]
+}`
+
+const germanCatalog = `{
+	"language": "de",
+	"messages": [
+		{
+			"id": "Hello {Name}!",
+			"key": "Hello %s!",
+			"message": "Hello {Name}!",
+			"translation": "Hallo {Name}!",
+			"placeholders": [
+				{"id": "Name", "string": "%[1]s", "type": "string", "argNum": 1, "expr": "name"}
+			]
+		},
+		{
+			"id": "{Files} files",
+			"key": "%d files",
+			"message": "{Files} files",
+			"translation": {
+				"select": {
+					"feature": "plural",
+					"arg": "Files",
+					"cases": {
+						"=0": {"msg": "keine Dateien"},
+						"one": {"msg": "{Files} Datei"},
+						"other": {"msg": "{Files} Dateien"}
+					}
+				}
+			},
+			"placeholders": [
+				{"id": "Files", "string": "%[1]d", "type": "int", "argNum": 1, "expr": "n"}
+			]
+		}
+	]
+}`
+
+func parse(t *testing.T, path, data string) *xtextimport.Catalog {
+	t.Helper()
+	c, err := xtextimport.ParseCatalog(path, []byte(data))
+	require.NoError(t, err)
+	return c
+}
+
+func TestParseCatalog(t *testing.T) {
+	c := parse(t, "locales/en/out.gotext.json", sourceCatalog)
+	require.Equal(t, language.English, c.Locale)
+	require.Len(t, c.Messages, 3)
+	require.Equal(t, "Hello %s!", c.Messages[0].Key)
+	require.Equal(t, xtextimport.Text{Msg: "Hello {Name}!"}, c.Messages[0].Message)
+	require.Equal(t, "plural", c.Messages[1].Translation.Select.Feature)
+
+	// The locale of catalogs without language is the name of the directory.
+	c = parse(t, "locales/de/messages.gotext.json", `{"messages": []}`)
+	require.Equal(t, language.German, c.Locale)
+
+	_, err := xtextimport.ParseCatalog("messages.gotext.json", []byte(`{}`))
+	require.ErrorIs(t, err, xtextimport.ErrNoLocale)
+	_, err = xtextimport.ParseCatalog("en/messages.gotext.json", []byte(`[`))
+	require.ErrorIs(t, err, xtextimport.ErrInvalidCatalog)
+}
+
+func TestConvert(t *testing.T) {
+	c := xtextimport.Convert(parse(t, "en/out.gotext.json", sourceCatalog), nil)
+	require.Len(t, c, 3)
+
+	require.Equal(t, "%d files", c[0].Key)
+	require.True(t, c[0].Plural)
+	require.Equal(t, xtextimport.Forms{One: "%d file", Other: "%d files"}, c[0].Forms)
+	require.Empty(t, c[0].Notes)
+	require.Equal(t, codeparser.MessageHash("%d files", ""), c[0].Hash)
+
+	require.Equal(t, "Bye", c[1].Key)
+	require.False(t, c[1].Plural)
+
+	require.Equal(t, "Hello %s!", c[2].Key)
+	require.Equal(t, "Home page.", c[2].Description)
+	require.Equal(t, xtextimport.Forms{Other: "Hello %s!"}, c[2].Forms)
+	require.Equal(t, codeparser.MessageHash("Hello %s!", "Home page."), c[2].Hash)
+
+	// Messages selecting plural cases only in translations are plural messages
+	// with a note asking for the source forms.
+	source := parse(t, "en/messages.gotext.json", `{"messages": [{
+		"id": "{N} days", "key": "%d days", "message": "{N} days",
+		"placeholders": [{"id": "N", "string": "%[1]d", "argNum": 1}]
+	}]}`)
+	c = xtextimport.Convert(source, []*xtextimport.Catalog{
+		parse(t, "de/out.gotext.json", germanCatalog),
+		parse(t, "fr/out.gotext.json", `{"messages": [{
+			"key": "%d days",
+			"translation": {"select": {"feature": "plural", "arg": "N", "cases": {}}}
+		}]}`),
+	})
+	require.Len(t, c, 1)
+	require.True(t, c[0].Plural)
+	require.Equal(t, xtextimport.Forms{Other: "%d days"}, c[0].Forms)
+	require.Equal(t, []string{
+		"the source catalog has no plural cases, add the source forms",
+	}, c[0].Notes)
+}
+
+func TestMakeCatalog(t *testing.T) {
+	c := xtextimport.Convert(parse(t, "en/out.gotext.json", sourceCatalog), nil)
+	po, notes, err := xtextimport.MakeCatalog(
+		parse(t, "de/out.gotext.json", germanCatalog), c,
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{`%d files: plural case "=0" isn't supported`}, notes)
+
+	var buf bytes.Buffer
+	require.NoError(t, gettext.Encoder{}.EncodePO(po, &buf))
+	require.Equal(t, `msgid ""
+msgstr ""
+"Language: de\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+
+#. Home page.
+msgctxt "`+c[2].Hash+`"
+msgid "Hello %s!"
+msgstr "Hallo %[1]s!"
+
+msgctxt "`+c[0].Hash+`"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] "%d Dateien"
+
+msgctxt "`+c[1].Hash+`"
+msgid "Bye"
+msgstr ""
+`, buf.String())
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+func TestFindCallSites(t *testing.T) {
+	const stub = `package message
+type Printer struct{}
+type Reference any
+func (*Printer) Printf(key Reference, a ...any) (int, error) { return 0, nil }
+func (*Printer) Sprintf(key Reference, a ...any) string { return "" }
+func (*Printer) Fprintf(w any, key Reference, a ...any) (int, error) { return 0, nil }
+func (*Printer) Sprint(a ...any) string { return "" }
+func Key(id string, fallback string) Reference { return nil }
+`
+	const src = `package p
+
+import "golang.org/x/text/message"
+
+const greeting = "Hello %s!"
+
+func f(p *message.Printer, w any, name, key string, n int) {
+	p.Printf(greeting, name)
+	_ = p.Sprintf("%d files", n)
+	p.Fprintf(w, message.Key("bye", "Bye"))
+	_ = p.Sprintf(key)
+	_ = p.Sprint("not translated")
+}
+`
+	fset := token.NewFileSet()
+	stubFile, err := parser.ParseFile(fset, "message.go", stub, 0)
+	require.NoError(t, err)
+	message, err := new(types.Config).Check(
+		"golang.org/x/text/message", fset, []*ast.File{stubFile}, nil,
+	)
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{Importer: importerFunc(func(string) (*types.Package, error) {
+		return message, nil
+	})}
+	_, err = conf.Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	sites := xtextimport.FindCallSites(fset, []*ast.File{file}, info)
+	for i := range sites {
+		sites[i].Pos = token.Position{Line: sites[i].Pos.Line}
+	}
+	require.Equal(t, []xtextimport.CallSite{
+		{
+			Pos: token.Position{Line: 8}, Method: "Printf", Receiver: "p",
+			Args: []string{"name"}, Key: "Hello %s!",
+		},
+		{
+			Pos: token.Position{Line: 9}, Method: "Sprintf", Receiver: "p",
+			Args: []string{"n"}, Key: "%d files",
+		},
+		{
+			Pos: token.Position{Line: 10}, Method: "Fprintf", Receiver: "p",
+			Writer: "w", Key: "Bye",
+		},
+		{Pos: token.Position{Line: 11}, Method: "Sprintf", Receiver: "p"},
+	}, sites)
+}
+
+func TestWriteReport(t *testing.T) {
+	c := xtextimport.Convert(parse(t, "en/out.gotext.json", sourceCatalog), nil)
+	pos := func(line int) token.Position {
+		return token.Position{Filename: "p.go", Line: line, Column: 2}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, xtextimport.WriteReport(&buf, []xtextimport.CallSite{
+		{Pos: pos(1), Method: "Printf", Receiver: "p", Args: []string{"name"}, Key: "Hello %s!"},
+		{Pos: pos(2), Method: "Sprintf", Receiver: "p", Args: []string{"n"}, Key: "%d files"},
+		{Pos: pos(3), Method: "Fprintf", Receiver: "p", Writer: "w", Key: "Bye"},
+		{Pos: pos(4), Method: "Sprintf", Receiver: "p.x", Args: []string{"a"}, Key: "Unknown %s"},
+		{Pos: pos(5), Method: "Sprintf", Receiver: "p"},
+	}, c))
+	require.Equal(t, `p.go:1:2: p.Printf
+	fmt.Printf(r.Text("Hello %s!"), name)
+
+p.go:2:2: p.Sprintf
+	r.Plural(localize.Forms{
+		One:   "%d file",
+		Other: "%d files",
+	}, n)
+
+p.go:3:2: p.Fprintf
+	fmt.Fprintf(w, r.Text("Bye"))
+
+p.go:4:2: p.x.Sprintf
+	! the message isn't in the catalog of the source locale
+	fmt.Sprintf(r.Text("Unknown %s"), a)
+
+p.go:5:2: p.Sprintf
+	! the format string isn't constant
+`, buf.String())
+}
@@ -0,0 +1,26 @@
+// Package xtexttest provides readers of golang.org/x/text catalogs
+// for the tests of packages using localize.Reader.
+package xtexttest
+
+import (
+	"testing"
+
+	"github.com/go-playground/locales/de"
+	"github.com/romshark/localize/xtextcatalog"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+// German returns a German reader of a catalog of the translations
+// of messages by key, which are either plain texts (see catalog.String)
+// or plural messages (see plural.Selectf).
+func German(t testing.TB, messages map[string]catalog.Message) *xtextcatalog.Reader {
+	t.Helper()
+	b := catalog.NewBuilder()
+	for key, m := range messages {
+		if err := b.Set(language.German, key, m); err != nil {
+			t.Fatalf("setting message %q: %v", key, err)
+		}
+	}
+	return xtextcatalog.NewReader(b, language.German, de.New())
+}
@@ -0,0 +1,99 @@
+package localize
+
+// KeyedReader wraps a Reader and additionally returns the key of the message
+// producing a localized string, which is the message hash (see Key.Hash).
+// Keys are identical for all locales, such that applications can log and
+// trace the key alongside the localized output:
+//
+//	s, key := keyed.TextKeyed("Payment failed.")
+//	logger.Error(s, slog.String("msg", key))
+//
+// The key is empty if the message can't be found in the catalog or
+// neither the wrapped reader nor any reader wrapped by it implements Cataloger.
+// KeyedReader is safe for concurrent use.
+type KeyedReader struct {
+	Reader
+	hashByStatic map[string]string
+	hashByPlural map[string]string
+}
+
+var _ Reader = new(KeyedReader)
+
+// NewKeyedReader creates a new keyed reader wrapping r.
+func NewKeyedReader(r Reader) *KeyedReader {
+	k := &KeyedReader{
+		Reader:       r,
+		hashByStatic: map[string]string{},
+		hashByPlural: map[string]string{},
+	}
+	if c, ok := findCataloger(r); ok {
+		for key, t := range c.Messages() {
+			if t.Plural {
+				k.hashByPlural[key.Source] = key.Hash
+				continue
+			}
+			k.hashByStatic[key.Source] = key.Hash
+		}
+	}
+	return k
+}
+
+// Unwrap returns the wrapped reader.
+func (k *KeyedReader) Unwrap() Reader { return k.Reader }
+
+// TextKeyed calls Text on the wrapped reader and returns its result
+// and the key of the message.
+func (k *KeyedReader) TextKeyed(text string) (localized, key string) {
+	return k.Reader.Text(text), k.hashByStatic[text]
+}
+
+// BlockKeyed calls Block on the wrapped reader and returns its result
+// and the key of the message.
+func (k *KeyedReader) BlockKeyed(text string) (localized, key string) {
+	return k.Reader.Block(text), k.hashByStatic[blockKey(k.hashByStatic, text)]
+}
+
+// PluralKeyed calls Plural on the wrapped reader and returns its result
+// and the key of the message.
+func (k *KeyedReader) PluralKeyed(
+	templates Forms, quantity any,
+) (localized, key string) {
+	return k.Reader.Plural(templates, quantity), k.hashByPlural[templates.Other]
+}
+
+// PluralBlockKeyed calls PluralBlock on the wrapped reader and returns
+// its result and the key of the message.
+func (k *KeyedReader) PluralBlockKeyed(
+	templates Forms, quantity any,
+) (localized, key string) {
+	return k.Reader.PluralBlock(templates, quantity),
+		k.hashByPlural[blockKey(k.hashByPlural, templates.Other)]
+}
+
+// CardinalKeyed calls Cardinal on the wrapped reader and returns its result
+// and the key of the message.
+func (k *KeyedReader) CardinalKeyed(
+	otherTemplate string, quantity any,
+) (localized, key string) {
+	return k.Reader.Cardinal(otherTemplate, quantity), k.hashByPlural[otherTemplate]
+}
+
+// WithRegister returns a keyed reader wrapping the reader of register
+// of the wrapped reader.
+func (k *KeyedReader) WithRegister(register Register) Reader {
+	return &KeyedReader{
+		Reader:       k.Reader.WithRegister(register),
+		hashByStatic: k.hashByStatic,
+		hashByPlural: k.hashByPlural,
+	}
+}
+
+// Section returns a keyed reader wrapping the reader of section name
+// of the wrapped reader.
+func (k *KeyedReader) Section(name string) Reader {
+	return &KeyedReader{
+		Reader:       k.Reader.Section(name),
+		hashByStatic: k.hashByStatic,
+		hashByPlural: k.hashByPlural,
+	}
+}
@@ -0,0 +1,73 @@
+package localize_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestKeyedReader(t *testing.T) {
+	r := MockCatalogReader{
+		MockReader: MockReader{
+			tag: language.German,
+			static: map[string]string{
+				"Hello":           "Hallo",
+				"\tMulti\n\tline": "Mehrzeilig",
+				"Not in catalog":  "Nicht im Katalog",
+			},
+		},
+		messages: []MockCatalogMessage{
+			{
+				Key:         localize.Key{Hash: "818274c2b2b715d5", Source: "Hello"},
+				Translation: localize.Translation{Text: "Hallo"},
+			},
+			{
+				Key:         localize.Key{Hash: "2167a000384d7a5b", Source: "Multi\nline"},
+				Translation: localize.Translation{Text: "Mehrzeilig"},
+			},
+			{
+				Key: localize.Key{Hash: "c2b9e5304ee8d192", Source: "%d apples"},
+				Translation: localize.Translation{Plural: true, Forms: localize.Forms{
+					One: "%d Apfel", Other: "%d Äpfel",
+				}},
+			},
+		},
+	}
+
+	k := localize.NewKeyedReader(r)
+	require.Equal(t, r, k.Unwrap())
+
+	s, key := k.TextKeyed("Hello")
+	require.Equal(t, "Hallo", s)
+	require.Equal(t, "818274c2b2b715d5", key)
+
+	s, key = k.BlockKeyed("\tMulti\n\tline")
+	require.Equal(t, "Mehrzeilig", s)
+	require.Equal(t, "2167a000384d7a5b", key)
+
+	_, key = k.PluralKeyed(localize.Forms{One: "%d apple", Other: "%d apples"}, 2)
+	require.Equal(t, "c2b9e5304ee8d192", key)
+	_, key = k.CardinalKeyed("%d apples", 2)
+	require.Equal(t, "c2b9e5304ee8d192", key)
+
+	s, key = k.TextKeyed("Not in catalog")
+	require.Equal(t, "Nicht im Katalog", s)
+	require.Empty(t, key)
+
+	// Keys are kept for other registers.
+	_, key = k.WithRegister(localize.RegisterFormal).(*localize.KeyedReader).
+		TextKeyed("Hello")
+	require.Equal(t, "818274c2b2b715d5", key)
+}
+
+func TestKeyedReaderNoCataloger(t *testing.T) {
+	k := localize.NewKeyedReader(MockReader{
+		tag:    language.English,
+		static: map[string]string{"Hello": "Hello"},
+	})
+	s, key := k.TextKeyed("Hello")
+	require.Equal(t, "Hello", s)
+	require.Empty(t, key)
+}
@@ -3,6 +3,8 @@ package localize
 import (
 	"errors"
 	"fmt"
+	"iter"
+	"slices"
 
 	"github.com/go-playground/locales"
 	"golang.org/x/text/language"
@@ -36,6 +38,55 @@ type Forms struct {
 	Other string
 }
 
+// CardinalForms returns the forms of Reader.Cardinal,
+// which are all set to otherTemplate.
+// Readers usually implement Cardinal as:
+//
+//	r.Plural(localize.CardinalForms(otherTemplate), quantity)
+func CardinalForms(otherTemplate string) Forms {
+	return Forms{
+		Zero:  otherTemplate,
+		One:   otherTemplate,
+		Two:   otherTemplate,
+		Few:   otherTemplate,
+		Many:  otherTemplate,
+		Other: otherTemplate,
+	}
+}
+
+// OrdinalForms defines the forms of messages with both an ordinal and
+// a cardinal quantity like "the 2nd item of 5 items" by the CLDR ordinal
+// plural category of the ordinal quantity. Each category defines the Forms
+// selected by the cardinal quantity. Categories left empty fall back to Other.
+//
+// For more information, see CLDR documentation:
+// https://cldr.unicode.org/index/cldr-spec/plural-rules
+type OrdinalForms struct {
+	// Zero defines the forms used for ordinal category zero,
+	// as required by some languages.
+	Zero Forms
+
+	// One defines the forms used for ordinal category one,
+	// like English "1st", "21st" and "101st".
+	One Forms
+
+	// Two defines the forms used for ordinal category two,
+	// like English "2nd" and "22nd".
+	Two Forms
+
+	// Few defines the forms used for ordinal category few,
+	// like English "3rd" and "23rd".
+	Few Forms
+
+	// Many defines the forms used for ordinal category many,
+	// like Italian "l'8°" and "l'11°".
+	Many Forms
+
+	// Other defines the forms used for all other ordinals and
+	// must always be defined.
+	Other Forms
+}
+
 // Reader reads localized data.
 type Reader interface {
 	// Locale provides the locale this reader localizes for.
@@ -68,6 +119,9 @@ type Reader interface {
 	//    localized="You have 5 unread emails" (quantity=int(5))
 	//    localized="You have 1 unread email" (quantity=int(1))
 	//
+	// quantity must be of a numeric type, a type with a numeric underlying type
+	// or implement either Quantifier or Int64er (see Quantity).
+	//
 	// For more information see unicode plural rules specification:
 	// https://www.unicode.org/cldr/charts/47/supplemental/language_plural_rules.html
 	Plural(templates Forms, quantity any) (localized string)
@@ -75,72 +129,564 @@ type Reader interface {
 	// PluralBlock behaves like Plural and formats like Block.
 	PluralBlock(templates Forms, quantity any) (localized string)
 
+	// Cardinal behaves like Plural with otherTemplate used for all forms
+	// of the source locale, such that the source text can be written
+	// without a Forms literal:
+	//
+	//   otherTemplate="%d items selected":
+	//    localized="5 items selected" (quantity=int(5))
+	//    localized="1 items selected" (quantity=int(1))
+	//
+	// Translations may still define all forms of their locale.
+	Cardinal(otherTemplate string, quantity any) (localized string)
+
+	// PluralRange provides plural translations for ranges of quantities
+	// in the form selected by the CLDR plural range rules of the locale
+	// for the plural categories of from and to (see Forms.RangeForm):
+	//
+	//   templates.Other="%d–%d days":
+	//    localized="1–3 days" (from=int(1), to=int(3))
+	//
+	// from and to are formatted in this order and must be quantities
+	// like the quantity of Plural, templates must have two quantity
+	// placeholders.
+	//
+	// For more information see unicode plural ranges specification:
+	// https://www.unicode.org/reports/tr35/tr35-numbers.html#Plural_Ranges
+	PluralRange(templates Forms, from, to any) (localized string)
+
+	// PluralOrdinal provides plural translations with both an ordinal and
+	// a cardinal quantity, such as a position among a total, selecting the
+	// Forms by the CLDR ordinal plural category of ordinal and the form of
+	// those by the cardinal plural category of quantity
+	// (see OrdinalForms.Forms):
+	//
+	//   templates.Two.Other="the %dnd item of %d items":
+	//    localized="the 2nd item of 5 items" (ordinal=int(2), quantity=int(5))
+	//
+	// ordinal and quantity are formatted in this order and must be
+	// quantities like the quantity of Plural, templates must have two
+	// quantity placeholders.
+	PluralOrdinal(templates OrdinalForms, ordinal, quantity any) (localized string)
+
+	// Grammar provides the grammatical form of the phrase of args joined by
+	// spaces according to the grammar helper key defined by the catalog,
+	// such as the contraction of the French preposition and article:
+	//
+	//   key="contraction" args=["de", "le"]:
+	//    localized="du"
+	//
+	// Grammar entries are auxiliary messages of translation catalogs
+	// (see GrammarContextPrefix). Returns the phrase if the catalog
+	// defines no grammar entry for it.
+	Grammar(key string, args ...string) (localized string)
+
+	// FormatCompact formats n in the CLDR short compact decimal notation
+	// of the locale (see FormatCompact) for counters and the like:
+	//
+	//   n=float64(1234):
+	//    localized="1.2K" (en)
+	//    localized="1,2 Tsd." (de)
+	//    localized="1,234" (ja)
+	FormatCompact(n float64) (localized string)
+
+	// Truncate returns s truncated to at most max grapheme clusters
+	// including the ellipsis of the locale (see Truncate) for UI snippets,
+	// without splitting user-perceived characters like emoji:
+	//
+	//   s="Hello world" max=8:
+	//    localized="Hello w…" (en)
+	//   s="简体中文文本" max=5:
+	//    localized="简体中……" (zh)
+	Truncate(s string, max int) (localized string)
+
+	// WithRegister returns a reader of the same catalog providing the
+	// variants of translations in register, such as the informal German "du"
+	// instead of "Sie" for RegisterInformal. Messages without a variant
+	// in register fall back to their regular translation.
+	// Variants are auxiliary messages of translation catalogs
+	// (see RegisterContextPrefix). RegisterDefault selects the regular
+	// translations.
+	WithRegister(register Register) Reader
+
+	// Section returns a reader of the same catalog providing the
+	// translations of the messages of section name, which are extracted
+	// from calls on the returned reader and translated separately from
+	// identical texts of other sections:
+	//
+	//   checkout := r.Section("Checkout")
+	//   checkout.Text("Total") // Translated separately from r.Text("Total").
+	//
+	// Sections of readers returned by Section are nested (see SectionPath).
+	// Messages without a translation in the section fall back to their
+	// regular translation.
+	Section(name string) Reader
+
 	// Translator returns the localized translator of github.com/go-playground/locales
 	// for the locale this reader localizes for.
 	Translator() locales.Translator
 }
 
+// Key identifies a message of a catalog.
+type Key struct {
+	// Hash is the unique hash of the message
+	// used as msgctxt in the gettext catalogs.
+	Hash string
+
+	// Source is the source text for static messages (Text and Block)
+	// or the source template of form Other for plural messages
+	// (Plural and PluralBlock).
+	Source string
+
+	// Section is the section of messages translated separately
+	// in their section (see Reader.Section) and empty otherwise.
+	Section string
+}
+
+// Translation is the translation of a message.
+type Translation struct {
+	// Plural is true for plural messages (Plural and PluralBlock).
+	Plural bool
+
+	// Text is the translated text of a static message (Text and Block).
+	// Empty if the message isn't translated.
+	Text string
+
+	// Forms are the translated templates of a plural message.
+	// Forms are empty if the message isn't translated.
+	Forms Forms
+}
+
+// Cataloger is an optional interface implemented by readers
+// providing access to all messages of their catalog.
+// All generated readers implement Cataloger.
+type Cataloger interface {
+	// Messages returns an iterator over all messages of the catalog
+	// ordered by hash.
+	Messages() iter.Seq2[Key, Translation]
+}
+
+// MetadataProvider is an optional interface implemented by readers
+// providing the header metadata of their catalog.
+// All generated readers implement MetadataProvider.
+type MetadataProvider interface {
+	// Metadata returns the headers of the catalog by name,
+	// such as Project-Id-Version, PO-Revision-Date and X- headers.
+	// The returned map may be modified by the caller.
+	Metadata() map[string]string
+}
+
+// Normalizer is an optional interface implemented by readers
+// normalizing the source texts they look up (see strfmt.Normalize).
+// All generated readers implement Normalizer.
+type Normalizer interface {
+	// Normalize returns text normalized like the source texts
+	// of the catalog were normalized when they were extracted.
+	Normalize(text string) string
+}
+
 // Bundle is a group of localized readers.
+// Bundle is immutable and safe for concurrent use.
 type Bundle struct {
-	locales          []language.Tag
-	readers          []Reader
-	defaultLocaleStr string
-	matcher          language.Matcher
-	readerByLocale   map[string]Reader
+	locales        []language.Tag
+	readers        []Reader
+	defaultReader  Reader
+	matcher        language.Matcher
+	matcherReaders []Reader
+	readerByLocale map[language.Tag]Reader
+	readerByBase   map[language.Base]Reader
+	readerByScript map[baseScript]Reader
+	matchMode      MatchMode
+	strictScripts  bool
+}
+
+// MatchMode defines which matches Bundle.Match accepts.
+type MatchMode uint8
+
+const (
+	// MatchBestEffort accepts any match with a confidence
+	// higher than language.No, such as "de-AT" matching "de".
+	MatchBestEffort MatchMode = iota
+
+	// MatchExact accepts only matches with confidence language.Exact.
+	MatchExact
+)
+
+// Options are optional bundle settings.
+type Options struct {
+	// MatchMode is MatchBestEffort by default.
+	MatchMode MatchMode
+
+	// Strict wraps all readers of the bundle in a StrictReader
+	// reporting missing translations to OnMissing.
+	// Strict mode should only be enabled in development
+	// and staging environments.
+	Strict bool
+
+	// OnMissing is called with a *MissingTranslationError for every message
+	// without a translation if Strict is enabled.
+	// If OnMissing is nil missing translations panic.
+	OnMissing func(err error)
+
+	// MinimumCoverage is the minimum translated fraction in range [0, 1]
+	// of a reader's catalog (see Bundle.Coverage). Readers below it are
+	// excluded from Match, MustMatch, ForLocale and ForBase, which fall back
+	// to the default reader instead, such that users don't see half-translated
+	// interfaces of barely started locales. The default reader and readers
+	// not implementing Cataloger are never excluded.
+	// Excluded readers are still returned by Readers.
+	MinimumCoverage float64
+
+	// EagerTranslators calls Translator on all readers when creating the bundle
+	// such that readers constructing their translators lazily on first use
+	// construct them upfront instead, moving the cost of construction to
+	// startup and keeping the first requests of every locale equally fast.
+	EagerTranslators bool
+
+	// Transliterations adds a transliterated reader (see Transliterate) for
+	// the target locale of every transliteration the bundle has no reader for.
+	// The source locale must be the locale of a reader of the bundle.
+	Transliterations []Transliteration
+
+	// StrictScripts prevents ForLocale from falling back to a reader of the
+	// base language of a locale written in a different script, such as
+	// "sr-Latn" falling back to "sr" written in Cyrillic. ForLocale falls
+	// back to the default reader instead.
+	StrictScripts bool
+
+	// NativeDigits wraps all readers of the bundle using WithNativeDigits
+	// rendering digits in the numbering system of their locale.
+	NativeDigits bool
+
+	// QuantityFormatter wraps all readers of the bundle using
+	// WithQuantityFormatter formatting the quantities of plural messages,
+	// such as FormatQuantity. Quantities are substituted as is if nil.
+	QuantityFormatter QuantityFormatter
+
+	// Register selects the register of all readers of the bundle
+	// (see Reader.WithRegister). Individual readers returned by the bundle
+	// can still select another register.
+	Register Register
 }
 
 var (
 	ErrEmptyBundle    = errors.New("bundle has no catalogs")
 	ErrReaderConflict = errors.New("conflicting readers")
+	ErrNoDefault      = errors.New("no reader for default locale")
+	ErrNoReader       = errors.New("no reader for locale")
 )
 
-// New creates a new localization bundle.
+// New creates a new localization bundle with default options.
+// See NewWithOptions for more information.
 func New(defaultLocale language.Tag, bundle ...Reader) (*Bundle, error) {
+	return NewWithOptions(defaultLocale, Options{}, bundle...)
+}
+
+// NewWithOptions creates a new localization bundle.
+//
+// The reader for defaultLocale is resolved by exact locale first and by the base
+// language of defaultLocale second, such that for example a bundle with
+// an "en-US" reader can have its default locale set to "en".
+// Returns ErrNoDefault if neither can be found.
+func NewWithOptions(
+	defaultLocale language.Tag, options Options, bundle ...Reader,
+) (*Bundle, error) {
 	if len(bundle) < 1 {
 		return nil, ErrEmptyBundle
 	}
-	def := defaultLocale.String()
+	bundle, err := withTransliterations(bundle, options.Transliterations)
+	if err != nil {
+		return nil, err
+	}
 	readers := make([]Reader, len(bundle))
-	readerByLocale := make(map[string]Reader, len(bundle))
+	readerByLocale := make(map[language.Tag]Reader, len(bundle))
 	locales := make([]language.Tag, len(bundle))
 	for i, r := range bundle {
-		locale := r.Locale()
+		if options.Register != RegisterDefault {
+			r = r.WithRegister(options.Register)
+		}
+		r = WithQuantityFormatter(r, options.QuantityFormatter)
+		if options.NativeDigits {
+			r = WithNativeDigits(r)
+		}
+		if options.Strict {
+			r = NewStrictReader(r, options.OnMissing)
+		}
+		locale := canonical(r.Locale())
 		locales[i] = locale
-		localeStr := locale.String()
-		if _, ok := readerByLocale[localeStr]; ok {
+		if _, ok := readerByLocale[locale]; ok {
 			return nil, fmt.Errorf("%w for %q", ErrReaderConflict, locale)
 		}
-		readerByLocale[localeStr] = r
+		readerByLocale[locale] = r
 		readers[i] = r
+		if options.EagerTranslators {
+			_ = r.Translator()
+		}
+	}
+
+	defaultLocale = canonical(defaultLocale)
+	defIndex := slices.Index(locales, defaultLocale)
+	if defIndex == -1 {
+		base, _ := defaultLocale.Base()
+		i, ok := indexByBase(locales, nil)[base]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrNoDefault, defaultLocale)
+		}
+		defIndex = i
+	}
+
+	// Readers below the minimum coverage are excluded from matching,
+	// the default reader is always included.
+	included := make([]bool, len(readers))
+	for i, r := range readers {
+		included[i] = true
+		if i == defIndex || options.MinimumCoverage <= 0 {
+			continue
+		}
+		if c, ok := coverageOf(r); ok && c.Percent() < options.MinimumCoverage*100 {
+			included[i] = false
+			delete(readerByLocale, locales[i])
+		}
+	}
+
+	readerByBase := make(map[language.Base]Reader, len(bundle))
+	for base, i := range indexByBase(locales, included) {
+		readerByBase[base] = readers[i]
+	}
+	readerByScript := make(map[baseScript]Reader, len(bundle))
+	for bs, i := range indexByScript(locales, included) {
+		readerByScript[bs] = readers[i]
+	}
+
+	// The first supported tag is the fallback of the matcher,
+	// therefore, the default reader must come first.
+	matcherReaders := make([]Reader, 0, len(readers))
+	matcherTags := make([]language.Tag, 0, len(readers))
+	matcherReaders = append(matcherReaders, readers[defIndex])
+	matcherTags = append(matcherTags, locales[defIndex])
+	for i, r := range readers {
+		if i == defIndex || !included[i] {
+			continue
+		}
+		matcherReaders = append(matcherReaders, r)
+		matcherTags = append(matcherTags, locales[i])
 	}
-	matcher := language.NewMatcher(locales)
+
 	return &Bundle{
-		matcher:          matcher,
-		locales:          locales,
-		readers:          readers,
-		defaultLocaleStr: def,
-		readerByLocale:   readerByLocale,
+		matcher:        language.NewMatcher(matcherTags),
+		matcherReaders: matcherReaders,
+		locales:        locales,
+		readers:        readers,
+		defaultReader:  readers[defIndex],
+		readerByLocale: readerByLocale,
+		readerByBase:   readerByBase,
+		readerByScript: readerByScript,
+		matchMode:      options.MatchMode,
+		strictScripts:  options.StrictScripts,
 	}, nil
 }
 
-// Match returns the best matching reader for locale.
+// indexByBase indexes locales by base language. A base-only locale like "en"
+// takes precedence over region-qualified ones like "en-US",
+// otherwise the first locale is used. Only locales with included[i] set
+// are indexed unless included is nil.
+func indexByBase(locales []language.Tag, included []bool) map[language.Base]int {
+	m := make(map[language.Base]int, len(locales))
+	for i, locale := range locales {
+		if included != nil && !included[i] {
+			continue
+		}
+		base, _ := locale.Base()
+		if _, ok := m[base]; !ok || locale == language.Make(base.String()) {
+			m[base] = i
+		}
+	}
+	return m
+}
+
+// baseScript is a base language written in a script.
+type baseScript struct {
+	base   language.Base
+	script language.Script
+}
+
+// scriptOf returns the base language and the (likely) script of locale.
+func scriptOf(locale language.Tag) baseScript {
+	base, _ := locale.Base()
+	script, _ := locale.Script()
+	return baseScript{base: base, script: script}
+}
+
+// indexByScript is like indexByBase but indexes locales by base language
+// and script, such that "zh-Hant" and "zh" (written in Han Simplified)
+// are indexed separately. A locale without region, like "sr-Latn",
+// takes precedence over region-qualified ones like "sr-Latn-RS".
+func indexByScript(
+	locales []language.Tag, included []bool,
+) map[baseScript]int {
+	m := make(map[baseScript]int, len(locales))
+	for i, locale := range locales {
+		if included != nil && !included[i] {
+			continue
+		}
+		bs := scriptOf(locale)
+		j, ok := m[bs]
+		if !ok || !hasRegion(locale) && hasRegion(locales[j]) {
+			m[bs] = i
+		}
+	}
+	return m
+}
+
+func hasRegion(t language.Tag) bool {
+	_, c := t.Region()
+	return c == language.Exact
+}
+
+// canonical returns the canonical form of t.
+func canonical(t language.Tag) language.Tag {
+	c, err := language.All.Canonicalize(t)
+	if err != nil {
+		return t
+	}
+	return c
+}
+
+// Match returns the best matching reader for locales.
+// Returns nil if none of the locales match in the match mode of the bundle.
+// Use MustMatch to fall back to the default reader instead.
 func (l *Bundle) Match(locales ...language.Tag) (Reader, language.Confidence) {
-	matchedTag, _, c := l.matcher.Match(locales...)
-	return l.readerByLocale[matchedTag.String()], c
+	_, index, c := l.matcher.Match(locales...)
+	if !l.accepts(c) {
+		return nil, c
+	}
+	return l.matcherReaders[index], c
+}
+
+// MustMatch is similar to Match but is guaranteed to never return nil.
+// Returns the default reader if none of the locales match
+// in the match mode of the bundle.
+func (l *Bundle) MustMatch(locales ...language.Tag) (Reader, language.Confidence) {
+	_, index, c := l.matcher.Match(locales...)
+	if !l.accepts(c) {
+		return l.defaultReader, c
+	}
+	return l.matcherReaders[index], c
+}
+
+// Negotiate is similar to MustMatch but also reports which of locales
+// was matched and whether the default reader was returned as fallback,
+// such that applications don't need to derive it for response headers
+// like Content-Language and analytics.
+func (l *Bundle) Negotiate(locales ...language.Tag) ReaderMatch {
+	_, index, c := l.matcher.Match(locales...)
+	if !l.accepts(c) {
+		return ReaderMatch{Reader: l.defaultReader, Confidence: c, Fallback: true}
+	}
+	m := ReaderMatch{Reader: l.matcherReaders[index], Confidence: c}
+	// The matcher doesn't report which of locales it matched.
+	for _, locale := range locales {
+		if _, i, c := l.matcher.Match(locale); i == index && l.accepts(c) {
+			m.Locale = locale
+			break
+		}
+	}
+	return m
+}
+
+// ReaderMatch is a reader returned by Bundle.Negotiate and Bundle.MatchAll.
+type ReaderMatch struct {
+	Reader Reader
+
+	// Locale is the requested locale Reader was matched for.
+	// Locale is language.Und if Fallback is true.
+	Locale language.Tag
+
+	Confidence language.Confidence
+
+	// Fallback is true if none of the requested locales matched
+	// and Reader is the default reader of the bundle.
+	Fallback bool
+}
+
+// MatchAll is similar to Match but returns all matching readers for locales
+// in the order of preference of locales instead of only the best one.
+// Every reader is included at most once for the first locale it matches.
+// Use MatchAll for pages mixing content of the preferred locale with
+// snippets of fallback locales, such as user-generated content that is
+// only available in some languages.
+// Returns nil if none of the locales match in the match mode of the bundle.
+func (l *Bundle) MatchAll(locales ...language.Tag) []ReaderMatch {
+	var matches []ReaderMatch
+	seen := make([]bool, len(l.matcherReaders))
+	for _, locale := range locales {
+		_, index, c := l.matcher.Match(locale)
+		if !l.accepts(c) || seen[index] {
+			continue
+		}
+		seen[index] = true
+		matches = append(matches, ReaderMatch{
+			Reader:     l.matcherReaders[index],
+			Locale:     locale,
+			Confidence: c,
+		})
+	}
+	return matches
+}
+
+func (l *Bundle) accepts(c language.Confidence) bool {
+	if l.matchMode == MatchExact {
+		return c == language.Exact
+	}
+	return c > language.No
+}
+
+// ForLocale returns the reader for locale, or the reader for the base language
+// of locale written in the same script, or the reader for the base language
+// of locale, or the default reader if none is found.
+// The reader for the base language is skipped if the bundle was created
+// with Options.StrictScripts.
+func (l *Bundle) ForLocale(locale language.Tag) Reader {
+	locale = canonical(locale)
+	if r, ok := l.readerByLocale[locale]; ok {
+		return r
+	}
+	bs := scriptOf(locale)
+	if r, ok := l.readerByScript[bs]; ok {
+		return r
+	}
+	if l.strictScripts {
+		return l.defaultReader
+	}
+	return l.ForBase(bs.base)
 }
 
 // ForBase returns either the localization for language, or the default localization
 // if no localization for language is found.
+// If the bundle contains multiple readers for language then the reader of
+// the base-only locale is preferred (e.g. "en" over "en-US"), otherwise
+// the first reader of language passed to New is returned.
 func (l *Bundle) ForBase(language language.Base) Reader {
-	r := l.readerByLocale[language.String()]
-	if r == nil {
-		r = l.readerByLocale[l.defaultLocaleStr]
+	if r, ok := l.readerByBase[language]; ok {
+		return r
+	}
+	return l.defaultReader
+}
+
+// ForSystem returns the reader for the locale of the user's operating system
+// (see DetectSystemLocale) like ForLocale, or the default reader
+// if the locale can't be detected.
+// ForSystem is intended for CLI applications localizing their own output.
+func (l *Bundle) ForSystem() Reader {
+	locale, ok := DetectSystemLocale()
+	if !ok {
+		return l.defaultReader
 	}
-	return r
+	return l.ForLocale(locale)
 }
 
 // Default returns the reader for the default locale.
-func (l *Bundle) Default() Reader { return l.readerByLocale[l.defaultLocaleStr] }
+func (l *Bundle) Default() Reader { return l.defaultReader }
 
 // Locales returns all locales of the bundle.
 func (l *Bundle) Locales() []language.Tag { return l.locales }
@@ -1,6 +1,7 @@
 package localize_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/go-playground/locales"
@@ -98,6 +99,34 @@ func (r MockReader) PluralBlock(templates localize.Forms, quantity any) string {
 	// return fmt.Sprintf(p.Other, quantity)
 }
 
+func (r MockReader) Cardinal(otherTemplate string, quantity any) string {
+	return r.Plural(localize.CardinalForms(otherTemplate), quantity)
+}
+
+func (r MockReader) PluralRange(templates localize.Forms, from, to any) string {
+	return r.Plural(templates, to)
+}
+
+func (r MockReader) PluralOrdinal(
+	templates localize.OrdinalForms, ordinal, quantity any,
+) string {
+	return r.Plural(templates.Other, quantity)
+}
+
+func (r MockReader) Grammar(key string, args ...string) string {
+	return localize.GrammarPhrase(args...)
+}
+
+func (r MockReader) FormatCompact(n float64) string { return fmt.Sprint(n) }
+
+func (r MockReader) Truncate(s string, max int) string {
+	return localize.Truncate(r.tag, s, max)
+}
+
+func (r MockReader) WithRegister(localize.Register) localize.Reader { return r }
+
+func (r MockReader) Section(string) localize.Reader { return r }
+
 func (r MockReader) Translator() locales.Translator {
 	panic("not yet implemented")
 }
@@ -118,6 +147,408 @@ func TestErrReaderConflict(t *testing.T) {
 	require.Nil(t, l)
 }
 
+func TestErrNoDefault(t *testing.T) {
+	german := &MockReader{tag: language.German}
+	l, err := localize.New(language.English, german)
+	require.ErrorIs(t, err, localize.ErrNoDefault)
+	require.Nil(t, l)
+}
+
+func TestDefault(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		defaultLocale language.Tag
+		readers       []language.Tag
+		expect        language.Tag
+	}{
+		{
+			name:          "exact",
+			defaultLocale: language.English,
+			readers:       []language.Tag{language.German, language.English},
+			expect:        language.English,
+		},
+		{
+			name:          "exact_region",
+			defaultLocale: language.AmericanEnglish,
+			readers: []language.Tag{
+				language.English, language.BritishEnglish, language.AmericanEnglish,
+			},
+			expect: language.AmericanEnglish,
+		},
+		{
+			name:          "by_base",
+			defaultLocale: language.English,
+			readers:       []language.Tag{language.German, language.AmericanEnglish},
+			expect:        language.AmericanEnglish,
+		},
+		{
+			name:          "by_base_prefer_base_only",
+			defaultLocale: language.AmericanEnglish,
+			readers: []language.Tag{
+				language.BritishEnglish, language.English, language.German,
+			},
+			expect: language.English,
+		},
+		{
+			name:          "by_base_first",
+			defaultLocale: language.English,
+			readers: []language.Tag{
+				language.German, language.BritishEnglish, language.AmericanEnglish,
+			},
+			expect: language.BritishEnglish,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := localize.New(tt.defaultLocale, mockReaders(tt.readers...)...)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, l.Default().Locale())
+		})
+	}
+}
+
+func TestForBase(t *testing.T) {
+	base := func(t language.Tag) language.Base {
+		b, _ := t.Base()
+		return b
+	}
+
+	for _, tt := range []struct {
+		name          string
+		defaultLocale language.Tag
+		readers       []language.Tag
+		base          language.Base
+		expect        language.Tag
+	}{
+		{
+			name:          "base_only",
+			defaultLocale: language.English,
+			readers:       []language.Tag{language.English, language.German},
+			base:          base(language.German),
+			expect:        language.German,
+		},
+		{
+			name:          "region_qualified",
+			defaultLocale: language.AmericanEnglish,
+			readers: []language.Tag{
+				language.AmericanEnglish, language.MustParse("de-CH"),
+			},
+			base:   base(language.German),
+			expect: language.MustParse("de-CH"),
+		},
+		{
+			name:          "prefer_base_only",
+			defaultLocale: language.English,
+			readers: []language.Tag{
+				language.English,
+				language.MustParse("de-CH"),
+				language.German,
+				language.MustParse("de-AT"),
+			},
+			base:   base(language.German),
+			expect: language.German,
+		},
+		{
+			name:          "first_region_qualified",
+			defaultLocale: language.English,
+			readers: []language.Tag{
+				language.English,
+				language.MustParse("de-AT"),
+				language.MustParse("de-CH"),
+			},
+			base:   base(language.German),
+			expect: language.MustParse("de-AT"),
+		},
+		{
+			name:          "fallback_default",
+			defaultLocale: language.BritishEnglish,
+			readers: []language.Tag{
+				language.German, language.BritishEnglish,
+			},
+			base:   base(language.French),
+			expect: language.BritishEnglish,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := localize.New(tt.defaultLocale, mockReaders(tt.readers...)...)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, l.ForBase(tt.base).Locale())
+		})
+	}
+}
+
+func TestForLocale(t *testing.T) {
+	l, err := localize.New(language.English, mockReaders(
+		language.English,
+		language.German,
+		language.MustParse("de-CH"),
+		language.MustParse("fr-CA"),
+	)...)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		locale language.Tag
+		expect language.Tag
+	}{
+		{language.English, language.English},
+		{language.AmericanEnglish, language.English},
+		{language.German, language.German},
+		{language.MustParse("de-CH"), language.MustParse("de-CH")},
+		{language.MustParse("de-AT"), language.German},
+		{language.French, language.MustParse("fr-CA")},
+		{language.MustParse("fr-FR"), language.MustParse("fr-CA")},
+		{language.Japanese, language.English},
+	} {
+		t.Run(tt.locale.String(), func(t *testing.T) {
+			require.Equal(t, tt.expect, l.ForLocale(tt.locale).Locale())
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	l, err := localize.New(language.AmericanEnglish, mockReaders(
+		language.German,
+		language.AmericanEnglish,
+		language.MustParse("de-CH"),
+		language.MustParse("fr-CA"),
+		language.Ukrainian,
+	)...)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name       string
+		locales    []language.Tag
+		expect     language.Tag
+		confidence language.Confidence
+	}{
+		{
+			name:       "exact",
+			locales:    []language.Tag{language.German},
+			expect:     language.German,
+			confidence: language.Exact,
+		},
+		{
+			name:       "exact_region",
+			locales:    []language.Tag{language.MustParse("de-CH")},
+			expect:     language.MustParse("de-CH"),
+			confidence: language.Exact,
+		},
+		{
+			name:       "region_fallback_to_base",
+			locales:    []language.Tag{language.MustParse("de-AT")},
+			expect:     language.German,
+			confidence: language.High,
+		},
+		{
+			name:       "base_to_region",
+			locales:    []language.Tag{language.French},
+			expect:     language.MustParse("fr-CA"),
+			confidence: language.High,
+		},
+		{
+			name:       "base_to_default_region",
+			locales:    []language.Tag{language.English},
+			expect:     language.AmericanEnglish,
+			confidence: language.Exact,
+		},
+		{
+			name: "preference_order",
+			locales: []language.Tag{
+				language.Japanese, language.Ukrainian, language.German,
+			},
+			expect:     language.Ukrainian,
+			confidence: language.Exact,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r, c := l.Match(tt.locales...)
+			require.NotNil(t, r)
+			require.Equal(t, tt.expect, r.Locale())
+			require.Equal(t, tt.confidence, c)
+
+			r, c = l.MustMatch(tt.locales...)
+			require.Equal(t, tt.expect, r.Locale())
+			require.Equal(t, tt.confidence, c)
+		})
+	}
+
+	for _, tt := range []struct {
+		name    string
+		locales []language.Tag
+	}{
+		{name: "no_match", locales: []language.Tag{language.Japanese}},
+		{name: "none", locales: nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r, c := l.Match(tt.locales...)
+			require.Nil(t, r)
+			require.Equal(t, language.No, c)
+
+			r, c = l.MustMatch(tt.locales...)
+			require.Equal(t, language.AmericanEnglish, r.Locale())
+			require.Equal(t, language.No, c)
+		})
+	}
+}
+
+func TestMatchExact(t *testing.T) {
+	l, err := localize.NewWithOptions(
+		language.English, localize.Options{MatchMode: localize.MatchExact},
+		mockReaders(language.English, language.German, language.MustParse("fr-CA"))...,
+	)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name       string
+		locales    []language.Tag
+		expect     language.Tag // Undetermined for no match.
+		confidence language.Confidence
+	}{
+		{
+			name:       "exact",
+			locales:    []language.Tag{language.German},
+			expect:     language.German,
+			confidence: language.Exact,
+		},
+		{
+			name:       "region_fallback_to_base",
+			locales:    []language.Tag{language.MustParse("de-AT")},
+			confidence: language.High,
+		},
+		{
+			name:       "base_to_region",
+			locales:    []language.Tag{language.French},
+			confidence: language.High,
+		},
+		{
+			name:       "no_match",
+			locales:    []language.Tag{language.Japanese},
+			confidence: language.No,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r, c := l.Match(tt.locales...)
+			require.Equal(t, tt.confidence, c)
+			if tt.expect == language.Und {
+				require.Nil(t, r)
+			} else {
+				require.Equal(t, tt.expect, r.Locale())
+			}
+
+			r, c = l.MustMatch(tt.locales...)
+			require.Equal(t, tt.confidence, c)
+			if tt.expect == language.Und {
+				require.Equal(t, language.English, r.Locale())
+			} else {
+				require.Equal(t, tt.expect, r.Locale())
+			}
+		})
+	}
+}
+
+func TestMatchAll(t *testing.T) {
+	l, err := localize.New(language.AmericanEnglish, mockReaders(
+		language.German,
+		language.AmericanEnglish,
+		language.MustParse("de-CH"),
+		language.MustParse("fr-CA"),
+		language.Ukrainian,
+	)...)
+	require.NoError(t, err)
+
+	type match struct {
+		Reader     language.Tag
+		Locale     language.Tag
+		Confidence language.Confidence
+	}
+	f := func(t *testing.T, expect []match, locales ...language.Tag) {
+		t.Helper()
+		var actual []match
+		for _, m := range l.MatchAll(locales...) {
+			actual = append(actual, match{
+				Reader: m.Reader.Locale(), Locale: m.Locale, Confidence: m.Confidence,
+			})
+		}
+		require.Equal(t, expect, actual)
+	}
+
+	f(t, nil)
+	f(t, nil, language.Japanese)
+	f(t, []match{
+		{language.German, language.German, language.Exact},
+	}, language.German)
+	f(t, []match{
+		{language.Ukrainian, language.Ukrainian, language.Exact},
+		{language.German, language.MustParse("de-AT"), language.High},
+		{language.MustParse("fr-CA"), language.French, language.High},
+		{language.AmericanEnglish, language.English, language.Exact},
+	},
+		language.Japanese,
+		language.Ukrainian,
+		language.MustParse("de-AT"),
+		language.German, // Duplicate of de-AT.
+		language.French,
+		language.English,
+	)
+
+	exact, err := localize.NewWithOptions(
+		language.English, localize.Options{MatchMode: localize.MatchExact},
+		mockReaders(language.English, language.German)...,
+	)
+	require.NoError(t, err)
+	m := exact.MatchAll(language.MustParse("de-AT"), language.English)
+	require.Len(t, m, 1)
+	require.Equal(t, language.English, m[0].Reader.Locale())
+}
+
+func TestNegotiate(t *testing.T) {
+	l, err := localize.New(language.AmericanEnglish, mockReaders(
+		language.German,
+		language.AmericanEnglish,
+		language.MustParse("fr-CA"),
+	)...)
+	require.NoError(t, err)
+
+	m := l.Negotiate(language.Japanese, language.MustParse("de-AT"), language.French)
+	require.Equal(t, language.German, m.Reader.Locale())
+	require.Equal(t, language.MustParse("de-AT"), m.Locale)
+	require.Equal(t, language.High, m.Confidence)
+	require.False(t, m.Fallback)
+
+	m = l.Negotiate(language.French)
+	require.Equal(t, language.MustParse("fr-CA"), m.Reader.Locale())
+	require.Equal(t, language.French, m.Locale)
+	require.False(t, m.Fallback)
+
+	for _, locales := range [][]language.Tag{nil, {language.Japanese}} {
+		m = l.Negotiate(locales...)
+		require.Equal(t, language.AmericanEnglish, m.Reader.Locale())
+		require.Equal(t, language.Und, m.Locale)
+		require.Equal(t, language.No, m.Confidence)
+		require.True(t, m.Fallback)
+	}
+
+	exact, err := localize.NewWithOptions(
+		language.English, localize.Options{MatchMode: localize.MatchExact},
+		mockReaders(language.English, language.German)...,
+	)
+	require.NoError(t, err)
+	m = exact.Negotiate(language.MustParse("de-AT"))
+	require.Equal(t, language.English, m.Reader.Locale())
+	require.True(t, m.Fallback)
+	m = exact.Negotiate(language.MustParse("de-AT"), language.German)
+	require.Equal(t, language.German, m.Reader.Locale())
+	require.Equal(t, language.German, m.Locale)
+	require.Equal(t, language.Exact, m.Confidence)
+}
+
+func mockReaders(locales ...language.Tag) []localize.Reader {
+	r := make([]localize.Reader, len(locales))
+	for i, l := range locales {
+		r[i] = MockReader{tag: l}
+	}
+	return r
+}
+
 // func Test(t *testing.T) {
 // 	baseEnglish, _ := language.English.Base()
 // 	baseGerman, _ := language.German.Base()
@@ -0,0 +1,160 @@
+// Package localizeapierr localizes the messages of API errors identified
+// by machine-readable codes, such as RFC 7807 problem details
+// (application/problem+json) and google.rpc.LocalizedMessage details
+// of gRPC and connect-go errors.
+//
+// The title and detail passed to Define are extracted by localize generate
+// like texts passed to localize.Reader methods and share the description
+// comment of the call. The error-codes directive tags their catalog entries
+// with the code for translators:
+//
+//	// Error of payments exceeding the balance.
+//	// error-codes: insufficient_funds
+//	var ErrInsufficientFunds = localizeapierr.Define(
+//		"insufficient_funds", http.StatusPaymentRequired,
+//		"Insufficient funds",
+//		"Your balance of %s doesn't cover the payment.",
+//	)
+package localizeapierr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/localizehttp"
+)
+
+var ErrDuplicateCode = errors.New("duplicate error code")
+
+// Def is the definition of an API error.
+type Def struct {
+	// Code is the machine-readable code of the error like "not_found".
+	Code string
+
+	// Status is the HTTP status code of the error.
+	Status int
+
+	// Title is the source text of the short summary of the error,
+	// which doesn't change between occurrences.
+	Title string
+
+	// Detail is the source text of the explanation of the error,
+	// which may contain fmt verbs formatted with the arguments of an occurrence.
+	Detail string
+}
+
+// Define returns the definition of the API error with code and HTTP status.
+// Both title and detail must not be empty.
+func Define(code string, status int, title, detail string) Def {
+	return Def{Code: code, Status: status, Title: title, Detail: detail}
+}
+
+// Problem is an RFC 7807 problem details object extended by the error code.
+type Problem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+
+	// Locale is the locale of Title and Detail.
+	// Locale is returned as Content-Language header by WriteProblem.
+	Locale string `json:"-"`
+}
+
+// Problem returns the problem details of d localized by r.
+// args are the arguments of the fmt verbs of the detail.
+func (d Def) Problem(r localize.Reader, args ...any) Problem {
+	return Problem{
+		Title:  r.Text(d.Title),
+		Status: d.Status,
+		Detail: d.detail(r, args),
+		Code:   d.Code,
+		Locale: r.Locale().String(),
+	}
+}
+
+// LocalizedMessage is the equivalent of the google.rpc.LocalizedMessage
+// error detail of gRPC and connect-go errors:
+//
+//	m := def.LocalizedMessage(r)
+//	err.AddDetail(&errdetails.LocalizedMessage{Locale: m.Locale, Message: m.Message})
+type LocalizedMessage struct {
+	// Locale is the BCP 47 locale of Message.
+	Locale string
+
+	Message string
+}
+
+// LocalizedMessage returns the detail of d localized by r.
+// args are the arguments of the fmt verbs of the detail.
+func (d Def) LocalizedMessage(r localize.Reader, args ...any) LocalizedMessage {
+	return LocalizedMessage{Locale: r.Locale().String(), Message: d.detail(r, args)}
+}
+
+func (d Def) detail(r localize.Reader, args []any) string {
+	if len(args) == 0 {
+		return r.Text(d.Detail)
+	}
+	return fmt.Sprintf(r.Text(d.Detail), args...)
+}
+
+// Registry is a set of API error definitions by code.
+type Registry struct{ byCode map[string]Def }
+
+// NewRegistry returns a registry of defs.
+// Returns ErrDuplicateCode if two definitions share a code.
+func NewRegistry(defs ...Def) (*Registry, error) {
+	r := &Registry{byCode: make(map[string]Def, len(defs))}
+	for _, d := range defs {
+		if _, ok := r.byCode[d.Code]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateCode, d.Code)
+		}
+		r.byCode[d.Code] = d
+	}
+	return r, nil
+}
+
+// Lookup returns the definition of code. ok is false if there is none.
+func (r *Registry) Lookup(code string) (d Def, ok bool) {
+	d, ok = r.byCode[code]
+	return d, ok
+}
+
+// ReaderForMetadata returns the reader of b for the Accept-Language entry
+// of gRPC metadata or HTTP headers md such as metadata.MD or http.Header.
+// Returns the default reader of b if md has none.
+func ReaderForMetadata(b *localize.Bundle, md map[string][]string) localize.Reader {
+	l := md["accept-language"]
+	if len(l) == 0 {
+		l = md["Accept-Language"]
+	}
+	var accept string
+	if len(l) > 0 {
+		accept = l[0]
+	}
+	return localizehttp.Negotiate(b, accept)
+}
+
+// WriteProblem writes the problem details of d for req to w as
+// application/problem+json. The reader is read from the context of req
+// (see localizehttp.FromRequest) or, if there's none, negotiated using
+// the Accept-Language header of req.
+func WriteProblem(
+	w http.ResponseWriter, req *http.Request, b *localize.Bundle, d Def, args ...any,
+) error {
+	r := localizehttp.FromRequest(req)
+	if r == nil {
+		r = localizehttp.Reader(b, req, localizehttp.Options{})
+	}
+	p := d.Problem(r, args...)
+	p.Instance = req.URL.Path
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("Content-Language", p.Locale)
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,117 @@
+package localizeapierr_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/localizeapierr"
+	"github.com/romshark/localize/localizehttp"
+	"github.com/romshark/localize/xtextcatalog"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+var errInsufficientFunds = localizeapierr.Define(
+	"insufficient_funds", http.StatusPaymentRequired,
+	"Insufficient funds",
+	"Your balance of %s doesn't cover the payment.",
+)
+
+func newTestBundle(t *testing.T) *localize.Bundle {
+	t.Helper()
+	c := catalog.NewBuilder()
+	require.NoError(t, c.SetString(language.German,
+		"Insufficient funds", "Unzureichendes Guthaben"))
+	require.NoError(t, c.SetString(language.German,
+		"Your balance of %s doesn't cover the payment.",
+		"Ihr Guthaben von %s deckt die Zahlung nicht."))
+	b, err := localize.New(language.English,
+		xtextcatalog.NewReader(c, language.English, en.New()),
+		xtextcatalog.NewReader(c, language.German, de.New()),
+	)
+	require.NoError(t, err)
+	return b
+}
+
+func TestProblem(t *testing.T) {
+	b := newTestBundle(t)
+	require.Equal(t, localizeapierr.Problem{
+		Title:  "Unzureichendes Guthaben",
+		Status: http.StatusPaymentRequired,
+		Detail: "Ihr Guthaben von 5 € deckt die Zahlung nicht.",
+		Code:   "insufficient_funds",
+		Locale: "de",
+	}, errInsufficientFunds.Problem(b.ForLocale(language.German), "5 €"))
+
+	require.Equal(t, localizeapierr.LocalizedMessage{
+		Locale:  "en",
+		Message: "Your balance of 5 € doesn't cover the payment.",
+	}, errInsufficientFunds.LocalizedMessage(b.Default(), "5 €"))
+}
+
+func TestRegistry(t *testing.T) {
+	notFound := localizeapierr.Define("not_found", http.StatusNotFound,
+		"Not found", "The resource doesn't exist.")
+	r, err := localizeapierr.NewRegistry(errInsufficientFunds, notFound)
+	require.NoError(t, err)
+	d, ok := r.Lookup("not_found")
+	require.True(t, ok)
+	require.Equal(t, notFound, d)
+	_, ok = r.Lookup("gone")
+	require.False(t, ok)
+
+	_, err = localizeapierr.NewRegistry(notFound, notFound)
+	require.ErrorIs(t, err, localizeapierr.ErrDuplicateCode)
+}
+
+func TestReaderForMetadata(t *testing.T) {
+	b := newTestBundle(t)
+	f := func(t *testing.T, expect language.Tag, md map[string][]string) {
+		t.Helper()
+		require.Equal(t, expect, localizeapierr.ReaderForMetadata(b, md).Locale())
+	}
+	f(t, language.English, nil)
+	f(t, language.German, map[string][]string{"accept-language": {"de-AT"}})
+	f(t, language.German, http.Header{"Accept-Language": {"fr, de;q=0.5"}})
+	f(t, language.English, map[string][]string{"accept-language": {"ja"}})
+}
+
+func TestWriteProblem(t *testing.T) {
+	b := newTestBundle(t)
+	f := func(t *testing.T, req *http.Request, expectLocale, expectBody string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		require.NoError(t, localizeapierr.WriteProblem(w, req, b, errInsufficientFunds, "5"))
+		require.Equal(t, http.StatusPaymentRequired, w.Code)
+		require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		require.Equal(t, expectLocale, w.Header().Get("Content-Language"))
+		require.JSONEq(t, expectBody, w.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+	req.Header.Set("Accept-Language", "de")
+	f(t, req, "de", `{
+		"title": "Unzureichendes Guthaben",
+		"status": 402,
+		"detail": "Ihr Guthaben von 5 deckt die Zahlung nicht.",
+		"instance": "/payments",
+		"code": "insufficient_funds"
+	}`)
+
+	// The reader provided by localizehttp takes precedence.
+	req = localizehttp.Request(b, httptest.NewRequest(http.MethodPost, "/payments", nil),
+		localizehttp.Options{})
+	req.Header.Set("Accept-Language", "de")
+	f(t, req, "en", `{
+		"title": "Insufficient funds",
+		"status": 402,
+		"detail": "Your balance of 5 doesn't cover the payment.",
+		"instance": "/payments",
+		"code": "insufficient_funds"
+	}`)
+}
@@ -0,0 +1,315 @@
+// Package localizedb provides a localize.Reader backed by a key-value store
+// such as a CMS or a database, allowing organizations that manage
+// translations outside of the source repository to join a localize.Bundle
+// alongside generated catalogs.
+//
+// Translations are cached in memory and invalidated when the store
+// reports changes through Store.Watch. Centrally managed translations
+// loaded a locale at a time, such as from an S3 bucket, a Redis hash or
+// an etcd prefix, can be served by a SourceStore reloading a Source
+// periodically or when notified about changes.
+package localizedb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-playground/locales"
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/strfmt"
+	"golang.org/x/text/language"
+)
+
+var _ localize.Reader = (*Reader)(nil)
+
+// Store is a key-value store of translations.
+// Translations are identified by locale and source text. Block messages are
+// identified by their dedented source text and plural messages by their
+// (dedented, for PluralBlock) source template of form Other.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the translation of the message with source text source
+	// in locale. ok is false if the store has no translation for it.
+	Get(
+		ctx context.Context, locale language.Tag, source string,
+	) (t localize.Translation, ok bool, err error)
+
+	// Watch returns a channel receiving the source texts of the messages
+	// of locale that changed in the store.
+	// The channel must be closed once ctx is canceled.
+	Watch(ctx context.Context, locale language.Tag) (<-chan string, error)
+}
+
+// Options are optional reader settings.
+type Options struct {
+	// OnError is called with errors returned by Store.Get, in which case
+	// the source text is used and the message isn't cached.
+	// Errors are ignored if OnError is nil.
+	OnError func(err error)
+}
+
+// Reader reads localized data from a Store.
+//
+// Messages that are not in the store fall back to the source text and
+// plural forms are then selected by the cardinal plural rule of
+// the translator. Reader is safe for concurrent use.
+type Reader struct {
+	ctx        context.Context
+	store      Store
+	locale     language.Tag
+	base       language.Base
+	translator locales.Translator
+	onError    func(err error)
+	register   localize.Register
+	section    string
+
+	// cache is shared by the readers of all registers.
+	cache *cache
+}
+
+type cache struct {
+	lock    sync.RWMutex
+	entries map[string]entry
+	gen     uint64 // Incremented by every invalidation.
+	caching atomic.Bool
+}
+
+// entry is a cached result of Store.Get.
+type entry struct {
+	t  localize.Translation
+	ok bool
+}
+
+// NewReader creates a new reader reading messages of locale from store.
+// translator must be the github.com/go-playground/locales translator
+// of locale, for example de.New() for German.
+//
+// ctx is passed to all store calls and stops watching for changes when
+// canceled. If the watch channel is closed the cache is purged and
+// caching is disabled since changes can no longer be observed.
+func NewReader(
+	ctx context.Context, store Store, locale language.Tag,
+	translator locales.Translator, opts Options,
+) (*Reader, error) {
+	changes, err := store.Watch(ctx, locale)
+	if err != nil {
+		return nil, fmt.Errorf("watching store: %w", err)
+	}
+	base, _ := locale.Base()
+	r := &Reader{
+		ctx:        ctx,
+		store:      store,
+		locale:     locale,
+		base:       base,
+		translator: translator,
+		onError:    opts.OnError,
+		cache:      &cache{entries: map[string]entry{}},
+	}
+	r.cache.caching.Store(true)
+	go r.watch(changes)
+	return r, nil
+}
+
+func (r *Reader) watch(changes <-chan string) {
+	for source := range changes {
+		r.Invalidate(source)
+	}
+	r.cache.caching.Store(false)
+	r.Purge()
+}
+
+// Invalidate removes the cached translation of the message
+// with source text source.
+func (r *Reader) Invalidate(source string) {
+	r.cache.lock.Lock()
+	defer r.cache.lock.Unlock()
+	delete(r.cache.entries, source)
+	r.cache.gen++
+}
+
+// Purge removes all cached translations.
+func (r *Reader) Purge() {
+	r.cache.lock.Lock()
+	defer r.cache.lock.Unlock()
+	clear(r.cache.entries)
+	r.cache.gen++
+}
+
+// Locale provides the locale this reader localizes for.
+func (r *Reader) Locale() language.Tag { return r.locale }
+
+// Base provides the base language this reader localizes for.
+func (r *Reader) Base() language.Base { return r.base }
+
+// Translator returns the localized translator of
+// github.com/go-playground/locales for the locale this reader localizes for.
+func (r *Reader) Translator() locales.Translator { return r.translator }
+
+// Text provides static 1-to-1 translations.
+func (r *Reader) Text(text string) (localized string) {
+	if t, ok := r.lookupVariant(text); ok && !t.Plural && t.Text != "" {
+		return t.Text
+	}
+	// Fall back to source translation.
+	return text
+}
+
+// Block provides static 1-to-1 translations for a multi-line string block.
+// Common leading indentation is automatically removed.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) Block(text string) (localized string) {
+	return r.Text(strfmt.Dedent(text))
+}
+
+// Plural provides plural translations in cardinal form.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) Plural(templates localize.Forms, quantity any) (localized string) {
+	if t, ok := r.lookupVariant(templates.Other); ok && t.Plural && t.Forms.Other != "" {
+		return fmt.Sprintf(t.Forms.CardinalForm(r.translator, quantity), quantity)
+	}
+	// Fall back to source translation.
+	return fmt.Sprintf(templates.CardinalForm(r.translator, quantity), quantity)
+}
+
+// PluralBlock behaves like Plural and formats like Block.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) PluralBlock(templates localize.Forms, quantity any) (localized string) {
+	// Translations are identified by dedented templates.
+	templates.Zero = strfmt.Dedent(templates.Zero)
+	templates.One = strfmt.Dedent(templates.One)
+	templates.Two = strfmt.Dedent(templates.Two)
+	templates.Few = strfmt.Dedent(templates.Few)
+	templates.Many = strfmt.Dedent(templates.Many)
+	templates.Other = strfmt.Dedent(templates.Other)
+	return strfmt.Dedent(r.Plural(templates, quantity))
+}
+
+// Cardinal behaves like Plural with otherTemplate used for all source forms.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) Cardinal(otherTemplate string, quantity any) (localized string) {
+	return r.Plural(localize.CardinalForms(otherTemplate), quantity)
+}
+
+// PluralRange provides plural translations for ranges of quantities.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) PluralRange(templates localize.Forms, from, to any) (localized string) {
+	if t, ok := r.lookupVariant(templates.Other); ok && t.Plural && t.Forms.Other != "" {
+		return fmt.Sprintf(t.Forms.RangeForm(r.translator, from, to), from, to)
+	}
+	// Fall back to source translation.
+	return fmt.Sprintf(templates.RangeForm(r.translator, from, to), from, to)
+}
+
+// PluralOrdinal provides plural translations with both an ordinal
+// and a cardinal quantity.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) PluralOrdinal(
+	templates localize.OrdinalForms, ordinal, quantity any,
+) (localized string) {
+	forms := templates.Forms(r.translator, ordinal)
+	if t, ok := r.lookupVariant(forms.Other); ok && t.Plural && t.Forms.Other != "" {
+		return fmt.Sprintf(t.Forms.CardinalForm(r.translator, quantity), ordinal, quantity)
+	}
+	// Fall back to source translation.
+	return fmt.Sprintf(forms.CardinalForm(r.translator, quantity), ordinal, quantity)
+}
+
+// Grammar provides the grammatical form of the phrase of args according to
+// the grammar helper key. Grammar entries are stored as text translations
+// of their localize.GrammarID. For more information, see
+// github.com/romshark/localize.Reader documentation.
+func (r *Reader) Grammar(key string, args ...string) (localized string) {
+	id := localize.GrammarID(key, args...)
+	if t, ok := r.lookup(id); ok && !t.Plural && t.Text != "" {
+		return t.Text
+	}
+	return localize.GrammarPhrase(args...)
+}
+
+// FormatCompact formats n in the compact decimal notation of the locale.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) FormatCompact(n float64) (localized string) {
+	return localize.FormatCompact(r.locale, r.translator, n)
+}
+
+// Truncate truncates s to at most max grapheme clusters including
+// the ellipsis of the locale.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) Truncate(s string, max int) (localized string) {
+	return localize.Truncate(r.locale, s, max)
+}
+
+// WithRegister returns a reader of the same store providing the variants
+// of translations in register, which are stored as translations of their
+// localize.RegisterID. The returned reader shares the cache of r.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) WithRegister(register localize.Register) localize.Reader {
+	w := *r
+	w.register = register
+	return &w
+}
+
+// Section returns a reader of the same store looking up the translations
+// of section name nested in the section of r first, which are stored as
+// translations of their localize.SectionID. The returned reader shares
+// the cache of r.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) Section(name string) localize.Reader {
+	w := *r
+	w.section = localize.SectionPath(r.section, name)
+	return &w
+}
+
+// lookupVariant returns the translation of source in the section and
+// register of the reader and falls back to the translation of source
+// outside of the section and the regular translation.
+// Variants are stored as translations of their localize.RegisterID.
+func (r *Reader) lookupVariant(source string) (localize.Translation, bool) {
+	if r.section != "" {
+		if t, ok := r.lookupRegister(localize.SectionID(r.section, source)); ok {
+			return t, true
+		}
+	}
+	return r.lookupRegister(source)
+}
+
+// lookupRegister returns the translation of source in the register
+// of the reader and falls back to the regular translation.
+func (r *Reader) lookupRegister(source string) (localize.Translation, bool) {
+	if r.register != localize.RegisterDefault {
+		if t, ok := r.lookup(localize.RegisterID(r.register, source)); ok {
+			return t, true
+		}
+	}
+	return r.lookup(source)
+}
+
+// lookup returns the translation of source from the cache or the store.
+func (r *Reader) lookup(source string) (localize.Translation, bool) {
+	r.cache.lock.RLock()
+	e, cached := r.cache.entries[source]
+	gen := r.cache.gen
+	r.cache.lock.RUnlock()
+	if cached {
+		return e.t, e.ok
+	}
+
+	t, ok, err := r.store.Get(r.ctx, r.locale, source)
+	if err != nil {
+		if r.onError != nil {
+			r.onError(fmt.Errorf("getting %q (%s): %w", source, r.locale, err))
+		}
+		return localize.Translation{}, false
+	}
+	if r.cache.caching.Load() {
+		r.cache.lock.Lock()
+		// Don't cache translations that were invalidated during Get.
+		if r.cache.gen == gen {
+			r.cache.entries[source] = entry{t: t, ok: ok}
+		}
+		r.cache.lock.Unlock()
+	}
+	return t, ok
+}
@@ -0,0 +1,284 @@
+package localizedb_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/pl"
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/localizedb"
+	"github.com/romshark/localize/localizetest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// MemStore is an in-memory localizedb.Store counting calls to Get.
+type MemStore struct {
+	lock    sync.Mutex
+	m       map[string]localize.Translation
+	gets    int
+	err     error
+	changes chan string
+}
+
+func NewMemStore() *MemStore {
+	return &MemStore{
+		m:       map[string]localize.Translation{},
+		changes: make(chan string),
+	}
+}
+
+func (s *MemStore) Get(
+	_ context.Context, _ language.Tag, source string,
+) (localize.Translation, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.gets++
+	if s.err != nil {
+		return localize.Translation{}, false, s.err
+	}
+	t, ok := s.m[source]
+	return t, ok, nil
+}
+
+func (s *MemStore) Watch(
+	ctx context.Context, _ language.Tag,
+) (<-chan string, error) {
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case source, ok := <-s.changes:
+				if !ok {
+					return
+				}
+				out <- source
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Set sets the translation of source and blocks until
+// the change is delivered to the watcher.
+func (s *MemStore) Set(source string, t localize.Translation) {
+	s.lock.Lock()
+	s.m[source] = t
+	s.lock.Unlock()
+	s.changes <- source
+}
+
+func (s *MemStore) Gets() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.gets
+}
+
+func newTestStore() *MemStore {
+	s := NewMemStore()
+	s.m["Hello"] = localize.Translation{Text: "Hallo"}
+	s.m["First line.\n  Second line."] = localize.Translation{
+		Text: "Erste Zeile.\n  Zweite Zeile.",
+	}
+	s.m["%d messages"] = localize.Translation{
+		Plural: true,
+		Forms:  localize.Forms{One: "%d Nachricht", Other: "%d Nachrichten"},
+	}
+	s.m[localize.GrammarID("contraction", "zu", "dem")] = localize.Translation{
+		Text: "zum",
+	}
+	s.m[localize.RegisterID(localize.RegisterInformal, "Hello")] = localize.Translation{
+		Text: "Hi",
+	}
+	s.m[localize.SectionID("Chat/Inbox", "%d messages")] = localize.Translation{
+		Plural: true,
+		Forms:  localize.Forms{One: "%d ungelesene", Other: "%d ungelesene"},
+	}
+	return s
+}
+
+func newTestReader(t *testing.T, s localizedb.Store) *localizedb.Reader {
+	t.Helper()
+	r, err := localizedb.NewReader(
+		t.Context(), s, language.German, de.New(), localizedb.Options{},
+	)
+	require.NoError(t, err)
+	return r
+}
+
+func TestReaderConformance(t *testing.T) {
+	localizetest.TestReaderConformance(t, newTestReader(t, newTestStore()))
+}
+
+func TestReaderConformancePolish(t *testing.T) {
+	r, err := localizedb.NewReader(
+		t.Context(), NewMemStore(), language.Polish, pl.New(), localizedb.Options{},
+	)
+	require.NoError(t, err)
+	localizetest.TestReaderConformance(t, r)
+}
+
+func TestReader(t *testing.T) {
+	r := newTestReader(t, newTestStore())
+	require.Equal(t, language.German, r.Locale())
+
+	require.Equal(t, "Hallo", r.Text("Hello"))
+	require.Equal(t, "Goodbye", r.Text("Goodbye"))
+	require.Equal(t, "Erste Zeile.\n  Zweite Zeile.",
+		r.Block("\n\t\tFirst line.\n\t\t  Second line.\n\t"))
+
+	forms := localize.Forms{One: "%d message", Other: "%d messages"}
+	require.Equal(t, "1 Nachricht", r.Plural(forms, 1))
+	require.Equal(t, "5 Nachrichten", r.Plural(forms, uint8(5)))
+	require.Equal(t, "1 Nachricht",
+		r.PluralBlock(localize.Forms{One: "\n\t%d message\n", Other: "\n\t%d messages\n"}, 1))
+	require.Equal(t, "2 Nachrichten", r.Cardinal("%d messages", 2))
+
+	forms = localize.Forms{One: "%d file", Other: "%d files"}
+	require.Equal(t, "1 file", r.Plural(forms, 1))
+	require.Equal(t, "2 files", r.Plural(forms, 2))
+
+	require.Equal(t, "zum", r.Grammar("contraction", "zu", "dem"))
+	require.Equal(t, "zu der", r.Grammar("contraction", "zu", "der"))
+}
+
+func TestReaderMissingCategories(t *testing.T) {
+	s := NewMemStore()
+	// The translation lacks the category many of Polish.
+	s.m["%d messages"] = localize.Translation{
+		Plural: true,
+		Forms: localize.Forms{
+			One: "%d wiadomość", Few: "%d wiadomości", Other: "%d wiadomości",
+		},
+	}
+	r, err := localizedb.NewReader(
+		t.Context(), s, language.Polish, pl.New(), localizedb.Options{},
+	)
+	require.NoError(t, err)
+
+	forms := localize.Forms{One: "%d message", Other: "%d messages"}
+	require.Equal(t, "1 wiadomość", r.Plural(forms, 1))
+	require.Equal(t, "2 wiadomości", r.Plural(forms, 2)) // few
+	require.Equal(t, "5 wiadomości", r.Plural(forms, 5)) // many
+
+	// Source forms lacking the categories few and many.
+	forms = localize.Forms{One: "%d file", Other: "%d files"}
+	require.Equal(t, "1 file", r.Plural(forms, 1))
+	require.Equal(t, "2 files", r.Plural(forms, 2))
+	require.Equal(t, "5 files", r.Plural(forms, 5))
+	require.Equal(t, "5 files", r.Cardinal("%d files", 5))
+}
+
+func TestReaderWithRegister(t *testing.T) {
+	s := newTestStore()
+	r := newTestReader(t, s)
+	informal := r.WithRegister(localize.RegisterInformal)
+	require.Equal(t, "Hi", informal.Text("Hello"))
+	require.Equal(t, "5 Nachrichten", informal.Cardinal("%d messages", 5))
+	require.Equal(t, "Hallo", r.Text("Hello"))
+	require.Equal(t, "Hallo", r.WithRegister(localize.RegisterFormal).Text("Hello"))
+
+	// The cache is shared by the readers of all registers.
+	gets := s.Gets()
+	s.Set(localize.RegisterID(localize.RegisterInformal, "Hello"),
+		localize.Translation{Text: "Hallöchen"})
+	require.Eventually(t, func() bool {
+		return informal.Text("Hello") == "Hallöchen"
+	}, time.Second, time.Millisecond)
+	require.Greater(t, s.Gets(), gets)
+}
+
+func TestReaderSection(t *testing.T) {
+	r := newTestReader(t, newTestStore())
+	inbox := r.Section("Chat").Section("Inbox")
+	require.Equal(t, "5 ungelesene", inbox.Cardinal("%d messages", 5))
+	require.Equal(t, "5 Nachrichten", r.Section("Chat").Cardinal("%d messages", 5))
+	// Messages of sections fall back to the translations outside of sections.
+	require.Equal(t, "Hallo", inbox.Text("Hello"))
+	require.Equal(t, "Hi", inbox.WithRegister(localize.RegisterInformal).Text("Hello"))
+}
+
+func TestReaderCache(t *testing.T) {
+	s := newTestStore()
+	r := newTestReader(t, s)
+
+	require.Equal(t, "Hallo", r.Text("Hello"))
+	require.Equal(t, "Hallo", r.Text("Hello"))
+	require.Equal(t, "Goodbye", r.Text("Goodbye"))
+	require.Equal(t, "Goodbye", r.Text("Goodbye"))
+	require.Equal(t, 2, s.Gets(), "missing translations must be cached too")
+
+	s.Set("Hello", localize.Translation{Text: "Servus"})
+	require.Eventually(t, func() bool { return r.Text("Hello") == "Servus" },
+		time.Second, time.Millisecond)
+
+	s.Set("Goodbye", localize.Translation{Text: "Tschüss"})
+	require.Eventually(t, func() bool { return r.Text("Goodbye") == "Tschüss" },
+		time.Second, time.Millisecond)
+
+	gets := s.Gets()
+	r.Purge()
+	require.Equal(t, "Servus", r.Text("Hello"))
+	require.Equal(t, gets+1, s.Gets())
+}
+
+func TestReaderWatchStopped(t *testing.T) {
+	s := newTestStore()
+	r := newTestReader(t, s)
+	require.Equal(t, "Hallo", r.Text("Hello"))
+
+	close(s.changes)
+	require.Eventually(t, func() bool {
+		gets := s.Gets()
+		r.Text("Hello")
+		return s.Gets() == gets+1
+	}, time.Second, time.Millisecond, "caching must be disabled")
+}
+
+func TestReaderError(t *testing.T) {
+	s := newTestStore()
+	s.err = errors.New("connection lost")
+	var errs []error
+	r, err := localizedb.NewReader(
+		t.Context(), s, language.German, de.New(), localizedb.Options{
+			OnError: func(err error) { errs = append(errs, err) },
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "Hello", r.Text("Hello"))
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], s.err)
+
+	s.lock.Lock()
+	s.err = nil
+	s.lock.Unlock()
+	require.Equal(t, "Hallo", r.Text("Hello"), "errors must not be cached")
+}
+
+type FailingWatchStore struct{ localizedb.Store }
+
+func (FailingWatchStore) Watch(context.Context, language.Tag) (<-chan string, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestNewReaderWatchErr(t *testing.T) {
+	_, err := localizedb.NewReader(
+		t.Context(), FailingWatchStore{}, language.German, de.New(),
+		localizedb.Options{},
+	)
+	require.Error(t, err)
+}
+
+func TestBundle(t *testing.T) {
+	b, err := localize.New(language.German, newTestReader(t, newTestStore()))
+	require.NoError(t, err)
+	require.Equal(t, "Hallo", b.Default().Text("Hello"))
+}
@@ -0,0 +1,206 @@
+package localizedb
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/romshark/localize"
+	"golang.org/x/text/language"
+)
+
+var _ Store = (*SourceStore)(nil)
+
+// Source provides all translations of a locale at once, such as the objects
+// of an S3 bucket, the fields of a Redis hash or the keys of an etcd prefix.
+// Implementations must be safe for concurrent use.
+type Source interface {
+	// Load returns the translations of locale by source text
+	// (see Store for how messages are identified).
+	Load(ctx context.Context, locale language.Tag) (map[string]localize.Translation, error)
+}
+
+// SourceOptions are optional SourceStore settings.
+type SourceOptions struct {
+	// PollInterval is the interval at which watched locales are reloaded.
+	// Locales are only reloaded by Refresh if PollInterval is zero,
+	// such as when the source notifies about changes.
+	PollInterval time.Duration
+
+	// OnError is called with errors returned by Source.Load when polling.
+	// Errors are ignored if OnError is nil.
+	OnError func(err error)
+}
+
+// SourceStore is a Store of the translations of a Source kept in memory.
+// Locales are loaded on first use and reloaded periodically or by Refresh,
+// in which case the changed translations are reported to all watchers.
+// SourceStore is safe for concurrent use.
+type SourceStore struct {
+	source  Source
+	opts    SourceOptions
+	lock    sync.Mutex
+	locales map[language.Tag]map[string]localize.Translation
+	watch   map[language.Tag][]*watcher
+}
+
+// watcher collects the changed source texts not yet delivered
+// to the channel returned by Watch.
+type watcher struct {
+	lock    sync.Mutex
+	pending map[string]struct{}
+	signal  chan struct{}
+}
+
+func (w *watcher) notify(sources []string) {
+	w.lock.Lock()
+	for _, s := range sources {
+		w.pending[s] = struct{}{}
+	}
+	w.lock.Unlock()
+	select {
+	case w.signal <- struct{}{}:
+	default: // Already signaled.
+	}
+}
+
+func (w *watcher) take() []string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	sources := slices.Sorted(maps.Keys(w.pending))
+	clear(w.pending)
+	return sources
+}
+
+// NewSourceStore creates a new store reading translations from source.
+func NewSourceStore(source Source, opts SourceOptions) *SourceStore {
+	return &SourceStore{
+		source:  source,
+		opts:    opts,
+		locales: map[language.Tag]map[string]localize.Translation{},
+		watch:   map[language.Tag][]*watcher{},
+	}
+}
+
+// Get returns the translation of the message with source text source
+// in locale and loads the translations of locale if not loaded yet.
+func (s *SourceStore) Get(
+	ctx context.Context, locale language.Tag, source string,
+) (t localize.Translation, ok bool, err error) {
+	s.lock.Lock()
+	m, loaded := s.locales[locale]
+	s.lock.Unlock()
+	if !loaded {
+		if m, err = s.load(ctx, locale); err != nil {
+			return localize.Translation{}, false, err
+		}
+	}
+	t, ok = m[source]
+	return t, ok, nil
+}
+
+// Refresh reloads the translations of locale and reports the source texts
+// of the changed translations to the watchers of locale.
+// Sources notifying about changes should call Refresh when notified.
+func (s *SourceStore) Refresh(ctx context.Context, locale language.Tag) error {
+	_, err := s.load(ctx, locale)
+	return err
+}
+
+func (s *SourceStore) load(
+	ctx context.Context, locale language.Tag,
+) (map[string]localize.Translation, error) {
+	m, err := s.source.Load(ctx, locale)
+	if err != nil {
+		return nil, fmt.Errorf("loading translations (%s): %w", locale, err)
+	}
+	if m == nil {
+		m = map[string]localize.Translation{}
+	}
+
+	s.lock.Lock()
+	previous := s.locales[locale]
+	s.locales[locale] = m
+	watchers := slices.Clone(s.watch[locale])
+	s.lock.Unlock()
+
+	var changed []string
+	for source, t := range m {
+		if p, ok := previous[source]; !ok || p != t {
+			changed = append(changed, source)
+		}
+	}
+	for source := range previous {
+		if _, ok := m[source]; !ok {
+			changed = append(changed, source)
+		}
+	}
+	if len(changed) > 0 {
+		for _, w := range watchers {
+			w.notify(changed)
+		}
+	}
+	return m, nil
+}
+
+// Watch returns a channel receiving the source texts of the messages
+// of locale that changed in the source and polls the source every
+// SourceOptions.PollInterval until ctx is canceled.
+func (s *SourceStore) Watch(
+	ctx context.Context, locale language.Tag,
+) (<-chan string, error) {
+	w := &watcher{
+		pending: map[string]struct{}{},
+		signal:  make(chan struct{}, 1),
+	}
+	s.lock.Lock()
+	s.watch[locale] = append(s.watch[locale], w)
+	s.lock.Unlock()
+
+	var tick <-chan time.Time
+	if s.opts.PollInterval > 0 {
+		t := time.NewTicker(s.opts.PollInterval)
+		tick = t.C
+		context.AfterFunc(ctx, t.Stop)
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		defer s.unwatch(locale, w)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+				if err := s.Refresh(ctx, locale); err != nil &&
+					s.opts.OnError != nil && ctx.Err() == nil {
+					s.opts.OnError(err)
+				}
+			case <-w.signal:
+				for _, source := range w.take() {
+					select {
+					case <-ctx.Done():
+						return
+					case out <- source:
+					}
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (s *SourceStore) unwatch(locale language.Tag, w *watcher) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.watch[locale] = slices.DeleteFunc(s.watch[locale], func(x *watcher) bool {
+		return x == w
+	})
+	if len(s.watch[locale]) == 0 {
+		delete(s.watch, locale)
+	}
+}
@@ -0,0 +1,152 @@
+package localizedb_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/locales/de"
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/localizedb"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// MapSource is an in-memory localizedb.Source counting calls to Load.
+type MapSource struct {
+	lock  sync.Mutex
+	m     map[string]localize.Translation
+	loads int
+	err   error
+}
+
+func (s *MapSource) Load(
+	_ context.Context, _ language.Tag,
+) (map[string]localize.Translation, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.loads++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return maps.Clone(s.m), nil
+}
+
+func (s *MapSource) Set(source string, t localize.Translation) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.m[source] = t
+}
+
+func (s *MapSource) Delete(source string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.m, source)
+}
+
+func (s *MapSource) Loads() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.loads
+}
+
+func newTestSource() *MapSource {
+	return &MapSource{m: map[string]localize.Translation{
+		"Hello": {Text: "Hallo"},
+		"%d messages": {
+			Plural: true,
+			Forms:  localize.Forms{One: "%d Nachricht", Other: "%d Nachrichten"},
+		},
+	}}
+}
+
+func TestSourceStoreRefresh(t *testing.T) {
+	src := newTestSource()
+	s := localizedb.NewSourceStore(src, localizedb.SourceOptions{})
+	r := newTestReader(t, s)
+
+	require.Equal(t, "Hallo", r.Text("Hello"))
+	require.Equal(t, "5 Nachrichten",
+		r.Plural(localize.Forms{One: "%d message", Other: "%d messages"}, 5))
+	require.Equal(t, "Goodbye", r.Text("Goodbye"))
+	require.Equal(t, 1, src.Loads(), "locales must be loaded once")
+
+	src.Set("Hello", localize.Translation{Text: "Servus"})
+	src.Set("Goodbye", localize.Translation{Text: "Tschüss"})
+	src.Delete("%d messages")
+	require.Equal(t, "Hallo", r.Text("Hello"), "changes must apply on refresh")
+
+	require.NoError(t, s.Refresh(t.Context(), language.German))
+	require.Eventually(t, func() bool {
+		return r.Text("Hello") == "Servus" && r.Text("Goodbye") == "Tschüss" &&
+			r.Cardinal("%d messages", 5) == "5 messages"
+	}, time.Second, time.Millisecond)
+	require.Equal(t, 2, src.Loads())
+}
+
+func TestSourceStorePoll(t *testing.T) {
+	src := newTestSource()
+	s := localizedb.NewSourceStore(src, localizedb.SourceOptions{
+		PollInterval: time.Millisecond,
+	})
+	r := newTestReader(t, s)
+	require.Equal(t, "Hallo", r.Text("Hello"))
+
+	src.Set("Hello", localize.Translation{Text: "Servus"})
+	require.Eventually(t, func() bool { return r.Text("Hello") == "Servus" },
+		time.Second, time.Millisecond)
+}
+
+func TestSourceStoreError(t *testing.T) {
+	src := newTestSource()
+	src.err = errors.New("connection lost")
+	var lock sync.Mutex
+	var errs []error
+	onError := func(err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		errs = append(errs, err)
+	}
+	s := localizedb.NewSourceStore(src, localizedb.SourceOptions{
+		PollInterval: time.Millisecond,
+		OnError:      onError,
+	})
+	r, err := localizedb.NewReader(
+		t.Context(), s, language.German, de.New(),
+		localizedb.Options{OnError: onError},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "Hello", r.Text("Hello"))
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(errs) > 1
+	}, time.Second, time.Millisecond, "polling errors must be reported")
+	lock.Lock()
+	require.ErrorIs(t, errs[0], src.err)
+	lock.Unlock()
+
+	// The translations are available once the source recovers.
+	src.lock.Lock()
+	src.err = nil
+	src.lock.Unlock()
+	require.Eventually(t, func() bool { return r.Text("Hello") == "Hallo" },
+		time.Second, time.Millisecond)
+}
+
+func TestSourceStoreWatchStopped(t *testing.T) {
+	s := localizedb.NewSourceStore(newTestSource(), localizedb.SourceOptions{
+		PollInterval: time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(t.Context())
+	changes, err := s.Watch(ctx, language.German)
+	require.NoError(t, err)
+	cancel()
+	for range changes {
+		// Drain until closed.
+	}
+}
@@ -0,0 +1,169 @@
+// Package localizehttp selects the reader of a bundle for HTTP requests
+// by explicit locale (such as a query parameter, cookie or URL parameter)
+// and the Accept-Language header, and provides it to handlers through
+// the request context.
+//
+// Middleware works with net/http and all routers based on it, like chi:
+//
+//	r := chi.NewRouter()
+//	r.Use(localizehttp.Middleware(bundle, localizehttp.Options{Query: "lang"}))
+//	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+//		l := localizehttp.FromRequest(r)
+//		fmt.Fprintln(w, l.Text("Welcome!"))
+//	})
+//
+// Negotiate selects a reader independently of net/http for frameworks with
+// their own context types such as fiber.
+package localizehttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/romshark/localize"
+	"golang.org/x/text/language"
+)
+
+// Options are optional settings of Middleware and Request.
+type Options struct {
+	// Query is the name of the query parameter selecting the locale
+	// explicitly, such as "lang" for "?lang=de". Ignored if empty.
+	Query string
+
+	// Cookie is the name of the cookie selecting the locale explicitly.
+	// Ignored if empty.
+	Cookie string
+
+	// Locale returns the locale selected explicitly by other means,
+	// such as a URL parameter of a router. Ignored if nil.
+	Locale func(r *http.Request) string
+
+	// SetHeaders enables setting the Content-Language response header
+	// and adding Accept-Language, and Cookie if Cookie is set, to the Vary
+	// response header. Locales selected by Query or Locale are part of
+	// the URL, such that caches must key on the full URL including the query.
+	SetHeaders bool
+}
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying r.
+func NewContext(ctx context.Context, r localize.Reader) context.Context {
+	return context.WithValue(ctx, ctxKey{}, localize.ReaderMatch{Reader: r})
+}
+
+// FromContext returns the reader carried by ctx.
+// ok is false if ctx carries no reader.
+func FromContext(ctx context.Context) (r localize.Reader, ok bool) {
+	m, ok := MatchFromContext(ctx)
+	return m.Reader, ok
+}
+
+// MatchFromContext returns the negotiation result carried by ctx.
+// Only Reader is set if ctx was created by NewContext.
+// ok is false if ctx carries no reader.
+func MatchFromContext(ctx context.Context) (m localize.ReaderMatch, ok bool) {
+	m, ok = ctx.Value(ctxKey{}).(localize.ReaderMatch)
+	return m, ok
+}
+
+// FromRequest returns the reader of req provided by Middleware or Request.
+// Returns nil if req carries no reader.
+func FromRequest(req *http.Request) localize.Reader {
+	r, _ := FromContext(req.Context())
+	return r
+}
+
+// MatchFromRequest returns the negotiation result of req provided by
+// Middleware or Request, which tells the requested locale that was matched
+// and whether the default reader was used as fallback, for example
+// for analytics. ok is false if req carries no reader.
+func MatchFromRequest(req *http.Request) (m localize.ReaderMatch, ok bool) {
+	return MatchFromContext(req.Context())
+}
+
+// Negotiate returns the reader of b for the first valid locale of explicit
+// matching b or, if none matches, the best match for the Accept-Language
+// header value acceptLanguage. Empty and invalid locales are ignored.
+// Returns the default reader of b if nothing matches.
+func Negotiate(
+	b *localize.Bundle, acceptLanguage string, explicit ...string,
+) localize.Reader {
+	return NegotiateMatch(b, acceptLanguage, explicit...).Reader
+}
+
+// NegotiateMatch is similar to Negotiate but returns the negotiation result
+// (see localize.Bundle.Negotiate).
+func NegotiateMatch(
+	b *localize.Bundle, acceptLanguage string, explicit ...string,
+) localize.ReaderMatch {
+	for _, s := range explicit {
+		if s == "" {
+			continue
+		}
+		t, err := language.Parse(s)
+		if err != nil {
+			continue
+		}
+		if m := b.Negotiate(t); !m.Fallback {
+			return m
+		}
+	}
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	return b.Negotiate(tags...)
+}
+
+// Reader returns the reader of b for req (see Negotiate). The explicit locales
+// of opts take precedence in the order Locale, Query and Cookie.
+func Reader(b *localize.Bundle, req *http.Request, opts Options) localize.Reader {
+	return Match(b, req, opts).Reader
+}
+
+// Match is similar to Reader but returns the negotiation result
+// (see NegotiateMatch).
+func Match(b *localize.Bundle, req *http.Request, opts Options) localize.ReaderMatch {
+	var explicit []string
+	if opts.Locale != nil {
+		explicit = append(explicit, opts.Locale(req))
+	}
+	if opts.Query != "" {
+		explicit = append(explicit, req.URL.Query().Get(opts.Query))
+	}
+	if opts.Cookie != "" {
+		if c, err := req.Cookie(opts.Cookie); err == nil {
+			explicit = append(explicit, c.Value)
+		}
+	}
+	return NegotiateMatch(b, req.Header.Get("Accept-Language"), explicit...)
+}
+
+// Request returns a shallow copy of req carrying the reader of b for req
+// and its negotiation result (see Match). Use Request in middleware of frameworks wrapping
+// *http.Request, such as gin:
+//
+//	router.Use(func(c *gin.Context) {
+//		c.Request = localizehttp.Request(bundle, c.Request, opts)
+//	})
+func Request(b *localize.Bundle, req *http.Request, opts Options) *http.Request {
+	ctx := context.WithValue(req.Context(), ctxKey{}, Match(b, req, opts))
+	return req.WithContext(ctx)
+}
+
+// Middleware returns a middleware providing the reader of b for every request
+// (see Reader) to next, which can be read using FromRequest.
+// Use echo.WrapMiddleware to use Middleware with echo.
+func Middleware(b *localize.Bundle, opts Options) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			req = Request(b, req, opts)
+			if opts.SetHeaders {
+				w.Header().Set("Content-Language", FromRequest(req).Locale().String())
+				w.Header().Add("Vary", "Accept-Language")
+				if opts.Cookie != "" {
+					w.Header().Add("Vary", "Cookie")
+				}
+			}
+			next.ServeHTTP(w, req)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package localizehttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/fr"
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/localizehttp"
+	"github.com/romshark/localize/xtextcatalog"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+func newTestBundle(t *testing.T) *localize.Bundle {
+	t.Helper()
+	c := catalog.NewBuilder()
+	b, err := localize.New(language.English,
+		xtextcatalog.NewReader(c, language.English, en.New()),
+		xtextcatalog.NewReader(c, language.German, de.New()),
+		xtextcatalog.NewReader(c, language.French, fr.New()),
+	)
+	require.NoError(t, err)
+	return b
+}
+
+func TestNegotiate(t *testing.T) {
+	b := newTestBundle(t)
+	f := func(t *testing.T, expect language.Tag, accept string, explicit ...string) {
+		t.Helper()
+		r := localizehttp.Negotiate(b, accept, explicit...)
+		require.Equal(t, expect, r.Locale())
+	}
+	f(t, language.English, "")
+	f(t, language.German, "de-CH, fr;q=0.5")
+	f(t, language.French, "ja, fr;q=0.8, de;q=0.5")
+	f(t, language.English, "ja")
+	f(t, language.French, "de", "fr")
+	f(t, language.French, "de", "", "invalid!", "ja", "fr")
+	f(t, language.German, "de", "ja")
+}
+
+func TestMiddleware(t *testing.T) {
+	b := newTestBundle(t)
+	opts := localizehttp.Options{
+		Query:  "lang",
+		Cookie: "locale",
+		Locale: func(r *http.Request) string {
+			return r.Header.Get("X-Locale")
+		},
+		SetHeaders: true,
+	}
+	var got localize.Reader
+	h := localizehttp.Middleware(b, opts)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			got = localizehttp.FromRequest(r)
+		},
+	))
+
+	f := func(t *testing.T, expect language.Tag, target string, header http.Header) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		require.Equal(t, expect, got.Locale())
+		require.Equal(t, expect.String(), w.Header().Get("Content-Language"))
+		require.Equal(t, []string{"Accept-Language", "Cookie"}, w.Header().Values("Vary"))
+	}
+	f(t, language.English, "/", nil)
+	f(t, language.German, "/", http.Header{"Accept-Language": {"de"}})
+	f(t, language.French, "/?lang=fr", http.Header{"Accept-Language": {"de"}})
+	f(t, language.German, "/", http.Header{
+		"Accept-Language": {"fr"}, "Cookie": {"locale=de"},
+	})
+	f(t, language.French, "/?lang=fr", http.Header{"Cookie": {"locale=de"}})
+	f(t, language.German, "/?lang=fr", http.Header{"X-Locale": {"de"}})
+
+	// Responses only vary by cookie if a cookie selects the locale.
+	h = localizehttp.Middleware(b, localizehttp.Options{
+		Query: "lang", SetHeaders: true,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?lang=fr", nil))
+	require.Equal(t, []string{"Accept-Language"}, w.Header().Values("Vary"))
+}
+
+func TestFromContext(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	require.Nil(t, localizehttp.FromRequest(req))
+	_, ok := localizehttp.FromContext(req.Context())
+	require.False(t, ok)
+
+	b := newTestBundle(t)
+	req.Header.Set("Accept-Language", "de")
+	req = localizehttp.Request(b, req, localizehttp.Options{})
+	r, ok := localizehttp.FromContext(req.Context())
+	require.True(t, ok)
+	require.Equal(t, language.German, r.Locale())
+}
+
+func TestMatchFromRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?lang=ja", nil)
+	_, ok := localizehttp.MatchFromRequest(req)
+	require.False(t, ok)
+
+	b := newTestBundle(t)
+	opts := localizehttp.Options{Query: "lang"}
+	req.Header.Set("Accept-Language", "ja, de-AT;q=0.8")
+	m, ok := localizehttp.MatchFromRequest(localizehttp.Request(b, req, opts))
+	require.True(t, ok)
+	require.Equal(t, language.German, m.Reader.Locale())
+	require.Equal(t, language.MustParse("de-AT"), m.Locale)
+	require.Equal(t, language.High, m.Confidence)
+	require.False(t, m.Fallback)
+
+	req.Header.Set("Accept-Language", "ja")
+	m, ok = localizehttp.MatchFromRequest(localizehttp.Request(b, req, opts))
+	require.True(t, ok)
+	require.Equal(t, language.English, m.Reader.Locale())
+	require.True(t, m.Fallback)
+
+	// Explicit locales take precedence.
+	m = localizehttp.NegotiateMatch(b, "de", "fr")
+	require.Equal(t, language.French, m.Locale)
+	require.False(t, m.Fallback)
+
+	m, ok = localizehttp.MatchFromContext(
+		localizehttp.NewContext(req.Context(), b.Default()),
+	)
+	require.True(t, ok)
+	require.Equal(t, b.Default(), m.Reader)
+}
@@ -0,0 +1,99 @@
+// Package localizemail localizes transactional emails consisting of
+// a subject, an optional preheader and a body rendered as
+// plain text and HTML.
+//
+// Texts passed to New and Mail.WithPreheader are extracted by
+// localize generate like texts passed to localize.Reader methods.
+// The subject and body passed to New share the description comment:
+//
+//	// Password reset email.
+//	m := localizemail.New(r,
+//		"Reset your password",
+//		`
+//			Hi %s,
+//
+//			use the following link to reset your password:
+//			%s
+//		`, name, link,
+//	)
+//	// Preview text of the password reset email.
+//	m = m.WithPreheader(r, "The link expires in 24 hours.")
+package localizemail
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/romshark/localize"
+	"golang.org/x/text/language"
+)
+
+// Mail is a localized email.
+type Mail struct {
+	// Locale is the locale of the reader the mail is localized by.
+	Locale language.Tag
+
+	Subject string
+
+	// Preheader is the preview text mail clients display after the subject.
+	// Preheader is empty unless set by WithPreheader.
+	Preheader string
+
+	// Text is the plain-text body.
+	Text string
+}
+
+// New localizes subject using Reader.Text and body using Reader.Block.
+// Both are formatted with args (see fmt.Sprintf) if they contain
+// placeholders, which must use explicit argument indexes like "%[2]s"
+// unless they consume all args in order.
+func New(r localize.Reader, subject, body string, args ...any) Mail {
+	return Mail{
+		Locale:  r.Locale(),
+		Subject: format(r.Text(subject), args),
+		Text:    format(r.Block(body), args),
+	}
+}
+
+// WithPreheader returns m with the preheader set to preheader
+// localized using Reader.Text and formatted with args if provided.
+func (m Mail) WithPreheader(
+	r localize.Reader, preheader string, args ...any,
+) Mail {
+	m.Preheader = format(r.Text(preheader), args)
+	return m
+}
+
+// HTML returns the HTML body of m. The text is HTML-escaped,
+// paragraphs separated by blank lines are wrapped in <p> elements and
+// line breaks within paragraphs are converted to <br>.
+// The preheader is prepended as a hidden element mail clients use as
+// preview text.
+func (m Mail) HTML() string {
+	var b strings.Builder
+	if m.Preheader != "" {
+		b.WriteString(`<div style="display:none;max-height:0;overflow:hidden">`)
+		b.WriteString(html.EscapeString(m.Preheader))
+		b.WriteString("</div>\n")
+	}
+	for _, p := range strings.Split(m.Text, "\n\n") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(p), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
+
+// format formats s with args unless args is empty or s contains
+// no placeholders, in which case s is returned unchanged.
+func format(s string, args []any) string {
+	if len(args) == 0 || !strings.Contains(s, "%") {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
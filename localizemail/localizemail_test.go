@@ -0,0 +1,71 @@
+package localizemail_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/xtexttest"
+	"github.com/romshark/localize/localizemail"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+var testMessages = map[string]catalog.Message{
+	"Reset your password": catalog.String("Passwort zurücksetzen"),
+	"Order %[2]s shipped": catalog.String("Bestellung %[2]s versandt"),
+	"Hi %s,\n\nyour order <%s> shipped.\nThanks!": catalog.String(
+		"Hallo %s,\n\ndeine Bestellung <%s> ist unterwegs.\nDanke!"),
+	"Arrives soon.": catalog.String("Kommt bald an."),
+}
+
+func TestNew(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+
+	m := localizemail.New(r, "Reset your password", `
+		Hi %s,
+
+		your order <%s> shipped.
+		Thanks!
+	`, "Anna", "A&B")
+	require.Equal(t, localizemail.Mail{
+		Locale:  language.German,
+		Subject: "Passwort zurücksetzen",
+		Text:    "Hallo Anna,\n\ndeine Bestellung <A&B> ist unterwegs.\nDanke!",
+	}, m)
+
+	m = localizemail.New(r, "Order %[2]s shipped", "Details", "Anna", "A1")
+	require.Equal(t, "Bestellung A1 versandt", m.Subject)
+	require.Equal(t, "Details", m.Text)
+
+	// Texts aren't formatted without args.
+	m = localizemail.New(r, "100%", "100% done")
+	require.Equal(t, "100%", m.Subject)
+	require.Equal(t, "100% done", m.Text)
+}
+
+func TestWithPreheader(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	m := localizemail.New(r, "Reset your password", "Body")
+	require.Empty(t, m.Preheader)
+	m = m.WithPreheader(r, "Arrives soon.")
+	require.Equal(t, "Kommt bald an.", m.Preheader)
+	m = m.WithPreheader(r, "Expires in %d hours.", 24)
+	require.Equal(t, "Expires in 24 hours.", m.Preheader)
+}
+
+func TestHTML(t *testing.T) {
+	m := localizemail.Mail{
+		Text: "Hallo Anna,\n\ndeine Bestellung <A&B>\nist unterwegs.\n\n\n\nDanke!",
+	}
+	require.Equal(t, "<p>Hallo Anna,</p>\n"+
+		"<p>deine Bestellung &lt;A&amp;B&gt;<br>\nist unterwegs.</p>\n"+
+		"<p>Danke!</p>\n", m.HTML())
+
+	m.Preheader = "Kommt <bald> an."
+	require.Equal(t,
+		`<div style="display:none;max-height:0;overflow:hidden">`+
+			"Kommt &lt;bald&gt; an.</div>\n"+
+			"<p>Hallo Anna,</p>\n"+
+			"<p>deine Bestellung &lt;A&amp;B&gt;<br>\nist unterwegs.</p>\n"+
+			"<p>Danke!</p>\n", m.HTML())
+}
@@ -0,0 +1,101 @@
+// Package localizeregion localizes texts that vary by region rather than
+// language, such as legal disclaimers that differ between countries
+// sharing a language. The variant is selected by region independently of
+// the locale of the reader, such that German readers in Switzerland get
+// the Swiss disclaimer in German and French readers in Switzerland get it
+// in French.
+//
+// Texts passed to Text, Block, For and ForBlock are extracted by
+// localize generate like texts passed to localize.Reader methods.
+// The regions directive tags the catalog entries of a variant with
+// its regions:
+//
+//	s := localizeregion.Text(r, userRegion,
+//		// Withdrawal disclaimer of the checkout page.
+//		"You may withdraw from the purchase within 14 days.",
+//		// Withdrawal disclaimer of the checkout page.
+//		// regions: CH
+//		localizeregion.For("CH", "Purchases are final."),
+//	)
+package localizeregion
+
+import (
+	"github.com/romshark/localize"
+	"golang.org/x/text/language"
+)
+
+// Variant is the source text of a message for a region.
+type Variant struct {
+	// Region is either a country like "DE" or a group of countries
+	// like "EU" or "150" (Europe).
+	Region language.Region
+
+	Text string
+
+	block bool
+}
+
+// For returns the variant of a text localized using Reader.Text for region,
+// which is a region subtag like "DE" or "EU".
+// The variant never matches if region is invalid.
+func For(region, text string) Variant {
+	r, _ := language.ParseRegion(region)
+	return Variant{Region: r, Text: text}
+}
+
+// ForBlock is like For but the variant is localized using Reader.Block.
+func ForBlock(region, text string) Variant {
+	v := For(region, text)
+	v.block = true
+	return v
+}
+
+// Select returns the variant for region, which is the first variant of
+// exactly region or, if there's none, the first variant of a group
+// containing region, such as "EU" for "DE". ok is false if no variant
+// applies to region.
+func Select(region language.Region, variants ...Variant) (v Variant, ok bool) {
+	if region == (language.Region{}) {
+		return Variant{}, false
+	}
+	for _, v := range variants {
+		if v.Region == region {
+			return v, true
+		}
+	}
+	for _, v := range variants {
+		if v.Region != (language.Region{}) && v.Region.Contains(region) {
+			return v, true
+		}
+	}
+	return Variant{}, false
+}
+
+// Text localizes the variant for region (see Select) or text if there's
+// no variant for region using Reader.Text.
+// Use language.Tag.Region to select by the region of a locale.
+func Text(
+	r localize.Reader, region language.Region, text string, variants ...Variant,
+) string {
+	if v, ok := Select(region, variants...); ok {
+		return localized(r, v)
+	}
+	return r.Text(text)
+}
+
+// Block is like Text but localizes text using Reader.Block.
+func Block(
+	r localize.Reader, region language.Region, text string, variants ...Variant,
+) string {
+	if v, ok := Select(region, variants...); ok {
+		return localized(r, v)
+	}
+	return r.Block(text)
+}
+
+func localized(r localize.Reader, v Variant) string {
+	if v.block {
+		return r.Block(v.Text)
+	}
+	return r.Text(v.Text)
+}
@@ -0,0 +1,65 @@
+package localizeregion_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/internal/xtexttest"
+	"github.com/romshark/localize/localizeregion"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+var testMessages = map[string]catalog.Message{
+	"Returns within 14 days.": catalog.String("Rückgabe innerhalb von 14 Tagen."),
+	"Returns within 30 days.": catalog.String("Rückgabe innerhalb von 30 Tagen."),
+	"Purchases are final.":    catalog.String("Käufe sind endgültig."),
+	"No returns.":             catalog.String("Keine Rückgabe."),
+}
+
+func TestText(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	variants := []localizeregion.Variant{
+		localizeregion.For("CH", "Purchases are final."),
+		localizeregion.For("EU", "Returns within 30 days."),
+		localizeregion.ForBlock("419", "No returns."),
+		localizeregion.For("invalid", "Never selected."),
+	}
+	f := func(t *testing.T, region, expect string) {
+		t.Helper()
+		var reg language.Region
+		if region != "" {
+			reg = language.MustParseRegion(region)
+		}
+		require.Equal(t, expect, localizeregion.Text(
+			r, reg, "Returns within 14 days.", variants...,
+		))
+	}
+	f(t, "CH", "Käufe sind endgültig.")
+	f(t, "DE", "Rückgabe innerhalb von 30 Tagen.") // EU
+	f(t, "AR", "Keine Rückgabe.")                  // Latin America
+	f(t, "US", "Rückgabe innerhalb von 14 Tagen.") // No variant
+	f(t, "", "Rückgabe innerhalb von 14 Tagen.")   // Unknown region
+	f(t, "ZZ", "Rückgabe innerhalb von 14 Tagen.") // Unknown region
+}
+
+func TestSelect(t *testing.T) {
+	// Exact matches take precedence over preceding groups.
+	v, ok := localizeregion.Select(language.MustParseRegion("DE"),
+		localizeregion.For("150", "Europe"),
+		localizeregion.For("DE", "Germany"),
+	)
+	require.True(t, ok)
+	require.Equal(t, "Germany", v.Text)
+
+	_, ok = localizeregion.Select(language.MustParseRegion("DE"))
+	require.False(t, ok)
+}
+
+func TestBlock(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	require.Equal(t, "Keine Rückgabe.", localizeregion.Block(
+		r, language.MustParseRegion("CH"), "Returns within 14 days.",
+		localizeregion.ForBlock("CH", "No returns."),
+	))
+}
@@ -0,0 +1,116 @@
+// Package localizerich localizes messages containing rich segments, such as
+// links, without splitting sentences into separately translated fragments.
+//
+// A message refers to its segments by name using placeholders like
+// "%[terms]s". The texts of segments are localized separately and wrapped
+// by render functions provided by the caller:
+//
+//	link := func(text string) string {
+//		return `<a href="/terms">` + text + `</a>`
+//	}
+//	s := localizerich.HTML(r, "Please accept the %[terms]s to continue.",
+//		// Link text of the terms of service.
+//		localizerich.Text(r, "terms", "terms of service", link),
+//	)
+//
+// Texts passed to Text, Plural, Format and HTML are extracted by
+// localize generate like texts passed to localize.Reader methods.
+package localizerich
+
+import (
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/romshark/localize"
+)
+
+// RenderFunc renders the localized text of a segment, for example
+// by wrapping it in a link.
+type RenderFunc func(text string) string
+
+// Segment is a localized rich segment of a message.
+type Segment struct {
+	// Name is the name of the segment referred to by
+	// the placeholder "%[Name]s".
+	Name string
+
+	// Text is the localized text of the segment.
+	Text string
+
+	// Render renders Text. Text is used as is if Render is nil.
+	Render RenderFunc
+}
+
+// Text returns the segment name with text localized using Reader.Text.
+func Text(r localize.Reader, name, text string, render RenderFunc) Segment {
+	return Segment{Name: name, Text: r.Text(text), Render: render}
+}
+
+// Plural returns the segment name with forms localized using
+// Reader.Plural, such as a link reading "%d new messages".
+func Plural(
+	r localize.Reader, name string, forms localize.Forms, quantity any,
+	render RenderFunc,
+) Segment {
+	return Segment{Name: name, Text: r.Plural(forms, quantity), Render: render}
+}
+
+// Format localizes text using Reader.Text and replaces the placeholders
+// of segments with their rendered texts. Placeholders of unknown segments
+// are kept as is.
+func Format(r localize.Reader, text string, segments ...Segment) string {
+	return replace(r.Text(text), segments, nil)
+}
+
+// HTML is like Format but escapes text outside of segments.
+// The render functions receive HTML-escaped texts and return HTML.
+func HTML(r localize.Reader, text string, segments ...Segment) template.HTML {
+	return template.HTML(replace(r.Text(text), segments, html.EscapeString))
+}
+
+// replace replaces the segment placeholders in s with the rendered segments.
+// If escape isn't nil it's applied to the texts of s and all segments
+// before rendering.
+func replace(s string, segments []Segment, escape func(string) string) string {
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "%[")
+		if i == -1 {
+			break
+		}
+		end := strings.Index(s[i:], "]s")
+		if end == -1 {
+			break
+		}
+		name := s[i+len("%[") : i+end]
+		seg, ok := segment(segments, name)
+		if !ok {
+			// Unknown segment, keep the placeholder.
+			b.WriteString(escape(s[:i+end+len("]s")]))
+			s = s[i+end+len("]s"):]
+			continue
+		}
+		b.WriteString(escape(s[:i]))
+		text := escape(seg.Text)
+		if seg.Render != nil {
+			text = seg.Render(text)
+		}
+		b.WriteString(text)
+		s = s[i+end+len("]s"):]
+	}
+	b.WriteString(escape(s))
+	return b.String()
+}
+
+func segment(segments []Segment, name string) (Segment, bool) {
+	for _, s := range segments {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Segment{}, false
+}
@@ -0,0 +1,79 @@
+package localizerich_test
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/internal/xtexttest"
+	"github.com/romshark/localize/localizerich"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/message/catalog"
+)
+
+var testMessages = map[string]catalog.Message{
+	"Please accept the %[terms]s to continue.": catalog.String(
+		"Bitte akzeptiere die %[terms]s, um fortzufahren."),
+	"terms of service":              catalog.String("Nutzungsbedingungen"),
+	"You have %[messages]s & more.": catalog.String("Du hast %[messages]s & mehr."),
+	"%d new messages": plural.Selectf(1, "",
+		plural.One, "%d neue Nachricht",
+		plural.Other, "%d neue Nachrichten",
+	),
+}
+
+func link(text string) string { return `<a href="/x">` + text + `</a>` }
+
+func TestFormat(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	require.Equal(t,
+		`Bitte akzeptiere die <a href="/x">Nutzungsbedingungen</a>, um fortzufahren.`,
+		localizerich.Format(r, "Please accept the %[terms]s to continue.",
+			localizerich.Text(r, "terms", "terms of service", link)))
+
+	// Segments without render function are used as is.
+	require.Equal(t, "Bitte akzeptiere die Nutzungsbedingungen, um fortzufahren.",
+		localizerich.Format(r, "Please accept the %[terms]s to continue.",
+			localizerich.Text(r, "terms", "terms of service", nil)))
+
+	// Placeholders of unknown segments are kept.
+	require.Equal(t, "Bitte akzeptiere die %[terms]s, um fortzufahren.",
+		localizerich.Format(r, "Please accept the %[terms]s to continue."))
+
+	// Untranslated messages fall back to the source text.
+	require.Equal(t, "Read %[a]s and <b>B</b>, 100%",
+		localizerich.Format(r, "Read %[a]s and %[b]s, 100%",
+			localizerich.Segment{Name: "b", Text: "B", Render: func(s string) string {
+				return "<b>" + s + "</b>"
+			}}))
+}
+
+func TestPlural(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	forms := localize.Forms{One: "%d new message", Other: "%d new messages"}
+	for n, expect := range map[int]string{
+		1: `Du hast <a href="/x">1 neue Nachricht</a> & mehr.`,
+		5: `Du hast <a href="/x">5 neue Nachrichten</a> & mehr.`,
+	} {
+		require.Equal(t, expect, localizerich.Format(r,
+			"You have %[messages]s & more.",
+			localizerich.Plural(r, "messages", forms, n, link)))
+	}
+}
+
+func TestHTML(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	forms := localize.Forms{One: "%d new message", Other: "%d new messages"}
+	require.Equal(t,
+		template.HTML(`Du hast <a href="/x">2 neue Nachrichten</a> &amp; mehr.`),
+		localizerich.HTML(r, "You have %[messages]s & more.",
+			localizerich.Plural(r, "messages", forms, 2, link)))
+
+	// Segment texts are escaped before rendering.
+	s := localizerich.HTML(r, "<%[x]s>", localizerich.Segment{
+		Name: "x", Text: "<script>", Render: strings.ToUpper,
+	})
+	require.Equal(t, template.HTML("&lt;&LT;SCRIPT&GT;&gt;"), s)
+}
@@ -0,0 +1,463 @@
+// Package localizetest provides a conformance test suite
+// for implementations of localize.Reader.
+package localizetest
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-playground/locales"
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/strfmt"
+	"golang.org/x/text/language"
+)
+
+// Quantities are the sample quantities used to verify plural form selection.
+var Quantities = []any{
+	0, 1, 2, 3, 4, 5, 6, 7, 10, 11, 12, 14, 20, 21, 22, 25, 100, 101, 102,
+	1_000_000, -1,
+	int8(1), int16(2), int32(3), int64(5),
+	uint(1), uint8(2), uint16(3), uint32(5), uint64(11),
+	float32(1), float64(2),
+	namedInt(1), namedInt(3), big.NewInt(1), big.NewInt(21),
+}
+
+// namedInt is a named type with a numeric underlying type.
+type namedInt int
+
+// Sample messages are prefixed with this string to avoid collisions
+// with messages of the catalog of the reader under test.
+const samplePrefix = "localizetest: "
+
+// TestReaderConformance verifies that r behaves as specified by
+// localize.Reader for messages that are not in its catalog:
+//
+//   - Locale and Base must be consistent.
+//   - Translator must not be nil and must be of the same base language.
+//   - Text must return the source text.
+//   - Block must return the dedented source text.
+//   - Plural must select the form by the cardinal plural rule of the Translator
+//     for all Quantities and must fall back to form Other for unsupported types.
+//   - Plural must fall back to form Other for categories of the locale
+//     the source forms don't define.
+//   - PluralBlock must behave like Plural and return the dedented result.
+//   - Cardinal must use its single template for all quantities.
+//   - PluralRange must format both quantities and must fall back to form Other
+//     for unsupported types.
+//   - PluralOrdinal must select the forms by the ordinal plural rule and the
+//     form of those by the cardinal plural rule of the Translator, format both
+//     quantities and must fall back to Other for unsupported types.
+//   - Section must return a reader of the same locale falling back to
+//     the source text.
+//   - If r implements localize.Cataloger then its messages must be
+//     ordered by hash and have unique hashes.
+//   - If r implements localize.MetadataProvider then modifying the returned
+//     metadata must not affect subsequent calls.
+//   - All methods must be safe for concurrent use and return the same results
+//     when called from multiple goroutines. Run tests with -race to detect
+//     data races.
+func TestReaderConformance(t *testing.T, r localize.Reader) {
+	t.Helper()
+
+	t.Run("Locale", func(t *testing.T) {
+		locale := r.Locale()
+		if locale == language.Und {
+			t.Fatalf("Locale() returned undefined locale")
+		}
+		base, _ := locale.Base()
+		if r.Base() != base {
+			t.Errorf("Base() = %q, expected base of Locale() (%q): %q",
+				r.Base(), locale, base)
+		}
+	})
+
+	t.Run("Translator", func(t *testing.T) {
+		tr := r.Translator()
+		if tr == nil {
+			t.Fatalf("Translator() returned nil")
+		}
+		trLocale, err := language.Parse(strings.ReplaceAll(tr.Locale(), "_", "-"))
+		if err != nil {
+			t.Fatalf("parsing Translator().Locale() (%q): %v", tr.Locale(), err)
+		}
+		if base, _ := trLocale.Base(); base != r.Base() {
+			t.Errorf("Translator().Locale() = %q, expected base language %q",
+				tr.Locale(), r.Base())
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		for _, text := range []string{
+			samplePrefix + "text",
+			samplePrefix + "  text with\n  multiple lines\n",
+		} {
+			if a := r.Text(text); a != text {
+				t.Errorf("Text(%q) = %q, expected source text", text, a)
+			}
+		}
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		for text, expect := range map[string]string{
+			samplePrefix + "block": samplePrefix + "block",
+			"\n\t\t" + samplePrefix + "first line\n\t\t  second line\n\n" +
+				"\t\tthird line\n\t": samplePrefix + "first line\n" +
+				"  second line\n\nthird line",
+		} {
+			if a := r.Block(text); a != expect {
+				t.Errorf("Block(%q) = %q, expected %q", text, a, expect)
+			}
+		}
+	})
+
+	t.Run("Plural", func(t *testing.T) {
+		templates := sampleForms("plural")
+		tr := r.Translator()
+		if tr == nil {
+			t.Skip("Translator() returned nil")
+		}
+		for _, q := range Quantities {
+			expect := fmt.Sprintf(formOf(templates, tr, q), q)
+			if a := r.Plural(templates, q); a != expect {
+				t.Errorf("Plural(%T(%v)) = %q, expected %q", q, q, a, expect)
+			}
+		}
+		expect := fmt.Sprintf(templates.Other, "x")
+		if a := r.Plural(templates, "x"); a != expect {
+			t.Errorf("Plural(string) = %q, expected form Other: %q", a, expect)
+		}
+	})
+
+	t.Run("PluralBlock", func(t *testing.T) {
+		templates := sampleForms("plural block")
+		indented := localize.Forms{
+			Zero:  "\n\t\t" + templates.Zero + "\n\t",
+			One:   "\n\t\t" + templates.One + "\n\t",
+			Two:   "\n\t\t" + templates.Two + "\n\t",
+			Few:   "\n\t\t" + templates.Few + "\n\t",
+			Many:  "\n\t\t" + templates.Many + "\n\t",
+			Other: "\n\t\t" + templates.Other + "\n\t",
+		}
+		tr := r.Translator()
+		if tr == nil {
+			t.Skip("Translator() returned nil")
+		}
+		for _, q := range Quantities {
+			expect := strfmt.Dedent(fmt.Sprintf(formOf(templates, tr, q), q))
+			if a := r.PluralBlock(indented, q); a != expect {
+				t.Errorf("PluralBlock(%T(%v)) = %q, expected %q", q, q, a, expect)
+			}
+		}
+	})
+
+	t.Run("PluralMissingForms", func(t *testing.T) {
+		// Source forms usually define fewer forms than the plural categories
+		// of the locale, such as English forms read in Polish or Russian.
+		templates := localize.Forms{
+			One:   samplePrefix + "missing forms one %v",
+			Other: samplePrefix + "missing forms other %v",
+		}
+		ranges := localize.Forms{
+			One:   samplePrefix + "missing forms one %v–%v",
+			Other: samplePrefix + "missing forms other %v–%v",
+		}
+		tr := r.Translator()
+		if tr == nil {
+			t.Skip("Translator() returned nil")
+		}
+		for _, q := range Quantities {
+			expect := fmt.Sprintf(templates.CardinalForm(tr, q), q)
+			for name, a := range map[string]string{
+				"Plural":      r.Plural(templates, q),
+				"PluralBlock": r.PluralBlock(templates, q),
+				"PluralRange": r.PluralRange(ranges, q, q),
+			} {
+				if strings.Contains(a, "%!") {
+					t.Errorf("%s(%T(%v)) = %q, contains formatting errors",
+						name, q, q, a)
+				}
+			}
+			if a := r.Plural(templates, q); a != expect {
+				t.Errorf("Plural(%T(%v)) = %q, expected %q", q, q, a, expect)
+			}
+		}
+	})
+
+	t.Run("Cardinal", func(t *testing.T) {
+		template := samplePrefix + "cardinal %v"
+		for _, q := range Quantities {
+			expect := fmt.Sprintf(template, q)
+			if a := r.Cardinal(template, q); a != expect {
+				t.Errorf("Cardinal(%T(%v)) = %q, expected %q", q, q, a, expect)
+			}
+		}
+		expect := fmt.Sprintf(template, "x")
+		if a := r.Cardinal(template, "x"); a != expect {
+			t.Errorf("Cardinal(string) = %q, expected %q", a, expect)
+		}
+	})
+
+	t.Run("PluralRange", func(t *testing.T) {
+		template := samplePrefix + "range %v–%v"
+		forms := localize.CardinalForms(template)
+		for _, q := range Quantities {
+			expect := fmt.Sprintf(template, q, q)
+			if a := r.PluralRange(forms, q, q); a != expect {
+				t.Errorf("PluralRange(%T(%v), %T(%v)) = %q, expected %q",
+					q, q, q, q, a, expect)
+			}
+		}
+		other := localize.Forms{Other: template}
+		expect := fmt.Sprintf(template, "x", 2)
+		if a := r.PluralRange(other, "x", 2); a != expect {
+			t.Errorf("PluralRange(string, int) = %q, expected %q", a, expect)
+		}
+	})
+
+	t.Run("PluralOrdinal", func(t *testing.T) {
+		templates := sampleOrdinalForms("ordinal")
+		tr := r.Translator()
+		if tr == nil {
+			t.Skip("Translator() returned nil")
+		}
+		for _, q := range Quantities {
+			forms := ordinalFormsOf(templates, tr, q)
+			expect := fmt.Sprintf(formOf(forms, tr, q), q, q)
+			if a := r.PluralOrdinal(templates, q, q); a != expect {
+				t.Errorf("PluralOrdinal(%T(%v), %T(%v)) = %q, expected %q",
+					q, q, q, q, a, expect)
+			}
+		}
+		expect := fmt.Sprintf(templates.Other.Other, "x", "x")
+		if a := r.PluralOrdinal(templates, "x", "x"); a != expect {
+			t.Errorf("PluralOrdinal(string, string) = %q, expected %q", a, expect)
+		}
+	})
+
+	t.Run("Grammar", func(t *testing.T) {
+		// Undefined grammar entries fall back to the phrase.
+		expect := samplePrefix + "grammar a b"
+		if a := r.Grammar(samplePrefix+"undefined", samplePrefix+"grammar", "a", "b"); a != expect {
+			t.Errorf("Grammar(undefined) = %q, expected %q", a, expect)
+		}
+	})
+
+	t.Run("FormatCompact", func(t *testing.T) {
+		tr := r.Translator()
+		for _, n := range []float64{0, -1.5, 999, 1234, 999_999, 12_345_678} {
+			expect := localize.FormatCompact(r.Locale(), tr, n)
+			if a := r.FormatCompact(n); a != expect {
+				t.Errorf("FormatCompact(%v) = %q, expected %q", n, a, expect)
+			}
+		}
+	})
+
+	t.Run("Truncate", func(t *testing.T) {
+		for _, s := range []string{"", "Short", "Café au lait, s'il vous plaît"} {
+			for _, max := range []int{0, 1, 5, 40} {
+				expect := localize.Truncate(r.Locale(), s, max)
+				if a := r.Truncate(s, max); a != expect {
+					t.Errorf("Truncate(%q, %d) = %q, expected %q", s, max, a, expect)
+				}
+			}
+		}
+	})
+
+	t.Run("WithRegister", func(t *testing.T) {
+		// Undefined variants fall back to the regular translation.
+		text := samplePrefix + "register"
+		for _, register := range []localize.Register{
+			localize.RegisterDefault, localize.RegisterFormal, localize.RegisterInformal,
+		} {
+			w := r.WithRegister(register)
+			if w.Locale() != r.Locale() {
+				t.Errorf("WithRegister(%s).Locale() = %q, expected %q",
+					register, w.Locale(), r.Locale())
+			}
+			if a := w.Text(text); a != text {
+				t.Errorf("WithRegister(%s).Text = %q, expected %q", register, a, text)
+			}
+		}
+	})
+
+	t.Run("Section", func(t *testing.T) {
+		// Messages of sections fall back to the source text.
+		text := samplePrefix + "section"
+		for _, w := range []localize.Reader{
+			r.Section("Checkout"),
+			r.Section("Checkout").Section("Payment"),
+			r.Section("Checkout").WithRegister(localize.RegisterInformal),
+		} {
+			if w.Locale() != r.Locale() {
+				t.Errorf("Section.Locale() = %q, expected %q", w.Locale(), r.Locale())
+			}
+			if a := w.Text(text); a != text {
+				t.Errorf("Section.Text = %q, expected %q", a, text)
+			}
+			other := samplePrefix + "%d sections"
+			if a, e := w.Cardinal(other, 5), fmt.Sprintf(other, 5); a != e {
+				t.Errorf("Section.Cardinal = %q, expected %q", a, e)
+			}
+		}
+	})
+
+	t.Run("Cataloger", func(t *testing.T) {
+		c, ok := r.(localize.Cataloger)
+		if !ok {
+			t.Skip("reader doesn't implement localize.Cataloger")
+		}
+		var previous string
+		i := 0
+		for key, translation := range c.Messages() {
+			if key.Hash == "" {
+				t.Errorf("message %d has no hash", i)
+			}
+			if i > 0 && key.Hash <= previous {
+				t.Errorf("message %d (%q) is not ordered by hash after %q",
+					i, key.Hash, previous)
+			}
+			if translation.Plural && translation.Text != "" {
+				t.Errorf("plural message %q has static text", key.Hash)
+			}
+			previous = key.Hash
+			i++
+		}
+	})
+
+	t.Run("MetadataProvider", func(t *testing.T) {
+		p, ok := r.(localize.MetadataProvider)
+		if !ok {
+			t.Skip("reader doesn't implement localize.MetadataProvider")
+		}
+		m := p.Metadata()
+		if m == nil {
+			t.Fatalf("Metadata() returned nil")
+		}
+		m[samplePrefix+"header"] = "x"
+		if _, ok := p.Metadata()[samplePrefix+"header"]; ok {
+			t.Errorf("Metadata() returned a map shared between calls")
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		forms := sampleForms("concurrent")
+		template := samplePrefix + "concurrent cardinal %v"
+		read := func() string {
+			var b strings.Builder
+			b.WriteString(r.Locale().String())
+			b.WriteString(r.Base().String())
+			b.WriteString(r.Translator().Locale())
+			b.WriteString(r.Text(samplePrefix + "concurrent"))
+			b.WriteString(r.Block(samplePrefix + "\n\tconcurrent\n"))
+			for _, q := range Quantities {
+				b.WriteString(r.Plural(forms, q))
+				b.WriteString(r.PluralBlock(forms, q))
+				b.WriteString(r.Cardinal(template, q))
+			}
+			if c, ok := r.(localize.Cataloger); ok {
+				for key := range c.Messages() {
+					b.WriteString(key.Hash)
+				}
+			}
+			if p, ok := r.(localize.MetadataProvider); ok {
+				b.WriteString(p.Metadata()["Language"])
+			}
+			return b.String()
+		}
+
+		const goroutines = 16
+		results := make(chan string, goroutines)
+		var wg sync.WaitGroup
+		for range goroutines {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results <- read()
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		expect := read()
+		for a := range results {
+			if a != expect {
+				t.Errorf("concurrent calls returned different results")
+				break
+			}
+		}
+	})
+}
+
+func sampleForms(name string) localize.Forms {
+	return localize.Forms{
+		Zero:  samplePrefix + name + " zero %v",
+		One:   samplePrefix + name + " one %v",
+		Two:   samplePrefix + name + " two %v",
+		Few:   samplePrefix + name + " few %v",
+		Many:  samplePrefix + name + " many %v",
+		Other: samplePrefix + name + " other %v",
+	}
+}
+
+func sampleOrdinalForms(name string) localize.OrdinalForms {
+	forms := func(category string) localize.Forms {
+		f := sampleForms(name + " " + category)
+		for _, t := range []*string{&f.Zero, &f.One, &f.Two, &f.Few, &f.Many, &f.Other} {
+			*t += " %v"
+		}
+		return f
+	}
+	return localize.OrdinalForms{
+		Zero: forms("zero"), One: forms("one"), Two: forms("two"),
+		Few: forms("few"), Many: forms("many"), Other: forms("other"),
+	}
+}
+
+// ordinalFormsOf returns the forms selected for ordinal quantity q
+// by the ordinal plural rule of tr.
+func ordinalFormsOf(
+	templates localize.OrdinalForms, tr locales.Translator, q any,
+) localize.Forms {
+	f, ok := localize.Quantity(q)
+	if !ok {
+		return templates.Other
+	}
+	switch tr.OrdinalPluralRule(f, 0) {
+	case locales.PluralRuleZero:
+		return templates.Zero
+	case locales.PluralRuleOne:
+		return templates.One
+	case locales.PluralRuleTwo:
+		return templates.Two
+	case locales.PluralRuleFew:
+		return templates.Few
+	case locales.PluralRuleMany:
+		return templates.Many
+	}
+	return templates.Other
+}
+
+// formOf returns the template of the form selected for quantity q
+// by the cardinal plural rule of tr.
+func formOf(templates localize.Forms, tr locales.Translator, q any) string {
+	f, ok := localize.Quantity(q)
+	if !ok {
+		return templates.Other
+	}
+	switch tr.CardinalPluralRule(f, 0) {
+	case locales.PluralRuleZero:
+		return templates.Zero
+	case locales.PluralRuleOne:
+		return templates.One
+	case locales.PluralRuleTwo:
+		return templates.Two
+	case locales.PluralRuleFew:
+		return templates.Few
+	case locales.PluralRuleMany:
+		return templates.Many
+	}
+	return templates.Other
+}
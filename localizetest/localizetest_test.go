@@ -0,0 +1,72 @@
+package localizetest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/ru"
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/localizetest"
+	"github.com/romshark/localize/strfmt"
+	"golang.org/x/text/language"
+)
+
+// sourceReader is a minimal conforming reader without a catalog.
+type sourceReader struct{ tr locales.Translator }
+
+var _ localize.Reader = sourceReader{}
+
+func (r sourceReader) Locale() language.Tag { return language.Russian }
+
+func (r sourceReader) Base() language.Base {
+	b, _ := language.Russian.Base()
+	return b
+}
+func (r sourceReader) Text(text string) string  { return text }
+func (r sourceReader) Block(text string) string { return strfmt.Dedent(text) }
+
+func (r sourceReader) Plural(templates localize.Forms, quantity any) string {
+	return fmt.Sprintf(templates.CardinalForm(r.tr, quantity), quantity)
+}
+
+func (r sourceReader) PluralBlock(templates localize.Forms, quantity any) string {
+	return strfmt.Dedent(r.Plural(templates, quantity))
+}
+
+func (r sourceReader) Cardinal(otherTemplate string, quantity any) string {
+	return r.Plural(localize.CardinalForms(otherTemplate), quantity)
+}
+
+func (r sourceReader) PluralRange(templates localize.Forms, from, to any) string {
+	return fmt.Sprintf(templates.RangeForm(r.tr, from, to), from, to)
+}
+
+func (r sourceReader) PluralOrdinal(
+	templates localize.OrdinalForms, ordinal, quantity any,
+) string {
+	f := templates.Forms(r.tr, ordinal)
+	return fmt.Sprintf(f.CardinalForm(r.tr, quantity), ordinal, quantity)
+}
+
+func (r sourceReader) Grammar(key string, args ...string) string {
+	return localize.GrammarPhrase(args...)
+}
+
+func (r sourceReader) FormatCompact(n float64) string {
+	return localize.FormatCompact(r.Locale(), r.tr, n)
+}
+
+func (r sourceReader) Truncate(s string, max int) string {
+	return localize.Truncate(r.Locale(), s, max)
+}
+
+func (r sourceReader) WithRegister(localize.Register) localize.Reader { return r }
+
+func (r sourceReader) Section(string) localize.Reader { return r }
+
+func (r sourceReader) Translator() locales.Translator { return r.tr }
+
+func TestReaderConformance(t *testing.T) {
+	localizetest.TestReaderConformance(t, sourceReader{tr: ru.New()})
+}
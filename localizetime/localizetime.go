@@ -0,0 +1,135 @@
+// Package localizetime formats localized weekday and month names,
+// date-time ranges and recurring schedules, such as
+// "every Mon–Fri at 9:00 am", using the CLDR data of the translator
+// of a localize.Reader.
+//
+// Schedule texts passed to Weekly are extracted by localize generate
+// like texts passed to localize.Reader methods:
+//
+//	// Recurring opening hours, like "every Mon–Fri at 9:00 am".
+//	s := localizetime.Weekly(r, "every %[1]s at %[2]s", opensAt,
+//		time.Monday, time.Tuesday, time.Wednesday,
+//		time.Thursday, time.Friday,
+//	)
+package localizetime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/romshark/localize"
+)
+
+// RangeSeparator separates the start and end of ranges.
+const RangeSeparator = "–"
+
+// ListSeparator separates the weekdays and ranges of weekdays
+// listed by Weekdays.
+const ListSeparator = ", "
+
+// Weekday returns the wide localized name of d, such as "Monday".
+func Weekday(r localize.Reader, d time.Weekday) string {
+	return r.Translator().WeekdayWide(d)
+}
+
+// WeekdayShort returns the abbreviated localized name of d, such as "Mon".
+func WeekdayShort(r localize.Reader, d time.Weekday) string {
+	return r.Translator().WeekdayAbbreviated(d)
+}
+
+// Month returns the wide localized name of m, such as "January".
+func Month(r localize.Reader, m time.Month) string {
+	return r.Translator().MonthWide(m)
+}
+
+// MonthShort returns the abbreviated localized name of m, such as "Jan".
+func MonthShort(r localize.Reader, m time.Month) string {
+	return r.Translator().MonthAbbreviated(m)
+}
+
+// Weekdays returns the abbreviated localized names of days ordered from
+// Monday to Sunday. Three or more consecutive days are joined to a range:
+//
+//	Mon–Fri
+//	Mon, Wed, Sat, Sun
+//	Mon–Wed, Fri
+//
+// Duplicate days are ignored.
+func Weekdays(r localize.Reader, days ...time.Weekday) string {
+	tr := r.Translator()
+	// Index days from Monday (0) to Sunday (6).
+	var week [7]bool
+	for _, d := range days {
+		week[(d+6)%7] = true
+	}
+	var l []string
+	for i := 0; i < len(week); i++ {
+		if !week[i] {
+			continue
+		}
+		end := i
+		for end+1 < len(week) && week[end+1] {
+			end++
+		}
+		first := tr.WeekdayAbbreviated(time.Weekday((i + 1) % 7))
+		switch end - i {
+		case 0:
+			l = append(l, first)
+		case 1:
+			l = append(l, first, tr.WeekdayAbbreviated(time.Weekday((end+1)%7)))
+		default:
+			l = append(l, first+RangeSeparator+
+				tr.WeekdayAbbreviated(time.Weekday((end+1)%7)))
+		}
+		i = end
+	}
+	return strings.Join(l, ListSeparator)
+}
+
+// Range returns the localized date-time range from start to end
+// in the location of start, such as:
+//
+//	Jan 2, 2025 9:00 am–10:30 am
+//	Jan 2, 2025 9:00 pm – Jan 3, 2025 1:00 am
+//
+// end is converted to the location of start.
+// Use RangeIn to format ranges in the location of the user.
+func Range(r localize.Reader, start, end time.Time) string {
+	tr := r.Translator()
+	end = end.In(start.Location())
+	if sameDay(start, end) {
+		return tr.FmtDateMedium(start) + " " +
+			tr.FmtTimeShort(start) + RangeSeparator + tr.FmtTimeShort(end)
+	}
+	return tr.FmtDateMedium(start) + " " + tr.FmtTimeShort(start) +
+		" " + RangeSeparator + " " +
+		tr.FmtDateMedium(end) + " " + tr.FmtTimeShort(end)
+}
+
+// RangeIn is like Range but converts start and end to loc
+// and appends the abbreviated name of the time zone, such as:
+//
+//	Jan 2, 2025 9:00 am–10:30 am CET
+func RangeIn(r localize.Reader, start, end time.Time, loc *time.Location) string {
+	start = start.In(loc)
+	return Range(r, start, end) + " " + start.Format("MST")
+}
+
+// Weekly localizes text using Reader.Text and formats it with the
+// localized weekdays (see Weekdays) as first and the short localized
+// time of day of at as second argument, such as
+// "every %[1]s at %[2]s" reading "every Mon–Fri at 9:00 am".
+// Only the time of day of at in its location is used.
+func Weekly(
+	r localize.Reader, text string, at time.Time, days ...time.Weekday,
+) string {
+	return fmt.Sprintf(r.Text(text),
+		Weekdays(r, days...), r.Translator().FmtTimeShort(at))
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
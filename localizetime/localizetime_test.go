@@ -0,0 +1,70 @@
+package localizetime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-playground/locales/en"
+	"github.com/romshark/localize/internal/xtexttest"
+	"github.com/romshark/localize/localizetime"
+	"github.com/romshark/localize/xtextcatalog"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+var testMessages = map[string]catalog.Message{
+	"every %[1]s at %[2]s": catalog.String("jeden %[1]s um %[2]s"),
+}
+
+func TestNames(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	require.Equal(t, "Montag", localizetime.Weekday(r, time.Monday))
+	require.Equal(t, "Mo.", localizetime.WeekdayShort(r, time.Monday))
+	require.Equal(t, "März", localizetime.Month(r, time.March))
+	require.Equal(t, "März", localizetime.MonthShort(r, time.March))
+}
+
+func TestWeekdays(t *testing.T) {
+	r := xtextcatalog.NewReader(catalog.NewBuilder(), language.English, en.New())
+	for _, tt := range []struct {
+		days   []time.Weekday
+		expect string
+	}{
+		{nil, ""},
+		{[]time.Weekday{time.Sunday}, "Sun"},
+		{[]time.Weekday{
+			time.Friday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+		}, "Mon–Fri"},
+		{[]time.Weekday{time.Saturday, time.Sunday, time.Monday}, "Mon, Sat, Sun"},
+		{[]time.Weekday{
+			time.Monday, time.Tuesday, time.Wednesday, time.Friday, time.Friday,
+		}, "Mon–Wed, Fri"},
+	} {
+		require.Equal(t, tt.expect, localizetime.Weekdays(r, tt.days...))
+	}
+}
+
+func TestRange(t *testing.T) {
+	r := xtextcatalog.NewReader(catalog.NewBuilder(), language.English, en.New())
+	start := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	require.Equal(t, "Jan 2, 2025 9:00 am–10:30 am",
+		localizetime.Range(r, start, start.Add(90*time.Minute)))
+	require.Equal(t, "Jan 2, 2025 9:00 am – Jan 3, 2025 10:30 am",
+		localizetime.Range(r, start, start.Add(25*time.Hour+30*time.Minute)))
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	require.Equal(t, "Jan 2, 2025 6:00 pm–7:30 pm JST",
+		localizetime.RangeIn(r, start, start.Add(90*time.Minute), tokyo))
+	require.Equal(t, "Jan 2, 2025 11:00 pm – Jan 3, 2025 1:00 am JST",
+		localizetime.RangeIn(r, start.Add(5*time.Hour), start.Add(7*time.Hour), tokyo))
+}
+
+func TestWeekly(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	at := time.Date(2025, 1, 1, 9, 30, 0, 0, time.UTC)
+	require.Equal(t, "jeden Mo.–Fr. um 09:30",
+		localizetime.Weekly(r, "every %[1]s at %[2]s", at,
+			time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday))
+}
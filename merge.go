@@ -0,0 +1,227 @@
+package localize
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// MergeReader returns a reader that provides every message from the first
+// reader of others whose catalog contains a translation of it and from primary
+// otherwise. This allows combining the readers of separately generated
+// bundles, such as the bundles of plugins generated with
+// `localize generate -only`.
+// Readers of others must be of the same locale as primary, readers of other
+// locales as well as readers that neither implement Cataloger nor wrap
+// a reader implementing it are ignored.
+// The returned reader implements Cataloger.
+func MergeReader(primary Reader, others ...Reader) Reader {
+	m := &mergeReader{
+		Reader: primary,
+		static: map[string]int{},
+		plural: map[string]int{},
+	}
+	byHash := map[string]mergeMessage{}
+	add := func(r Reader, isPrimary bool) {
+		index := len(m.others)
+		if !isPrimary {
+			m.others = append(m.others, r)
+		}
+		c, ok := findCataloger(r)
+		if !ok {
+			return
+		}
+		for k, t := range c.Messages() {
+			if e, ok := byHash[k.Hash]; !ok ||
+				!isTranslated(e.translation) && isTranslated(t) {
+				byHash[k.Hash] = mergeMessage{key: k, translation: t}
+			}
+			if isPrimary || !isTranslated(t) {
+				continue
+			}
+			readers := m.static
+			if t.Plural {
+				readers = m.plural
+			}
+			if _, ok := readers[k.Source]; !ok {
+				readers[k.Source] = index
+			}
+		}
+	}
+	for _, r := range others {
+		if r.Locale() == primary.Locale() {
+			add(r, false)
+		}
+	}
+	add(primary, true)
+	m.messages = slices.SortedFunc(maps.Values(byHash), func(a, b mergeMessage) int {
+		return strings.Compare(a.key.Hash, b.key.Hash)
+	})
+	return m
+}
+
+// MergeReaders merges the readers of primary with the readers of the same
+// locale of others using MergeReader.
+// Locales not provided by primary are ignored.
+// For example, to combine the main bundle with the bundle of a plugin:
+//
+//	localize.New(language.English, localize.MergeReaders(
+//		localizebundle.Readers(), pluginbundle.Readers(),
+//	)...)
+func MergeReaders(primary iter.Seq[Reader], others ...iter.Seq[Reader]) []Reader {
+	var all []Reader
+	for _, o := range others {
+		all = slices.AppendSeq(all, o)
+	}
+	var merged []Reader
+	for r := range primary {
+		merged = append(merged, MergeReader(r, all...))
+	}
+	return merged
+}
+
+// Merge returns a new bundle combining the readers of all bundles,
+// such as the bundle of a library and the bundle of the application using it.
+// The readers of each locale are merged using MergeReader with the reader
+// of the first bundle providing the locale as primary, such that messages are
+// read from the first bundle translating them.
+// The default locale and match mode are those of the first bundle.
+// Returns ErrEmptyBundle if no bundles are passed.
+func Merge(bundles ...*Bundle) (*Bundle, error) {
+	if len(bundles) < 1 {
+		return nil, ErrEmptyBundle
+	}
+	var locales []language.Tag
+	byLocale := map[language.Tag][]Reader{}
+	for _, b := range bundles {
+		for i, r := range b.readers {
+			l := b.locales[i]
+			if _, ok := byLocale[l]; !ok {
+				locales = append(locales, l)
+			}
+			byLocale[l] = append(byLocale[l], r)
+		}
+	}
+	readers := make([]Reader, len(locales))
+	for i, l := range locales {
+		if r := byLocale[l]; len(r) > 1 {
+			readers[i] = MergeReader(r[0], r[1:]...)
+		} else {
+			readers[i] = r[0]
+		}
+	}
+	return NewWithOptions(bundles[0].defaultReader.Locale(), Options{
+		MatchMode: bundles[0].matchMode,
+	}, readers...)
+}
+
+type mergeMessage struct {
+	key         Key
+	translation Translation
+}
+
+type mergeReader struct {
+	Reader
+
+	// others are the merged readers other than the primary reader.
+	others []Reader
+
+	// static and plural map the source of every message translated
+	// by one of the merged readers to the index of the reader
+	// translating it in others.
+	static, plural map[string]int
+
+	messages []mergeMessage
+}
+
+var _ Cataloger = new(mergeReader)
+
+// Unwrap returns the primary reader.
+func (m *mergeReader) Unwrap() Reader { return m.Reader }
+
+func (m *mergeReader) Text(text string) string {
+	if i, ok := m.static[text]; ok {
+		return m.others[i].Text(text)
+	}
+	return m.Reader.Text(text)
+}
+
+func (m *mergeReader) Block(text string) string {
+	if i, ok := m.static[blockKey(m.static, text)]; ok {
+		return m.others[i].Block(text)
+	}
+	return m.Reader.Block(text)
+}
+
+func (m *mergeReader) Plural(templates Forms, quantity any) string {
+	if i, ok := m.plural[templates.Other]; ok {
+		return m.others[i].Plural(templates, quantity)
+	}
+	return m.Reader.Plural(templates, quantity)
+}
+
+func (m *mergeReader) PluralBlock(templates Forms, quantity any) string {
+	if i, ok := m.plural[blockKey(m.plural, templates.Other)]; ok {
+		return m.others[i].PluralBlock(templates, quantity)
+	}
+	return m.Reader.PluralBlock(templates, quantity)
+}
+
+func (m *mergeReader) Cardinal(otherTemplate string, quantity any) string {
+	if i, ok := m.plural[otherTemplate]; ok {
+		return m.others[i].Cardinal(otherTemplate, quantity)
+	}
+	return m.Reader.Cardinal(otherTemplate, quantity)
+}
+
+func (m *mergeReader) PluralRange(templates Forms, from, to any) string {
+	if i, ok := m.plural[templates.Other]; ok {
+		return m.others[i].PluralRange(templates, from, to)
+	}
+	return m.Reader.PluralRange(templates, from, to)
+}
+
+func (m *mergeReader) PluralOrdinal(templates OrdinalForms, ordinal, quantity any) string {
+	forms := templates.Forms(m.Translator(), ordinal)
+	if i, ok := m.plural[forms.Other]; ok {
+		return m.others[i].PluralOrdinal(templates, ordinal, quantity)
+	}
+	return m.Reader.PluralOrdinal(templates, ordinal, quantity)
+}
+
+// WithRegister returns the merged readers of register.
+func (m *mergeReader) WithRegister(register Register) Reader {
+	w := *m
+	w.Reader = m.Reader.WithRegister(register)
+	w.others = make([]Reader, len(m.others))
+	for i, r := range m.others {
+		w.others[i] = r.WithRegister(register)
+	}
+	return &w
+}
+
+// Section returns the merged readers of section name.
+func (m *mergeReader) Section(name string) Reader {
+	w := *m
+	w.Reader = m.Reader.Section(name)
+	w.others = make([]Reader, len(m.others))
+	for i, r := range m.others {
+		w.others[i] = r.Section(name)
+	}
+	return &w
+}
+
+// Messages returns an iterator over the messages of all merged catalogs
+// ordered by hash.
+func (m *mergeReader) Messages() iter.Seq2[Key, Translation] {
+	return func(yield func(Key, Translation) bool) {
+		for _, e := range m.messages {
+			if !yield(e.key, e.translation) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package localize_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestMergeReader(t *testing.T) {
+	main := MockCatalogReader{
+		MockReader: MockReader{tag: language.German, static: map[string]string{
+			"Hello": "Hallo",
+			"Save":  "Speichern (main)",
+		}},
+		messages: []MockCatalogMessage{
+			{
+				Key:         localize.Key{Hash: "b", Source: "Hello"},
+				Translation: localize.Translation{Text: "Hallo"},
+			},
+			{
+				Key:         localize.Key{Hash: "c", Source: "Save"},
+				Translation: localize.Translation{Text: "Speichern (main)"},
+			},
+			{
+				Key:         localize.Key{Hash: "d", Source: "Untranslated"},
+				Translation: localize.Translation{},
+			},
+		},
+	}
+	plugin := MockCatalogReader{
+		MockReader: MockReader{tag: language.German, static: map[string]string{
+			"Save":         "Speichern (plugin)",
+			"Plugin":       "Erweiterung",
+			"Untranslated": "Übersetzt",
+			"Hello":        "",
+		}},
+		messages: []MockCatalogMessage{
+			{
+				Key:         localize.Key{Hash: "a", Source: "Plugin"},
+				Translation: localize.Translation{Text: "Erweiterung"},
+			},
+			{
+				Key:         localize.Key{Hash: "b", Source: "Hello"},
+				Translation: localize.Translation{},
+			},
+			{
+				Key:         localize.Key{Hash: "c", Source: "Save"},
+				Translation: localize.Translation{Text: "Speichern (plugin)"},
+			},
+			{
+				Key:         localize.Key{Hash: "d", Source: "Untranslated"},
+				Translation: localize.Translation{Text: "Übersetzt"},
+			},
+		},
+	}
+	french := MockCatalogReader{
+		MockReader: MockReader{tag: language.French, static: map[string]string{
+			"Hello": "Bonjour",
+		}},
+		messages: []MockCatalogMessage{{
+			Key:         localize.Key{Hash: "b", Source: "Hello"},
+			Translation: localize.Translation{Text: "Bonjour"},
+		}},
+	}
+
+	r := localize.MergeReader(main, french, plugin)
+	require.Equal(t, language.German, r.Locale())
+	require.Equal(t, "Hallo", r.Text("Hello"))
+	require.Equal(t, "Speichern (plugin)", r.Text("Save"))
+	require.Equal(t, "Erweiterung", r.Text("Plugin"))
+	require.Equal(t, "Übersetzt", r.Text("Untranslated"))
+	require.Equal(t, "Erweiterung", r.Block("Plugin"))
+	require.Equal(t, "", r.Text("Unknown"))
+
+	c, ok := r.(localize.Cataloger)
+	require.True(t, ok)
+	require.Equal(t, map[string]string{
+		"a": "Erweiterung",
+		"b": "Hallo",
+		"c": "Speichern (plugin)",
+		"d": "Übersetzt",
+	}, maps.Collect(func(yield func(string, string) bool) {
+		for k, tr := range c.Messages() {
+			if !yield(k.Hash, tr.Text) {
+				return
+			}
+		}
+	}))
+	hashes := slices.Collect(func(yield func(string) bool) {
+		for k := range c.Messages() {
+			if !yield(k.Hash) {
+				return
+			}
+		}
+	})
+	require.Equal(t, []string{"a", "b", "c", "d"}, hashes)
+}
+
+func TestMergeReaders(t *testing.T) {
+	main := []localize.Reader{
+		MockReader{tag: language.English},
+		MockReader{tag: language.German},
+	}
+	plugin := []localize.Reader{
+		MockCatalogReader{
+			MockReader: MockReader{tag: language.German, static: map[string]string{
+				"Plugin": "Erweiterung",
+			}},
+			messages: []MockCatalogMessage{{
+				Key:         localize.Key{Hash: "a", Source: "Plugin"},
+				Translation: localize.Translation{Text: "Erweiterung"},
+			}},
+		},
+		MockReader{tag: language.French},
+	}
+	merged := localize.MergeReaders(slices.Values(main), slices.Values(plugin))
+	b, err := localize.New(language.English, merged...)
+	require.NoError(t, err)
+	require.Equal(t, []language.Tag{language.English, language.German}, b.Locales())
+	require.Equal(t, "Erweiterung", b.ForBase(language.MustParseBase("de")).Text("Plugin"))
+}
+
+func TestMerge(t *testing.T) {
+	app, err := localize.NewWithOptions(language.English,
+		localize.Options{MatchMode: localize.MatchExact},
+		MockReader{tag: language.English},
+		MockCatalogReader{
+			MockReader: MockReader{tag: language.German, static: map[string]string{
+				"Open": "Öffnen",
+			}},
+			messages: []MockCatalogMessage{{
+				Key:         localize.Key{Hash: "a", Source: "Open"},
+				Translation: localize.Translation{Text: "Öffnen"},
+			}},
+		},
+	)
+	require.NoError(t, err)
+	lib, err := localize.New(language.English,
+		MockReader{tag: language.English},
+		MockCatalogReader{
+			MockReader: MockReader{tag: language.German, static: map[string]string{
+				"Retry": "Wiederholen",
+			}},
+			messages: []MockCatalogMessage{{
+				Key:         localize.Key{Hash: "b", Source: "Retry"},
+				Translation: localize.Translation{Text: "Wiederholen"},
+			}},
+		},
+		MockReader{tag: language.French, static: map[string]string{
+			"Retry": "Réessayer",
+		}},
+	)
+	require.NoError(t, err)
+
+	b, err := localize.Merge(app, lib)
+	require.NoError(t, err)
+	require.Equal(t, []language.Tag{
+		language.English, language.German, language.French,
+	}, b.Locales())
+	require.Equal(t, language.English, b.Default().Locale())
+
+	de := b.ForLocale(language.German)
+	require.Equal(t, "Öffnen", de.Text("Open"))
+	require.Equal(t, "Wiederholen", de.Text("Retry"))
+	require.Equal(t, "Réessayer", b.ForLocale(language.French).Text("Retry"))
+
+	r, _ := b.Match(language.MustParse("de-AT"))
+	require.Nil(t, r, "match mode of the first bundle")
+
+	_, err = localize.Merge()
+	require.ErrorIs(t, err, localize.ErrEmptyBundle)
+}
@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Summary is the summary of a generate run received by post-generate hooks
+// enabled with
+//
+//	localize generate -post-generate "upload-pot --project web"
+//
+// as JSON on stdin after all files are written. It's also the content of
+// the file written by -summary.
+type Summary struct {
+	// Messages is the number of unique messages in the source code.
+	Messages int64 `json:"messages"`
+
+	// Locales are the changes of the translation catalogs ordered by locale.
+	Locales []LocaleSummary `json:"locales"`
+
+	// Files are the slash-separated paths of all written files in ascending order.
+	Files []string `json:"files"`
+
+	// Phases are the phases of the run in the order of execution.
+	Phases []Phase `json:"phases"`
+
+	TimeTotalNanoseconds int64 `json:"timeTotalNanoseconds"`
+}
+
+// LocaleSummary are the changes of the translation catalogs of a locale.
+type LocaleSummary struct {
+	Locale string `json:"locale"`
+
+	// New is the number of messages added to the catalogs.
+	New int `json:"new"`
+
+	// Changed is the number of existing messages whose references
+	// or flags changed.
+	Changed int `json:"changed"`
+
+	// Obsoleted is the number of messages marked obsolete.
+	Obsoleted int `json:"obsoleted"`
+}
+
+// Phase is a timed phase of a generate run.
+type Phase struct {
+	Name                string `json:"name"`
+	DurationNanoseconds int64  `json:"durationNanoseconds"`
+}
+
+// Hook reads the Summary from stdin and calls hook, which implements
+// a post-generate hook in Go:
+//
+//	func main() {
+//		plugin.Hook(func(s *plugin.Summary) error {
+//			// Upload the catalog template if any catalog changed.
+//		})
+//	}
+//
+// Hook exits with status 1 if the summary can't be read or hook returns
+// an error, which fails the generate run.
+func Hook(hook func(*Summary) error) {
+	if err := runHook(os.Stdin, hook); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func runHook(r io.Reader, hook func(*Summary) error) error {
+	var s Summary
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return fmt.Errorf("decoding summary: %w", err)
+	}
+	return hook(&s)
+}
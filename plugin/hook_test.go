@@ -0,0 +1,39 @@
+package plugin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHook(t *testing.T) {
+	t.Parallel()
+
+	in := `{"messages":3,"locales":[{"locale":"de","new":1,"changed":0,"obsoleted":2}],` +
+		`"files":["localizebundle/catalog.pot"],"phases":[],"timeTotalNanoseconds":7}`
+	var received *Summary
+	err := runHook(strings.NewReader(in), func(s *Summary) error {
+		received = s
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, &Summary{
+		Messages:             3,
+		Locales:              []LocaleSummary{{Locale: "de", New: 1, Obsoleted: 2}},
+		Files:                []string{"localizebundle/catalog.pot"},
+		Phases:               []Phase{},
+		TimeTotalNanoseconds: 7,
+	}, received)
+
+	errHook := errors.New("upload failed")
+	err = runHook(strings.NewReader(in), func(*Summary) error { return errHook })
+	require.ErrorIs(t, err, errHook)
+
+	err = runHook(strings.NewReader("{"), func(*Summary) error {
+		t.Fatal("hook called with invalid summary")
+		return nil
+	})
+	require.ErrorContains(t, err, "decoding summary")
+}
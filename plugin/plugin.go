@@ -0,0 +1,148 @@
+// Package plugin defines the protocol of localize output plugins.
+//
+// Output plugins add custom export formats to the generate command
+// without changes to localize itself. Similar to protoc, a plugin is an
+// executable that reads a JSON encoded Request from stdin and writes a JSON
+// encoded Response to stdout. The plugin named "foo" enabled with
+//
+//	localize generate -plugin foo=out/foo
+//
+// is resolved as executable "localize-gen-foo" in PATH and the files of
+// its response are written to directory "out/foo".
+// Names containing a path separator are executed as the given path instead.
+//
+// Plugins written in Go can use Main to implement the protocol:
+//
+//	func main() {
+//		plugin.Main(func(req *plugin.Request) (*plugin.Response, error) {
+//			// Encode req.Messages in the custom format.
+//		})
+//	}
+//
+// Post-generate hooks run after all files are written and receive
+// the Summary of the run instead, see Hook.
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ProtocolVersion is the version of the protocol defined by this package.
+const ProtocolVersion = 1
+
+// ExecutablePrefix is the prefix of plugin executable names.
+const ExecutablePrefix = "localize-gen-"
+
+// Request is the input of a plugin.
+type Request struct {
+	// Version is the ProtocolVersion of the generate command.
+	Version int `json:"version"`
+
+	// SourceLocale is the BCP 47 locale of the source code texts.
+	SourceLocale string `json:"sourceLocale"`
+
+	// Locales are the BCP 47 locales of all translation catalogs
+	// ordered alphabetically.
+	Locales []string `json:"locales"`
+
+	// Messages are all messages of the source code ordered by hash.
+	Messages []Message `json:"messages"`
+}
+
+// Message is a message extracted from the source code.
+type Message struct {
+	// Hash is the unique hash of the message
+	// used as msgctxt in the gettext catalogs.
+	Hash string `json:"hash"`
+
+	// Description is the description comment of the message, if any.
+	Description string `json:"description,omitempty"`
+
+	// References are the source code positions of the message
+	// in the format "path:line".
+	References []string `json:"references,omitempty"`
+
+	// Source are the forms of the source text.
+	Source []Form `json:"source"`
+
+	// Translations are the translations of the message
+	// in the order of Request.Locales.
+	Translations []Translation `json:"translations"`
+}
+
+// Translation is the translation of a message to a locale.
+type Translation struct {
+	// Locale is the BCP 47 locale of the translation catalog.
+	Locale string `json:"locale"`
+
+	// Translated is false if any form of the message isn't translated.
+	Translated bool `json:"translated"`
+
+	// Forms are the translated forms, which are empty
+	// if the catalog doesn't contain the message.
+	Forms []Form `json:"forms,omitempty"`
+}
+
+// Form is a text of a message. Static messages (Text and Block) have a single
+// form without name, plural messages (Plural, PluralBlock and Cardinal) have
+// one form per CLDR plural form of the locale named like "One" or "Other".
+type Form struct {
+	Name string `json:"name,omitempty"`
+	Text string `json:"text"`
+}
+
+// Response is the output of a plugin.
+type Response struct {
+	// Error is reported by the generate command if not empty.
+	// Plugins should report errors caused by the input in Error and exit
+	// with status 0, a non-zero exit status indicates a plugin failure.
+	Error string `json:"error,omitempty"`
+
+	// Files are written to the output directory of the plugin.
+	Files []File `json:"files,omitempty"`
+}
+
+// File is a file generated by a plugin.
+type File struct {
+	// Name is the slash-separated path of the file relative to the output
+	// directory of the plugin. Name must not escape the output directory.
+	Name string `json:"name"`
+
+	// Content is the content of the file.
+	Content string `json:"content"`
+}
+
+// Main reads the Request from stdin, calls generate and writes
+// the Response to stdout. Errors returned by generate are reported
+// in Response.Error. Main exits with status 1 if the request can't be read
+// or the response can't be written.
+func Main(generate func(*Request) (*Response, error)) {
+	if err := run(os.Stdin, os.Stdout, generate); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(r io.Reader, w io.Writer, generate func(*Request) (*Response, error)) error {
+	var req Request
+	if err := json.NewDecoder(r).Decode(&req); err != nil {
+		return fmt.Errorf("decoding request: %w", err)
+	}
+	if req.Version != ProtocolVersion {
+		return fmt.Errorf("unsupported protocol version %d, expected %d",
+			req.Version, ProtocolVersion)
+	}
+	resp, err := generate(&req)
+	if err != nil {
+		resp = &Response{Error: err.Error()}
+	} else if resp == nil {
+		resp = &Response{}
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return fmt.Errorf("encoding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package plugin
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	in := `{"version":1,"sourceLocale":"en","locales":["de"],"messages":[` +
+		`{"hash":"abc","source":[{"text":"Hello"}],` +
+		`"translations":[{"locale":"de","translated":true,"forms":[{"text":"Hallo"}]}]}]}`
+	var out bytes.Buffer
+	err := run(strings.NewReader(in), &out, func(req *Request) (*Response, error) {
+		require.Equal(t, &Request{
+			Version:      1,
+			SourceLocale: "en",
+			Locales:      []string{"de"},
+			Messages: []Message{{
+				Hash:   "abc",
+				Source: []Form{{Text: "Hello"}},
+				Translations: []Translation{{
+					Locale: "de", Translated: true, Forms: []Form{{Text: "Hallo"}},
+				}},
+			}},
+		}, req)
+		return &Response{Files: []File{{Name: "de.txt", Content: "Hallo"}}}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"files":[{"name":"de.txt","content":"Hallo"}]}`+"\n", out.String())
+}
+
+func TestRunError(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	err := run(strings.NewReader(`{"version":1}`), &out,
+		func(*Request) (*Response, error) { return nil, errors.New("bad input") })
+	require.NoError(t, err)
+	require.Equal(t, `{"error":"bad input"}`+"\n", out.String())
+}
+
+func TestRunUnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	err := run(strings.NewReader(`{"version":2}`), &out,
+		func(*Request) (*Response, error) { panic("unreachable") })
+	require.ErrorContains(t, err, "unsupported protocol version 2")
+	require.Zero(t, out.Len())
+}
@@ -0,0 +1,129 @@
+package localize
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/locales"
+	"github.com/romshark/localize/internal/cldr"
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrNoTranslator       = errors.New("no translator")
+	ErrTranslatorMismatch = errors.New("translator of another language")
+	ErrCatalogOrder       = errors.New("catalog not ordered by hash")
+	ErrIncompletePlural   = errors.New("incomplete plural translation")
+	ErrPluralText         = errors.New("plural message with static text")
+)
+
+// Problem is a problem of a reader of a bundle found by Bundle.Preflight.
+type Problem struct {
+	Locale language.Tag
+
+	// Key is the message the problem was found in.
+	// Key is zero for problems of the reader itself.
+	Key Key
+
+	// Err is the problem wrapping one of ErrNoTranslator,
+	// ErrTranslatorMismatch, ErrUnsupportedLocale, ErrCatalogOrder,
+	// ErrIncompletePlural and ErrPluralText.
+	Err error
+}
+
+func (p Problem) Error() string {
+	if p.Key.Hash != "" {
+		return fmt.Sprintf("%s: message %s (%q): %v",
+			p.Locale, p.Key.Hash, p.Key.Source, p.Err)
+	}
+	return fmt.Sprintf("%s: %v", p.Locale, p.Err)
+}
+
+func (p Problem) Unwrap() error { return p.Err }
+
+// Preflight validates all readers of the bundle and returns the problems
+// found, which is nil if there are none. It's meant to be run at startup
+// such that misconfigured bundles fail fast instead of on the first request
+// of an affected locale. Preflight constructs the translators of all readers
+// (see Options.EagerTranslators) and checks that:
+//
+//   - the translator exists and localizes for the base language of the reader,
+//     recovering panics of Reader.Translator.
+//   - plural rules are available for the locale of the reader.
+//   - the catalogs of readers implementing Cataloger are ordered by hash
+//     and have no plural messages with static text.
+//   - translated plural messages define all forms required by the cardinal
+//     plural rules of the locale (see Forms.Complete), since missing forms
+//     fall back to the source text.
+//
+// Preflight iterates over all catalogs on every call.
+func (l *Bundle) Preflight() []Problem {
+	var problems []Problem
+	for i, r := range l.readers {
+		problems = append(problems, preflight(l.locales[i], r)...)
+	}
+	return problems
+}
+
+// preflight returns the problems of reader r of locale.
+func preflight(locale language.Tag, r Reader) (problems []Problem) {
+	report := func(key Key, err error) {
+		problems = append(problems, Problem{Locale: locale, Key: key, Err: err})
+	}
+
+	if _, ok := cldr.ByTagOrBase(locale); !ok {
+		report(Key{}, ErrUnsupportedLocale)
+	}
+
+	tr, err := translatorOf(r)
+	if err != nil {
+		report(Key{}, err)
+	} else if translatorBase(tr) != r.Base() {
+		report(Key{}, fmt.Errorf("%w: %s", ErrTranslatorMismatch, tr.Locale()))
+		tr = nil // Plural rules of another language can't be checked against.
+	}
+
+	c, ok := findCataloger(r)
+	if !ok {
+		return problems
+	}
+	var previous string
+	first := true
+	for key, t := range c.Messages() {
+		if !first && key.Hash <= previous {
+			report(key, fmt.Errorf("%w: after %s", ErrCatalogOrder, previous))
+		}
+		first, previous = false, key.Hash
+		if !t.Plural {
+			continue
+		}
+		if t.Text != "" {
+			report(key, ErrPluralText)
+		}
+		if tr != nil && isTranslated(t) && !t.Forms.Complete(tr) {
+			report(key, fmt.Errorf("%w: %s", ErrIncompletePlural, t.Forms))
+		}
+	}
+	return problems
+}
+
+// translatorOf returns the translator of r recovering panics.
+func translatorOf(r Reader) (tr locales.Translator, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%w: Translator panicked: %v", ErrNoTranslator, p)
+		}
+	}()
+	if tr = r.Translator(); tr == nil {
+		return nil, ErrNoTranslator
+	}
+	return tr, nil
+}
+
+// translatorBase returns the base language of tr.
+func translatorBase(tr locales.Translator) language.Base {
+	t, _ := language.Parse(strings.ReplaceAll(tr.Locale(), "_", "-"))
+	base, _ := t.Base()
+	return base
+}
@@ -0,0 +1,87 @@
+package localize_test
+
+import (
+	"testing"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+type preflightReader struct {
+	MockCatalogReader
+	tr locales.Translator
+}
+
+func (r preflightReader) Translator() locales.Translator { return r.tr }
+
+func TestPreflight(t *testing.T) {
+	source := preflightReader{
+		MockCatalogReader: MockCatalogReader{
+			MockReader: MockReader{tag: language.English},
+			messages: []MockCatalogMessage{
+				{Key: localize.Key{Hash: "a"}, Translation: localize.Translation{
+					Text: "Hello",
+				}},
+				{Key: localize.Key{Hash: "b"}, Translation: localize.Translation{
+					Plural: true,
+					Forms:  localize.Forms{One: "%d file", Other: "%d files"},
+				}},
+			},
+		},
+		tr: en.New(),
+	}
+	b, err := localize.New(language.English, source)
+	require.NoError(t, err)
+	require.Nil(t, b.Preflight())
+
+	fileKey := localize.Key{Hash: "b", Source: "%d files"}
+	incomplete := preflightReader{
+		MockCatalogReader: MockCatalogReader{
+			MockReader: MockReader{tag: language.Russian},
+			messages: []MockCatalogMessage{
+				{Key: fileKey, Translation: localize.Translation{
+					Plural: true,
+					Forms:  localize.Forms{One: "%d файл", Other: "%d файла"},
+				}},
+				{Key: localize.Key{Hash: "a"}, Translation: localize.Translation{
+					Plural: true, Text: "x",
+				}},
+			},
+		},
+		tr: ru.New(),
+	}
+	mismatch := preflightReader{
+		MockCatalogReader: MockCatalogReader{MockReader: MockReader{tag: language.German}},
+		tr:                en.New(),
+	}
+	panicking := MockReader{tag: language.French} // Translator panics.
+	b, err = localize.New(language.English, source, incomplete, mismatch, panicking)
+	require.NoError(t, err)
+
+	problems := b.Preflight()
+	require.Len(t, problems, 5)
+	for i, expect := range []struct {
+		locale language.Tag
+		key    localize.Key
+		err    error
+	}{
+		{language.Russian, fileKey, localize.ErrIncompletePlural},
+		{language.Russian, localize.Key{Hash: "a"}, localize.ErrCatalogOrder},
+		{language.Russian, localize.Key{Hash: "a"}, localize.ErrPluralText},
+		{language.German, localize.Key{}, localize.ErrTranslatorMismatch},
+		{language.French, localize.Key{}, localize.ErrNoTranslator},
+	} {
+		require.Equal(t, expect.locale, problems[i].Locale, i)
+		require.Equal(t, expect.key, problems[i].Key, i)
+		require.ErrorIs(t, problems[i], expect.err, i)
+	}
+	require.Equal(t,
+		`ru: message b ("%d files"): incomplete plural translation: `+
+			`Forms{One: "%d файл", Other: "%d файла"}`,
+		problems[0].Error())
+	require.Equal(t, "de: translator of another language: en", problems[3].Error())
+}
@@ -0,0 +1,37 @@
+package localize
+
+import (
+	"fmt"
+	"io"
+)
+
+// Printer is a near drop-in replacement of the fmt printing functions
+// for user-facing output, which translates format strings using a Reader
+// before formatting:
+//
+//	p := localize.NewPrinter(r)
+//	p.Fprintf(w, "Welcome back, %s!", name) // Instead of fmt.Fprintf.
+//
+// Format strings passed to Printer methods are extracted by localize generate
+// like texts passed to Reader.Text and must therefore be constants.
+// Translations must keep the verbs of the format string.
+type Printer struct{ Reader Reader }
+
+// NewPrinter creates a new printer translating format strings using r.
+func NewPrinter(r Reader) Printer { return Printer{Reader: r} }
+
+// Sprintf is like fmt.Sprintf but formats the translation of format.
+func (p Printer) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(p.Reader.Text(format), args...)
+}
+
+// Fprintf is like fmt.Fprintf but formats the translation of format.
+func (p Printer) Fprintf(w io.Writer, format string, args ...any) (n int, err error) {
+	return fmt.Fprintf(w, p.Reader.Text(format), args...)
+}
+
+// Errorf is like fmt.Errorf but formats the translation of format.
+// Errors wrapped using %w are unwrapped as usual.
+func (p Printer) Errorf(format string, args ...any) error {
+	return fmt.Errorf(p.Reader.Text(format), args...)
+}
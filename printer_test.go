@@ -0,0 +1,34 @@
+package localize_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestPrinter(t *testing.T) {
+	p := localize.NewPrinter(MockReader{
+		tag: language.German,
+		static: map[string]string{
+			"Welcome back, %s!": "Willkommen zurück, %s!",
+			"%d new messages":   "%d neue Nachrichten",
+			"opening %q: %w":    "%q kann nicht geöffnet werden: %w",
+		},
+	})
+
+	require.Equal(t, "Willkommen zurück, Anna!", p.Sprintf("Welcome back, %s!", "Anna"))
+
+	var b strings.Builder
+	n, err := p.Fprintf(&b, "%d new messages", 3)
+	require.NoError(t, err)
+	require.Equal(t, "3 neue Nachrichten", b.String())
+	require.Equal(t, b.Len(), n)
+
+	err = p.Errorf("opening %q: %w", "a.txt", fs.ErrNotExist)
+	require.EqualError(t, err, `"a.txt" kann nicht geöffnet werden: file does not exist`)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,64 @@
+package localize
+
+import "reflect"
+
+// Quantifier is implemented by quantity types that aren't numeric types,
+// such as decimals, to be accepted by Reader.Plural and Reader.PluralBlock.
+type Quantifier interface {
+	// Quantity returns the quantity used to select the plural form.
+	Quantity() float64
+}
+
+// Int64er is implemented by quantity types such as *big.Int
+// to be accepted by Reader.Plural and Reader.PluralBlock.
+type Int64er interface {
+	Int64() int64
+}
+
+const (
+	minInt53 = -1 << 53
+	maxInt53 = 1 << 53
+)
+
+// Quantity converts quantity to float64 for plural form selection.
+// Supported are all numeric types, types with a numeric underlying type,
+// Quantifier, Int64er and SensitiveArg wrapping any of them.
+// If quantity also implements `IsInt64() bool` (like *big.Int)
+// then it must return true.
+// Returns ok=false if quantity isn't supported or if the conversion is lossy
+// in which case the plural form Other is expected to be used.
+func Quantity(quantity any) (q float64, ok bool) {
+	switch n := quantity.(type) {
+	case SensitiveArg:
+		return Quantity(n.value)
+	case Quantifier:
+		return n.Quantity(), true
+	case Int64er:
+		if i, ok := n.(interface{ IsInt64() bool }); ok && !i.IsInt64() {
+			return 0, false
+		}
+		return int64Quantity(n.Int64())
+	}
+
+	v := reflect.ValueOf(quantity)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int64Quantity(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		if n := v.Uint(); n < maxInt53 {
+			return float64(n), true
+		}
+		return 0, false // Lossy conversion.
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func int64Quantity(n int64) (float64, bool) {
+	if n >= maxInt53 || n <= minInt53 {
+		return 0, false // Lossy conversion.
+	}
+	return float64(n), true
+}
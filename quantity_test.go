@@ -0,0 +1,45 @@
+package localize_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+)
+
+type namedInt int
+
+type decimal struct{ units, nanos int64 }
+
+func (d decimal) Quantity() float64 { return float64(d.units) + float64(d.nanos)/1e9 }
+
+func TestQuantity(t *testing.T) {
+	f := func(t *testing.T, quantity any, expect float64) {
+		t.Helper()
+		q, ok := localize.Quantity(quantity)
+		require.True(t, ok)
+		require.Equal(t, expect, q)
+	}
+	f(t, 42, 42)
+	f(t, int8(-3), -3)
+	f(t, uint64(7), 7)
+	f(t, float32(1.5), 1.5)
+	f(t, 2.25, 2.25)
+	f(t, namedInt(5), 5)
+	f(t, big.NewInt(11), 11)
+	f(t, decimal{units: 1, nanos: 500_000_000}, 1.5)
+	f(t, localize.Sensitive(int8(4)), 4)
+
+	fErr := func(t *testing.T, quantity any) {
+		t.Helper()
+		_, ok := localize.Quantity(quantity)
+		require.False(t, ok)
+	}
+	fErr(t, "5")
+	fErr(t, nil)
+	fErr(t, uint64(1<<53))
+	fErr(t, int64(-1<<53))
+	fErr(t, new(big.Int).Lsh(big.NewInt(1), 64)) // Not int64.
+	fErr(t, big.NewInt(1<<60))                   // Lossy.
+}
@@ -0,0 +1,115 @@
+package localize
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales"
+)
+
+// QuantityFormatter formats the quantity of plural messages in the locale
+// of t before it's substituted into the selected template, such as rendering
+// 1200 as "1.2K". The formatted quantity replaces the format verb of
+// the quantity including its width and precision.
+type QuantityFormatter func(t locales.Translator, quantity float64) string
+
+// FormatQuantity is the default QuantityFormatter formatting quantity
+// with the decimal and grouping separators of the locale of t
+// and all of its fraction digits, such as "1,234.5" for "en"
+// and "1.234,5" for "de".
+func FormatQuantity(t locales.Translator, quantity float64) string {
+	return t.FmtNumber(quantity, fractionDigits(quantity))
+}
+
+// fractionDigits returns the number of fraction digits of the shortest
+// decimal representation of f.
+func fractionDigits(f float64) uint64 {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return uint64(len(s) - i - 1)
+	}
+	return 0
+}
+
+// WithQuantityFormatter returns a reader formatting the quantities of
+// Plural, PluralBlock, Cardinal, PluralRange and PluralOrdinal of r
+// using format before they're substituted into templates.
+// The plural form is still selected by the unformatted quantity.
+// Quantities not supported by Quantity are substituted as is.
+// Returns r if format is nil.
+func WithQuantityFormatter(r Reader, format QuantityFormatter) Reader {
+	if format == nil {
+		return r
+	}
+	return &quantityFormatterReader{Reader: r, format: format}
+}
+
+type quantityFormatterReader struct {
+	Reader
+	format QuantityFormatter
+}
+
+// Unwrap returns the wrapped reader.
+func (r *quantityFormatterReader) Unwrap() Reader { return r.Reader }
+
+// quantity returns quantity formatted by r.format preserving SensitiveArg.
+func (r *quantityFormatterReader) quantity(quantity any) any {
+	q, ok := Quantity(quantity)
+	if !ok {
+		return quantity
+	}
+	f := formattedQuantity{q: q, s: r.format(r.Reader.Translator(), q)}
+	if _, ok := quantity.(SensitiveArg); ok {
+		return Sensitive(f)
+	}
+	return f
+}
+
+func (r *quantityFormatterReader) Plural(templates Forms, quantity any) string {
+	return r.Reader.Plural(templates, r.quantity(quantity))
+}
+
+func (r *quantityFormatterReader) PluralBlock(templates Forms, quantity any) string {
+	return r.Reader.PluralBlock(templates, r.quantity(quantity))
+}
+
+func (r *quantityFormatterReader) Cardinal(otherTemplate string, quantity any) string {
+	return r.Reader.Cardinal(otherTemplate, r.quantity(quantity))
+}
+
+func (r *quantityFormatterReader) PluralRange(templates Forms, from, to any) string {
+	return r.Reader.PluralRange(templates, r.quantity(from), r.quantity(to))
+}
+
+func (r *quantityFormatterReader) PluralOrdinal(
+	templates OrdinalForms, ordinal, quantity any,
+) string {
+	return r.Reader.PluralOrdinal(templates, ordinal, r.quantity(quantity))
+}
+
+func (r *quantityFormatterReader) WithRegister(register Register) Reader {
+	return &quantityFormatterReader{
+		Reader: r.Reader.WithRegister(register), format: r.format,
+	}
+}
+
+func (r *quantityFormatterReader) Section(name string) Reader {
+	return &quantityFormatterReader{
+		Reader: r.Reader.Section(name), format: r.format,
+	}
+}
+
+// formattedQuantity is a quantity formatted by a QuantityFormatter.
+// It implements Quantifier for plural form selection and fmt.Formatter
+// substituting the formatted quantity for any verb.
+type formattedQuantity struct {
+	q float64
+	s string
+}
+
+func (f formattedQuantity) Quantity() float64 { return f.q }
+
+// Format implements fmt.Formatter.
+func (f formattedQuantity) Format(s fmt.State, _ rune) { _, _ = io.WriteString(s, f.s) }
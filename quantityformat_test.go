@@ -0,0 +1,77 @@
+package localize_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// pluralRuleReader selects the plural form by the CLDR cardinal
+// plural rules of its translator.
+type pluralRuleReader struct{ translatorReader }
+
+func (r pluralRuleReader) Plural(templates localize.Forms, quantity any) string {
+	q, ok := localize.Quantity(quantity)
+	if ok && r.tr.CardinalPluralRule(q, 0) == locales.PluralRuleOne {
+		return fmt.Sprintf(templates.One, quantity)
+	}
+	return fmt.Sprintf(templates.Other, quantity)
+}
+
+func (r pluralRuleReader) Cardinal(otherTemplate string, quantity any) string {
+	return r.Plural(localize.CardinalForms(otherTemplate), quantity)
+}
+
+func (r pluralRuleReader) PluralRange(templates localize.Forms, from, to any) string {
+	return fmt.Sprintf(templates.Other, from, to)
+}
+
+func TestFormatQuantity(t *testing.T) {
+	require.Equal(t, "1,234", localize.FormatQuantity(en.New(), 1234))
+	require.Equal(t, "1,234.5", localize.FormatQuantity(en.New(), 1234.5))
+	require.Equal(t, "1.234,25", localize.FormatQuantity(de.New(), 1234.25))
+}
+
+func TestWithQuantityFormatter(t *testing.T) {
+	r := pluralRuleReader{translatorReader{
+		MockReader: MockReader{tag: language.English}, tr: en.New(),
+	}}
+	require.Equal(t, localize.Reader(r), localize.WithQuantityFormatter(r, nil))
+
+	compact := func(t locales.Translator, q float64) string {
+		if q >= 1000 {
+			return t.FmtNumber(q/1000, 1) + "K"
+		}
+		return localize.FormatQuantity(t, q)
+	}
+	f := localize.WithQuantityFormatter(r, compact)
+	forms := localize.Forms{One: "%d file", Other: "%5d files"}
+	require.Equal(t, "1 file", f.Plural(forms, 1))
+	require.Equal(t, "1.2K files", f.Plural(forms, 1200))
+	require.Equal(t, "1.2K files", f.Plural(forms, localize.Sensitive(1200)))
+	require.Equal(t, "2.5 files", f.Plural(forms, 2.5))
+	require.Equal(t, "1.2K files", f.Cardinal("%d files", 1234))
+	require.Equal(t, "2-1.2K files",
+		f.PluralRange(localize.Forms{Other: "%d-%d files"}, 2, 1200))
+	require.Equal(t, "%!d(string=    x) files", f.Plural(forms, "x"))
+	require.Equal(t, r, f.(interface{ Unwrap() localize.Reader }).Unwrap())
+}
+
+func TestOptionsQuantityFormatter(t *testing.T) {
+	r := pluralRuleReader{translatorReader{
+		MockReader: MockReader{tag: language.German}, tr: de.New(),
+	}}
+	b, err := localize.NewWithOptions(language.German, localize.Options{
+		QuantityFormatter: localize.FormatQuantity,
+	}, r)
+	require.NoError(t, err)
+	require.Equal(t, "12.345 Dateien", b.Default().Plural(localize.Forms{
+		One: "%d Datei", Other: "%d Dateien",
+	}, 12345))
+}
@@ -0,0 +1,69 @@
+package localize
+
+import "fmt"
+
+// Register is the register of address of localized texts, such as the
+// formal German "Sie" and the informal "du".
+type Register uint8
+
+const (
+	// RegisterDefault is the register of the regular translations.
+	RegisterDefault Register = iota
+
+	// RegisterFormal selects formal variants of translations.
+	RegisterFormal
+
+	// RegisterInformal selects informal variants of translations.
+	RegisterInformal
+)
+
+// String returns the name of r used in the msgctxt of variant entries,
+// like "formal".
+func (r Register) String() string {
+	switch r {
+	case RegisterDefault:
+		return "default"
+	case RegisterFormal:
+		return "formal"
+	case RegisterInformal:
+		return "informal"
+	}
+	return fmt.Sprintf("Register(%d)", uint8(r))
+}
+
+// ParseRegister returns the register named s (see Register.String).
+// ok is false if s names no register.
+func ParseRegister(s string) (r Register, ok bool) {
+	switch s {
+	case "default":
+		return RegisterDefault, true
+	case "formal":
+		return RegisterFormal, true
+	case "informal":
+		return RegisterInformal, true
+	}
+	return 0, false
+}
+
+// RegisterContextPrefix is the msgctxt prefix of register variant entries,
+// which are auxiliary messages of translation catalogs providing the
+// translation of a message in a register other than RegisterDefault.
+// The msgctxt of a variant is the prefix followed by the register,
+// its msgid (and msgid_plural) are those of the message:
+//
+//	msgctxt "register:informal"
+//	msgid "Please sign in."
+//	msgstr "Bitte melde dich an."
+//
+// Messages without a variant of the selected register fall back to their
+// regular translation. Variant entries are written by translators
+// and kept by localize generate.
+const RegisterContextPrefix = "register:"
+
+// RegisterID returns the identifier of the variant of the message with
+// source text source in register for catalogs without message contexts,
+// which is the msgctxt and msgid separated by "\x04" like in GNU gettext
+// MO files. The source of plural messages is the template of form Other.
+func RegisterID(register Register, source string) string {
+	return RegisterContextPrefix + register.String() + "\x04" + source
+}
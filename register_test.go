@@ -0,0 +1,114 @@
+package localize_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// MockRegisterReader provides the static variants of its register.
+type MockRegisterReader struct {
+	MockCatalogReader
+	register localize.Register
+	variants map[localize.Register]map[string]string
+}
+
+func (r MockRegisterReader) Text(text string) string {
+	if s, ok := r.variants[r.register][text]; ok {
+		return s
+	}
+	return r.MockCatalogReader.Text(text)
+}
+
+func (r MockRegisterReader) WithRegister(register localize.Register) localize.Reader {
+	r.register = register
+	return r
+}
+
+func newMockRegisterReader() MockRegisterReader {
+	return MockRegisterReader{
+		MockCatalogReader: MockCatalogReader{
+			MockReader: MockReader{tag: language.German, static: map[string]string{
+				"Please sign in.": "Bitte melden Sie sich an.",
+				"Save":            "Speichern",
+			}},
+			messages: []MockCatalogMessage{
+				{
+					Key:         localize.Key{Hash: "a", Source: "Please sign in."},
+					Translation: localize.Translation{Text: "Bitte melden Sie sich an."},
+				},
+				{
+					Key:         localize.Key{Hash: "b", Source: "Save"},
+					Translation: localize.Translation{Text: "Speichern"},
+				},
+			},
+		},
+		variants: map[localize.Register]map[string]string{
+			localize.RegisterInformal: {"Please sign in.": "Bitte melde dich an."},
+		},
+	}
+}
+
+func TestRegisterString(t *testing.T) {
+	for _, r := range []localize.Register{
+		localize.RegisterDefault, localize.RegisterFormal, localize.RegisterInformal,
+	} {
+		p, ok := localize.ParseRegister(r.String())
+		require.True(t, ok)
+		require.Equal(t, r, p)
+	}
+	_, ok := localize.ParseRegister("casual")
+	require.False(t, ok)
+	require.Equal(t, "Register(9)", localize.Register(9).String())
+	require.Equal(t, "register:informal\x04Save",
+		localize.RegisterID(localize.RegisterInformal, "Save"))
+}
+
+func TestWithRegisterWrappers(t *testing.T) {
+	r := newMockRegisterReader()
+	const informal = "Bitte melde dich an."
+
+	upper := localize.TransformerFunc(func(_ language.Tag, s string) string {
+		return "<" + s + ">"
+	})
+	c := localize.Chain(r, upper).WithRegister(localize.RegisterInformal)
+	require.Equal(t, "<"+informal+">", c.Text("Please sign in."))
+	require.Equal(t, "<Speichern>", c.Text("Save"))
+
+	d := localize.NewDebugReader(r)
+	d.SetEnabled(false)
+	dr := d.WithRegister(localize.RegisterInformal)
+	require.Equal(t, informal, dr.Text("Please sign in."))
+	d.SetEnabled(true)
+	require.Equal(t, "[a] "+informal, dr.Text("Please sign in."),
+		"the returned reader must follow the state of the original")
+
+	var missing []error
+	s := localize.NewStrictReader(r, func(err error) { missing = append(missing, err) })
+	sr := s.WithRegister(localize.RegisterInformal)
+	require.Equal(t, informal, sr.Text("Please sign in."))
+	require.Equal(t, "Speichern", sr.Text("Save"))
+	require.Empty(t, missing)
+	sr.Text("Unknown")
+	require.Len(t, missing, 1)
+
+	m := localize.MergeReader(MockReader{tag: language.German}, r).
+		WithRegister(localize.RegisterInformal)
+	require.Equal(t, informal, m.Text("Please sign in."))
+
+	// Formal falls back to the regular translations.
+	require.Equal(t, "Bitte melden Sie sich an.",
+		r.WithRegister(localize.RegisterFormal).Text("Please sign in."))
+}
+
+func TestOptionsRegister(t *testing.T) {
+	b, err := localize.NewWithOptions(language.German, localize.Options{
+		Register: localize.RegisterInformal,
+	}, newMockRegisterReader())
+	require.NoError(t, err)
+	require.Equal(t, "Bitte melde dich an.", b.Default().Text("Please sign in."))
+	require.Equal(t, "Bitte melden Sie sich an.", b.Default().
+		WithRegister(localize.RegisterDefault).Text("Please sign in."))
+}
@@ -0,0 +1,81 @@
+package localize
+
+import "time"
+
+// Schedule is the period in which a time-limited message, such as
+// promotional copy, may be shown. Schedules are set using the
+// `// not-before: 2025-11-28` and `// not-after: 2025-12-01T23:59:59Z`
+// source code directives of a message.
+type Schedule struct {
+	// NotBefore is the start of the period.
+	// The period has no start if NotBefore is zero.
+	NotBefore time.Time
+
+	// NotAfter is the end of the period.
+	// The period has no end if NotAfter is zero.
+	NotAfter time.Time
+}
+
+// IsZero returns true if s neither has a start nor an end.
+func (s Schedule) IsZero() bool { return s.NotBefore.IsZero() && s.NotAfter.IsZero() }
+
+// Active returns true if t is within s. Both start and end are inclusive.
+func (s Schedule) Active(t time.Time) bool {
+	return (s.NotBefore.IsZero() || !t.Before(s.NotBefore)) &&
+		(s.NotAfter.IsZero() || !t.After(s.NotAfter))
+}
+
+// Expired returns true if s ended before t.
+func (s Schedule) Expired(t time.Time) bool {
+	return !s.NotAfter.IsZero() && t.After(s.NotAfter)
+}
+
+// Embargoed returns true if s starts after t.
+func (s Schedule) Embargoed(t time.Time) bool {
+	return !s.NotBefore.IsZero() && t.Before(s.NotBefore)
+}
+
+// Scheduler is an optional interface implemented by readers
+// providing the schedules of time-limited messages.
+// All generated readers implement Scheduler.
+type Scheduler interface {
+	// Schedule returns the schedule of the message with source text text.
+	// ok is false if the message has no schedule.
+	Schedule(text string) (s Schedule, ok bool)
+}
+
+// Scheduled localizes text using Reader.Text if its schedule (see Scheduler)
+// is active at now, or alternative otherwise, such as:
+//
+//	// Banner of the Black Friday sale.
+//	// not-before: 2025-11-28
+//	// not-after: 2025-12-01T23:59:59Z
+//	s := localize.Scheduled(r, time.Now(),
+//		"Black Friday: everything reduced!",
+//		"Discover our new collection.",
+//	)
+//
+// text is localized if neither r nor any reader wrapped by it implements
+// Scheduler or text has no schedule.
+func Scheduled(r Reader, now time.Time, text, alternative string) string {
+	if s, ok := findScheduler(r); ok {
+		if sched, ok := s.Schedule(text); ok && !sched.Active(now) {
+			return r.Text(alternative)
+		}
+	}
+	return r.Text(text)
+}
+
+// findScheduler returns the first Scheduler in the chain of wrapped readers.
+func findScheduler(r Reader) (Scheduler, bool) {
+	for {
+		if s, ok := r.(Scheduler); ok {
+			return s, true
+		}
+		w, ok := r.(interface{ Unwrap() Reader })
+		if !ok {
+			return nil, false
+		}
+		r = w.Unwrap()
+	}
+}
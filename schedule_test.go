@@ -0,0 +1,65 @@
+package localize_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/locales/en"
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/xtextcatalog"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+func TestScheduleActive(t *testing.T) {
+	d := func(day int) time.Time { return time.Date(2025, 11, day, 0, 0, 0, 0, time.UTC) }
+	s := localize.Schedule{NotBefore: d(10), NotAfter: d(20)}
+	for _, tt := range []struct {
+		t                          time.Time
+		active, embargoed, expired bool
+	}{
+		{d(9), false, true, false},
+		{d(10), true, false, false},
+		{d(15), true, false, false},
+		{d(20), true, false, false},
+		{d(21), false, false, true},
+	} {
+		require.Equal(t, tt.active, s.Active(tt.t), tt.t)
+		require.Equal(t, tt.embargoed, s.Embargoed(tt.t), tt.t)
+		require.Equal(t, tt.expired, s.Expired(tt.t), tt.t)
+	}
+	require.True(t, localize.Schedule{}.IsZero())
+	require.True(t, localize.Schedule{}.Active(d(1)))
+}
+
+// schedulerReader is a reader with a schedule for the message "Sale!".
+type schedulerReader struct{ *xtextcatalog.Reader }
+
+func (schedulerReader) Schedule(text string) (localize.Schedule, bool) {
+	if text != "Sale!" {
+		return localize.Schedule{}, false
+	}
+	return localize.Schedule{
+		NotAfter: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+	}, true
+}
+
+func TestScheduled(t *testing.T) {
+	var r localize.Reader = schedulerReader{
+		xtextcatalog.NewReader(catalog.NewBuilder(), language.English, en.New()),
+	}
+	before := time.Date(2025, 11, 30, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, "Sale!", localize.Scheduled(r, before, "Sale!", "New in."))
+	require.Equal(t, "New in.", localize.Scheduled(r, after, "Sale!", "New in."))
+	require.Equal(t, "Hello", localize.Scheduled(r, after, "Hello", "Bye"))
+
+	// Schedulers are found in wrapped readers.
+	upper := localize.Transliterate(r, language.English,
+		localize.TransformerFunc(func(_ language.Tag, s string) string {
+			return strings.ToUpper(s)
+		}))
+	require.Equal(t, "NEW IN.", localize.Scheduled(upper, after, "Sale!", "New in."))
+}
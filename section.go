@@ -0,0 +1,33 @@
+package localize
+
+import "strings"
+
+// SectionContextPrefix is the prefix of the identifiers of the messages
+// extracted from calls on readers returned by Reader.Section, which are
+// translated separately from identical texts of other sections.
+// Such messages are flagged `#, scoped` in catalogs and carry their
+// section as `#. Section: <name>` extracted comment.
+const SectionContextPrefix = "section:"
+
+// SectionSeparator separates the names of nested sections,
+// like "Checkout/Payment".
+const SectionSeparator = "/"
+
+// SectionID returns the identifier of the message with source text source
+// in section, which is the section prefixed by SectionContextPrefix and
+// the source separated by "\x04" like in GNU gettext MO files.
+// The source of plural messages is the template of form Other.
+func SectionID(section, source string) string {
+	return SectionContextPrefix + section + "\x04" + source
+}
+
+// SectionPath returns the path of section name nested in section parent,
+// which is name if parent is empty. Leading and trailing spaces of name
+// are removed.
+func SectionPath(parent, name string) string {
+	name = strings.TrimSpace(name)
+	if parent == "" {
+		return name
+	}
+	return parent + SectionSeparator + name
+}
@@ -0,0 +1,63 @@
+package localize_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// MockSectionReader provides the static translations of its section.
+type MockSectionReader struct {
+	MockReader
+	section  string
+	sections map[string]map[string]string
+}
+
+func (r MockSectionReader) Text(text string) string {
+	if s, ok := r.sections[r.section][text]; ok {
+		return s
+	}
+	return r.MockReader.Text(text)
+}
+
+func (r MockSectionReader) Section(name string) localize.Reader {
+	r.section = localize.SectionPath(r.section, name)
+	return r
+}
+
+func TestSectionID(t *testing.T) {
+	require.Equal(t, "section:Checkout/Payment\x04Pay",
+		localize.SectionID("Checkout/Payment", "Pay"))
+	require.Equal(t, "Checkout", localize.SectionPath("", " Checkout "))
+	require.Equal(t, "Checkout/Payment", localize.SectionPath("Checkout", "Payment"))
+}
+
+func TestSectionWrappers(t *testing.T) {
+	r := MockSectionReader{
+		MockReader: MockReader{tag: language.German, static: map[string]string{
+			"Total": "Summe",
+		}},
+		sections: map[string]map[string]string{
+			"Checkout/Payment": {"Total": "Gesamtbetrag"},
+		},
+	}
+	upper := localize.TransformerFunc(func(_ language.Tag, s string) string {
+		return "<" + s + ">"
+	})
+	for name, w := range map[string]localize.Reader{
+		"Chain":       localize.Chain(r, upper),
+		"DebugReader": localize.NewDebugReader(r),
+		"StrictReader": localize.NewStrictReader(r, func(err error) {
+			t.Errorf("unexpected missing translation: %v", err)
+		}),
+		"MergeReader": localize.MergeReader(r, MockReader{tag: language.German}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := w.Section("Checkout").Section("Payment")
+			require.Contains(t, s.Text("Total"), "Gesamtbetrag")
+			require.Contains(t, w.Section("Checkout").Text("Total"), "Summe")
+		})
+	}
+}
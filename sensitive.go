@@ -0,0 +1,40 @@
+package localize
+
+import "fmt"
+
+// RedactedPlaceholder replaces sensitive arguments (see Sensitive)
+// in errors and logs.
+const RedactedPlaceholder = "[redacted]"
+
+// SensitiveArg is an argument of a formatted message marked as sensitive
+// using Sensitive.
+type SensitiveArg struct{ value any }
+
+// Sensitive marks value as a sensitive argument of a formatted message,
+// such as the quantity of a plural message or an argument of a localizemail
+// message. Localized strings contain value as is, but hooks and errors
+// reporting the message, such as *MissingTranslationError,
+// contain RedactedPlaceholder instead:
+//
+//	r.Plural(localize.Forms{
+//		One:   "You owe %d euro.",
+//		Other: "You owe %d euros.",
+//	}, localize.Sensitive(debt))
+func Sensitive(value any) SensitiveArg { return SensitiveArg{value: value} }
+
+// Value returns the raw value of s.
+func (s SensitiveArg) Value() any { return s.value }
+
+// Format implements fmt.Formatter formatting the raw value of s.
+func (s SensitiveArg) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, fmt.FormatString(f, verb), s.value)
+}
+
+// Redact returns RedactedPlaceholder if arg is a SensitiveArg,
+// otherwise returns arg.
+func Redact(arg any) any {
+	if _, ok := arg.(SensitiveArg); ok {
+		return RedactedPlaceholder
+	}
+	return arg
+}
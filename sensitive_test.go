@@ -0,0 +1,46 @@
+package localize_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSensitive(t *testing.T) {
+	s := localize.Sensitive(42)
+	require.Equal(t, 42, s.Value())
+	require.Equal(t, "42 0042 42.000 +42", fmt.Sprintf("%v %04d %.3f %+d",
+		s, s, localize.Sensitive(42.0), s))
+	require.Equal(t, `"secret"`, fmt.Sprintf("%q", localize.Sensitive("secret")))
+
+	require.Equal(t, localize.RedactedPlaceholder, localize.Redact(s))
+	require.Equal(t, 42, localize.Redact(42))
+	require.Nil(t, localize.Redact(nil))
+}
+
+func TestSensitiveStrictReader(t *testing.T) {
+	var reported []error
+	s := localize.NewStrictReader(newStrictTestReader(),
+		func(err error) { reported = append(reported, err) })
+
+	s.Plural(localize.Forms{One: "%d pear", Other: "%d pears"}, localize.Sensitive(7))
+	s.Cardinal("%d pears", 7)
+	require.Equal(t, []error{
+		&localize.MissingTranslationError{
+			Locale: s.Locale(), Source: "%d pears",
+			Quantity: localize.RedactedPlaceholder,
+		},
+		&localize.MissingTranslationError{
+			Locale: s.Locale(), Source: "%d pears", Quantity: 7,
+		},
+	}, reported)
+
+	defer func() {
+		var err *localize.MissingTranslationError
+		require.ErrorAs(t, recover().(error), &err)
+		require.Equal(t, localize.RedactedPlaceholder, err.Quantity)
+	}()
+	localize.MustPlural(s, localize.Forms{Other: "%d pears"}, localize.Sensitive(7))
+}
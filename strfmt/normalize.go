@@ -0,0 +1,108 @@
+package strfmt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization is a set of normalizations applied to source texts
+// before they're hashed and written to catalogs, such that visually
+// identical texts are the same message.
+type Normalization uint8
+
+const (
+	// NormalizeNFC converts texts to Unicode normalization form C,
+	// such that precomposed characters and their decomposed equivalents
+	// like "é" and "é" are identical.
+	NormalizeNFC Normalization = 1 << iota
+
+	// NormalizeSpaces collapses runs of spaces following a non-space
+	// character into a single space. Indentation at the start of lines
+	// is preserved.
+	NormalizeSpaces
+
+	// NormalizeNone applies no normalization.
+	NormalizeNone Normalization = 0
+)
+
+// normalizationNames are the names of the normalizations in the order
+// of their bits.
+var normalizationNames = [...]string{"nfc", "spaces"}
+
+// String returns the comma-separated names of the normalizations of n
+// as accepted by ParseNormalization, or "none".
+func (n Normalization) String() string {
+	if n == NormalizeNone {
+		return "none"
+	}
+	var names []string
+	for i, name := range normalizationNames {
+		if n&(1<<i) != 0 {
+			names = append(names, name)
+			n &^= 1 << i
+		}
+	}
+	if n != 0 {
+		return "Normalization(" + strconv.Itoa(int(n)) + ")"
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseNormalization parses a comma-separated list of normalizations
+// ("nfc" and "spaces"), or "none".
+func ParseNormalization(s string) (Normalization, error) {
+	if s == "none" {
+		return NormalizeNone, nil
+	}
+	var n Normalization
+	for name := range strings.SplitSeq(s, ",") {
+		i := -1
+		for j, nm := range normalizationNames {
+			if strings.TrimSpace(name) == nm {
+				i = j
+			}
+		}
+		if i == -1 {
+			return 0, fmt.Errorf(
+				"unknown normalization %q, use nfc, spaces or none", name,
+			)
+		}
+		n |= 1 << i
+	}
+	return n, nil
+}
+
+// Normalize returns s normalized according to n.
+// Returns s if it's already normalized.
+func Normalize(s string, n Normalization) string {
+	if n&NormalizeNFC != 0 {
+		s = norm.NFC.String(s)
+	}
+	if n&NormalizeSpaces != 0 && strings.Contains(s, "  ") {
+		s = collapseSpaces(s)
+	}
+	return s
+}
+
+// collapseSpaces collapses runs of spaces following
+// a non-space character of a line into a single space.
+func collapseSpaces(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	indentation := true
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\n':
+			indentation = true
+		case c != ' ':
+			indentation = false
+		case !indentation && i > 0 && s[i-1] == ' ':
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package strfmt_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/strfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+	f := func(t *testing.T, expect string, n strfmt.Normalization, input string) {
+		t.Helper()
+		require.Equal(t, expect, strfmt.Normalize(input, n))
+	}
+
+	const decomposed, precomposed = "Cafe\u0301", "Caf\u00e9"
+	f(t, decomposed, strfmt.NormalizeNone, decomposed)
+	f(t, precomposed, strfmt.NormalizeNFC, decomposed)
+	f(t, precomposed, strfmt.NormalizeNFC, precomposed)
+	f(t, "Save  changes", strfmt.NormalizeNFC, "Save  changes")
+
+	f(t, "", strfmt.NormalizeSpaces, "")
+	f(t, "Save changes", strfmt.NormalizeSpaces, "Save    changes")
+	f(t, "Save changes ", strfmt.NormalizeSpaces, "Save changes  ")
+	f(t, "Items:\n  - one\n  - two", strfmt.NormalizeSpaces,
+		"Items:\n  - one\n  - two")
+	f(t, "  Indented text", strfmt.NormalizeSpaces, "  Indented   text")
+	f(t, "Tab\t\tkept", strfmt.NormalizeSpaces, "Tab\t\tkept")
+	f(t, precomposed+" au lait",
+		strfmt.NormalizeNFC|strfmt.NormalizeSpaces, decomposed+"  au lait")
+}
+
+func TestParseNormalization(t *testing.T) {
+	t.Parallel()
+	for _, n := range []strfmt.Normalization{
+		strfmt.NormalizeNone,
+		strfmt.NormalizeNFC,
+		strfmt.NormalizeSpaces,
+		strfmt.NormalizeNFC | strfmt.NormalizeSpaces,
+	} {
+		p, err := strfmt.ParseNormalization(n.String())
+		require.NoError(t, err)
+		require.Equal(t, n, p)
+	}
+	n, err := strfmt.ParseNormalization("spaces, nfc")
+	require.NoError(t, err)
+	require.Equal(t, strfmt.NormalizeNFC|strfmt.NormalizeSpaces, n)
+	require.Equal(t, "nfc,spaces", n.String())
+
+	_, err = strfmt.ParseNormalization("nfd")
+	require.Error(t, err)
+	_, err = strfmt.ParseNormalization("")
+	require.Error(t, err)
+}
@@ -1,48 +1,261 @@
 // Package strfmt provides string formatting functions.
 package strfmt
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // Dedent removes leading/trailing blank lines and
 // the common leading indentation from all non-empty lines.
+// Returns a substring of s if no indentation needs to be removed.
 func Dedent(s string) string {
-	lines := strings.Split(s, "\n")
-	for len(lines) > 0 && isLineBlank(lines[0]) {
-		lines = lines[1:]
+	// Find the first and the last non-blank line
+	// and the common indentation of all non-blank lines in between.
+	start, end, minInd := -1, 0, -1
+	for i := 0; i < len(s); {
+		lineEnd := lineEnd(s, i)
+		if line := s[i:lineEnd]; !isLineBlank(line) {
+			if start == -1 {
+				start = i
+			}
+			end = lineEnd
+			if indent := leadingWhitespace(line); minInd == -1 || indent < minInd {
+				minInd = indent
+			}
+		}
+		i = lineEnd + 1
 	}
-	for len(lines) > 0 && isLineBlank(lines[len(lines)-1]) {
-		lines = lines[:len(lines)-1]
+	if start == -1 {
+		return ""
 	}
-	minInd := -1
-	for _, line := range lines {
-		if isLineBlank(line) {
-			continue
+	s = s[start:end]
+	if minInd == 0 {
+		return strings.TrimSpace(s)
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		lineEnd := lineEnd(s, i)
+		if i > 0 {
+			b.WriteByte('\n')
 		}
-		if indent := leadingWhitespace(line); minInd == -1 || indent < minInd {
-			minInd = indent
+		if line := s[i:lineEnd]; isLineBlank(line) {
+			b.WriteString(line)
+		} else {
+			b.WriteString(line[minInd:])
 		}
+		i = lineEnd + 1
 	}
-	// Dedent.
-	for i, line := range lines {
-		if isLineBlank(line) {
+	return strings.TrimSpace(b.String())
+}
+
+// BlankLinePolicy defines how DedentOptions.Dedent handles blank lines
+// between the first and the last non-blank line.
+type BlankLinePolicy uint8
+
+const (
+	// BlankLinesKeep keeps blank lines including their whitespace.
+	BlankLinesKeep BlankLinePolicy = iota
+
+	// BlankLinesEmpty removes the whitespace of blank lines.
+	BlankLinesEmpty
+
+	// BlankLinesCollapse removes the whitespace of blank lines and
+	// collapses consecutive blank lines into a single one.
+	BlankLinesCollapse
+)
+
+// DedentOptions are the options of DedentOptions.Dedent.
+// The zero value behaves like Dedent.
+type DedentOptions struct {
+	// TabWidth is the number of columns between tab stops used to compare
+	// the indentation of lines mixing tabs and spaces.
+	// If 0 a tab is a single column like a space.
+	TabWidth int
+
+	// BlankLines defines how blank lines are handled.
+	BlankLines BlankLinePolicy
+}
+
+// Dedent is like Dedent but measures indentation and handles blank lines
+// according to o. A tab crossing the common indentation is replaced by
+// the spaces remaining after removing the common indentation.
+func (o DedentOptions) Dedent(s string) string {
+	if o == (DedentOptions{}) {
+		return Dedent(s)
+	}
+	start, end, minInd := -1, 0, -1
+	for i := 0; i < len(s); {
+		lineEnd := lineEnd(s, i)
+		if line := s[i:lineEnd]; !isLineBlank(line) {
+			if start == -1 {
+				start = i
+			}
+			end = lineEnd
+			if indent := o.indentation(line); minInd == -1 || indent < minInd {
+				minInd = indent
+			}
+		}
+		i = lineEnd + 1
+	}
+	if start == -1 {
+		return ""
+	}
+	s = s[start:end]
+
+	var b strings.Builder
+	b.Grow(len(s))
+	prevBlank := false
+	for i := 0; i < len(s); {
+		lineEnd := lineEnd(s, i)
+		line := s[i:lineEnd]
+		i = lineEnd + 1
+		blank := isLineBlank(line)
+		if blank && prevBlank && o.BlankLines == BlankLinesCollapse {
 			continue
 		}
-		if len(line) >= minInd {
-			lines[i] = line[minInd:]
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		prevBlank = blank
+		switch {
+		case blank && o.BlankLines != BlankLinesKeep:
+		case blank:
+			b.WriteString(line)
+		default:
+			o.writeDedented(&b, line, minInd)
 		}
 	}
-	return strings.TrimSpace(strings.Join(lines, "\n"))
+	return strings.TrimSpace(b.String())
+}
+
+// indentation returns the width of the indentation of line in columns.
+func (o DedentOptions) indentation(line string) (columns int) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			columns++
+		case '\t':
+			if o.TabWidth > 0 {
+				columns += o.TabWidth - columns%o.TabWidth
+			} else {
+				columns++
+			}
+		default:
+			return columns
+		}
+	}
+	return columns
+}
+
+// writeDedented writes line to b without the first columns of indentation.
+// columns must not exceed the indentation of line.
+func (o DedentOptions) writeDedented(b *strings.Builder, line string, columns int) {
+	c := 0
+	for n := 0; n < len(line) && c < columns; n++ {
+		if line[n] == '\t' && o.TabWidth > 0 {
+			c += o.TabWidth - c%o.TabWidth
+		} else {
+			c++
+		}
+		if c >= columns {
+			// Preserve the columns of a tab crossing the indentation.
+			for range c - columns {
+				b.WriteByte(' ')
+			}
+			b.WriteString(line[n+1:])
+			return
+		}
+	}
+	b.WriteString(line)
+}
+
+// lineEnd returns the index of the line feed terminating the line
+// starting at index i or len(s) if the line is the last one.
+func lineEnd(s string, i int) int {
+	if n := strings.IndexByte(s[i:], '\n'); n != -1 {
+		return i + n
+	}
+	return len(s)
 }
 
 func isLineBlank(s string) bool { return strings.TrimSpace(s) == "" }
 
 func leadingWhitespace(s string) (count int) {
-	for _, r := range s {
-		if r == ' ' || r == '\t' {
-			count++
+	for count < len(s) && (s[count] == ' ' || s[count] == '\t') {
+		count++
+	}
+	return count
+}
+
+// DedentMode defines how Block and PluralBlock texts are formatted.
+type DedentMode uint8
+
+const (
+	// DedentPreserve preserves the line breaks of the dedented text exactly.
+	DedentPreserve DedentMode = iota
+
+	// DedentReflow joins the lines of every paragraph of the dedented text
+	// into a single line. Paragraphs are separated by a single blank line.
+	DedentReflow
+)
+
+// String returns the name of the mode as accepted by ParseDedentMode.
+func (m DedentMode) String() string {
+	switch m {
+	case DedentPreserve:
+		return "preserve"
+	case DedentReflow:
+		return "reflow"
+	}
+	return "DedentMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// ParseDedentMode parses the name of a mode ("preserve" or "reflow").
+func ParseDedentMode(s string) (DedentMode, error) {
+	switch s {
+	case "preserve":
+		return DedentPreserve, nil
+	case "reflow":
+		return DedentReflow, nil
+	}
+	return 0, fmt.Errorf("unknown dedent mode %q, use preserve or reflow", s)
+}
+
+// DedentWith is like Dedent but formats the result according to mode.
+func DedentWith(s string, mode DedentMode) string {
+	if mode == DedentReflow {
+		return Reflow(Dedent(s))
+	}
+	return Dedent(s)
+}
+
+// Reflow joins the lines of every paragraph of s into a single line
+// separated by spaces. Paragraphs are separated by blank lines, which are
+// collapsed into a single blank line. Leading and trailing whitespace
+// of all lines is removed.
+func Reflow(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	blank := false
+	for line := range strings.SplitSeq(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			blank = true
 			continue
 		}
-		break
+		if b.Len() > 0 {
+			if blank {
+				b.WriteString("\n\n")
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		blank = false
+		b.WriteString(line)
 	}
-	return count
+	return b.String()
 }
@@ -2,6 +2,7 @@ package strfmt_test
 
 import (
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/romshark/localize/strfmt"
@@ -62,6 +63,88 @@ func TestDedent(t *testing.T) {
 `)
 }
 
+func TestReflow(t *testing.T) {
+	t.Parallel()
+	f := func(t *testing.T, expect, input string) {
+		t.Helper()
+		require.Equal(t, expect, strfmt.Reflow(input))
+	}
+
+	f(t, "", ``)
+	f(t, "foo", `foo`)
+	f(t, "foo bar", "foo\nbar")
+	f(t, "foo bar", "  foo  \n\tbar ")
+	f(t, "foo bar\n\nbazz", "foo\nbar\n\nbazz")
+	f(t, "foo\n\nbar", "\n\nfoo\n \n\t\n\nbar\n\n")
+}
+
+func TestDedentWith(t *testing.T) {
+	t.Parallel()
+	const input = `
+		First paragraph
+		  spanning two lines.
+
+		Second paragraph.
+	`
+	require.Equal(t, strfmt.Dedent(input),
+		strfmt.DedentWith(input, strfmt.DedentPreserve))
+	require.Equal(t, "First paragraph spanning two lines.\n\nSecond paragraph.",
+		strfmt.DedentWith(input, strfmt.DedentReflow))
+}
+
+func TestParseDedentMode(t *testing.T) {
+	t.Parallel()
+	for _, m := range []strfmt.DedentMode{strfmt.DedentPreserve, strfmt.DedentReflow} {
+		p, err := strfmt.ParseDedentMode(m.String())
+		require.NoError(t, err)
+		require.Equal(t, m, p)
+	}
+	_, err := strfmt.ParseDedentMode("wrap")
+	require.Error(t, err)
+}
+
+// dedentReference is the original implementation of Dedent
+// based on strings.Split and strings.Join.
+func dedentReference(s string) string {
+	lines := strings.Split(s, "\n")
+	isBlank := func(s string) bool { return strings.TrimSpace(s) == "" }
+	for len(lines) > 0 && isBlank(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	minInd := -1
+	for _, line := range lines {
+		if isBlank(line) {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if minInd == -1 || indent < minInd {
+			minInd = indent
+		}
+	}
+	for i, line := range lines {
+		if !isBlank(line) {
+			lines[i] = line[minInd:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func FuzzDedent(f *testing.F) {
+	for _, s := range []string{
+		"", "foo", " foo ", "foo\n\tbar", "\n\t\tfoo\n\t",
+		"\n\t\tfoo\n\n\t\t bar\n\tbazz\n", "\r\n  foo\r\n  bar\r\n",
+		"\t\n \n\t foo\n \t\n",
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		require.Equal(t, dedentReference(s), strfmt.Dedent(s))
+	})
+}
+
 func BenchmarkDedent(b *testing.B) {
 	var s string
 	for b.Loop() {
@@ -85,3 +168,58 @@ func BenchmarkDedent(b *testing.B) {
 	}
 	runtime.KeepAlive(s)
 }
+
+func BenchmarkDedentUnindented(b *testing.B) {
+	var s string
+	for b.Loop() {
+		s = strfmt.Dedent("Lorem ipsum dolor sit amet,\nconsectetur adipiscing elit.\n")
+	}
+	runtime.KeepAlive(s)
+}
+
+func BenchmarkDedentWithReflow(b *testing.B) {
+	var s string
+	for b.Loop() {
+		s = strfmt.DedentWith(`
+			Lorem ipsum dolor sit amet, consectetur adipiscing elit.
+			Quisque ultrices pretium felis quis iaculis.
+
+			Vestibulum eu augue porttitor ex varius dapibus.
+		`, strfmt.DedentReflow)
+	}
+	runtime.KeepAlive(s)
+}
+
+func TestDedentOptions(t *testing.T) {
+	t.Parallel()
+	f := func(t *testing.T, o strfmt.DedentOptions, expect, input string) {
+		t.Helper()
+		require.Equal(t, expect, o.Dedent(input))
+	}
+
+	// The zero value behaves like Dedent.
+	f(t, strfmt.DedentOptions{}, "foo\n\t\t \nbar", "\n\t\tfoo\n\t\t \n\t\tbar\n")
+
+	tab4 := strfmt.DedentOptions{TabWidth: 4}
+	f(t, tab4, "foo\nbar", "\n\tfoo\n    bar\n")
+	f(t, tab4, "foo\n  bar", "\n  foo\n\tbar\n")
+	f(t, tab4, "foo\n\tbar", "\n    foo\n\t\tbar\n")
+	f(t, tab4, "foo\nbar", "foo\nbar")
+	f(t, tab4, "", "\n\t\n")
+
+	empty := strfmt.DedentOptions{BlankLines: strfmt.BlankLinesEmpty}
+	f(t, empty, "foo\n\n\nbar", "\n\tfoo\n\t \n\t\n\tbar\n")
+
+	collapse := strfmt.DedentOptions{BlankLines: strfmt.BlankLinesCollapse}
+	f(t, collapse, "foo\n\nbar\n\nbazz", "\n\tfoo\n\t \n\t\n\tbar\n\n\tbazz\n")
+}
+
+func FuzzDedentOptions(f *testing.F) {
+	f.Add("\n\t\tfoo\n\t\t \n\t\tbar\n")
+	f.Add("\n  foo\n\tbar\n")
+	f.Fuzz(func(t *testing.T, s string) {
+		// Without tabs the tab width doesn't matter.
+		s = strings.ReplaceAll(s, "\t", " ")
+		require.Equal(t, strfmt.Dedent(s), strfmt.DedentOptions{TabWidth: 4}.Dedent(s))
+	})
+}
@@ -0,0 +1,248 @@
+package localize
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// ErrMissingTranslation is wrapped by MissingTranslationError.
+var ErrMissingTranslation = errors.New("missing translation")
+
+// MissingTranslationError is reported by StrictReader and the Must helpers
+// when a message has no translation.
+type MissingTranslationError struct {
+	Locale language.Tag
+
+	// Source is the source text for static messages (Text and Block)
+	// or the source template of form Other for plural messages
+	// (Plural and PluralBlock).
+	Source string
+
+	// Quantity is the quantity of plural messages or nil for static messages.
+	// Sensitive quantities (see Sensitive) are replaced by RedactedPlaceholder.
+	Quantity any
+
+	// NotInCatalog is true if the message isn't in the catalog at all,
+	// which usually means that the bundle wasn't regenerated
+	// after the source code changed.
+	NotInCatalog bool
+}
+
+func (e *MissingTranslationError) Error() string {
+	if e.NotInCatalog {
+		return fmt.Sprintf("%s (%s): message not in catalog: %q",
+			ErrMissingTranslation, e.Locale, e.Source)
+	}
+	return fmt.Sprintf("%s (%s): %q", ErrMissingTranslation, e.Locale, e.Source)
+}
+
+func (e *MissingTranslationError) Unwrap() error { return ErrMissingTranslation }
+
+// StrictReader wraps a Reader and reports every message that either isn't
+// in the catalog or isn't translated, instead of silently falling back to
+// the source text. The wrapped reader still provides the localized string.
+//
+// Messages are only checked if the wrapped reader or any reader wrapped by it
+// implements Cataloger, otherwise nothing is reported.
+// StrictReader is meant for development and staging environments
+// to catch catalog drift early.
+type StrictReader struct {
+	Reader
+	onMissing func(err error)
+
+	// static and plural map the source of every message in the catalog
+	// to true if the message is translated.
+	static, plural map[string]bool
+
+	// checked is false if the wrapped reader doesn't implement Cataloger.
+	checked bool
+}
+
+var _ Reader = new(StrictReader)
+
+// NewStrictReader creates a new strict reader wrapping r.
+// onMissing is called with a *MissingTranslationError for every message
+// without a translation. If onMissing is nil StrictReader panics instead.
+func NewStrictReader(r Reader, onMissing func(err error)) *StrictReader {
+	s := &StrictReader{
+		Reader:    r,
+		onMissing: onMissing,
+		static:    map[string]bool{},
+		plural:    map[string]bool{},
+	}
+	if c, ok := findCataloger(r); ok {
+		s.checked = true
+		for k, t := range c.Messages() {
+			if t.Plural {
+				s.plural[k.Source] = isTranslated(t)
+				continue
+			}
+			s.static[k.Source] = isTranslated(t)
+		}
+	}
+	return s
+}
+
+// Unwrap returns the wrapped reader.
+func (s *StrictReader) Unwrap() Reader { return s.Reader }
+
+// Text calls Text on the wrapped reader and reports missing translations.
+func (s *StrictReader) Text(text string) (localized string) {
+	s.report(s.check(s.static, text, nil))
+	return s.Reader.Text(text)
+}
+
+// Block calls Block on the wrapped reader and reports missing translations.
+func (s *StrictReader) Block(text string) (localized string) {
+	s.report(s.check(s.static, blockKey(s.static, text), nil))
+	return s.Reader.Block(text)
+}
+
+// Plural calls Plural on the wrapped reader and reports missing translations.
+func (s *StrictReader) Plural(templates Forms, quantity any) (localized string) {
+	s.report(s.check(s.plural, templates.Other, quantity))
+	return s.Reader.Plural(templates, quantity)
+}
+
+// PluralBlock calls PluralBlock on the wrapped reader
+// and reports missing translations.
+func (s *StrictReader) PluralBlock(templates Forms, quantity any) (localized string) {
+	s.report(s.check(s.plural, blockKey(s.plural, templates.Other), quantity))
+	return s.Reader.PluralBlock(templates, quantity)
+}
+
+// Cardinal calls Cardinal on the wrapped reader and reports missing translations.
+func (s *StrictReader) Cardinal(otherTemplate string, quantity any) (localized string) {
+	s.report(s.check(s.plural, otherTemplate, quantity))
+	return s.Reader.Cardinal(otherTemplate, quantity)
+}
+
+// PluralRange calls PluralRange on the wrapped reader
+// and reports missing translations.
+func (s *StrictReader) PluralRange(templates Forms, from, to any) (localized string) {
+	s.report(s.check(s.plural, templates.Other, to))
+	return s.Reader.PluralRange(templates, from, to)
+}
+
+// PluralOrdinal calls PluralOrdinal on the wrapped reader
+// and reports missing translations.
+func (s *StrictReader) PluralOrdinal(
+	templates OrdinalForms, ordinal, quantity any,
+) (localized string) {
+	forms := templates.Forms(s.Translator(), ordinal)
+	s.report(s.check(s.plural, forms.Other, quantity))
+	return s.Reader.PluralOrdinal(templates, ordinal, quantity)
+}
+
+// WithRegister returns a strict reader wrapping the reader of register
+// of the wrapped reader. Missing variants of register aren't reported
+// since they fall back to the regular translations.
+func (s *StrictReader) WithRegister(register Register) Reader {
+	return &StrictReader{
+		Reader:    s.Reader.WithRegister(register),
+		onMissing: s.onMissing,
+		static:    s.static,
+		plural:    s.plural,
+		checked:   s.checked,
+	}
+}
+
+// Section returns a strict reader wrapping the reader of section name
+// of the wrapped reader. Messages of the section are reported as missing
+// only if no message with their source text is translated.
+func (s *StrictReader) Section(name string) Reader {
+	return &StrictReader{
+		Reader:    s.Reader.Section(name),
+		onMissing: s.onMissing,
+		static:    s.static,
+		plural:    s.plural,
+		checked:   s.checked,
+	}
+}
+
+func (s *StrictReader) check(
+	translated map[string]bool, source string, quantity any,
+) error {
+	if !s.checked {
+		return nil
+	}
+	ok, inCatalog := translated[source]
+	if ok {
+		return nil
+	}
+	return &MissingTranslationError{
+		Locale:       s.Locale(),
+		Source:       source,
+		Quantity:     Redact(quantity),
+		NotInCatalog: !inCatalog,
+	}
+}
+
+func (s *StrictReader) report(err error) {
+	if err == nil {
+		return
+	}
+	if s.onMissing == nil {
+		panic(err)
+	}
+	s.onMissing(err)
+}
+
+// strictReaderOf returns the first StrictReader in the chain of wrapped readers
+// or a new one wrapping r if there is none.
+func strictReaderOf(r Reader) *StrictReader {
+	for w := r; ; {
+		if s, ok := w.(*StrictReader); ok {
+			return s
+		}
+		u, ok := w.(interface{ Unwrap() Reader })
+		if !ok {
+			return NewStrictReader(r, nil)
+		}
+		w = u.Unwrap()
+	}
+}
+
+// MustText is like r.Text but panics with a *MissingTranslationError if
+// the message has no translation, even if r isn't strict.
+// If r doesn't wrap a StrictReader a new one is created on every call,
+// which is expensive for large catalogs.
+func MustText(r Reader, text string) string {
+	s := strictReaderOf(r)
+	if err := s.check(s.static, text, nil); err != nil {
+		panic(err)
+	}
+	return r.Text(text)
+}
+
+// MustBlock is like r.Block but panics if the message has no translation.
+// See MustText for more information.
+func MustBlock(r Reader, text string) string {
+	s := strictReaderOf(r)
+	if err := s.check(s.static, blockKey(s.static, text), nil); err != nil {
+		panic(err)
+	}
+	return r.Block(text)
+}
+
+// MustPlural is like r.Plural but panics if the message has no translation.
+// See MustText for more information.
+func MustPlural(r Reader, templates Forms, quantity any) string {
+	s := strictReaderOf(r)
+	if err := s.check(s.plural, templates.Other, quantity); err != nil {
+		panic(err)
+	}
+	return r.Plural(templates, quantity)
+}
+
+// MustPluralBlock is like r.PluralBlock but panics if the message
+// has no translation. See MustText for more information.
+func MustPluralBlock(r Reader, templates Forms, quantity any) string {
+	s := strictReaderOf(r)
+	if err := s.check(s.plural, blockKey(s.plural, templates.Other), quantity); err != nil {
+		panic(err)
+	}
+	return r.PluralBlock(templates, quantity)
+}
@@ -0,0 +1,122 @@
+package localize_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func newStrictTestReader() MockCatalogReader {
+	return MockCatalogReader{
+		MockReader: MockReader{
+			tag: language.German,
+			static: map[string]string{
+				"Hello":          "Hallo",
+				"Untranslated":   "Untranslated",
+				"Not in catalog": "Not in catalog",
+			},
+		},
+		messages: []MockCatalogMessage{
+			{
+				Key:         localize.Key{Hash: "818274c2b2b715d5", Source: "Hello"},
+				Translation: localize.Translation{Text: "Hallo"},
+			},
+			{
+				Key:         localize.Key{Hash: "1", Source: "Untranslated"},
+				Translation: localize.Translation{},
+			},
+			{
+				Key: localize.Key{Hash: "c2b9e5304ee8d192", Source: "%d apples"},
+				Translation: localize.Translation{Plural: true, Forms: localize.Forms{
+					One: "%d Apfel", Other: "%d Äpfel",
+				}},
+			},
+			{
+				Key:         localize.Key{Hash: "2", Source: "%d pears"},
+				Translation: localize.Translation{Plural: true},
+			},
+		},
+	}
+}
+
+func TestStrictReader(t *testing.T) {
+	var reported []error
+	r := newStrictTestReader()
+	s := localize.NewStrictReader(r, func(err error) { reported = append(reported, err) })
+	require.Equal(t, r, s.Unwrap())
+	require.Equal(t, language.German, s.Locale())
+
+	require.Equal(t, "Hallo", s.Text("Hello"))
+	s.Block("\n\tHello\n")
+	s.Plural(localize.Forms{One: "%d apple", Other: "%d apples"}, 2)
+	require.Empty(t, reported)
+
+	require.Equal(t, "Untranslated", s.Text("Untranslated"))
+	require.Equal(t, "Not in catalog", s.Text("Not in catalog"))
+	s.PluralBlock(localize.Forms{One: "\n\t%d pear\n", Other: "\n\t%d pears\n"}, 2)
+
+	require.Len(t, reported, 3)
+	for _, err := range reported {
+		require.True(t, errors.Is(err, localize.ErrMissingTranslation))
+	}
+	require.Equal(t, &localize.MissingTranslationError{
+		Locale: language.German, Source: "Untranslated",
+	}, reported[0])
+	require.Equal(t, &localize.MissingTranslationError{
+		Locale: language.German, Source: "Not in catalog", NotInCatalog: true,
+	}, reported[1])
+	require.Equal(t, &localize.MissingTranslationError{
+		Locale: language.German, Source: "%d pears", Quantity: 2,
+	}, reported[2])
+}
+
+func TestStrictReaderPanic(t *testing.T) {
+	s := localize.NewStrictReader(newStrictTestReader(), nil)
+	require.Equal(t, "Hallo", s.Text("Hello"))
+	require.PanicsWithError(t,
+		`missing translation (de): message not in catalog: "Not in catalog"`,
+		func() { s.Text("Not in catalog") })
+}
+
+func TestStrictReaderNoCataloger(t *testing.T) {
+	s := localize.NewStrictReader(MockReader{tag: language.German}, nil)
+	require.NotPanics(t, func() { s.Text("Anything") })
+}
+
+func TestStrictBundle(t *testing.T) {
+	var reported []error
+	b, err := localize.NewWithOptions(language.German, localize.Options{
+		Strict:    true,
+		OnMissing: func(err error) { reported = append(reported, err) },
+	}, newStrictTestReader())
+	require.NoError(t, err)
+
+	r := b.Default()
+	require.IsType(t, &localize.StrictReader{}, r)
+	require.Equal(t, "Hallo", r.Text("Hello"))
+	require.Equal(t, "Untranslated", r.Text("Untranslated"))
+	require.Len(t, reported, 1)
+}
+
+func TestMust(t *testing.T) {
+	r := newStrictTestReader()
+
+	require.Equal(t, "Hallo", localize.MustText(r, "Hello"))
+	require.NotPanics(t, func() { localize.MustBlock(r, "\n\tHello\n") })
+	require.NotPanics(t, func() {
+		localize.MustPlural(r, localize.Forms{Other: "%d apples"}, 2)
+	})
+
+	require.PanicsWithError(t, `missing translation (de): "Untranslated"`,
+		func() { localize.MustText(r, "Untranslated") })
+	require.PanicsWithError(t, `missing translation (de): "%d pears"`,
+		func() { localize.MustPluralBlock(r, localize.Forms{Other: "%d pears"}, 2) })
+
+	// Must helpers panic even if the strict reader doesn't.
+	s := localize.NewStrictReader(r, func(error) {})
+	c := localize.Chain(s, localize.TitleCaseTransformer)
+	require.Panics(t, func() { localize.MustText(c, "Untranslated") })
+}
@@ -0,0 +1,50 @@
+package localize
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DetectSystemLocale returns the locale of the user's operating system.
+// The POSIX environment variables LC_ALL, LC_MESSAGES and LANG are checked
+// in this order on all platforms, the first one set defines the locale.
+// On Windows the user default locale is used if none of them is set.
+// Returns false if no locale is detected or the locale is "C" or "POSIX".
+func DetectSystemLocale() (language.Tag, bool) {
+	for _, name := range [...]string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return parsePOSIXLocale(v)
+		}
+	}
+	return userDefaultLocale()
+}
+
+// parsePOSIXLocale parses a POSIX locale in the format
+// language[_territory][.codeset][@modifier] like "de_DE.UTF-8".
+func parsePOSIXLocale(s string) (language.Tag, bool) {
+	s, modifier, _ := strings.Cut(s, "@")
+	s, _, _ = strings.Cut(s, ".")
+	if s == "" || s == "C" || s == "POSIX" {
+		return language.Und, false
+	}
+	tag, err := language.Parse(strings.ReplaceAll(s, "_", "-"))
+	if err != nil {
+		return language.Und, false
+	}
+	// Modifiers like in "sr_RS@latin" select the script.
+	var script string
+	switch modifier {
+	case "latin":
+		script = "Latn"
+	case "cyrillic":
+		script = "Cyrl"
+	}
+	if script != "" {
+		if t, err := language.Compose(tag, language.MustParseScript(script)); err == nil {
+			tag = t
+		}
+	}
+	return tag, true
+}
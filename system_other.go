@@ -0,0 +1,7 @@
+//go:build !windows
+
+package localize
+
+import "golang.org/x/text/language"
+
+func userDefaultLocale() (language.Tag, bool) { return language.Und, false }
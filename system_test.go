@@ -0,0 +1,63 @@
+package localize_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestDetectSystemLocale(t *testing.T) {
+	for _, tt := range []struct {
+		name                    string
+		lcAll, lcMessages, lang string
+		expect                  string // Empty if not detected.
+	}{
+		{name: "lang", lang: "de_DE.UTF-8", expect: "de-DE"},
+		{name: "lang_base", lang: "fr", expect: "fr"},
+		{name: "lc_messages", lcMessages: "uk_UA.UTF-8", lang: "de_DE", expect: "uk-UA"},
+		{name: "lc_all", lcAll: "ja_JP", lcMessages: "uk_UA", lang: "de_DE", expect: "ja-JP"},
+		{name: "modifier_script", lang: "sr_RS.UTF-8@latin", expect: "sr-Latn-RS"},
+		{name: "modifier_other", lang: "de_DE@euro", expect: "de-DE"},
+		{name: "c", lang: "C.UTF-8"},
+		{name: "posix", lcAll: "POSIX", lang: "de_DE"},
+		{name: "invalid", lang: "not a locale"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", tt.lcMessages)
+			t.Setenv("LANG", tt.lang)
+			tag, ok := localize.DetectSystemLocale()
+			if tt.expect == "" {
+				require.False(t, ok)
+				require.Equal(t, language.Und, tag)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tt.expect, tag.String())
+		})
+	}
+}
+
+func TestForSystem(t *testing.T) {
+	l, err := localize.New(language.English, mockReaders(
+		language.English, language.German,
+	)...)
+	require.NoError(t, err)
+
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+
+	t.Setenv("LANG", "de_AT.UTF-8")
+	require.Equal(t, language.German, l.ForSystem().Locale())
+
+	t.Setenv("LANG", "C")
+	require.Equal(t, language.English, l.ForSystem().Locale())
+
+	if runtime.GOOS != "windows" {
+		t.Setenv("LANG", "")
+		require.Equal(t, language.English, l.ForSystem().Locale())
+	}
+}
@@ -0,0 +1,29 @@
+package localize
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/text/language"
+)
+
+var procGetUserDefaultLocaleName = syscall.NewLazyDLL("kernel32.dll").
+	NewProc("GetUserDefaultLocaleName")
+
+// userDefaultLocale returns the user default locale of Windows.
+func userDefaultLocale() (language.Tag, bool) {
+	const localeNameMaxLength = 85 // LOCALE_NAME_MAX_LENGTH
+	var buf [localeNameMaxLength]uint16
+	n, _, _ := procGetUserDefaultLocaleName.Call(
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)),
+	)
+	if n == 0 {
+		return language.Und, false
+	}
+	// n includes the terminating null character.
+	tag, err := language.Parse(syscall.UTF16ToString(buf[:n-1]))
+	if err != nil {
+		return language.Und, false
+	}
+	return tag, true
+}
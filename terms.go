@@ -0,0 +1,33 @@
+package localize
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// NewTermTransformer returns a Transformer replacing the term placeholders
+// "{name}" in localized strings with the values of terms by name,
+// such that protected terms like brand names can be changed without
+// retranslating the messages using them:
+//
+//	l = localize.Chain(l, localize.NewTermTransformer(map[string]string{
+//		"productName": "Acme Cloud",
+//	}))
+//	l.Text("Welcome to {productName}!") // "Willkommen bei Acme Cloud!"
+//
+// Placeholders of names not in terms are left unchanged.
+// Use `localize generate -term name` to validate the placeholders
+// in the source code.
+func NewTermTransformer(terms map[string]string) Transformer {
+	oldnew := make([]string, 0, len(terms)*2)
+	for _, name := range slices.Sorted(maps.Keys(terms)) {
+		oldnew = append(oldnew, "{"+name+"}", terms[name])
+	}
+	r := strings.NewReplacer(oldnew...)
+	return TransformerFunc(func(_ language.Tag, s string) string {
+		return r.Replace(s)
+	})
+}
@@ -0,0 +1,30 @@
+package localize_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestTermTransformer(t *testing.T) {
+	tr := localize.NewTermTransformer(map[string]string{
+		"productName": "Acme Cloud",
+		"company":     "Acme",
+	})
+	f := func(t *testing.T, input, expect string) {
+		t.Helper()
+		require.Equal(t, expect, tr.Transform(language.German, input))
+	}
+	f(t, "Willkommen bei {productName}!", "Willkommen bei Acme Cloud!")
+	f(t, "{productName} von {company}", "Acme Cloud von Acme")
+	f(t, "{unknown} {productName", "{unknown} {productName")
+	f(t, "", "")
+
+	r := localize.Chain(MockReader{
+		tag:    language.German,
+		static: map[string]string{"Welcome to {productName}": "Willkommen bei {productName}"},
+	}, tr)
+	require.Equal(t, "Willkommen bei Acme Cloud", r.Text("Welcome to {productName}"))
+}
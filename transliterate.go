@@ -0,0 +1,145 @@
+package localize
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// ErrNoTransliterationSource is returned by NewWithOptions if the source
+// locale of a transliteration has no reader in the bundle.
+var ErrNoTransliterationSource = errors.New("no reader for transliteration source")
+
+// Transliteration derives the reader of locale Target from the reader of
+// locale Source by transliterating all localized strings, such that a
+// bundle with an "sr-Cyrl" catalog can serve "sr-Latn" without a catalog
+// of its own.
+type Transliteration struct {
+	Source, Target language.Tag
+
+	// Transliterator transliterates the localized strings of Source.
+	// It's called with locale Target.
+	Transliterator Transformer
+}
+
+// Transliterate returns a reader for locale that localizes using r and
+// transliterates every localized string using t.
+// Base and Translator are those of r.
+func Transliterate(r Reader, locale language.Tag, t Transformer) Reader {
+	return &transliteratedReader{Reader: r, locale: locale, t: t}
+}
+
+type transliteratedReader struct {
+	Reader
+	locale language.Tag
+	t      Transformer
+}
+
+// Unwrap returns the wrapped reader.
+func (r *transliteratedReader) Unwrap() Reader { return r.Reader }
+
+func (r *transliteratedReader) Locale() language.Tag { return r.locale }
+
+func (r *transliteratedReader) Text(text string) string {
+	return r.t.Transform(r.locale, r.Reader.Text(text))
+}
+
+func (r *transliteratedReader) Block(text string) string {
+	return r.t.Transform(r.locale, r.Reader.Block(text))
+}
+
+func (r *transliteratedReader) Plural(templates Forms, quantity any) string {
+	return r.t.Transform(r.locale, r.Reader.Plural(templates, quantity))
+}
+
+func (r *transliteratedReader) PluralBlock(templates Forms, quantity any) string {
+	return r.t.Transform(r.locale, r.Reader.PluralBlock(templates, quantity))
+}
+
+func (r *transliteratedReader) Cardinal(otherTemplate string, quantity any) string {
+	return r.t.Transform(r.locale, r.Reader.Cardinal(otherTemplate, quantity))
+}
+
+func (r *transliteratedReader) PluralRange(templates Forms, from, to any) string {
+	return r.t.Transform(r.locale, r.Reader.PluralRange(templates, from, to))
+}
+
+func (r *transliteratedReader) PluralOrdinal(
+	templates OrdinalForms, ordinal, quantity any,
+) string {
+	return r.t.Transform(r.locale, r.Reader.PluralOrdinal(templates, ordinal, quantity))
+}
+
+func (r *transliteratedReader) Grammar(key string, args ...string) string {
+	return r.t.Transform(r.locale, r.Reader.Grammar(key, args...))
+}
+
+func (r *transliteratedReader) FormatCompact(n float64) string {
+	return r.t.Transform(r.locale, r.Reader.FormatCompact(n))
+}
+
+func (r *transliteratedReader) WithRegister(register Register) Reader {
+	return &transliteratedReader{
+		Reader: r.Reader.WithRegister(register), locale: r.locale, t: r.t,
+	}
+}
+
+func (r *transliteratedReader) Section(name string) Reader {
+	return &transliteratedReader{
+		Reader: r.Reader.Section(name), locale: r.locale, t: r.t,
+	}
+}
+
+// SerbianLatinTransliterator transliterates Serbian Cyrillic to
+// Serbian Latin (gajica).
+var SerbianLatinTransliterator Transformer = TransformerFunc(
+	func(_ language.Tag, s string) string {
+		return replacerSerbianLatin.Replace(s)
+	},
+)
+
+var replacerSerbianLatin = strings.NewReplacer(
+	"А", "A", "Б", "B", "В", "V", "Г", "G", "Д", "D", "Ђ", "Đ",
+	"Е", "E", "Ж", "Ž", "З", "Z", "И", "I", "Ј", "J", "К", "K",
+	"Л", "L", "Љ", "Lj", "М", "M", "Н", "N", "Њ", "Nj", "О", "O",
+	"П", "P", "Р", "R", "С", "S", "Т", "T", "Ћ", "Ć", "У", "U",
+	"Ф", "F", "Х", "H", "Ц", "C", "Ч", "Č", "Џ", "Dž", "Ш", "Š",
+	"а", "a", "б", "b", "в", "v", "г", "g", "д", "d", "ђ", "đ",
+	"е", "e", "ж", "ž", "з", "z", "и", "i", "ј", "j", "к", "k",
+	"л", "l", "љ", "lj", "м", "m", "н", "n", "њ", "nj", "о", "o",
+	"п", "p", "р", "r", "с", "s", "т", "t", "ћ", "ć", "у", "u",
+	"ф", "f", "х", "h", "ц", "c", "ч", "č", "џ", "dž", "ш", "š",
+)
+
+// withTransliterations returns bundle with the readers of all transliterations
+// whose target locale has no reader appended.
+func withTransliterations(
+	bundle []Reader, transliterations []Transliteration,
+) ([]Reader, error) {
+	if len(transliterations) < 1 {
+		return bundle, nil
+	}
+	byLocale := make(map[language.Tag]Reader, len(bundle))
+	for _, r := range bundle {
+		byLocale[canonical(r.Locale())] = r
+	}
+	bundle = slices.Clip(bundle)
+	for _, t := range transliterations {
+		target := canonical(t.Target)
+		if _, ok := byLocale[target]; ok {
+			// A catalog of the target locale takes precedence.
+			continue
+		}
+		source, ok := byLocale[canonical(t.Source)]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrNoTransliterationSource, t.Source)
+		}
+		r := Transliterate(source, target, t.Transliterator)
+		byLocale[target] = r
+		bundle = append(bundle, r)
+	}
+	return bundle, nil
+}
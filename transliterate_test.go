@@ -0,0 +1,85 @@
+package localize_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestTransliterations(t *testing.T) {
+	sr := MockReader{
+		tag:    language.Serbian,
+		static: map[string]string{"Good night": "Лаку ноћ, Џоне"},
+	}
+	srLatn := language.MustParse("sr-Latn")
+	l, err := localize.NewWithOptions(language.English, localize.Options{
+		Transliterations: []localize.Transliteration{{
+			Source:         language.Serbian,
+			Target:         srLatn,
+			Transliterator: localize.SerbianLatinTransliterator,
+		}},
+	}, MockReader{tag: language.English}, sr)
+	require.NoError(t, err)
+	require.Equal(t, []language.Tag{language.English, language.Serbian, srLatn},
+		l.Locales())
+
+	r := l.ForLocale(srLatn)
+	require.Equal(t, srLatn, r.Locale())
+	require.Equal(t, "Laku noć, Džone", r.Text("Good night"))
+	require.Equal(t, sr, r.(interface{ Unwrap() localize.Reader }).Unwrap())
+
+	require.Equal(t, srLatn, l.ForLocale(language.MustParse("sr-Latn-RS")).Locale())
+	require.Equal(t, language.Serbian, l.ForLocale(language.MustParse("sr-RS")).Locale())
+	require.Equal(t, "Лаку ноћ, Џоне", l.ForLocale(language.Serbian).Text("Good night"))
+
+	m, _ := l.Match(srLatn)
+	require.Equal(t, srLatn, m.Locale())
+}
+
+func TestTransliterationsCatalogPrecedence(t *testing.T) {
+	srLatn := language.MustParse("sr-Latn")
+	l, err := localize.NewWithOptions(language.English, localize.Options{
+		Transliterations: []localize.Transliteration{{
+			Source:         language.Serbian,
+			Target:         srLatn,
+			Transliterator: localize.SerbianLatinTransliterator,
+		}},
+	}, mockReaders(language.English, language.Serbian, srLatn)...)
+	require.NoError(t, err)
+	require.Len(t, l.Readers(), 3)
+	require.Equal(t, srLatn, l.ForLocale(srLatn).Locale())
+}
+
+func TestErrNoTransliterationSource(t *testing.T) {
+	_, err := localize.NewWithOptions(language.English, localize.Options{
+		Transliterations: []localize.Transliteration{{
+			Source:         language.Serbian,
+			Target:         language.MustParse("sr-Latn"),
+			Transliterator: localize.SerbianLatinTransliterator,
+		}},
+	}, mockReaders(language.English)...)
+	require.ErrorIs(t, err, localize.ErrNoTransliterationSource)
+}
+
+func TestForLocaleScripts(t *testing.T) {
+	zhHant := language.MustParse("zh-Hant")
+	readers := mockReaders(language.English, language.Chinese, zhHant)
+
+	l, err := localize.New(language.English, readers...)
+	require.NoError(t, err)
+	require.Equal(t, zhHant, l.ForLocale(language.MustParse("zh-TW")).Locale())
+	require.Equal(t, language.Chinese, l.ForLocale(language.MustParse("zh-CN")).Locale())
+
+	l, err = localize.New(language.English, readers[:2]...)
+	require.NoError(t, err)
+	require.Equal(t, language.Chinese, l.ForLocale(language.MustParse("zh-TW")).Locale())
+
+	l, err = localize.NewWithOptions(language.English, localize.Options{
+		StrictScripts: true,
+	}, readers[:2]...)
+	require.NoError(t, err)
+	require.Equal(t, language.English, l.ForLocale(language.MustParse("zh-TW")).Locale())
+	require.Equal(t, language.Chinese, l.ForLocale(language.MustParse("zh-CN")).Locale())
+}
@@ -0,0 +1,58 @@
+package localize
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/romshark/localize/internal/grapheme"
+	"golang.org/x/text/language"
+)
+
+// ellipses maps locales to the ellipsis appended to truncated texts.
+// Locales are matched by base language, all others use the ellipsis
+// of "root".
+var ellipses = map[string]string{
+	"root": "…",
+	// Chinese typography uses a double ellipsis (GB/T 15834).
+	"zh": "……",
+}
+
+// Ellipsis returns the ellipsis appended to texts truncated
+// by Truncate in locale, such as "…" for "en" and "……" for "zh".
+func Ellipsis(locale language.Tag) string {
+	base, _ := locale.Base()
+	if e, ok := ellipses[base.String()]; ok {
+		return e
+	}
+	return ellipses["root"]
+}
+
+// Truncate returns s truncated to at most max grapheme clusters
+// (user-perceived characters) including the ellipsis of locale
+// (see Ellipsis), such that combining marks, emoji sequences and
+// flags are never split. Trailing white space is removed before the
+// ellipsis is appended. Returns s if it has at most max clusters, and
+// s truncated to max clusters without an ellipsis if max leaves no room
+// for at least one cluster before the ellipsis.
+func Truncate(locale language.Tag, s string, max int) string {
+	if max < 1 {
+		return ""
+	}
+	ellipsis := Ellipsis(locale)
+	keep := max - grapheme.Count(ellipsis)
+	if keep < 1 {
+		keep, ellipsis = max, ""
+	}
+	end, n := 0, 0 // Byte length of the first keep clusters and cluster count.
+	for rest := s; rest != ""; n++ {
+		if n == max {
+			return strings.TrimRightFunc(s[:end], unicode.IsSpace) + ellipsis
+		}
+		c, r := grapheme.Next(rest)
+		if n < keep {
+			end += len(c)
+		}
+		rest = r
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package localize_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestTruncate(t *testing.T) {
+	const family = "\U0001f469‍\U0001f469‍\U0001f467"
+	for _, tt := range []struct {
+		locale string
+		s      string
+		max    int
+		expect string
+	}{
+		{"en", "", 5, ""},
+		{"en", "Hello", 5, "Hello"},
+		{"en", "Hello", 0, ""},
+		{"en", "Hello", -1, ""},
+		{"en", "Hello world", 8, "Hello w…"},
+		// Trailing white space is removed before the ellipsis.
+		{"en", "Hello world", 7, "Hello…"},
+		// No room for text before the ellipsis.
+		{"en", "Hello", 1, "H"},
+		// Combining marks aren't split.
+		{"fr", "Café au lait", 5, "Café…"},
+		{"ja", "日本語のテキスト", 4, "日本語…"},
+		{"zh", "简体中文文本", 5, "简体中……"},
+		{"zh-TW", "繁體中文文本", 4, "繁體……"},
+		{"zh", "简体", 2, "简体"},
+		{"en", family + family + family, 2, family + "…"},
+		{"en", "\U0001f1e9\U0001f1ea\U0001f1eb\U0001f1f7", 1, "\U0001f1e9\U0001f1ea"},
+	} {
+		t.Run(tt.locale, func(t *testing.T) {
+			require.Equal(t, tt.expect, localize.Truncate(
+				language.MustParse(tt.locale), tt.s, tt.max,
+			))
+		})
+	}
+}
+
+func TestEllipsis(t *testing.T) {
+	require.Equal(t, "…", localize.Ellipsis(language.English))
+	require.Equal(t, "……", localize.Ellipsis(language.SimplifiedChinese))
+	require.Equal(t, "…", localize.Ellipsis(language.Und))
+}
@@ -0,0 +1,185 @@
+package typography
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// Casing is the capitalization convention of headings in a locale,
+// such as title case in English ("Account Settings") and sentence case
+// in French ("Paramètres du compte").
+type Casing uint8
+
+const (
+	// CasingNone leaves headings unchanged.
+	CasingNone Casing = iota
+
+	// CasingTitle capitalizes all words of headings. English minor words
+	// like "of" and "the" are lowercased unless they're first or last.
+	CasingTitle
+
+	// CasingSentence capitalizes the first word of headings only.
+	// Capitalized words other than the first are lowercased unless they're
+	// acronyms ("URL"), mixed case ("iPhone") or single letters ("I").
+	// Sentence case must not be applied to locales capitalizing nouns,
+	// like German.
+	CasingSentence
+)
+
+// String returns the name of c used by ParseCasing.
+func (c Casing) String() string {
+	switch c {
+	case CasingNone:
+		return "none"
+	case CasingTitle:
+		return "title"
+	case CasingSentence:
+		return "sentence"
+	}
+	return fmt.Sprintf("Casing(%d)", uint8(c))
+}
+
+// ParseCasing returns the casing named s (see Casing.String).
+// ok is false if s names no casing.
+func ParseCasing(s string) (c Casing, ok bool) {
+	switch s {
+	case "none":
+		return CasingNone, true
+	case "title":
+		return CasingTitle, true
+	case "sentence":
+		return CasingSentence, true
+	}
+	return 0, false
+}
+
+// minorWordsEnglish are the words that aren't capitalized
+// in English title case unless they're first or last.
+var minorWordsEnglish = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "as": {}, "at": {}, "but": {}, "by": {},
+	"for": {}, "in": {}, "nor": {}, "of": {}, "on": {}, "or": {}, "per": {},
+	"the": {}, "to": {}, "via": {}, "vs": {},
+}
+
+// Apply returns heading s converted to casing c in locale.
+// The substrings of s listed in keep, such as product names, and
+// Go fmt placeholders such as "%s" are left untouched.
+func (c Casing) Apply(locale language.Tag, s string, keep ...string) string {
+	if c != CasingTitle && c != CasingSentence {
+		return s
+	}
+	base, _ := locale.Base()
+	english := base.String() == "en"
+
+	// kept marks the bytes of s that must not be changed.
+	kept := make([]bool, len(s))
+	for _, k := range keep {
+		for off := 0; k != ""; {
+			i := strings.Index(s[off:], k)
+			if i < 0 {
+				break
+			}
+			for j := off + i; j < off+i+len(k); j++ {
+				kept[j] = true
+			}
+			off += i + len(k)
+		}
+	}
+
+	type word struct{ start, end int }
+	var words []word
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '%':
+			// Skip the placeholder up to and including its verb.
+			i += size
+			for i < len(s) {
+				r, size := utf8.DecodeRuneInString(s[i:])
+				i += size
+				if unicode.IsLetter(r) || r == '%' {
+					break
+				}
+			}
+			continue
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(s) {
+				r, size := utf8.DecodeRuneInString(s[i:])
+				if !unicode.IsLetter(r) && r != '\'' && r != '’' {
+					break
+				}
+				i += size
+			}
+			words = append(words, word{start, i})
+			continue
+		}
+		i += size
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	last := 0
+	for i, w := range words {
+		b.WriteString(s[last:w.start])
+		last = w.end
+		text := s[w.start:w.end]
+		if kept[w.start] {
+			b.WriteString(text)
+			continue
+		}
+		switch {
+		case i == 0:
+			b.WriteString(capitalize(text))
+		case c == CasingSentence:
+			if isCapitalized(text) {
+				b.WriteString(lowercase(text))
+			} else {
+				b.WriteString(text)
+			}
+		case english && i < len(words)-1 && isMinorEnglish(text):
+			if isCapitalized(text) {
+				b.WriteString(lowercase(text))
+			} else {
+				b.WriteString(text)
+			}
+		default:
+			b.WriteString(capitalize(text))
+		}
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func isMinorEnglish(w string) bool {
+	_, ok := minorWordsEnglish[strings.ToLower(w)]
+	return ok
+}
+
+// capitalize uppercases the first letter of all-lowercase words.
+func capitalize(w string) string {
+	if strings.ToLower(w) != w {
+		return w // Capitalized, acronym or mixed case.
+	}
+	r, size := utf8.DecodeRuneInString(w)
+	return string(unicode.ToTitle(r)) + w[size:]
+}
+
+// lowercase lowercases the first letter of w.
+func lowercase(w string) string {
+	r, size := utf8.DecodeRuneInString(w)
+	return string(unicode.ToLower(r)) + w[size:]
+}
+
+// isCapitalized returns true for words of at least two letters
+// consisting of an uppercase letter followed by lowercase letters only.
+func isCapitalized(w string) bool {
+	r, size := utf8.DecodeRuneInString(w)
+	rest := w[size:]
+	return unicode.IsUpper(r) && utf8.RuneCountInString(rest) > 0 &&
+		strings.ToLower(rest) == rest
+}
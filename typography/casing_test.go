@@ -0,0 +1,49 @@
+package typography_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/typography"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestCasingApply(t *testing.T) {
+	f := func(
+		t *testing.T, c typography.Casing, locale, input, expect string,
+		keep ...string,
+	) {
+		t.Helper()
+		require.Equal(t, expect, c.Apply(language.MustParse(locale), input, keep...))
+	}
+
+	f(t, typography.CasingNone, "en", "account settings", "account settings")
+
+	f(t, typography.CasingTitle, "en", "terms of service", "Terms of Service")
+	f(t, typography.CasingTitle, "en", "the state of the art", "The State of the Art")
+	f(t, typography.CasingTitle, "en", "what to look for", "What to Look For")
+	f(t, typography.CasingTitle, "en", "manage your iPhone and URL", "Manage Your iPhone and URL")
+	f(t, typography.CasingTitle, "en", "%d new messages", "%d New Messages")
+	f(t, typography.CasingTitle, "en", "don't panic", "Don't Panic")
+	f(t, typography.CasingTitle, "nl", "uw account", "Uw Account")
+
+	f(t, typography.CasingSentence, "fr", "Paramètres Du Compte", "Paramètres du compte")
+	f(t, typography.CasingSentence, "fr", "paramètres du compte", "Paramètres du compte")
+	f(t, typography.CasingSentence, "es", "Gestionar Su iPhone Con URL", "Gestionar su iPhone con URL")
+	f(t, typography.CasingSentence, "fr", "Modifier %[1]s Et Plus", "Modifier %[1]s et plus")
+	f(t, typography.CasingSentence, "en", "What I Like", "What I like")
+	f(t, typography.CasingSentence, "fr", "Essayez Acme Cloud Gratuitement",
+		"Essayez Acme Cloud gratuitement", "Acme Cloud")
+}
+
+func TestParseCasing(t *testing.T) {
+	for _, c := range []typography.Casing{
+		typography.CasingNone, typography.CasingTitle, typography.CasingSentence,
+	} {
+		p, ok := typography.ParseCasing(c.String())
+		require.True(t, ok)
+		require.Equal(t, c, p)
+	}
+	_, ok := typography.ParseCasing("upper")
+	require.False(t, ok)
+}
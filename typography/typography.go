@@ -0,0 +1,197 @@
+// Package typography provides locale-specific typographic post-processing.
+//
+// Apply can be used as a localize.Transformer at runtime:
+//
+//	localize.Chain(reader, localize.TransformerFunc(typography.Apply))
+package typography
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	nbsp       = "\u00a0" // No-break space.
+	narrowNBSP = "\u202f" // Narrow no-break space.
+	enDash     = "–"
+	emDash     = "—"
+	apostrophe = "’"
+)
+
+// Rules are the typographic rules of a locale.
+type Rules struct {
+	// QuoteOpen and QuoteClose replace pairs of straight double quotes.
+	QuoteOpen, QuoteClose string
+
+	// SpaceInsideQuotes is inserted after QuoteOpen and before QuoteClose.
+	SpaceInsideQuotes string
+
+	// SpaceBeforePunctuation is inserted before ";", ":", "!" and "?"
+	// replacing any regular space preceding them.
+	SpaceBeforePunctuation string
+}
+
+// RulesFor returns the typographic rules for locale.
+// English quotation rules are used for unknown locales.
+func RulesFor(locale language.Tag) Rules {
+	base, _ := locale.Base()
+	switch base.String() {
+	case "fr":
+		if r, _ := locale.Region(); r.String() == "CH" {
+			return Rules{QuoteOpen: "«", QuoteClose: "»"}
+		}
+		return Rules{
+			QuoteOpen: "«", QuoteClose: "»",
+			SpaceInsideQuotes:      nbsp,
+			SpaceBeforePunctuation: narrowNBSP,
+		}
+	case "de":
+		if r, _ := locale.Region(); r.String() == "CH" || r.String() == "LI" {
+			return Rules{QuoteOpen: "«", QuoteClose: "»"}
+		}
+		return Rules{QuoteOpen: "„", QuoteClose: "“"}
+	case "cs", "sk", "sl", "hr", "et", "lt", "is", "bg", "ka":
+		return Rules{QuoteOpen: "„", QuoteClose: "“"}
+	case "pl", "ro", "hu", "nl":
+		return Rules{QuoteOpen: "„", QuoteClose: "”"}
+	case "ru", "uk", "be", "es", "it", "pt", "ca", "el", "no", "nb", "nn", "ar", "fa":
+		return Rules{QuoteOpen: "«", QuoteClose: "»"}
+	case "da":
+		return Rules{QuoteOpen: "»", QuoteClose: "«"}
+	case "sv", "fi":
+		return Rules{QuoteOpen: "”", QuoteClose: "”"}
+	case "ja", "zh":
+		return Rules{QuoteOpen: "「", QuoteClose: "」"}
+	}
+	return Rules{QuoteOpen: "“", QuoteClose: "”"}
+}
+
+// Apply applies the typographic rules of locale to s. See Rules.Apply.
+func Apply(locale language.Tag, s string) string {
+	return RulesFor(locale).Apply(s)
+}
+
+// Apply returns s with:
+//   - pairs of straight double quotes replaced by the locale's quotation marks.
+//   - apostrophes between letters replaced by "’".
+//   - " -- " replaced by a spaced em dash and " - " by a spaced en dash.
+//   - the space before ";", ":", "!" and "?" replaced if required by the locale.
+//     Sequences like "?!" are treated as a single punctuation mark.
+//
+// Hyphens without surrounding spaces such as in "555-1234", "2024-01-05"
+// and "--force" are kept, numeric ranges must be written with an en dash
+// explicitly like "1–5". Words that aren't prose such as URLs,
+// email addresses, paths and query strings (see isVerbatim)
+// and Go fmt placeholders such as "%-5d" are left untouched.
+func (r Rules) Apply(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	quoteOpen := false
+	prev := rune(-1) // Previous rune of s.
+	for i := 0; i < len(s); {
+		if prev == -1 || unicode.IsSpace(prev) {
+			if word := wordAt(s, i); isVerbatim(word) {
+				b.WriteString(word)
+				prev, _ = utf8.DecodeLastRuneInString(word)
+				i += len(word)
+				continue
+			}
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		next, _ := utf8.DecodeRuneInString(s[i+size:])
+		if i+size >= len(s) {
+			next = -1
+		}
+
+		switch {
+		case c == '"':
+			if !quoteOpen && strings.Count(s[i+size:], `"`) < 1 {
+				// Unpaired quote.
+				b.WriteRune(c)
+				break
+			}
+			if quoteOpen {
+				b.WriteString(r.SpaceInsideQuotes)
+				b.WriteString(r.QuoteClose)
+			} else {
+				b.WriteString(r.QuoteOpen)
+				b.WriteString(r.SpaceInsideQuotes)
+			}
+			quoteOpen = !quoteOpen
+
+		case c == '\'' && unicode.IsLetter(prev) && unicode.IsLetter(next):
+			b.WriteString(apostrophe)
+
+		case c == '-' && next == '-' && isSpaceOrEdge(prev) &&
+			isSpaceOrEdge(runeAt(s, i+2)):
+			b.WriteString(emDash)
+			size++ // Skip the second hyphen.
+
+		case c == '-' && prev == ' ' && next == ' ':
+			b.WriteString(enDash)
+
+		case r.SpaceBeforePunctuation != "" && c == ' ' &&
+			isSpacedPunctuation(next) && !isVerbatim(wordAt(s, i+size)):
+			b.WriteString(r.SpaceBeforePunctuation)
+
+		case r.SpaceBeforePunctuation != "" && prev != ' ' && prev != -1 &&
+			!isSpacedPunctuation(prev) &&
+			isSpacedPunctuation(c) && c != ':' && !isPlaceholderVerb(s, i):
+			b.WriteString(r.SpaceBeforePunctuation)
+			b.WriteRune(c)
+
+		default:
+			b.WriteRune(c)
+		}
+		prev = c
+		i += size
+	}
+	return b.String()
+}
+
+// isSpacedPunctuation returns true for the punctuation marks preceded
+// by Rules.SpaceBeforePunctuation.
+func isSpacedPunctuation(c rune) bool {
+	return c == ';' || c == ':' || c == '!' || c == '?'
+}
+
+// isSpaceOrEdge returns true if c is a space or -1 for the edges of a string.
+func isSpaceOrEdge(c rune) bool { return c == -1 || unicode.IsSpace(c) }
+
+// runeAt returns the rune at byte index i of s or -1 if i is out of range.
+func runeAt(s string, i int) rune {
+	if i >= len(s) {
+		return -1
+	}
+	c, _ := utf8.DecodeRuneInString(s[i:])
+	return c
+}
+
+// wordAt returns the word of s starting at byte index i
+// up to the next space.
+func wordAt(s string, i int) string {
+	if end := strings.IndexFunc(s[i:], unicode.IsSpace); end != -1 {
+		return s[i : i+end]
+	}
+	return s[i:]
+}
+
+// isVerbatim returns true for words that aren't prose and must not be
+// changed, such as URLs, email addresses, paths and query strings.
+func isVerbatim(word string) bool {
+	if strings.Contains(word, "://") || strings.HasPrefix(word, "www.") {
+		return true
+	}
+	if at := strings.IndexByte(word, '@'); at > 0 &&
+		strings.Contains(word[at+1:], ".") {
+		return true // Email address.
+	}
+	return strings.ContainsAny(word, "/\\=&_")
+}
+
+// isPlaceholderVerb returns true if s[i] is preceded by a
+// fmt placeholder prefix like "%" (e.g. "%!").
+func isPlaceholderVerb(s string, i int) bool { return i > 0 && s[i-1] == '%' }
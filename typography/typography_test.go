@@ -0,0 +1,65 @@
+package typography_test
+
+import (
+	"testing"
+
+	"github.com/romshark/localize/typography"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestApply(t *testing.T) {
+	f := func(t *testing.T, locale, input, expect string) {
+		t.Helper()
+		actual := typography.Apply(language.MustParse(locale), input)
+		require.Equal(t, expect, actual)
+	}
+
+	f(t, "en", `Say "hello"`, "Say “hello”")
+	f(t, "en", `It's "fine" -- really`, "It’s “fine” — really")
+	f(t, "en", "Pages 1-5 - done", "Pages 1-5 – done")
+	f(t, "en", "Pages 1–5", "Pages 1–5")
+	f(t, "en", `Unpaired " quote`, `Unpaired " quote`)
+	f(t, "en", "%-5d items", "%-5d items")
+	f(t, "en", "'quoted'", "'quoted'")
+
+	f(t, "de", `Sag "Hallo"`, "Sag „Hallo“")
+	f(t, "de-CH", `Sag "Hallo"`, "Sag «Hallo»")
+	f(t, "uk", `Скажи "привіт"`, "Скажи «привіт»")
+	f(t, "ja", `"こんにちは"`, "「こんにちは」")
+
+	f(t, "fr", `Dites "bonjour" !`, "Dites «\u00a0bonjour\u00a0»\u202f!")
+	f(t, "fr", "Vraiment?", "Vraiment\u202f?")
+	f(t, "fr", "Note : ceci", "Note\u202f: ceci")
+	f(t, "fr-CH", "Vraiment?", "Vraiment?")
+	f(t, "fr", "%d%!", "%d%!")
+	f(t, "fr", "Vraiment?!", "Vraiment\u202f?!")
+	f(t, "fr", "Vraiment ?!", "Vraiment\u202f?!")
+}
+
+func TestApplyNonProse(t *testing.T) {
+	f := func(t *testing.T, locale, input, expect string) {
+		t.Helper()
+		actual := typography.Apply(language.MustParse(locale), input)
+		require.Equal(t, expect, actual)
+	}
+
+	// URLs, email addresses and paths are kept.
+	f(t, "fr", "Voir https://example.com/search?q=1 !",
+		"Voir https://example.com/search?q=1\u202f!")
+	f(t, "fr", "Ouvrez www.example.com/faq?id=2", "Ouvrez www.example.com/faq?id=2")
+	f(t, "fr", "Écrivez à support@example.com!", "Écrivez à support@example.com!")
+	f(t, "en", `See "./it's-here" and C:\\don't`, `See "./it's-here" and C:\\don't`)
+	f(t, "fr", "Query ?a=1&b=2", "Query ?a=1&b=2")
+
+	// Hyphens without surrounding spaces aren't dashes.
+	f(t, "en", "Call 555-1234", "Call 555-1234")
+	f(t, "en", "Due 2024-01-05", "Due 2024-01-05")
+	f(t, "en", "Run with --force", "Run with --force")
+	f(t, "en", "well--known", "well--known")
+	f(t, "en", "Wait -- really", "Wait — really")
+
+	// Consecutive punctuation is spaced as one mark.
+	f(t, "fr", "Really?!", "Really\u202f?!")
+	f(t, "fr", "Quoi;!", "Quoi\u202f;!")
+}
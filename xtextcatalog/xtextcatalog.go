@@ -0,0 +1,245 @@
+// Package xtextcatalog provides a localize.Reader backed by
+// a golang.org/x/text/message/catalog.Catalog allowing projects with
+// catalogs in the x/text ecosystem to join a localize.Bundle while migrating.
+package xtextcatalog
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-playground/locales"
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/strfmt"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+var _ localize.Reader = (*Reader)(nil)
+
+// Reader reads localized data from an x/text catalog.
+//
+// Messages are looked up by their source text. Block and PluralBlock
+// messages are looked up by their dedented source text and plural messages
+// by their source template of form Other, which receives the quantity
+// as its first argument. Messages that are not in the catalog fall back
+// to the source text and plural forms are then selected by the cardinal
+// plural rule of the translator.
+type Reader struct {
+	catalog    catalog.Catalog
+	locale     language.Tag
+	base       language.Base
+	translator locales.Translator
+	register   localize.Register
+	section    string
+}
+
+// NewReader creates a new reader reading messages of locale from c,
+// which can also be a *catalog.Builder.
+// translator must be the github.com/go-playground/locales translator
+// of locale, for example de.New() for German.
+func NewReader(
+	c catalog.Catalog, locale language.Tag, translator locales.Translator,
+) *Reader {
+	base, _ := locale.Base()
+	return &Reader{catalog: c, locale: locale, base: base, translator: translator}
+}
+
+// Locale provides the locale this reader localizes for.
+func (r *Reader) Locale() language.Tag { return r.locale }
+
+// Base provides the base language this reader localizes for.
+func (r *Reader) Base() language.Base { return r.base }
+
+// Translator returns the localized translator of
+// github.com/go-playground/locales for the locale this reader localizes for.
+func (r *Reader) Translator() locales.Translator { return r.translator }
+
+// Text provides static 1-to-1 translations.
+func (r *Reader) Text(text string) (localized string) {
+	if s, ok := r.lookup(text, nil); ok {
+		return s
+	}
+	// Fall back to source translation.
+	return text
+}
+
+// Block provides static 1-to-1 translations for a multi-line string block.
+// Common leading indentation is automatically removed.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) Block(text string) (localized string) {
+	return r.Text(strfmt.Dedent(text))
+}
+
+// Plural provides plural translations in cardinal form.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) Plural(templates localize.Forms, quantity any) (localized string) {
+	if tmpl, ok := r.lookup(templates.Other, argOf(quantity)); ok {
+		return fmt.Sprintf(tmpl, quantity)
+	}
+	// Fall back to source translation.
+	return fmt.Sprintf(templates.CardinalForm(r.translator, quantity), quantity)
+}
+
+// PluralBlock behaves like Plural and formats like Block.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) PluralBlock(templates localize.Forms, quantity any) (localized string) {
+	// Translations are indexed by dedented templates.
+	templates.Zero = strfmt.Dedent(templates.Zero)
+	templates.One = strfmt.Dedent(templates.One)
+	templates.Two = strfmt.Dedent(templates.Two)
+	templates.Few = strfmt.Dedent(templates.Few)
+	templates.Many = strfmt.Dedent(templates.Many)
+	templates.Other = strfmt.Dedent(templates.Other)
+	return strfmt.Dedent(r.Plural(templates, quantity))
+}
+
+// Cardinal behaves like Plural with otherTemplate used for all source forms.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) Cardinal(otherTemplate string, quantity any) (localized string) {
+	return r.Plural(localize.CardinalForms(otherTemplate), quantity)
+}
+
+// PluralRange provides plural translations for ranges of quantities.
+// x/text catalogs have no plural range rules, translations are therefore
+// selected by the plural form of to. Source texts are selected by the
+// CLDR plural range rules (see localize.Forms.RangeForm).
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) PluralRange(templates localize.Forms, from, to any) (localized string) {
+	if tmpl, ok := r.lookup(templates.Other, argOf(to)); ok {
+		return fmt.Sprintf(tmpl, from, to)
+	}
+	// Fall back to source translation.
+	return fmt.Sprintf(templates.RangeForm(r.translator, from, to), from, to)
+}
+
+// PluralOrdinal provides plural translations with both an ordinal
+// and a cardinal quantity. The forms of the ordinal category of ordinal
+// are identified by their template of form Other.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) PluralOrdinal(
+	templates localize.OrdinalForms, ordinal, quantity any,
+) (localized string) {
+	forms := templates.Forms(r.translator, ordinal)
+	if tmpl, ok := r.lookup(forms.Other, argOf(quantity)); ok {
+		return fmt.Sprintf(tmpl, ordinal, quantity)
+	}
+	// Fall back to source translation.
+	return fmt.Sprintf(forms.CardinalForm(r.translator, quantity), ordinal, quantity)
+}
+
+// Grammar provides the grammatical form of the phrase of args according to
+// the grammar helper key. Grammar entries are looked up by
+// localize.GrammarID. For more information, see
+// github.com/romshark/localize.Reader documentation.
+func (r *Reader) Grammar(key string, args ...string) (localized string) {
+	if s, ok := r.lookup(localize.GrammarID(key, args...), nil); ok {
+		return s
+	}
+	return localize.GrammarPhrase(args...)
+}
+
+// FormatCompact formats n in the compact decimal notation of the locale.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) FormatCompact(n float64) (localized string) {
+	return localize.FormatCompact(r.locale, r.translator, n)
+}
+
+// Truncate truncates s to at most max grapheme clusters including
+// the ellipsis of the locale.
+// For more information, see github.com/romshark/localize.Reader documentation.
+func (r *Reader) Truncate(s string, max int) (localized string) {
+	return localize.Truncate(r.locale, s, max)
+}
+
+// WithRegister returns a reader of the same catalog providing the variants
+// of translations in register, which are looked up by their
+// localize.RegisterID. For more information, see
+// github.com/romshark/localize.Reader documentation.
+func (r *Reader) WithRegister(register localize.Register) localize.Reader {
+	w := *r
+	w.register = register
+	return &w
+}
+
+// Section returns a reader of the same catalog looking up the translations
+// of section name nested in the section of r first, which are looked up by
+// their localize.SectionID. For more information, see
+// github.com/romshark/localize.Reader documentation.
+func (r *Reader) Section(name string) localize.Reader {
+	w := *r
+	w.section = localize.SectionPath(r.section, name)
+	return &w
+}
+
+// lookup returns the format string of key selected for arg preferring
+// the message of the section of the reader.
+// ok is false if the catalog has no message for key.
+func (r *Reader) lookup(key string, arg any) (format string, ok bool) {
+	if r.section != "" {
+		if s, ok := r.lookupRegister(localize.SectionID(r.section, key), arg); ok {
+			return s, true
+		}
+	}
+	return r.lookupRegister(key, arg)
+}
+
+// lookupRegister returns the format string of key selected for arg
+// preferring the variant of the register of the reader.
+// ok is false if the catalog has no message for key.
+func (r *Reader) lookupRegister(key string, arg any) (format string, ok bool) {
+	if r.register != localize.RegisterDefault {
+		if s, ok := r.execute(localize.RegisterID(r.register, key), arg); ok {
+			return s, true
+		}
+	}
+	return r.execute(key, arg)
+}
+
+// execute returns the format string of key selected for arg.
+// ok is false if the catalog has no message for key.
+func (r *Reader) execute(key string, arg any) (format string, ok bool) {
+	rn := &renderer{arg: arg}
+	if err := r.catalog.Context(r.locale, rn).Execute(key); err != nil {
+		return "", false
+	}
+	return rn.b.String(), true
+}
+
+// argOf returns quantity as an argument x/text plural selectors can handle.
+// Quantities of types other than the predeclared numeric types are converted
+// using localize.Quantity.
+func argOf(quantity any) any {
+	switch quantity.(type) {
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return quantity
+	}
+	q, ok := localize.Quantity(quantity)
+	if !ok {
+		return quantity
+	}
+	if q == math.Trunc(q) {
+		return int64(q)
+	}
+	return q
+}
+
+// renderer implements the renderer of x/text catalog messages
+// collecting the rendered format string.
+type renderer struct {
+	b   strings.Builder
+	arg any
+}
+
+func (r *renderer) Render(s string) { r.b.WriteString(s) }
+
+// Arg returns the i-th (1-based) argument.
+// The only argument is the quantity of plural messages.
+func (r *renderer) Arg(i int) any {
+	if i != 1 {
+		return nil
+	}
+	return r.arg
+}
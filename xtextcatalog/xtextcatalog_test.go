@@ -0,0 +1,110 @@
+package xtextcatalog_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/go-playground/locales/pl"
+	"github.com/romshark/localize"
+	"github.com/romshark/localize/internal/xtexttest"
+	"github.com/romshark/localize/localizetest"
+	"github.com/romshark/localize/xtextcatalog"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+var testMessages = map[string]catalog.Message{
+	"Hello": catalog.String("Hallo"),
+	"First line.\n  Second line.": catalog.String(
+		"Erste Zeile.\n  Zweite Zeile."),
+	"%d messages": plural.Selectf(1, "%d",
+		"=0", "keine Nachrichten (%d)",
+		"one", "%d Nachricht",
+		"other", "%d Nachrichten",
+	),
+	localize.GrammarID("contraction", "zu", "dem"):          catalog.String("zum"),
+	localize.RegisterID(localize.RegisterInformal, "Hello"): catalog.String("Hi"),
+	localize.RegisterID(localize.RegisterInformal, "%d messages"): plural.Selectf(1, "%d",
+		"one", "%d Nachricht für dich",
+		"other", "%d Nachrichten für dich",
+	),
+	localize.SectionID("Account", "Hello"): catalog.String("Willkommen zurück"),
+}
+
+func TestReaderConformance(t *testing.T) {
+	localizetest.TestReaderConformance(t, xtexttest.German(t, testMessages))
+}
+
+func TestReaderConformancePolish(t *testing.T) {
+	localizetest.TestReaderConformance(t,
+		xtextcatalog.NewReader(catalog.NewBuilder(), language.Polish, pl.New()))
+}
+
+func TestReader(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	require.Equal(t, language.German, r.Locale())
+
+	require.Equal(t, "Hallo", r.Text("Hello"))
+	require.Equal(t, "Goodbye", r.Text("Goodbye"))
+	require.Equal(t, "Erste Zeile.\n  Zweite Zeile.",
+		r.Block("\n\t\tFirst line.\n\t\t  Second line.\n\t"))
+
+	forms := localize.Forms{One: "%d message", Other: "%d messages"}
+	require.Equal(t, "keine Nachrichten (0)", r.Plural(forms, 0))
+	require.Equal(t, "1 Nachricht", r.Plural(forms, 1))
+	require.Equal(t, "5 Nachrichten", r.Plural(forms, uint8(5)))
+	require.Equal(t, "1 Nachricht", r.Plural(forms, big.NewInt(1)))
+	require.Equal(t, "1 Nachricht",
+		r.PluralBlock(localize.Forms{One: "\n\t%d message\n", Other: "\n\t%d messages\n"}, 1))
+
+	forms = localize.Forms{One: "%d file", Other: "%d files"}
+	require.Equal(t, "1 file", r.Plural(forms, 1))
+	require.Equal(t, "2 files", r.Plural(forms, 2))
+
+	require.Equal(t, "zum", r.Grammar("contraction", "zu", "dem"))
+	require.Equal(t, "zu der", r.Grammar("contraction", "zu", "der"))
+}
+
+func TestReaderSourceFallback(t *testing.T) {
+	// Polish has the categories few and many the source forms don't define.
+	r := xtextcatalog.NewReader(catalog.NewBuilder(), language.Polish, pl.New())
+	forms := localize.Forms{One: "%d file", Other: "%d files"}
+	require.Equal(t, "1 file", r.Plural(forms, 1))
+	require.Equal(t, "2 files", r.Plural(forms, 2)) // few
+	require.Equal(t, "5 files", r.Plural(forms, 5)) // many
+	require.Equal(t, "22 files", r.PluralBlock(forms, 22))
+	require.Equal(t, "5 files", r.Cardinal("%d files", 5))
+}
+
+func TestReaderWithRegister(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	informal := r.WithRegister(localize.RegisterInformal)
+	require.Equal(t, "Hi", informal.Text("Hello"))
+	require.Equal(t, "5 Nachrichten für dich",
+		informal.Plural(localize.Forms{One: "%d message", Other: "%d messages"}, 5))
+	// Messages without variant fall back to the regular translation.
+	require.Equal(t, "Erste Zeile.\n  Zweite Zeile.",
+		informal.Block("\n\t\tFirst line.\n\t\t  Second line.\n\t"))
+	require.Equal(t, "Hallo", r.WithRegister(localize.RegisterFormal).Text("Hello"))
+	require.Equal(t, "Hallo", r.Text("Hello"))
+}
+
+func TestReaderSection(t *testing.T) {
+	r := xtexttest.German(t, testMessages)
+	account := r.Section("Account")
+	require.Equal(t, "Willkommen zurück", account.Text("Hello"))
+	require.Equal(t, "Willkommen zurück",
+		account.WithRegister(localize.RegisterInformal).Text("Hello"))
+	// Messages of sections fall back to the translations outside of sections.
+	require.Equal(t, "5 Nachrichten",
+		account.Plural(localize.Forms{One: "%d message", Other: "%d messages"}, 5))
+	require.Equal(t, "Hallo", r.Section("Settings").Text("Hello"))
+}
+
+func TestBundle(t *testing.T) {
+	b, err := localize.New(language.German, xtexttest.German(t, testMessages))
+	require.NoError(t, err)
+	require.Equal(t, "Hallo", b.Default().Text("Hello"))
+}